@@ -0,0 +1,336 @@
+package heapcraft
+
+// smallHeapThreshold is the number of elements an AdaptiveHeap stores inline
+// before switching to its tree representation.
+const smallHeapThreshold = 8
+
+// AdaptiveHeap is a heap optimized for queues that are usually tiny. While it
+// holds at most smallHeapThreshold elements, they are kept in an inline array
+// sorted by priority, avoiding node allocation and pointer chasing. Once the
+// threshold is exceeded the elements move into a PairingHeap, and they move
+// back inline when the heap shrinks to half the threshold. The switch is
+// transparent to callers. The heap can be either a min-heap or max-heap
+// depending on the comparison function.
+type AdaptiveHeap[V any, P any] struct {
+	small  [smallHeapThreshold]HeapNode[V, P]
+	n      int
+	tree   *PairingHeap[V, P]
+	cmp    func(a, b P) bool
+	config HeapConfig
+	alarms depthAlarms
+	stats  heapStats
+	events listeners[HeapEvent[V, P]]
+}
+
+// inline reports whether the heap is currently using its inline array.
+func (a *AdaptiveHeap[V, P]) inline() bool { return a.tree == nil }
+
+// IsInline returns true if the heap currently stores its elements in the
+// inline array rather than in the tree structure.
+func (a *AdaptiveHeap[V, P]) IsInline() bool { return a.inline() }
+
+// Clone creates a copy of the heap. If values or priorities are reference
+// types, those reference values are shared between the original and cloned
+// heaps.
+func (a *AdaptiveHeap[V, P]) Clone() *AdaptiveHeap[V, P] {
+	cloned := *a
+	if a.tree != nil {
+		cloned.tree = a.tree.Clone()
+	}
+	cloned.alarms = a.alarms.clone()
+	cloned.events = a.events.clone()
+	return &cloned
+}
+
+// Clear removes all elements from the heap and returns it to inline mode.
+func (a *AdaptiveHeap[V, P]) Clear() {
+	cleared := a.Length()
+	a.small = [smallHeapThreshold]HeapNode[V, P]{}
+	a.n = 0
+	a.tree = nil
+	a.stats.record(OpClear, cleared, 0)
+	a.alarms.check(0)
+	emitClearEvent(a.events)
+}
+
+// Length returns the current number of elements in the heap.
+func (a *AdaptiveHeap[V, P]) Length() int {
+	if a.inline() {
+		return a.n
+	}
+	return a.tree.Length()
+}
+
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (a *AdaptiveHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return a.alarms.register(n, a.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (a *AdaptiveHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return a.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (a *AdaptiveHeap[V, P]) Stats() HeapStats { return a.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (a *AdaptiveHeap[V, P]) Instrument(hook Instrumentation) { a.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped, and
+// whenever the heap is cleared. Returns an ID that can be passed to
+// RemoveListener.
+func (a *AdaptiveHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return a.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (a *AdaptiveHeap[V, P]) RemoveListener(id string) error { return a.events.deregister(id) }
+
+// IsEmpty returns true if the heap contains no elements.
+func (a *AdaptiveHeap[V, P]) IsEmpty() bool { return a.Length() == 0 }
+
+// Verify checks the internal consistency of the heap: in inline mode the
+// array must be sorted with the best element at the end, and in tree mode the
+// pairing heap must pass its own Verify. It is intended for tests and
+// debugging and returns an error wrapping ErrInvariantViolated for the first
+// violation.
+func (a *AdaptiveHeap[V, P]) Verify() error {
+	if !a.inline() {
+		if a.n != 0 {
+			return invariantError("%d elements are left inline in tree mode", a.n)
+		}
+		return a.tree.Verify()
+	}
+	if a.n > smallHeapThreshold {
+		return invariantError("%d elements are inline, above the threshold of %d", a.n, smallHeapThreshold)
+	}
+	for i := 1; i < a.n; i++ {
+		if a.cmp(a.small[i-1].priority, a.small[i].priority) {
+			return invariantError("inline element at index %d comes before the element after it", i-1)
+		}
+	}
+	return nil
+}
+
+// insertInline places a new element into the sorted inline array. The element
+// with the highest priority (according to cmp) is kept at the end of the array
+// and elements of equal priority are popped in insertion order.
+func (a *AdaptiveHeap[V, P]) insertInline(value V, priority P) {
+	i := a.n
+	for i > 0 && !a.cmp(priority, a.small[i-1].priority) {
+		a.small[i] = a.small[i-1]
+		i--
+	}
+	a.small[i] = HeapNode[V, P]{value: value, priority: priority}
+	a.n++
+}
+
+// grow moves all inline elements into a newly created tree structure.
+func (a *AdaptiveHeap[V, P]) grow() {
+	a.tree = NewSimplePairingHeap(a.small[:a.n], a.cmp, a.config)
+	a.small = [smallHeapThreshold]HeapNode[V, P]{}
+	a.n = 0
+	a.stats.rebalance()
+}
+
+// shrink moves all tree elements back into the inline array. Elements are
+// popped from the tree in priority order, so they are written from the end of
+// the array towards the start.
+func (a *AdaptiveHeap[V, P]) shrink() {
+	a.stats.rebalance()
+	a.n = a.tree.Length()
+	for i := a.n - 1; i >= 0; i-- {
+		v, p, _ := a.tree.Pop()
+		a.small[i] = HeapNode[V, P]{value: v, priority: p}
+	}
+	a.tree = nil
+}
+
+// peek is an internal method that returns the root element without removing
+// it. Returns zero values and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) peek() (V, P, error) {
+	if !a.inline() {
+		return a.tree.Peek()
+	}
+	if a.n == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	root := a.small[a.n-1]
+	return root.value, root.priority, nil
+}
+
+// Peek returns the value and priority of the root element without removing it.
+// Returns zero values and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) Peek() (V, P, error) { return a.peek() }
+
+// PeekValue returns the value at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(a.peek())
+}
+
+// PeekPriority returns the priority at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(a.peek())
+}
+
+// pop is an internal method that removes and returns the root element.
+// Returns zero values and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) pop() (V, P, error) {
+	if !a.inline() {
+		v, p, err := a.tree.Pop()
+		if a.tree.Length() <= smallHeapThreshold/2 {
+			a.shrink()
+		}
+		a.stats.record(OpPop, 1, a.Length())
+		a.alarms.check(a.Length())
+		emitHeapEvent(a.events, EventPop, "", v, p)
+		return v, p, err
+	}
+	if a.n == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	a.n--
+	a.stats.record(OpPop, 1, a.n)
+	a.alarms.check(a.n)
+	root := a.small[a.n]
+	a.small[a.n] = HeapNode[V, P]{}
+	emitHeapEvent(a.events, EventPop, "", root.value, root.priority)
+	return root.value, root.priority, nil
+}
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) Pop() (V, P, error) { return a.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(a.Peek, a.Pop, pred)
+}
+
+// PopValue removes and returns just the value at the root.
+// Returns zero value and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(a.pop())
+}
+
+// PopPriority removes and returns just the priority at the root.
+// Returns zero value and an error if the heap is empty.
+func (a *AdaptiveHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(a.pop())
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (a *AdaptiveHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(a.Length(), a.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (a *AdaptiveHeap[V, P]) DrainValues() []V {
+	return drainValues(a.Length(), a.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (a *AdaptiveHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(a.Length(), a.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (a *AdaptiveHeap[V, P]) forEach(fn func(v V, p P)) {
+	if !a.inline() {
+		a.tree.forEach(fn)
+		return
+	}
+	for _, node := range a.small[:a.n] {
+		fn(node.value, node.priority)
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (a *AdaptiveHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(a.Length(), a.forEach, a.cmp, opts)
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first. The comparison function is not encoded.
+func (a *AdaptiveHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalHeap(a.Export(ExportOptions[V, P]{}))
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. The heap must have been created with its constructor so that
+// it has a comparison function to restore them with.
+func (a *AdaptiveHeap[V, P]) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalHeap[V, P](data, a.cmp)
+	if err != nil {
+		return err
+	}
+
+	a.Clear()
+	for _, node := range decoded.Nodes {
+		a.Push(node.value, node.priority)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap in a compact binary format using
+// encoding/gob, for persisting the heap across process restarts. Elements are
+// written in internal order without sorting. The comparison function is not
+// encoded.
+func (a *AdaptiveHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotNodes(a.Length(), a.forEach))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. The heap must have been created with its constructor so
+// that it has a comparison function to restore them with.
+func (a *AdaptiveHeap[V, P]) Restore(data []byte) error {
+	if a.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	a.Clear()
+	for i, value := range snapshot.Values {
+		a.Push(value, snapshot.Priorities[i])
+	}
+	return nil
+}
+
+// Push adds a new element to the heap. While the heap is small the element is
+// inserted into the inline array; once the threshold would be exceeded, the
+// heap switches to its tree representation.
+func (a *AdaptiveHeap[V, P]) Push(value V, priority P) {
+	if a.inline() && a.n == smallHeapThreshold {
+		a.grow()
+	}
+	if a.inline() {
+		a.insertInline(value, priority)
+	} else {
+		a.tree.Push(value, priority)
+	}
+	a.stats.record(OpPush, 1, a.Length())
+	a.alarms.check(a.Length())
+	emitHeapEvent(a.events, EventPush, "", value, priority)
+}
@@ -0,0 +1,13 @@
+package heapcraft
+
+// NewAdaptiveHeap creates a new adaptive heap from the given data slice. The
+// comparison function determines the heap order (min or max), and config
+// builds the pairing heap used once the heap outgrows its inline storage, as
+// NewSimplePairingHeap does.
+func NewAdaptiveHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *AdaptiveHeap[V, P] {
+	heap := AdaptiveHeap[V, P]{cmp: cmp, config: config}
+	for i := range data {
+		heap.Push(data[i].value, data[i].priority)
+	}
+	return &heap
+}
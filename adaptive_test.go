@@ -0,0 +1,130 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveHeap_InlineOrder(t *testing.T) {
+	h := NewAdaptiveHeap([]HeapNode[int, int]{
+		CreateHeapNode(5, 5),
+		CreateHeapNode(2, 2),
+		CreateHeapNode(8, 8),
+		CreateHeapNode(3, 3),
+	}, lt, HeapConfig{})
+	assert.True(t, h.IsInline())
+	assert.Equal(t, 4, h.Length())
+
+	value, priority, err := h.Peek()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 2, priority)
+
+	for _, expected := range []int{2, 3, 5, 8} {
+		value, _ := h.PopValue()
+		assert.Equal(t, expected, value)
+	}
+	_, _, err = h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestAdaptiveHeap_EqualPrioritiesFIFO(t *testing.T) {
+	h := NewAdaptiveHeap[string, int](nil, lt, HeapConfig{})
+	h.Push("a", 1)
+	h.Push("b", 1)
+	h.Push("c", 0)
+	h.Push("d", 1)
+
+	expected := []string{"c", "a", "b", "d"}
+	for _, e := range expected {
+		value, _ := h.PopValue()
+		assert.Equal(t, e, value)
+	}
+}
+
+func TestAdaptiveHeap_SwitchesRepresentation(t *testing.T) {
+	h := NewAdaptiveHeap[int, int](nil, lt, HeapConfig{})
+	for i := smallHeapThreshold; i > 0; i-- {
+		h.Push(i, i)
+	}
+	assert.True(t, h.IsInline())
+
+	h.Push(0, 0)
+	assert.False(t, h.IsInline())
+	assert.Equal(t, smallHeapThreshold+1, h.Length())
+
+	clone := h.Clone()
+	for i := 0; i <= smallHeapThreshold/2; i++ {
+		value, _ := h.PopValue()
+		assert.Equal(t, i, value)
+	}
+	assert.True(t, h.IsInline())
+	assert.Equal(t, smallHeapThreshold/2, h.Length())
+	value, _ := h.PeekValue()
+	assert.Equal(t, smallHeapThreshold/2+1, value)
+
+	assert.False(t, clone.IsInline())
+	assert.Equal(t, smallHeapThreshold+1, clone.Length())
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	assert.True(t, h.IsInline())
+}
+
+func TestAdaptiveHeap_RandomOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	h := NewAdaptiveHeap[int, int](nil, gt, HeapConfig{UsePool: true})
+	expected := make([]int, 0)
+	for round := 0; round < 50; round++ {
+		for i := 0; i < r.Intn(20); i++ {
+			n := r.Intn(100)
+			h.Push(n, n)
+			expected = append(expected, n)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(expected)))
+		for i := 0; i < r.Intn(20) && len(expected) > 0; i++ {
+			value, err := h.PopValue()
+			assert.Nil(t, err)
+			assert.Equal(t, expected[0], value)
+			expected = expected[1:]
+		}
+		assert.Equal(t, len(expected), h.Length())
+	}
+}
+
+// -------------------------------- Adaptive Heap Benchmarks --------------------------------
+
+// benchmarkSmallQueue pushes and pops in small bursts so the heap never holds
+// more than a handful of elements, which is the workload AdaptiveHeap targets.
+func benchmarkSmallQueue(b *testing.B, push func(v, p int), pop func()) {
+	insertions := generateRandomNumbersv1(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		push(insertions[i], insertions[i])
+		if i%4 == 3 {
+			for j := 0; j < 4; j++ {
+				pop()
+			}
+		}
+	}
+}
+
+func BenchmarkAdaptiveHeap_SmallQueue(b *testing.B) {
+	heap := NewAdaptiveHeap[int, int](nil, lt, HeapConfig{})
+	benchmarkSmallQueue(b, heap.Push, func() { heap.Pop() })
+}
+
+func BenchmarkPairingHeap_SmallQueue(b *testing.B) {
+	heap := NewPairingHeap[int, int](nil, lt, false)
+	benchmarkSmallQueue(b, heap.Push, func() { heap.Pop() })
+}
+
+func BenchmarkBinaryHeap_SmallQueue(b *testing.B) {
+	heap := NewBinaryHeap[int, int](nil, lt, false)
+	benchmarkSmallQueue(b, heap.Push, func() { heap.Pop() })
+}
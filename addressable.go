@@ -0,0 +1,116 @@
+package heapcraft
+
+// AddressableMinQueue is a priority queue of distinct keys whose priorities
+// can be lowered in place, the operation at the heart of Dijkstra's and A*
+// shortest-path searches. It is built on FullPairingHeap.UpdatePriority and
+// keeps the mapping from keys to node IDs itself, so callers address elements
+// by their own keys, such as graph vertices, instead of by generated IDs.
+type AddressableMinQueue[K comparable, P any] struct {
+	heap *FullPairingHeap[K, P]
+	ids  map[K]string
+	lt   func(a, b P) bool
+}
+
+// Push adds key with the given priority. Returns ErrKeyExists if the key is
+// already in the queue.
+func (q *AddressableMinQueue[K, P]) Push(key K, priority P) error {
+	if _, exists := q.ids[key]; exists {
+		return ErrKeyExists
+	}
+	id, err := q.heap.Push(key, priority)
+	if err != nil {
+		return err
+	}
+	q.ids[key] = id
+	return nil
+}
+
+// DecreaseKey lowers the priority of key. Returns ErrKeyNotFound if the key
+// is not in the queue and ErrPriorityNotDecreased if priority does not come
+// before its current priority.
+func (q *AddressableMinQueue[K, P]) DecreaseKey(key K, priority P) error {
+	id, exists := q.ids[key]
+	if !exists {
+		return &KeyNotFoundError[K]{Key: key}
+	}
+	current, _ := q.heap.GetPriority(id)
+	if !q.lt(priority, current) {
+		return ErrPriorityNotDecreased
+	}
+	return q.heap.UpdatePriority(id, priority)
+}
+
+// Relax pushes key with the given priority if it is not in the queue, or
+// lowers its priority if priority comes before the current one. Returns true
+// if the queue changed. This is the edge relaxation step of Dijkstra's
+// algorithm.
+func (q *AddressableMinQueue[K, P]) Relax(key K, priority P) (bool, error) {
+	id, exists := q.ids[key]
+	if !exists {
+		if err := q.Push(key, priority); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	current, _ := q.heap.GetPriority(id)
+	if !q.lt(priority, current) {
+		return false, nil
+	}
+	return true, q.heap.UpdatePriority(id, priority)
+}
+
+// Contains reports whether key is in the queue.
+func (q *AddressableMinQueue[K, P]) Contains(key K) bool {
+	_, exists := q.ids[key]
+	return exists
+}
+
+// Priority returns the current priority of key. Returns zero value and
+// ErrKeyNotFound if the key is not in the queue.
+func (q *AddressableMinQueue[K, P]) Priority(key K) (P, error) {
+	id, exists := q.ids[key]
+	if !exists {
+		var zero P
+		return zero, &KeyNotFoundError[K]{Key: key}
+	}
+	return q.heap.GetPriority(id)
+}
+
+// Remove deletes key from the queue and returns its priority. Returns zero
+// value and ErrKeyNotFound if the key is not in the queue.
+func (q *AddressableMinQueue[K, P]) Remove(key K) (P, error) {
+	id, exists := q.ids[key]
+	if !exists {
+		var zero P
+		return zero, &KeyNotFoundError[K]{Key: key}
+	}
+	delete(q.ids, key)
+	_, priority, err := q.heap.Remove(id)
+	return priority, err
+}
+
+// Peek returns the key with the lowest priority and its priority without
+// removing it. Returns zero values and an error if the queue is empty.
+func (q *AddressableMinQueue[K, P]) Peek() (K, P, error) { return q.heap.Peek() }
+
+// Pop removes and returns the key with the lowest priority and its priority.
+// Returns zero values and an error if the queue is empty.
+func (q *AddressableMinQueue[K, P]) Pop() (K, P, error) {
+	key, priority, err := q.heap.Pop()
+	if err == nil {
+		delete(q.ids, key)
+	}
+	return key, priority, err
+}
+
+// Length returns the number of keys in the queue.
+func (q *AddressableMinQueue[K, P]) Length() int { return q.heap.Length() }
+
+// IsEmpty returns true if the queue contains no keys.
+func (q *AddressableMinQueue[K, P]) IsEmpty() bool { return q.heap.IsEmpty() }
+
+// Clear removes all keys from the queue.
+func (q *AddressableMinQueue[K, P]) Clear() {
+	q.heap.Clear()
+	clear(q.ids)
+}
@@ -0,0 +1,17 @@
+package heapcraft
+
+// NewAddressableMinQueue creates an empty AddressableMinQueue. The comparison
+// function lt should return true if a < b; the key with the lowest priority
+// is popped first. config builds the underlying FullPairingHeap; its IDs are
+// never exposed, so a nil IDGenerator selects an IntegerIDGenerator rather
+// than the UUID default.
+func NewAddressableMinQueue[K comparable, P any](lt func(a, b P) bool, config HeapConfig) *AddressableMinQueue[K, P] {
+	if config.IDGenerator == nil {
+		config.IDGenerator = &IntegerIDGenerator{}
+	}
+	return &AddressableMinQueue[K, P]{
+		heap: NewFullPairingHeap[K, P](nil, lt, config),
+		ids:  make(map[K]string, config.Capacity),
+		lt:   lt,
+	}
+}
@@ -0,0 +1,72 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressableMinQueue(t *testing.T) {
+	q := NewAddressableMinQueue[string, int](lt, HeapConfig{})
+	require.Nil(t, q.Push("a", 5))
+	require.Nil(t, q.Push("b", 3))
+	require.Nil(t, q.Push("c", 8))
+	assert.ErrorIs(t, q.Push("a", 1), ErrKeyExists)
+	assert.True(t, q.Contains("c"))
+	assert.Equal(t, 3, q.Length())
+
+	require.Nil(t, q.DecreaseKey("c", 1))
+	assert.ErrorIs(t, q.DecreaseKey("c", 2), ErrPriorityNotDecreased)
+	assert.ErrorIs(t, q.DecreaseKey("z", 0), ErrKeyNotFound)
+
+	priority, err := q.Priority("c")
+	require.Nil(t, err)
+	assert.Equal(t, 1, priority)
+
+	key, priority, err := q.Peek()
+	require.Nil(t, err)
+	assert.Equal(t, "c", key)
+	assert.Equal(t, 1, priority)
+
+	key, _, err = q.Pop()
+	require.Nil(t, err)
+	assert.Equal(t, "c", key)
+	assert.False(t, q.Contains("c"))
+	require.Nil(t, q.Push("c", 10))
+
+	priority, err = q.Remove("a")
+	require.Nil(t, err)
+	assert.Equal(t, 5, priority)
+	_, err = q.Remove("a")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = q.Priority("a")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	key, _, _ = q.Pop()
+	assert.Equal(t, "b", key)
+	q.Clear()
+	assert.True(t, q.IsEmpty())
+	assert.False(t, q.Contains("c"))
+	_, _, err = q.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestAddressableMinQueue_Relax(t *testing.T) {
+	q := NewAddressableMinQueue[int, float64](func(a, b float64) bool { return a < b }, HeapConfig{UsePool: true})
+
+	relaxed, err := q.Relax(1, 4.5)
+	require.Nil(t, err)
+	assert.True(t, relaxed)
+
+	relaxed, err = q.Relax(1, 6)
+	require.Nil(t, err)
+	assert.False(t, relaxed)
+
+	relaxed, err = q.Relax(1, 2.5)
+	require.Nil(t, err)
+	assert.True(t, relaxed)
+
+	priority, _ := q.Priority(1)
+	assert.Equal(t, 2.5, priority)
+}
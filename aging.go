@@ -0,0 +1,205 @@
+package heapcraft
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+// agingEntry is a value stored in an AgingHeap together with the priority it
+// was pushed with and the time it was pushed at.
+type agingEntry[V any, P any] struct {
+	value    V
+	priority P
+	pushed   time.Time
+}
+
+// AgingHeap is a heap whose elements gain (or lose) priority the longer they
+// wait, so that a steady stream of important elements cannot starve the rest
+// forever. The effective priority of an element is age(priority, waited),
+// where priority is the one it was pushed with and waited is the time since
+// it was pushed. Effective priorities are re-evaluated lazily: Pop and Peek
+// recompute every element's priority and re-heapify, in O(n), when at least
+// the refresh interval has passed since the last re-evaluation, and otherwise
+// order elements by their priorities as of then. The priorities returned by
+// Pop, Peek and Export are effective priorities. The heap is not safe for
+// concurrent use; use SyncAgingHeap for that.
+type AgingHeap[V any, P any] struct {
+	heap      *DaryHeap[agingEntry[V, P], P]
+	age       func(priority P, waited time.Duration) P
+	now       func() time.Time
+	interval  time.Duration
+	refreshed time.Time
+}
+
+// LinearAging returns an aging function for a min-heap that lowers a
+// priority by step for every period an element waits, so that an element
+// pushed with priority p overtakes one pushed with priority p-step after
+// waiting one more period. For a max-heap with signed or floating-point
+// priorities, pass a negative step. Unsigned priorities can only age towards
+// zero, so they suit min-heaps only, and they stop at zero instead of
+// wrapping around to the largest value, which would starve the element.
+func LinearAging[P constraints.Integer | constraints.Float](step P, period time.Duration) func(priority P, waited time.Duration) P {
+	return func(priority P, waited time.Duration) P {
+		periods := P(waited / period)
+		var zero P
+		if unsigned := zero-1 > zero; unsigned && step != 0 && periods > priority/step {
+			return zero
+		}
+		return priority - step*periods
+	}
+}
+
+// Refresh re-evaluates the effective priority of every element as of now and
+// restores the heap order, regardless of the refresh interval.
+func (a *AgingHeap[V, P]) Refresh() {
+	now := a.now()
+	for i := range a.heap.data {
+		entry := a.heap.data[i].value
+		a.heap.data[i].priority = a.age(entry.priority, now.Sub(entry.pushed))
+	}
+	a.heap.heapify()
+	a.refreshed = now
+}
+
+// refreshIfDue re-evaluates effective priorities if the refresh interval has
+// passed since the last re-evaluation.
+func (a *AgingHeap[V, P]) refreshIfDue() {
+	if a.now().Sub(a.refreshed) >= a.interval {
+		a.Refresh()
+	}
+}
+
+// Maintain re-evaluates effective priorities, allowing the heap to be
+// registered with a Maintenance registry so that Pop rarely has to. Returns
+// the context error if ctx is already done.
+func (a *AgingHeap[V, P]) Maintain(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	a.Refresh()
+	return nil
+}
+
+// Push inserts an element with the given priority, which starts aging from
+// now. Until the next re-evaluation it is ordered by age(priority, 0).
+func (a *AgingHeap[V, P]) Push(value V, priority P) {
+	entry := agingEntry[V, P]{value: value, priority: priority, pushed: a.now()}
+	a.heap.Push(entry, a.age(priority, 0))
+}
+
+// Clear removes all elements from the heap.
+func (a *AgingHeap[V, P]) Clear() { a.heap.Clear() }
+
+// Length returns the number of elements in the heap.
+func (a *AgingHeap[V, P]) Length() int { return a.heap.Length() }
+
+// IsEmpty returns true if the heap contains no elements.
+func (a *AgingHeap[V, P]) IsEmpty() bool { return a.heap.IsEmpty() }
+
+// Verify checks the internal consistency of the underlying d-ary heap, against
+// the effective priorities as of the last re-evaluation. It is intended for
+// tests and debugging and returns an error wrapping ErrInvariantViolated for
+// the first violation.
+func (a *AgingHeap[V, P]) Verify() error { return a.heap.Verify() }
+
+// peek is an internal method that re-evaluates priorities if due and returns
+// the root element without removing it.
+func (a *AgingHeap[V, P]) peek() (V, P, error) {
+	a.refreshIfDue()
+	entry, priority, err := a.heap.Peek()
+	return entry.value, priority, err
+}
+
+// Peek returns the value and effective priority of the element with the best
+// effective priority without removing it. Returns zero values and an error if
+// the heap is empty.
+func (a *AgingHeap[V, P]) Peek() (V, P, error) { return a.peek() }
+
+// PeekValue returns the value of the element with the best effective priority
+// without removing it. Returns zero value and an error if the heap is empty.
+func (a *AgingHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(a.peek())
+}
+
+// PeekPriority returns the best effective priority without removing its
+// element. Returns zero value and an error if the heap is empty.
+func (a *AgingHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(a.peek())
+}
+
+// popRoot removes and returns the root element without re-evaluating
+// priorities.
+func (a *AgingHeap[V, P]) popRoot() (V, P, error) {
+	entry, priority, err := a.heap.Pop()
+	return entry.value, priority, err
+}
+
+// pop is an internal method that re-evaluates priorities if due and removes
+// and returns the root element.
+func (a *AgingHeap[V, P]) pop() (V, P, error) {
+	a.refreshIfDue()
+	return a.popRoot()
+}
+
+// Pop removes and returns the value and effective priority of the element
+// with the best effective priority. Returns zero values and an error if the
+// heap is empty.
+func (a *AgingHeap[V, P]) Pop() (V, P, error) { return a.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and effective priority. If pred reports false the root stays in
+// the heap and its value and priority are returned with false. Returns zero
+// values, false and an error if the heap is empty.
+func (a *AgingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(a.Peek, a.Pop, pred)
+}
+
+// PopValue removes and returns the value of the element with the best
+// effective priority. Returns zero value and an error if the heap is empty.
+func (a *AgingHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(a.pop())
+}
+
+// PopPriority removes and returns the best effective priority. Returns zero
+// value and an error if the heap is empty.
+func (a *AgingHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(a.pop())
+}
+
+// Drain re-evaluates effective priorities once and removes and returns every
+// element in that order.
+func (a *AgingHeap[V, P]) Drain() []HeapNode[V, P] {
+	a.Refresh()
+	return drainNodes(a.Length(), a.popRoot)
+}
+
+// DrainValues re-evaluates effective priorities once and removes and returns
+// the values of every element in that order.
+func (a *AgingHeap[V, P]) DrainValues() []V {
+	a.Refresh()
+	return drainValues(a.Length(), a.popRoot)
+}
+
+// DrainPriorities re-evaluates effective priorities once and removes and
+// returns them in order.
+func (a *AgingHeap[V, P]) DrainPriorities() []P {
+	a.Refresh()
+	return drainPriorities(a.Length(), a.popRoot)
+}
+
+// forEach calls fn for every element with its effective priority as of now,
+// in internal order.
+func (a *AgingHeap[V, P]) forEach(fn func(v V, p P)) {
+	now := a.now()
+	for _, node := range a.heap.data {
+		fn(node.value.value, a.age(node.value.priority, now.Sub(node.value.pushed)))
+	}
+}
+
+// Export returns a copy of the elements of the heap with their effective
+// priorities as of now, according to opts. The heap itself is not modified.
+func (a *AgingHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(a.Length(), a.forEach, a.heap.cmp, opts)
+}
@@ -0,0 +1,28 @@
+package heapcraft
+
+import "time"
+
+// NewAgingHeap creates an empty AgingHeap. The comparison function determines
+// the heap order (min or max) of effective priorities, which age computes
+// from the priority an element was pushed with and the time it has waited.
+// interval is the minimum time between re-evaluations of every effective
+// priority; zero re-evaluates on every Pop and Peek. now is the clock used to
+// measure waiting times; if nil, time.Now is used. config sets the options of
+// the d-ary heap the elements are kept in.
+func NewAgingHeap[V any, P any](cmp func(a, b P) bool, age func(priority P, waited time.Duration) P, interval time.Duration, now func() time.Time, config DaryHeapConfig) *AgingHeap[V, P] {
+	if now == nil {
+		now = time.Now
+	}
+	return &AgingHeap[V, P]{
+		heap:      NewDaryHeapWithConfig[agingEntry[V, P], P](2, nil, cmp, config),
+		age:       age,
+		now:       now,
+		interval:  interval,
+		refreshed: now(),
+	}
+}
+
+// NewSyncAgingHeap creates an empty thread-safe AgingHeap. See NewAgingHeap.
+func NewSyncAgingHeap[V any, P any](cmp func(a, b P) bool, age func(priority P, waited time.Duration) P, interval time.Duration, now func() time.Time, config DaryHeapConfig) *SyncAgingHeap[V, P] {
+	return &SyncAgingHeap[V, P]{heap: NewAgingHeap[V, P](cmp, age, interval, now, config)}
+}
@@ -0,0 +1,156 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+)
+
+// SyncAgingHeap is a thread-safe wrapper around AgingHeap. Every operation
+// takes an exclusive lock, since even Peek may re-evaluate priorities.
+type SyncAgingHeap[V any, P any] struct {
+	heap *AgingHeap[V, P]
+	mu   sync.Mutex
+}
+
+// Refresh re-evaluates the effective priority of every element as of now and
+// restores the heap order.
+func (s *SyncAgingHeap[V, P]) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Refresh()
+}
+
+// Maintain re-evaluates effective priorities, allowing the heap to be
+// registered with a Maintenance registry. Returns the context error if ctx is
+// already done.
+func (s *SyncAgingHeap[V, P]) Maintain(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Maintain(ctx)
+}
+
+// Push inserts an element with the given priority, which starts aging from
+// now.
+func (s *SyncAgingHeap[V, P]) Push(value V, priority P) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Push(value, priority)
+}
+
+// Clear removes all elements from the heap.
+func (s *SyncAgingHeap[V, P]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Clear()
+}
+
+// Length returns the number of elements in the heap.
+func (s *SyncAgingHeap[V, P]) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SyncAgingHeap[V, P]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under the heap lock. It is
+// intended for tests and debugging.
+func (s *SyncAgingHeap[V, P]) Verify() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Verify()
+}
+
+// Peek returns the value and effective priority of the element with the best
+// effective priority without removing it.
+func (s *SyncAgingHeap[V, P]) Peek() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Peek()
+}
+
+// PeekValue returns the value of the element with the best effective priority
+// without removing it.
+func (s *SyncAgingHeap[V, P]) PeekValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns the best effective priority without removing its
+// element.
+func (s *SyncAgingHeap[V, P]) PeekPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and effective priority of the element
+// with the best effective priority.
+func (s *SyncAgingHeap[V, P]) Pop() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and effective priority. The check and the removal happen under a
+// single lock, so no other goroutine can take the root in between. pred must
+// not call back into the heap.
+func (s *SyncAgingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopValue removes and returns the value of the element with the best
+// effective priority.
+func (s *SyncAgingHeap[V, P]) PopValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns the best effective priority.
+func (s *SyncAgingHeap[V, P]) PopPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPriority()
+}
+
+// Drain re-evaluates effective priorities once and removes and returns every
+// element in that order.
+func (s *SyncAgingHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues re-evaluates effective priorities once and removes and returns
+// the values of every element in that order.
+func (s *SyncAgingHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities re-evaluates effective priorities once and removes and
+// returns them in order.
+func (s *SyncAgingHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements of the heap with their effective
+// priorities as of now, according to opts.
+func (s *SyncAgingHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Export(opts)
+}
@@ -0,0 +1,101 @@
+package heapcraft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgingHeap_PreventsStarvation(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewAgingHeap[string, int](lt, LinearAging(1, time.Second), 0, clock.Now, DaryHeapConfig{})
+
+	heap.Push("background", 10)
+	for i := 0; i < 5; i++ {
+		clock.Advance(2 * time.Second)
+		heap.Push("urgent", 5)
+		value, _, err := heap.Pop()
+		require.NoError(t, err)
+		if i < 2 {
+			assert.Equal(t, "urgent", value, "round %d", i)
+		} else {
+			// After waiting 6 seconds the background job is at 4, ahead of
+			// the urgent job pushed with 5.
+			assert.Equal(t, "background", value, "round %d", i)
+			break
+		}
+	}
+
+	clock.Advance(3 * time.Second)
+	heap.Push("fresh", 0)
+	nodes := heap.Export(ExportOptions[string, int]{})
+	assert.Equal(t, []int{0, 2}, nodePriorities(nodes))
+	assert.Equal(t, []string{"fresh", "urgent"}, heap.DrainValues())
+	assert.True(t, heap.IsEmpty())
+}
+
+func TestLinearAging_UnsignedSaturates(t *testing.T) {
+	age := LinearAging[uint](2, time.Second)
+	assert.Equal(t, uint(1), age(5, 2*time.Second))
+	assert.Equal(t, uint(0), age(5, 3*time.Second))
+	assert.Equal(t, uint(0), age(5, time.Hour))
+
+	clock := newFakeClock()
+	heap := NewAgingHeap[string, uint](func(a, b uint) bool { return a < b }, LinearAging[uint](1, time.Second), 0, clock.Now, DaryHeapConfig{})
+	heap.Push("waiting", 5)
+	clock.Advance(10 * time.Second)
+	heap.Push("fresh", 3)
+	value, priority, err := heap.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "waiting", value)
+	assert.Equal(t, uint(0), priority)
+}
+
+func TestAgingHeap_RefreshInterval(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewAgingHeap[string, int](lt, LinearAging(1, time.Second), time.Minute, clock.Now, DaryHeapConfig{})
+	heap.Push("old", 10)
+	clock.Advance(30 * time.Second)
+	heap.Push("new", 5)
+
+	// The old element has aged to -20, but priorities are not re-evaluated
+	// until a minute has passed since the heap was created.
+	value, priority, err := heap.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "new", value)
+	assert.Equal(t, 5, priority)
+
+	clock.Advance(30 * time.Second)
+	value, priority, err = heap.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "old", value)
+	assert.Equal(t, -50, priority)
+	require.NoError(t, heap.Verify())
+
+	clock.Advance(10 * time.Second)
+	require.NoError(t, heap.Maintain(context.Background()))
+	priority, err = heap.PeekPriority()
+	require.NoError(t, err)
+	assert.Equal(t, -60, priority)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, heap.Maintain(ctx), context.Canceled)
+}
+
+func TestSyncAgingHeap(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewSyncAgingHeap[int, float64](gtFloat, LinearAging(-0.5, time.Second), 0, clock.Now, DaryHeapConfig{})
+	heap.Push(1, 3)
+	heap.Push(2, 1)
+	clock.Advance(5 * time.Second)
+	heap.Push(3, 3)
+
+	assert.Equal(t, 3, heap.Length())
+	assert.Equal(t, []int{1, 2, 3}, heap.DrainValues())
+	_, _, err := heap.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
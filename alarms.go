@@ -0,0 +1,94 @@
+package heapcraft
+
+// DepthEvent describes a heap's length crossing the threshold of a depth
+// alarm. Rising is true when the length reached the threshold from below and
+// false when it dropped back below the alarm's reset level.
+type DepthEvent struct {
+	Threshold int
+	Length    int
+	Rising    bool
+}
+
+// depthAlarm is a single threshold watcher. Once raised it stays raised until
+// the length falls to reset, so a length hovering around the threshold does
+// not fire the alarm on every push and pop.
+type depthAlarm struct {
+	threshold int
+	reset     int
+	raised    bool
+	fn        func(DepthEvent)
+}
+
+// depthAlarms maintains a registry of depth alarms (ID → alarm). It is nil
+// until the first alarm is registered so heaps without alarms pay only a
+// length check on each mutation.
+type depthAlarms map[string]*depthAlarm
+
+// register adds an alarm for threshold n to the registry, creating it if
+// needed, and returns its unique ID. The alarm starts raised if length is
+// already at or above n, without firing.
+func (a *depthAlarms) register(n int, length int, fn func(DepthEvent)) string {
+	if n < 1 {
+		n = 1
+	}
+	if *a == nil {
+		*a = make(depthAlarms)
+	}
+
+	id := registrationIDs.Next()
+	(*a)[id] = &depthAlarm{
+		threshold: n,
+		reset:     n - max(1, n/10),
+		raised:    length >= n,
+		fn:        fn,
+	}
+	return id
+}
+
+// deregister removes the alarm with the specified ID, returning an error if it
+// does not exist.
+func (a depthAlarms) deregister(id string) error {
+	if _, exists := a[id]; !exists {
+		return ErrCallbackNotFound
+	}
+	delete(a, id)
+	return nil
+}
+
+// check fires every alarm whose threshold the new length has crossed.
+func (a depthAlarms) check(length int) {
+	if len(a) == 0 {
+		return
+	}
+	a.fire(length)
+}
+
+// fire updates the state of each alarm for the new length and invokes the
+// ones that changed state.
+func (a depthAlarms) fire(length int) {
+	for _, alarm := range a {
+		switch {
+		case !alarm.raised && length >= alarm.threshold:
+			alarm.raised = true
+		case alarm.raised && length <= alarm.reset:
+			alarm.raised = false
+		default:
+			continue
+		}
+		alarm.fn(DepthEvent{Threshold: alarm.threshold, Length: length, Rising: alarm.raised})
+	}
+}
+
+// clone returns a copy of the registry in which each alarm keeps its current
+// state but is independent of the original.
+func (a depthAlarms) clone() depthAlarms {
+	if a == nil {
+		return nil
+	}
+	cloned := make(depthAlarms, len(a))
+	for id, alarm := range a {
+		copied := *alarm
+		cloned[id] = &copied
+	}
+	return cloned
+}
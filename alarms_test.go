@@ -0,0 +1,145 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alarmHeap is a heap that supports depth alarms.
+type alarmHeap interface {
+	Heap[int, int]
+	RegisterDepthAlarm(n int, fn func(DepthEvent)) string
+	RemoveDepthAlarm(id string) error
+}
+
+func TestHeap_DepthAlarm(t *testing.T) {
+	heaps := map[string]func() alarmHeap{
+		"dary":             func() alarmHeap { return NewDaryHeap[int, int](3, nil, lt, false) },
+		"syncDary":         func() alarmHeap { return NewSyncDaryHeap[int, int](3, nil, lt, true) },
+		"pairing":          func() alarmHeap { return NewPairingHeap[int, int](nil, lt, false) },
+		"syncPairing":      func() alarmHeap { return NewSyncPairingHeap[int, int](nil, lt, true) },
+		"leftist":          func() alarmHeap { return NewLeftistHeap[int, int](nil, lt, false) },
+		"syncLeftist":      func() alarmHeap { return NewSyncLeftistHeap[int, int](nil, lt, true) },
+		"skew":             func() alarmHeap { return NewSkewHeap[int, int](nil, lt, false) },
+		"syncSkew":         func() alarmHeap { return NewSyncSkewHeap[int, int](nil, lt, true) },
+		"binomial":         func() alarmHeap { return NewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncBinomial":     func() alarmHeap { return NewSyncBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"skewBinomial":     func() alarmHeap { return NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncSkewBinomial": func() alarmHeap { return NewSyncSkewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}) },
+		"adaptive":         func() alarmHeap { return NewAdaptiveHeap[int, int](nil, lt, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		var events []DepthEvent
+		id := heap.RegisterDepthAlarm(20, func(e DepthEvent) { events = append(events, e) })
+
+		for i := 0; i < 25; i++ {
+			heap.Push(i, i)
+		}
+		require.Len(t, events, 1, name)
+		assert.Equal(t, DepthEvent{Threshold: 20, Length: 20, Rising: true}, events[0], name)
+
+		// Popping below the threshold but above the reset level does not fire.
+		for heap.Length() > 19 {
+			heap.Pop()
+		}
+		heap.Push(0, 0)
+		heap.Pop()
+		assert.Len(t, events, 1, name)
+
+		for heap.Length() > 18 {
+			heap.Pop()
+		}
+		require.Len(t, events, 2, name)
+		assert.Equal(t, DepthEvent{Threshold: 20, Length: 18, Rising: false}, events[1], name)
+
+		require.Nil(t, heap.RemoveDepthAlarm(id), name)
+		assert.ErrorIs(t, heap.RemoveDepthAlarm(id), ErrCallbackNotFound, name)
+		heap.Clear()
+		assert.Len(t, events, 2, name)
+	}
+}
+
+func TestTrackedHeap_DepthAlarm(t *testing.T) {
+	type trackedAlarmHeap interface {
+		TrackedHeap[int, int]
+		RegisterDepthAlarm(n int, fn func(DepthEvent)) string
+	}
+
+	heaps := map[string]func() trackedAlarmHeap{
+		"pairing":     func() trackedAlarmHeap { return NewFullPairingHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncPairing": func() trackedAlarmHeap { return NewSyncFullPairingHeap[int, int](nil, lt, HeapConfig{}) },
+		"leftist":     func() trackedAlarmHeap { return NewFullLeftistHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncLeftist": func() trackedAlarmHeap { return NewSyncFullLeftistHeap[int, int](nil, lt, HeapConfig{}) },
+		"skew":        func() trackedAlarmHeap { return NewFullSkewHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncSkew":    func() trackedAlarmHeap { return NewSyncFullSkewHeap[int, int](nil, lt, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		var events []DepthEvent
+		heap.RegisterDepthAlarm(2, func(e DepthEvent) { events = append(events, e) })
+
+		heap.Push(1, 1)
+		id, err := heap.Push(2, 2)
+		require.Nil(t, err, name)
+		require.Len(t, events, 1, name)
+		assert.True(t, events[0].Rising, name)
+
+		_, _, err = heap.Remove(id)
+		require.Nil(t, err, name)
+		require.Len(t, events, 2, name)
+		assert.Equal(t, DepthEvent{Threshold: 2, Length: 1, Rising: false}, events[1], name)
+	}
+}
+
+func TestRadixHeap_DepthAlarm(t *testing.T) {
+	heap := NewRadixHeap[int, uint](nil, false)
+	var events []DepthEvent
+	heap.RegisterDepthAlarm(3, func(e DepthEvent) { events = append(events, e) })
+
+	heap.Push(1, 5)
+	heap.Push(2, 6)
+	other := NewRadixHeap[int, uint]([]HeapNode[int, uint]{CreateHeapNode(3, uint(1))}, false)
+	heap.Merge(other)
+	require.Len(t, events, 1)
+	assert.Equal(t, DepthEvent{Threshold: 3, Length: 3, Rising: true}, events[0])
+
+	heap.Pop()
+	require.Len(t, events, 2)
+	assert.False(t, events[1].Rising)
+}
+
+func TestDepthAlarm_RegisterAboveThreshold(t *testing.T) {
+	heap := NewBinaryHeap[int, int](nil, lt, false)
+	for i := 0; i < 5; i++ {
+		heap.Push(i, i)
+	}
+
+	var events []DepthEvent
+	heap.RegisterDepthAlarm(3, func(e DepthEvent) { events = append(events, e) })
+	heap.Push(5, 5)
+	assert.Empty(t, events)
+
+	heap.Clear()
+	require.Len(t, events, 1)
+	assert.Equal(t, DepthEvent{Threshold: 3, Length: 0, Rising: false}, events[0])
+}
+
+func TestDepthAlarm_Clone(t *testing.T) {
+	heap := NewBinaryHeap[int, int](nil, lt, false)
+	fired := 0
+	heap.RegisterDepthAlarm(2, func(DepthEvent) { fired++ })
+	heap.Push(1, 1)
+
+	cloned := heap.Clone()
+	cloned.Push(2, 2)
+	assert.Equal(t, 1, fired)
+
+	// The original alarm has not been raised by the clone's push.
+	heap.Push(2, 2)
+	assert.Equal(t, 2, fired)
+}
@@ -0,0 +1,69 @@
+package heapcraft
+
+// DefaultArenaSlabSize is the number of nodes per slab used by the arena
+// constructors when no positive slab size is given.
+const DefaultArenaSlabSize = 4096
+
+// arenaPool is a pool that carves nodes out of large slabs instead of
+// allocating them one by one, so a heap of millions of nodes costs the garbage
+// collector a few thousand objects. Slots are never reused: released nodes are
+// simply dropped, and a slab is reclaimed by the garbage collector once none of
+// its nodes is reachable, so a heap that is built once and drained does no
+// bookkeeping per pop. reset drops the current slab, which is how Clear
+// releases an arena-backed heap in O(1). It is not safe for concurrent use;
+// heaps only call it while holding their write lock.
+type arenaPool[N any] struct {
+	slabSize int
+	slab     []N
+	misses   uint64
+}
+
+// Get returns the next slot of the current slab, starting a new slab when it
+// is full.
+func (p *arenaPool[N]) Get() *N {
+	if len(p.slab) == cap(p.slab) {
+		p.slab = make([]N, 0, p.slabSize)
+	}
+	p.slab = p.slab[:len(p.slab)+1]
+	p.misses++
+	return &p.slab[len(p.slab)-1]
+}
+
+// Put is a no-op for the arena pool; the node's slot is reclaimed with its
+// slab.
+func (p *arenaPool[N]) Put(node *N) {}
+
+// fresh returns a new, empty arena pool with the same slab size.
+func (p *arenaPool[N]) fresh() pool[*N] { return newArenaPool[N](p.slabSize) }
+
+// stats reports every slot handed out as a miss, since none are reused.
+func (p *arenaPool[N]) stats() PoolStats { return PoolStats{Misses: p.misses} }
+
+// idle returns the number of slots left in the current slab.
+func (p *arenaPool[N]) idle() int { return cap(p.slab) - len(p.slab) }
+
+// reserve starts a new slab with room for at least n nodes if the current one
+// has fewer free slots left.
+func (p *arenaPool[N]) reserve(n int) {
+	if cap(p.slab)-len(p.slab) < n {
+		p.slab = make([]N, 0, max(n, p.slabSize))
+	}
+}
+
+// reset forgets the current slab, leaving it and every earlier slab to the
+// garbage collector. Slots are never handed out twice, so nodes that are still
+// referenced elsewhere stay valid.
+func (p *arenaPool[N]) reset() { p.slab = nil }
+
+// newArenaPool creates an arena pool with slabs of slabSize nodes, or
+// DefaultArenaSlabSize if slabSize is not positive.
+func newArenaPool[N any](slabSize int) pool[*N] {
+	if slabSize <= 0 {
+		slabSize = DefaultArenaSlabSize
+	}
+	return &arenaPool[N]{slabSize: slabSize}
+}
+
+// resetter is implemented by pools that can release all of their nodes at
+// once.
+type resetter interface{ reset() }
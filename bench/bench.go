@@ -0,0 +1,311 @@
+// Package bench is a benchmark suite that runs every heap in heapcraft through
+// the same mixed workloads, so that structures can be compared side by side
+// and regressions spotted between releases.
+//
+// A Workload describes how many elements the heap starts with, the share of
+// pushes, pops and priority updates among the operations, and whether pushed
+// priorities are random, ascending or descending. A Subject adapts one heap
+// to the small Queue interface the workloads drive. Bench runs one pair as a
+// standard Go benchmark, reporting the time per operation, so results can be
+// compared across releases with benchstat:
+//
+//	go test ./bench -bench . -count 10 > new.txt
+//	benchstat old.txt new.txt
+//
+// Run measures every pair and WriteTable prints the results as a table with a
+// row per subject and a column per workload. cmd/heapbench does both.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+	"text/tabwriter"
+)
+
+// Queue is the part of a heap that a workload drives. Values pushed by a
+// workload are unique, so Update can identify an element by its value.
+type Queue interface {
+	Push(value int, priority int)
+	Pop() (int, int, error)
+	Length() int
+}
+
+// Updater is implemented by queues that can change the priority of an element
+// they hold. Workloads that update priorities only run on such queues.
+type Updater interface {
+	Update(value int, priority int) error
+}
+
+// Subject is a heap under test.
+type Subject struct {
+	// Name identifies the subject in benchmark names and tables.
+	Name string
+	// New returns an empty min-heap.
+	New func() Queue
+}
+
+// InputOrder determines the priorities pushed by a workload.
+type InputOrder int
+
+const (
+	// Random pushes uniformly random priorities.
+	Random InputOrder = iota
+	// Ascending pushes priorities in increasing order, so every element is
+	// pushed behind all the others.
+	Ascending
+	// Descending pushes priorities in decreasing order, so every element
+	// becomes the new root.
+	Descending
+)
+
+// String returns the lower-case name of the order.
+func (o InputOrder) String() string {
+	switch o {
+	case Random:
+		return "random"
+	case Ascending:
+		return "ascending"
+	case Descending:
+		return "descending"
+	}
+	return "unknown"
+}
+
+// Workload is a mix of heap operations.
+type Workload struct {
+	// Name identifies the workload in benchmark names and tables.
+	Name string
+	// Size is the number of elements pushed before timing starts.
+	Size int
+	// Push, Pop and Update are the relative weights of each operation. A pop
+	// or update on an empty heap is replaced by a push.
+	Push, Pop, Update int
+	// Order determines the pushed priorities.
+	Order InputOrder
+	// Seed seeds the operation mix and random priorities.
+	Seed int64
+}
+
+// Workloads returns the default workloads: balanced, push-heavy and
+// pop-heavy mixes of random priorities, balanced mixes of sorted and reverse
+// sorted priorities, and a mix with priority updates.
+func Workloads() []Workload {
+	return []Workload{
+		{Name: "balanced", Size: 10_000, Push: 1, Pop: 1, Order: Random, Seed: 1},
+		{Name: "push-heavy", Size: 10_000, Push: 3, Pop: 1, Order: Random, Seed: 1},
+		{Name: "pop-heavy", Size: 100_000, Push: 1, Pop: 3, Order: Random, Seed: 1},
+		{Name: "ascending", Size: 10_000, Push: 1, Pop: 1, Order: Ascending, Seed: 1},
+		{Name: "descending", Size: 10_000, Push: 1, Pop: 1, Order: Descending, Seed: 1},
+		{Name: "updates", Size: 10_000, Push: 2, Pop: 2, Update: 1, Order: Random, Seed: 1},
+	}
+}
+
+// Supports reports whether subject can run workload, which it cannot if the
+// workload updates priorities and the subject's queues do not implement
+// Updater.
+func (w Workload) Supports(subject Subject) bool {
+	if w.Update == 0 {
+		return true
+	}
+	_, ok := subject.New().(Updater)
+	return ok
+}
+
+// op is one operation of a workload.
+type op uint8
+
+const (
+	opPush op = iota
+	opPop
+	opUpdate
+)
+
+// cycleLength is the number of operations and random priorities generated
+// up front. Longer runs cycle through them, which keeps random number
+// generation out of the measured time.
+const cycleLength = 1 << 12
+
+// driver runs a workload against a queue. It keeps the values held by the
+// queue in live, with their positions in pos, so that updates can pick an
+// element at random in O(1).
+type driver struct {
+	queue      Queue
+	updater    Updater
+	order      InputOrder
+	ops        []op
+	priorities []int
+	next       int
+	live       []int
+	pos        []int
+}
+
+// newDriver creates the queue for subject, generates the operations of
+// workload and pushes its initial elements.
+func newDriver(subject Subject, workload Workload) *driver {
+	rng := rand.New(rand.NewSource(workload.Seed))
+	d := &driver{queue: subject.New(), order: workload.Order}
+	d.updater, _ = d.queue.(Updater)
+
+	total := workload.Push + workload.Pop + workload.Update
+	d.ops = make([]op, cycleLength)
+	d.priorities = make([]int, cycleLength)
+	for i := range d.ops {
+		switch n := rng.Intn(max(total, 1)); {
+		case n < workload.Push || total == 0:
+			d.ops[i] = opPush
+		case n < workload.Push+workload.Pop:
+			d.ops[i] = opPop
+		default:
+			d.ops[i] = opUpdate
+		}
+		d.priorities[i] = rng.Intn(1 << 30)
+	}
+	for i := 0; i < workload.Size; i++ {
+		d.push(i)
+	}
+	return d
+}
+
+// priority returns the priority for the ith operation.
+func (d *driver) priority(i int) int {
+	switch d.order {
+	case Ascending:
+		return d.next
+	case Descending:
+		return -d.next
+	}
+	return d.priorities[i%cycleLength]
+}
+
+// push pushes a new element, tracking its value if the queue takes updates.
+func (d *driver) push(i int) {
+	value := d.next
+	d.queue.Push(value, d.priority(i))
+	d.next++
+	if d.updater != nil {
+		d.pos = append(d.pos, len(d.live))
+		d.live = append(d.live, value)
+	}
+}
+
+// step runs the ith operation of the workload.
+func (d *driver) step(i int) {
+	o := d.ops[i%cycleLength]
+	if o != opPush && d.queue.Length() == 0 {
+		o = opPush
+	}
+	switch o {
+	case opPush:
+		d.push(i)
+	case opPop:
+		value, _, _ := d.queue.Pop()
+		if d.updater != nil {
+			d.forget(value)
+		}
+	case opUpdate:
+		value := d.live[d.priorities[i%cycleLength]%len(d.live)]
+		d.updater.Update(value, d.priority(i))
+	}
+}
+
+// forget removes a popped value from live by moving the last value into its
+// place.
+func (d *driver) forget(value int) {
+	i, last := d.pos[value], d.live[len(d.live)-1]
+	d.live[i], d.pos[last] = last, i
+	d.live = d.live[:len(d.live)-1]
+}
+
+// Bench runs workload against subject as a Go benchmark, timing one
+// operation per iteration. Setup, including the initial pushes, is not
+// timed. It skips the benchmark if the subject does not support the workload.
+func Bench(b *testing.B, subject Subject, workload Workload) {
+	if !workload.Supports(subject) {
+		b.Skipf("%s does not support priority updates", subject.Name)
+	}
+	d := newDriver(subject, workload)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.step(i)
+	}
+}
+
+// Result is the outcome of running one workload against one subject.
+type Result struct {
+	Subject  string
+	Workload string
+	// Skipped is set if the subject does not support the workload, in which
+	// case the measurements are zero.
+	Skipped     bool
+	NsPerOp     float64
+	AllocsPerOp int64
+}
+
+// Measure runs workload against subject with testing.Benchmark and returns
+// the time and allocations per operation.
+func Measure(subject Subject, workload Workload) Result {
+	result := Result{Subject: subject.Name, Workload: workload.Name}
+	if !workload.Supports(subject) {
+		result.Skipped = true
+		return result
+	}
+	r := testing.Benchmark(func(b *testing.B) { Bench(b, subject, workload) })
+	result.NsPerOp = float64(r.T.Nanoseconds()) / float64(max(r.N, 1))
+	result.AllocsPerOp = r.AllocsPerOp()
+	return result
+}
+
+// Run measures every workload against every subject, in order.
+func Run(subjects []Subject, workloads []Workload) []Result {
+	results := make([]Result, 0, len(subjects)*len(workloads))
+	for _, subject := range subjects {
+		for _, workload := range workloads {
+			results = append(results, Measure(subject, workload))
+		}
+	}
+	return results
+}
+
+// WriteTable writes results as an aligned table with a row per subject and a
+// column per workload, in the order they first appear in results. Each cell
+// shows the time per operation in nanoseconds, and unsupported pairs show
+// "-".
+func WriteTable(w io.Writer, results []Result) error {
+	var subjects, workloads []string
+	cells := make(map[[2]string]Result, len(results))
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if !seen["s"+r.Subject] {
+			seen["s"+r.Subject] = true
+			subjects = append(subjects, r.Subject)
+		}
+		if !seen["w"+r.Workload] {
+			seen["w"+r.Workload] = true
+			workloads = append(workloads, r.Workload)
+		}
+		cells[[2]string{r.Subject, r.Workload}] = r
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "ns/op\t")
+	for _, workload := range workloads {
+		fmt.Fprintf(tw, "%s\t", workload)
+	}
+	fmt.Fprintln(tw)
+	for _, subject := range subjects {
+		fmt.Fprintf(tw, "%s\t", subject)
+		for _, workload := range workloads {
+			r, ok := cells[[2]string{subject, workload}]
+			if !ok || r.Skipped {
+				fmt.Fprint(tw, "-\t")
+				continue
+			}
+			fmt.Fprintf(tw, "%.0f\t", r.NsPerOp)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,66 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/galactixx/heapcraft"
+)
+
+func BenchmarkSuite(b *testing.B) {
+	for _, workload := range Workloads() {
+		for _, subject := range Subjects() {
+			b.Run(workload.Name+"/"+subject.Name, func(b *testing.B) {
+				Bench(b, subject, workload)
+			})
+		}
+	}
+}
+
+func TestDriver_KeepsQueuesConsistent(t *testing.T) {
+	workload := Workload{Size: 100, Push: 2, Pop: 2, Update: 1, Seed: 3}
+	for _, subject := range Subjects() {
+		if !workload.Supports(subject) {
+			continue
+		}
+		d := newDriver(subject, workload)
+		for i := 0; i < 5000; i++ {
+			d.step(i)
+		}
+		if d.queue.Length() != len(d.live) {
+			t.Fatalf("%s holds %d elements, driver tracks %d", subject.Name, d.queue.Length(), len(d.live))
+		}
+		if v, ok := d.queue.(heapcraft.Verifier); ok {
+			if err := v.Verify(); err != nil {
+				t.Fatalf("%s: %v", subject.Name, err)
+			}
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	results := []Result{
+		{Subject: "dary-2", Workload: "balanced", NsPerOp: 41.6},
+		{Subject: "dary-2", Workload: "updates", Skipped: true},
+		{Subject: "full-pairing", Workload: "balanced", NsPerOp: 120},
+		{Subject: "full-pairing", Workload: "updates", NsPerOp: 230.2},
+	}
+	var out bytes.Buffer
+	if err := WriteTable(&out, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("table has %d lines, want 3:\n%s", len(lines), out.String())
+	}
+	for i, want := range [][]string{
+		{"ns/op", "balanced", "updates"},
+		{"dary-2", "42", "-"},
+		{"full-pairing", "120", "230"},
+	} {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("line %d is %q, want %q", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package bench
+
+import (
+	"strconv"
+
+	"github.com/galactixx/heapcraft"
+)
+
+func lt(a, b int) bool { return a < b }
+
+// simpleQueue adapts a heapcraft.Heap to Queue.
+type simpleQueue struct {
+	heapcraft.Heap[int, int]
+}
+
+// trackedQueue adapts a heapcraft.TrackedHeap to Queue and Updater. It
+// remembers the ID returned for every value, which the integer ID generator
+// makes equal to the value's position in push order.
+type trackedQueue struct {
+	heap heapcraft.TrackedHeap[int, int]
+	ids  []string
+}
+
+func (q *trackedQueue) Push(value int, priority int) {
+	id, _ := q.heap.Push(value, priority)
+	q.ids = append(q.ids, id)
+}
+
+func (q *trackedQueue) Pop() (int, int, error) { return q.heap.Pop() }
+func (q *trackedQueue) Length() int            { return q.heap.Length() }
+
+func (q *trackedQueue) Update(value int, priority int) error {
+	return q.heap.UpdatePriority(q.ids[value], priority)
+}
+
+// keyedQueue adapts a heapcraft.KeyedHeap to Queue and Updater, using each
+// value as its own key.
+type keyedQueue struct {
+	heap *heapcraft.KeyedHeap[int, int, int]
+}
+
+func (q *keyedQueue) Push(value int, priority int) { q.heap.Push(value, value, priority) }
+func (q *keyedQueue) Pop() (int, int, error)       { return q.heap.Pop() }
+func (q *keyedQueue) Length() int                  { return q.heap.Length() }
+
+func (q *keyedQueue) Update(value int, priority int) error {
+	return q.heap.UpdatePriority(value, priority)
+}
+
+// simple returns a Subject for a heap without updates.
+func simple(name string, newHeap func() heapcraft.Heap[int, int]) Subject {
+	return Subject{Name: name, New: func() Queue { return simpleQueue{newHeap()} }}
+}
+
+// tracked returns a Subject for a heap with updates by ID.
+func tracked(name string, newHeap func(config heapcraft.HeapConfig) heapcraft.TrackedHeap[int, int]) Subject {
+	return Subject{Name: name, New: func() Queue {
+		config := heapcraft.HeapConfig{IDGenerator: &heapcraft.IntegerIDGenerator{}}
+		return &trackedQueue{heap: newHeap(config)}
+	}}
+}
+
+// Subjects returns the default subjects: d-ary heaps with d of 2, 3, 4, 8 and
+// 16, the pairing, leftist, skew, binomial, skew binomial and adaptive heaps,
+// and the heaps that support priority updates.
+func Subjects() []Subject {
+	subjects := make([]Subject, 0, 16)
+	for _, d := range []int{2, 3, 4, 8, 16} {
+		subjects = append(subjects, simple("dary-"+strconv.Itoa(d), func() heapcraft.Heap[int, int] {
+			return heapcraft.NewDaryHeapWithConfig[int, int](d, nil, lt, heapcraft.DaryHeapConfig{})
+		}))
+	}
+	return append(subjects,
+		simple("pairing", func() heapcraft.Heap[int, int] {
+			return heapcraft.NewSimplePairingHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}),
+		simple("leftist", func() heapcraft.Heap[int, int] {
+			return heapcraft.NewSimpleLeftistHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}),
+		simple("skew", func() heapcraft.Heap[int, int] {
+			return heapcraft.NewSimpleSkewHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}),
+		simple("binomial", func() heapcraft.Heap[int, int] {
+			return heapcraft.NewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}),
+		simple("skew-binomial", func() heapcraft.Heap[int, int] {
+			return heapcraft.NewSkewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}),
+		simple("adaptive", func() heapcraft.Heap[int, int] {
+			return heapcraft.NewAdaptiveHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}),
+		tracked("full-pairing", func(config heapcraft.HeapConfig) heapcraft.TrackedHeap[int, int] {
+			return heapcraft.NewFullPairingHeap[int, int](nil, lt, config)
+		}),
+		tracked("full-leftist", func(config heapcraft.HeapConfig) heapcraft.TrackedHeap[int, int] {
+			return heapcraft.NewFullLeftistHeap[int, int](nil, lt, config)
+		}),
+		tracked("full-skew", func(config heapcraft.HeapConfig) heapcraft.TrackedHeap[int, int] {
+			return heapcraft.NewFullSkewHeap[int, int](nil, lt, config)
+		}),
+		Subject{Name: "keyed-4", New: func() Queue {
+			return &keyedQueue{heap: heapcraft.NewKeyedHeap[int, int, int](4, lt, heapcraft.DaryHeapConfig{})}
+		}},
+	)
+}
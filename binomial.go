@@ -0,0 +1,530 @@
+package heapcraft
+
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
+// binomialNode represents a node in a binomial heap. Each node is the root of
+// a binomial tree of the given degree, whose children are linked through the
+// sibling pointer in decreasing order of degree.
+type binomialNode[V any, P any] struct {
+	value    V
+	priority P
+	degree   int
+	child    *binomialNode[V, P]
+	sibling  *binomialNode[V, P]
+}
+
+// Value returns the value stored in the node.
+func (n *binomialNode[V, P]) Value() V { return n.value }
+
+// Priority returns the priority of the node.
+func (n *binomialNode[V, P]) Priority() P { return n.priority }
+
+// BinomialHeap implements a binomial heap, a forest of binomial trees kept in
+// increasing order of degree with at most one tree per degree. It supports
+// O(log n) insertion, removal and melding of two heaps. The heap can be either
+// a min-heap or max-heap depending on the comparison function.
+type BinomialHeap[V any, P any] struct {
+	head   *binomialNode[V, P]
+	cmp    func(a, b P) bool
+	size   int
+	pool   pool[*binomialNode[V, P]]
+	alarms depthAlarms
+	stats  heapStats
+	events listeners[HeapEvent[V, P]]
+}
+
+// cloneNode creates a deep copy of a binomial node.
+// It recursively clones the child and sibling.
+func (b *BinomialHeap[V, P]) cloneNode(node *binomialNode[V, P]) *binomialNode[V, P] {
+	if node == nil {
+		return nil
+	}
+
+	cloned := b.pool.Get()
+	cloned.value = node.value
+	cloned.priority = node.priority
+	cloned.degree = node.degree
+	cloned.child = b.cloneNode(node.child)
+	cloned.sibling = b.cloneNode(node.sibling)
+	return cloned
+}
+
+// Clone creates a deep copy of the heap structure and nodes. If values or
+// priorities are reference types, those reference values are shared between the
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other.
+func (b *BinomialHeap[V, P]) Clone() *BinomialHeap[V, P] {
+	cloned := &BinomialHeap[V, P]{
+		cmp:    b.cmp,
+		size:   b.size,
+		pool:   b.pool.fresh(),
+		alarms: b.alarms.clone(),
+		stats:  b.stats,
+		events: b.events.clone(),
+	}
+	cloned.head = cloned.cloneNode(b.head)
+	return cloned
+}
+
+// Clear removes all elements from the heap.
+// The heap is ready for new insertions after clearing.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
+func (b *BinomialHeap[V, P]) Clear() {
+	cleared := b.Length()
+	releaseTree(b.pool, treeRoots(b.head), func(n *binomialNode[V, P], visit func(*binomialNode[V, P])) {
+		if n.child != nil {
+			visit(n.child)
+		}
+		if n.sibling != nil {
+			visit(n.sibling)
+		}
+	})
+	b.head = nil
+	b.size = 0
+	b.stats.record(OpClear, cleared, b.size)
+	b.alarms.check(b.size)
+	emitClearEvent(b.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (b *BinomialHeap[V, P]) PoolStats() PoolStats { return b.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes and the
+// nodes idle in its pool. Memory that values and priorities point to, such as
+// the contents of strings, is not counted.
+func (b *BinomialHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[BinomialHeap[V, P]]() + nodeMemory(b.size, b.pool)
+}
+
+// Length returns the current number of elements in the heap.
+func (b *BinomialHeap[V, P]) Length() int { return b.size }
+
+// IsEmpty returns true if the heap contains no elements.
+func (b *BinomialHeap[V, P]) IsEmpty() bool { return b.size == 0 }
+
+// Verify checks the internal consistency of the heap: the root list must be
+// in strictly increasing order of degree, every node of degree k must have
+// children of degrees k-1 down to 0 in that order, no node may come before its
+// parent, and the trees must hold exactly Length nodes. It is intended for
+// tests and debugging, runs in O(n) and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (b *BinomialHeap[V, P]) Verify() error {
+	var roots []*binomialNode[V, P]
+	for root := b.head; root != nil; root = root.sibling {
+		if len(roots) > 0 && roots[len(roots)-1].degree >= root.degree {
+			return invariantError("root of degree %d follows a root of degree %d", root.degree, roots[len(roots)-1].degree)
+		}
+		roots = append(roots, root)
+	}
+
+	visited, err := verifyTree(roots,
+		func(node *binomialNode[V, P], visit func(*binomialNode[V, P])) {
+			for child := node.child; child != nil; child = child.sibling {
+				visit(child)
+			}
+		},
+		func(node, parent *binomialNode[V, P]) error {
+			degree := node.degree - 1
+			for child := node.child; child != nil; child = child.sibling {
+				if child.degree != degree {
+					return invariantError("node of degree %d has a child of degree %d instead of %d", node.degree, child.degree, degree)
+				}
+				degree--
+			}
+			if degree != -1 {
+				return invariantError("node of degree %d has %d children", node.degree, node.degree-degree-1)
+			}
+			if parent == nil {
+				return nil
+			}
+			return verifyOrder(b.cmp, node.priority, parent.priority)
+		})
+	if err != nil {
+		return err
+	}
+	return verifySize(visited, b.size)
+}
+
+// findRoot scans the root list and returns the root with the highest priority
+// (according to cmp) together with the root preceding it in the list.
+// The caller must ensure the heap is not empty.
+func (b *BinomialHeap[V, P]) findRoot() (*binomialNode[V, P], *binomialNode[V, P]) {
+	var prev, prevBest *binomialNode[V, P]
+	best := b.head
+	for cur := b.head; cur != nil; prev, cur = cur, cur.sibling {
+		if b.cmp(cur.priority, best.priority) {
+			best, prevBest = cur, prev
+		}
+	}
+	return best, prevBest
+}
+
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (b *BinomialHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return b.alarms.register(n, b.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (b *BinomialHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return b.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (b *BinomialHeap[V, P]) Stats() HeapStats { return b.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (b *BinomialHeap[V, P]) Instrument(hook Instrumentation) { b.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element melded in from another heap, and whenever the heap is
+// cleared. Returns an ID that can be passed to RemoveListener.
+func (b *BinomialHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return b.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (b *BinomialHeap[V, P]) RemoveListener(id string) error { return b.events.deregister(id) }
+
+// peek is an internal method that returns the root node's value and priority
+// without removing it. Returns zero values and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) peek() (V, P, error) {
+	if b.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	root, _ := b.findRoot()
+	return root.value, root.priority, nil
+}
+
+// Peek returns the value and priority of the root element without removing it.
+// Returns zero values and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) Peek() (V, P, error) { return b.peek() }
+
+// PeekValue returns the value at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(b.peek())
+}
+
+// PeekPriority returns the priority at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(b.peek())
+}
+
+// link makes the tree rooted at child the first child of the tree rooted at
+// parent. Both trees must have the same degree.
+func (b *BinomialHeap[V, P]) link(child, parent *binomialNode[V, P]) {
+	child.sibling = parent.child
+	parent.child = child
+	parent.degree++
+	b.stats.rebalance()
+}
+
+// mergeRoots merges two root lists, each sorted by increasing degree, into a
+// single root list sorted by increasing degree. Trees of equal degree are not
+// yet combined.
+func (b *BinomialHeap[V, P]) mergeRoots(x, y *binomialNode[V, P]) *binomialNode[V, P] {
+	var head binomialNode[V, P]
+	tail := &head
+	for x != nil && y != nil {
+		if x.degree <= y.degree {
+			tail.sibling, x = x, x.sibling
+		} else {
+			tail.sibling, y = y, y.sibling
+		}
+		tail = tail.sibling
+	}
+
+	if x != nil {
+		tail.sibling = x
+	} else {
+		tail.sibling = y
+	}
+	return head.sibling
+}
+
+// union combines two root lists into a single valid binomial root list,
+// linking trees of equal degree so that at most one tree of each degree
+// remains. Returns the head of the resulting root list.
+func (b *BinomialHeap[V, P]) union(x, y *binomialNode[V, P]) *binomialNode[V, P] {
+	head := b.mergeRoots(x, y)
+	if head == nil {
+		return nil
+	}
+
+	var prev *binomialNode[V, P]
+	cur := head
+	next := cur.sibling
+	for next != nil {
+		switch {
+		case cur.degree != next.degree ||
+			(next.sibling != nil && next.sibling.degree == cur.degree):
+			prev, cur = cur, next
+		case !b.cmp(next.priority, cur.priority):
+			cur.sibling = next.sibling
+			b.link(next, cur)
+		default:
+			if prev == nil {
+				head = next
+			} else {
+				prev.sibling = next
+			}
+			b.link(cur, next)
+			cur = next
+		}
+		next = cur.sibling
+	}
+	return head
+}
+
+// pop is an internal method that removes the root node and returns it.
+// The children of the removed root are reversed into a root list and
+// unioned back into the heap. Returns zero values and an error if the
+// heap is empty.
+func (b *BinomialHeap[V, P]) pop() (V, P, error) {
+	if b.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+
+	removed, prev := b.findRoot()
+	if prev == nil {
+		b.head = removed.sibling
+	} else {
+		prev.sibling = removed.sibling
+	}
+
+	// Children are stored in decreasing order of degree, so reverse them
+	// to form a valid root list before unioning.
+	var children *binomialNode[V, P]
+	for child := removed.child; child != nil; {
+		next := child.sibling
+		child.sibling = children
+		children = child
+		child = next
+	}
+
+	b.head = b.union(b.head, children)
+	b.size--
+	b.stats.record(OpPop, 1, b.size)
+	b.alarms.check(b.size)
+	removed.child, removed.sibling, removed.degree = nil, nil, 0
+	v, p := removed.value, removed.priority
+	b.pool.Put(removed)
+	emitHeapEvent(b.events, EventPop, "", v, p)
+	return v, p, nil
+}
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) Pop() (V, P, error) { return b.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(b.Peek, b.Pop, pred)
+}
+
+// PopValue removes and returns just the value at the root.
+// Returns zero value and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(b.pop())
+}
+
+// PopPriority removes and returns just the priority at the root.
+// Returns zero value and an error if the heap is empty.
+func (b *BinomialHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(b.pop())
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (b *BinomialHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(b.Length(), b.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (b *BinomialHeap[V, P]) DrainValues() []V {
+	return drainValues(b.Length(), b.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (b *BinomialHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(b.Length(), b.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (b *BinomialHeap[V, P]) forEach(fn func(v V, p P)) {
+	stack := make([]*binomialNode[V, P], 0)
+	if b.head != nil {
+		stack = append(stack, b.head)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(node.value, node.priority)
+		if node.child != nil {
+			stack = append(stack, node.child)
+		}
+		if node.sibling != nil {
+			stack = append(stack, node.sibling)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (b *BinomialHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(b.Length(), b.forEach, b.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (b *BinomialHeap[V, P]) ordered() iter.Seq[*binomialNode[V, P]] {
+	var roots []*binomialNode[V, P]
+	for root := b.head; root != nil; root = root.sibling {
+		roots = append(roots, root)
+	}
+	priority := func(n *binomialNode[V, P]) P { return n.priority }
+	return orderedNodes(b.cmp, roots, priority, func(n *binomialNode[V, P], visit func(*binomialNode[V, P])) {
+		for child := n.child; child != nil; child = child.sibling {
+			visit(child)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (b *BinomialHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(b.ordered(), func(n *binomialNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (b *BinomialHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, b.ordered(), func(n *binomialNode[V, P]) HeapNode[V, P] {
+		return HeapNode[V, P]{value: n.value, priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its priority and degree and drawing child and
+// sibling links. The root list is the chain of sibling links from the first
+// root. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (b *BinomialHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, treeRoots(b.head),
+		func(node *binomialNode[V, P]) string {
+			return fmt.Sprintf("%v\ndegree=%d", node.priority, node.degree)
+		},
+		func(node *binomialNode[V, P], edge func(dotEdge[*binomialNode[V, P]])) {
+			if node.child != nil {
+				edge(dotEdge[*binomialNode[V, P]]{label: "child", to: node.child})
+			}
+			if node.sibling != nil {
+				edge(dotEdge[*binomialNode[V, P]]{label: "sibling", to: node.sibling})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first. The comparison function is not encoded.
+func (b *BinomialHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalHeap(b.Export(ExportOptions[V, P]{}))
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. The heap must have been created with its constructor so that
+// it has a comparison function to restore them with.
+func (b *BinomialHeap[V, P]) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalHeap[V, P](data, b.cmp)
+	if err != nil {
+		return err
+	}
+
+	b.Clear()
+	for _, node := range decoded.Nodes {
+		b.Push(node.value, node.priority)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap in a compact binary format using
+// encoding/gob, for persisting the heap across process restarts. Elements are
+// written in internal order without sorting. The comparison function is not
+// encoded.
+func (b *BinomialHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotNodes(b.Length(), b.forEach))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. The heap must have been created with its constructor so
+// that it has a comparison function to restore them with.
+func (b *BinomialHeap[V, P]) Restore(data []byte) error {
+	if b.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	b.Clear()
+	for i, value := range snapshot.Values {
+		b.Push(value, snapshot.Priorities[i])
+	}
+	return nil
+}
+
+// Push adds a new element to the heap by creating a single-node binomial tree
+// and unioning it with the existing root list.
+func (b *BinomialHeap[V, P]) Push(value V, priority P) {
+	newNode := b.pool.Get()
+	newNode.value = value
+	newNode.priority = priority
+	newNode.degree = 0
+	b.head = b.union(newNode, b.head)
+	b.size++
+	b.stats.record(OpPush, 1, b.size)
+	b.alarms.check(b.size)
+	emitHeapEvent(b.events, EventPush, "", value, priority)
+}
+
+// Meld merges another binomial heap into this one in O(log n) time. The other
+// heap is consumed by the operation and left empty. Both heaps are expected to
+// share the same comparison function.
+func (b *BinomialHeap[V, P]) Meld(other *BinomialHeap[V, P]) {
+	if other == nil || other == b {
+		return
+	}
+	if len(b.events) > 0 {
+		other.forEach(func(v V, p P) { emitHeapEvent(b.events, EventPush, "", v, p) })
+	}
+	b.head = b.union(b.head, other.head)
+	b.size += other.size
+	b.stats.record(OpMeld, other.size, b.size)
+	b.alarms.check(b.size)
+	other.head = nil
+	other.Clear()
+}
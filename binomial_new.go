@@ -0,0 +1,31 @@
+package heapcraft
+
+// NewBinomialHeap creates a new binomial heap from the given data slice.
+// Each element is inserted individually using the provided comparison function
+// to determine heap order (min or max). Returns an empty heap if the input
+// slice is empty. Nodes are allocated as config selects: from an arena if
+// ArenaSlabSize is positive, otherwise from the pool chosen by UsePool. The
+// heap does not track IDs, so IDGenerator is ignored.
+func NewBinomialHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *BinomialHeap[V, P] {
+	pool := newConfiguredPool(config, func() *binomialNode[V, P] {
+		return &binomialNode[V, P]{}
+	})
+	heap := BinomialHeap[V, P]{cmp: cmp, size: 0, pool: pool}
+	if len(data) == 0 {
+		return &heap
+	}
+
+	for i := range data {
+		heap.Push(data[i].value, data[i].priority)
+	}
+	return &heap
+}
+
+// NewSyncBinomialHeap constructs a new thread-safe binomial heap from the given
+// data and comparison function. The resulting heap is safe for concurrent use.
+// See NewBinomialHeap.
+func NewSyncBinomialHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SyncBinomialHeap[V, P] {
+	return &SyncBinomialHeap[V, P]{
+		heap: NewBinomialHeap(data, cmp, config),
+	}
+}
@@ -0,0 +1,291 @@
+package heapcraft
+
+import (
+	"io"
+	"sync"
+)
+
+// SyncBinomialHeap provides a thread-safe wrapper around BinomialHeap.
+// It uses a read-write mutex to allow concurrent reads and exclusive writes.
+type SyncBinomialHeap[V any, P any] struct {
+	heap *BinomialHeap[V, P]
+	mu   sync.RWMutex
+}
+
+// Clone creates a deep copy of the heap structure and nodes. If values or
+// priorities are reference types, those reference values are shared between the
+// original and cloned heaps.
+func (s *SyncBinomialHeap[V, P]) Clone() *SyncBinomialHeap[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &SyncBinomialHeap[V, P]{heap: s.heap.Clone()}
+}
+
+// Clear removes all elements from the heap.
+// The heap is ready for new insertions after clearing.
+func (s *SyncBinomialHeap[V, P]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Clear()
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncBinomialHeap[V, P]) PoolStats() PoolStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncBinomialHeap[V, P]) ApproxMemoryUsage() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
+// Length returns the current number of elements in the heap.
+func (s *SyncBinomialHeap[V, P]) Length() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Length()
+}
+
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (s *SyncBinomialHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SyncBinomialHeap[V, P]) RemoveDepthAlarm(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncBinomialHeap[V, P]) Stats() HeapStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncBinomialHeap[V, P]) Instrument(hook Instrumentation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncBinomialHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncBinomialHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SyncBinomialHeap[V, P]) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncBinomialHeap[V, P]) Verify() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Verify()
+}
+
+// Peek returns the value and priority of the root element without removing it.
+// Returns zero values and an error if the heap is empty.
+func (s *SyncBinomialHeap[V, P]) Peek() (V, P, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Peek()
+}
+
+// PeekValue returns the value at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncBinomialHeap[V, P]) PeekValue() (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns the priority at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncBinomialHeap[V, P]) PeekPriority() (P, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty.
+func (s *SyncBinomialHeap[V, P]) Pop() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncBinomialHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single write
+// lock. pred must not call back into the heap.
+func (s *SyncBinomialHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return popWhile(s.heap.Peek, s.heap.Pop, pred)
+}
+
+// PopValue removes and returns just the value at the root.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncBinomialHeap[V, P]) PopValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns just the priority at the root.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncBinomialHeap[V, P]) PopPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPriority()
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncBinomialHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncBinomialHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncBinomialHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncBinomialHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, in the same format as the underlying heap. It holds a read lock
+// until the whole array has been written.
+func (s *SyncBinomialHeap[V, P]) StreamJSON(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.StreamJSON(w)
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format while holding a read lock.
+func (s *SyncBinomialHeap[V, P]) WriteDOT(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.WriteDOT(w)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (s *SyncBinomialHeap[V, P]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (s *SyncBinomialHeap[V, P]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (s *SyncBinomialHeap[V, P]) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (s *SyncBinomialHeap[V, P]) Restore(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Restore(data)
+}
+
+// Push adds a new element to the heap by creating a single-node binomial tree
+// and unioning it with the existing root list.
+func (s *SyncBinomialHeap[V, P]) Push(value V, priority P) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Push(value, priority)
+}
+
+// Meld merges another thread-safe binomial heap into this one. The other heap
+// is consumed by the operation and left empty. Locks are acquired in a
+// consistent order so that two heaps melded into each other concurrently
+// cannot deadlock.
+func (s *SyncBinomialHeap[V, P]) Meld(other *SyncBinomialHeap[V, P]) {
+	if other == nil || other == s {
+		return
+	}
+
+	defer lockPair(&s.mu, &other.mu)()
+	s.heap.Meld(other.heap)
+}
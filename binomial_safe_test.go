@@ -0,0 +1,59 @@
+package heapcraft
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncBinomialHeap_BasicOperations(t *testing.T) {
+	heap := NewSyncBinomialHeap[int](nil, lt, HeapConfig{})
+
+	assert.True(t, heap.IsEmpty())
+	heap.Push(10, 1)
+	heap.Push(20, 2)
+	heap.Push(5, 0)
+	assert.Equal(t, 3, heap.Length())
+
+	value, err := heap.PeekValue()
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+
+	clone := heap.Clone()
+	value, err = heap.PopValue()
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+	assert.Equal(t, 3, clone.Length())
+
+	heap.Clear()
+	assert.True(t, heap.IsEmpty())
+}
+
+func TestSyncBinomialHeap_ConcurrentMeld(t *testing.T) {
+	h1 := NewSyncBinomialHeap[int](nil, lt, HeapConfig{})
+	h2 := NewSyncBinomialHeap[int](nil, lt, HeapConfig{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(val int) {
+			defer wg.Done()
+			h1.Push(val, val)
+		}(i)
+		go func(val int) {
+			defer wg.Done()
+			h2.Push(val, val)
+		}(i)
+	}
+	wg.Wait()
+
+	h1.Meld(h2)
+	assert.Equal(t, 100, h1.Length())
+	assert.True(t, h2.IsEmpty())
+
+	priority, err := h1.PeekPriority()
+	require.NoError(t, err)
+	assert.Equal(t, 0, priority)
+}
@@ -0,0 +1,155 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectBinomial(h *BinomialHeap[int, int]) []int {
+	result := make([]int, 0)
+	for !h.IsEmpty() {
+		val, _ := h.PopValue()
+		result = append(result, val)
+	}
+	return result
+}
+
+func TestBinomialHeap_PopOrder(t *testing.T) {
+	data := []HeapNode[int, int]{
+		CreateHeapNode(9, 9),
+		CreateHeapNode(4, 4),
+		CreateHeapNode(6, 6),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(7, 7),
+		CreateHeapNode(3, 3),
+	}
+	h := NewBinomialHeap(data, lt, HeapConfig{})
+	assert.False(t, h.IsEmpty())
+	assert.Equal(t, len(data), h.Length())
+
+	expected := []int{1, 3, 4, 6, 7, 9}
+	actual := collectBinomial(h)
+	assert.Equal(t, expected, actual)
+	assert.True(t, h.IsEmpty())
+
+	_, _, err := h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestBinomialHeap_InsertPopPeekLenIsEmpty(t *testing.T) {
+	h := NewBinomialHeap([]HeapNode[int, int]{}, gt, HeapConfig{})
+	assert.True(t, h.IsEmpty())
+	_, _, err := h.Peek()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	for _, v := range []int{5, 2, 8, 3, 6} {
+		h.Push(v, v)
+	}
+	assert.Equal(t, 5, h.Length())
+
+	value, priority, err := h.Peek()
+	assert.Nil(t, err)
+	assert.Equal(t, 8, value)
+	assert.Equal(t, 8, priority)
+
+	pri, _ := h.PeekPriority()
+	assert.Equal(t, 8, pri)
+	pri, _ = h.PopPriority()
+	assert.Equal(t, 8, pri)
+	val, _ := h.PeekValue()
+	assert.Equal(t, 6, val)
+	assert.Equal(t, []int{6, 5, 3, 2}, collectBinomial(h))
+}
+
+func TestBinomialHeap_RandomOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	h := NewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	expected := make([]int, 0, 500)
+	for i := 0; i < 500; i++ {
+		n := r.Intn(100)
+		h.Push(n, n)
+		expected = append(expected, n)
+	}
+	sort.Ints(expected)
+	assert.Equal(t, expected, collectBinomial(h))
+}
+
+func TestBinomialHeap_Clone(t *testing.T) {
+	h := NewBinomialHeap([]HeapNode[int, int]{
+		CreateHeapNode(4, 4),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(3, 3),
+		CreateHeapNode(2, 2),
+	}, lt, HeapConfig{})
+
+	clone := h.Clone()
+	assert.Equal(t, h.Length(), clone.Length())
+
+	h.Push(0, 0)
+	value, _ := h.PeekValue()
+	assert.Equal(t, 0, value)
+	cloneValue, _ := clone.PeekValue()
+	assert.Equal(t, 1, cloneValue)
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	assert.Equal(t, []int{1, 2, 3, 4}, collectBinomial(clone))
+}
+
+func TestBinomialHeap_Meld(t *testing.T) {
+	h1 := NewBinomialHeap([]HeapNode[int, int]{
+		CreateHeapNode(5, 5),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(9, 9),
+	}, lt, HeapConfig{})
+	h2 := NewBinomialHeap([]HeapNode[int, int]{
+		CreateHeapNode(4, 4),
+		CreateHeapNode(8, 8),
+		CreateHeapNode(0, 0),
+		CreateHeapNode(2, 2),
+	}, lt, HeapConfig{})
+
+	h1.Meld(h2)
+	assert.Equal(t, 7, h1.Length())
+	assert.True(t, h2.IsEmpty())
+	assert.Equal(t, []int{0, 1, 2, 4, 5, 8, 9}, collectBinomial(h1))
+
+	// Melding with an empty heap or itself is a no-op.
+	h1.Push(3, 3)
+	h1.Meld(h2)
+	h1.Meld(h1)
+	assert.Equal(t, 1, h1.Length())
+}
+
+// -------------------------------- Binomial Heap Benchmarks --------------------------------
+
+func BenchmarkBinomialHeap_Insertion(b *testing.B) {
+	data := make([]HeapNode[int, int], 0)
+	heap := NewBinomialHeap(data, lt, HeapConfig{})
+
+	insertions := generateRandomNumbersv1(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(insertions[i], insertions[i])
+	}
+}
+
+func BenchmarkBinomialHeap_Deletion(b *testing.B) {
+	data := make([]HeapNode[int, int], 0)
+	heap := NewBinomialHeap(data, lt, HeapConfig{})
+
+	for i := 0; i < b.N; i++ {
+		heap.Push(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Pop()
+	}
+}
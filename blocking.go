@@ -0,0 +1,107 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+)
+
+// BlockingHeap wraps a heap that is safe for concurrent use, such as
+// SyncDaryHeap or SyncPairingHeap, and adds PopWait, which blocks until an
+// element is available instead of failing with ErrHeapEmpty. It lets a pool
+// of workers consume a heap without busy-polling.
+//
+// Every other heap method is promoted from the wrapped heap. Elements must be
+// pushed through the BlockingHeap, not the wrapped heap directly, or waiting
+// consumers are not woken.
+type BlockingHeap[V any, P any] struct {
+	Heap[V, P]
+
+	// mu guards ready and waiting, and orders a push after the emptiness
+	// check of any consumer that is about to wait, so wakeups are never lost.
+	mu sync.Mutex
+	// ready is the condition consumers wait on. sync.Cond cannot be combined
+	// with a context, so the condition is a channel that is closed to
+	// broadcast a push and then replaced.
+	ready   chan struct{}
+	waiting int
+}
+
+// Push adds an element to the wrapped heap and wakes any consumers blocked
+// in PopWait.
+func (b *BlockingHeap[V, P]) Push(value V, priority P) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Heap.Push(value, priority)
+	if b.waiting > 0 {
+		close(b.ready)
+		b.ready = make(chan struct{})
+	}
+}
+
+// PopWait removes and returns the value and priority of the root element,
+// blocking until the heap is non-empty. Returns zero values and the context's
+// error if ctx is done before an element becomes available.
+func (b *BlockingHeap[V, P]) PopWait(ctx context.Context) (V, P, error) {
+	for {
+		b.mu.Lock()
+		value, priority, err := b.Heap.Pop()
+		if err == nil {
+			b.mu.Unlock()
+			return value, priority, nil
+		}
+		ready := b.ready
+		b.waiting++
+		b.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+		}
+
+		b.mu.Lock()
+		b.waiting--
+		b.mu.Unlock()
+
+		if err := ctx.Err(); err != nil {
+			v, p := zeroValuePair[V, P]()
+			return v, p, err
+		}
+	}
+}
+
+// PopValueWait removes and returns just the value at the root, blocking until
+// the heap is non-empty. Returns zero value and the context's error if ctx is
+// done before an element becomes available.
+func (b *BlockingHeap[V, P]) PopValueWait(ctx context.Context) (V, error) {
+	return valueFromNode(b.PopWait(ctx))
+}
+
+// PopPriorityWait removes and returns just the priority at the root, blocking
+// until the heap is non-empty. Returns zero value and the context's error if
+// ctx is done before an element becomes available.
+func (b *BlockingHeap[V, P]) PopPriorityWait(ctx context.Context) (P, error) {
+	return priorityFromNode(b.PopWait(ctx))
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// it. If the wrapped heap has its own PopIf, as every thread-safe heap in the
+// package does, the check and the removal are atomic. Otherwise the root is
+// peeked and popped in two steps. If pred reports false the root stays in the
+// heap and is returned with false.
+func (b *BlockingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	if heap, ok := b.Heap.(interface {
+		PopIf(pred func(V, P) bool) (V, P, bool, error)
+	}); ok {
+		return heap.PopIf(pred)
+	}
+	return popIf(b.Heap.Peek, b.Heap.Pop, pred)
+}
+
+// Verify checks the internal consistency of the wrapped heap if it implements
+// Verifier, and returns nil otherwise. It is intended for tests and debugging.
+func (b *BlockingHeap[V, P]) Verify() error {
+	if verifier, ok := b.Heap.(Verifier); ok {
+		return verifier.Verify()
+	}
+	return nil
+}
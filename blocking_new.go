@@ -0,0 +1,9 @@
+package heapcraft
+
+// NewBlockingHeap wraps the given heap in a BlockingHeap. The heap must be safe
+// for concurrent use, such as one created by NewSyncDaryHeap,
+// NewSyncPairingHeap, NewSyncLeftistHeap, NewSyncSkewHeap or
+// NewSyncBinomialHeap.
+func NewBlockingHeap[V any, P any](heap Heap[V, P]) *BlockingHeap[V, P] {
+	return &BlockingHeap[V, P]{Heap: heap, ready: make(chan struct{})}
+}
@@ -0,0 +1,109 @@
+package heapcraft
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockingHeap_PopWaitAvailable(t *testing.T) {
+	heap := NewBlockingHeap(NewSyncDaryHeap[string, int](2, nil, lt, false))
+	heap.Push("b", 2)
+	heap.Push("a", 1)
+
+	value, priority, err := heap.PopWait(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "a", value)
+	assert.Equal(t, 1, priority)
+
+	value, err = heap.PopValueWait(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "b", value)
+	assert.True(t, heap.IsEmpty())
+}
+
+func TestBlockingHeap_PopWaitBlocksUntilPush(t *testing.T) {
+	heap := NewBlockingHeap(NewSyncPairingHeap[string, int](nil, lt, false))
+	result := make(chan int)
+	go func() {
+		priority, err := heap.PopPriorityWait(context.Background())
+		assert.Nil(t, err)
+		result <- priority
+	}()
+
+	select {
+	case <-result:
+		t.Fatal("PopWait returned before an element was pushed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	heap.Push("a", 7)
+	select {
+	case priority := <-result:
+		assert.Equal(t, 7, priority)
+	case <-time.After(time.Second):
+		t.Fatal("PopWait was not woken by Push")
+	}
+}
+
+func TestBlockingHeap_PopWaitContext(t *testing.T) {
+	heap := NewBlockingHeap(NewSyncSkewHeap[string, int](nil, lt, false))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	value, priority, err := heap.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "", value)
+	assert.Equal(t, 0, priority)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = heap.PopValueWait(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, heap.waiting)
+}
+
+func TestBlockingHeap_WorkerPool(t *testing.T) {
+	heap := NewBlockingHeap(NewSyncBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	received := make([]int, 0, 1000)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				value, err := heap.PopValueWait(ctx)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				received = append(received, value)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		heap.Push(i, i)
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 1000
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	sort.Ints(received)
+	for i, value := range received {
+		assert.Equal(t, i, value)
+	}
+}
@@ -0,0 +1,182 @@
+package heapcraft
+
+// ShedPolicy selects which element a BoundedHeap sheds when a push would take
+// it past its capacity.
+type ShedPolicy int
+
+const (
+	// ShedRejectNew keeps the heap as it is and sheds the element being
+	// pushed.
+	ShedRejectNew ShedPolicy = iota
+	// ShedDropWorst sheds whichever of the pushed element and the worst
+	// element in the heap comes last according to the comparison function,
+	// so the heap keeps the best elements it has seen.
+	ShedDropWorst
+	// ShedDropOldest sheds the element that has been in the heap the longest
+	// to make room for the pushed one.
+	ShedDropOldest
+)
+
+// BoundedHeap is a heap with a soft capacity. Once it holds capacity elements,
+// every further Push sheds one element according to its ShedPolicy and hands
+// it to the handlers registered with OnShed, so the heap never grows past its
+// capacity. It protects queues fed by untrusted producers from unbounded
+// memory growth. Elements of equal priority pop in insertion order. The heap
+// is not safe for concurrent use; use SyncBoundedHeap for that.
+type BoundedHeap[V any, P any] struct {
+	heap     *DaryHeap[V, P]
+	capacity int
+	policy   ShedPolicy
+	onShed   listeners[HeapNode[V, P]]
+}
+
+// OnShed registers fn to be called with every element the heap sheds, and
+// returns an ID that can be passed to RemoveListener.
+func (b *BoundedHeap[V, P]) OnShed(fn func(node HeapNode[V, P])) string {
+	return b.onShed.register(fn)
+}
+
+// RemoveListener removes the shed handler with the specified ID. Returns an
+// error if no handler exists with the given ID.
+func (b *BoundedHeap[V, P]) RemoveListener(id string) error {
+	return b.onShed.deregister(id)
+}
+
+// Capacity returns the number of elements the heap holds before it starts
+// shedding.
+func (b *BoundedHeap[V, P]) Capacity() int { return b.capacity }
+
+// Policy returns the policy used to choose which element is shed.
+func (b *BoundedHeap[V, P]) Policy() ShedPolicy { return b.policy }
+
+// shed notifies the shed handlers about the given element.
+func (b *BoundedHeap[V, P]) shed(value V, priority P) {
+	b.onShed.emit(HeapNode[V, P]{value: value, priority: priority})
+}
+
+// worst returns the index of the element that would be popped last. It is
+// always a leaf, so only the leaves are scanned.
+func (b *BoundedHeap[V, P]) worst() int {
+	h := b.heap
+	n := h.Length()
+	idx := n - 1
+	for i := (n-2)/h.d + 1; i < n; i++ {
+		if h.before(h.data[idx], h.data[i]) {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// oldest returns the index of the element that was inserted first.
+func (b *BoundedHeap[V, P]) oldest() int {
+	data := b.heap.data
+	idx := 0
+	for i := 1; i < len(data); i++ {
+		if data[i].seq < data[idx].seq {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// Push inserts an element into the heap. If the heap is already at capacity,
+// one element is shed according to the heap's policy first: the pushed
+// element itself under ShedRejectNew, or under ShedDropWorst when it would be
+// the worst element, otherwise the worst or oldest element in the heap.
+// Pushing into a heap with a capacity of zero or less sheds every element.
+func (b *BoundedHeap[V, P]) Push(value V, priority P) {
+	if b.heap.Length() < b.capacity {
+		b.heap.Push(value, priority)
+		return
+	}
+	if b.capacity <= 0 || b.policy == ShedRejectNew {
+		b.shed(value, priority)
+		return
+	}
+
+	var idx int
+	if b.policy == ShedDropWorst {
+		idx = b.worst()
+		if !b.heap.cmp(priority, b.heap.data[idx].priority) {
+			b.shed(value, priority)
+			return
+		}
+	} else {
+		idx = b.oldest()
+	}
+	removed := b.heap.removeAt(idx)
+	b.heap.Push(value, priority)
+	b.shed(removed.value, removed.priority)
+}
+
+// Clear removes all elements from the heap without notifying the shed
+// handlers.
+func (b *BoundedHeap[V, P]) Clear() { b.heap.Clear() }
+
+// Length returns the number of elements in the heap.
+func (b *BoundedHeap[V, P]) Length() int { return b.heap.Length() }
+
+// IsEmpty returns true if the heap contains no elements.
+func (b *BoundedHeap[V, P]) IsEmpty() bool { return b.heap.IsEmpty() }
+
+// Verify checks the internal consistency of the heap: the underlying d-ary
+// heap must pass its own Verify and hold no more than Capacity elements. It is
+// intended for tests and debugging and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (b *BoundedHeap[V, P]) Verify() error {
+	if n := b.heap.Length(); n > max(b.capacity, 0) {
+		return invariantError("%d elements exceed the capacity of %d", n, b.capacity)
+	}
+	return b.heap.Verify()
+}
+
+// Peek returns the value and priority of the root element without removing
+// it. Returns zero values and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) Peek() (V, P, error) { return b.heap.Peek() }
+
+// PeekValue returns the value of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) PeekValue() (V, error) { return b.heap.PeekValue() }
+
+// PeekPriority returns the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) PeekPriority() (P, error) { return b.heap.PeekPriority() }
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) Pop() (V, P, error) { return b.heap.Pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(b.Peek, b.Pop, pred)
+}
+
+// PopValue removes and returns the value of the root element. Returns zero
+// value and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) PopValue() (V, error) { return b.heap.PopValue() }
+
+// PopPriority removes and returns the priority of the root element. Returns
+// zero value and an error if the heap is empty.
+func (b *BoundedHeap[V, P]) PopPriority() (P, error) { return b.heap.PopPriority() }
+
+// Drain removes all elements from the heap and returns them in priority
+// order. The heap is empty afterwards.
+func (b *BoundedHeap[V, P]) Drain() []HeapNode[V, P] { return b.heap.Drain() }
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (b *BoundedHeap[V, P]) DrainValues() []V { return b.heap.DrainValues() }
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (b *BoundedHeap[V, P]) DrainPriorities() []P { return b.heap.DrainPriorities() }
+
+// Export returns a copy of the elements in the heap according to opts. The
+// heap itself is not modified.
+func (b *BoundedHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return b.heap.Export(opts)
+}
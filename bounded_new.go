@@ -0,0 +1,21 @@
+package heapcraft
+
+// NewBoundedHeap creates an empty BoundedHeap that sheds elements according
+// to policy once it holds capacity elements. The comparison function
+// determines the heap order (min or max), and config sets the options of the
+// stable d-ary heap the elements are kept in.
+func NewBoundedHeap[V any, P any](capacity int, policy ShedPolicy, cmp func(a, b P) bool, config DaryHeapConfig) *BoundedHeap[V, P] {
+	return &BoundedHeap[V, P]{
+		heap:     NewStableDaryHeap[V, P](2, nil, cmp, config),
+		capacity: capacity,
+		policy:   policy,
+		onShed:   make(listeners[HeapNode[V, P]]),
+	}
+}
+
+// NewSyncBoundedHeap creates an empty thread-safe BoundedHeap that sheds
+// elements according to policy once it holds capacity elements. The
+// comparison function determines the heap order (min or max).
+func NewSyncBoundedHeap[V any, P any](capacity int, policy ShedPolicy, cmp func(a, b P) bool, config DaryHeapConfig) *SyncBoundedHeap[V, P] {
+	return &SyncBoundedHeap[V, P]{heap: NewBoundedHeap[V, P](capacity, policy, cmp, config)}
+}
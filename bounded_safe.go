@@ -0,0 +1,157 @@
+package heapcraft
+
+import "sync"
+
+// SyncBoundedHeap is a thread-safe wrapper around BoundedHeap. Push takes an
+// exclusive lock, so the capacity check and any shedding happen atomically.
+type SyncBoundedHeap[V any, P any] struct {
+	heap *BoundedHeap[V, P]
+	lock sync.RWMutex
+}
+
+// OnShed registers fn to be called with every element the heap sheds, and
+// returns an ID that can be passed to RemoveListener. fn runs while the heap
+// is locked and must not call back into it.
+func (s *SyncBoundedHeap[V, P]) OnShed(fn func(node HeapNode[V, P])) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.OnShed(fn)
+}
+
+// RemoveListener removes the shed handler with the specified ID. Returns an
+// error if no handler exists with the given ID.
+func (s *SyncBoundedHeap[V, P]) RemoveListener(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
+// Capacity returns the number of elements the heap holds before it starts
+// shedding.
+func (s *SyncBoundedHeap[V, P]) Capacity() int { return s.heap.Capacity() }
+
+// Policy returns the policy used to choose which element is shed.
+func (s *SyncBoundedHeap[V, P]) Policy() ShedPolicy { return s.heap.Policy() }
+
+// Push inserts an element into the heap, shedding one element according to
+// the heap's policy if it is already at capacity.
+func (s *SyncBoundedHeap[V, P]) Push(value V, priority P) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.Push(value, priority)
+}
+
+// Clear removes all elements from the heap without notifying the shed
+// handlers.
+func (s *SyncBoundedHeap[V, P]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.Clear()
+}
+
+// Length returns the number of elements in the heap.
+func (s *SyncBoundedHeap[V, P]) Length() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SyncBoundedHeap[V, P]) IsEmpty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncBoundedHeap[V, P]) Verify() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Verify()
+}
+
+// Peek returns the value and priority of the root element without removing
+// it.
+func (s *SyncBoundedHeap[V, P]) Peek() (V, P, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Peek()
+}
+
+// PeekValue returns the value of the root element without removing it.
+func (s *SyncBoundedHeap[V, P]) PeekValue() (V, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns the priority of the root element without removing it.
+func (s *SyncBoundedHeap[V, P]) PeekPriority() (P, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and priority of the root element.
+func (s *SyncBoundedHeap[V, P]) Pop() (V, P, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncBoundedHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopValue removes and returns the value of the root element.
+func (s *SyncBoundedHeap[V, P]) PopValue() (V, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns the priority of the root element.
+func (s *SyncBoundedHeap[V, P]) PopPriority() (P, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopPriority()
+}
+
+// Drain removes all elements from the heap and returns them in priority
+// order.
+func (s *SyncBoundedHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order.
+func (s *SyncBoundedHeap[V, P]) DrainValues() []V {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order.
+func (s *SyncBoundedHeap[V, P]) DrainPriorities() []P {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap according to opts.
+func (s *SyncBoundedHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Export(opts)
+}
@@ -0,0 +1,81 @@
+package heapcraft
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fillBounded creates a bounded min-heap with the given policy, records shed
+// values, and pushes priorities in order using their index as the value.
+func fillBounded(policy ShedPolicy, capacity int, priorities ...int) (*BoundedHeap[int, int], *[]int) {
+	heap := NewBoundedHeap[int, int](capacity, policy, lt, DaryHeapConfig{})
+	shed := &[]int{}
+	heap.OnShed(func(node HeapNode[int, int]) { *shed = append(*shed, node.Value()) })
+	for i, p := range priorities {
+		heap.Push(i, p)
+	}
+	return heap, shed
+}
+
+func TestBoundedHeap_RejectNew(t *testing.T) {
+	heap, shed := fillBounded(ShedRejectNew, 3, 5, 1, 3, 0, 9)
+
+	assert.Equal(t, 3, heap.Length())
+	assert.Equal(t, []int{3, 4}, *shed)
+	assert.Equal(t, []int{1, 2, 0}, heap.DrainValues())
+}
+
+func TestBoundedHeap_DropWorst(t *testing.T) {
+	heap, shed := fillBounded(ShedDropWorst, 3, 5, 1, 3, 0, 9, 3)
+
+	assert.Equal(t, 3, heap.Length())
+	// 0 evicts 5, 9 is itself the worst, and a tie with the worst keeps the
+	// element already in the heap.
+	assert.Equal(t, []int{0, 4, 5}, *shed)
+	assert.Equal(t, []int{0, 1, 3}, heap.DrainPriorities())
+}
+
+func TestBoundedHeap_DropOldest(t *testing.T) {
+	heap, shed := fillBounded(ShedDropOldest, 3, 5, 1, 3, 0, 9)
+
+	assert.Equal(t, 3, heap.Length())
+	assert.Equal(t, []int{0, 1}, *shed)
+	assert.Equal(t, []int{3, 2, 4}, heap.DrainValues())
+
+	// Once the oldest element has been popped, the next oldest is shed.
+	heap.Push(0, 4)
+	heap.Push(1, 2)
+	heap.Push(2, 6)
+	_, _, _ = heap.Pop()
+	heap.Push(3, 8)
+	heap.Push(4, 1)
+	assert.Equal(t, []int{0, 1, 0}, *shed)
+	assert.Equal(t, []int{4, 2, 3}, heap.DrainValues())
+}
+
+func TestBoundedHeap_ZeroCapacity(t *testing.T) {
+	heap, shed := fillBounded(ShedDropOldest, 0, 1, 2)
+
+	assert.True(t, heap.IsEmpty())
+	assert.Equal(t, []int{0, 1}, *shed)
+}
+
+func TestSyncBoundedHeap_ConcurrentPush(t *testing.T) {
+	heap := NewSyncBoundedHeap[int, int](10, ShedDropWorst, lt, DaryHeapConfig{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				heap.Push(i, g*100+i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, heap.Length())
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, heap.DrainPriorities())
+}
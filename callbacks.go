@@ -1,19 +1,17 @@
 package heapcraft
 
-import (
-	"sync"
-
-	"github.com/google/uuid"
-)
+import "sync"
 
 // callbacks is an interface that defines the methods for managing
-// callbacks.
+// callbacks. Every registry owns its entries: clone returns an independent
+// registry of the same concrete type, so a clone of a thread-safe registry is
+// itself thread-safe.
 type callbacks interface {
 	run(x, y int)
 	register(fn func(x, y int)) callback
 	deregister(id string) error
 	count() int
-	getCallbacks() callbacks
+	clone() callbacks
 }
 
 // callbacks maintains a registry of callback functions (ID → function).
@@ -35,7 +33,7 @@ func (c baseCallbacks) run(x, y int) {
 // register adds a callback function to be called on each swap and returns a
 // callback struct containing the function and its unique ID.
 func (c baseCallbacks) register(fn func(x, y int)) callback {
-	newId := uuid.New().String()
+	newId := registrationIDs.Next()
 	callback := callback{ID: newId, Function: fn}
 	c[newId] = callback
 	return callback
@@ -54,8 +52,11 @@ func (c baseCallbacks) deregister(id string) error {
 // count returns the number of registered callbacks.
 func (c baseCallbacks) count() int { return len(c) }
 
-// getCallbacks returns a copy of the callbacks map.
-func (c baseCallbacks) getCallbacks() callbacks {
+// clone returns an independent copy of the registry.
+func (c baseCallbacks) clone() callbacks { return c.copy() }
+
+// copy returns a copy of the callbacks map.
+func (c baseCallbacks) copy() baseCallbacks {
 	callbacksMap := make(baseCallbacks, len(c))
 	for k, v := range c {
 		callbacksMap[k] = v
@@ -108,10 +109,10 @@ func (c *syncCallbacks) count() int {
 	return c.callbacks.count()
 }
 
-// getCallbacks returns a copy of the callbacks map.
-// This is the thread-safe version of getCallbacks.
-func (c *syncCallbacks) getCallbacks() callbacks {
+// clone returns an independent thread-safe copy of the registry.
+// This is the thread-safe version of clone.
+func (c *syncCallbacks) clone() callbacks {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	return c.callbacks.getCallbacks()
+	return &syncCallbacks{callbacks: c.callbacks.copy()}
 }
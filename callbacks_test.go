@@ -226,8 +226,8 @@ func TestSyncCallbacksCount(t *testing.T) {
 	assert.Equal(t, 2, callbacks.count())
 }
 
-// TestBaseCallbacksGetCallbacks tests the getCallbacks method with baseCallbacks.
-func TestBaseCallbacksGetCallbacks(t *testing.T) {
+// TestBaseCallbacksClone tests the clone method with baseCallbacks.
+func TestBaseCallbacksClone(t *testing.T) {
 	callbacks := make(baseCallbacks, 0)
 
 	// Register some callbacks
@@ -236,8 +236,9 @@ func TestBaseCallbacksGetCallbacks(t *testing.T) {
 	callbacks.register(fn)
 
 	// Get a copy of the callbacks
-	copied := callbacks.getCallbacks()
+	copied := callbacks.clone()
 	assert.Equal(t, 2, copied.count())
+	assert.IsType(t, baseCallbacks{}, copied)
 
 	// Verify the copy is independent
 	callbacks.deregister(callback1.ID)
@@ -245,8 +246,8 @@ func TestBaseCallbacksGetCallbacks(t *testing.T) {
 	assert.Equal(t, 2, copied.count()) // Copy should be unchanged
 }
 
-// TestSyncCallbacksGetCallbacks tests the getCallbacks method with syncCallbacks.
-func TestSyncCallbacksGetCallbacks(t *testing.T) {
+// TestSyncCallbacksClone tests the clone method with syncCallbacks.
+func TestSyncCallbacksClone(t *testing.T) {
 	callbacks := NewSyncCallbacks()
 
 	// Register some callbacks
@@ -255,8 +256,9 @@ func TestSyncCallbacksGetCallbacks(t *testing.T) {
 	callbacks.register(fn)
 
 	// Get a copy of the callbacks
-	copied := callbacks.getCallbacks()
+	copied := callbacks.clone()
 	assert.Equal(t, 2, copied.count())
+	assert.IsType(t, &syncCallbacks{}, copied)
 
 	// Verify the copy is independent
 	callbacks.deregister(callback1.ID)
@@ -0,0 +1,146 @@
+package heapcraft
+
+import "math"
+
+// HeapKind names a heap implementation recommended by RecommendHeap.
+type HeapKind int
+
+const (
+	// KindDary is a DaryHeap.
+	KindDary HeapKind = iota
+	// KindPairing is a PairingHeap.
+	KindPairing
+	// KindAdaptive is an AdaptiveHeap.
+	KindAdaptive
+	// KindFullPairing is a FullPairingHeap.
+	KindFullPairing
+	// KindFullSkew is a FullSkewHeap.
+	KindFullSkew
+)
+
+// String returns the name of the heap type.
+func (k HeapKind) String() string {
+	switch k {
+	case KindDary:
+		return "DaryHeap"
+	case KindPairing:
+		return "PairingHeap"
+	case KindAdaptive:
+		return "AdaptiveHeap"
+	case KindFullPairing:
+		return "FullPairingHeap"
+	case KindFullSkew:
+		return "FullSkewHeap"
+	}
+	return "unknown"
+}
+
+// arityCandidates are the arities RecommendArity chooses from.
+var arityCandidates = [...]int{2, 3, 4, 8, 16}
+
+// WorkloadProfile describes how a heap will be used. The operation fields are
+// relative frequencies, so only their ratios matter: Pushes: 3, Pops: 1 and
+// Pushes: 75, Pops: 25 describe the same workload.
+type WorkloadProfile struct {
+	Pushes       float64
+	Pops         float64
+	DecreaseKeys float64
+	Melds        float64
+	// ExpectedSize is the typical number of elements in the heap, or zero if
+	// it is not known.
+	ExpectedSize int
+}
+
+// popDominated reports whether pops clearly outnumber pushes, as when a heap
+// is built once and then drained.
+func (w WorkloadProfile) popDominated() bool { return w.Pops > 1.5*w.Pushes }
+
+// meldShare is the fraction of operations that are melds.
+func (w WorkloadProfile) meldShare() float64 {
+	total := w.Pushes + w.Pops + w.DecreaseKeys + w.Melds
+	if total <= 0 {
+		return 0
+	}
+	return w.Melds / total
+}
+
+// RecommendHeap returns the heap type best suited to profile, following the
+// results of the bench package:
+//
+//   - Workloads with decrease-keys need a tracked heap: FullSkewHeap if pops
+//     dominate, where its cheap deletions win, and FullPairingHeap otherwise.
+//   - Heaps that usually hold no more than a handful of elements are fastest
+//     as an AdaptiveHeap, which keeps them in an inline array.
+//   - Melds in more than one operation in a hundred call for a PairingHeap,
+//     which melds in O(1) where a DaryHeap takes O(n).
+//   - Pop-dominated workloads suit a DaryHeap, whose contiguous array makes
+//     sifting cheap and allocates nothing per element. RecommendArity picks
+//     its arity.
+//   - Anything else, including balanced and push-heavy mixes, is fastest as a
+//     PairingHeap.
+func RecommendHeap(profile WorkloadProfile) HeapKind {
+	switch {
+	case profile.DecreaseKeys > 0 && profile.popDominated():
+		return KindFullSkew
+	case profile.DecreaseKeys > 0:
+		return KindFullPairing
+	case profile.ExpectedSize > 0 && profile.ExpectedSize <= smallHeapThreshold:
+		return KindAdaptive
+	case profile.meldShare() > 0.01:
+		return KindPairing
+	case profile.popDominated():
+		return KindDary
+	}
+	return KindPairing
+}
+
+// RecommendArity returns the arity of DaryHeap that does the fewest
+// comparisons for profile. A push sifts up one comparison per level and a pop
+// sifts down d per level, with log_d(n) levels either way, so the cost of an
+// arity d is proportional to (pushes/pops + d) / ln(d). It is lowest at d=3
+// or d=4 for balanced workloads, which the bench package also measured as the
+// fastest, and moves to 8 or 16 as pushes come to dominate. The arity is
+// never larger than ExpectedSize, since a wider heap would be a flat array.
+// Profiles without pops are treated as balanced.
+func RecommendArity(profile WorkloadProfile) int {
+	ratio := 1.0
+	if profile.Pops > 0 {
+		ratio = profile.Pushes / profile.Pops
+	}
+	best, bestCost := 0, math.Inf(1)
+	for _, d := range arityCandidates {
+		if best != 0 && profile.ExpectedSize > 0 && d > profile.ExpectedSize {
+			break
+		}
+		if cost := (ratio + float64(d)) / math.Log(float64(d)); cost < bestCost {
+			best, bestCost = d, cost
+		}
+	}
+	return best
+}
+
+// ChooseHeap creates an empty heap of the type RecommendHeap returns for
+// profile, ignoring DecreaseKeys since a Heap cannot update priorities; use
+// ChooseTrackedHeap for workloads that do. The result can be type-asserted to
+// the concrete type that RecommendHeap names, for instance to call Meld.
+func ChooseHeap[V any, P any](profile WorkloadProfile, cmp func(a, b P) bool) Heap[V, P] {
+	profile.DecreaseKeys = 0
+	switch RecommendHeap(profile) {
+	case KindDary:
+		return NewAutoDaryHeap[V, P](nil, cmp, profile)
+	case KindAdaptive:
+		return NewAdaptiveHeap[V, P](nil, cmp, HeapConfig{})
+	}
+	return NewSimplePairingHeap[V, P](nil, cmp, HeapConfig{})
+}
+
+// ChooseTrackedHeap creates an empty tracked heap of the type RecommendHeap
+// returns for profile, treating it as a workload with decrease-keys even if
+// DecreaseKeys is zero. config is passed to the heap's constructor.
+func ChooseTrackedHeap[V any, P any](profile WorkloadProfile, cmp func(a, b P) bool, config HeapConfig) TrackedHeap[V, P] {
+	profile.DecreaseKeys = max(profile.DecreaseKeys, 1)
+	if RecommendHeap(profile) == KindFullSkew {
+		return NewFullSkewHeap[V, P](nil, cmp, config)
+	}
+	return NewFullPairingHeap[V, P](nil, cmp, config)
+}
@@ -0,0 +1,63 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendHeap(t *testing.T) {
+	cases := []struct {
+		profile WorkloadProfile
+		want    HeapKind
+	}{
+		{WorkloadProfile{Pushes: 1, Pops: 1}, KindPairing},
+		{WorkloadProfile{Pushes: 3, Pops: 1}, KindPairing},
+		{WorkloadProfile{Pushes: 1, Pops: 4}, KindDary},
+		{WorkloadProfile{Pushes: 1, Pops: 4, Melds: 1}, KindPairing},
+		{WorkloadProfile{Pushes: 1, Pops: 1, ExpectedSize: 5}, KindAdaptive},
+		{WorkloadProfile{Pushes: 1, Pops: 1, ExpectedSize: 5000}, KindPairing},
+		{WorkloadProfile{Pushes: 2, Pops: 2, DecreaseKeys: 1}, KindFullPairing},
+		{WorkloadProfile{Pushes: 1, Pops: 4, DecreaseKeys: 1}, KindFullSkew},
+		{WorkloadProfile{}, KindPairing},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, RecommendHeap(c.profile), "%+v", c.profile)
+	}
+	assert.Equal(t, "FullSkewHeap", KindFullSkew.String())
+}
+
+func TestChooseHeap(t *testing.T) {
+	drain := WorkloadProfile{Pushes: 1, Pops: 3, DecreaseKeys: 5, ExpectedSize: 100}
+	h := ChooseHeap[string, int](drain, lt)
+	assert.IsType(t, &DaryHeap[string, int]{}, h)
+	h.Push("b", 2)
+	h.Push("a", 1)
+	assert.Equal(t, []string{"a", "b"}, h.DrainValues())
+
+	assert.IsType(t, &PairingHeap[string, int]{}, ChooseHeap[string, int](WorkloadProfile{Pushes: 1, Pops: 1, Melds: 1}, lt))
+	assert.IsType(t, &AdaptiveHeap[string, int]{}, ChooseHeap[string, int](WorkloadProfile{ExpectedSize: 3}, lt))
+
+	assert.IsType(t, &FullSkewHeap[string, int]{}, ChooseTrackedHeap[string, int](drain, lt, HeapConfig{}))
+	tracked := ChooseTrackedHeap[string, int](WorkloadProfile{Pushes: 1, Pops: 1}, lt, HeapConfig{})
+	assert.IsType(t, &FullPairingHeap[string, int]{}, tracked)
+	id, err := tracked.Push("x", 9)
+	assert.NoError(t, err)
+	assert.NoError(t, tracked.UpdatePriority(id, 1))
+}
+
+func TestRecommendArity(t *testing.T) {
+	assert.Equal(t, 4, RecommendArity(WorkloadProfile{Pushes: 1, Pops: 1}))
+	assert.Equal(t, 3, RecommendArity(WorkloadProfile{Pushes: 1, Pops: 4}))
+	assert.Equal(t, 8, RecommendArity(WorkloadProfile{Pushes: 10, Pops: 1}))
+	assert.Equal(t, 16, RecommendArity(WorkloadProfile{Pushes: 50, Pops: 1}))
+	assert.Equal(t, 4, RecommendArity(WorkloadProfile{Pushes: 1}))
+	assert.Equal(t, 2, RecommendArity(WorkloadProfile{Pushes: 50, Pops: 1, ExpectedSize: 2}))
+	assert.Equal(t, 8, RecommendArity(WorkloadProfile{Pushes: 50, Pops: 1, ExpectedSize: 10}))
+
+	h := NewAutoDaryHeap([]HeapNode[int, int]{CreateHeapNode(3, 3), CreateHeapNode(1, 1)}, lt,
+		WorkloadProfile{Pushes: 10, Pops: 1, ExpectedSize: 100})
+	assert.Equal(t, 8, h.d)
+	assert.GreaterOrEqual(t, cap(h.data), 100)
+	assert.Equal(t, []int{1, 3}, h.DrainValues())
+}
@@ -0,0 +1,54 @@
+// Command heapbench runs the heapcraft benchmark suite and prints a table of
+// the time per operation, with a row per heap and a column per workload.
+//
+// Usage:
+//
+//	heapbench [-subjects regexp] [-workloads regexp]
+//
+// Only subjects and workloads whose names match the regular expressions are
+// run. Every pair takes about a second.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/galactixx/heapcraft/bench"
+)
+
+func main() {
+	subjectPattern := flag.String("subjects", "", "run only subjects matching this regexp")
+	workloadPattern := flag.String("workloads", "", "run only workloads matching this regexp")
+	flag.Parse()
+
+	subjectRE, err := regexp.Compile(*subjectPattern)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "heapbench:", err)
+		os.Exit(2)
+	}
+	workloadRE, err := regexp.Compile(*workloadPattern)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "heapbench:", err)
+		os.Exit(2)
+	}
+
+	var subjects []bench.Subject
+	for _, s := range bench.Subjects() {
+		if subjectRE.MatchString(s.Name) {
+			subjects = append(subjects, s)
+		}
+	}
+	var workloads []bench.Workload
+	for _, w := range bench.Workloads() {
+		if workloadRE.MatchString(w.Name) {
+			workloads = append(workloads, w)
+		}
+	}
+
+	if err := bench.WriteTable(os.Stdout, bench.Run(subjects, workloads)); err != nil {
+		fmt.Fprintln(os.Stderr, "heapbench:", err)
+		os.Exit(1)
+	}
+}
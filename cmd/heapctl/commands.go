@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/galactixx/heapcraft/heappb"
+	"google.golang.org/protobuf/proto"
+)
+
+// readSnapshot reads and decodes a snapshot file.
+func readSnapshot(path string) (*heappb.HeapSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot heappb.HeapSnapshot
+	if err := proto.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// parseFlags parses the flags of a subcommand and checks that exactly want
+// positional arguments remain.
+func parseFlags(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != want {
+		return nil, fmt.Errorf("%s: expected %d file argument(s), got %d", fs.Name(), want, fs.NArg())
+	}
+	return fs.Args(), nil
+}
+
+// formatBytes renders an encoded value or priority for display. Printable
+// UTF-8 is shown verbatim and anything else as hexadecimal.
+func formatBytes(b []byte) string {
+	if len(b) == 0 {
+		return "<empty>"
+	}
+	if utf8.Valid(b) && bytes.IndexFunc(b, func(r rune) bool { return !unicode.IsPrint(r) }) < 0 {
+		return string(b)
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+// runInspect prints a summary of a snapshot file.
+func runInspect(args []string, w io.Writer) error {
+	files, err := parseFlags(flag.NewFlagSet("inspect", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := readSnapshot(files[0])
+	if err != nil {
+		return err
+	}
+
+	nodes := snapshot.GetNodes()
+	fmt.Fprintf(w, "file:     %s\n", files[0])
+	fmt.Fprintf(w, "nodes:    %d\n", len(nodes))
+	fmt.Fprintf(w, "size:     %d bytes\n", proto.Size(snapshot))
+	if len(nodes) > 0 {
+		fmt.Fprintf(w, "best:     %s\n", formatBytes(nodes[0].GetPriority()))
+		fmt.Fprintf(w, "worst:    %s\n", formatBytes(nodes[len(nodes)-1].GetPriority()))
+	}
+	return nil
+}
+
+// runPrint prints the nodes of a snapshot file, one per line, in the order
+// they were written.
+func runPrint(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("print", flag.ContinueOnError)
+	limit := fs.Int("limit", 0, "maximum number of nodes to print (0 prints all)")
+	files, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := readSnapshot(files[0])
+	if err != nil {
+		return err
+	}
+
+	nodes := snapshot.GetNodes()
+	if *limit > 0 && *limit < len(nodes) {
+		nodes = nodes[:*limit]
+	}
+
+	fmt.Fprintf(w, "%-6s %-20s %s\n", "INDEX", "PRIORITY", "VALUE")
+	for i, node := range nodes {
+		fmt.Fprintf(w, "%-6d %-20s %s\n", i, formatBytes(node.GetPriority()), formatBytes(node.GetValue()))
+	}
+	return nil
+}
+
+// runValidate checks that a snapshot file decodes and that every node has a
+// priority. With -order, priorities are decoded as JSON numbers and must be
+// listed best-first for a min-heap or a max-heap respectively.
+func runValidate(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	order := fs.String("order", "", "expected priority order of JSON-number priorities: min or max")
+	files, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *order != "" && *order != "min" && *order != "max" {
+		return fmt.Errorf("validate: -order must be min or max, got %q", *order)
+	}
+
+	snapshot, err := readSnapshot(files[0])
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	var last float64
+	for i, node := range snapshot.GetNodes() {
+		if len(node.GetPriority()) == 0 {
+			problems = append(problems, fmt.Sprintf("node %d: missing priority", i))
+			continue
+		}
+		if *order == "" {
+			continue
+		}
+
+		var priority float64
+		if err := json.Unmarshal(node.GetPriority(), &priority); err != nil {
+			problems = append(problems, fmt.Sprintf("node %d: priority is not a JSON number", i))
+			continue
+		}
+		if i > 0 && ((*order == "min" && priority < last) || (*order == "max" && priority > last)) {
+			problems = append(problems, fmt.Sprintf("node %d: priority %v out of %s-heap order", i, priority, *order))
+		}
+		last = priority
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(w, problem)
+		}
+		return errDiffers
+	}
+	fmt.Fprintf(w, "%s: ok (%d nodes)\n", files[0], len(snapshot.GetNodes()))
+	return nil
+}
+
+// runDiff compares the nodes of two snapshot files as multisets of
+// (priority, value) pairs. Nodes only present in the first file are prefixed
+// with "-" and nodes only present in the second with "+".
+func runDiff(args []string, w io.Writer) error {
+	files, err := parseFlags(flag.NewFlagSet("diff", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+
+	a, errA := readSnapshot(files[0])
+	b, errB := readSnapshot(files[1])
+	if err := errors.Join(errA, errB); err != nil {
+		return err
+	}
+
+	type key struct{ priority, value string }
+	counts := make(map[key]int)
+	for _, node := range a.GetNodes() {
+		counts[key{string(node.GetPriority()), string(node.GetValue())}]++
+	}
+	for _, node := range b.GetNodes() {
+		counts[key{string(node.GetPriority()), string(node.GetValue())}]--
+	}
+
+	differs := false
+	report := func(prefix string, nodes []*heappb.HeapNode, keep func(int) bool) {
+		for _, node := range nodes {
+			k := key{string(node.GetPriority()), string(node.GetValue())}
+			if keep(counts[k]) {
+				differs = true
+				fmt.Fprintf(w, "%s %s %s\n", prefix, formatBytes(node.GetPriority()), formatBytes(node.GetValue()))
+				if prefix == "-" {
+					counts[k]--
+				} else {
+					counts[k]++
+				}
+			}
+		}
+	}
+	report("-", a.GetNodes(), func(n int) bool { return n > 0 })
+	report("+", b.GetNodes(), func(n int) bool { return n < 0 })
+
+	if differs {
+		return errDiffers
+	}
+	fmt.Fprintln(w, "snapshots are identical")
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/galactixx/heapcraft"
+	"github.com/galactixx/heapcraft/heappb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// writeSnapshot encodes the nodes as a snapshot file in a temporary directory
+// and returns its path.
+func writeSnapshot(t *testing.T, name string, nodes []heapcraft.HeapNode[string, int]) string {
+	snapshot, err := heappb.FromHeapNodes(nodes, heappb.JSONCodec[string](), heappb.JSONCodec[int]())
+	require.Nil(t, err)
+	data, err := proto.Marshal(snapshot)
+	require.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func runOutput(args ...string) (string, error) {
+	var out bytes.Buffer
+	err := run(args, &out)
+	return out.String(), err
+}
+
+var sample = []heapcraft.HeapNode[string, int]{
+	heapcraft.CreateHeapNode("a", 1),
+	heapcraft.CreateHeapNode("b", 2),
+	heapcraft.CreateHeapNode("c", 3),
+}
+
+func TestInspect(t *testing.T) {
+	path := writeSnapshot(t, "a.pb", sample)
+	out, err := runOutput("inspect", path)
+	require.Nil(t, err)
+	assert.Contains(t, out, "nodes:    3")
+	assert.Contains(t, out, "best:     1")
+	assert.Contains(t, out, "worst:    3")
+}
+
+func TestPrint(t *testing.T) {
+	path := writeSnapshot(t, "a.pb", sample)
+	out, err := runOutput("print", "-limit", "2", path)
+	require.Nil(t, err)
+	assert.Contains(t, out, `"a"`)
+	assert.Contains(t, out, `"b"`)
+	assert.NotContains(t, out, `"c"`)
+}
+
+func TestValidate(t *testing.T) {
+	path := writeSnapshot(t, "a.pb", sample)
+	out, err := runOutput("validate", "-order", "min", path)
+	require.Nil(t, err)
+	assert.Contains(t, out, "ok (3 nodes)")
+
+	out, err = runOutput("validate", "-order", "max", path)
+	assert.ErrorIs(t, err, errDiffers)
+	assert.Contains(t, out, "node 1: priority 2 out of max-heap order")
+
+	_, err = runOutput("validate", "-order", "sideways", path)
+	assert.NotNil(t, err)
+
+	corrupt := filepath.Join(t.TempDir(), "corrupt.pb")
+	require.Nil(t, os.WriteFile(corrupt, []byte{0xff, 0xff}, 0o644))
+	_, err = runOutput("validate", corrupt)
+	assert.NotNil(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	a := writeSnapshot(t, "a.pb", sample)
+	b := writeSnapshot(t, "b.pb", []heapcraft.HeapNode[string, int]{
+		heapcraft.CreateHeapNode("a", 1),
+		heapcraft.CreateHeapNode("c", 3),
+		heapcraft.CreateHeapNode("d", 4),
+	})
+
+	out, err := runOutput("diff", a, a)
+	require.Nil(t, err)
+	assert.Contains(t, out, "identical")
+
+	out, err = runOutput("diff", a, b)
+	assert.ErrorIs(t, err, errDiffers)
+	assert.Equal(t, "- 2 \"b\"\n+ 4 \"d\"\n", out)
+}
+
+func TestRunErrors(t *testing.T) {
+	_, err := runOutput()
+	assert.NotNil(t, err)
+	_, err = runOutput("bogus")
+	assert.NotNil(t, err)
+	_, err = runOutput("inspect")
+	assert.NotNil(t, err)
+	_, err = runOutput("inspect", filepath.Join(t.TempDir(), "missing.pb"))
+	assert.NotNil(t, err)
+	assert.Equal(t, "0x00ff", formatBytes([]byte{0x00, 0xff}))
+	assert.Equal(t, "<empty>", formatBytes(nil))
+}
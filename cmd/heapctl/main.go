@@ -0,0 +1,66 @@
+// Command heapctl inspects, validates, diffs and pretty-prints heap snapshot
+// files written in the heappb.HeapSnapshot protocol buffer format.
+//
+// Usage:
+//
+//	heapctl inspect <file>
+//	heapctl print [-limit n] <file>
+//	heapctl validate [-order min|max] <file>
+//	heapctl diff <file-a> <file-b>
+//
+// Values and priorities are stored as bytes in a snapshot. They are shown as
+// text when they hold printable UTF-8 (such as the output of a JSON codec) and
+// as hexadecimal otherwise.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// errDiffers is returned by the diff and validate commands when the files
+// differ or fail validation, so that main can exit with status 1 without
+// printing a second error message.
+var errDiffers = errors.New("snapshots differ")
+
+const usage = `usage:
+  heapctl inspect <file>
+  heapctl print [-limit n] <file>
+  heapctl validate [-order min|max] <file>
+  heapctl diff <file-a> <file-b>
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, errDiffers) {
+			fmt.Fprintln(os.Stderr, "heapctl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run dispatches to the subcommand named by the first argument and writes
+// its output to w.
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing command\n" + usage)
+	}
+
+	switch args[0] {
+	case "inspect":
+		return runInspect(args[1:], w)
+	case "print":
+		return runPrint(args[1:], w)
+	case "validate":
+		return runValidate(args[1:], w)
+	case "diff":
+		return runDiff(args[1:], w)
+	case "help", "-h", "--help":
+		_, err := io.WriteString(w, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
@@ -0,0 +1,81 @@
+package heapcraft
+
+import "cmp"
+
+// Comparator orders priorities of type P. It is an alternative to the bare
+// comparison functions taken by heap constructors, for orderings that carry
+// state or are built from several fields; FromComparator turns one into such
+// a function. Less reports whether a belongs closer to the root than b.
+type Comparator[P any] interface {
+	Less(a, b P) bool
+}
+
+// TieBreaker is optionally implemented by a Comparator to order priorities
+// that its Less considers equal, where neither Less(a, b) nor Less(b, a)
+// holds. Tie reports whether a belongs closer to the root than b.
+type TieBreaker[P any] interface {
+	Tie(a, b P) bool
+}
+
+// LessFunc adapts a comparison function to a Comparator.
+type LessFunc[P any] func(a, b P) bool
+
+// Less reports whether a belongs closer to the root than b.
+func (f LessFunc[P]) Less(a, b P) bool { return f(a, b) }
+
+// Reverse returns the opposite ordering, turning a min-heap ordering into a
+// max-heap one and vice versa.
+func (f LessFunc[P]) Reverse() LessFunc[P] { return Reverse(f) }
+
+// Reverse returns a comparison function that orders priorities in the
+// opposite direction to less, so that a max-heap can be built from a
+// min-heap's comparison function and vice versa.
+func Reverse[P any](less func(a, b P) bool) func(a, b P) bool {
+	return func(a, b P) bool { return less(b, a) }
+}
+
+// ByKey returns a Comparator that orders priorities by an ordered key derived
+// from them, smallest key first, such as a deadline field of a job.
+func ByKey[P any, K cmp.Ordered](key func(P) K) LessFunc[P] {
+	return func(a, b P) bool { return key(a) < key(b) }
+}
+
+// Lexicographic returns a Comparator that orders priorities by the first of
+// comparators that tells them apart, so that later comparators only break
+// ties left by earlier ones: deadline, then submitter, then sequence. Each
+// comparator's TieBreaker, if it has one, is ignored, since the next
+// comparator takes its place.
+func Lexicographic[P any](comparators ...Comparator[P]) LessFunc[P] {
+	return func(a, b P) bool {
+		for _, c := range comparators {
+			if c.Less(a, b) {
+				return true
+			}
+			if c.Less(b, a) {
+				return false
+			}
+		}
+		return false
+	}
+}
+
+// FromComparator returns the comparison function for c that heap
+// constructors take. If c implements TieBreaker, priorities that Less
+// considers equal are ordered by Tie; otherwise they are left unordered, and
+// a stable heap such as one made by NewStableDaryHeap pops them in insertion
+// order.
+func FromComparator[P any](c Comparator[P]) func(a, b P) bool {
+	tie, ok := c.(TieBreaker[P])
+	if !ok {
+		return c.Less
+	}
+	return func(a, b P) bool {
+		if c.Less(a, b) {
+			return true
+		}
+		if c.Less(b, a) {
+			return false
+		}
+		return tie.Tie(a, b)
+	}
+}
@@ -0,0 +1,71 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jobPriority struct {
+	deadline  int
+	submitter string
+	seq       int
+}
+
+// deadlineOrder orders jobs by deadline and breaks ties by submitter.
+type deadlineOrder struct{}
+
+func (deadlineOrder) Less(a, b jobPriority) bool { return a.deadline < b.deadline }
+func (deadlineOrder) Tie(a, b jobPriority) bool  { return a.submitter < b.submitter }
+
+func popSeqs(heap *DaryHeap[string, jobPriority]) []int {
+	var seqs []int
+	for _, p := range heap.DrainPriorities() {
+		seqs = append(seqs, p.seq)
+	}
+	return seqs
+}
+
+var comparatorJobs = []jobPriority{
+	{deadline: 2, submitter: "bob", seq: 0},
+	{deadline: 1, submitter: "carol", seq: 1},
+	{deadline: 2, submitter: "alice", seq: 2},
+	{deadline: 1, submitter: "carol", seq: 3},
+	{deadline: 2, submitter: "alice", seq: 4},
+}
+
+func TestFromComparator_TieBreaker(t *testing.T) {
+	heap := NewDaryHeap[string, jobPriority](3, nil, FromComparator[jobPriority](deadlineOrder{}), false)
+	for _, p := range comparatorJobs {
+		heap.Push("", p)
+	}
+	seqs := popSeqs(heap)
+	assert.ElementsMatch(t, []int{1, 3}, seqs[:2])
+	assert.ElementsMatch(t, []int{2, 4}, seqs[2:4])
+	assert.Equal(t, 0, seqs[4])
+}
+
+func TestLexicographic(t *testing.T) {
+	order := Lexicographic[jobPriority](
+		ByKey(func(p jobPriority) int { return p.deadline }),
+		ByKey(func(p jobPriority) string { return p.submitter }),
+		ByKey(func(p jobPriority) int { return p.seq }).Reverse(),
+	)
+	heap := NewDaryHeap[string, jobPriority](2, nil, FromComparator[jobPriority](order), false)
+	for _, p := range comparatorJobs {
+		heap.Push("", p)
+	}
+	assert.Equal(t, []int{3, 1, 4, 2, 0}, popSeqs(heap))
+	assert.False(t, order.Less(comparatorJobs[0], comparatorJobs[0]))
+}
+
+func TestFromComparator_Stable(t *testing.T) {
+	// Without a tie-breaker, a stable heap pops equal deadlines first-in,
+	// first-out.
+	byDeadline := ByKey(func(p jobPriority) int { return p.deadline })
+	heap := NewStableBinaryHeap[string, jobPriority](nil, FromComparator[jobPriority](byDeadline), DaryHeapConfig{})
+	for _, p := range comparatorJobs {
+		heap.Push("", p)
+	}
+	assert.Equal(t, []int{1, 3, 0, 2, 4}, popSeqs(heap))
+}
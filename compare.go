@@ -0,0 +1,55 @@
+package heapcraft
+
+// pairKey is a value and priority pair used to count elements when comparing
+// heaps.
+type pairKey[V comparable, P comparable] struct {
+	value    V
+	priority P
+}
+
+// sameNodes reports whether a and b hold the same value and priority pairs
+// the same number of times, in any order.
+func sameNodes[V comparable, P comparable](a, b []HeapNode[V, P]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[pairKey[V, P]]int, len(a))
+	for _, node := range a {
+		counts[pairKey[V, P]{node.value, node.priority}]++
+	}
+	for _, node := range b {
+		key := pairKey[V, P]{node.value, node.priority}
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}
+
+// SameContents reports whether a and b hold the same value and priority
+// pairs, counting duplicates, regardless of their internal structure or of
+// the order in which they would be popped. Neither heap is modified. It is
+// useful in tests and for reconciling replicated queues.
+func SameContents[V comparable, P comparable](a, b BaseHeap[V, P]) bool {
+	return sameNodes(a.Export(ExportOptions[V, P]{}), b.Export(ExportOptions[V, P]{}))
+}
+
+// Equal reports whether a and b hold the same contents, as SameContents does,
+// and would also pop their priorities in the same order. Elements with equal
+// priorities may pop in any order, so their values are compared as a group
+// rather than position by position. Two heaps with the same elements but
+// opposite orderings are therefore only equal if every priority is the same.
+// Neither heap is modified.
+func Equal[V comparable, P comparable](a, b BaseHeap[V, P]) bool {
+	left, right := a.Export(ExportOptions[V, P]{}), b.Export(ExportOptions[V, P]{})
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i].priority != right[i].priority {
+			return false
+		}
+	}
+	return sameNodes(left, right)
+}
@@ -0,0 +1,37 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqualAndSameContents(t *testing.T) {
+	nodes := func() []HeapNode[string, int] {
+		return []HeapNode[string, int]{
+			CreateHeapNode("a", 1), CreateHeapNode("b", 2), CreateHeapNode("c", 2),
+			CreateHeapNode("d", 3), CreateHeapNode("d", 3),
+		}
+	}
+	dary := NewDaryHeap(4, nodes(), lt, false)
+	pairing := NewPairingHeap(nodes(), lt, false)
+	pairing.Push("a", 1)
+	pairing.Pop()
+	assert.True(t, SameContents[string, int](dary, pairing))
+	assert.True(t, Equal[string, int](dary, pairing))
+	assert.Equal(t, 5, dary.Length())
+
+	maxHeap := NewLeftistHeap(nodes(), gt, false)
+	assert.True(t, SameContents[string, int](dary, maxHeap))
+	assert.False(t, Equal[string, int](dary, maxHeap))
+
+	pairing.Push("d", 3)
+	assert.False(t, SameContents[string, int](dary, pairing))
+	dary.Push("e", 3)
+	assert.False(t, SameContents[string, int](dary, pairing))
+	assert.False(t, Equal[string, int](dary, pairing))
+
+	empty := NewSkewHeap[string, int](nil, lt, false)
+	assert.True(t, Equal[string, int](empty, NewBinomialHeap[string, int](nil, lt, HeapConfig{})))
+	assert.False(t, SameContents[string, int](empty, dary))
+}
@@ -0,0 +1,147 @@
+package heapcraft
+
+import "fmt"
+
+// compositeLane is one heap of a CompositeQueue with its share of pops.
+type compositeLane[V any, P any] struct {
+	name   string
+	heap   Heap[V, P]
+	weight int
+	// credit is the lane's running balance in smooth weighted round-robin.
+	credit int
+}
+
+// CompositeQueue multiplexes several heaps, called lanes, under a single Pop.
+// Each lane has a weight, and pops are shared between the non-empty lanes in
+// proportion to their weights using smooth weighted round-robin: with weights
+// 7 and 3, every ten pops take seven elements from the first lane and three
+// from the second, interleaved rather than in runs. Within a lane, elements
+// come out in that heap's priority order. A lane that is empty neither earns
+// nor spends credit, so the other lanes absorb its share until it has
+// elements again. Any Heap can
+// be a lane, and lanes need not be of the same type. A CompositeQueue is not
+// safe for concurrent use.
+type CompositeQueue[V any, P any] struct {
+	lanes []compositeLane[V, P]
+	index map[string]int
+}
+
+// AddLane adds heap as a lane under name with the given weight. Elements
+// already in heap are served like any other. Returns ErrInvalidWeight if
+// weight is not positive and ErrLaneExists if name is already in use.
+func (q *CompositeQueue[V, P]) AddLane(name string, heap Heap[V, P], weight int) error {
+	if weight <= 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidWeight, weight)
+	}
+	if _, ok := q.index[name]; ok {
+		return fmt.Errorf("%w: %q", ErrLaneExists, name)
+	}
+	q.index[name] = len(q.lanes)
+	q.lanes = append(q.lanes, compositeLane[V, P]{name: name, heap: heap, weight: weight})
+	return nil
+}
+
+// Lane returns the heap of the named lane. Returns ErrLaneNotFound if there
+// is no such lane.
+func (q *CompositeQueue[V, P]) Lane(name string) (Heap[V, P], error) {
+	i, ok := q.index[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrLaneNotFound, name)
+	}
+	return q.lanes[i].heap, nil
+}
+
+// Push inserts an element into the named lane. Returns ErrLaneNotFound if
+// there is no such lane.
+func (q *CompositeQueue[V, P]) Push(lane string, value V, priority P) error {
+	heap, err := q.Lane(lane)
+	if err != nil {
+		return err
+	}
+	heap.Push(value, priority)
+	return nil
+}
+
+// next returns the index of the lane the next pop takes from, or -1 if every
+// lane is empty, along with the total weight of the non-empty lanes. It does
+// not change any credit, so that Peek and Pop agree.
+func (q *CompositeQueue[V, P]) next() (int, int) {
+	best, total := -1, 0
+	for i := range q.lanes {
+		lane := &q.lanes[i]
+		if lane.heap.IsEmpty() {
+			continue
+		}
+		total += lane.weight
+		if best < 0 || lane.credit+lane.weight > q.lanes[best].credit+q.lanes[best].weight {
+			best = i
+		}
+	}
+	return best, total
+}
+
+// PopLane removes and returns the next element along with the name of the
+// lane it came from. Every non-empty lane earns its weight in credit, the lane
+// with the most credit is served and pays back the total weight of the
+// non-empty lanes. Returns ErrHeapEmpty if every lane is empty.
+func (q *CompositeQueue[V, P]) PopLane() (string, V, P, error) {
+	best, total := q.next()
+	if best < 0 {
+		v, p := zeroValuePair[V, P]()
+		return "", v, p, ErrHeapEmpty
+	}
+	for i := range q.lanes {
+		if !q.lanes[i].heap.IsEmpty() {
+			q.lanes[i].credit += q.lanes[i].weight
+		}
+	}
+	lane := &q.lanes[best]
+	lane.credit -= total
+	v, p, err := lane.heap.Pop()
+	return lane.name, v, p, err
+}
+
+// Pop removes and returns the next element. See PopLane.
+func (q *CompositeQueue[V, P]) Pop() (V, P, error) {
+	_, v, p, err := q.PopLane()
+	return v, p, err
+}
+
+// Peek returns the element that the next Pop would return without removing
+// it. Returns ErrHeapEmpty if every lane is empty.
+func (q *CompositeQueue[V, P]) Peek() (V, P, error) {
+	best, _ := q.next()
+	if best < 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	return q.lanes[best].heap.Peek()
+}
+
+// Length returns the number of elements across all lanes.
+func (q *CompositeQueue[V, P]) Length() int {
+	n := 0
+	for _, lane := range q.lanes {
+		n += lane.heap.Length()
+	}
+	return n
+}
+
+// IsEmpty returns true if every lane is empty.
+func (q *CompositeQueue[V, P]) IsEmpty() bool {
+	for _, lane := range q.lanes {
+		if !lane.heap.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear removes all elements from every lane and resets the round-robin
+// state. The lanes themselves are kept.
+func (q *CompositeQueue[V, P]) Clear() {
+	for i := range q.lanes {
+		q.lanes[i].heap.Clear()
+		q.lanes[i].credit = 0
+	}
+}
@@ -0,0 +1,7 @@
+package heapcraft
+
+// NewCompositeQueue creates a CompositeQueue without lanes. Add lanes with
+// AddLane before pushing.
+func NewCompositeQueue[V any, P any]() *CompositeQueue[V, P] {
+	return &CompositeQueue[V, P]{index: make(map[string]int)}
+}
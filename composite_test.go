@@ -0,0 +1,86 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeQueue_Weights(t *testing.T) {
+	q := NewCompositeQueue[int, int]()
+	require.NoError(t, q.AddLane("high", NewBinaryHeap[int, int](nil, lt, false), 7))
+	require.NoError(t, q.AddLane("background", NewPairingHeap[int, int](nil, lt, false), 3))
+	for i := 0; i < 100; i++ {
+		require.NoError(t, q.Push("high", i, i))
+		require.NoError(t, q.Push("background", 1000+i, i))
+	}
+	assert.Equal(t, 200, q.Length())
+
+	counts := map[string]int{}
+	var order []string
+	for i := 0; i < 100; i++ {
+		v, p, err := q.Peek()
+		require.NoError(t, err)
+		lane, pv, pp, err := q.PopLane()
+		require.NoError(t, err)
+		assert.Equal(t, v, pv)
+		assert.Equal(t, p, pp)
+		counts[lane]++
+		if i < 10 {
+			order = append(order, lane)
+		}
+	}
+	assert.Equal(t, map[string]int{"high": 70, "background": 30}, counts)
+	// Smooth round-robin interleaves the lanes instead of serving runs.
+	assert.Equal(t, []string{"high", "background", "high", "high", "high", "background", "high",
+		"high", "background", "high"}, order)
+
+	// Within a lane, elements come out in priority order.
+	lane, err := q.Lane("high")
+	require.NoError(t, err)
+	p, err := lane.PeekPriority()
+	require.NoError(t, err)
+	assert.Equal(t, 70, p)
+}
+
+func TestCompositeQueue_EmptyLanes(t *testing.T) {
+	q := NewCompositeQueue[string, int]()
+	_, _, err := q.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	require.NoError(t, q.AddLane("a", NewBinaryHeap[string, int](nil, lt, false), 1))
+	require.NoError(t, q.AddLane("b", NewBinaryHeap[string, int](nil, lt, false), 1))
+	assert.True(t, q.IsEmpty())
+	_, _, err = q.Peek()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	// With one lane empty, the other takes every pop.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Push("a", "a", i))
+	}
+	values := []string{}
+	for !q.IsEmpty() {
+		v, _, err := q.Pop()
+		require.NoError(t, err)
+		values = append(values, v)
+	}
+	assert.Equal(t, []string{"a", "a", "a"}, values)
+
+	q.Push("a", "a", 1)
+	q.Push("b", "b", 1)
+	q.Clear()
+	assert.Zero(t, q.Length())
+	_, err = q.Lane("b")
+	assert.NoError(t, err)
+}
+
+func TestCompositeQueue_Errors(t *testing.T) {
+	q := NewCompositeQueue[string, int]()
+	assert.ErrorIs(t, q.AddLane("a", NewBinaryHeap[string, int](nil, lt, false), 0), ErrInvalidWeight)
+	require.NoError(t, q.AddLane("a", NewBinaryHeap[string, int](nil, lt, false), 1))
+	assert.ErrorIs(t, q.AddLane("a", NewBinaryHeap[string, int](nil, lt, false), 2), ErrLaneExists)
+	assert.ErrorIs(t, q.Push("missing", "x", 1), ErrLaneNotFound)
+	_, err := q.Lane("missing")
+	assert.ErrorIs(t, err, ErrLaneNotFound)
+}
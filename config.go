@@ -7,8 +7,80 @@ type HeapConfig struct {
 	// IDGenerator is a pointer to an IDGenerator that is used to generate
 	// unique IDs for the heap. If nil, the default IDGenerator is used.
 	IDGenerator IDGenerator
+	// ArenaSlabSize, if positive, makes a tree-based heap allocate its nodes
+	// from slabs of this many nodes and release them all at once on Clear,
+	// instead of allocating and pooling them one by one. It takes precedence
+	// over UsePool.
+	ArenaSlabSize int
+	// Capacity, if positive, is the number of elements the heap is expected
+	// to hold. Tracked heaps size their element maps and index arrays for it
+	// up front, and an arena allocates its first slab large enough for that
+	// many nodes, so a bulk load of known size does not grow them repeatedly.
+	Capacity int
+	// MaxDepth limits how deep the recursive merges of pairing and skew heaps
+	// may go before an operation fails with ErrMaxDepthExceeded, or, where it
+	// has no error result, merges without recursion instead. Zero selects a
+	// default of 2^20 and a negative value disables the limit.
+	MaxDepth int
+	// AuxiliaryPush builds a PairingHeap in auxiliary mode, as described on
+	// PairingHeap: Push prepends new elements to an auxiliary list
+	// in O(1) and the list is melded into the tree on the next Pop. Other
+	// heaps ignore it.
+	AuxiliaryPush bool
 }
 
+// DaryHeapConfig is a struct that contains the configuration for a d-ary heap,
+// and for the heaps that keep their elements in one.
+type DaryHeapConfig struct {
+	// UsePool has no effect.
+	//
+	// Deprecated: d-ary heaps store their elements by value in their array
+	// and have no nodes to pool, so there is nothing for it to switch on. It
+	// is kept only so existing configurations still compile.
+	UsePool bool
+	// Stable breaks ties between equal priorities by insertion order, as in
+	// NewStableDaryHeap.
+	Stable bool
+	// DisableCallbacks builds the heap without a swap callback registry, for
+	// callers that never register one. Register then panics and Deregister
+	// fails with ErrCallbacksDisabled.
+	DisableCallbacks bool
+	// BlockLevels stores the heap in the blocked layout of a B-heap when it
+	// is 2 or more: the array is divided into blocks that each hold a whole
+	// subtree of BlockLevels levels, so sifting an element down moves into a
+	// new block once every BlockLevels levels instead of touching a distant
+	// part of the array on almost every level. This reduces cache and TLB
+	// misses once the heap is much larger than the CPU caches, at the cost of
+	// some index arithmetic on every step. Indices passed to Update, Fix and
+	// Remove and reported to swap callbacks are positions in this layout.
+	BlockLevels int
+	// AutoShrink releases memory as the heap drains: whenever an element is
+	// removed and the array has more than four times the capacity it needs,
+	// it is reallocated with room for twice its length. Small arrays are left
+	// alone.
+	AutoShrink bool
+	// Capacity, if larger than the initial data, is the number of elements
+	// the heap's array is sized for up front, as if by Reserve.
+	Capacity int
+}
+
+// RadixHeapConfig is a struct that contains the configuration for a radix
+// heap.
+type RadixHeapConfig struct {
+	// UsePool indicates whether to use a pool for the heap's elements.
+	UsePool bool
+	// AutoShrink releases the backing array of the lowest bucket as soon as
+	// pops have emptied it, instead of keeping it for the next elements that
+	// land there, so that the heap does not hold on to the memory of its
+	// largest burst. The other buckets already release theirs whenever they
+	// are redistributed.
+	AutoShrink bool
+}
+
+// sizeHint returns the number of elements to size a heap's maps and arrays
+// for when it starts with n elements: n or Capacity, whichever is larger.
+func (h *HeapConfig) sizeHint(n int) int { return max(h.Capacity, n) }
+
 // GetGenerator returns the IDGenerator from the HeapConfig.
 // If the IDGenerator is nil, the default IDGenerator is returned.
 func (h *HeapConfig) GetGenerator() IDGenerator {
@@ -17,3 +89,103 @@ func (h *HeapConfig) GetGenerator() IDGenerator {
 	}
 	return h.IDGenerator
 }
+
+// HeapOption sets one field of a heap configuration. The same options build a
+// HeapConfig with NewHeapConfig, a DaryHeapConfig with NewDaryHeapConfig and a
+// RadixHeapConfig with NewRadixHeapConfig, so one list of options can be kept
+// and reused with any constructor that takes one of them. Options are applied
+// in order, and an option for a field a configuration does not have is
+// ignored by it.
+type HeapOption func(*heapOptions)
+
+// heapOptions collects the configurations that options are applied to.
+type heapOptions struct {
+	heap  HeapConfig
+	dary  DaryHeapConfig
+	radix RadixHeapConfig
+}
+
+// applyOptions applies opts in order to empty configurations.
+func applyOptions(opts []HeapOption) heapOptions {
+	var o heapOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewHeapConfig returns the HeapConfig set by opts, for the tracked heaps,
+// indexed heaps and timers.
+func NewHeapConfig(opts ...HeapOption) HeapConfig { return applyOptions(opts).heap }
+
+// NewDaryHeapConfig returns the DaryHeapConfig set by opts, for the d-ary
+// heaps and the heaps built on them.
+func NewDaryHeapConfig(opts ...HeapOption) DaryHeapConfig { return applyOptions(opts).dary }
+
+// NewRadixHeapConfig returns the RadixHeapConfig set by opts, for the radix
+// heaps.
+func NewRadixHeapConfig(opts ...HeapOption) RadixHeapConfig { return applyOptions(opts).radix }
+
+// WithPool sets whether the heap reuses its nodes through a pool. D-ary heaps
+// have no nodes to pool, so it leaves a DaryHeapConfig unchanged.
+func WithPool(usePool bool) HeapOption {
+	return func(o *heapOptions) {
+		o.heap.UsePool = usePool
+		o.radix.UsePool = usePool
+	}
+}
+
+// WithIDGenerator sets the generator used for the IDs of a tracked heap's
+// elements.
+func WithIDGenerator(generator IDGenerator) HeapOption {
+	return func(o *heapOptions) { o.heap.IDGenerator = generator }
+}
+
+// WithArena allocates a tracked heap's nodes from slabs of slabSize nodes.
+func WithArena(slabSize int) HeapOption {
+	return func(o *heapOptions) { o.heap.ArenaSlabSize = slabSize }
+}
+
+// WithCapacity sets the number of elements the heap is expected to hold, so
+// that its arrays and maps are sized for them up front.
+func WithCapacity(n int) HeapOption {
+	return func(o *heapOptions) {
+		o.heap.Capacity = n
+		o.dary.Capacity = n
+	}
+}
+
+// WithMaxDepth sets the recursion limit of a pairing or skew heap's merges.
+func WithMaxDepth(n int) HeapOption {
+	return func(o *heapOptions) { o.heap.MaxDepth = n }
+}
+
+// WithAuxiliaryPush builds a pairing heap in auxiliary mode.
+func WithAuxiliaryPush() HeapOption {
+	return func(o *heapOptions) { o.heap.AuxiliaryPush = true }
+}
+
+// WithStableOrder makes a d-ary heap pop equal priorities in insertion order.
+func WithStableOrder() HeapOption {
+	return func(o *heapOptions) { o.dary.Stable = true }
+}
+
+// WithCallbacks sets whether a d-ary heap keeps a swap callback registry.
+// Callbacks are enabled by default.
+func WithCallbacks(enabled bool) HeapOption {
+	return func(o *heapOptions) { o.dary.DisableCallbacks = !enabled }
+}
+
+// WithAutoShrink makes a d-ary or radix heap release memory as it drains.
+func WithAutoShrink() HeapOption {
+	return func(o *heapOptions) {
+		o.dary.AutoShrink = true
+		o.radix.AutoShrink = true
+	}
+}
+
+// WithBlockLevels stores a d-ary heap in the blocked layout of a B-heap with
+// subtrees of the given number of levels per block.
+func WithBlockLevels(levels int) HeapOption {
+	return func(o *heapOptions) { o.dary.BlockLevels = levels }
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHeapConfigDefaultGenerator(t *testing.T) {
@@ -39,3 +40,66 @@ func TestHeapConfigUsePool(t *testing.T) {
 	config.UsePool = false
 	assert.False(t, config.UsePool)
 }
+
+func TestHeapOptions(t *testing.T) {
+	generator := &IntegerIDGenerator{}
+	opts := []HeapOption{
+		WithPool(true),
+		WithIDGenerator(generator),
+		WithArena(64),
+		WithCapacity(100),
+		WithStableOrder(),
+		WithCallbacks(false),
+		WithAutoShrink(),
+		WithBlockLevels(3),
+	}
+
+	assert.Equal(t, HeapConfig{UsePool: true, IDGenerator: generator, ArenaSlabSize: 64, Capacity: 100}, NewHeapConfig(opts...))
+	assert.Equal(t, DaryHeapConfig{
+		Stable:           true,
+		DisableCallbacks: true,
+		BlockLevels:      3,
+		AutoShrink:       true,
+		Capacity:         100,
+	}, NewDaryHeapConfig(opts...))
+	assert.Equal(t, RadixHeapConfig{UsePool: true, AutoShrink: true}, NewRadixHeapConfig(opts...))
+	assert.Equal(t, DaryHeapConfig{}, NewDaryHeapConfig(WithCallbacks(false), WithCallbacks(true)))
+
+	heap := NewDaryHeapWithConfig(2, []HeapNode[string, int]{
+		CreateHeapNode("b", 1),
+		CreateHeapNode("a", 1),
+	}, lt, NewDaryHeapConfig(WithStableOrder(), WithCapacity(50)))
+	assert.GreaterOrEqual(t, cap(heap.data), 50)
+	heap.Push("c", 0)
+	assert.Equal(t, []string{"c", "b", "a"}, heap.DrainValues())
+
+	tracked := NewFullPairingHeap[string, int](nil, lt, NewHeapConfig(WithIDGenerator(generator)))
+	id, err := tracked.Push("a", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", id)
+}
+
+func TestConstructors_PassConfigThrough(t *testing.T) {
+	data := []HeapNode[string, int]{CreateHeapNode("x", 1), CreateHeapNode("y", 1), CreateHeapNode("z", 1)}
+	stable := NewBinaryHeapWithConfig(data, lt, DaryHeapConfig{Stable: true})
+	assert.Equal(t, []string{"x", "y", "z"}, stable.DrainValues())
+
+	syncHeap := NewSyncBinaryHeapWithConfig[string, int](nil, lt, DaryHeapConfig{DisableCallbacks: true})
+	assert.ErrorIs(t, syncHeap.Deregister("missing"), ErrCallbacksDisabled)
+
+	keyed := NewKeyedHeap[string, string, int](2, lt, DaryHeapConfig{DisableCallbacks: true, Stable: true})
+	keyed.Push("a", "first", 1)
+	keyed.Push("b", "second", 1)
+	keyed.Push("c", "third", 0)
+	value, priority, err := keyed.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, "second", value)
+	assert.Equal(t, 1, priority)
+	assert.Equal(t, []string{"third", "first", "second"}, keyed.DrainValues())
+
+	queue := NewAddressableMinQueue[string, int](lt, HeapConfig{})
+	assert.IsType(t, &IntegerIDGenerator{}, queue.heap.idGen)
+	generator := &AtomicIDGenerator{}
+	queue = NewAddressableMinQueue[string, int](lt, HeapConfig{IDGenerator: generator})
+	assert.True(t, queue.heap.idGen == IDGenerator(generator))
+}
@@ -1,5 +1,14 @@
 package heapcraft
 
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
 // DaryHeap represents a generic d-ary heap with support for swap callbacks. The
 // heap can be either a min-heap or max-heap depending on the comparison
 // function.   - data: slice of HeapNode containing value-priority pairs   - cmp:
@@ -10,33 +19,174 @@ type DaryHeap[V any, P any] struct {
 	data   []HeapNode[V, P]
 	cmp    func(a, b P) bool
 	onSwap callbacks
+	// hooked records whether any swap callback is registered, so that swap
+	// can skip the registry, and the lock of a thread-safe one, when none is.
+	hooked bool
 	d      int
-	pool   pool[HeapNode[V, P]]
+	blocks blockLayout
+	alarms depthAlarms
+	stats  heapStats
+	events listeners[HeapEvent[V, P]]
+	stable bool
+	seq    uint64
+	// shrink reallocates the array as elements are removed once it has far
+	// more capacity than it needs.
+	shrink bool
+	// handles holds the handle of the element at each index, or nil for
+	// elements without one. It stays nil until the first handle is taken.
+	handles []*DaryHandle
+}
+
+// blockLayout describes the blocked layout of a B-heap, in which the array is
+// divided into blocks that each hold a complete subtree of a fixed number of
+// levels. Within a block the subtree is stored level by level as in the
+// implicit layout, and the d children of each of its bottom-level nodes are
+// the roots of d consecutive blocks. Blocks are filled in breadth-first order
+// of the tree they form, so the elements always occupy a prefix of the array
+// and every element still comes after its parent. The zero value is the
+// implicit layout.
+type blockLayout struct {
+	levels int
+	// size is the number of elements in a block.
+	size int
+	// fanout is the number of child blocks of a block.
+	fanout int
+	// leaves is the index within a block of its first bottom-level node.
+	leaves int
+}
+
+// newBlockLayout returns the layout of a d-ary B-heap whose blocks hold
+// subtrees of the given number of levels. Fewer than two levels per block is
+// the implicit layout.
+func newBlockLayout(d int, levels int) blockLayout {
+	if levels < 2 {
+		return blockLayout{}
+	}
+	width, size := 1, 1
+	for l := 1; l < levels; l++ {
+		width *= d
+		size += width
+	}
+	return blockLayout{levels: levels, size: size, fanout: width * d, leaves: size - width}
+}
+
+// parent returns the index of the parent of the element at index i > 0.
+func (b blockLayout) parent(i int, d int) int {
+	block, local := i/b.size, i%b.size
+	if local > 0 {
+		return block*b.size + (local-1)/d
+	}
+	slot := (block - 1) % b.fanout
+	return (block-1)/b.fanout*b.size + b.leaves + slot/d
+}
+
+// children returns the index of the first child of the element at index i and
+// the distance between consecutive children. The children of a bottom-level
+// node are the roots of consecutive blocks, one block apart.
+func (b blockLayout) children(i int, d int) (int, int) {
+	block, local := i/b.size, i%b.size
+	if local < b.leaves {
+		return block*b.size + d*local + 1, 1
+	}
+	return (block*b.fanout + 1 + (local-b.leaves)*d) * b.size, b.size
+}
+
+// parent returns the index of the parent of the element at index i > 0.
+func (h *DaryHeap[V, P]) parent(i int) int {
+	if h.blocks.size == 0 {
+		return (i - 1) / h.d
+	}
+	return h.blocks.parent(i, h.d)
+}
+
+// children returns the index of the first child of the element at index i and
+// the distance between consecutive children. The first child may lie past the
+// end of the heap.
+func (h *DaryHeap[V, P]) children(i int) (int, int) {
+	if h.blocks.size == 0 {
+		return h.d*i + 1, 1
+	}
+	return h.blocks.children(i, h.d)
+}
+
+// setArity changes the arity of the heap, keeping the number of levels per
+// block of a blocked layout. The elements must be re-heapified afterwards.
+func (h *DaryHeap[V, P]) setArity(d int) {
+	h.d = d
+	h.blocks = newBlockLayout(d, h.blocks.levels)
+}
+
+// heapify restores the heap order over the whole array in O(n) by sifting
+// down every element that has children, children before their parents.
+func (h *DaryHeap[V, P]) heapify() {
+	start := h.Length() - 1
+	if h.blocks.size == 0 {
+		start = (h.Length() - 2) / h.d
+	}
+	for i := start; i >= 0; i-- {
+		h.siftDown(i)
+	}
 }
 
 // getNewNode creates a new HeapNode with the given value and priority.
 // It is used to create new nodes when inserting elements into the heap.
 func (h *DaryHeap[V, P]) getNewNode(value V, priority P) HeapNode[V, P] {
-	node := h.pool.Get()
-	node.value = value
-	node.priority = priority
+	node := HeapNode[V, P]{value: value, priority: priority}
+	if h.stable {
+		h.seq++
+		node.seq = h.seq
+	}
 	return node
 }
 
+// before reports whether a belongs closer to the root than b. On a stable
+// heap, elements of equal priority are ordered by insertion, so they pop in
+// FIFO order.
+func (h *DaryHeap[V, P]) before(a, b HeapNode[V, P]) bool {
+	if h.cmp(a.priority, b.priority) {
+		return true
+	}
+	return h.stable && a.seq < b.seq && !h.cmp(b.priority, a.priority)
+}
+
 // Deregister removes the callback with the specified ID from the heap's swap
-// callbacks. Returns an error if no callback exists with the given ID.
-func (h *DaryHeap[V, P]) Deregister(id string) error { return h.onSwap.deregister(id) }
+// callbacks. Returns an error if no callback exists with the given ID, or
+// ErrCallbacksDisabled if the heap was created with DisableCallbacks.
+func (h *DaryHeap[V, P]) Deregister(id string) error {
+	if h.onSwap == nil {
+		return ErrCallbacksDisabled
+	}
+	if err := h.onSwap.deregister(id); err != nil {
+		return err
+	}
+	h.hooked = h.onSwap.count() > 0
+	return nil
+}
 
 // Register adds a callback function to be called whenever elements in the heap
 // swap positions. Returns a callback that can be used to deregister the
-// function later.
-func (h *DaryHeap[V, P]) Register(fn func(x, y int)) callback { return h.onSwap.register(fn) }
+// function later. Panics with ErrCallbacksDisabled if the heap was created
+// with DisableCallbacks.
+func (h *DaryHeap[V, P]) Register(fn func(x, y int)) callback {
+	if h.onSwap == nil {
+		panic(ErrCallbacksDisabled)
+	}
+	registered := h.onSwap.register(fn)
+	h.hooked = true
+	return registered
+}
 
 // swap exchanges the elements at indices i and j in the heap, and invokes all
-// registered swap callbacks with the indices.
+// registered swap callbacks with the indices. Heaps without callbacks only
+// pay for a branch.
 func (h *DaryHeap[V, P]) swap(i int, j int) {
 	h.data[i], h.data[j] = h.data[j], h.data[i]
-	h.onSwap.run(i, j)
+	if h.handles != nil {
+		h.swapHandles(i, j)
+	}
+	if h.hooked {
+		h.onSwap.run(i, j)
+	}
 }
 
 // swapWithLast swaps the element at index i with the last element in the heap,
@@ -45,14 +195,112 @@ func (h *DaryHeap[V, P]) swap(i int, j int) {
 func (h *DaryHeap[V, P]) swapWithLastAndRemove(i int) HeapNode[V, P] {
 	removed := h.data[i]
 	h.swap(i, h.Length()-1)
-	h.data = h.data[:h.Length()-1]
+	h.dropLast()
+	h.stats.record(OpPop, 1, h.Length())
+	h.alarms.check(h.Length())
 	h.siftDown(i)
 	return removed
 }
 
+// dropLast shortens the array by one element, zeroing the vacated slot so that
+// the backing array does not keep the removed value reachable.
+func (h *DaryHeap[V, P]) dropLast() {
+	last := h.Length() - 1
+	h.data[last] = HeapNode[V, P]{}
+	h.data = h.data[:last]
+	if h.handles != nil {
+		h.releaseHandle(last)
+		h.handles = h.handles[:last]
+	}
+	if h.shrink {
+		h.data = shrinkNodes(h.data)
+	}
+}
+
+// removeAt removes the element at index i and restores the heap order around
+// the element moved into its place, sifting it up or down as needed. Unlike
+// swapWithLastAndRemove, it is correct for any index, and every move is
+// reported to the swap callbacks. Returns the removed HeapNode.
+func (h *DaryHeap[V, P]) removeAt(i int) HeapNode[V, P] {
+	last := h.Length() - 1
+	removed := h.data[i]
+	h.swap(i, last)
+	h.dropLast()
+	h.stats.record(OpRemove, 1, last)
+	h.alarms.check(last)
+	if i < last {
+		h.restoreHeap(i)
+	}
+	emitHeapEvent(h.events, EventRemove, "", removed.value, removed.priority)
+	return removed
+}
+
 // Clear removes all elements from the heap by resetting its underlying slice to
-// length zero.
-func (h *DaryHeap[V, P]) Clear() { h.data = nil }
+// length zero. The backing array is released; use Reset to keep it.
+func (h *DaryHeap[V, P]) Clear() {
+	cleared := h.Length()
+	h.data = nil
+	h.finishClear(cleared)
+}
+
+// Reset removes all elements from the heap like Clear, but truncates the
+// underlying slice instead of releasing it, so that a heap refilled to a
+// similar size does not allocate again. The vacated slots are zeroed so that
+// the backing array does not keep the removed values reachable.
+func (h *DaryHeap[V, P]) Reset() {
+	cleared := h.Length()
+	clear(h.data)
+	h.data = h.data[:0]
+	h.finishClear(cleared)
+}
+
+// finishClear records the removal of all n elements of the heap.
+func (h *DaryHeap[V, P]) finishClear(n int) {
+	h.releaseHandles()
+	h.stats.record(OpClear, n, 0)
+	h.alarms.check(0)
+	emitClearEvent(h.events)
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its array, counted
+// by capacity, and its handle array. Memory that values and priorities point
+// to, such as the contents of strings, is not counted.
+func (h *DaryHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[DaryHeap[V, P]]() + cap(h.data)*sizeOf[HeapNode[V, P]]() + cap(h.handles)*sizeOf[*DaryHandle]()
+}
+
+// Maintain performs pending upkeep on the heap. If the underlying slice has
+// more than twice the capacity needed for its elements, it is reallocated to
+// fit so the excess memory can be reclaimed. Returns the context error if ctx
+// is already done.
+func (h *DaryHeap[V, P]) Maintain(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if cap(h.data) > 2*len(h.data) {
+		h.data = compactNodes(h.data)
+	}
+	return nil
+}
+
+// Reserve grows the underlying slice so that at least n more elements can be
+// pushed without reallocating it, which avoids repeated growth during a bulk
+// load of known size. A heap built with AutoShrink may release the reserved
+// capacity again as elements are removed.
+func (h *DaryHeap[V, P]) Reserve(n int) {
+	if n > 0 {
+		h.data = slices.Grow(h.data, n)
+	}
+}
+
+// Compact reallocates the underlying slice to fit the heap's elements exactly,
+// releasing any capacity left over from earlier growth. It runs in O(n) and is
+// intended for long-lived heaps after a burst has been drained.
+func (h *DaryHeap[V, P]) Compact() {
+	if cap(h.data) > len(h.data) {
+		h.data = compactNodes(h.data)
+	}
+}
 
 // Length returns the current number of elements in the heap.
 func (h *DaryHeap[V, P]) Length() int { return len(h.data) }
@@ -60,6 +308,19 @@ func (h *DaryHeap[V, P]) Length() int { return len(h.data) }
 // IsEmpty returns true if the heap contains no elements.
 func (h *DaryHeap[V, P]) IsEmpty() bool { return h.Length() == 0 }
 
+// Verify checks the internal consistency of the heap: every element must not
+// come before its parent. It is intended for tests and debugging, runs in O(n)
+// and returns an error wrapping ErrInvariantViolated for the first violation.
+func (h *DaryHeap[V, P]) Verify() error {
+	for i := 1; i < len(h.data); i++ {
+		parent := h.parent(i)
+		if h.before(h.data[i], h.data[parent]) {
+			return invariantError("element at index %d comes before its parent at index %d", i, parent)
+		}
+	}
+	return nil
+}
+
 // pop removes and returns the root element of the heap.
 // If the heap is empty, returns a zero value and priority with an error.
 func (h *DaryHeap[V, P]) pop() (V, P, error) {
@@ -69,10 +330,43 @@ func (h *DaryHeap[V, P]) pop() (V, P, error) {
 	}
 	removed := h.swapWithLastAndRemove(0)
 	v, p := removed.value, removed.priority
-	h.pool.Put(removed)
+	emitHeapEvent(h.events, EventPop, "", v, p)
 	return v, p, nil
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (h *DaryHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return h.alarms.register(n, h.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (h *DaryHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return h.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (h *DaryHeap[V, P]) Stats() HeapStats { return h.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (h *DaryHeap[V, P]) Instrument(hook Instrumentation) { h.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. Events carry the element's
+// value and priority but no ID. Returns an ID that can be passed to
+// RemoveListener.
+func (h *DaryHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return h.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *DaryHeap[V, P]) RemoveListener(id string) error { return h.events.deregister(id) }
+
 // peek returns the root HeapNode without removing it.
 // If the heap is empty, returns a zero value and priority with an error.
 func (h *DaryHeap[V, P]) peek() (V, P, error) {
@@ -89,6 +383,46 @@ func (h *DaryHeap[V, P]) peek() (V, P, error) {
 // cmp). If the heap is empty, returns a zero value and priority with an error.
 func (h *DaryHeap[V, P]) Pop() (V, P, error) { return h.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (h *DaryHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(h.Peek, h.Pop, pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped. It returns nil if pred
+// rejects the root or the heap is empty.
+func (h *DaryHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	return popWhile(h.Peek, h.Pop, pred)
+}
+
+// CountAtOrBelow returns the number of elements whose priority does not come
+// after p according to cmp: with a less-than comparison, those with a priority
+// at or below p. It walks the heap from the root and skips every subtree whose
+// root comes after p, so it runs in O(k*d) for k matching elements rather
+// than O(n).
+func (h *DaryHeap[V, P]) CountAtOrBelow(p P) int {
+	if h.IsEmpty() || h.cmp(p, h.data[0].priority) {
+		return 0
+	}
+	count := 0
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		count++
+		first, step := h.children(i)
+		for k := first; k < first+h.d*step && k < h.Length(); k += step {
+			if !h.cmp(p, h.data[k].priority) {
+				stack = append(stack, k)
+			}
+		}
+	}
+	return count
+}
+
 // Peek returns the root HeapNode without removing it.
 // If the heap is empty, returns a zero value and priority with an error.
 func (h *DaryHeap[V, P]) Peek() (V, P, error) { return h.peek() }
@@ -105,6 +439,150 @@ func (h *DaryHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(h.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (h *DaryHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(h.Length(), h.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (h *DaryHeap[V, P]) DrainValues() []V {
+	return drainValues(h.Length(), h.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (h *DaryHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(h.Length(), h.pop)
+}
+
+// forEach calls fn for every element in the heap, in storage order.
+func (h *DaryHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range h.data {
+		fn(node.value, node.priority)
+	}
+}
+
+// Data returns a read-only view of the heap's array: an iterator over the
+// values and priorities of its elements in storage order, with the root first.
+// Unlike the slice passed to NewDaryHeapOwned, the view cannot be used to
+// modify the heap, and nothing is copied. The heap must not be modified while
+// the iterator is in use.
+func (h *DaryHeap[V, P]) Data() iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		for _, node := range h.data {
+			if !yield(node.value, node.priority) {
+				return
+			}
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (h *DaryHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(h.Length(), h.forEach, h.cmp, opts)
+}
+
+// ordered returns an iterator over the indices of the heap's elements in the
+// order they would be popped.
+func (h *DaryHeap[V, P]) ordered() iter.Seq[int] {
+	var roots []int
+	if !h.IsEmpty() {
+		roots = []int{0}
+	}
+	node := func(i int) HeapNode[V, P] { return h.data[i] }
+	return orderedNodes(h.before, roots, node, func(i int, visit func(int)) {
+		first, step := h.children(i)
+		for k := first; k < first+h.d*step && k < h.Length(); k += step {
+			visit(k)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (h *DaryHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(h.ordered(), func(i int) (V, P) { return h.data[i].value, h.data[i].priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (h *DaryHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, h.ordered(), func(i int) HeapNode[V, P] { return h.data[i] })
+}
+
+// MarshalJSON encodes the heap as a JSON object holding its arity in "d" and
+// its elements best-first in "nodes". The comparison function is not encoded.
+func (h *DaryHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(heapJSON[V, P]{D: h.d, Nodes: h.Export(ExportOptions[V, P]{})})
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON, adopting the encoded arity if one is present. The heap must
+// have been created with its constructor so that it has a comparison function
+// to restore them with.
+func (h *DaryHeap[V, P]) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalHeap[V, P](data, h.cmp)
+	if err != nil {
+		return err
+	}
+
+	if decoded.D >= 1 {
+		h.setArity(decoded.D)
+	}
+	h.Clear()
+	for _, node := range decoded.Nodes {
+		h.Push(node.value, node.priority)
+	}
+	return nil
+}
+
+// Snapshot encodes the arity and elements of the heap in a compact binary
+// format using encoding/gob, for persisting the heap across process restarts.
+// Elements are written in internal order without sorting. The comparison
+// function is not encoded.
+func (h *DaryHeap[V, P]) Snapshot() ([]byte, error) {
+	snapshot := snapshotNodes(h.Length(), h.forEach)
+	snapshot.D = h.d
+	return encodeSnapshot(snapshot)
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot and adopts its arity. The elements are heapified in O(n).
+// The heap must have been created with its constructor so that it has a
+// comparison function to restore them with.
+func (h *DaryHeap[V, P]) Restore(data []byte) error {
+	if h.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	if snapshot.D >= 1 {
+		h.setArity(snapshot.D)
+	}
+	h.Clear()
+	h.data = make([]HeapNode[V, P], len(snapshot.Values))
+	for i, value := range snapshot.Values {
+		h.data[i] = h.getNewNode(value, snapshot.Priorities[i])
+		emitHeapEvent(h.events, EventPush, "", value, snapshot.Priorities[i])
+	}
+	h.heapify()
+	h.stats.rebalance()
+	h.stats.record(OpPush, h.Length(), h.Length())
+	h.alarms.check(h.Length())
+	return nil
+}
+
 // PeekValue returns just the value of the root element without removing it.
 // If the heap is empty, returns a zero value with an error.
 func (h *DaryHeap[V, P]) PeekValue() (V, error) {
@@ -120,9 +598,49 @@ func (h *DaryHeap[V, P]) PeekPriority() (P, error) {
 // Push inserts a new element with the given value and priority into the heap.
 // The element is added at the end and then sifted up to maintain the heap
 // property.
-func (h *DaryHeap[V, P]) Push(value V, priority P) {
+func (h *DaryHeap[V, P]) Push(value V, priority P) { h.push(value, priority, nil) }
+
+// push inserts a new element, attaching handle to it if handle is not nil.
+func (h *DaryHeap[V, P]) push(value V, priority P, handle *DaryHandle) {
 	h.data = append(h.data, h.getNewNode(value, priority))
+	if h.handles != nil {
+		handle.attach(h.Length() - 1)
+		h.handles = append(h.handles, handle)
+	}
 	h.siftUp(h.Length() - 1)
+	h.stats.record(OpPush, 1, h.Length())
+	h.alarms.check(h.Length())
+	emitHeapEvent(h.events, EventPush, "", value, priority)
+}
+
+// PushAll inserts all of the given elements into the heap. When the batch is
+// large relative to the heap, the elements are appended and the whole slice is
+// re-heapified in O(n) instead of sifting each element up in O(log n).
+// Otherwise each element is sifted up, which is cheaper for small batches.
+func (h *DaryHeap[V, P]) PushAll(data []HeapNode[V, P]) {
+	start := h.Length()
+	h.data = slices.Grow(h.data, len(data))
+	for i := range data {
+		h.data = append(h.data, h.getNewNode(data[i].value, data[i].priority))
+	}
+	if h.handles != nil {
+		h.handles = append(h.handles, make([]*DaryHandle, len(data))...)
+	}
+
+	n := h.Length()
+	if len(data)*bits.Len(uint(n)) >= n {
+		h.heapify()
+		h.stats.rebalance()
+	} else {
+		for i := start; i < n; i++ {
+			h.siftUp(i)
+		}
+	}
+	h.stats.record(OpPush, len(data), n)
+	h.alarms.check(n)
+	for i := range data {
+		emitHeapEvent(h.events, EventPush, "", data[i].value, data[i].priority)
+	}
 }
 
 // siftUp moves the element at index i up the tree until the heap property is
@@ -131,11 +649,12 @@ func (h *DaryHeap[V, P]) Push(value V, priority P) {
 // priorities.
 func (h *DaryHeap[V, P]) siftUp(i int) {
 	for i > 0 {
-		parent := (i - 1) / h.d
-		if !h.cmp(h.data[i].priority, h.data[parent].priority) {
+		parent := h.parent(i)
+		if !h.before(h.data[i], h.data[parent]) {
 			break
 		}
 		h.swap(i, parent)
+		h.stats.swap()
 		i = parent
 	}
 }
@@ -146,21 +665,25 @@ func (h *DaryHeap[V, P]) siftUp(i int) {
 func (h *DaryHeap[V, P]) siftDown(i int) {
 	cur := i
 	n := h.Length()
-	for h.d*cur+1 < n {
-		left := h.d*cur + 1
-		right := min(left+h.d, n)
+	for {
+		left, step := h.children(cur)
+		if left >= n {
+			break
+		}
+		right := min(left+h.d*step, n)
 
 		swapIdx := left
-		for k := left + 1; k < right; k++ {
-			if h.cmp(h.data[k].priority, h.data[swapIdx].priority) {
+		for k := left + step; k < right; k += step {
+			if h.before(h.data[k], h.data[swapIdx]) {
 				swapIdx = k
 			}
 		}
 
-		if !h.cmp(h.data[swapIdx].priority, h.data[cur].priority) {
+		if !h.before(h.data[swapIdx], h.data[cur]) {
 			break
 		}
 		h.swap(swapIdx, cur)
+		h.stats.swap()
 		cur = swapIdx
 	}
 }
@@ -169,13 +692,25 @@ func (h *DaryHeap[V, P]) siftDown(i int) {
 // updated. It decides whether to sift up or down based on the element's priority
 // relative to its parent.
 func (h *DaryHeap[V, P]) restoreHeap(i int) {
-	if i > 0 && h.cmp(h.data[i].priority, h.data[(i-1)/h.d].priority) {
+	if i > 0 && h.before(h.data[i], h.data[h.parent(i)]) {
 		h.siftUp(i)
 	} else {
 		h.siftDown(i)
 	}
 }
 
+// At returns the value and priority of the element at index i of the
+// underlying array without removing it. Index 0 is the root; other indices
+// change whenever elements are swapped, so use a handle to follow an element.
+// Returns an error if the index is out of bounds.
+func (h *DaryHeap[V, P]) At(i int) (V, P, error) {
+	if i < 0 || i >= h.Length() {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &IndexOutOfBoundsError{Index: i, Length: h.Length()}
+	}
+	return h.data[i].value, h.data[i].priority, nil
+}
+
 // Update replaces the element at index i with a new value and priority.
 // It then restores the heap property by either sifting up (if the new priority
 // is more appropriate than its parent) or sifting down (if the new priority is
@@ -183,37 +718,39 @@ func (h *DaryHeap[V, P]) restoreHeap(i int) {
 // Returns an error if the index is out of bounds.
 func (h *DaryHeap[V, P]) Update(i int, value V, priority P) error {
 	if i < 0 || i >= h.Length() {
-		return ErrIndexOutOfBounds
+		return &IndexOutOfBoundsError{Index: i, Length: h.Length()}
 	}
 	element := h.getNewNode(value, priority)
 	h.data[i] = element
 	h.restoreHeap(i)
+	emitHeapEvent(h.events, EventUpdate, "", value, priority)
+	return nil
+}
+
+// Fix restores the heap order around the element at index i after its
+// priority has been changed in place, for instance through a pointer held by
+// the caller. It is equivalent to, but cheaper than, removing the element and
+// pushing it again. Swap callbacks can be used to keep track of indices.
+// Returns an error if the index is out of bounds.
+func (h *DaryHeap[V, P]) Fix(i int) error {
+	if i < 0 || i >= h.Length() {
+		return &IndexOutOfBoundsError{Index: i, Length: h.Length()}
+	}
+	h.restoreHeap(i)
 	return nil
 }
 
 // Remove deletes the element at index i from the heap and returns it.
 // The heap property is restored by replacing the removed element with the last
-// element and sifting it down to its appropriate position.
+// element and sifting that element up or down to its appropriate position.
 // Returns the removed element and an error if the index is out of bounds.
 func (h *DaryHeap[V, P]) Remove(i int) (V, P, error) {
 	if i < 0 || i >= h.Length() {
 		v, p := zeroValuePair[V, P]()
-		return v, p, ErrIndexOutOfBounds
-	}
-
-	removed := h.data[i]
-	h.data[i] = h.data[h.Length()-1]
-	h.data = h.data[:h.Length()-1]
-
-	idx := i
-	if i > 0 {
-		idx = i - 1
+		return v, p, &IndexOutOfBoundsError{Index: i, Length: h.Length()}
 	}
-
-	v, p := removed.value, removed.priority
-	h.restoreHeap(idx)
-	h.pool.Put(removed)
-	return v, p, nil
+	removed := h.removeAt(i)
+	return removed.value, removed.priority, nil
 }
 
 // PopPush atomically removes the root element and inserts a new element into
@@ -226,9 +763,13 @@ func (h *DaryHeap[V, P]) PopPush(value V, priority P) (V, P) {
 	element := h.getNewNode(value, priority)
 	removed := h.data[0]
 	h.data[0] = element
+	h.releaseHandle(0)
 	h.siftDown(0)
 	v, p := removed.value, removed.priority
-	h.pool.Put(removed)
+	h.stats.record(OpPop, 1, h.Length())
+	h.stats.record(OpPush, 1, h.Length())
+	emitHeapEvent(h.events, EventPop, "", v, p)
+	emitHeapEvent(h.events, EventPush, "", value, priority)
 	return v, p
 }
 
@@ -244,24 +785,39 @@ func (h *DaryHeap[V, P]) PushPop(value V, priority P) (V, P) {
 	element := h.getNewNode(value, priority)
 	removed := h.data[0]
 	h.data[0] = element
+	h.releaseHandle(0)
 	h.siftDown(0)
 	v, p := removed.value, removed.priority
-	h.pool.Put(removed)
+	h.stats.record(OpPush, 1, h.Length())
+	h.stats.record(OpPop, 1, h.Length())
+	emitHeapEvent(h.events, EventPush, "", value, priority)
+	emitHeapEvent(h.events, EventPop, "", v, p)
 	return v, p
 }
 
 // Clone creates a deep copy of the heap structure. The new heap preserves the
 // original size. If values or priorities are reference types, those reference
-// values are shared between the original and cloned heaps.
+// values are shared between the original and cloned heaps. The clone receives
+// its own copy of the swap callback registry.
 func (h *DaryHeap[V, P]) Clone() *DaryHeap[V, P] {
 	newData := make([]HeapNode[V, P], h.Length())
 	copy(newData, h.data)
-	callbacks := h.onSwap.getCallbacks()
+	var onSwap callbacks
+	if h.onSwap != nil {
+		onSwap = h.onSwap.clone()
+	}
 	return &DaryHeap[V, P]{
 		data:   newData,
 		cmp:    h.cmp,
-		onSwap: callbacks,
+		onSwap: onSwap,
+		hooked: h.hooked,
 		d:      h.d,
-		pool:   h.pool,
+		blocks: h.blocks,
+		alarms: h.alarms.clone(),
+		stats:  h.stats,
+		events: h.events.clone(),
+		stable: h.stable,
+		seq:    h.seq,
+		shrink: h.shrink,
 	}
 }
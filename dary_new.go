@@ -1,10 +1,21 @@
 package heapcraft
 
+import (
+	"fmt"
+	"io"
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
 // NewBinaryHeap creates a new binary heap (d=2) from the given data slice and
 // comparison function. The comparison function determines the heap order (min or
 // max). It is a convenience wrapper around NewDaryHeap with d=2.
+//
+// Deprecated: use NewBinaryHeapWithConfig, which takes a DaryHeapConfig in
+// place of the unused usePool flag.
 func NewBinaryHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
-	return NewDaryHeap(2, data, cmp, usePool)
+	return NewBinaryHeapWithConfig(data, cmp, DaryHeapConfig{})
 }
 
 // NewBinaryHeapCopy creates a new binary heap (d=2) from a copy of the given data
@@ -12,6 +23,9 @@ func NewBinaryHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, u
 // data before heapifying it, leaving the original data unchanged. The comparison
 // function determines the heap order (min or max). It is a convenience wrapper
 // around NewDaryHeapCopy with d=2.
+//
+// Deprecated: use NewBinaryHeapWithConfig with a copy of data, such as
+// slices.Clone(data).
 func NewBinaryHeapCopy[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
 	return NewDaryHeapCopy(2, data, cmp, usePool)
 }
@@ -19,76 +33,163 @@ func NewBinaryHeapCopy[V any, P any](data []HeapNode[V, P], cmp func(a, b P) boo
 // NewDaryHeapCopy creates a new d-ary heap from a copy of the provided data
 // slice. The comparison function determines the heap order (min or max). The
 // original data slice remains unchanged.
+//
+// Deprecated: use NewDaryHeapWithConfig with a copy of data, such as
+// slices.Clone(data).
 func NewDaryHeapCopy[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
 	heap := make([]HeapNode[V, P], len(data))
 	copy(heap, data)
-	return NewDaryHeap(d, heap, cmp, usePool)
+	return newDaryHeap(d, heap, cmp, DaryHeapConfig{})
 }
 
 // NewDaryHeap transforms the given slice of HeapNode into a valid d-ary heap
 // in-place. The comparison function determines the heap order (min or max).
 // Uses siftDown starting from the last parent toward the root to build the heap.
+// See NewDaryHeapWithConfig.
+//
+// Deprecated: use NewDaryHeapWithConfig, which takes a DaryHeapConfig in
+// place of the unused usePool flag.
 func NewDaryHeap[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
-	pool := newPool(usePool, func() HeapNode[V, P] {
-		return HeapNode[V, P]{}
-	})
+	return newDaryHeap(d, data, cmp, DaryHeapConfig{})
+}
 
-	callbacks := make(baseCallbacks, 0)
-	h := DaryHeap[V, P]{
-		data:   data,
-		cmp:    cmp,
-		onSwap: callbacks,
-		d:      d,
-		pool:   pool,
+// NewDaryHeapFromSlices creates a new d-ary heap from parallel slices of
+// values and priorities, pairing the elements at the same index. The heap's
+// array is built directly from the two slices and heapified in place, so no
+// intermediate slice of HeapNode is allocated, and values and priorities are
+// left unchanged. The heap is built with the options set in config, as by
+// NewDaryHeapWithConfig. Returns ErrLengthMismatch if the slices have
+// different lengths.
+func NewDaryHeapFromSlices[V any, P any](d int, values []V, priorities []P, cmp func(a, b P) bool, config DaryHeapConfig) (*DaryHeap[V, P], error) {
+	data, err := NodesFromPairs(values, priorities)
+	if err != nil {
+		return nil, err
 	}
+	return newDaryHeap(d, data, cmp, config), nil
+}
 
-	// Start sifting down from the last parent node toward the root.
-	start := (h.Length() - 2) / d
-	for i := start; i >= 0; i-- {
-		h.siftDown(i)
-	}
-	return &h
+// NewBinaryHeapFromSlices creates a new binary heap (d=2) from parallel slices
+// of values and priorities. It is a convenience wrapper around
+// NewDaryHeapFromSlices with d=2.
+func NewBinaryHeapFromSlices[V any, P any](values []V, priorities []P, cmp func(a, b P) bool, config DaryHeapConfig) (*DaryHeap[V, P], error) {
+	return NewDaryHeapFromSlices(2, values, priorities, cmp, config)
 }
 
-// nDary builds a heap of size n from the data slice.
-// It uses Push for the first n elements and PushPop for the remainder to
-// maintain a heap of exactly size n. This is used as the underlying
-// implementation for both NLargestDary and NSmallestDary.
-func nDary[V any, P any](n int, d int, data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
-	pool := newPool(usePool, func() HeapNode[V, P] {
-		return HeapNode[V, P]{}
-	})
+// NewDaryHeapOwned creates a new d-ary heap that takes ownership of data,
+// heapifying it in-place and using it as the heap's array without copying.
+// The caller must not read or write data afterwards: the heap reorders it,
+// grows it and zeroes removed slots. It behaves exactly like
+// NewDaryHeapWithConfig and exists to make the transfer of ownership explicit
+// at the call site; pass a copy of data to keep the original slice, and use
+// Data to read the elements back.
+func NewDaryHeapOwned[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(d, data, cmp, config)
+}
 
-	callbacks := make(baseCallbacks, 0)
-	heap := DaryHeap[V, P]{
-		data:   make([]HeapNode[V, P], 0, n),
+// NewBinaryHeapOwned creates a new binary heap (d=2) that takes ownership of
+// data. It is a convenience wrapper around NewDaryHeapOwned with d=2.
+func NewBinaryHeapOwned[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return NewDaryHeapOwned(2, data, cmp, config)
+}
+
+// NewStableDaryHeap transforms the given slice of HeapNode into a valid d-ary
+// heap in-place, like NewDaryHeapWithConfig, but breaks ties between equal
+// priorities by insertion order so that they pop first-in, first-out, as if
+// config.Stable were set. Elements of data are treated as inserted in slice
+// order. Update counts as a fresh insertion, and elements restored by Restore
+// or UnmarshalJSON are sequenced in the order they are read.
+func NewStableDaryHeap[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	config.Stable = true
+	return newDaryHeap(d, data, cmp, config)
+}
+
+// NewStableBinaryHeap creates a new stable binary heap (d=2) from the given
+// data slice. It is a convenience wrapper around NewStableDaryHeap with d=2.
+func NewStableBinaryHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return NewStableDaryHeap(2, data, cmp, config)
+}
+
+// NewDaryHeapWithConfig transforms the given slice of HeapNode into a valid
+// d-ary heap in-place, with the options set in config. The comparison
+// function determines the heap order (min or max). The heap adopts data as
+// its array, like NewDaryHeapOwned: data is reordered immediately and
+// overwritten by later operations, so callers that still need it should pass
+// a copy. Elements are stored by value in the array, so Push and Pop do not
+// allocate once the array has grown. Setting DisableCallbacks leaves out the
+// swap callback registry for heaps that never need one. Like every d-ary
+// constructor, it panics with ErrInvalidArity if d is less than 1. A heap
+// with d == 1 is a sorted list, so Push and Pop take O(n).
+func NewDaryHeapWithConfig[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(d, data, cmp, config)
+}
+
+// NewBinaryHeapWithConfig creates a new binary heap (d=2) from the given data
+// slice with the options set in config. It is a convenience wrapper around
+// NewDaryHeapWithConfig with d=2.
+func NewBinaryHeapWithConfig[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(2, data, cmp, config)
+}
+
+// NewAutoDaryHeap transforms data into a d-ary heap in-place, like NewDaryHeap,
+// with the arity RecommendArity picks for profile, and reserves room for
+// profile.ExpectedSize elements.
+func NewAutoDaryHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, profile WorkloadProfile) *DaryHeap[V, P] {
+	return newDaryHeap(RecommendArity(profile), data, cmp, DaryHeapConfig{Capacity: profile.ExpectedSize})
+}
+
+// newDaryHeap builds a d-ary heap over data in-place. When config.Stable is
+// true, each element is numbered in slice order before heapifying so that
+// equal priorities keep that order. Every d-ary constructor goes through it,
+// so it is where an arity below 1 is rejected.
+func newDaryHeap[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	if d < 1 {
+		panic(fmt.Errorf("%w: got %d", ErrInvalidArity, d))
+	}
+	h := DaryHeap[V, P]{
+		data:   data,
 		cmp:    cmp,
-		onSwap: callbacks,
 		d:      d,
-		pool:   pool,
+		blocks: newBlockLayout(d, config.BlockLevels),
+		stable: config.Stable,
+		shrink: config.AutoShrink,
 	}
-	i := 0
-	m := len(data)
-	minNum := min(n, m)
-
-	// Build initial heap with the first min(n, m) elements.
-	for ; i < minNum; i++ {
-		element := data[i]
-		heap.Push(element.value, element.priority)
+	if !config.DisableCallbacks {
+		h.onSwap = make(baseCallbacks, 0)
 	}
-
-	// For remaining elements, use PushPop to keep the heap size at n.
-	for ; i < m; i++ {
-		element := data[i]
-		heap.PushPop(element.value, element.priority)
+	if h.stable {
+		for i := range h.data {
+			h.seq++
+			h.data[i].seq = h.seq
+		}
 	}
-	return &heap
+
+	// Sift down every parent node, from the last toward the root.
+	h.heapify()
+	h.Reserve(config.Capacity - h.Length())
+	return &h
+}
+
+// nDary builds a heap of size n from the data slice, holding the n elements
+// that cmp would pop last. The elements are chosen with SelectK over a copy of
+// data, so the original slice is left unchanged, and the result is heapified
+// in place. This is used as the underlying implementation for both
+// NLargestDary and NSmallestDary; SelectKSeq covers inputs that are only
+// available as a stream.
+func nDary[V any, P any](n int, d int, data []HeapNode[V, P], cmp func(a, b P) bool) *DaryHeap[V, P] {
+	copied := make([]HeapNode[V, P], len(data))
+	copy(copied, data)
+	selected := SelectK(n, copied, Reverse(cmp))
+
+	heap := make([]HeapNode[V, P], len(selected), max(n, len(selected)))
+	copy(heap, selected)
+	return newDaryHeap(d, heap, cmp, DaryHeapConfig{})
 }
 
 // NLargestDary returns a min-heap of size n containing the n largest
 // elements from data. The comparison function lt should return true if a < b.
+// usePool has no effect, since a d-ary heap has no nodes to pool.
 func NLargestDary[V any, P any](n int, d int, data []HeapNode[V, P], lt func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
-	return nDary(n, d, data, lt, usePool)
+	return nDary(n, d, data, lt)
 }
 
 // NLargestBinary returns a min-heap of size n containing the n largest
@@ -101,8 +202,9 @@ func NLargestBinary[V any, P any](n int, data []HeapNode[V, P], lt func(a, b P)
 
 // NSmallestDary returns a max-heap of size n containing the n smallest
 // elements from data. The comparison function gt should return true if a > b.
+// usePool has no effect, since a d-ary heap has no nodes to pool.
 func NSmallestDary[V any, P any](n int, d int, data []HeapNode[V, P], gt func(a, b P) bool, usePool bool) *DaryHeap[V, P] {
-	return nDary(n, d, data, gt, usePool)
+	return nDary(n, d, data, gt)
 }
 
 // NSmallestBinary returns a max-heap of size n containing the n smallest
@@ -113,17 +215,66 @@ func NSmallestBinary[V any, P any](n int, data []HeapNode[V, P], gt func(a, b P)
 	return NSmallestDary(n, 2, data, gt, usePool)
 }
 
+// NLargestFromSeq returns a min-heap of size n containing the n largest
+// elements produced by seq, like NLargestDary, for inputs that are generated
+// or read incrementally and never held in memory at once. It keeps only the
+// best n elements seen so far, in O(n) memory, using SelectKSeq. The
+// comparison function lt should return true if a < b, and config sets the
+// options of the returned heap.
+func NLargestFromSeq[V any, P any](n int, d int, seq iter.Seq2[V, P], lt func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(d, SelectKSeq(n, seq, Reverse(lt)), lt, config)
+}
+
+// NSmallestFromSeq returns a max-heap of size n containing the n smallest
+// elements produced by seq. See NLargestFromSeq. The comparison function gt
+// should return true if a > b.
+func NSmallestFromSeq[V any, P any](n int, d int, seq iter.Seq2[V, P], gt func(a, b P) bool, config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(d, SelectKSeq(n, seq, Reverse(gt)), gt, config)
+}
+
+// NLargestFromReader returns a min-heap of size n containing the n largest
+// elements read from r, which holds JSON objects with "value" and "priority"
+// fields, either as one JSON array such as StreamJSON writes or as a sequence
+// of objects such as JSON Lines. Elements are decoded one at a time, so r can
+// be far larger than memory. Returns the first error from reading or decoding
+// r.
+func NLargestFromReader[V any, P any](n int, d int, r io.Reader, lt func(a, b P) bool, config DaryHeapConfig) (*DaryHeap[V, P], error) {
+	var err error
+	heap := NLargestFromSeq(n, d, decodeNodes[V, P](r, &err), lt, config)
+	if err != nil {
+		return nil, err
+	}
+	return heap, nil
+}
+
+// NSmallestFromReader returns a max-heap of size n containing the n smallest
+// elements read from r. See NLargestFromReader.
+func NSmallestFromReader[V any, P any](n int, d int, r io.Reader, gt func(a, b P) bool, config DaryHeapConfig) (*DaryHeap[V, P], error) {
+	var err error
+	heap := NSmallestFromSeq(n, d, decodeNodes[V, P](r, &err), gt, config)
+	if err != nil {
+		return nil, err
+	}
+	return heap, nil
+}
+
 // NewSyncBinaryHeap creates a new thread-safe binary heap (d=2) from the given
 // data slice and comparison function. The comparison function determines the
 // heap order (min or max).
+//
+// Deprecated: use NewSyncBinaryHeapWithConfig, which takes a DaryHeapConfig
+// in place of the unused usePool flag.
 func NewSyncBinaryHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncDaryHeap[V, P] {
-	return NewSyncDaryHeap(2, data, cmp, usePool)
+	return NewSyncBinaryHeapWithConfig(data, cmp, DaryHeapConfig{})
 }
 
 // NewSyncBinaryHeapCopy creates a new thread-safe binary heap (d=2) from a copy
 // of the given data slice. Unlike NewSyncBinaryHeap, this function creates a
 // new slice and copies the data before heapifying it, leaving the original data
 // unchanged.
+//
+// Deprecated: use NewSyncBinaryHeapWithConfig with a copy of data, such as
+// slices.Clone(data).
 func NewSyncBinaryHeapCopy[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncDaryHeap[V, P] {
 	return NewSyncDaryHeapCopy(2, data, cmp, usePool)
 }
@@ -131,17 +282,105 @@ func NewSyncBinaryHeapCopy[V any, P any](data []HeapNode[V, P], cmp func(a, b P)
 // NewSyncDaryHeapCopy creates a new thread-safe d-ary heap from a copy of the
 // provided data slice. The comparison function determines the heap order (min or
 // max). The original data slice remains unchanged.
+//
+// Deprecated: use NewSyncDaryHeapWithConfig with a copy of data, such as
+// slices.Clone(data).
 func NewSyncDaryHeapCopy[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncDaryHeap[V, P] {
 	heap := NewDaryHeapCopy(d, data, cmp, usePool)
 	heap.onSwap = NewSyncCallbacks()
 	return &SyncDaryHeap[V, P]{heap: heap}
 }
 
+// NewSyncDaryHeapFromSlices creates a new thread-safe d-ary heap from
+// parallel slices of values and priorities. See NewDaryHeapFromSlices.
+func NewSyncDaryHeapFromSlices[V any, P any](d int, values []V, priorities []P, cmp func(a, b P) bool, config DaryHeapConfig) (*SyncDaryHeap[V, P], error) {
+	heap, err := NewDaryHeapFromSlices(d, values, priorities, cmp, config)
+	if err != nil {
+		return nil, err
+	}
+	if !config.DisableCallbacks {
+		heap.onSwap = NewSyncCallbacks()
+	}
+	return &SyncDaryHeap[V, P]{heap: heap}, nil
+}
+
+// NewSyncBinaryHeapFromSlices creates a new thread-safe binary heap (d=2) from
+// parallel slices of values and priorities. See NewDaryHeapFromSlices.
+func NewSyncBinaryHeapFromSlices[V any, P any](values []V, priorities []P, cmp func(a, b P) bool, config DaryHeapConfig) (*SyncDaryHeap[V, P], error) {
+	return NewSyncDaryHeapFromSlices(2, values, priorities, cmp, config)
+}
+
+// NewSyncDaryHeapOwned creates a new thread-safe d-ary heap that takes
+// ownership of data. See NewDaryHeapOwned.
+func NewSyncDaryHeapOwned[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *SyncDaryHeap[V, P] {
+	return NewSyncDaryHeapWithConfig(d, data, cmp, config)
+}
+
+// NewSyncStableDaryHeap creates a new thread-safe d-ary heap that pops equal
+// priorities in insertion order. See NewStableDaryHeap.
+func NewSyncStableDaryHeap[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *SyncDaryHeap[V, P] {
+	heap := NewStableDaryHeap(d, data, cmp, config)
+	if !config.DisableCallbacks {
+		heap.onSwap = NewSyncCallbacks()
+	}
+	return &SyncDaryHeap[V, P]{heap: heap}
+}
+
 // NewSyncDaryHeap creates a new thread-safe d-ary heap from the given data
 // slice and comparison function. The comparison function determines the heap
 // order (min or max).
+//
+// Deprecated: use NewSyncDaryHeapWithConfig, which takes a DaryHeapConfig in
+// place of the unused usePool flag.
 func NewSyncDaryHeap[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncDaryHeap[V, P] {
-	heap := NewDaryHeap(d, data, cmp, usePool)
+	return NewSyncDaryHeapWithConfig(d, data, cmp, DaryHeapConfig{})
+}
+
+// NewSyncAutoDaryHeap creates a new thread-safe d-ary heap with the arity
+// RecommendArity picks for profile. See NewAutoDaryHeap.
+func NewSyncAutoDaryHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, profile WorkloadProfile) *SyncDaryHeap[V, P] {
+	heap := NewAutoDaryHeap(data, cmp, profile)
 	heap.onSwap = NewSyncCallbacks()
 	return &SyncDaryHeap[V, P]{heap: heap}
 }
+
+// NewSyncDaryHeapWithConfig creates a new thread-safe d-ary heap from the
+// given data slice with the options set in config. See NewDaryHeapWithConfig.
+func NewSyncDaryHeapWithConfig[V any, P any](d int, data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *SyncDaryHeap[V, P] {
+	heap := NewDaryHeapWithConfig(d, data, cmp, config)
+	if !config.DisableCallbacks {
+		heap.onSwap = NewSyncCallbacks()
+	}
+	return &SyncDaryHeap[V, P]{heap: heap}
+}
+
+// NewSyncBinaryHeapWithConfig creates a new thread-safe binary heap (d=2) from
+// the given data slice with the options set in config. See
+// NewBinaryHeapWithConfig.
+func NewSyncBinaryHeapWithConfig[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config DaryHeapConfig) *SyncDaryHeap[V, P] {
+	return NewSyncDaryHeapWithConfig(2, data, cmp, config)
+}
+
+// NewMinDaryHeap creates a d-ary min-heap over data in-place, ordered by the
+// natural ordering of P, so the smallest priority is popped first.
+func NewMinDaryHeap[V any, P constraints.Ordered](d int, data []HeapNode[V, P], config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(d, data, orderedLess[P], config)
+}
+
+// NewMaxDaryHeap creates a d-ary max-heap over data in-place, ordered by the
+// natural ordering of P, so the largest priority is popped first.
+func NewMaxDaryHeap[V any, P constraints.Ordered](d int, data []HeapNode[V, P], config DaryHeapConfig) *DaryHeap[V, P] {
+	return newDaryHeap(d, data, orderedGreater[P], config)
+}
+
+// NewMinBinaryHeap creates a binary min-heap (d=2) over data in-place. It is a
+// convenience wrapper around NewMinDaryHeap with d=2.
+func NewMinBinaryHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config DaryHeapConfig) *DaryHeap[V, P] {
+	return NewMinDaryHeap(2, data, config)
+}
+
+// NewMaxBinaryHeap creates a binary max-heap (d=2) over data in-place. It is a
+// convenience wrapper around NewMaxDaryHeap with d=2.
+func NewMaxBinaryHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config DaryHeapConfig) *DaryHeap[V, P] {
+	return NewMaxDaryHeap(2, data, config)
+}
@@ -1,6 +1,9 @@
 package heapcraft
 
 import (
+	"context"
+	"io"
+	"iter"
 	"sync"
 )
 
@@ -14,6 +17,8 @@ type SyncDaryHeap[V any, P any] struct {
 // Deregister removes the callback with the specified ID from the heap's swap
 // callbacks. Returns an error if no callback exists with the given ID.
 func (h *SyncDaryHeap[V, P]) Deregister(id string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
 	return h.heap.Deregister(id)
 }
 
@@ -21,6 +26,8 @@ func (h *SyncDaryHeap[V, P]) Deregister(id string) error {
 // swap positions. Returns a callback that can be used to deregister the
 // function later.
 func (h *SyncDaryHeap[V, P]) Register(fn func(x, y int)) callback {
+	h.lock.Lock()
+	defer h.lock.Unlock()
 	return h.heap.Register(fn)
 }
 
@@ -32,6 +39,48 @@ func (h *SyncDaryHeap[V, P]) Clear() {
 	h.heap.Clear()
 }
 
+// Reset removes all elements from the heap while keeping the capacity of its
+// underlying slice.
+func (h *SyncDaryHeap[V, P]) Reset() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Reset()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (h *SyncDaryHeap[V, P]) ApproxMemoryUsage() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.ApproxMemoryUsage()
+}
+
+// Maintain performs pending upkeep on the heap. If the underlying slice has
+// more than twice the capacity needed for its elements, it is reallocated to
+// fit so the excess memory can be reclaimed. Returns the context error if ctx
+// is already done.
+func (h *SyncDaryHeap[V, P]) Maintain(ctx context.Context) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Maintain(ctx)
+}
+
+// Reserve grows the underlying slice so that at least n more elements can be
+// pushed without reallocating it.
+func (h *SyncDaryHeap[V, P]) Reserve(n int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Reserve(n)
+}
+
+// Compact reallocates the underlying slice to fit the heap's elements exactly,
+// releasing any capacity left over from earlier growth.
+func (h *SyncDaryHeap[V, P]) Compact() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Compact()
+}
+
 // Length returns the current number of elements in the heap.
 func (h *SyncDaryHeap[V, P]) Length() int {
 	h.lock.RLock()
@@ -39,6 +88,58 @@ func (h *SyncDaryHeap[V, P]) Length() int {
 	return h.heap.Length()
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (h *SyncDaryHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (h *SyncDaryHeap[V, P]) RemoveDepthAlarm(id string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (h *SyncDaryHeap[V, P]) Stats() HeapStats {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (h *SyncDaryHeap[V, P]) Instrument(hook Instrumentation) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (h *SyncDaryHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *SyncDaryHeap[V, P]) RemoveListener(id string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RemoveListener(id)
+}
+
 // IsEmpty returns true if the heap contains no elements.
 func (h *SyncDaryHeap[V, P]) IsEmpty() bool {
 	h.lock.RLock()
@@ -46,6 +147,14 @@ func (h *SyncDaryHeap[V, P]) IsEmpty() bool {
 	return h.heap.IsEmpty()
 }
 
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (h *SyncDaryHeap[V, P]) Verify() error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Verify()
+}
+
 // Pop removes and returns the root element of the heap (minimum or maximum per
 // cmp). If the heap is empty, returns a zero value and priority with an error.
 func (h *SyncDaryHeap[V, P]) Pop() (V, P, error) {
@@ -54,6 +163,34 @@ func (h *SyncDaryHeap[V, P]) Pop() (V, P, error) {
 	return h.heap.Pop()
 }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (h *SyncDaryHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopIf(pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single write
+// lock. pred must not call back into the heap.
+func (h *SyncDaryHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopWhile(pred)
+}
+
+// CountAtOrBelow returns the number of elements whose priority does not come
+// after p according to cmp, visiting only those elements and their children.
+func (h *SyncDaryHeap[V, P]) CountAtOrBelow(p P) int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.CountAtOrBelow(p)
+}
+
 // Peek returns the root HeapNode without removing it.
 // If the heap is empty, returns a zero value and priority with an error.
 func (h *SyncDaryHeap[V, P]) Peek() (V, P, error) {
@@ -78,6 +215,91 @@ func (h *SyncDaryHeap[V, P]) PopPriority() (P, error) {
 	return h.heap.PopPriority()
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (h *SyncDaryHeap[V, P]) Drain() []HeapNode[V, P] {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (h *SyncDaryHeap[V, P]) DrainValues() []V {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (h *SyncDaryHeap[V, P]) DrainPriorities() []P {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (h *SyncDaryHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Export(opts)
+}
+
+// Data returns a read-only view of the heap's array in storage order. The
+// iterator holds a read lock while it runs, so the loop body must not modify
+// the heap.
+func (h *SyncDaryHeap[V, P]) Data() iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		h.lock.RLock()
+		defer h.lock.RUnlock()
+		h.heap.Data()(yield)
+	}
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, in the same format as the underlying heap. It holds a read lock
+// until the whole array has been written.
+func (h *SyncDaryHeap[V, P]) StreamJSON(w io.Writer) error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.StreamJSON(w)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (h *SyncDaryHeap[V, P]) MarshalJSON() ([]byte, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (h *SyncDaryHeap[V, P]) UnmarshalJSON(data []byte) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (h *SyncDaryHeap[V, P]) Snapshot() ([]byte, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (h *SyncDaryHeap[V, P]) Restore(data []byte) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Restore(data)
+}
+
 // PeekValue returns just the value of the root element without removing it.
 // If the heap is empty, returns a zero value with an error.
 func (h *SyncDaryHeap[V, P]) PeekValue() (V, error) {
@@ -102,6 +324,14 @@ func (h *SyncDaryHeap[V, P]) Push(value V, priority P) {
 	h.heap.Push(value, priority)
 }
 
+// PushAll inserts all of the given elements into the heap under a single
+// write lock.
+func (h *SyncDaryHeap[V, P]) PushAll(data []HeapNode[V, P]) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.PushAll(data)
+}
+
 // Update replaces the element at index i with a new value and priority.
 // It then restores the heap property by either sifting up (if the new priority
 // is more appropriate than its parent) or sifting down (if the new priority is
@@ -113,6 +343,15 @@ func (h *SyncDaryHeap[V, P]) Update(i int, value V, priority P) error {
 	return h.heap.Update(i, value, priority)
 }
 
+// Fix restores the heap order around the element at index i after its
+// priority has been changed in place. Returns an error if the index is out of
+// bounds.
+func (h *SyncDaryHeap[V, P]) Fix(i int) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Fix(i)
+}
+
 // Remove deletes the element at index i from the heap and returns it.
 // The heap property is restored by replacing the removed element with the last
 // element and sifting it down to its appropriate position.
@@ -123,6 +362,63 @@ func (h *SyncDaryHeap[V, P]) Remove(i int) (V, P, error) {
 	return h.heap.Remove(i)
 }
 
+// At returns the value and priority of the element at index i of the
+// underlying array without removing it. Returns an error if the index is out
+// of bounds.
+func (h *SyncDaryHeap[V, P]) At(i int) (V, P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.At(i)
+}
+
+// PushHandle inserts a new element and returns a handle that follows it
+// through later operations.
+func (h *SyncDaryHeap[V, P]) PushHandle(value V, priority P) *DaryHandle {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PushHandle(value, priority)
+}
+
+// HandleAt returns a handle for the element currently at index i. Returns an
+// error if the index is out of bounds.
+func (h *SyncDaryHeap[V, P]) HandleAt(i int) (*DaryHandle, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.HandleAt(i)
+}
+
+// IndexOf returns the current index of the element referred to by handle.
+// Returns ErrStaleHandle if the element has left the heap.
+func (h *SyncDaryHeap[V, P]) IndexOf(handle *DaryHandle) (int, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.IndexOf(handle)
+}
+
+// GetByHandle returns the value and priority of the element referred to by
+// handle. Returns ErrStaleHandle if the element has left the heap.
+func (h *SyncDaryHeap[V, P]) GetByHandle(handle *DaryHandle) (V, P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.GetByHandle(handle)
+}
+
+// UpdateByHandle replaces the value and priority of the element referred to by
+// handle. Returns ErrStaleHandle if the element has left the heap.
+func (h *SyncDaryHeap[V, P]) UpdateByHandle(handle *DaryHandle, value V, priority P) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.UpdateByHandle(handle, value, priority)
+}
+
+// RemoveByHandle removes the element referred to by handle and returns it.
+// Returns ErrStaleHandle if the element has already left the heap.
+func (h *SyncDaryHeap[V, P]) RemoveByHandle(handle *DaryHandle) (V, P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RemoveByHandle(handle)
+}
+
 // PopPush atomically removes the root element and inserts a new element into the heap.
 // Returns the removed root element.
 func (h *SyncDaryHeap[V, P]) PopPush(value V, priority P) (V, P) {
@@ -143,11 +439,10 @@ func (h *SyncDaryHeap[V, P]) PushPop(value V, priority P) (V, P) {
 
 // Clone creates a deep copy of the heap structure. The new heap preserves the
 // original size. If values or priorities are reference types, those reference
-// values are shared between the original and cloned heaps.
+// values are shared between the original and cloned heaps. The clone's swap
+// callback registry is an independent, thread-safe copy of this heap's.
 func (h *SyncDaryHeap[V, P]) Clone() *SyncDaryHeap[V, P] {
 	h.lock.RLock()
 	defer h.lock.RUnlock()
-	clonedHeap := h.heap.Clone()
-	clonedHeap.onSwap = &syncCallbacks{callbacks: clonedHeap.onSwap.(baseCallbacks)}
-	return &SyncDaryHeap[V, P]{heap: clonedHeap}
+	return &SyncDaryHeap[V, P]{heap: h.heap.Clone()}
 }
@@ -1,7 +1,9 @@
 package heapcraft
 
 import (
+	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -134,10 +136,10 @@ func TestSyncDaryHeapUpdateAndRemove(t *testing.T) {
 
 	// Test error cases
 	err = heap.Update(10, 1, 1)
-	assert.Equal(t, ErrIndexOutOfBounds, err)
+	assert.ErrorIs(t, err, ErrIndexOutOfBounds)
 
 	_, _, err = heap.Remove(10)
-	assert.Equal(t, ErrIndexOutOfBounds, err)
+	assert.ErrorIs(t, err, ErrIndexOutOfBounds)
 }
 
 // TestSyncDaryHeapPopPushAndPushPop tests PopPush and PushPop operations.
@@ -220,6 +222,75 @@ func TestSyncDaryHeapClone(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestSyncDaryHeapCloneOfClone verifies that every generation of clones owns
+// an independent, thread-safe callback registry.
+func TestSyncDaryHeapCloneOfClone(t *testing.T) {
+	original := NewSyncBinaryHeap[int, int](nil, lt, false)
+	var originalSwaps, cloneSwaps atomic.Int64
+	original.Register(func(x, y int) { originalSwaps.Add(1) })
+
+	clone := original.Clone()
+	grandchild := clone.Clone()
+	for _, heap := range []*SyncDaryHeap[int, int]{original, clone, grandchild} {
+		assert.IsType(t, &syncCallbacks{}, heap.heap.onSwap)
+	}
+	assert.NotSame(t, original.heap.onSwap, clone.heap.onSwap)
+	assert.NotSame(t, clone.heap.onSwap, grandchild.heap.onSwap)
+
+	// Cloning the wrapped heap directly keeps the registry thread-safe too.
+	assert.IsType(t, &syncCallbacks{}, original.heap.Clone().onSwap)
+	assert.IsType(t, baseCallbacks{}, NewBinaryHeap[int, int](nil, lt, false).Clone().onSwap)
+
+	// Callbacks registered on a clone only observe swaps in that clone.
+	onlyClone := clone.Register(func(x, y int) { cloneSwaps.Add(1) })
+	assert.ErrorIs(t, original.Deregister(onlyClone.ID), ErrCallbackNotFound)
+	assert.ErrorIs(t, grandchild.Deregister(onlyClone.ID), ErrCallbackNotFound)
+
+	for _, heap := range []*SyncDaryHeap[int, int]{original, clone, grandchild} {
+		heap.Push(2, 2)
+		heap.Push(1, 1)
+	}
+	assert.Equal(t, int64(3), originalSwaps.Load())
+	assert.Equal(t, int64(1), cloneSwaps.Load())
+}
+
+// TestSyncDaryHeapConcurrentClone clones heaps and clones of clones while
+// other goroutines push, pop and register callbacks on the same heaps. It is
+// meant to be run with the race detector.
+func TestSyncDaryHeapConcurrentClone(t *testing.T) {
+	original := NewSyncDaryHeap[int, int](3, nil, lt, true)
+	original.Register(func(x, y int) {})
+
+	var wg sync.WaitGroup
+	clones := make(chan *SyncDaryHeap[int, int], 64)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			heap := original
+			for i := 0; i < 50; i++ {
+				heap.Push(g*100+i, i)
+				callback := heap.Register(func(x, y int) {})
+				if i%10 == 0 {
+					heap = heap.Clone()
+					clones <- heap
+				}
+				heap.Pop()
+				assert.Nil(t, heap.Deregister(callback.ID))
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(clones)
+
+	for clone := range clones {
+		assert.IsType(t, &syncCallbacks{}, clone.heap.onSwap)
+		assert.GreaterOrEqual(t, clone.heap.onSwap.count(), 1)
+		values := clone.DrainPriorities()
+		assert.True(t, sort.IntsAreSorted(values))
+	}
+}
+
 // TestSyncDaryHeapStress tests stress conditions with many concurrent operations.
 func TestSyncDaryHeapStress(t *testing.T) {
 	heap := NewSyncBinaryHeap([]HeapNode[int, int]{}, lt, false)
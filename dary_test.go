@@ -2,9 +2,12 @@ package heapcraft
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // lt returns true if a is less than b
@@ -282,6 +285,50 @@ func TestRegisterDeregisterCallbacksDary(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDaryHeap_DisableCallbacks(t *testing.T) {
+	config := DaryHeapConfig{Stable: true, DisableCallbacks: true}
+	h := NewDaryHeapWithConfig(2, []HeapNode[string, int]{
+		CreateHeapNode("b", 2),
+		CreateHeapNode("a", 1),
+		CreateHeapNode("c", 1),
+	}, lt, config)
+	assert.Panics(t, func() { h.Register(func(x, y int) {}) })
+	assert.ErrorIs(t, h.Deregister("missing"), ErrCallbacksDisabled)
+
+	h.Push("d", 0)
+	clone := h.Clone()
+	assert.Equal(t, []string{"d", "a", "c", "b"}, h.DrainValues())
+	assert.Equal(t, []string{"d", "a", "c", "b"}, clone.DrainValues())
+
+	syncHeap := NewSyncDaryHeapWithConfig[int, int](4, nil, lt, config)
+	syncHeap.Push(1, 1)
+	assert.ErrorIs(t, syncHeap.Deregister("missing"), ErrCallbacksDisabled)
+
+	var swaps int
+	hooked := NewSyncDaryHeapWithConfig[int, int](4, nil, lt, DaryHeapConfig{})
+	cb := hooked.Register(func(x, y int) { swaps++ })
+	hooked.Push(2, 2)
+	hooked.Push(1, 1)
+	assert.Equal(t, 1, swaps)
+	require.NoError(t, hooked.Deregister(cb.ID))
+	hooked.Push(0, 0)
+	assert.Equal(t, 1, swaps)
+}
+
+func TestDaryHeap_PushPopDoesNotAllocate(t *testing.T) {
+	for _, usePool := range []bool{false, true} {
+		h := NewDaryHeap[int, int](4, nil, lt, usePool)
+		for i := 0; i < 100; i++ {
+			h.Push(i, i)
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			h.Push(50, 50)
+			h.Pop()
+		})
+		assert.Zero(t, allocs, "usePool=%t", usePool)
+	}
+}
+
 func TestPeekPopEmptyDary(t *testing.T) {
 	h := DaryHeap[string, int]{data: []HeapNode[string, int]{}, cmp: lt, d: 2}
 	_, _, err := h.Peek()
@@ -412,6 +459,84 @@ func BenchmarkBinaryPopPush(b *testing.B) {
 	}
 }
 
+func BenchmarkDaryHeap_SwapCallbacks(b *testing.B) {
+	heaps := []struct {
+		name string
+		heap func() *DaryHeap[int, int]
+	}{
+		{"disabled", func() *DaryHeap[int, int] {
+			return NewDaryHeapWithConfig[int, int](4, nil, lt, DaryHeapConfig{DisableCallbacks: true})
+		}},
+		{"unused", func() *DaryHeap[int, int] { return NewDaryHeap[int, int](4, nil, lt, false) }},
+		{"unusedSync", func() *DaryHeap[int, int] { return NewSyncDaryHeap[int, int](4, nil, lt, false).heap }},
+		{"registered", func() *DaryHeap[int, int] {
+			h := NewDaryHeap[int, int](4, nil, lt, false)
+			h.Register(func(x, y int) {})
+			return h
+		}},
+		{"registeredSync", func() *DaryHeap[int, int] {
+			h := NewSyncDaryHeap[int, int](4, nil, lt, false).heap
+			h.Register(func(x, y int) {})
+			return h
+		}},
+	}
+	for _, bench := range heaps {
+		b.Run(bench.name, func(b *testing.B) {
+			heap := bench.heap()
+			insertions := generateRandomNumbersv1(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				heap.Push(insertions[i], insertions[i])
+			}
+			for i := 0; i < b.N; i++ {
+				heap.Pop()
+			}
+		})
+	}
+}
+
+func BenchmarkDaryHeap_PushPopAllocs(b *testing.B) {
+	for _, usePool := range []bool{false, true} {
+		b.Run(fmt.Sprintf("usePool=%t", usePool), func(b *testing.B) {
+			heap := NewDaryHeap[int, int](4, nil, lt, usePool)
+			for i := 0; i < 1000; i++ {
+				heap.Push(i, i)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				heap.Push(i, i)
+				heap.Pop()
+			}
+		})
+	}
+}
+
+// BenchmarkDaryHeap_BlockLayout pushes and pops random priorities on a heap
+// of a few million elements, large enough to outgrow the CPU caches, in the
+// implicit and blocked layouts.
+func BenchmarkDaryHeap_BlockLayout(b *testing.B) {
+	const size = 1 << 22
+	for _, levels := range []int{0, 3, 4} {
+		b.Run(fmt.Sprintf("levels=%d", levels), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(42))
+			data := make([]HeapNode[int, int], size)
+			for i := range data {
+				p := rng.Int()
+				data[i] = CreateHeapNode(p, p)
+			}
+			heap := NewDaryHeapWithConfig(4, data, lt, DaryHeapConfig{BlockLevels: levels})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p := rng.Int()
+				heap.Push(p, p)
+				heap.Pop()
+			}
+		})
+	}
+}
+
 // -------------------------------- D-ary Heap Benchmarks (d=3) --------------------------------
 
 func BenchmarkDaryHeap3Insertion(b *testing.B) {
@@ -527,3 +652,387 @@ func BenchmarkDaryHeap4PopPush(b *testing.B) {
 		heap.PopPush(insertions[i], insertions[i])
 	}
 }
+
+func TestDaryHeap_Fix(t *testing.T) {
+	type job struct{ priority int }
+	less := func(a, b *job) bool { return a.priority < b.priority }
+
+	for _, d := range []int{2, 3, 4} {
+		jobs := make([]*job, 30)
+		h := NewDaryHeap[int, *job](d, nil, less, false)
+		for i := range jobs {
+			jobs[i] = &job{priority: (i * 7) % 30}
+			h.Push(i, jobs[i])
+		}
+
+		// Mutate priorities through the shared pointers, moving some elements
+		// up and others down, and repair each one.
+		for i := 0; i < h.Length(); i++ {
+			node := h.data[i]
+			node.priority.priority = (node.priority.priority*13 + 5) % 50
+			assert.NoError(t, h.Fix(i))
+		}
+
+		last := -1
+		for !h.IsEmpty() {
+			_, p, _ := h.Pop()
+			assert.LessOrEqual(t, last, p.priority)
+			last = p.priority
+		}
+	}
+
+	h := NewBinaryHeap[int, int](nil, lt, false)
+	assert.ErrorIs(t, h.Fix(0), ErrIndexOutOfBounds)
+	syncHeap := NewSyncBinaryHeap[int, int](nil, lt, false)
+	syncHeap.Push(1, 1)
+	assert.Nil(t, syncHeap.Fix(0))
+	assert.ErrorIs(t, syncHeap.Fix(-1), ErrIndexOutOfBounds)
+
+	var outOfBounds *IndexOutOfBoundsError
+	require.ErrorAs(t, syncHeap.Update(3, 0, 0), &outOfBounds)
+	assert.Equal(t, IndexOutOfBoundsError{Index: 3, Length: 1}, *outOfBounds)
+	assert.Equal(t, "index out of bounds: index 3, length 1", outOfBounds.Error())
+}
+
+func TestStableDaryHeap_FIFOTies(t *testing.T) {
+	for _, d := range []int{2, 3, 4} {
+		data := []HeapNode[int, int]{}
+		for i := 0; i < 10; i++ {
+			data = append(data, CreateHeapNode(i, i%3))
+		}
+		h := NewStableDaryHeap(d, data, lt, DaryHeapConfig{})
+		for i := 10; i < 30; i++ {
+			h.Push(i, i%3)
+		}
+
+		var values []int
+		for !h.IsEmpty() {
+			v, _, err := h.Pop()
+			require.NoError(t, err)
+			values = append(values, v)
+		}
+		for i := 1; i < len(values); i++ {
+			if values[i-1]%3 == values[i]%3 {
+				assert.Less(t, values[i-1], values[i], "d=%d", d)
+			}
+		}
+		assert.Len(t, values, 30)
+	}
+
+	syncHeap := NewSyncStableDaryHeap[string, int](2, nil, lt, DaryHeapConfig{})
+	for _, v := range []string{"a", "b", "c"} {
+		syncHeap.Push(v, 1)
+	}
+	clone := syncHeap.Clone()
+	assert.Equal(t, []string{"a", "b", "c"}, syncHeap.DrainValues())
+
+	clone.Push("d", 1)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, clone.DrainValues())
+}
+
+func TestMinMaxDaryHeap(t *testing.T) {
+	data := func() []HeapNode[string, float64] {
+		return []HeapNode[string, float64]{
+			CreateHeapNode("b", 2.5), CreateHeapNode("a", -1.0), CreateHeapNode("c", 7.0),
+		}
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, NewMinDaryHeap(3, data(), DaryHeapConfig{}).DrainValues())
+	assert.Equal(t, []string{"c", "b", "a"}, NewMaxDaryHeap(3, data(), DaryHeapConfig{}).DrainValues())
+	assert.Equal(t, []string{"a", "b", "c"}, NewMinBinaryHeap(data(), DaryHeapConfig{}).DrainValues())
+	assert.Equal(t, []string{"c", "b", "a"}, NewMaxBinaryHeap(data(), DaryHeapConfig{}).DrainValues())
+}
+
+func TestDaryHeap_PushAll(t *testing.T) {
+	for _, batch := range []int{3, 200} {
+		h := NewDaryHeap[int, int](4, nil, lt, false)
+		for i := 0; i < 100; i++ {
+			h.Push(i, (i*37)%100)
+		}
+		var swaps int
+		h.Register(func(x, y int) { swaps++ })
+
+		data := make([]HeapNode[int, int], batch)
+		for i := range data {
+			data[i] = CreateHeapNode(i, (i*53)%batch-50)
+		}
+		h.PushAll(data)
+
+		assert.Equal(t, 100+batch, h.Length(), "batch=%d", batch)
+		assert.True(t, swaps > 0, "batch=%d", batch)
+		assert.IsNonDecreasing(t, h.DrainPriorities(), "batch=%d", batch)
+	}
+
+	syncHeap := NewSyncStableDaryHeap[string, int](2, nil, lt, DaryHeapConfig{})
+	syncHeap.Push("a", 1)
+	syncHeap.PushAll([]HeapNode[string, int]{CreateHeapNode("b", 1), CreateHeapNode("c", 0)})
+	assert.Equal(t, []string{"c", "a", "b"}, syncHeap.DrainValues())
+}
+
+func TestDaryHeap_BlockLayout(t *testing.T) {
+	for _, d := range []int{2, 3, 4} {
+		for _, levels := range []int{0, 2, 3, 4} {
+			layout := newBlockLayout(d, levels)
+			for i := 0; i < 2000 && levels > 0; i++ {
+				first, step := layout.children(i, d)
+				for k := 0; k < d; k++ {
+					child := first + k*step
+					require.Greater(t, child, i, "d=%d levels=%d", d, levels)
+					require.Equal(t, i, layout.parent(child, d), "d=%d levels=%d child=%d", d, levels, child)
+				}
+			}
+
+			name := fmt.Sprintf("d=%d levels=%d", d, levels)
+			data := make([]HeapNode[int, int], 500)
+			for i := range data {
+				data[i] = CreateHeapNode(i, (i*7919)%1000)
+			}
+			h := NewDaryHeapWithConfig(d, data, lt, DaryHeapConfig{BlockLevels: levels})
+			require.NoError(t, h.Verify(), name)
+			for i := 0; i < 300; i++ {
+				h.Push(i, (i*104729)%1000)
+			}
+			require.NoError(t, h.Update(h.Length()-1, -1, -1), name)
+			_, _, err := h.Remove(h.Length() / 2)
+			require.NoError(t, err, name)
+			require.NoError(t, h.Verify(), name)
+
+			clone := h.Clone()
+			sorted := nodePriorities(Sorted(h))
+			assert.IsNonDecreasing(t, sorted, name)
+			assert.Equal(t, -1, sorted[0], name)
+
+			var ordered []int
+			for _, p := range h.Ordered() {
+				ordered = append(ordered, p)
+			}
+			assert.Equal(t, sorted, ordered, name)
+			assert.Equal(t, sorted, clone.DrainPriorities(), name)
+			assert.Equal(t, sorted, h.DrainPriorities(), name)
+		}
+	}
+
+	h := NewSyncDaryHeapWithConfig[int, int](2, nil, lt, DaryHeapConfig{BlockLevels: 3})
+	for i := 100; i > 0; i-- {
+		h.Push(i, i)
+	}
+	snapshot, err := h.Snapshot()
+	require.NoError(t, err)
+	restored := NewDaryHeapWithConfig[int, int](4, nil, lt, DaryHeapConfig{BlockLevels: 3})
+	require.NoError(t, restored.Restore(snapshot))
+	require.NoError(t, restored.Verify())
+	assert.Equal(t, newBlockLayout(2, 3), restored.blocks)
+	assert.Equal(t, h.DrainPriorities(), restored.DrainPriorities())
+}
+
+func TestDaryHeap_InvalidArity(t *testing.T) {
+	for _, d := range []int{0, -3} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok, "d=%d did not panic with an error", d)
+				assert.ErrorIs(t, err, ErrInvalidArity)
+				assert.Contains(t, err.Error(), fmt.Sprint(d))
+			}()
+			NewDaryHeap[int, int](d, nil, lt, false)
+		}()
+	}
+	assert.Panics(t, func() { NewSyncDaryHeap[int, int](0, nil, lt, false) })
+	assert.Panics(t, func() { NewKeyedHeap[string, int, int](-1, lt, DaryHeapConfig{}) })
+	assert.NotPanics(t, func() { NewDaryHeap[int, int](2, nil, lt, false) })
+}
+
+func TestDaryHeap_UnaryArity(t *testing.T) {
+	for _, levels := range []int{0, 3} {
+		name := fmt.Sprintf("levels=%d", levels)
+		data := []HeapNode[int, int]{CreateHeapNode(5, 5), CreateHeapNode(2, 2), CreateHeapNode(8, 8)}
+		h := NewDaryHeapWithConfig(1, data, lt, DaryHeapConfig{BlockLevels: levels})
+		require.NoError(t, h.Verify(), name)
+		for _, p := range []int{7, 1, 9, 3} {
+			h.Push(p, p)
+			require.NoError(t, h.Verify(), name)
+		}
+		_, _, err := h.Remove(2)
+		require.NoError(t, err, name)
+		require.NoError(t, h.Verify(), name)
+		assert.Equal(t, 6, h.Length(), name)
+		priorities := h.DrainPriorities()
+		assert.True(t, sort.IntsAreSorted(priorities), name)
+	}
+
+	keyed := NewKeyedHeap[string, int, int](1, lt, DaryHeapConfig{})
+	keyed.Push("a", 1, 3)
+	keyed.Push("b", 2, 1)
+	_, v, _, err := keyed.PopKey()
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestDaryHeap_Handles(t *testing.T) {
+	for _, levels := range []int{0, 2} {
+		name := fmt.Sprintf("levels=%d", levels)
+		h := NewDaryHeapWithConfig[int, int](3, nil, lt, DaryHeapConfig{BlockLevels: levels})
+		for i := 0; i < 50; i++ {
+			h.Push(i, (i*37)%50+10)
+		}
+		handles := make(map[int]*DaryHandle)
+		for _, p := range []int{5, 100, 30, 1} {
+			handles[p] = h.PushHandle(p, p)
+		}
+		for p, handle := range handles {
+			v, priority, err := h.GetByHandle(handle)
+			require.NoError(t, err, name)
+			assert.Equal(t, p, v, name)
+			assert.Equal(t, p, priority, name)
+		}
+
+		// The handle follows its element as it moves to the root and back.
+		require.NoError(t, h.UpdateByHandle(handles[100], 100, 0), name)
+		i, err := h.IndexOf(handles[100])
+		require.NoError(t, err, name)
+		assert.Equal(t, 0, i, name)
+		require.NoError(t, h.UpdateByHandle(handles[100], 100, 100), name)
+		_, priority, err := h.GetByHandle(handles[100])
+		require.NoError(t, err, name)
+		assert.Equal(t, 100, priority, name)
+
+		v, _, err := h.RemoveByHandle(handles[30])
+		require.NoError(t, err, name)
+		assert.Equal(t, 30, v, name)
+		_, _, err = h.RemoveByHandle(handles[30])
+		assert.ErrorIs(t, err, ErrStaleHandle, name)
+		require.NoError(t, h.Verify(), name)
+
+		v, _, err = h.Pop()
+		require.NoError(t, err, name)
+		assert.Equal(t, 1, v, name)
+		_, _, err = h.GetByHandle(handles[1])
+		assert.ErrorIs(t, err, ErrStaleHandle, name)
+
+		// Elements pushed without a handle can be given one.
+		other, err := h.HandleAt(h.Length() - 1)
+		require.NoError(t, err, name)
+		want, _, _ := h.At(h.Length() - 1)
+		again, _ := h.HandleAt(h.Length() - 1)
+		assert.True(t, other == again, name)
+		for h.Length() > 1 {
+			h.Pop()
+			if got, _, err := h.GetByHandle(other); err == nil {
+				assert.Equal(t, want, got, name)
+			}
+		}
+
+		foreign := NewDaryHeap[int, int](3, nil, lt, false).PushHandle(1, 1)
+		_, err = h.IndexOf(foreign)
+		assert.ErrorIs(t, err, ErrStaleHandle, name)
+		_, err = h.IndexOf(nil)
+		assert.ErrorIs(t, err, ErrStaleHandle, name)
+
+		h.Clear()
+		assert.ErrorIs(t, h.UpdateByHandle(handles[100], 1, 1), ErrStaleHandle, name)
+		_, err = h.HandleAt(0)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDaryHeap_At(t *testing.T) {
+	h := NewDaryHeap[string, int](2, nil, lt, false)
+	_, _, err := h.At(0)
+	assert.ErrorIs(t, err, ErrIndexOutOfBounds)
+	h.Push("b", 2)
+	h.Push("a", 1)
+	v, p, err := h.At(0)
+	require.NoError(t, err)
+	assert.Equal(t, "a", v)
+	assert.Equal(t, 1, p)
+	_, _, err = h.At(-1)
+	assert.Error(t, err)
+	_, _, err = h.At(2)
+	assert.Error(t, err)
+}
+
+func TestDaryHeap_Reset(t *testing.T) {
+	h := NewDaryHeap[*int, int](4, nil, lt, false)
+	for i := 0; i < 1000; i++ {
+		h.Push(new(int), i)
+	}
+	handle := h.PushHandle(new(int), -1)
+	capacity := cap(h.data)
+	h.Reset()
+	assert.True(t, h.IsEmpty())
+	assert.Equal(t, capacity, cap(h.data))
+	assert.Nil(t, h.data[:1][0].value)
+	_, err := h.IndexOf(handle)
+	assert.ErrorIs(t, err, ErrStaleHandle)
+
+	allocs := testing.AllocsPerRun(5, func() {
+		for i := 0; i < 1000; i++ {
+			h.Push(nil, i)
+		}
+		h.Reset()
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, capacity, cap(h.data))
+
+	h.Push(nil, 1)
+	h.Clear()
+	assert.Zero(t, cap(h.data))
+}
+
+func TestDaryHeap_FromSlices(t *testing.T) {
+	values := []string{"c", "a", "d", "b"}
+	priorities := []int{3, 1, 4, 2}
+	h, err := NewDaryHeapFromSlices(3, values, priorities, lt, DaryHeapConfig{})
+	require.NoError(t, err)
+	require.NoError(t, h.Verify())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, h.DrainValues())
+	assert.Equal(t, []string{"c", "a", "d", "b"}, values)
+	assert.Equal(t, []int{3, 1, 4, 2}, priorities)
+
+	sh, err := NewSyncBinaryHeapFromSlices(values, priorities, gt, DaryHeapConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 3, 2, 1}, sh.DrainPriorities())
+
+	stable, err := NewBinaryHeapFromSlices([]string{"x", "y", "z"}, []int{1, 1, 1}, lt, DaryHeapConfig{Stable: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "y", "z"}, stable.DrainValues())
+
+	noCallbacks, err := NewSyncDaryHeapFromSlices(2, values, priorities, lt, DaryHeapConfig{DisableCallbacks: true})
+	require.NoError(t, err)
+	assert.ErrorIs(t, noCallbacks.Deregister("missing"), ErrCallbacksDisabled)
+
+	_, err = NewBinaryHeapFromSlices(values, priorities[:2], lt, DaryHeapConfig{})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, err = NewSyncDaryHeapFromSlices(4, values[:1], priorities, lt, DaryHeapConfig{})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestDaryHeap_OwnedAndData(t *testing.T) {
+	data := []HeapNode[string, int]{
+		CreateHeapNode("c", 3), CreateHeapNode("a", 1), CreateHeapNode("b", 2),
+	}
+	h := NewBinaryHeapOwned(data, lt, DaryHeapConfig{})
+	assert.Equal(t, "a", data[0].value, "the owned slice is the heap's array")
+
+	var values []string
+	for v := range h.Data() {
+		values = append(values, v)
+	}
+	assert.Equal(t, []string{"a", "c", "b"}, values)
+	for v, p := range h.Data() {
+		assert.Equal(t, "a", v)
+		assert.Equal(t, 1, p)
+		break
+	}
+
+	sh := NewSyncDaryHeapOwned(3, []HeapNode[string, int]{CreateHeapNode("x", 2), CreateHeapNode("y", 1)}, lt, DaryHeapConfig{})
+	var priorities []int
+	for _, p := range sh.Data() {
+		priorities = append(priorities, p)
+	}
+	assert.Equal(t, []int{1, 2}, priorities)
+	sh.Push("z", 0)
+
+	stable := NewDaryHeapOwned(2, []HeapNode[string, int]{CreateHeapNode("x", 1), CreateHeapNode("y", 1)}, lt, DaryHeapConfig{Stable: true})
+	assert.Equal(t, []string{"x", "y"}, stable.DrainValues())
+}
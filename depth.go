@@ -0,0 +1,16 @@
+package heapcraft
+
+// defaultMaxDepth is the recursion depth allowed when HeapConfig.MaxDepth is
+// zero. It is far below the depth at which the Go runtime aborts on stack
+// exhaustion, which cannot be recovered from.
+const defaultMaxDepth = 1 << 20
+
+// depthExceeds reports whether a recursion of the given depth would exceed
+// limit, the MaxDepth a heap was configured with. A limit of zero selects
+// defaultMaxDepth and a negative limit disables the check.
+func depthExceeds(limit, depth int) bool {
+	if limit == 0 {
+		limit = defaultMaxDepth
+	}
+	return limit > 0 && depth > limit
+}
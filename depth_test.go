@@ -0,0 +1,202 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepthExceeds(t *testing.T) {
+	assert.False(t, depthExceeds(0, defaultMaxDepth))
+	assert.True(t, depthExceeds(0, defaultMaxDepth+1))
+	assert.False(t, depthExceeds(10, 10))
+	assert.True(t, depthExceeds(10, 11))
+	assert.False(t, depthExceeds(-1, 1<<30))
+
+	config := NewHeapConfig(WithMaxDepth(10))
+	assert.Equal(t, 10, config.MaxDepth)
+	assert.Equal(t, 10, NewSimplePairingHeap[int, int](nil, lt, config).maxDepth)
+	assert.Equal(t, 10, NewSimpleSkewHeap[int, int](nil, lt, config).maxDepth)
+	assert.Equal(t, 10, NewFullPairingHeap[int, int](nil, lt, config).maxDepth)
+	assert.Equal(t, 10, NewFullSkewHeap[int, int](nil, lt, config).maxDepth)
+}
+
+func TestPairingHeap_MaxDepthExceeded(t *testing.T) {
+	heap := NewFullPairingHeap[int, int](nil, lt, HeapConfig{})
+	for i := 0; i < 20; i++ {
+		_, err := heap.Push(i, i)
+		require.NoError(t, err)
+	}
+
+	heap.maxDepth = 4
+	_, _, err := heap.Pop()
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+	assert.Equal(t, 20, heap.Length())
+
+	heap.maxDepth = 0
+	for i := 0; i < 20; i++ {
+		_, priority, err := heap.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, i, priority)
+	}
+}
+
+func TestSkewHeap_MaxDepthExceeded(t *testing.T) {
+	heap := NewFullSkewHeap[int, int](nil, lt, HeapConfig{})
+	for i := 0; i < 20; i++ {
+		_, err := heap.Push(i, i)
+		require.NoError(t, err)
+	}
+
+	heap.maxDepth = 2
+	_, err := heap.Push(20, 20)
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+	assert.Equal(t, 20, heap.Length())
+
+	heap.maxDepth = 0
+	for i := 0; i < 20; i++ {
+		_, priority, err := heap.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, i, priority)
+	}
+}
+
+func TestSimpleSkewHeap_MaxDepthFallback(t *testing.T) {
+	heap := NewSimpleSkewHeap[int, int](nil, lt, NewHeapConfig(WithMaxDepth(2)))
+	other := NewSkewHeap[int, int](nil, lt, false)
+	for i := 0; i < 20; i++ {
+		heap.Push(i, i)
+		other.Push(i+100, i+100)
+	}
+
+	assert.ErrorIs(t, heap.TryPush(20, 20), ErrMaxDepthExceeded)
+	assert.ErrorIs(t, heap.TryPushAll([]HeapNode[int, int]{CreateHeapNode(21, 21)}), ErrMaxDepthExceeded)
+	assert.ErrorIs(t, heap.TryMeld(other), ErrMaxDepthExceeded)
+	assert.Equal(t, 20, heap.Length())
+	assert.Equal(t, 20, other.Length())
+	require.NoError(t, heap.Verify())
+
+	// The operations without an error result merge without recursion instead.
+	heap.Push(20, 20)
+	heap.PushAll([]HeapNode[int, int]{CreateHeapNode(21, 21)})
+	heap.Meld(other)
+	require.NoError(t, heap.Verify())
+	assert.Equal(t, 42, heap.Length())
+	assert.True(t, other.IsEmpty())
+
+	heap.maxDepth = -1
+	want := make([]int, 0, 42)
+	for i := 0; i < 22; i++ {
+		want = append(want, i)
+	}
+	for i := 0; i < 20; i++ {
+		want = append(want, i+100)
+	}
+	assert.Equal(t, want, heap.DrainPriorities())
+}
+
+// degenerateHeaps builds simple tree-based heaps of n elements shaped as a
+// single path: ascending pushes hang every node off the pairing root's sibling
+// list, and descending pushes chain the leftist and skew trees down their left
+// links.
+func degenerateHeaps(n int) (*PairingHeap[int, int], *LeftistHeap[int, int], *SkewHeap[int, int]) {
+	pairing := NewPairingHeap[int, int](nil, lt, false)
+	leftist := NewLeftistHeap[int, int](nil, lt, false)
+	skew := NewSkewHeap[int, int](nil, lt, false)
+	for i := 0; i < n; i++ {
+		pairing.Push(i, i)
+		leftist.Push(n-i, n-i)
+		skew.Push(n-i, n-i)
+	}
+	return pairing, leftist, skew
+}
+
+func TestClone_DeepTrees(t *testing.T) {
+	const n = 1_000_000
+	pairing, leftist, skew := degenerateHeaps(n)
+	pairing.maxDepth, skew.maxDepth = 4, 4
+
+	pairingClone := pairing.Clone()
+	leftistClone := leftist.Clone()
+	skewClone := skew.Clone()
+	assert.Equal(t, n, pairingClone.Length())
+	assert.Equal(t, n, leftistClone.Length())
+	assert.Equal(t, n, skewClone.Length())
+
+	assert.Equal(t, 4, pairingClone.maxDepth)
+	pairingClone.maxDepth, skewClone.maxDepth = -1, -1
+	require.NoError(t, leftistClone.Verify())
+	for i := 0; i < 3; i++ {
+		p, err := pairingClone.PopPriority()
+		require.NoError(t, err)
+		assert.Equal(t, i, p)
+		p, err = leftistClone.PopPriority()
+		require.NoError(t, err)
+		assert.Equal(t, i+1, p)
+		p, err = skewClone.PopPriority()
+		require.NoError(t, err)
+		assert.Equal(t, i+1, p)
+	}
+	assert.Equal(t, n, pairing.Length())
+}
+
+func TestReplaceRoot_MaxDepthExceeded(t *testing.T) {
+	pairing := NewPairingHeap[int, int](nil, lt, false)
+	skew := NewSkewHeap[int, int](nil, lt, false)
+	for i := 0; i < 20; i++ {
+		pairing.Push(i, i)
+		skew.Push(19-i, 19-i)
+	}
+	pairing.maxDepth, skew.maxDepth = 4, 2
+
+	_, _, err := pairing.TryPopPush(100, 100)
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+	assert.NoError(t, pairing.Verify())
+	assert.Equal(t, 20, pairing.Length())
+	_, _, err = skew.TryPushPop(100, 100)
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+	assert.NoError(t, skew.Verify())
+	assert.Equal(t, 20, skew.Length())
+
+	// PopPush and PushPop fall back to merging without recursion.
+	_, p := pairing.PopPush(100, 100)
+	assert.Equal(t, 0, p)
+	_, p = skew.PushPop(100, 100)
+	assert.Equal(t, 0, p)
+	require.NoError(t, pairing.Verify())
+	require.NoError(t, skew.Verify())
+
+	pairing.maxDepth, skew.maxDepth = -1, -1
+	want := make([]int, 0, 20)
+	for i := 1; i < 20; i++ {
+		want = append(want, i)
+	}
+	want = append(want, 100)
+	assert.Equal(t, want, pairing.DrainPriorities())
+	assert.Equal(t, want, skew.DrainPriorities())
+}
+
+// -------------------------------- Clone Benchmarks --------------------------------
+
+func BenchmarkCloneDegenerate(b *testing.B) {
+	pairing, leftist, skew := degenerateHeaps(100_000)
+	b.Run("pairing", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pairing.Clone()
+		}
+	})
+	b.Run("leftist", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			leftist.Clone()
+		}
+	})
+	b.Run("skew", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			skew.Clone()
+		}
+	})
+}
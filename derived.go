@@ -0,0 +1,26 @@
+package heapcraft
+
+// DerivedHeap wraps a heap whose priorities are derived from the values
+// themselves, such as a job's deadline, so that elements can be pushed by
+// value alone. The underlying heap is embedded, so every other operation,
+// including the two-argument Push for an element whose priority should differ
+// from the derived one, is available unchanged.
+type DerivedHeap[V any, P any] struct {
+	Heap[V, P]
+	priority func(V) P
+}
+
+// PriorityOf returns the priority derived from value.
+func (h *DerivedHeap[V, P]) PriorityOf(value V) P { return h.priority(value) }
+
+// PushValue inserts value with the priority derived from it.
+func (h *DerivedHeap[V, P]) PushValue(value V) {
+	h.Heap.Push(value, h.priority(value))
+}
+
+// PushValues inserts every value with the priority derived from it.
+func (h *DerivedHeap[V, P]) PushValues(values ...V) {
+	for _, value := range values {
+		h.PushValue(value)
+	}
+}
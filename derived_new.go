@@ -0,0 +1,15 @@
+package heapcraft
+
+// NewDerivedHeap wraps heap so that elements can be pushed by value, with
+// their priority derived by priority. The heap may already hold elements.
+func NewDerivedHeap[V any, P any](heap Heap[V, P], priority func(V) P) *DerivedHeap[V, P] {
+	return &DerivedHeap[V, P]{Heap: heap, priority: priority}
+}
+
+// NewDerivedDaryHeap creates a d-ary heap over values, with every priority
+// derived from its value by priority, and wraps it in a DerivedHeap. The
+// values slice is not modified, and config sets the options of the d-ary
+// heap.
+func NewDerivedDaryHeap[V any, P any](d int, values []V, priority func(V) P, cmp func(a, b P) bool, config DaryHeapConfig) *DerivedHeap[V, P] {
+	return NewDerivedHeap(NewDaryHeapWithConfig(d, NodesFromSlice(values, priority), cmp, config), priority)
+}
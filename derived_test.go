@@ -0,0 +1,43 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deadlineJob struct {
+	name     string
+	deadline int
+}
+
+func jobDeadline(j deadlineJob) int { return j.deadline }
+
+func TestDerivedHeap_PushValue(t *testing.T) {
+	heap := NewDerivedHeap[deadlineJob, int](NewPairingHeap[deadlineJob, int](nil, lt, false), jobDeadline)
+	heap.PushValue(deadlineJob{"report", 30})
+	heap.PushValues(deadlineJob{"backup", 10}, deadlineJob{"deploy", 20})
+	// An explicit priority still overrides the derived one.
+	heap.Push(deadlineJob{"hotfix", 40}, 0)
+	assert.Equal(t, 40, heap.PriorityOf(deadlineJob{"hotfix", 40}))
+
+	var names []string
+	for _, job := range heap.DrainValues() {
+		names = append(names, job.name)
+	}
+	assert.Equal(t, []string{"hotfix", "backup", "deploy", "report"}, names)
+}
+
+func TestDerivedHeap_ReverseDary(t *testing.T) {
+	values := []deadlineJob{{"a", 3}, {"b", 9}, {"c", 1}}
+	heap := NewDerivedDaryHeap(3, values, jobDeadline, Reverse(lt), DaryHeapConfig{})
+	assert.Equal(t, deadlineJob{"a", 3}, values[0])
+	heap.PushValue(deadlineJob{"d", 5})
+
+	job, priority, err := heap.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "b", job.name)
+	assert.Equal(t, 9, priority)
+	assert.Equal(t, []int{5, 3, 1}, heap.DrainPriorities())
+}
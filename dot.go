@@ -0,0 +1,67 @@
+package heapcraft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// dotEdge is a link from one node of a tree heap to another, as drawn by
+// writeDOT. Back edges, such as parent pointers, are drawn dashed and are not
+// followed when discovering nodes.
+type dotEdge[N any] struct {
+	label string
+	to    N
+	back  bool
+}
+
+// writeDOT writes the trees below roots to w as a Graphviz digraph. label
+// returns the text shown for a node, and links calls edge for every link out
+// of a node. Nodes are numbered in the order they are discovered, without
+// recursion, so trees of any depth can be drawn. Back edges to nodes that are
+// not reachable from the roots are drawn to a placeholder node, which makes
+// stale pointers visible.
+func writeDOT[N comparable](w io.Writer, roots []N, label func(N) string, links func(node N, edge func(dotEdge[N]))) error {
+	ids := make(map[N]int)
+	var order []N
+	stack := append([]N(nil), roots...)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, seen := ids[node]; seen {
+			continue
+		}
+		ids[node] = len(order)
+		order = append(order, node)
+		links(node, func(e dotEdge[N]) {
+			if !e.back {
+				stack = append(stack, e.to)
+			}
+		})
+	}
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintln(buf, "digraph heap {")
+	fmt.Fprintln(buf, "\tnode [shape=box];")
+	for i, node := range order {
+		fmt.Fprintf(buf, "\tn%d [label=%s];\n", i, strconv.Quote(label(node)))
+	}
+	for i, node := range order {
+		links(node, func(e dotEdge[N]) {
+			to, known := ids[e.to]
+			target := "n" + strconv.Itoa(to)
+			if !known {
+				target = "missing" + strconv.Itoa(i) + e.label
+				fmt.Fprintf(buf, "\t%s [label=\"?\", style=dashed];\n", target)
+			}
+			style := ""
+			if e.back {
+				style = ", style=dashed, constraint=false"
+			}
+			fmt.Fprintf(buf, "\tn%d -> %s [label=%s%s];\n", i, target, strconv.Quote(e.label), style)
+		})
+	}
+	fmt.Fprintln(buf, "}")
+	return buf.Flush()
+}
@@ -0,0 +1,74 @@
+package heapcraft
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dotWriter is implemented by every heap that can be drawn with WriteDOT.
+type dotWriter interface {
+	WriteDOT(w io.Writer) error
+}
+
+func TestWriteDOT(t *testing.T) {
+	data := []HeapNode[int, int]{CreateHeapNode(1, 3), CreateHeapNode(2, 1), CreateHeapNode(3, 2)}
+	config := HeapConfig{IDGenerator: &IntegerIDGenerator{}}
+	heaps := map[string]dotWriter{
+		"pairing":     NewPairingHeap(data, lt, false),
+		"fullPairing": NewSyncFullPairingHeap(data, lt, config),
+		"leftist":     NewSyncLeftistHeap(data, lt, false),
+		"fullLeftist": NewFullLeftistHeap(data, lt, config),
+		"skew":        NewSkewHeap(data, lt, false),
+		"fullSkew":    NewSyncFullSkewHeap(data, lt, config),
+		"binomial":    NewBinomialHeap(data, lt, HeapConfig{}),
+	}
+
+	for name, heap := range heaps {
+		var buf bytes.Buffer
+		assert.NoError(t, heap.WriteDOT(&buf), name)
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "digraph heap {\n"), name)
+		assert.True(t, strings.HasSuffix(out, "}\n"), name)
+		// Every line with a label is either a node or an edge.
+		assert.Equal(t, 3, strings.Count(out, " [label=")-strings.Count(out, " -> "), name)
+		assert.Contains(t, out, "n0 -> n", name)
+	}
+}
+
+func TestWriteDOT_Labels(t *testing.T) {
+	heap := NewFullLeftistHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	heap.Push("a", 2)
+	heap.Push("b", 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, heap.WriteDOT(&buf))
+	assert.Equal(t, `digraph heap {
+	node [shape=box];
+	n0 [label="1\n1\ns=1"];
+	n1 [label="0\n2\ns=1"];
+	n0 -> n1 [label="left"];
+	n1 -> n0 [label="parent", style=dashed, constraint=false];
+}
+`, buf.String())
+}
+
+func TestWriteDOT_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, NewSkewHeap[int, int](nil, lt, false).WriteDOT(&buf))
+	assert.Equal(t, "digraph heap {\n\tnode [shape=box];\n}\n", buf.String())
+}
+
+// failingWriter rejects every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteDOT_WriteError(t *testing.T) {
+	heap := NewPairingHeap([]HeapNode[int, int]{CreateHeapNode(1, 1)}, lt, false)
+	assert.EqualError(t, heap.WriteDOT(failingWriter{}), "disk full")
+}
@@ -1,12 +1,22 @@
 package heapcraft
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
 
 var (
 	// ErrCallbackNotFound is returned when attempting to deregister a callback that
 	// doesn't exist.
 	ErrCallbackNotFound = errors.New("callback not found")
 
+	// ErrCallbacksDisabled is returned, or panicked with by Register, when
+	// registering or deregistering a swap callback on a d-ary heap created
+	// with DisableCallbacks set.
+	ErrCallbacksDisabled = errors.New("swap callbacks are disabled for this heap")
+
 	// ErrHeapEmpty is returned when attempting to access elements from an empty heap.
 	ErrHeapEmpty = errors.New("the heap is empty and contains no elements")
 
@@ -30,4 +40,160 @@ var (
 	// ErrIDGenerationFailed is returned when attempting to generate a unique ID for a
 	// node that already exists.
 	ErrIDGenerationFailed = errors.New("failed to generate a unique ID")
+
+	// ErrDuplicateID is returned when attempting to meld two tracked heaps that
+	// contain a node with the same ID.
+	ErrDuplicateID = errors.New("id already exists in the heap")
+
+	// ErrInvalidTiers is returned when tier bounds are not strictly ascending or
+	// a tier configuration is otherwise invalid.
+	ErrInvalidTiers = errors.New("tier bounds must be strictly ascending")
+
+	// ErrUninitializedHeap is returned when attempting to unmarshal into a heap that
+	// was not created with its constructor and so has no comparison function.
+	ErrUninitializedHeap = errors.New("heap must be created with its constructor before unmarshalling")
+
+	// ErrInvalidSnapshot is returned when restoring a heap from a snapshot that is
+	// malformed or was written in an unsupported format version.
+	ErrInvalidSnapshot = errors.New("snapshot is malformed or has an unsupported version")
+
+	// ErrTaskNotFound is returned when attempting to deregister a maintenance task
+	// that doesn't exist.
+	ErrTaskNotFound = errors.New("maintenance task not found")
+
+	// ErrKeyExists is returned when attempting to push a key that is already in
+	// an AddressableMinQueue.
+	ErrKeyExists = errors.New("key already exists in the queue")
+
+	// ErrKeyNotFound is returned when attempting to access a key that is not in
+	// an AddressableMinQueue.
+	ErrKeyNotFound = errors.New("key does not exist in the queue")
+
+	// ErrPriorityNotDecreased is returned by DecreaseKey when the new priority
+	// does not come before the key's current priority, and by DecreasePriority
+	// when it comes after the node's current priority.
+	ErrPriorityNotDecreased = errors.New("new priority does not decrease the current one")
+
+	// ErrPriorityNotIncreased is returned by IncreasePriority when the new
+	// priority comes before the node's current priority.
+	ErrPriorityNotIncreased = errors.New("new priority does not increase the current one")
+
+	// ErrInvalidInterval is returned when adding an interval to an IntervalHeap
+	// whose lower bound is greater than its upper bound.
+	ErrInvalidInterval = errors.New("interval lower bound is greater than its upper bound")
+
+	// ErrMaxDepthExceeded is returned when an operation would recurse deeper
+	// than the MaxDepth a heap was configured with.
+	ErrMaxDepthExceeded = errors.New("operation exceeds the maximum recursion depth")
+
+	// ErrInvariantViolated is returned by Verify when the internal structure of
+	// a heap is inconsistent. The returned error wraps it with a description of
+	// the first violation found.
+	ErrInvariantViolated = errors.New("heap invariant violated")
+
+	// ErrLengthMismatch is returned by NodesFromPairs and the FromSlices
+	// constructors when they are given different numbers of values and
+	// priorities.
+	ErrLengthMismatch = errors.New("values and priorities have different lengths")
+
+	// ErrInvalidArity is panicked with, wrapped together with the offending
+	// value, by the d-ary heap constructors when d is less than 1. External
+	// heaps return it when their arity is less than 2.
+	ErrInvalidArity = errors.New("invalid d-ary heap arity")
+
+	// ErrStaleHandle is returned when a DaryHandle is used after its element
+	// has left the heap, or with a heap other than the one that issued it.
+	ErrStaleHandle = errors.New("handle does not refer to an element of this heap")
+
+	// ErrLaneExists is returned when adding a lane to a CompositeQueue under a
+	// name that is already in use.
+	ErrLaneExists = errors.New("lane already exists in the queue")
+
+	// ErrLaneNotFound is returned when a CompositeQueue has no lane with the
+	// requested name.
+	ErrLaneNotFound = errors.New("lane does not exist in the queue")
+
+	// ErrInvalidWeight is returned when a CompositeQueue lane is given a
+	// weight that is not positive.
+	ErrInvalidWeight = errors.New("lane weight must be positive")
+
+	// ErrRateLimited is returned by RateLimitedHeap.Pop when no token is
+	// available to pop an element yet.
+	ErrRateLimited = errors.New("pop rate limit exceeded")
+
+	// ErrInvalidValueSize is returned when a value pushed onto an ExternalHeap
+	// is not exactly the configured value size.
+	ErrInvalidValueSize = errors.New("value does not match the heap's value size")
 )
+
+// PriorityError is returned by a radix heap when an element's priority is
+// less than the last extracted priority. It carries the offending priority
+// and the heap's last priority so callers can log, clamp or reroute the
+// element, and it matches ErrPriorityLessThanLast with errors.Is.
+type PriorityError[P constraints.Unsigned] struct {
+	// Priority is the priority that was rejected.
+	Priority P
+	// Last is the last extracted priority of the heap at the time.
+	Last P
+}
+
+// Error returns a description of the error including both priorities.
+func (e *PriorityError[P]) Error() string {
+	return fmt.Sprintf("%s: priority %d, last %d", ErrPriorityLessThanLast, e.Priority, e.Last)
+}
+
+// Unwrap returns ErrPriorityLessThanLast.
+func (e *PriorityError[P]) Unwrap() error { return ErrPriorityLessThanLast }
+
+// NodeNotFoundError is returned when no element of a heap has the requested
+// ID. It carries the ID so that it can be logged without parsing the message,
+// and it matches ErrNodeNotFound with errors.Is.
+type NodeNotFoundError struct {
+	// ID is the ID that was looked up.
+	ID string
+}
+
+// Error returns a description of the error including the ID.
+func (e *NodeNotFoundError) Error() string {
+	return fmt.Sprintf("%s: id %q", ErrNodeNotFound, e.ID)
+}
+
+// Unwrap returns ErrNodeNotFound.
+func (e *NodeNotFoundError) Unwrap() error { return ErrNodeNotFound }
+
+// KeyNotFoundError is returned by KeyedHeap and AddressableMinQueue when no
+// element is stored under the requested key. It carries the key and matches
+// both ErrKeyNotFound and ErrNodeNotFound with errors.Is, since KeyedHeap
+// reported a missing key as ErrNodeNotFound before it was introduced.
+type KeyNotFoundError[K comparable] struct {
+	// Key is the key that was looked up.
+	Key K
+}
+
+// Error returns a description of the error including the key.
+func (e *KeyNotFoundError[K]) Error() string {
+	return fmt.Sprintf("%s: key %v", ErrKeyNotFound, e.Key)
+}
+
+// Unwrap returns ErrKeyNotFound and ErrNodeNotFound.
+func (e *KeyNotFoundError[K]) Unwrap() []error {
+	return []error{ErrKeyNotFound, ErrNodeNotFound}
+}
+
+// IndexOutOfBoundsError is returned when an index lies outside a heap. It
+// carries the index and the length of the heap at the time, and it matches
+// ErrIndexOutOfBounds with errors.Is.
+type IndexOutOfBoundsError struct {
+	// Index is the index that was requested.
+	Index int
+	// Length is the number of elements in the heap at the time.
+	Length int
+}
+
+// Error returns a description of the error including the index and length.
+func (e *IndexOutOfBoundsError) Error() string {
+	return fmt.Sprintf("%s: index %d, length %d", ErrIndexOutOfBounds, e.Index, e.Length)
+}
+
+// Unwrap returns ErrIndexOutOfBounds.
+func (e *IndexOutOfBoundsError) Unwrap() error { return ErrIndexOutOfBounds }
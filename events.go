@@ -0,0 +1,115 @@
+package heapcraft
+
+import "github.com/google/uuid"
+
+// ValueUpdateEvent describes a change made to a tracked node by UpdateValue.
+// It carries the ID of the node together with its previous and new values.
+type ValueUpdateEvent[V any] struct {
+	ID  string
+	Old V
+	New V
+}
+
+// listeners maintains a registry of typed event handlers (ID → function).
+type listeners[E any] map[string]func(E)
+
+// emit invokes each registered handler with the provided event.
+func (l listeners[E]) emit(event E) {
+	for _, fn := range l {
+		fn(event)
+	}
+}
+
+// register adds a handler to the registry and returns its unique ID.
+func (l listeners[E]) register(fn func(E)) string {
+	id := uuid.New().String()
+	l[id] = fn
+	return id
+}
+
+// deregister removes the handler with the specified ID, returning an error if
+// it does not exist.
+func (l listeners[E]) deregister(id string) error {
+	if _, exists := l[id]; !exists {
+		return ErrCallbackNotFound
+	}
+	delete(l, id)
+	return nil
+}
+
+// clone returns a copy of the registry.
+func (l listeners[E]) clone() listeners[E] {
+	cloned := make(listeners[E], len(l))
+	for k, v := range l {
+		cloned[k] = v
+	}
+	return cloned
+}
+
+// add registers fn like register, creating the registry first if it is nil.
+func (l *listeners[E]) add(fn func(E)) string {
+	if *l == nil {
+		*l = make(listeners[E])
+	}
+	return l.register(fn)
+}
+
+// HeapEventKind identifies what happened to the element of a HeapEvent.
+type HeapEventKind int
+
+const (
+	// EventPush reports an element entering the heap.
+	EventPush HeapEventKind = iota
+	// EventPop reports an element leaving the top of the heap.
+	EventPop
+	// EventRemove reports an element removed by ID or index.
+	EventRemove
+	// EventUpdate reports an element whose value or priority was replaced.
+	EventUpdate
+	// EventClear reports every element being removed by Clear.
+	EventClear
+)
+
+// String returns the lower-case name of the event kind.
+func (k HeapEventKind) String() string {
+	switch k {
+	case EventPush:
+		return "push"
+	case EventPop:
+		return "pop"
+	case EventRemove:
+		return "remove"
+	case EventUpdate:
+		return "update"
+	case EventClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// HeapEvent describes an element entering, leaving or changing in a heap. ID
+// is set only by heaps that track their elements by ID. For EventUpdate, Value
+// and Priority hold the element's new contents. EventClear carries no element.
+type HeapEvent[V any, P any] struct {
+	Kind     HeapEventKind
+	ID       string
+	Value    V
+	Priority P
+}
+
+// emitHeapEvent reports an event about a single element to l. The event is
+// only built when a listener is registered.
+func emitHeapEvent[V any, P any](l listeners[HeapEvent[V, P]], kind HeapEventKind, id string, value V, priority P) {
+	if len(l) == 0 {
+		return
+	}
+	l.emit(HeapEvent[V, P]{Kind: kind, ID: id, Value: value, Priority: priority})
+}
+
+// emitClearEvent reports to l that the heap was cleared.
+func emitClearEvent[V any, P any](l listeners[HeapEvent[V, P]]) {
+	if len(l) == 0 {
+		return
+	}
+	l.emit(HeapEvent[V, P]{Kind: EventClear})
+}
@@ -0,0 +1,246 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeners_RegisterEmitDeregister(t *testing.T) {
+	l := make(listeners[int])
+	received := make([]int, 0)
+	id := l.register(func(e int) { received = append(received, e) })
+
+	l.emit(1)
+	cloned := l.clone()
+	require.NoError(t, l.deregister(id))
+	assert.ErrorIs(t, l.deregister(id), ErrCallbackNotFound)
+	l.emit(2)
+	cloned.emit(3)
+	assert.Equal(t, []int{1, 3}, received)
+}
+
+func TestTrackedHeaps_OnValueUpdate(t *testing.T) {
+	config := HeapConfig{UsePool: false}
+	heaps := map[string]TrackedHeap[string, int]{
+		"pairing":     NewFullPairingHeap[string, int](nil, lt, config),
+		"syncPairing": NewSyncFullPairingHeap[string, int](nil, lt, config),
+		"leftist":     NewFullLeftistHeap[string, int](nil, lt, config),
+		"syncLeftist": NewSyncFullLeftistHeap[string, int](nil, lt, config),
+		"skew":        NewFullSkewHeap[string, int](nil, lt, config),
+		"syncSkew":    NewSyncFullSkewHeap[string, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		events := make([]ValueUpdateEvent[string], 0)
+		listenerID := heap.OnValueUpdate(func(e ValueUpdateEvent[string]) {
+			events = append(events, e)
+		})
+
+		id, _ := heap.Push("old", 1)
+		require.NoError(t, heap.UpdateValue(id, "new"), name)
+		assert.ErrorIs(t, heap.UpdateValue("missing", "x"), ErrNodeNotFound, name)
+		assert.Equal(t, []ValueUpdateEvent[string]{{ID: id, Old: "old", New: "new"}}, events, name)
+
+		require.NoError(t, heap.RemoveListener(listenerID), name)
+		assert.ErrorIs(t, heap.RemoveListener(listenerID), ErrCallbackNotFound, name)
+		require.NoError(t, heap.UpdateValue(id, "newer"), name)
+		assert.Len(t, events, 1, name)
+	}
+}
+
+// eventHeap is a heap that reports its elements to listeners.
+type eventHeap interface {
+	Heap[int, int]
+	EventSource[int, int]
+}
+
+func TestHeap_Events(t *testing.T) {
+	heaps := map[string]func() eventHeap{
+		"dary":             func() eventHeap { return NewDaryHeap[int, int](3, nil, lt, false) },
+		"syncDary":         func() eventHeap { return NewSyncDaryHeap[int, int](3, nil, lt, true) },
+		"pairing":          func() eventHeap { return NewPairingHeap[int, int](nil, lt, false) },
+		"syncPairing":      func() eventHeap { return NewSyncPairingHeap[int, int](nil, lt, true) },
+		"leftist":          func() eventHeap { return NewLeftistHeap[int, int](nil, lt, false) },
+		"syncLeftist":      func() eventHeap { return NewSyncLeftistHeap[int, int](nil, lt, true) },
+		"skew":             func() eventHeap { return NewSkewHeap[int, int](nil, lt, false) },
+		"syncSkew":         func() eventHeap { return NewSyncSkewHeap[int, int](nil, lt, true) },
+		"binomial":         func() eventHeap { return NewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncBinomial":     func() eventHeap { return NewSyncBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"adaptive":         func() eventHeap { return NewAdaptiveHeap[int, int](nil, lt, HeapConfig{}) },
+		"skewBinomial":     func() eventHeap { return NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncSkewBinomial": func() eventHeap { return NewSyncSkewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		var events []HeapEvent[int, int]
+		id := heap.OnEvent(func(e HeapEvent[int, int]) { events = append(events, e) })
+
+		heap.Push(10, 2)
+		heap.Push(20, 1)
+		heap.Pop()
+		heap.Clear()
+		assert.Equal(t, []HeapEvent[int, int]{
+			{Kind: EventPush, Value: 10, Priority: 2},
+			{Kind: EventPush, Value: 20, Priority: 1},
+			{Kind: EventPop, Value: 20, Priority: 1},
+			{Kind: EventClear},
+		}, events, name)
+
+		require.NoError(t, heap.RemoveListener(id), name)
+		assert.ErrorIs(t, heap.RemoveListener(id), ErrCallbackNotFound, name)
+		heap.Push(30, 3)
+		assert.Len(t, events, 4, name)
+	}
+}
+
+func TestTrackedHeaps_Events(t *testing.T) {
+	config := HeapConfig{UsePool: false}
+	heaps := map[string]TrackedHeap[string, int]{
+		"pairing":     NewFullPairingHeap[string, int](nil, lt, config),
+		"syncPairing": NewSyncFullPairingHeap[string, int](nil, lt, config),
+		"leftist":     NewFullLeftistHeap[string, int](nil, lt, config),
+		"syncLeftist": NewSyncFullLeftistHeap[string, int](nil, lt, config),
+		"skew":        NewFullSkewHeap[string, int](nil, lt, config),
+		"syncSkew":    NewSyncFullSkewHeap[string, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		source, ok := heap.(EventSource[string, int])
+		require.True(t, ok, name)
+		var events []HeapEvent[string, int]
+		listenerID := source.OnEvent(func(e HeapEvent[string, int]) { events = append(events, e) })
+
+		a, _ := heap.Push("a", 3)
+		b, _ := heap.Push("b", 5)
+		require.NoError(t, heap.UpdatePriority(b, 1), name)
+		require.NoError(t, heap.UpdateValue(a, "A"), name)
+		_, _, err := heap.Remove(a)
+		require.NoError(t, err, name)
+		_, _, err = heap.Pop()
+		require.NoError(t, err, name)
+		assert.Equal(t, []HeapEvent[string, int]{
+			{Kind: EventPush, ID: a, Value: "a", Priority: 3},
+			{Kind: EventPush, ID: b, Value: "b", Priority: 5},
+			{Kind: EventUpdate, ID: b, Value: "b", Priority: 1},
+			{Kind: EventUpdate, ID: a, Value: "A", Priority: 3},
+			{Kind: EventRemove, ID: a, Value: "A", Priority: 3},
+			{Kind: EventPop, ID: b, Value: "b", Priority: 1},
+		}, events, name)
+
+		valueID := heap.OnValueUpdate(func(ValueUpdateEvent[string]) {})
+		require.NoError(t, heap.RemoveListener(valueID), name)
+		require.NoError(t, heap.RemoveListener(listenerID), name)
+		assert.ErrorIs(t, heap.RemoveListener(listenerID), ErrCallbackNotFound, name)
+	}
+}
+
+func TestHeap_EventsMeldAndRadix(t *testing.T) {
+	h := NewPairingHeap[int, int](nil, lt, false)
+	other := NewPairingHeap([]HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}, lt, false)
+	var kinds []string
+	h.OnEvent(func(e HeapEvent[int, int]) { kinds = append(kinds, e.Kind.String()) })
+	h.PushAll([]HeapNode[int, int]{CreateHeapNode(3, 3), CreateHeapNode(4, 4)})
+	h.Meld(other)
+	h.PopPush(5, 5)
+	assert.Equal(t, []string{"push", "push", "push", "push", "pop", "push"}, kinds)
+
+	r := NewRadixHeap[int, uint](nil, false)
+	var popped []uint
+	r.OnEvent(func(e HeapEvent[int, uint]) {
+		if e.Kind == EventPop {
+			popped = append(popped, e.Priority)
+		}
+	})
+	for _, p := range []uint{1, 4, 7, 2} {
+		require.NoError(t, r.Push(int(p), p))
+	}
+	_, err := r.AdvanceTo(5)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 4}, popped)
+}
+
+func TestHeap_EventsIDAndRangeHeaps(t *testing.T) {
+	indexed := NewIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	var indexedEvents []HeapEvent[int, int]
+	indexed.OnEvent(func(e HeapEvent[int, int]) { indexedEvents = append(indexedEvents, e) })
+	a, err := indexed.Push(10, 2)
+	require.NoError(t, err)
+	b, err := indexed.Push(20, 3)
+	require.NoError(t, err)
+	require.NoError(t, indexed.UpdateByID(b, 21, 1))
+	_, _, err = indexed.Pop()
+	require.NoError(t, err)
+	indexed.Clear()
+	assert.Equal(t, []HeapEvent[int, int]{
+		{Kind: EventPush, ID: a, Value: 10, Priority: 2},
+		{Kind: EventPush, ID: b, Value: 20, Priority: 3},
+		{Kind: EventUpdate, ID: b, Value: 21, Priority: 1},
+		{Kind: EventPop, ID: b, Value: 21, Priority: 1},
+		{Kind: EventClear},
+	}, indexedEvents)
+
+	keyed := NewKeyedHeap[string, int, int](2, lt, DaryHeapConfig{})
+	var keyedEvents []HeapEvent[int, int]
+	keyed.OnEvent(func(e HeapEvent[int, int]) { keyedEvents = append(keyedEvents, e) })
+	keyed.Push("a", 10, 2)
+	keyed.Push("a", 11, 4)
+	require.NoError(t, keyed.UpdatePriority("a", 1))
+	_, _, _, err = keyed.PopKey()
+	require.NoError(t, err)
+	assert.Equal(t, []HeapEvent[int, int]{
+		{Kind: EventPush, Value: 10, Priority: 2},
+		{Kind: EventUpdate, Value: 11, Priority: 4},
+		{Kind: EventUpdate, Value: 11, Priority: 1},
+		{Kind: EventPop, Value: 11, Priority: 1},
+	}, keyedEvents)
+
+	intervals, err := NewIntervalHeap[int, int](nil, HeapConfig{})
+	require.NoError(t, err)
+	var intervalEvents []HeapEvent[int, int]
+	intervals.OnEvent(func(e HeapEvent[int, int]) { intervalEvents = append(intervalEvents, e) })
+	require.NoError(t, intervals.Push(1, 5, 9))
+	_, err = intervals.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, []HeapEvent[int, int]{
+		{Kind: EventPush, Value: 1, Priority: 5},
+		{Kind: EventPop, Value: 1, Priority: 5},
+	}, intervalEvents)
+
+	radix := NewMultiLevelRadixHeap[int, uint](nil, 4, RadixHeapConfig{})
+	var radixKinds []string
+	radix.OnEvent(func(e HeapEvent[int, uint]) { radixKinds = append(radixKinds, e.Kind.String()) })
+	require.NoError(t, radix.Push(1, 3))
+	require.NoError(t, radix.Push(2, 3))
+	_, err = radix.PopEqual()
+	require.NoError(t, err)
+	radix.Clear()
+	assert.Equal(t, []string{"push", "push", "pop", "pop", "clear"}, radixKinds)
+}
+
+func TestHeap_EventsMeldSkewBinomialAndSoft(t *testing.T) {
+	nodes := []HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}
+
+	sb := NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{})
+	var sbKinds []string
+	sb.OnEvent(func(e HeapEvent[int, int]) { sbKinds = append(sbKinds, e.Kind.String()) })
+	sb.Push(3, 3)
+	sb.Meld(NewSkewBinomialHeap(nodes, lt, HeapConfig{}))
+	_, _, err := sb.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"push", "push", "push", "pop"}, sbKinds)
+
+	soft := NewSoftHeap[int, int](nil, lt, 0.1)
+	var softKinds []string
+	id := soft.OnEvent(func(e HeapEvent[int, int]) { softKinds = append(softKinds, e.Kind.String()) })
+	soft.Push(3, 3)
+	soft.Meld(NewSoftHeap(nodes, lt, 0.1))
+	_, _, err = soft.Pop()
+	require.NoError(t, err)
+	soft.Clear()
+	assert.Equal(t, []string{"push", "push", "push", "pop", "clear"}, softKinds)
+	require.NoError(t, soft.RemoveListener(id))
+	assert.ErrorIs(t, soft.RemoveListener(id), ErrCallbackNotFound)
+}
@@ -8,7 +8,7 @@ import (
 
 func DaryHeapExample() {
 	// Create a binary heap (d=2) with min-heap ordering
-	heap := heapcraft.NewBinaryHeap[int](nil, func(a, b int) bool { return a < b }, false)
+	heap := heapcraft.NewBinaryHeapWithConfig[int](nil, func(a, b int) bool { return a < b }, heapcraft.DaryHeapConfig{})
 
 	// Push some elements
 	elements := []struct {
@@ -38,7 +38,7 @@ func DaryHeapExample() {
 	}
 
 	// Example with a 3-ary heap
-	heap3 := heapcraft.NewDaryHeap[int](3, nil, func(a, b int) bool { return a > b }, false)
+	heap3 := heapcraft.NewDaryHeapWithConfig[int](3, nil, func(a, b int) bool { return a > b }, heapcraft.DaryHeapConfig{})
 
 	// Push elements
 	for _, elem := range elements {
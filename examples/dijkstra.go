@@ -0,0 +1,50 @@
+package examples
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/galactixx/heapcraft"
+)
+
+func DijkstraExample() {
+	// A small road network with distances in kilometres
+	roads := map[string]map[string]int{
+		"Amsterdam": {"Utrecht": 45, "Haarlem": 20},
+		"Haarlem":   {"Leiden": 30},
+		"Leiden":    {"The Hague": 20},
+		"Utrecht":   {"Rotterdam": 60, "The Hague": 65},
+		"The Hague": {"Rotterdam": 25},
+	}
+	neighbors := func(city string) iter.Seq2[string, int] {
+		return func(yield func(string, int) bool) {
+			for next, distance := range roads[city] {
+				if !yield(next, distance) {
+					return
+				}
+			}
+		}
+	}
+
+	// Compute shortest paths from Amsterdam to every reachable city
+	paths := heapcraft.Dijkstra("Amsterdam", neighbors)
+	if distance, ok := paths.Distance("Rotterdam"); ok {
+		fmt.Printf("Amsterdam -> Rotterdam: %d km via %v\n", distance, paths.PathTo("Rotterdam"))
+	}
+
+	// The same search by hand with an AddressableMinQueue, relaxing edges by
+	// city name instead of by heap node ID
+	queue := heapcraft.NewAddressableMinQueue[string](func(a, b int) bool { return a < b }, heapcraft.HeapConfig{})
+	queue.Push("Amsterdam", 0)
+	settled := make(map[string]int)
+	for !queue.IsEmpty() {
+		city, distance, _ := queue.Pop()
+		settled[city] = distance
+		for next, length := range neighbors(city) {
+			if _, done := settled[next]; !done {
+				queue.Relax(next, distance+length)
+			}
+		}
+	}
+	fmt.Printf("Settled %d cities, The Hague at %d km\n", len(settled), settled["The Hague"])
+}
@@ -0,0 +1,93 @@
+package examples
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/galactixx/heapcraft"
+)
+
+// JobSchedulerExample runs a DB-backed scheduler with an in-memory heap. It
+// expects a jobs table like:
+//
+//	CREATE TABLE jobs (
+//	    id       INTEGER PRIMARY KEY,
+//	    name     TEXT NOT NULL,
+//	    priority INTEGER NOT NULL,
+//	    status   TEXT NOT NULL DEFAULT 'pending'
+//	);
+//
+// Any database/sql driver works; the queries use ? placeholders.
+func JobSchedulerExample(ctx context.Context, db *sql.DB) error {
+	// Jobs with a lower priority number run first
+	heap := heapcraft.NewSyncFullPairingHeap[string, int](
+		nil,
+		func(a, b int) bool { return a < b },
+		heapcraft.HeapConfig{IDGenerator: &heapcraft.IntegerIDGenerator{}},
+	)
+
+	// Hydrate the heap from the pending jobs, using each row's primary key as
+	// its node ID so the heap and the table refer to jobs the same way
+	rows, err := db.QueryContext(ctx, "SELECT id, name, priority FROM jobs WHERE status = 'pending'")
+	if err != nil {
+		return err
+	}
+	loaded, err := heapcraft.LoadRows(heap, rows, func(rows heapcraft.RowScanner) (string, string, int, error) {
+		var (
+			id       string
+			name     string
+			priority int
+		)
+		err := rows.Scan(&id, &name, &priority)
+		return id, name, priority, err
+	})
+	rows.Close()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Loaded %d pending jobs\n", loaded)
+
+	// A job re-prioritised in the table is updated in the heap by the same ID
+	if _, err := db.ExecContext(ctx, "UPDATE jobs SET priority = 0 WHERE id = 42"); err != nil {
+		return err
+	}
+	if _, err := heap.GetValue("42"); err == nil {
+		heap.UpdatePriority("42", 0)
+	}
+
+	// Claim jobs in priority order. The row is marked as running before the
+	// job leaves the heap, so a failed write leaves the job queued. If no row
+	// was updated, another worker claimed the job first: it still leaves this
+	// heap, but must not be run here as well
+	claimed := false
+	claim := func(id string, name string, priority int) error {
+		result, err := db.ExecContext(ctx, "UPDATE jobs SET status = 'running' WHERE id = ? AND status = 'pending'", id)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = affected == 1
+		return nil
+	}
+	for !heap.IsEmpty() {
+		id, name, _, err := heap.PopCommit(claim)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			fmt.Printf("Skipping job %s (%s), claimed by another worker\n", id, name)
+			continue
+		}
+		fmt.Printf("Running job %s (%s)\n", id, name)
+
+		// Record the outcome once the work is done
+		if _, err := db.ExecContext(ctx, "UPDATE jobs SET status = 'done' WHERE id = ?", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package examples
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// fakeJob is a row of the jobs table held by fakeJobStore.
+type fakeJob struct {
+	id       int64
+	name     string
+	priority int64
+	status   string
+}
+
+// fakeJobStore is an in-memory jobs table that understands exactly the
+// queries JobSchedulerExample runs. afterSelect, if set, runs once the
+// pending jobs have been read, to simulate another worker changing rows.
+type fakeJobStore struct {
+	mu          sync.Mutex
+	jobs        map[int64]*fakeJob
+	afterSelect func(jobs map[int64]*fakeJob)
+}
+
+var (
+	fakeStoresMu sync.Mutex
+	fakeStores   = map[string]*fakeJobStore{}
+)
+
+func init() { sql.Register("heapcraft-fakejobs", fakeJobDriver{}) }
+
+// openFakeJobs returns a *sql.DB backed by store.
+func openFakeJobs(name string, store *fakeJobStore) (*sql.DB, error) {
+	fakeStoresMu.Lock()
+	fakeStores[name] = store
+	fakeStoresMu.Unlock()
+	return sql.Open("heapcraft-fakejobs", name)
+}
+
+type fakeJobDriver struct{}
+
+func (fakeJobDriver) Open(name string) (driver.Conn, error) {
+	fakeStoresMu.Lock()
+	defer fakeStoresMu.Unlock()
+	store, ok := fakeStores[name]
+	if !ok {
+		return nil, fmt.Errorf("no fake job store named %q", name)
+	}
+	return &fakeJobConn{store: store}, nil
+}
+
+type fakeJobConn struct{ store *fakeJobStore }
+
+func (c *fakeJobConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeJobStmt{store: c.store, query: query}, nil
+}
+
+func (c *fakeJobConn) Close() error { return nil }
+
+func (c *fakeJobConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeJobStmt struct {
+	store *fakeJobStore
+	query string
+}
+
+func (s *fakeJobStmt) Close() error  { return nil }
+func (s *fakeJobStmt) NumInput() int { return -1 }
+
+func (s *fakeJobStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	var id int64
+	if len(args) == 1 {
+		if _, err := fmt.Sscan(fmt.Sprint(args[0]), &id); err != nil {
+			return nil, err
+		}
+	}
+	affected := int64(0)
+	switch s.query {
+	case "UPDATE jobs SET priority = 0 WHERE id = 42":
+		if job, ok := s.store.jobs[42]; ok {
+			job.priority = 0
+			affected = 1
+		}
+	case "UPDATE jobs SET status = 'running' WHERE id = ? AND status = 'pending'":
+		if job, ok := s.store.jobs[id]; ok && job.status == "pending" {
+			job.status = "running"
+			affected = 1
+		}
+	case "UPDATE jobs SET status = 'done' WHERE id = ?":
+		if job, ok := s.store.jobs[id]; ok {
+			job.status = "done"
+			affected = 1
+		}
+	default:
+		return nil, fmt.Errorf("unexpected statement %q", s.query)
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+func (s *fakeJobStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query != "SELECT id, name, priority FROM jobs WHERE status = 'pending'" {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	rows := &fakeJobRows{}
+	for _, job := range s.store.jobs {
+		if job.status == "pending" {
+			rows.values = append(rows.values, []driver.Value{job.id, job.name, job.priority})
+		}
+	}
+	sort.Slice(rows.values, func(i, j int) bool { return rows.values[i][0].(int64) < rows.values[j][0].(int64) })
+	if s.store.afterSelect != nil {
+		s.store.afterSelect(s.store.jobs)
+	}
+	return rows, nil
+}
+
+type fakeJobRows struct{ values [][]driver.Value }
+
+func (r *fakeJobRows) Columns() []string { return []string{"id", "name", "priority"} }
+func (r *fakeJobRows) Close() error      { return nil }
+
+func (r *fakeJobRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func ExampleJobSchedulerExample() {
+	store := &fakeJobStore{
+		jobs: map[int64]*fakeJob{
+			1:  {id: 1, name: "backup", priority: 5, status: "pending"},
+			2:  {id: 2, name: "email", priority: 1, status: "pending"},
+			3:  {id: 3, name: "report", priority: 3, status: "pending"},
+			42: {id: 42, name: "cleanup", priority: 9, status: "pending"},
+		},
+		// Another worker claims the report job after it has been loaded.
+		afterSelect: func(jobs map[int64]*fakeJob) { jobs[3].status = "running" },
+	}
+	db, err := openFakeJobs("scheduler", store)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer db.Close()
+
+	if err := JobSchedulerExample(context.Background(), db); err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, id := range []int64{1, 2, 3, 42} {
+		fmt.Printf("job %d is %s\n", id, store.jobs[id].status)
+	}
+	// Output:
+	// Loaded 4 pending jobs
+	// Running job 42 (cleanup)
+	// Running job 2 (email)
+	// Skipping job 3 (report), claimed by another worker
+	// Running job 1 (backup)
+	// job 1 is done
+	// job 2 is done
+	// job 3 is running
+	// job 42 is done
+}
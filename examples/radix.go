@@ -8,7 +8,7 @@ import (
 
 func RadixHeapExample() {
 	// Create a radix heap (only works with unsigned integers)
-	heap := heapcraft.NewRadixHeap[int, uint](nil, false)
+	heap := heapcraft.NewRadixHeapWithConfig[int, uint](nil, heapcraft.RadixHeapConfig{})
 
 	// Push some elements with unsigned integer priorities
 	elements := []struct {
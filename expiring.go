@@ -0,0 +1,232 @@
+package heapcraft
+
+import (
+	"context"
+	"time"
+)
+
+// expiringEntry is a value stored in an ExpiringHeap together with the time
+// after which it is no longer returned. A zero deadline never expires.
+type expiringEntry[V any] struct {
+	value    V
+	deadline time.Time
+}
+
+// ExpiringHeap is a heap whose elements carry a deadline. Expired elements are
+// never returned: Pop and Peek transparently discard expired elements that
+// reach the root, and Sweep discards every expired element at once. Handlers
+// registered with OnExpire are told about each discarded element, which makes
+// the heap usable as a cache-expiry or timeout manager. Elements are ordered
+// by priority, not by deadline. The heap is not safe for concurrent use; use
+// SyncExpiringHeap when sweeping from a background goroutine.
+type ExpiringHeap[V any, P any] struct {
+	heap     *DaryHeap[expiringEntry[V], P]
+	now      func() time.Time
+	onExpire listeners[HeapNode[V, P]]
+}
+
+// expired reports whether the entry's deadline has passed at time now.
+func (e *ExpiringHeap[V, P]) expired(entry expiringEntry[V], now time.Time) bool {
+	return !entry.deadline.IsZero() && !now.Before(entry.deadline)
+}
+
+// reap notifies the expiry handlers of an expired node.
+func (e *ExpiringHeap[V, P]) reap(node HeapNode[expiringEntry[V], P]) {
+	e.onExpire.emit(HeapNode[V, P]{value: node.value.value, priority: node.priority})
+}
+
+// OnExpire registers fn to be called with every expired element the heap
+// discards, and returns an ID that can be passed to RemoveListener.
+func (e *ExpiringHeap[V, P]) OnExpire(fn func(node HeapNode[V, P])) string {
+	return e.onExpire.register(fn)
+}
+
+// RemoveListener removes the expiry handler with the specified ID. Returns
+// an error if no handler exists with the given ID.
+func (e *ExpiringHeap[V, P]) RemoveListener(id string) error {
+	return e.onExpire.deregister(id)
+}
+
+// Push inserts an element that expires ttl from now. A ttl of zero or less
+// means the element never expires.
+func (e *ExpiringHeap[V, P]) Push(value V, priority P, ttl time.Duration) {
+	var deadline time.Time
+	if ttl > 0 {
+		deadline = e.now().Add(ttl)
+	}
+	e.PushWithDeadline(value, priority, deadline)
+}
+
+// PushWithDeadline inserts an element that expires at deadline. A zero
+// deadline means the element never expires.
+func (e *ExpiringHeap[V, P]) PushWithDeadline(value V, priority P, deadline time.Time) {
+	e.heap.Push(expiringEntry[V]{value: value, deadline: deadline}, priority)
+}
+
+// skipExpired discards expired elements from the root until the root is live
+// or the heap is empty.
+func (e *ExpiringHeap[V, P]) skipExpired() {
+	now := e.now()
+	for e.heap.Length() > 0 && e.expired(e.heap.data[0].value, now) {
+		e.reap(e.heap.swapWithLastAndRemove(0))
+	}
+}
+
+// Sweep discards every expired element and returns how many were discarded.
+// It runs in O(n) and is intended to be called periodically, directly or
+// through Maintain, so that expired elements below the root do not hold on to
+// memory until they are popped.
+func (e *ExpiringHeap[V, P]) Sweep() int {
+	now := e.now()
+	data := e.heap.data
+	kept := data[:0]
+	var reaped []HeapNode[expiringEntry[V], P]
+	for _, node := range data {
+		if e.expired(node.value, now) {
+			reaped = append(reaped, node)
+		} else {
+			kept = append(kept, node)
+		}
+	}
+	if len(reaped) == 0 {
+		return 0
+	}
+
+	clear(data[len(kept):])
+	e.heap.data = kept
+	e.heap.heapify()
+	for _, node := range reaped {
+		e.reap(node)
+	}
+	return len(reaped)
+}
+
+// Maintain sweeps expired elements from the heap, allowing it to be registered
+// with a Maintenance registry for background expiry. Returns the context
+// error if ctx is already done.
+func (e *ExpiringHeap[V, P]) Maintain(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e.Sweep()
+	return nil
+}
+
+// Clear removes all elements from the heap without notifying the expiry
+// handlers.
+func (e *ExpiringHeap[V, P]) Clear() { e.heap.Clear() }
+
+// Length returns the number of elements in the heap. Expired elements count
+// until they are discarded by Pop, Peek or Sweep.
+func (e *ExpiringHeap[V, P]) Length() int { return e.heap.Length() }
+
+// IsEmpty returns true if the heap contains no elements, including expired
+// elements that have not been discarded yet.
+func (e *ExpiringHeap[V, P]) IsEmpty() bool { return e.heap.IsEmpty() }
+
+// Verify checks the internal consistency of the underlying d-ary heap. It is
+// intended for tests and debugging and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (e *ExpiringHeap[V, P]) Verify() error { return e.heap.Verify() }
+
+// peek is an internal method that discards expired elements at the root and
+// returns the first live element without removing it.
+func (e *ExpiringHeap[V, P]) peek() (V, P, error) {
+	e.skipExpired()
+	entry, priority, err := e.heap.Peek()
+	return entry.value, priority, err
+}
+
+// Peek returns the value and priority of the best live element without
+// removing it. Expired elements at the root are discarded. Returns zero values
+// and an error if no live element remains.
+func (e *ExpiringHeap[V, P]) Peek() (V, P, error) { return e.peek() }
+
+// PeekValue returns the value of the best live element without removing it.
+// Returns zero value and an error if no live element remains.
+func (e *ExpiringHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(e.peek())
+}
+
+// PeekPriority returns the priority of the best live element without removing
+// it. Returns zero value and an error if no live element remains.
+func (e *ExpiringHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(e.peek())
+}
+
+// popRoot removes and returns the root element whether or not it has expired.
+func (e *ExpiringHeap[V, P]) popRoot() (V, P, error) {
+	entry, priority, err := e.heap.Pop()
+	return entry.value, priority, err
+}
+
+// pop is an internal method that discards expired elements at the root and
+// removes and returns the first live element.
+func (e *ExpiringHeap[V, P]) pop() (V, P, error) {
+	e.skipExpired()
+	return e.popRoot()
+}
+
+// Pop removes and returns the value and priority of the best live element,
+// discarding any expired elements that precede it. Returns zero values and an
+// error if no live element remains.
+func (e *ExpiringHeap[V, P]) Pop() (V, P, error) { return e.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (e *ExpiringHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(e.Peek, e.Pop, pred)
+}
+
+// PopValue removes and returns the value of the best live element. Returns
+// zero value and an error if no live element remains.
+func (e *ExpiringHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(e.pop())
+}
+
+// PopPriority removes and returns the priority of the best live element.
+// Returns zero value and an error if no live element remains.
+func (e *ExpiringHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(e.pop())
+}
+
+// Drain discards expired elements and removes and returns the live ones in
+// priority order. Elements that expire while the heap is being drained are
+// still returned.
+func (e *ExpiringHeap[V, P]) Drain() []HeapNode[V, P] {
+	e.Sweep()
+	return drainNodes(e.Length(), e.popRoot)
+}
+
+// DrainValues discards expired elements and removes and returns the values of
+// the live ones in priority order.
+func (e *ExpiringHeap[V, P]) DrainValues() []V {
+	e.Sweep()
+	return drainValues(e.Length(), e.popRoot)
+}
+
+// DrainPriorities discards expired elements and removes and returns the
+// priorities of the live ones in priority order.
+func (e *ExpiringHeap[V, P]) DrainPriorities() []P {
+	e.Sweep()
+	return drainPriorities(e.Length(), e.popRoot)
+}
+
+// forEach calls fn for every live element in internal order.
+func (e *ExpiringHeap[V, P]) forEach(fn func(v V, p P)) {
+	now := e.now()
+	for _, node := range e.heap.data {
+		if !e.expired(node.value, now) {
+			fn(node.value.value, node.priority)
+		}
+	}
+}
+
+// Export returns a copy of the live elements of the heap according to opts.
+// Expired elements are skipped but not discarded, and the heap itself is not
+// modified.
+func (e *ExpiringHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(e.Length(), e.forEach, e.heap.cmp, opts)
+}
@@ -0,0 +1,26 @@
+package heapcraft
+
+import "time"
+
+// NewExpiringHeap creates an empty ExpiringHeap. The comparison function
+// determines the heap order (min or max). now is the clock used to decide
+// whether an element has expired; if nil, time.Now is used. config sets the
+// options of the d-ary heap the elements are kept in.
+func NewExpiringHeap[V any, P any](cmp func(a, b P) bool, now func() time.Time, config DaryHeapConfig) *ExpiringHeap[V, P] {
+	if now == nil {
+		now = time.Now
+	}
+	return &ExpiringHeap[V, P]{
+		heap:     NewDaryHeapWithConfig[expiringEntry[V], P](2, nil, cmp, config),
+		now:      now,
+		onExpire: make(listeners[HeapNode[V, P]]),
+	}
+}
+
+// NewSyncExpiringHeap creates an empty thread-safe ExpiringHeap. The
+// comparison function determines the heap order (min or max). now is the
+// clock used to decide whether an element has expired; if nil, time.Now is
+// used.
+func NewSyncExpiringHeap[V any, P any](cmp func(a, b P) bool, now func() time.Time, config DaryHeapConfig) *SyncExpiringHeap[V, P] {
+	return &SyncExpiringHeap[V, P]{heap: NewExpiringHeap[V, P](cmp, now, config)}
+}
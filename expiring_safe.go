@@ -0,0 +1,184 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SyncExpiringHeap is a thread-safe wrapper around ExpiringHeap. Every
+// operation takes an exclusive lock, since even Peek may discard expired
+// elements. It is the variant to use when a Maintenance registry sweeps the
+// heap from a background goroutine.
+type SyncExpiringHeap[V any, P any] struct {
+	heap *ExpiringHeap[V, P]
+	mu   sync.Mutex
+}
+
+// OnExpire registers fn to be called with every expired element the heap
+// discards, and returns an ID that can be passed to RemoveListener. fn runs
+// while the heap is locked and must not call back into it.
+func (s *SyncExpiringHeap[V, P]) OnExpire(fn func(node HeapNode[V, P])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnExpire(fn)
+}
+
+// RemoveListener removes the expiry handler with the specified ID. Returns
+// an error if no handler exists with the given ID.
+func (s *SyncExpiringHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
+// Push inserts an element that expires ttl from now. A ttl of zero or less
+// means the element never expires.
+func (s *SyncExpiringHeap[V, P]) Push(value V, priority P, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Push(value, priority, ttl)
+}
+
+// PushWithDeadline inserts an element that expires at deadline. A zero
+// deadline means the element never expires.
+func (s *SyncExpiringHeap[V, P]) PushWithDeadline(value V, priority P, deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.PushWithDeadline(value, priority, deadline)
+}
+
+// Sweep discards every expired element and returns how many were discarded.
+func (s *SyncExpiringHeap[V, P]) Sweep() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Sweep()
+}
+
+// Maintain sweeps expired elements from the heap, allowing it to be registered
+// with a Maintenance registry for background expiry. Returns the context
+// error if ctx is already done.
+func (s *SyncExpiringHeap[V, P]) Maintain(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Maintain(ctx)
+}
+
+// Clear removes all elements from the heap without notifying the expiry
+// handlers.
+func (s *SyncExpiringHeap[V, P]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Clear()
+}
+
+// Length returns the number of elements in the heap. Expired elements count
+// until they are discarded by Pop, Peek or Sweep.
+func (s *SyncExpiringHeap[V, P]) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements, including expired
+// elements that have not been discarded yet.
+func (s *SyncExpiringHeap[V, P]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under the heap lock. It is
+// intended for tests and debugging.
+func (s *SyncExpiringHeap[V, P]) Verify() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Verify()
+}
+
+// Peek returns the value and priority of the best live element without
+// removing it. Expired elements at the root are discarded.
+func (s *SyncExpiringHeap[V, P]) Peek() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Peek()
+}
+
+// PeekValue returns the value of the best live element without removing it.
+func (s *SyncExpiringHeap[V, P]) PeekValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns the priority of the best live element without removing
+// it.
+func (s *SyncExpiringHeap[V, P]) PeekPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and priority of the best live element,
+// discarding any expired elements that precede it.
+func (s *SyncExpiringHeap[V, P]) Pop() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncExpiringHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopValue removes and returns the value of the best live element.
+func (s *SyncExpiringHeap[V, P]) PopValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns the priority of the best live element.
+func (s *SyncExpiringHeap[V, P]) PopPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPriority()
+}
+
+// Drain discards expired elements and removes and returns the live ones in
+// priority order.
+func (s *SyncExpiringHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues discards expired elements and removes and returns the values of
+// the live ones in priority order.
+func (s *SyncExpiringHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities discards expired elements and removes and returns the
+// priorities of the live ones in priority order.
+func (s *SyncExpiringHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the live elements of the heap according to opts.
+func (s *SyncExpiringHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Export(opts)
+}
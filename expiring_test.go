@@ -0,0 +1,124 @@
+package heapcraft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for expiry tests.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+func newFakeClock() *fakeClock               { return &fakeClock{now: time.Unix(1_700_000_000, 0)} }
+
+func TestExpiringHeap_PopSkipsExpired(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewExpiringHeap[string, int](lt, clock.Now, DaryHeapConfig{})
+	var expired []string
+	heap.OnExpire(func(node HeapNode[string, int]) { expired = append(expired, node.Value()) })
+
+	heap.Push("a", 1, time.Second)
+	heap.Push("b", 2, time.Minute)
+	heap.Push("c", 3, 0)
+	heap.PushWithDeadline("d", 0, clock.Now().Add(time.Second))
+	assert.Equal(t, 4, heap.Length())
+
+	clock.Advance(time.Second)
+	value, priority, err := heap.Peek()
+	require.Nil(t, err)
+	assert.Equal(t, "b", value)
+	assert.Equal(t, 2, priority)
+	assert.ElementsMatch(t, []string{"a", "d"}, expired)
+	assert.Equal(t, 2, heap.Length())
+
+	clock.Advance(time.Hour)
+	value, err = heap.PopValue()
+	require.Nil(t, err)
+	assert.Equal(t, "c", value)
+
+	_, err = heap.PopValue()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+	require.Len(t, expired, 3)
+	assert.Equal(t, "b", expired[2])
+}
+
+func TestExpiringHeap_Sweep(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewExpiringHeap[int, int](lt, clock.Now, DaryHeapConfig{})
+	reaped := 0
+	id := heap.OnExpire(func(HeapNode[int, int]) { reaped++ })
+
+	for i := 0; i < 100; i++ {
+		ttl := time.Minute
+		if i%3 == 0 {
+			ttl = time.Second
+		}
+		heap.Push(i, 100-i, ttl)
+	}
+
+	clock.Advance(time.Second)
+	assert.Equal(t, 34, heap.Sweep())
+	assert.Equal(t, 34, reaped)
+	assert.Equal(t, 66, heap.Length())
+	assert.Equal(t, 0, heap.Sweep())
+
+	priorities := heap.DrainPriorities()
+	require.Len(t, priorities, 66)
+	assert.IsNonDecreasing(t, priorities)
+	for _, p := range priorities {
+		assert.NotZero(t, (100-p)%3)
+	}
+
+	require.Nil(t, heap.RemoveListener(id))
+	assert.ErrorIs(t, heap.RemoveListener(id), ErrCallbackNotFound)
+}
+
+func TestExpiringHeap_Export(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewExpiringHeap[string, int](lt, clock.Now, DaryHeapConfig{})
+	heap.Push("a", 3, time.Second)
+	heap.Push("b", 2, time.Minute)
+	heap.Push("c", 1, time.Second)
+
+	clock.Advance(time.Second)
+	nodes := heap.Export(ExportOptions[string, int]{})
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "b", nodes[0].Value())
+	assert.Equal(t, 3, heap.Length())
+
+	heap.Clear()
+	assert.True(t, heap.IsEmpty())
+}
+
+func TestSyncExpiringHeap_Maintain(t *testing.T) {
+	clock := newFakeClock()
+	heap := NewSyncExpiringHeap[string, int](lt, clock.Now, DaryHeapConfig{})
+	heap.Push("a", 1, time.Second)
+	heap.Push("b", 2, 0)
+
+	maintenance := NewMaintenance()
+	maintenance.RegisterHeap(heap)
+	clock.Advance(time.Second)
+	require.Nil(t, maintenance.Maintain(context.Background()))
+	assert.Equal(t, 1, heap.Length())
+	assert.Equal(t, []string{"b"}, heap.DrainValues())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, heap.Maintain(ctx), context.Canceled)
+}
+
+func TestNewExpiringHeap_DefaultClock(t *testing.T) {
+	heap := NewExpiringHeap[string, int](lt, nil, DaryHeapConfig{})
+	heap.Push("a", 1, time.Hour)
+	heap.PushWithDeadline("b", 0, time.Now().Add(-time.Second))
+
+	value, err := heap.PopValue()
+	require.Nil(t, err)
+	assert.Equal(t, "a", value)
+}
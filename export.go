@@ -0,0 +1,56 @@
+package heapcraft
+
+import "slices"
+
+// ExportOrder determines the order in which Export returns elements.
+type ExportOrder int
+
+const (
+	// BestFirst returns elements in the order they would be popped.
+	BestFirst ExportOrder = iota
+	// WorstFirst returns elements in the reverse of the order they would be
+	// popped.
+	WorstFirst
+)
+
+// ExportOptions configures an Export call.
+type ExportOptions[V any, P any] struct {
+	// Limit is the maximum number of elements to return. Zero or a negative
+	// value means no limit.
+	Limit int
+	// Filter, if not nil, is called for every element and only elements for
+	// which it returns true are exported.
+	Filter func(value V, priority P) bool
+	// Order determines whether the best or the worst elements come first.
+	Order ExportOrder
+}
+
+// exportNodes collects the elements visited by forEach that pass the filter,
+// sorts them according to cmp and the requested order, and truncates the
+// result to the limit. The heap itself is not modified.
+func exportNodes[V any, P any](n int, forEach func(fn func(v V, p P)), cmp func(a, b P) bool, opts ExportOptions[V, P]) []HeapNode[V, P] {
+	nodes := make([]HeapNode[V, P], 0, n)
+	forEach(func(v V, p P) {
+		if opts.Filter == nil || opts.Filter(v, p) {
+			nodes = append(nodes, HeapNode[V, P]{value: v, priority: p})
+		}
+	})
+
+	slices.SortStableFunc(nodes, func(a, b HeapNode[V, P]) int {
+		switch {
+		case cmp(a.priority, b.priority):
+			return -1
+		case cmp(b.priority, a.priority):
+			return 1
+		}
+		return 0
+	})
+	if opts.Order == WorstFirst {
+		slices.Reverse(nodes)
+	}
+
+	if opts.Limit > 0 && len(nodes) > opts.Limit {
+		nodes = nodes[:opts.Limit:opts.Limit]
+	}
+	return nodes
+}
@@ -0,0 +1,70 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport_Options(t *testing.T) {
+	data := []HeapNode[string, int]{
+		CreateHeapNode("e", 5),
+		CreateHeapNode("a", 1),
+		CreateHeapNode("d", 4),
+		CreateHeapNode("b", 2),
+		CreateHeapNode("c", 3),
+		CreateHeapNode("f", 6),
+	}
+	heap := NewPairingHeap(data, lt, false)
+
+	all := heap.Export(ExportOptions[string, int]{})
+	assert.Equal(t, []HeapNode[string, int]{
+		CreateHeapNode("a", 1),
+		CreateHeapNode("b", 2),
+		CreateHeapNode("c", 3),
+		CreateHeapNode("d", 4),
+		CreateHeapNode("e", 5),
+		CreateHeapNode("f", 6),
+	}, all)
+	assert.Equal(t, 6, heap.Length())
+
+	even := func(_ string, p int) bool { return p%2 == 0 }
+	assert.Equal(t, []HeapNode[string, int]{
+		CreateHeapNode("f", 6),
+		CreateHeapNode("d", 4),
+	}, heap.Export(ExportOptions[string, int]{Filter: even, Order: WorstFirst, Limit: 2}))
+
+	assert.Equal(t, []HeapNode[string, int]{
+		CreateHeapNode("b", 2),
+		CreateHeapNode("d", 4),
+		CreateHeapNode("f", 6),
+	}, heap.Export(ExportOptions[string, int]{Filter: even, Limit: 10}))
+
+	none := func(string, int) bool { return false }
+	assert.Empty(t, heap.Export(ExportOptions[string, int]{Filter: none}))
+
+	value, _ := heap.PeekValue()
+	assert.Equal(t, "a", value)
+}
+
+func TestExport_MaxHeapAndTracked(t *testing.T) {
+	heap := NewFullSkewHeap[string, int](nil, gt, HeapConfig{})
+	heap.Push("low", 1)
+	heap.Push("high", 9)
+	heap.Push("mid", 5)
+
+	assert.Equal(t, []HeapNode[string, int]{
+		CreateHeapNode("high", 9),
+		CreateHeapNode("mid", 5),
+	}, heap.Export(ExportOptions[string, int]{Limit: 2}))
+	assert.Equal(t, 3, heap.Length())
+}
+
+func TestExport_AdaptiveBothModes(t *testing.T) {
+	heap := NewAdaptiveHeap[int, int](nil, lt, HeapConfig{})
+	for i := 20; i > 0; i-- {
+		heap.Push(i, i)
+		exported := heap.Export(ExportOptions[int, int]{Limit: 1})
+		assert.Equal(t, []HeapNode[int, int]{CreateHeapNode(i, i)}, exported)
+	}
+}
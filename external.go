@@ -0,0 +1,412 @@
+package heapcraft
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+)
+
+// ExternalStorage is the backend of an ExternalHeap, such as an *os.File.
+// Reads past the end of the written data must return io.EOF, as os.File
+// does; the missing bytes are treated as zeros.
+type ExternalStorage interface {
+	io.ReaderAt
+	io.WriterAt
+}
+
+// externalMagic identifies an external heap file. The final byte is the
+// layout version.
+var externalMagic = [8]byte{'H', 'C', 'E', 'X', 'T', 'R', 0, 1}
+
+// externalHeaderSize is the size of the header that starts the storage of an
+// external heap: the magic, the arity, the size of one priority, the value
+// size, the page size and the element count, all little-endian. Pages start
+// right after it.
+const externalHeaderSize = 40
+
+// ExternalHeapConfig configures an ExternalHeap.
+type ExternalHeapConfig struct {
+	// Arity is the number of children of each element, at least 2. Wider
+	// heaps are shallower and so touch fewer pages per operation. Zero means
+	// 8.
+	Arity int
+	// ValueSize is the size in bytes of every value. Variable-size payloads
+	// are best kept in a separate file and referred to by their offset.
+	ValueSize int
+	// PageSize is the size in bytes of the unit read from and written to the
+	// storage. A page holds as many whole records as fit, and at least one.
+	// Zero means 64 KiB.
+	PageSize int
+	// CachePages is the number of pages kept in memory. The least recently
+	// used page is written back, if modified, and dropped to make room for
+	// another. Zero means 256.
+	CachePages int
+}
+
+// withDefaults returns the config with zero fields replaced by their
+// defaults.
+func (c ExternalHeapConfig) withDefaults() ExternalHeapConfig {
+	if c.Arity == 0 {
+		c.Arity = 8
+	}
+	if c.PageSize == 0 {
+		c.PageSize = 64 << 10
+	}
+	if c.CachePages == 0 {
+		c.CachePages = 256
+	}
+	return c
+}
+
+// externalPage is a page of records cached in memory.
+type externalPage struct {
+	data  []byte
+	dirty bool
+	// used is the value of the heap's clock when the page was last accessed.
+	used uint64
+}
+
+// ExternalHeap is a d-ary min- or max-heap of fixed-size records kept in an
+// ExternalStorage rather than in memory, for queues larger than RAM. Each
+// record is a priority of a fixed-size numeric type followed by a value of
+// exactly ValueSize bytes. Records are laid out in heap order across pages of
+// PageSize bytes, and only the CachePages most recently used pages are held
+// in memory. The pages near the root are touched by every operation and so
+// stay cached, and a push or pop reads and writes O(log_d n) pages at most.
+//
+// Modified pages reach the storage when they are evicted or on Flush, which
+// also records the element count so that OpenExternalHeap can resume the heap
+// later. If the storage fails, the heap may be left inconsistent, so the
+// error is kept and returned by every later operation until Clear. An
+// ExternalHeap is not safe for concurrent use.
+type ExternalHeap[P FlatPriority] struct {
+	storage ExternalStorage
+	cmp     func(a, b P) bool
+	d       int
+	n       int
+	psize   int
+	vsize   int
+	rsize   int
+	// perPage is the number of records in a page.
+	perPage  int
+	capacity int
+	pages    map[int]*externalPage
+	clock    uint64
+	err      error
+}
+
+// Length returns the number of elements in the heap.
+func (h *ExternalHeap[P]) Length() int { return h.n }
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *ExternalHeap[P]) IsEmpty() bool { return h.n == 0 }
+
+// Arity returns the number of children of each element.
+func (h *ExternalHeap[P]) Arity() int { return h.d }
+
+// ValueSize returns the size in bytes of every value.
+func (h *ExternalHeap[P]) ValueSize() int { return h.vsize }
+
+// Err returns the storage error that left the heap unusable, or nil.
+func (h *ExternalHeap[P]) Err() error { return h.err }
+
+// pageOffset returns the storage offset of page i.
+func (h *ExternalHeap[P]) pageOffset(i int) int64 {
+	return externalHeaderSize + int64(i)*int64(h.perPage*h.rsize)
+}
+
+// page returns page i, reading it from the storage and evicting the least
+// recently used page if it is not cached.
+func (h *ExternalHeap[P]) page(i int) (*externalPage, error) {
+	h.clock++
+	if page, ok := h.pages[i]; ok {
+		page.used = h.clock
+		return page, nil
+	}
+
+	var page *externalPage
+	if len(h.pages) >= h.capacity {
+		victim, oldest := -1, uint64(0)
+		for j, cached := range h.pages {
+			if victim < 0 || cached.used < oldest {
+				victim, oldest = j, cached.used
+			}
+		}
+		if err := h.writePage(victim, h.pages[victim]); err != nil {
+			return nil, err
+		}
+		page = h.pages[victim]
+		delete(h.pages, victim)
+	} else {
+		page = &externalPage{data: make([]byte, h.perPage*h.rsize)}
+	}
+
+	read, err := h.storage.ReadAt(page.data, h.pageOffset(i))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	clear(page.data[read:])
+	page.dirty, page.used = false, h.clock
+	h.pages[i] = page
+	return page, nil
+}
+
+// writePage writes page i back to the storage if it was modified.
+func (h *ExternalHeap[P]) writePage(i int, page *externalPage) error {
+	if !page.dirty {
+		return nil
+	}
+	if _, err := h.storage.WriteAt(page.data, h.pageOffset(i)); err != nil {
+		return err
+	}
+	page.dirty = false
+	return nil
+}
+
+// record returns the bytes of the record at index i within its cached page.
+// The slice is only valid until the next page is loaded.
+func (h *ExternalHeap[P]) record(i int) ([]byte, error) {
+	page, err := h.page(i / h.perPage)
+	if err != nil {
+		return nil, err
+	}
+	start := (i % h.perPage) * h.rsize
+	return page.data[start : start+h.rsize], nil
+}
+
+// priorityAt returns the priority of the record at index i.
+func (h *ExternalHeap[P]) priorityAt(i int) (P, error) {
+	var priority P
+	rec, err := h.record(i)
+	if err != nil {
+		return priority, err
+	}
+	binary.Decode(rec, binary.LittleEndian, &priority)
+	return priority, nil
+}
+
+// load copies the record at index i into buf.
+func (h *ExternalHeap[P]) load(i int, buf []byte) error {
+	rec, err := h.record(i)
+	if err != nil {
+		return err
+	}
+	copy(buf, rec)
+	return nil
+}
+
+// store copies buf into the record at index i.
+func (h *ExternalHeap[P]) store(i int, buf []byte) error {
+	page, err := h.page(i / h.perPage)
+	if err != nil {
+		return err
+	}
+	start := (i % h.perPage) * h.rsize
+	copy(page.data[start:start+h.rsize], buf)
+	page.dirty = true
+	return nil
+}
+
+// move copies the record at index from to index to, going through a buffer
+// so that only one page needs to be cached at a time.
+func (h *ExternalHeap[P]) move(from, to int, buf []byte) error {
+	if err := h.load(from, buf); err != nil {
+		return err
+	}
+	return h.store(to, buf)
+}
+
+// siftUp places rec, whose priority is priority, at index i or above it,
+// moving the ancestors it beats down by one level.
+func (h *ExternalHeap[P]) siftUp(i int, rec []byte, priority P) error {
+	buf := make([]byte, h.rsize)
+	for i > 0 {
+		parent := (i - 1) / h.d
+		pp, err := h.priorityAt(parent)
+		if err != nil {
+			return err
+		}
+		if !h.cmp(priority, pp) {
+			break
+		}
+		if err := h.move(parent, i, buf); err != nil {
+			return err
+		}
+		i = parent
+	}
+	return h.store(i, rec)
+}
+
+// siftDown places rec, whose priority is priority, at index i or below it,
+// moving the best child up by one level at every step.
+func (h *ExternalHeap[P]) siftDown(i int, rec []byte, priority P) error {
+	buf := make([]byte, h.rsize)
+	for {
+		first := h.d*i + 1
+		if first >= h.n {
+			break
+		}
+		best, bp := first, P(0)
+		for c := first; c < min(first+h.d, h.n); c++ {
+			cp, err := h.priorityAt(c)
+			if err != nil {
+				return err
+			}
+			if c == first || h.cmp(cp, bp) {
+				best, bp = c, cp
+			}
+		}
+		if !h.cmp(bp, priority) {
+			break
+		}
+		if err := h.move(best, i, buf); err != nil {
+			return err
+		}
+		i = best
+	}
+	return h.store(i, rec)
+}
+
+// fail records err as the error that left the heap unusable and returns it.
+func (h *ExternalHeap[P]) fail(err error) error {
+	if err != nil {
+		h.err = err
+	}
+	return err
+}
+
+// Push inserts an element with the given value and priority. Returns
+// ErrInvalidValueSize if the value is not exactly ValueSize bytes long, or
+// the storage error if a page could not be read or written.
+func (h *ExternalHeap[P]) Push(value []byte, priority P) error {
+	if h.err != nil {
+		return h.err
+	}
+	if len(value) != h.vsize {
+		return ErrInvalidValueSize
+	}
+	rec := make([]byte, h.psize, h.rsize)
+	binary.Encode(rec, binary.LittleEndian, priority)
+	rec = append(rec, value...)
+
+	h.n++
+	return h.fail(h.siftUp(h.n-1, rec, priority))
+}
+
+// decode splits a record into a copy of its value and its priority.
+func (h *ExternalHeap[P]) decode(rec []byte) ([]byte, P) {
+	var priority P
+	binary.Decode(rec, binary.LittleEndian, &priority)
+	return slices.Clone(rec[h.psize:]), priority
+}
+
+// peek is an internal method that returns the root element.
+func (h *ExternalHeap[P]) peek() ([]byte, P, error) {
+	var zero P
+	if h.err != nil {
+		return nil, zero, h.err
+	}
+	if h.n == 0 {
+		return nil, zero, ErrHeapEmpty
+	}
+	rec, err := h.record(0)
+	if err != nil {
+		return nil, zero, h.fail(err)
+	}
+	value, priority := h.decode(rec)
+	return value, priority, nil
+}
+
+// Peek returns a copy of the value and the priority of the root element
+// without removing it. Returns zero values and an error if the heap is empty.
+func (h *ExternalHeap[P]) Peek() ([]byte, P, error) { return h.peek() }
+
+// PeekValue returns a copy of the value of the root element without removing
+// it. Returns nil and an error if the heap is empty.
+func (h *ExternalHeap[P]) PeekValue() ([]byte, error) {
+	return valueFromNode(h.peek())
+}
+
+// PeekPriority returns the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *ExternalHeap[P]) PeekPriority() (P, error) {
+	return priorityFromNode(h.peek())
+}
+
+// pop is an internal method that removes and returns the root element.
+func (h *ExternalHeap[P]) pop() ([]byte, P, error) {
+	value, priority, err := h.peek()
+	if err != nil {
+		return value, priority, err
+	}
+
+	h.n--
+	if h.n > 0 {
+		last := make([]byte, h.rsize)
+		if err := h.load(h.n, last); err != nil {
+			return nil, priority, h.fail(err)
+		}
+		var lp P
+		binary.Decode(last, binary.LittleEndian, &lp)
+		if err := h.siftDown(0, last, lp); err != nil {
+			return nil, priority, h.fail(err)
+		}
+	}
+	return value, priority, nil
+}
+
+// Pop removes and returns the value and priority of the root element. Returns
+// zero values and an error if the heap is empty.
+func (h *ExternalHeap[P]) Pop() ([]byte, P, error) { return h.pop() }
+
+// PopValue removes and returns the value of the root element. Returns nil and
+// an error if the heap is empty.
+func (h *ExternalHeap[P]) PopValue() ([]byte, error) {
+	return valueFromNode(h.pop())
+}
+
+// PopPriority removes and returns the priority of the root element. Returns
+// zero value and an error if the heap is empty.
+func (h *ExternalHeap[P]) PopPriority() (P, error) {
+	return priorityFromNode(h.pop())
+}
+
+// Flush writes every modified page and a header recording the element count
+// to the storage, so that OpenExternalHeap can resume the heap from it.
+// Pages are written in storage order.
+func (h *ExternalHeap[P]) Flush() error {
+	if h.err != nil {
+		return h.err
+	}
+	indices := make([]int, 0, len(h.pages))
+	for i := range h.pages {
+		indices = append(indices, i)
+	}
+	slices.Sort(indices)
+	for _, i := range indices {
+		if err := h.writePage(i, h.pages[i]); err != nil {
+			return h.fail(err)
+		}
+	}
+
+	header := make([]byte, 0, externalHeaderSize)
+	header = append(header, externalMagic[:]...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(h.d))
+	header = binary.LittleEndian.AppendUint32(header, uint32(h.psize))
+	header = binary.LittleEndian.AppendUint64(header, uint64(h.vsize))
+	header = binary.LittleEndian.AppendUint64(header, uint64(h.perPage*h.rsize))
+	header = binary.LittleEndian.AppendUint64(header, uint64(h.n))
+	_, err := h.storage.WriteAt(header, 0)
+	return h.fail(err)
+}
+
+// Clear removes all elements from the heap and drops the cached pages without
+// writing them back. It also clears a storage error kept from an earlier
+// operation. The storage is not truncated; its stale pages are overwritten as
+// the heap grows again.
+func (h *ExternalHeap[P]) Clear() {
+	h.n = 0
+	h.err = nil
+	clear(h.pages)
+}
@@ -0,0 +1,74 @@
+package heapcraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// NewExternalHeap creates an empty ExternalHeap that keeps its records in
+// storage, ordered by cmp. Any data already in the storage is ignored and
+// overwritten as the heap grows. Returns an error wrapping ErrInvalidArity if
+// the arity is less than 2, or ErrInvalidValueSize if the value size is
+// negative.
+func NewExternalHeap[P FlatPriority](storage ExternalStorage, cmp func(a, b P) bool, config ExternalHeapConfig) (*ExternalHeap[P], error) {
+	config = config.withDefaults()
+	if config.Arity < 2 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidArity, config.Arity)
+	}
+	if config.ValueSize < 0 {
+		return nil, ErrInvalidValueSize
+	}
+
+	var zero P
+	psize := binary.Size(zero)
+	rsize := psize + config.ValueSize
+	return &ExternalHeap[P]{
+		storage:  storage,
+		cmp:      cmp,
+		d:        config.Arity,
+		psize:    psize,
+		vsize:    config.ValueSize,
+		rsize:    rsize,
+		perPage:  max(config.PageSize/rsize, 1),
+		capacity: max(config.CachePages, 1),
+		pages:    make(map[int]*externalPage),
+	}, nil
+}
+
+// OpenExternalHeap resumes an ExternalHeap from storage last written by
+// Flush. The arity, value size and page size are read from the storage, so
+// only CachePages is taken from config. Returns ErrInvalidSnapshot if the
+// storage does not start with a valid header for priorities of type P.
+func OpenExternalHeap[P FlatPriority](storage ExternalStorage, cmp func(a, b P) bool, config ExternalHeapConfig) (*ExternalHeap[P], error) {
+	header := make([]byte, externalHeaderSize)
+	if _, err := storage.ReadAt(header, 0); err != nil {
+		return nil, ErrInvalidSnapshot
+	}
+	if !bytes.Equal(header[:8], externalMagic[:]) {
+		return nil, ErrInvalidSnapshot
+	}
+
+	var zero P
+	d := binary.LittleEndian.Uint32(header[8:])
+	psize := binary.LittleEndian.Uint32(header[12:])
+	vsize := binary.LittleEndian.Uint64(header[16:])
+	pageSize := binary.LittleEndian.Uint64(header[24:])
+	n := binary.LittleEndian.Uint64(header[32:])
+	rsize := uint64(psize) + vsize
+	if d < 2 || int(psize) != binary.Size(zero) || pageSize < rsize || pageSize%rsize != 0 {
+		return nil, ErrInvalidSnapshot
+	}
+
+	heap, err := NewExternalHeap[P](storage, cmp, ExternalHeapConfig{
+		Arity:      int(d),
+		ValueSize:  int(vsize),
+		PageSize:   int(pageSize),
+		CachePages: config.CachePages,
+	})
+	if err != nil {
+		return nil, err
+	}
+	heap.n = int(n)
+	return heap, nil
+}
@@ -0,0 +1,118 @@
+package heapcraft
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createExternalFile creates an empty temporary file to back an external
+// heap.
+func createExternalFile(t *testing.T) *os.File {
+	file, err := os.Create(filepath.Join(t.TempDir(), "heap.ext"))
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+// failingStorage is an ExternalStorage whose writes fail.
+type failingStorage struct{ *os.File }
+
+func (failingStorage) WriteAt([]byte, int64) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestExternalHeap_MatchesDaryHeap(t *testing.T) {
+	// Pages of four records and a cache of two pages force constant eviction.
+	config := ExternalHeapConfig{Arity: 3, ValueSize: 4, PageSize: 48, CachePages: 2}
+	heap, err := NewExternalHeap[int64](createExternalFile(t), func(a, b int64) bool { return a < b }, config)
+	require.NoError(t, err)
+	reference := NewDaryHeap[uint32, int64](3, nil, func(a, b int64) bool { return a < b }, false)
+
+	r := rand.New(rand.NewSource(7))
+	value := make([]byte, 4)
+	for i := 0; i < 2000; i++ {
+		if reference.Length() > 0 && r.Intn(3) == 0 {
+			want, wantPriority, _ := reference.Pop()
+			got, priority, err := heap.Pop()
+			require.NoError(t, err)
+			assert.Equal(t, wantPriority, priority)
+			assert.Equal(t, want, binary.LittleEndian.Uint32(got))
+			continue
+		}
+		priority := r.Int63n(500) - 250
+		binary.LittleEndian.PutUint32(value, uint32(i))
+		require.NoError(t, heap.Push(value, priority))
+		reference.Push(uint32(i), priority)
+	}
+	assert.Equal(t, reference.Length(), heap.Length())
+	for !reference.IsEmpty() {
+		wantPriority, _ := reference.PopPriority()
+		priority, err := heap.PopPriority()
+		require.NoError(t, err)
+		assert.Equal(t, wantPriority, priority)
+	}
+	_, _, err = heap.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestExternalHeap_FlushAndOpen(t *testing.T) {
+	file := createExternalFile(t)
+	gt := func(a, b float64) bool { return a > b }
+	heap, err := NewExternalHeap[float64](file, gt, ExternalHeapConfig{ValueSize: 2, PageSize: 30, CachePages: 1})
+	require.NoError(t, err)
+	for i, p := range []float64{1.5, 9, -2, 4.25, 7} {
+		require.NoError(t, heap.Push([]byte{byte(i), 'x'}, p))
+	}
+	assert.ErrorIs(t, heap.Push([]byte{1}, 3), ErrInvalidValueSize)
+	require.NoError(t, heap.Flush())
+
+	reopened, err := OpenExternalHeap[float64](file, gt, ExternalHeapConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, reopened.Length())
+	assert.Equal(t, 8, reopened.Arity())
+	assert.Equal(t, 2, reopened.ValueSize())
+	value, priority, err := reopened.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 'x'}, value)
+	assert.Equal(t, 9.0, priority)
+
+	var priorities []float64
+	for !reopened.IsEmpty() {
+		p, err := reopened.PopPriority()
+		require.NoError(t, err)
+		priorities = append(priorities, p)
+	}
+	assert.Equal(t, []float64{9, 7, 4.25, 1.5, -2}, priorities)
+
+	_, err = OpenExternalHeap[int32](file, nil, ExternalHeapConfig{})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	_, err = OpenExternalHeap[float64](createExternalFile(t), gt, ExternalHeapConfig{})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	_, err = NewExternalHeap[float64](file, gt, ExternalHeapConfig{Arity: 1})
+	assert.ErrorIs(t, err, ErrInvalidArity)
+}
+
+func TestExternalHeap_StorageErrorIsSticky(t *testing.T) {
+	storage := failingStorage{createExternalFile(t)}
+	heap, err := NewExternalHeap[int32](storage, func(a, b int32) bool { return a < b }, ExternalHeapConfig{PageSize: 4, CachePages: 1})
+	require.NoError(t, err)
+
+	// The first page stays cached; the second push evicts it and fails.
+	require.NoError(t, heap.Push(nil, 2))
+	err = heap.Push(nil, 1)
+	require.Error(t, err)
+	assert.Equal(t, err, heap.Err())
+	_, _, popErr := heap.Pop()
+	assert.Equal(t, err, popErr)
+
+	heap.Clear()
+	assert.NoError(t, heap.Err())
+	assert.True(t, heap.IsEmpty())
+}
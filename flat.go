@@ -0,0 +1,163 @@
+package heapcraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"iter"
+)
+
+// FlatPriority is the set of fixed-size priority types that can be stored in
+// the flat layout read by FlatHeap.
+type FlatPriority interface {
+	~int8 | ~int16 | ~int32 | ~int64 |
+		~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// flatMagic identifies a flat heap file. The final byte is the layout version.
+var flatMagic = [8]byte{'H', 'C', 'F', 'L', 'A', 'T', 0, 1}
+
+// flatHeaderSize is the size of the fixed header that starts a flat heap: the
+// magic, the arity, the size of one priority, the element count and the
+// length of the value section, all little-endian.
+const flatHeaderSize = 32
+
+// WriteFlat writes the elements of a d-ary heap to w in a flat, read-only
+// layout that FlatHeap can open directly from a memory-mapped file. Elements
+// are written best-first, which is also a valid d-ary heap ordering, so
+// readers can peek, iterate in order and select the kth element without a
+// comparison function. Values are stored as the bytes returned by encode. The
+// heap itself is not modified.
+//
+// The layout is a 32-byte header followed by a column of fixed-size
+// priorities, a column of len+1 value offsets and the concatenated values.
+func WriteFlat[V any, P FlatPriority](w io.Writer, heap *DaryHeap[V, P], encode func(V) ([]byte, error)) error {
+	nodes := heap.Export(ExportOptions[V, P]{})
+	var zero P
+	psize := binary.Size(zero)
+
+	priorities := make([]byte, 0, len(nodes)*psize)
+	offsets := make([]byte, 8, (len(nodes)+1)*8)
+	var values bytes.Buffer
+	for _, node := range nodes {
+		var err error
+		if priorities, err = binary.Append(priorities, binary.LittleEndian, node.priority); err != nil {
+			return err
+		}
+
+		value, err := encode(node.value)
+		if err != nil {
+			return err
+		}
+		values.Write(value)
+		offsets = binary.LittleEndian.AppendUint64(offsets, uint64(values.Len()))
+	}
+
+	header := make([]byte, 0, flatHeaderSize)
+	header = append(header, flatMagic[:]...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(heap.d))
+	header = binary.LittleEndian.AppendUint32(header, uint32(psize))
+	header = binary.LittleEndian.AppendUint64(header, uint64(len(nodes)))
+	header = binary.LittleEndian.AppendUint64(header, uint64(values.Len()))
+
+	for _, section := range [][]byte{header, priorities, offsets, values.Bytes()} {
+		if _, err := w.Write(section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlatHeap is a read-only view of a d-ary heap written by WriteFlat. It reads
+// elements in place from its backing bytes, typically a memory-mapped file
+// opened with OpenFlat, so several processes can share one precomputed heap
+// without copying it. Values are returned as byte slices that alias the
+// backing data; they must not be modified and are only valid until Close.
+// A FlatHeap is safe for concurrent use.
+type FlatHeap[P FlatPriority] struct {
+	d          int
+	n          int
+	psize      int
+	priorities []byte
+	offsets    []byte
+	values     []byte
+	release    func() error
+}
+
+// Length returns the number of elements in the heap.
+func (f *FlatHeap[P]) Length() int { return f.n }
+
+// IsEmpty returns true if the heap contains no elements.
+func (f *FlatHeap[P]) IsEmpty() bool { return f.n == 0 }
+
+// Arity returns the arity of the d-ary heap the layout was written from.
+func (f *FlatHeap[P]) Arity() int { return f.d }
+
+// at returns the value and priority of the element at index i, where index 0
+// is the root.
+func (f *FlatHeap[P]) at(i int) ([]byte, P) {
+	var priority P
+	binary.Decode(f.priorities[i*f.psize:], binary.LittleEndian, &priority)
+	start := binary.LittleEndian.Uint64(f.offsets[i*8:])
+	end := binary.LittleEndian.Uint64(f.offsets[(i+1)*8:])
+	return f.values[start:end:end], priority
+}
+
+// Kth returns the value and priority of the element that would be popped kth,
+// counting from zero, in O(1). Returns zero values and ErrIndexOutOfBounds if
+// k is outside the heap.
+func (f *FlatHeap[P]) Kth(k int) ([]byte, P, error) {
+	if k < 0 || k >= f.n {
+		var zero P
+		return nil, zero, &IndexOutOfBoundsError{Index: k, Length: f.n}
+	}
+	value, priority := f.at(k)
+	return value, priority, nil
+}
+
+// Peek returns the value and priority of the root element. Returns zero values
+// and an error if the heap is empty.
+func (f *FlatHeap[P]) Peek() ([]byte, P, error) {
+	if f.n == 0 {
+		var zero P
+		return nil, zero, ErrHeapEmpty
+	}
+	value, priority := f.at(0)
+	return value, priority, nil
+}
+
+// PeekValue returns the value of the root element. Returns nil and an error if
+// the heap is empty.
+func (f *FlatHeap[P]) PeekValue() ([]byte, error) {
+	return valueFromNode(f.Peek())
+}
+
+// PeekPriority returns the priority of the root element. Returns zero value
+// and an error if the heap is empty.
+func (f *FlatHeap[P]) PeekPriority() (P, error) {
+	return priorityFromNode(f.Peek())
+}
+
+// All returns an iterator over the values and priorities of the heap in the
+// order they would be popped.
+func (f *FlatHeap[P]) All() iter.Seq2[[]byte, P] {
+	return func(yield func([]byte, P) bool) {
+		for i := 0; i < f.n; i++ {
+			if !yield(f.at(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Close releases the backing data, unmapping it if it was memory-mapped by
+// OpenFlat. Value slices returned by the heap must not be used afterwards.
+func (f *FlatHeap[P]) Close() error {
+	release := f.release
+	*f = FlatHeap[P]{}
+	if release == nil {
+		return nil
+	}
+	return release()
+}
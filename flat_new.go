@@ -0,0 +1,78 @@
+package heapcraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+)
+
+// OpenFlat opens a file written by WriteFlat as a read-only FlatHeap. On
+// platforms that support it the file is memory-mapped and shared with other
+// processes mapping the same file; elsewhere it is read into memory. The heap
+// must be closed to release the mapping.
+func OpenFlat[P FlatPriority](path string) (*FlatHeap[P], error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, release, err := mapFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	heap, err := NewFlatHeap[P](data)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	heap.release = release
+	return heap, nil
+}
+
+// NewFlatHeap creates a read-only FlatHeap over bytes written by WriteFlat.
+// The data is used in place and must not be modified while the heap is in
+// use. Returns ErrInvalidSnapshot if the data is not a valid flat layout for
+// priorities of type P.
+func NewFlatHeap[P FlatPriority](data []byte) (*FlatHeap[P], error) {
+	if len(data) < flatHeaderSize || !bytes.Equal(data[:8], flatMagic[:]) {
+		return nil, ErrInvalidSnapshot
+	}
+
+	var zero P
+	d := binary.LittleEndian.Uint32(data[8:])
+	psize := binary.LittleEndian.Uint32(data[12:])
+	n := binary.LittleEndian.Uint64(data[16:])
+	valuesLen := binary.LittleEndian.Uint64(data[24:])
+	if int(psize) != binary.Size(zero) {
+		return nil, ErrInvalidSnapshot
+	}
+
+	body := uint64(len(data) - flatHeaderSize)
+	if n > body/(uint64(psize)+8) || n*uint64(psize)+(n+1)*8+valuesLen != body {
+		return nil, ErrInvalidSnapshot
+	}
+
+	prioritiesEnd := flatHeaderSize + int(n)*int(psize)
+	offsetsEnd := prioritiesEnd + (int(n)+1)*8
+	heap := &FlatHeap[P]{
+		d:          int(d),
+		n:          int(n),
+		psize:      int(psize),
+		priorities: data[flatHeaderSize:prioritiesEnd],
+		offsets:    data[prioritiesEnd:offsetsEnd],
+		values:     data[offsetsEnd:],
+	}
+
+	// Offsets are validated once up front so that reads never go out of range.
+	var last uint64
+	for i := 0; i <= heap.n; i++ {
+		offset := binary.LittleEndian.Uint64(heap.offsets[i*8:])
+		if offset < last || offset > valuesLen || (i == 0 && offset != 0) {
+			return nil, ErrInvalidSnapshot
+		}
+		last = offset
+	}
+	return heap, nil
+}
@@ -0,0 +1,124 @@
+package heapcraft
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeString(v string) ([]byte, error) { return []byte(v), nil }
+
+// writeFlatFile writes the heap in the flat layout to a temporary file and
+// returns its path.
+func writeFlatFile(t *testing.T, heap *DaryHeap[string, int64]) string {
+	var buf bytes.Buffer
+	require.Nil(t, WriteFlat(&buf, heap, encodeString))
+	path := filepath.Join(t.TempDir(), "heap.flat")
+	require.Nil(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path
+}
+
+func TestFlatHeap_OpenAndRead(t *testing.T) {
+	heap := NewDaryHeap[string, int64](4, nil, func(a, b int64) bool { return a < b }, false)
+	for _, p := range []int64{50, -3, 7, 1000, 0, 7} {
+		heap.Push("v"+strconv.FormatInt(p, 10), p)
+	}
+	path := writeFlatFile(t, heap)
+	assert.Equal(t, 6, heap.Length())
+
+	// Two independent mappings of the same file see the same elements.
+	first, err := OpenFlat[int64](path)
+	require.Nil(t, err)
+	second, err := OpenFlat[int64](path)
+	require.Nil(t, err)
+
+	for _, flat := range []*FlatHeap[int64]{first, second} {
+		assert.Equal(t, 6, flat.Length())
+		assert.False(t, flat.IsEmpty())
+		assert.Equal(t, 4, flat.Arity())
+
+		value, priority, err := flat.Peek()
+		require.Nil(t, err)
+		assert.Equal(t, "v-3", string(value))
+		assert.Equal(t, int64(-3), priority)
+
+		value, priority, err = flat.Kth(5)
+		require.Nil(t, err)
+		assert.Equal(t, "v1000", string(value))
+		assert.Equal(t, int64(1000), priority)
+		_, _, err = flat.Kth(6)
+		assert.ErrorIs(t, err, ErrIndexOutOfBounds)
+
+		var priorities []int64
+		for _, p := range flat.All() {
+			priorities = append(priorities, p)
+		}
+		assert.Equal(t, []int64{-3, 0, 7, 7, 50, 1000}, priorities)
+	}
+
+	assert.Nil(t, first.Close())
+	assert.Nil(t, second.Close())
+	assert.True(t, first.IsEmpty())
+}
+
+func TestFlatHeap_Empty(t *testing.T) {
+	heap := NewBinaryHeap[string, int64](nil, func(a, b int64) bool { return a < b }, false)
+	flat, err := OpenFlat[int64](writeFlatFile(t, heap))
+	require.Nil(t, err)
+	defer flat.Close()
+
+	assert.True(t, flat.IsEmpty())
+	_, err = flat.PeekValue()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+	_, err = flat.PeekPriority()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestFlatHeap_EarlyBreak(t *testing.T) {
+	heap := NewBinaryHeap[string, float64](nil, func(a, b float64) bool { return a > b }, false)
+	for i := 0; i < 10; i++ {
+		heap.Push(strconv.Itoa(i), float64(i)/2)
+	}
+	var buf bytes.Buffer
+	require.Nil(t, WriteFlat(&buf, heap, encodeString))
+
+	flat, err := NewFlatHeap[float64](buf.Bytes())
+	require.Nil(t, err)
+	var values []string
+	for value := range flat.All() {
+		values = append(values, string(value))
+		if len(values) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"9", "8", "7"}, values)
+}
+
+func TestFlatHeap_Invalid(t *testing.T) {
+	heap := NewBinaryHeap[string, int64](nil, func(a, b int64) bool { return a < b }, false)
+	heap.Push("a", 1)
+	heap.Push("b", 2)
+	var buf bytes.Buffer
+	require.Nil(t, WriteFlat(&buf, heap, encodeString))
+	data := buf.Bytes()
+
+	_, err := NewFlatHeap[int32](data)
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	_, err = NewFlatHeap[int64](data[:len(data)-1])
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	_, err = NewFlatHeap[int64]([]byte("not a flat heap"))
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	corrupt := bytes.Clone(data)
+	corrupt[flatHeaderSize+2*8+8] = 0xff
+	_, err = NewFlatHeap[int64](corrupt)
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	_, err = OpenFlat[int64](filepath.Join(t.TempDir(), "missing.flat"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,131 @@
+package heapcraft
+
+// DaryHandle refers to an element of a DaryHeap and follows it as sifting
+// moves it around the array, so that the element can be read, updated or
+// removed without registering a swap callback to track its index. A handle is
+// only meaningful to the heap that issued it, and becomes stale once its
+// element leaves the heap by being popped, removed or cleared.
+//
+// Handles are optional. A heap keeps no handle bookkeeping until the first one
+// is taken, after which every swap also moves a pointer in a parallel array.
+type DaryHandle struct {
+	// index is the position of the element in the heap's array, or -1 once
+	// the element has left the heap.
+	index int
+}
+
+// attach records that the handle's element is at index i. A nil handle is
+// ignored.
+func (handle *DaryHandle) attach(i int) {
+	if handle != nil {
+		handle.index = i
+	}
+}
+
+// swapHandles exchanges the handles at indices i and j and records their new
+// positions.
+func (h *DaryHeap[V, P]) swapHandles(i, j int) {
+	h.handles[i], h.handles[j] = h.handles[j], h.handles[i]
+	h.handles[i].attach(i)
+	h.handles[j].attach(j)
+}
+
+// trackHandles starts handle bookkeeping if it has not started yet.
+func (h *DaryHeap[V, P]) trackHandles() {
+	if h.handles == nil {
+		h.handles = make([]*DaryHandle, h.Length(), cap(h.data))
+	}
+}
+
+// releaseHandle marks the handle at index i, if any, as stale and detaches it
+// from the array, for an element that is about to leave the heap.
+func (h *DaryHeap[V, P]) releaseHandle(i int) {
+	if h.handles == nil || h.handles[i] == nil {
+		return
+	}
+	h.handles[i].index = -1
+	h.handles[i] = nil
+}
+
+// releaseHandles marks every handle as stale and drops the bookkeeping.
+func (h *DaryHeap[V, P]) releaseHandles() {
+	for _, handle := range h.handles {
+		if handle != nil {
+			handle.index = -1
+		}
+	}
+	h.handles = nil
+}
+
+// lookup returns the current index of the element referred to by handle, or
+// ErrStaleHandle if the handle does not refer to an element of this heap.
+func (h *DaryHeap[V, P]) lookup(handle *DaryHandle) (int, error) {
+	if handle == nil || handle.index < 0 || handle.index >= len(h.handles) || h.handles[handle.index] != handle {
+		return 0, ErrStaleHandle
+	}
+	return handle.index, nil
+}
+
+// PushHandle inserts a new element like Push and returns a handle that follows
+// it through later operations.
+func (h *DaryHeap[V, P]) PushHandle(value V, priority P) *DaryHandle {
+	h.trackHandles()
+	handle := &DaryHandle{}
+	h.push(value, priority, handle)
+	return handle
+}
+
+// HandleAt returns a handle for the element currently at index i, creating
+// one if it has none, so that elements pushed without PushHandle can be
+// followed too. Returns an error if the index is out of bounds.
+func (h *DaryHeap[V, P]) HandleAt(i int) (*DaryHandle, error) {
+	if i < 0 || i >= h.Length() {
+		return nil, &IndexOutOfBoundsError{Index: i, Length: h.Length()}
+	}
+	h.trackHandles()
+	if h.handles[i] == nil {
+		h.handles[i] = &DaryHandle{index: i}
+	}
+	return h.handles[i], nil
+}
+
+// IndexOf returns the current index of the element referred to by handle.
+// Returns ErrStaleHandle if the element has left the heap.
+func (h *DaryHeap[V, P]) IndexOf(handle *DaryHandle) (int, error) {
+	return h.lookup(handle)
+}
+
+// GetByHandle returns the value and priority of the element referred to by
+// handle. Returns ErrStaleHandle if the element has left the heap.
+func (h *DaryHeap[V, P]) GetByHandle(handle *DaryHandle) (V, P, error) {
+	i, err := h.lookup(handle)
+	if err != nil {
+		v, p := zeroValuePair[V, P]()
+		return v, p, err
+	}
+	return h.data[i].value, h.data[i].priority, nil
+}
+
+// UpdateByHandle replaces the value and priority of the element referred to by
+// handle and restores the heap order around it. The handle keeps referring to
+// the element. Returns ErrStaleHandle if the element has left the heap.
+func (h *DaryHeap[V, P]) UpdateByHandle(handle *DaryHandle, value V, priority P) error {
+	i, err := h.lookup(handle)
+	if err != nil {
+		return err
+	}
+	return h.Update(i, value, priority)
+}
+
+// RemoveByHandle removes the element referred to by handle and returns it. The
+// handle becomes stale. Returns ErrStaleHandle if the element has already left
+// the heap.
+func (h *DaryHeap[V, P]) RemoveByHandle(handle *DaryHandle) (V, P, error) {
+	i, err := h.lookup(handle)
+	if err != nil {
+		v, p := zeroValuePair[V, P]()
+		return v, p, err
+	}
+	removed := h.removeAt(i)
+	return removed.value, removed.priority, nil
+}
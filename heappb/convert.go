@@ -0,0 +1,99 @@
+// Package heappb provides protocol buffer definitions for heapcraft heap
+// nodes and snapshots, along with converters between the generated messages
+// and heapcraft.HeapNode.
+//
+// Heap values and priorities are generic, so the messages carry them as bytes.
+// A Codec is supplied for each of the two types to encode and decode them.
+package heappb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative heap.proto
+
+import (
+	"encoding/json"
+
+	"github.com/galactixx/heapcraft"
+)
+
+// Codec converts values of type T to and from the bytes stored in the value
+// and priority fields of a HeapNode message.
+type Codec[T any] struct {
+	Marshal   func(T) ([]byte, error)
+	Unmarshal func([]byte) (T, error)
+}
+
+// JSONCodec returns a Codec that encodes values using encoding/json.
+func JSONCodec[T any]() Codec[T] {
+	return Codec[T]{
+		Marshal: func(v T) ([]byte, error) { return json.Marshal(v) },
+		Unmarshal: func(data []byte) (T, error) {
+			var v T
+			err := json.Unmarshal(data, &v)
+			return v, err
+		},
+	}
+}
+
+// FromHeapNode encodes a heapcraft.HeapNode into a HeapNode message using the
+// given value and priority codecs.
+func FromHeapNode[V any, P any](node heapcraft.HeapNode[V, P], values Codec[V], priorities Codec[P]) (*HeapNode, error) {
+	value, err := values.Marshal(node.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	priority, err := priorities.Marshal(node.Priority())
+	if err != nil {
+		return nil, err
+	}
+	return &HeapNode{Value: value, Priority: priority}, nil
+}
+
+// ToHeapNode decodes a HeapNode message into a heapcraft.HeapNode using the
+// given value and priority codecs.
+func ToHeapNode[V any, P any](msg *HeapNode, values Codec[V], priorities Codec[P]) (heapcraft.HeapNode[V, P], error) {
+	value, err := values.Unmarshal(msg.GetValue())
+	if err != nil {
+		return heapcraft.HeapNode[V, P]{}, err
+	}
+
+	priority, err := priorities.Unmarshal(msg.GetPriority())
+	if err != nil {
+		return heapcraft.HeapNode[V, P]{}, err
+	}
+	return heapcraft.CreateHeapNode(value, priority), nil
+}
+
+// FromHeapNodes encodes a slice of heapcraft.HeapNode into a HeapSnapshot
+// message, preserving the order of the nodes.
+func FromHeapNodes[V any, P any](nodes []heapcraft.HeapNode[V, P], values Codec[V], priorities Codec[P]) (*HeapSnapshot, error) {
+	snapshot := &HeapSnapshot{Nodes: make([]*HeapNode, 0, len(nodes))}
+	for _, node := range nodes {
+		msg, err := FromHeapNode(node, values, priorities)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Nodes = append(snapshot.Nodes, msg)
+	}
+	return snapshot, nil
+}
+
+// ToHeapNodes decodes a HeapSnapshot message into a slice of
+// heapcraft.HeapNode, preserving the order of the nodes. The result can be
+// passed directly to any heap constructor to rebuild the heap.
+func ToHeapNodes[V any, P any](snapshot *HeapSnapshot, values Codec[V], priorities Codec[P]) ([]heapcraft.HeapNode[V, P], error) {
+	nodes := make([]heapcraft.HeapNode[V, P], 0, len(snapshot.GetNodes()))
+	for _, msg := range snapshot.GetNodes() {
+		node, err := ToHeapNode(msg, values, priorities)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Snapshot captures the current elements of a heap as a HeapSnapshot message,
+// ordered best-first. The heap itself is not modified.
+func Snapshot[V any, P any](heap heapcraft.BaseHeap[V, P], values Codec[V], priorities Codec[P]) (*HeapSnapshot, error) {
+	return FromHeapNodes(heap.Export(heapcraft.ExportOptions[V, P]{}), values, priorities)
+}
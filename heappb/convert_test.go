@@ -0,0 +1,72 @@
+package heappb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/galactixx/heapcraft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func lt(a, b int) bool { return a < b }
+
+func TestHeapNodeRoundTrip(t *testing.T) {
+	node := heapcraft.CreateHeapNode("task", 7)
+	msg, err := FromHeapNode(node, JSONCodec[string](), JSONCodec[int]())
+	require.Nil(t, err)
+	assert.Equal(t, []byte(`"task"`), msg.GetValue())
+	assert.Equal(t, []byte(`7`), msg.GetPriority())
+
+	decoded, err := ToHeapNode(msg, JSONCodec[string](), JSONCodec[int]())
+	require.Nil(t, err)
+	assert.Equal(t, node, decoded)
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	heap := heapcraft.NewBinaryHeap[string, int](nil, lt, false)
+	heap.Push("c", 3)
+	heap.Push("a", 1)
+	heap.Push("b", 2)
+
+	snapshot, err := Snapshot[string, int](heap, JSONCodec[string](), JSONCodec[int]())
+	require.Nil(t, err)
+	assert.Equal(t, 3, heap.Length())
+
+	// The snapshot survives the wire format unchanged.
+	data, err := proto.Marshal(snapshot)
+	require.Nil(t, err)
+	var received HeapSnapshot
+	require.Nil(t, proto.Unmarshal(data, &received))
+
+	nodes, err := ToHeapNodes(&received, JSONCodec[string](), JSONCodec[int]())
+	require.Nil(t, err)
+	assert.Equal(t, []heapcraft.HeapNode[string, int]{
+		heapcraft.CreateHeapNode("a", 1),
+		heapcraft.CreateHeapNode("b", 2),
+		heapcraft.CreateHeapNode("c", 3),
+	}, nodes)
+
+	restored := heapcraft.NewPairingHeap(nodes, lt, false)
+	assert.Equal(t, []string{"a", "b", "c"}, restored.DrainValues())
+}
+
+func TestCodecErrors(t *testing.T) {
+	errEncode := errors.New("encode failed")
+	failing := Codec[int]{
+		Marshal:   func(int) ([]byte, error) { return nil, errEncode },
+		Unmarshal: func([]byte) (int, error) { return 0, errEncode },
+	}
+
+	nodes := []heapcraft.HeapNode[string, int]{heapcraft.CreateHeapNode("a", 1)}
+	_, err := FromHeapNodes(nodes, JSONCodec[string](), failing)
+	assert.ErrorIs(t, err, errEncode)
+
+	snapshot := &HeapSnapshot{Nodes: []*HeapNode{{Value: []byte(`"a"`), Priority: []byte(`1`)}}}
+	_, err = ToHeapNodes(snapshot, JSONCodec[string](), failing)
+	assert.ErrorIs(t, err, errEncode)
+
+	_, err = ToHeapNodes(&HeapSnapshot{Nodes: []*HeapNode{{Value: []byte(`{`)}}}, JSONCodec[string](), JSONCodec[int]())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: heap.proto
+
+package heappb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HeapNode is a single heap element. Values and priorities are generic on the
+// Go side, so both are carried as bytes produced by a caller-supplied codec.
+type HeapNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         []byte                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Priority      []byte                 `protobuf:"bytes,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeapNode) Reset() {
+	*x = HeapNode{}
+	mi := &file_heap_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeapNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeapNode) ProtoMessage() {}
+
+func (x *HeapNode) ProtoReflect() protoreflect.Message {
+	mi := &file_heap_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeapNode.ProtoReflect.Descriptor instead.
+func (*HeapNode) Descriptor() ([]byte, []int) {
+	return file_heap_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HeapNode) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *HeapNode) GetPriority() []byte {
+	if x != nil {
+		return x.Priority
+	}
+	return nil
+}
+
+// HeapSnapshot is a point-in-time copy of the elements held by a heap, listed
+// from the highest priority to the lowest according to the heap's comparator.
+type HeapSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*HeapNode            `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeapSnapshot) Reset() {
+	*x = HeapSnapshot{}
+	mi := &file_heap_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeapSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeapSnapshot) ProtoMessage() {}
+
+func (x *HeapSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_heap_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeapSnapshot.ProtoReflect.Descriptor instead.
+func (*HeapSnapshot) Descriptor() ([]byte, []int) {
+	return file_heap_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HeapSnapshot) GetNodes() []*HeapNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+var File_heap_proto protoreflect.FileDescriptor
+
+const file_heap_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"heap.proto\x12\fheapcraft.v1\"<\n" +
+	"\bHeapNode\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\fR\x05value\x12\x1a\n" +
+	"\bpriority\x18\x02 \x01(\fR\bpriority\"<\n" +
+	"\fHeapSnapshot\x12,\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x16.heapcraft.v1.HeapNodeR\x05nodesB'Z%github.com/galactixx/heapcraft/heappbb\x06proto3"
+
+var (
+	file_heap_proto_rawDescOnce sync.Once
+	file_heap_proto_rawDescData []byte
+)
+
+func file_heap_proto_rawDescGZIP() []byte {
+	file_heap_proto_rawDescOnce.Do(func() {
+		file_heap_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_heap_proto_rawDesc), len(file_heap_proto_rawDesc)))
+	})
+	return file_heap_proto_rawDescData
+}
+
+var file_heap_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_heap_proto_goTypes = []any{
+	(*HeapNode)(nil),     // 0: heapcraft.v1.HeapNode
+	(*HeapSnapshot)(nil), // 1: heapcraft.v1.HeapSnapshot
+}
+var file_heap_proto_depIdxs = []int32{
+	0, // 0: heapcraft.v1.HeapSnapshot.nodes:type_name -> heapcraft.v1.HeapNode
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_heap_proto_init() }
+func file_heap_proto_init() {
+	if File_heap_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_heap_proto_rawDesc), len(file_heap_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_heap_proto_goTypes,
+		DependencyIndexes: file_heap_proto_depIdxs,
+		MessageInfos:      file_heap_proto_msgTypes,
+	}.Build()
+	File_heap_proto = out.File
+	file_heap_proto_goTypes = nil
+	file_heap_proto_depIdxs = nil
+}
@@ -0,0 +1,112 @@
+package heaptest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/galactixx/heapcraft"
+)
+
+// seedOps are operation sequences added to every fuzz corpus. They include
+// runs that leave nodes with a single child before updating them, which is
+// where the relinking done by UpdatePriority is most delicate.
+var seedOps = [][]byte{
+	{},
+	{0, 5, 0, 3, 4, 4, 6},
+	{0, 9, 0, 8, 0, 7, 0, 6, 2, 3, 0, 1, 3, 1, 200, 4, 2, 0},
+	{0, 1, 0, 2, 0, 3, 2, 3, 0, 0, 4, 1, 9, 5, 0, 7, 1, 6, 0},
+	{0, 50, 1, 40, 0, 30, 1, 20, 2, 3, 2, 255, 4, 0, 0, 5, 1, 2},
+	{1, 7, 1, 7, 1, 7, 3, 0, 7, 3, 1, 7, 5, 2, 2, 2, 2},
+	{128, 3, 0, 10, 2, 0, 4, 129, 1, 2, 3, 0, 0, 2, 2, 2},
+}
+
+func addSeeds(f *testing.F) {
+	for _, ops := range seedOps {
+		f.Add(ops)
+	}
+}
+
+func FuzzReplay(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		Replay(t, heapcraft.NewDaryHeap[int, int](3, nil, lt, false), ops)
+		Replay(t, heapcraft.NewPairingHeap[int, int](nil, lt, true), ops)
+		Replay(t, heapcraft.NewLeftistHeap[int, int](nil, lt, false), ops)
+		Replay(t, heapcraft.NewSkewHeap[int, int](nil, lt, false), ops)
+		Replay(t, heapcraft.NewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{}), ops)
+		Replay(t, heapcraft.NewSkewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{}), ops)
+	})
+}
+
+func FuzzUpdatePriority(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		config := heapcraft.HeapConfig{IDGenerator: &heapcraft.IntegerIDGenerator{}}
+		ReplayTracked(t, heapcraft.NewFullPairingHeap[int, int](nil, lt, config), ops)
+		ReplayTracked(t, heapcraft.NewFullLeftistHeap[int, int](nil, lt, config), ops)
+		ReplayTracked(t, heapcraft.NewFullSkewHeap[int, int](nil, lt, config), ops)
+	})
+}
+
+// melder is a heap that can absorb another heap of its own type.
+type melder[H any] interface {
+	heapcraft.Heap[int, int]
+	heapcraft.Verifier
+	Meld(other H)
+}
+
+// checkMeld builds two heaps from a and b, melds the second into the first
+// and checks that the result holds the elements of both in order.
+func checkMeld[H melder[H]](t *testing.T, newHeap func() H, a, b []byte) {
+	t.Helper()
+	first, second := newHeap(), newHeap()
+	var want []int
+	for i, data := range [][]byte{a, b} {
+		heap := []H{first, second}[i]
+		for j, p := range data {
+			// Every fourth byte pops instead, so that the melded trees are
+			// not only the shapes built by a run of pushes.
+			if j%4 == 3 {
+				heap.Pop()
+				continue
+			}
+			heap.Push(int(p), int(p))
+		}
+		want = append(want, nodePriorities(heap.Export(heapcraft.ExportOptions[int, int]{}))...)
+	}
+	slices.Sort(want)
+
+	first.Meld(second)
+	CheckInvariants(t, first)
+	if !second.IsEmpty() {
+		t.Fatalf("Meld left %d elements in the other heap", second.Length())
+	}
+	if got := first.DrainPriorities(); !slices.Equal(got, want) {
+		t.Fatalf("melded heap drained %v, want %v", got, want)
+	}
+}
+
+func FuzzMeld(f *testing.F) {
+	for i := range seedOps {
+		f.Add(seedOps[i], seedOps[len(seedOps)-1-i])
+	}
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		checkMeld(t, func() *heapcraft.PairingHeap[int, int] { return heapcraft.NewPairingHeap[int, int](nil, lt, false) }, a, b)
+		checkMeld(t, func() *heapcraft.LeftistHeap[int, int] { return heapcraft.NewLeftistHeap[int, int](nil, lt, false) }, a, b)
+		checkMeld(t, func() *heapcraft.SkewHeap[int, int] { return heapcraft.NewSkewHeap[int, int](nil, lt, false) }, a, b)
+		checkMeld(t, func() *heapcraft.BinomialHeap[int, int] {
+			return heapcraft.NewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}, a, b)
+		checkMeld(t, func() *heapcraft.SkewBinomialHeap[int, int] {
+			return heapcraft.NewSkewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		}, a, b)
+	})
+}
+
+func FuzzRadixMonotone(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		ReplayMonotone(t, heapcraft.NewRadixHeap[int, uint](nil, false), ops)
+		ReplayMonotone(t, heapcraft.NewMultiLevelRadixHeap[int, uint](nil, 4, heapcraft.RadixHeapConfig{}), ops)
+	})
+}
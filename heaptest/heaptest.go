@@ -0,0 +1,340 @@
+// Package heaptest provides conformance tests for implementations of
+// heapcraft.Heap, so that custom heaps and wrappers can be checked against the
+// same behaviour as the heaps in heapcraft itself.
+//
+// The checks run on heaps of int values and int priorities, ordered so that
+// the smallest priority is popped first. Random operations are replayed
+// against a sorted slice that serves as the model, and every result is
+// compared with it. Heaps that implement heapcraft.Verifier also have their
+// internal invariants checked along the way.
+//
+//	func TestMyHeap(t *testing.T) {
+//		heaptest.Run(t, func() heapcraft.Heap[int, int] { return NewMyHeap() })
+//	}
+package heaptest
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/galactixx/heapcraft"
+)
+
+// Factory returns a new, empty min-heap: among its elements, the one with the
+// smallest priority must be popped first.
+type Factory func() heapcraft.Heap[int, int]
+
+// Config controls how many operations the checks perform. Zero fields take
+// their default values.
+type Config struct {
+	// Operations is the number of random operations replayed by CheckModel.
+	// Defaults to 2000.
+	Operations int
+	// Seed seeds the random operations, so that a failure can be reproduced.
+	// Defaults to 1.
+	Seed int64
+	// Concurrent makes Run also call CheckConcurrent. It should only be set
+	// for heaps that are safe for concurrent use.
+	Concurrent bool
+	// Goroutines is the number of goroutines used by CheckConcurrent.
+	// Defaults to 4.
+	Goroutines int
+}
+
+// withDefaults returns a copy of c with zero fields set to their defaults.
+func (c Config) withDefaults() Config {
+	if c.Operations <= 0 {
+		c.Operations = 2000
+	}
+	if c.Seed == 0 {
+		c.Seed = 1
+	}
+	if c.Goroutines <= 0 {
+		c.Goroutines = 4
+	}
+	return c
+}
+
+// Run runs every check against heaps created by newHeap, each as its own
+// subtest, using the default Config.
+func Run(t *testing.T, newHeap Factory) {
+	RunWithConfig(t, newHeap, Config{})
+}
+
+// RunWithConfig runs every check against heaps created by newHeap, each as
+// its own subtest. CheckConcurrent only runs if config.Concurrent is set.
+func RunWithConfig(t *testing.T, newHeap Factory, config Config) {
+	t.Run("Empty", func(t *testing.T) { CheckEmpty(t, newHeap()) })
+	t.Run("Model", func(t *testing.T) { CheckModel(t, newHeap(), config) })
+	t.Run("DrainAndExport", func(t *testing.T) { CheckDrainAndExport(t, newHeap()) })
+	if config.Concurrent {
+		t.Run("Concurrent", func(t *testing.T) { CheckConcurrent(t, newHeap, config) })
+	}
+}
+
+// CheckInvariants fails the test if heap implements heapcraft.Verifier and
+// its Verify method reports an error. Other heaps pass unchecked.
+func CheckInvariants(t testing.TB, heap any) {
+	t.Helper()
+	if v, ok := heap.(heapcraft.Verifier); ok {
+		if err := v.Verify(); err != nil {
+			t.Fatalf("invariant violated: %v", err)
+		}
+	}
+}
+
+// CheckEmpty checks that an empty heap reports a length of zero and that every
+// Pop and Peek variant returns heapcraft.ErrHeapEmpty.
+func CheckEmpty(t testing.TB, heap heapcraft.Heap[int, int]) {
+	t.Helper()
+	if !heap.IsEmpty() || heap.Length() != 0 {
+		t.Fatalf("new heap has length %d, want 0", heap.Length())
+	}
+	checks := map[string]func() error{
+		"Pop":          func() error { _, _, err := heap.Pop(); return err },
+		"PopValue":     func() error { _, err := heap.PopValue(); return err },
+		"PopPriority":  func() error { _, err := heap.PopPriority(); return err },
+		"Peek":         func() error { _, _, err := heap.Peek(); return err },
+		"PeekValue":    func() error { _, err := heap.PeekValue(); return err },
+		"PeekPriority": func() error { _, err := heap.PeekPriority(); return err },
+	}
+	for name, check := range checks {
+		if err := check(); !errors.Is(err, heapcraft.ErrHeapEmpty) {
+			t.Errorf("%s on empty heap returned %v, want ErrHeapEmpty", name, err)
+		}
+	}
+	if nodes := heap.Drain(); len(nodes) != 0 {
+		t.Errorf("Drain on empty heap returned %d elements", len(nodes))
+	}
+	CheckInvariants(t, heap)
+}
+
+// model is the sorted-slice oracle that CheckModel compares a heap against.
+// Every pushed value is unique, so priorities records which priority each
+// value was pushed with.
+type model struct {
+	sorted     []int
+	priorities map[int]int
+}
+
+// push records a pushed element.
+func (m *model) push(value, priority int) {
+	i, _ := slices.BinarySearch(m.sorted, priority)
+	m.sorted = slices.Insert(m.sorted, i, priority)
+	m.priorities[value] = priority
+}
+
+// pop checks that an element popped from the heap is one that was pushed and
+// has the smallest priority, and removes it from the model.
+func (m *model) pop(t testing.TB, value, priority int) {
+	t.Helper()
+	want, pushed := m.priorities[value]
+	if !pushed {
+		t.Fatalf("popped value %d that was never pushed or was already popped", value)
+	}
+	if want != priority {
+		t.Fatalf("popped value %d with priority %d, want %d", value, priority, want)
+	}
+	if priority != m.sorted[0] {
+		t.Fatalf("popped priority %d, want smallest priority %d", priority, m.sorted[0])
+	}
+	delete(m.priorities, value)
+	m.sorted = m.sorted[1:]
+}
+
+// remove removes a pushed element from the model wherever it is in the order.
+func (m *model) remove(value int) {
+	i, _ := slices.BinarySearch(m.sorted, m.priorities[value])
+	m.sorted = slices.Delete(m.sorted, i, i+1)
+	delete(m.priorities, value)
+}
+
+// checkPeek checks that heap peeks at the smallest priority in the model, or
+// reports ErrHeapEmpty if the model is empty.
+func (m *model) checkPeek(t testing.TB, heap heapcraft.BaseHeap[int, int]) {
+	t.Helper()
+	priority, err := heap.PeekPriority()
+	if len(m.sorted) == 0 {
+		if !errors.Is(err, heapcraft.ErrHeapEmpty) {
+			t.Fatalf("PeekPriority on empty heap returned %v, want ErrHeapEmpty", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("PeekPriority with %d elements: %v", len(m.sorted), err)
+	}
+	if priority != m.sorted[0] {
+		t.Fatalf("PeekPriority returned %d, want %d", priority, m.sorted[0])
+	}
+}
+
+// checkLength checks that heap has as many elements as the model.
+func (m *model) checkLength(t testing.TB, heap heapcraft.BaseHeap[int, int]) {
+	t.Helper()
+	if heap.Length() != len(m.sorted) || heap.IsEmpty() != (len(m.sorted) == 0) {
+		t.Fatalf("heap has length %d, want %d", heap.Length(), len(m.sorted))
+	}
+}
+
+// CheckModel replays config.Operations random pushes, pops, peeks and clears
+// against heap and a sorted slice, failing the test at the first result that
+// differs. Heaps that implement heapcraft.Verifier are verified every hundred
+// operations. heap must be empty when the check starts.
+func CheckModel(t testing.TB, heap heapcraft.Heap[int, int], config Config) {
+	t.Helper()
+	config = config.withDefaults()
+	rng := rand.New(rand.NewSource(config.Seed))
+	m := &model{priorities: make(map[int]int)}
+
+	for i := 0; i < config.Operations; i++ {
+		switch op := rng.Intn(20); {
+		case op < 11 || len(m.sorted) == 0:
+			priority := rng.Intn(config.Operations / 4)
+			heap.Push(i, priority)
+			m.push(i, priority)
+		case op < 17:
+			value, priority, err := heap.Pop()
+			if err != nil {
+				t.Fatalf("Pop with %d elements: %v", len(m.sorted), err)
+			}
+			m.pop(t, value, priority)
+		case op < 19:
+			m.checkPeek(t, heap)
+		default:
+			if rng.Intn(10) == 0 {
+				heap.Clear()
+				m.sorted, m.priorities = m.sorted[:0], make(map[int]int)
+			}
+		}
+
+		m.checkLength(t, heap)
+		if i%100 == 0 {
+			CheckInvariants(t, heap)
+		}
+	}
+
+	for _, node := range heap.Drain() {
+		m.pop(t, node.Value(), node.Priority())
+	}
+	if len(m.sorted) != 0 || !heap.IsEmpty() {
+		t.Fatalf("Drain left %d elements behind", len(m.sorted))
+	}
+	CheckInvariants(t, heap)
+}
+
+// CheckDrainAndExport checks that Export returns the elements in pop order
+// without removing them, honours its Limit, Filter and Order options, and
+// that DrainValues and DrainPriorities empty the heap in pop order.
+func CheckDrainAndExport(t testing.TB, heap heapcraft.Heap[int, int]) {
+	t.Helper()
+	priorities := []int{5, 3, 8, 1, 9, 2, 7, 3, 6, 4}
+	for i, p := range priorities {
+		heap.Push(i, p)
+	}
+	sorted := slices.Clone(priorities)
+	slices.Sort(sorted)
+
+	exported := heap.Export(heapcraft.ExportOptions[int, int]{})
+	if got := nodePriorities(exported); !slices.Equal(got, sorted) {
+		t.Fatalf("Export returned priorities %v, want %v", got, sorted)
+	}
+	if heap.Length() != len(priorities) {
+		t.Fatalf("Export changed the length to %d", heap.Length())
+	}
+
+	worst := heap.Export(heapcraft.ExportOptions[int, int]{
+		Limit:  3,
+		Filter: func(value, priority int) bool { return priority%2 == 1 },
+		Order:  heapcraft.WorstFirst,
+	})
+	if got := nodePriorities(worst); !slices.Equal(got, []int{9, 7, 5}) {
+		t.Fatalf("Export with options returned priorities %v, want [9 7 5]", got)
+	}
+
+	if got := heap.DrainPriorities(); !slices.Equal(got, sorted) {
+		t.Fatalf("DrainPriorities returned %v, want %v", got, sorted)
+	}
+	if !heap.IsEmpty() {
+		t.Fatalf("DrainPriorities left %d elements behind", heap.Length())
+	}
+
+	for i, p := range priorities {
+		heap.Push(i, p)
+	}
+	values := heap.DrainValues()
+	if len(values) != len(priorities) {
+		t.Fatalf("DrainValues returned %d values, want %d", len(values), len(priorities))
+	}
+	for i, v := range values {
+		if priorities[v] != sorted[i] {
+			t.Fatalf("DrainValues returned value %d at position %d, want one with priority %d", v, i, sorted[i])
+		}
+	}
+	CheckInvariants(t, heap)
+}
+
+// CheckConcurrent pushes and pops from several goroutines at once and then
+// checks that no element was lost or popped twice, and that the remaining
+// elements drain in order. It is a smoke test for heaps that are safe for
+// concurrent use; run it with the race detector to get the most out of it.
+func CheckConcurrent(t testing.TB, newHeap Factory, config Config) {
+	t.Helper()
+	config = config.withDefaults()
+	heap := newHeap()
+	perGoroutine := config.Operations / config.Goroutines
+
+	var wg sync.WaitGroup
+	popped := make([][]int, config.Goroutines)
+	for g := 0; g < config.Goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				value := g*perGoroutine + i
+				heap.Push(value, value)
+				if i%3 == 0 {
+					if v, _, err := heap.Pop(); err == nil {
+						popped[g] = append(popped[g], v)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for _, values := range popped {
+		for _, v := range values {
+			if seen[v] {
+				t.Fatalf("value %d was popped twice", v)
+			}
+			seen[v] = true
+		}
+	}
+	remaining := heap.DrainPriorities()
+	if !slices.IsSorted(remaining) {
+		t.Fatalf("remaining elements did not drain in order")
+	}
+	for _, p := range remaining {
+		if seen[p] {
+			t.Fatalf("value %d was popped and also left in the heap", p)
+		}
+		seen[p] = true
+	}
+	if want := perGoroutine * config.Goroutines; len(seen) != want {
+		t.Fatalf("accounted for %d elements, want %d", len(seen), want)
+	}
+	CheckInvariants(t, heap)
+}
+
+// nodePriorities returns the priorities of nodes in order.
+func nodePriorities(nodes []heapcraft.HeapNode[int, int]) []int {
+	priorities := make([]int, len(nodes))
+	for i, node := range nodes {
+		priorities[i] = node.Priority()
+	}
+	return priorities
+}
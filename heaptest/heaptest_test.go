@@ -0,0 +1,106 @@
+package heaptest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galactixx/heapcraft"
+)
+
+func lt(a, b int) bool { return a < b }
+
+func TestHeaps(t *testing.T) {
+	heaps := map[string]Factory{
+		"binary":     func() heapcraft.Heap[int, int] { return heapcraft.NewBinaryHeap[int, int](nil, lt, false) },
+		"dary":       func() heapcraft.Heap[int, int] { return heapcraft.NewDaryHeap[int, int](4, nil, lt, false) },
+		"pairing":    func() heapcraft.Heap[int, int] { return heapcraft.NewPairingHeap[int, int](nil, lt, true) },
+		"auxPairing": func() heapcraft.Heap[int, int] { return heapcraft.NewAuxPairingHeap[int, int](nil, lt, false) },
+		"leftist":    func() heapcraft.Heap[int, int] { return heapcraft.NewLeftistHeap[int, int](nil, lt, false) },
+		"skew":       func() heapcraft.Heap[int, int] { return heapcraft.NewSkewHeap[int, int](nil, lt, false) },
+		"binomial": func() heapcraft.Heap[int, int] {
+			return heapcraft.NewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		},
+		"skewBinomial": func() heapcraft.Heap[int, int] {
+			return heapcraft.NewSkewBinomialHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		},
+		"adaptive": func() heapcraft.Heap[int, int] {
+			return heapcraft.NewAdaptiveHeap[int, int](nil, lt, heapcraft.HeapConfig{})
+		},
+		"mpsc": func() heapcraft.Heap[int, int] { return heapcraft.NewMPSCHeap[int, int](lt) },
+		// Without aging, an AgingHeap must behave like any other heap.
+		"aging": func() heapcraft.Heap[int, int] {
+			return heapcraft.NewAgingHeap[int, int](lt, func(p int, _ time.Duration) int { return p }, 0, nil, heapcraft.DaryHeapConfig{})
+		},
+	}
+	for name, newHeap := range heaps {
+		t.Run(name, func(t *testing.T) { Run(t, newHeap) })
+	}
+}
+
+func TestSyncHeaps(t *testing.T) {
+	heaps := map[string]Factory{
+		"syncDary":    func() heapcraft.Heap[int, int] { return heapcraft.NewSyncDaryHeap[int, int](3, nil, lt, false) },
+		"syncPairing": func() heapcraft.Heap[int, int] { return heapcraft.NewSyncPairingHeap[int, int](nil, lt, false) },
+		"syncLeftist": func() heapcraft.Heap[int, int] { return heapcraft.NewSyncLeftistHeap[int, int](nil, lt, false) },
+		"sharded": func() heapcraft.Heap[int, int] {
+			return heapcraft.NewShardedSyncHeap[int, int](4, lt, heapcraft.DaryHeapConfig{})
+		},
+		"syncHeap": func() heapcraft.Heap[int, int] {
+			return heapcraft.NewSyncHeap[int, int](heapcraft.NewSkewHeap[int, int](nil, lt, false))
+		},
+	}
+	for name, newHeap := range heaps {
+		t.Run(name, func(t *testing.T) {
+			RunWithConfig(t, newHeap, Config{Operations: 1000, Seed: 7, Concurrent: true})
+		})
+	}
+}
+
+// brokenHeap pops elements in insertion order, which CheckModel must catch.
+type brokenHeap struct {
+	heapcraft.Heap[int, int]
+	fifo []heapcraft.HeapNode[int, int]
+}
+
+func (b *brokenHeap) Push(value, priority int) {
+	b.Heap.Push(value, priority)
+	b.fifo = append(b.fifo, heapcraft.CreateHeapNode(value, priority))
+}
+
+func (b *brokenHeap) Pop() (int, int, error) {
+	if len(b.fifo) == 0 {
+		return b.Heap.Pop()
+	}
+	node := b.fifo[0]
+	b.fifo = b.fifo[1:]
+	b.Heap.Pop()
+	return node.Value(), node.Priority(), nil
+}
+
+// recorder captures failures instead of stopping the test.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failed = true
+	panic(r)
+}
+
+func TestCheckModel_DetectsWrongOrder(t *testing.T) {
+	r := &recorder{TB: t}
+	func() {
+		defer func() {
+			if p := recover(); p != nil && p != r {
+				panic(p)
+			}
+		}()
+		CheckModel(r, &brokenHeap{Heap: heapcraft.NewBinaryHeap[int, int](nil, lt, false)}, Config{})
+	}()
+	if !r.failed {
+		t.Fatal("CheckModel accepted a heap that pops in insertion order")
+	}
+}
@@ -0,0 +1,275 @@
+package heaptest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/galactixx/heapcraft"
+)
+
+// The Replay functions decode a byte slice into a sequence of heap operations
+// and check each result against a model, which makes them suitable as the
+// body of a native fuzz target:
+//
+//	func FuzzMyHeap(f *testing.F) {
+//		f.Add([]byte{0, 5, 0, 3, 4, 4})
+//		f.Fuzz(func(t *testing.T, ops []byte) {
+//			heaptest.Replay(t, NewMyHeap(), ops)
+//		})
+//	}
+//
+// Every byte selects an operation, and the bytes that follow it supply its
+// operands, such as a priority or which tracked element to update. Missing
+// operands read as zero, so every byte slice decodes to a valid sequence.
+
+// opReader reads operation codes and operands from a byte slice.
+type opReader struct {
+	data []byte
+}
+
+// more reports whether any bytes are left to read.
+func (r *opReader) more() bool { return len(r.data) > 0 }
+
+// next returns the next byte, or zero once the data is used up.
+func (r *opReader) next() byte {
+	if len(r.data) == 0 {
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+// Replay decodes ops into pushes, pops, peeks and clears, applies them to
+// heap and checks every result against a sorted-slice model, verifying the
+// heap after each operation if it implements heapcraft.Verifier. heap must be
+// empty and pop the smallest priority first.
+func Replay(t testing.TB, heap heapcraft.Heap[int, int], ops []byte) {
+	t.Helper()
+	r := &opReader{data: ops}
+	m := &model{priorities: make(map[int]int)}
+	for value := 0; r.more(); value++ {
+		switch r.next() % 8 {
+		case 0, 1, 2, 3:
+			priority := int(r.next())
+			heap.Push(value, priority)
+			m.push(value, priority)
+		case 4, 5:
+			checkPop(t, heap, m)
+		case 6:
+			m.checkPeek(t, heap)
+		case 7:
+			heap.Clear()
+			m.sorted, m.priorities = m.sorted[:0], make(map[int]int)
+		}
+		m.checkLength(t, heap)
+		CheckInvariants(t, heap)
+	}
+	for !heap.IsEmpty() {
+		checkPop(t, heap, m)
+	}
+	m.checkLength(t, heap)
+}
+
+// checkPop pops from heap and checks the result against the model. Returns
+// the popped value, and false if the heap was empty.
+func checkPop(t testing.TB, heap heapcraft.BaseHeap[int, int], m *model) (int, bool) {
+	t.Helper()
+	value, priority, err := heap.Pop()
+	if len(m.sorted) == 0 {
+		if !errors.Is(err, heapcraft.ErrHeapEmpty) {
+			t.Fatalf("Pop on empty heap returned %v, want ErrHeapEmpty", err)
+		}
+		return 0, false
+	}
+	if err != nil {
+		t.Fatalf("Pop with %d elements: %v", len(m.sorted), err)
+	}
+	m.pop(t, value, priority)
+	return value, true
+}
+
+// tracked maps the values pushed to a TrackedHeap to the IDs it returned, and
+// keeps the IDs of elements still in the heap in a slice so that operands can
+// pick one by index.
+type tracked struct {
+	ids   []string
+	idOf  map[int]string
+	value map[string]int
+}
+
+// pick returns the ID at index b modulo the number of live elements.
+func (tr *tracked) pick(b byte) string { return tr.ids[int(b)%len(tr.ids)] }
+
+// add records that value was pushed with the given ID.
+func (tr *tracked) add(id string, value int) {
+	tr.ids = append(tr.ids, id)
+	tr.idOf[value] = id
+	tr.value[id] = value
+}
+
+// drop forgets the element with the given value.
+func (tr *tracked) drop(value int) {
+	id := tr.idOf[value]
+	for i := range tr.ids {
+		if tr.ids[i] == id {
+			tr.ids = append(tr.ids[:i], tr.ids[i+1:]...)
+			break
+		}
+	}
+	delete(tr.idOf, value)
+	delete(tr.value, id)
+}
+
+// ReplayTracked decodes ops into pushes, pops, lookups, priority and value
+// updates and removals by ID, applies them to heap and checks every result
+// against a model, verifying the heap after each operation if it implements
+// heapcraft.Verifier. Updates move elements both towards and away from the
+// root, which exercises the restructuring paths of UpdatePriority. heap must
+// be empty and pop the smallest priority first.
+func ReplayTracked(t testing.TB, heap heapcraft.TrackedHeap[int, int], ops []byte) {
+	t.Helper()
+	r := &opReader{data: ops}
+	m := &model{priorities: make(map[int]int)}
+	tr := &tracked{idOf: make(map[int]string), value: make(map[string]int)}
+	value := 0
+	for r.more() {
+		code := r.next() % 8
+		if code > 2 && len(tr.ids) == 0 {
+			code = 0
+		}
+		switch code {
+		case 0, 1:
+			priority := int(r.next())
+			id, err := heap.Push(value, priority)
+			if err != nil {
+				t.Fatalf("Push: %v", err)
+			}
+			m.push(value, priority)
+			tr.add(id, value)
+			value++
+		case 2:
+			if v, ok := checkPop(t, heap, m); ok {
+				tr.drop(v)
+			}
+		case 3, 4:
+			id, priority := tr.pick(r.next()), int(r.next())
+			if err := heap.UpdatePriority(id, priority); err != nil {
+				t.Fatalf("UpdatePriority(%q, %d): %v", id, priority, err)
+			}
+			v := tr.value[id]
+			m.remove(v)
+			m.push(v, priority)
+		case 5:
+			id := tr.pick(r.next())
+			v, priority, err := heap.Remove(id)
+			if err != nil {
+				t.Fatalf("Remove(%q): %v", id, err)
+			}
+			if v != tr.value[id] || priority != m.priorities[v] {
+				t.Fatalf("Remove(%q) returned (%d, %d), want (%d, %d)", id, v, priority, tr.value[id], m.priorities[tr.value[id]])
+			}
+			m.remove(v)
+			tr.drop(v)
+		case 6:
+			id := tr.pick(r.next())
+			v, priority, err := heap.Get(id)
+			if err != nil {
+				t.Fatalf("Get(%q): %v", id, err)
+			}
+			if v != tr.value[id] || priority != m.priorities[v] {
+				t.Fatalf("Get(%q) returned (%d, %d), want (%d, %d)", id, v, priority, tr.value[id], m.priorities[tr.value[id]])
+			}
+		case 7:
+			id := tr.pick(r.next())
+			old := tr.value[id]
+			if err := heap.UpdateValue(id, value); err != nil {
+				t.Fatalf("UpdateValue(%q): %v", id, err)
+			}
+			priority := m.priorities[old]
+			m.remove(old)
+			tr.drop(old)
+			m.push(value, priority)
+			tr.add(id, value)
+			value++
+		}
+		m.checkLength(t, heap)
+		m.checkPeek(t, heap)
+		CheckInvariants(t, heap)
+	}
+	for !heap.IsEmpty() {
+		checkPop(t, heap, m)
+	}
+	m.checkLength(t, heap)
+}
+
+// MonotoneHeap is a min-heap whose Push rejects priorities below the last one
+// popped, such as heapcraft.RadixHeap and heapcraft.MultiLevelRadixHeap.
+type MonotoneHeap interface {
+	heapcraft.BaseHeap[int, uint]
+	Push(value int, priority uint) error
+}
+
+// ReplayMonotone decodes ops into pushes, pops and clears, applies them to
+// heap and checks every result against a sorted-slice model. Pushes are
+// usually at or above the last popped priority, and must succeed; the rest
+// fall below it and must fail with heapcraft.ErrPriorityLessThanLast unless
+// the heap is empty, in which case any priority is accepted. heap must be
+// empty when the replay starts.
+func ReplayMonotone(t testing.TB, heap MonotoneHeap, ops []byte) {
+	t.Helper()
+	r := &opReader{data: ops}
+	m := &model{priorities: make(map[int]int)}
+	last := 0
+	for value := 0; r.more(); value++ {
+		switch code := r.next(); code % 4 {
+		case 0, 1:
+			delta := int(r.next())
+			priority, below := last+delta, code&0x80 != 0 && last > 0
+			if below {
+				priority = last - 1 - delta%last
+			}
+			err := heap.Push(value, uint(priority))
+			if below && len(m.sorted) > 0 {
+				if !errors.Is(err, heapcraft.ErrPriorityLessThanLast) {
+					t.Fatalf("Push(%d) with last %d returned %v, want ErrPriorityLessThanLast", priority, last, err)
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("Push(%d) with last %d: %v", priority, last, err)
+			}
+			if len(m.sorted) == 0 {
+				last = priority
+			}
+			m.push(value, priority)
+		case 2:
+			v, priority, err := heap.Pop()
+			if len(m.sorted) == 0 {
+				if !errors.Is(err, heapcraft.ErrHeapEmpty) {
+					t.Fatalf("Pop on empty heap returned %v, want ErrHeapEmpty", err)
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("Pop with %d elements: %v", len(m.sorted), err)
+			}
+			m.pop(t, v, int(priority))
+			last = int(priority)
+		case 3:
+			heap.Clear()
+			m.sorted, m.priorities = m.sorted[:0], make(map[int]int)
+			last = 0
+		}
+		if heap.Length() != len(m.sorted) {
+			t.Fatalf("heap has length %d, want %d", heap.Length(), len(m.sorted))
+		}
+		CheckInvariants(t, heap)
+	}
+	for v, priority, err := heap.Pop(); err == nil; v, priority, err = heap.Pop() {
+		m.pop(t, v, int(priority))
+	}
+	if len(m.sorted) != 0 {
+		t.Fatalf("heap lost %d elements", len(m.sorted))
+	}
+}
@@ -1,7 +1,13 @@
 package heapcraft
 
 import (
+	"bufio"
+	"crypto/rand"
+	"iter"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,6 +16,29 @@ import (
 // that can generate unique IDs.
 type IDGenerator interface{ Next() string }
 
+// BatchIDGenerator is an IDGenerator that can also issue many IDs in one
+// call. Bulk operations such as PushAll use NextN when the configured
+// generator implements it, and fall back to calling Next once per ID
+// otherwise, so existing generators keep working unchanged.
+type BatchIDGenerator interface {
+	IDGenerator
+	// NextN returns n new IDs, each as unique as one returned by Next.
+	NextN(n int) []string
+}
+
+// NextIDs returns n IDs from gen, using NextN in a single call if gen is a
+// BatchIDGenerator and calling Next n times otherwise.
+func NextIDs(gen IDGenerator, n int) []string {
+	if batch, ok := gen.(BatchIDGenerator); ok {
+		return batch.NextN(n)
+	}
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = gen.Next()
+	}
+	return ids
+}
+
 // IntegerIDGenerator is a generator that uses integers.
 type IntegerIDGenerator struct{ NextID int }
 
@@ -20,6 +49,16 @@ func (g *IntegerIDGenerator) Next() string {
 	return intID
 }
 
+// NextN returns the next n integer IDs as strings.
+func (g *IntegerIDGenerator) NextN(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(g.NextID + i)
+	}
+	g.NextID += n
+	return ids
+}
+
 // UUIDGenerator is a generator that uses UUIDs.
 type UUIDGenerator struct{}
 
@@ -27,3 +66,236 @@ type UUIDGenerator struct{}
 func (g *UUIDGenerator) Next() string {
 	return uuid.New().String()
 }
+
+// uuidBufferSize caps the buffer of random bytes NextN reads through, which
+// is 16 bytes per UUID.
+const uuidBufferSize = 4096
+
+// NextN returns n new UUIDs as strings (UUIDv4). The random bytes for the
+// whole batch are read from crypto/rand through a single buffer instead of
+// one read per UUID.
+func (g *UUIDGenerator) NextN(n int) []string {
+	ids := make([]string, n)
+	if n == 0 {
+		return ids
+	}
+	random := bufio.NewReaderSize(rand.Reader, min(16*n, uuidBufferSize))
+	for i := range ids {
+		ids[i] = uuid.Must(uuid.NewRandomFromReader(random)).String()
+	}
+	return ids
+}
+
+// AtomicIDGenerator is a generator that uses integers like IntegerIDGenerator,
+// but draws them from an atomic counter so that one generator can be shared by
+// heaps used from different goroutines. The zero value starts at 0.
+type AtomicIDGenerator struct{ next atomic.Uint64 }
+
+// Next returns the next integer ID as a string.
+func (g *AtomicIDGenerator) Next() string {
+	return strconv.FormatUint(g.next.Add(1)-1, 10)
+}
+
+// NextN returns the next n integer IDs as strings, reserving the whole range
+// with a single atomic add.
+func (g *AtomicIDGenerator) NextN(n int) []string {
+	ids := make([]string, n)
+	start := g.next.Add(uint64(n)) - uint64(n)
+	for i := range ids {
+		ids[i] = strconv.FormatUint(start+uint64(i), 10)
+	}
+	return ids
+}
+
+// advance moves the counter past id, if it is below it.
+func (g *AtomicIDGenerator) advance(id uint64) {
+	for {
+		next := g.next.Load()
+		if id < next || g.next.CompareAndSwap(next, id+1) {
+			return
+		}
+	}
+}
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeSequenceMask = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeIDGenerator is a generator of snowflake-style IDs: a millisecond
+// timestamp relative to Epoch, followed by a 10-bit Node number and a 12-bit
+// sequence, formatted as a decimal string. IDs from generators with distinct
+// Node numbers never collide, so separate processes can issue IDs without
+// coordinating, and IDs from one generator increase over time. More than 4096
+// IDs in one millisecond, or a clock that moves backwards, borrow from the
+// following milliseconds instead of blocking. It is safe for concurrent use.
+type SnowflakeIDGenerator struct {
+	// Node distinguishes generators; only its low 10 bits are used.
+	Node uint16
+	// Epoch is the origin of the timestamps. If zero, the Unix epoch is used.
+	Epoch time.Time
+
+	mu   sync.Mutex
+	last int64
+	seq  int64
+}
+
+// Next returns a new snowflake ID as a string.
+func (g *SnowflakeIDGenerator) Next() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return strconv.FormatInt(g.next(), 10)
+}
+
+// NextN returns n new snowflake IDs as strings, taking the generator's lock
+// once for the whole batch.
+func (g *SnowflakeIDGenerator) NextN(n int) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.FormatInt(g.next(), 10)
+	}
+	return ids
+}
+
+// next is an internal method that returns the next ID as an integer. The
+// caller must hold the lock.
+func (g *SnowflakeIDGenerator) next() int64 {
+	var now int64
+	if g.Epoch.IsZero() {
+		now = time.Now().UnixMilli()
+	} else {
+		now = time.Since(g.Epoch).Milliseconds()
+	}
+	if now > g.last {
+		g.last, g.seq = now, 0
+	} else if g.seq++; g.seq > snowflakeSequenceMask {
+		g.last, g.seq = g.last+1, 0
+	}
+	node := int64(g.Node) & (1<<snowflakeNodeBits - 1)
+	return g.last<<(snowflakeNodeBits+snowflakeSequenceBits) | node<<snowflakeSequenceBits | g.seq
+}
+
+// DefaultIDBlockSize is the number of IDs a BlockIDGenerator draws at a time
+// when BlockSize is not set.
+const DefaultIDBlockSize = 1024
+
+// BlockIDGenerator hands out IDs from a block that it fills from Source with a
+// single NextIDs call whenever the block runs out. With a BatchIDGenerator
+// source such as UUIDGenerator, this moves the cost of generation out of most
+// Push calls and into one batched call per block. Like the generator it wraps,
+// it is not safe for concurrent use.
+type BlockIDGenerator struct {
+	// Source produces the IDs. If nil, UUIDGenerator is used.
+	Source IDGenerator
+	// BlockSize is the number of IDs drawn at a time. If not positive,
+	// DefaultIDBlockSize is used.
+	BlockSize int
+
+	block []string
+}
+
+// refill is an internal method that draws a new block of IDs from the source.
+func (g *BlockIDGenerator) refill() {
+	if g.Source == nil {
+		g.Source = &UUIDGenerator{}
+	}
+	size := g.BlockSize
+	if size <= 0 {
+		size = DefaultIDBlockSize
+	}
+	g.block = NextIDs(g.Source, size)
+}
+
+// Next returns the next ID of the current block, drawing a new block first if
+// it is empty.
+func (g *BlockIDGenerator) Next() string {
+	if len(g.block) == 0 {
+		g.refill()
+	}
+	id := g.block[0]
+	g.block = g.block[1:]
+	return id
+}
+
+// NextN returns n IDs, taking what is left of the current block first and
+// drawing the rest from the source in one call.
+func (g *BlockIDGenerator) NextN(n int) []string {
+	ids := make([]string, 0, n)
+	take := min(n, len(g.block))
+	ids = append(ids, g.block[:take]...)
+	g.block = g.block[take:]
+	if rest := n - take; rest > 0 {
+		if g.Source == nil {
+			g.Source = &UUIDGenerator{}
+		}
+		ids = append(ids, NextIDs(g.Source, rest)...)
+	}
+	return ids
+}
+
+// registrationIDs issues the IDs of registered callbacks, alarms and
+// maintenance tasks. They only have to be unique, so a shared counter is used
+// instead of UUIDs.
+var registrationIDs AtomicIDGenerator
+
+// advanceIDGenerator moves an IntegerIDGenerator or AtomicIDGenerator past an
+// ID that was restored into a heap from a checkpoint, so that newly generated
+// IDs do not collide with it. Other generators and non-numeric IDs are left
+// untouched.
+func advanceIDGenerator(gen IDGenerator, id string) {
+	switch g := gen.(type) {
+	case *IntegerIDGenerator:
+		if n, err := strconv.Atoi(id); err == nil && n >= g.NextID {
+			g.NextID = n + 1
+		}
+	case *AtomicIDGenerator:
+		if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+			g.advance(n)
+		}
+	}
+}
+
+// generateIDs draws n IDs from gen in one batch for a bulk insert. It fails with
+// ErrIDGenerationFailed if an ID collides with a key of elements or with
+// another ID of the batch, so the insert can be rejected before the heap is
+// modified.
+func generateIDs[N any](gen IDGenerator, n int, elements map[string]N) ([]string, error) {
+	ids := NextIDs(gen, n)
+	batch := make(map[string]struct{}, n)
+	for _, id := range ids {
+		if _, exists := elements[id]; exists {
+			return nil, ErrIDGenerationFailed
+		}
+		if _, exists := batch[id]; exists {
+			return nil, ErrIDGenerationFailed
+		}
+		batch[id] = struct{}{}
+	}
+	return ids, nil
+}
+
+// reassignIDs gives every node of a freshly cloned heap a new ID drawn from
+// gen. New IDs are handed out in the order nodes yields them, so the result
+// only depends on the heap and the generator. It returns the element map keyed
+// by the new IDs and a mapping from each old ID to its new one, or
+// ErrIDGenerationFailed if a new ID collides with an old one.
+func reassignIDs[N any](gen IDGenerator, nodes iter.Seq[N], elements map[string]N, id func(N) *string) (map[string]N, map[string]string, error) {
+	ids, err := generateIDs(gen, len(elements), elements)
+	if err != nil {
+		return nil, nil, err
+	}
+	renamed := make(map[string]N, len(elements))
+	mapping := make(map[string]string, len(elements))
+	i := 0
+	for node := range nodes {
+		field := id(node)
+		mapping[*field] = ids[i]
+		*field = ids[i]
+		renamed[ids[i]] = node
+		i++
+	}
+	return renamed, mapping, nil
+}
@@ -1,9 +1,14 @@
 package heapcraft
 
 import (
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntegerIDGenerator(t *testing.T) {
@@ -56,3 +61,246 @@ func TestIDGeneratorInterface(t *testing.T) {
 	id := generator.Next()
 	assert.Len(t, id, 36)
 }
+
+// countingGenerator implements only Next, as generators written before
+// BatchIDGenerator existed do.
+type countingGenerator struct{ calls int }
+
+func (g *countingGenerator) Next() string {
+	g.calls++
+	return "id-" + strconv.Itoa(g.calls)
+}
+
+func TestNextIDs(t *testing.T) {
+	integers := &IntegerIDGenerator{NextID: 5}
+	assert.Equal(t, []string{"5", "6", "7"}, NextIDs(integers, 3))
+	assert.Equal(t, "8", integers.Next())
+	assert.Empty(t, NextIDs(integers, 0))
+
+	uuids := NextIDs(&UUIDGenerator{}, 300)
+	seen := make(map[string]struct{}, len(uuids))
+	for _, id := range uuids {
+		parsed, err := uuid.Parse(id)
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(4), parsed.Version())
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, 300)
+
+	legacy := &countingGenerator{}
+	assert.Equal(t, []string{"id-1", "id-2"}, NextIDs(legacy, 2))
+	assert.Equal(t, 2, legacy.calls)
+}
+
+func TestFullLeftistHeap_UsesConfiguredGenerator(t *testing.T) {
+	data := []HeapNode[int, int]{CreateHeapNode(1, 3), CreateHeapNode(2, 1)}
+	heap := NewFullLeftistHeap(data, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+
+	value, err := heap.GetValue("0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	value, err = heap.GetValue("1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
+
+func TestAtomicIDGenerator(t *testing.T) {
+	generator := &AtomicIDGenerator{}
+	assert.Equal(t, "0", generator.Next())
+	assert.Equal(t, []string{"1", "2", "3"}, generator.NextN(3))
+
+	const workers, perWorker = 8, 500
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[string]struct{}, workers*perWorker)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id := generator.Next()
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, seen, workers*perWorker)
+
+	advanceIDGenerator(generator, "99999")
+	assert.Equal(t, "100000", generator.Next())
+	advanceIDGenerator(generator, "5")
+	assert.Equal(t, "100001", generator.Next())
+}
+
+func TestSnowflakeIDGenerator(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+	generator := &SnowflakeIDGenerator{Node: 7, Epoch: epoch}
+
+	// More IDs than fit in one millisecond's sequence must stay unique and
+	// increasing.
+	ids := NextIDs(generator, 3*(snowflakeSequenceMask+1))
+	previous := int64(-1)
+	for _, id := range ids {
+		n, err := strconv.ParseInt(id, 10, 64)
+		require.NoError(t, err)
+		require.Greater(t, n, previous)
+		assert.Equal(t, int64(7), n>>snowflakeSequenceBits&(1<<snowflakeNodeBits-1))
+		previous = n
+	}
+
+	other := &SnowflakeIDGenerator{Node: 8, Epoch: epoch}
+	assert.NotEqual(t, generator.Next(), other.Next())
+}
+
+func TestBlockIDGenerator(t *testing.T) {
+	source := &countingGenerator{}
+	generator := &BlockIDGenerator{Source: source, BlockSize: 4}
+
+	assert.Equal(t, "id-1", generator.Next())
+	assert.Equal(t, 4, source.calls)
+	assert.Equal(t, []string{"id-2", "id-3", "id-4", "id-5", "id-6"}, generator.NextN(5))
+	assert.Equal(t, "id-7", generator.Next())
+	assert.Equal(t, 10, source.calls)
+
+	uuids := &BlockIDGenerator{}
+	_, err := uuid.Parse(uuids.Next())
+	assert.NoError(t, err)
+	assert.Len(t, uuids.block, DefaultIDBlockSize-1)
+}
+
+func TestFullSkewHeap_UsesBlockGenerator(t *testing.T) {
+	generator := &BlockIDGenerator{Source: &IntegerIDGenerator{}, BlockSize: 2}
+	heap := NewFullSkewHeap[int](nil, lt, HeapConfig{IDGenerator: generator})
+	for i := 0; i < 5; i++ {
+		id, err := heap.Push(i, i)
+		require.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(i), id)
+	}
+	require.NoError(t, heap.Verify())
+}
+
+// -------------------------------- ID Generator Benchmarks --------------------------------
+
+func benchmarkIDGenerator(b *testing.B, generator IDGenerator) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		generator.Next()
+	}
+}
+
+func BenchmarkUUIDGenerator(b *testing.B) { benchmarkIDGenerator(b, &UUIDGenerator{}) }
+
+func BenchmarkAtomicIDGenerator(b *testing.B) { benchmarkIDGenerator(b, &AtomicIDGenerator{}) }
+
+func BenchmarkSnowflakeIDGenerator(b *testing.B) { benchmarkIDGenerator(b, &SnowflakeIDGenerator{}) }
+
+func BenchmarkBlockIDGenerator(b *testing.B) { benchmarkIDGenerator(b, &BlockIDGenerator{}) }
+
+// freshCloneCase builds a tracked heap with an IntegerIDGenerator and exposes
+// what TestCloneFresh needs from it and from its fresh clones.
+type freshCloneCase struct {
+	push       func(v, p int) string
+	cloneFresh func() (func(id string) (int, error), func() error, map[string]string, error)
+	popValue   func() (int, error)
+}
+
+func TestCloneFresh(t *testing.T) {
+	cases := map[string]func() freshCloneCase{
+		"pairing": func() freshCloneCase {
+			h := NewFullPairingHeap[int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+			return freshCloneCase{
+				push: func(v, p int) string { id, _ := h.Push(v, p); return id },
+				cloneFresh: func() (func(string) (int, error), func() error, map[string]string, error) {
+					c, ids, err := h.CloneFresh()
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					return c.GetValue, c.Verify, ids, nil
+				},
+				popValue: h.PopValue,
+			}
+		},
+		"leftist": func() freshCloneCase {
+			h := NewSyncFullLeftistHeap[int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+			return freshCloneCase{
+				push: func(v, p int) string { id, _ := h.Push(v, p); return id },
+				cloneFresh: func() (func(string) (int, error), func() error, map[string]string, error) {
+					c, ids, err := h.CloneFresh()
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					return c.GetValue, c.Verify, ids, nil
+				},
+				popValue: h.PopValue,
+			}
+		},
+		"skew": func() freshCloneCase {
+			h := NewFullSkewHeap[int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+			return freshCloneCase{
+				push: func(v, p int) string { id, _ := h.Push(v, p); return id },
+				cloneFresh: func() (func(string) (int, error), func() error, map[string]string, error) {
+					c, ids, err := h.CloneFresh()
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					return c.GetValue, c.Verify, ids, nil
+				},
+				popValue: h.PopValue,
+			}
+		},
+	}
+
+	for name, build := range cases {
+		t.Run(name, func(t *testing.T) {
+			template := build()
+			original := make(map[string]int)
+			for _, p := range []int{30, 10, 50, 20, 40} {
+				original[template.push(p, p)] = p
+			}
+
+			getFirst, verifyFirst, first, err := template.cloneFresh()
+			require.NoError(t, err)
+			require.NoError(t, verifyFirst())
+			_, _, second, err := template.cloneFresh()
+			require.NoError(t, err)
+
+			// IDs are assigned in pop order, continuing from the template's
+			// generator, and siblings never share one.
+			byPriority := map[int]string{10: "5", 20: "6", 30: "7", 40: "8", 50: "9"}
+			firstIDs := make(map[string]struct{}, len(first))
+			for _, id := range first {
+				firstIDs[id] = struct{}{}
+			}
+			for oldID, p := range original {
+				assert.Equal(t, byPriority[p], first[oldID])
+				value, err := getFirst(first[oldID])
+				require.NoError(t, err)
+				assert.Equal(t, p, value)
+				_, err = getFirst(oldID)
+				assert.ErrorIs(t, err, ErrNodeNotFound)
+				assert.NotContains(t, firstIDs, second[oldID])
+			}
+			assert.Len(t, second, len(original))
+
+			// The template keeps its own IDs and elements.
+			value, err := template.popValue()
+			require.NoError(t, err)
+			assert.Equal(t, 10, value)
+		})
+	}
+}
+
+func TestCloneFresh_Collision(t *testing.T) {
+	heap := NewFullSkewHeap[int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	heap.Push(1, 1)
+	heap.Push(2, 2)
+	heap.idGen = &IntegerIDGenerator{NextID: 1}
+
+	cloned, ids, err := heap.CloneFresh()
+	assert.ErrorIs(t, err, ErrIDGenerationFailed)
+	assert.Nil(t, cloned)
+	assert.Nil(t, ids)
+	assert.Equal(t, 2, heap.Length())
+}
@@ -0,0 +1,254 @@
+package heapcraft
+
+// indexedEntry is a value stored in an IndexedDaryHeap together with the ID
+// it was assigned on insertion.
+type indexedEntry[V any] struct {
+	id    string
+	value V
+}
+
+// IndexedDaryHeap is a d-ary heap that assigns an ID to every pushed element
+// and keeps an ID-to-index map up to date through the heap's swap callbacks.
+// It supports updating and removing elements by ID in O(log n), which makes
+// decrease-key available on the array-based heap without registering a
+// callback by hand. The heap is not safe for concurrent use; use
+// SyncIndexedDaryHeap for that.
+type IndexedDaryHeap[V any, P any] struct {
+	heap   *DaryHeap[indexedEntry[V], P]
+	index  map[string]int
+	idGen  IDGenerator
+	events listeners[HeapEvent[V, P]]
+}
+
+// track records the new positions of the elements at indices x and y after
+// the underlying heap has swapped them.
+func (h *IndexedDaryHeap[V, P]) track(x, y int) {
+	h.index[h.heap.data[x].value.id] = x
+	h.index[h.heap.data[y].value.id] = y
+}
+
+// Push inserts a new element into the heap and returns its ID. Returns
+// ErrIDGenerationFailed if the generated ID is already in use.
+func (h *IndexedDaryHeap[V, P]) Push(value V, priority P) (string, error) {
+	id := h.idGen.Next()
+	if _, exists := h.index[id]; exists {
+		return "", ErrIDGenerationFailed
+	}
+	h.index[id] = h.heap.Length()
+	h.heap.Push(indexedEntry[V]{id: id, value: value}, priority)
+	emitHeapEvent(h.events, EventPush, id, value, priority)
+	return id, nil
+}
+
+// IndexOf returns the current index of the element with the given ID in the
+// underlying array. Returns an error if the ID does not exist.
+func (h *IndexedDaryHeap[V, P]) IndexOf(id string) (int, error) {
+	i, exists := h.index[id]
+	if !exists {
+		return 0, &NodeNotFoundError{ID: id}
+	}
+	return i, nil
+}
+
+// get is an internal method that returns the value and priority of the
+// element with the given ID.
+func (h *IndexedDaryHeap[V, P]) get(id string) (V, P, error) {
+	i, exists := h.index[id]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &NodeNotFoundError{ID: id}
+	}
+	node := h.heap.data[i]
+	return node.value.value, node.priority, nil
+}
+
+// Get returns the value and priority of the element with the given ID.
+// Returns zero values and an error if the ID does not exist.
+func (h *IndexedDaryHeap[V, P]) Get(id string) (V, P, error) { return h.get(id) }
+
+// GetValue returns the value of the element with the given ID. Returns zero
+// value and an error if the ID does not exist.
+func (h *IndexedDaryHeap[V, P]) GetValue(id string) (V, error) {
+	return valueFromNode(h.get(id))
+}
+
+// GetPriority returns the priority of the element with the given ID. Returns
+// zero value and an error if the ID does not exist.
+func (h *IndexedDaryHeap[V, P]) GetPriority(id string) (P, error) {
+	return priorityFromNode(h.get(id))
+}
+
+// UpdateByID replaces the value and priority of the element with the given
+// ID and restores the heap order by sifting it up or down. Returns an error
+// if the ID does not exist.
+func (h *IndexedDaryHeap[V, P]) UpdateByID(id string, value V, priority P) error {
+	i, exists := h.index[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	h.heap.data[i].value.value = value
+	h.heap.data[i].priority = priority
+	h.heap.restoreHeap(i)
+	emitHeapEvent(h.events, EventUpdate, id, value, priority)
+	return nil
+}
+
+// RemoveByID deletes the element with the given ID from the heap and returns
+// its value and priority. Returns an error if the ID does not exist.
+func (h *IndexedDaryHeap[V, P]) RemoveByID(id string) (V, P, error) {
+	i, exists := h.index[id]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &NodeNotFoundError{ID: id}
+	}
+	removed := h.heap.removeAt(i)
+	delete(h.index, id)
+	v, p := removed.value.value, removed.priority
+	emitHeapEvent(h.events, EventRemove, id, v, p)
+	return v, p, nil
+}
+
+// Clear removes all elements from the heap.
+func (h *IndexedDaryHeap[V, P]) Clear() {
+	h.heap.Clear()
+	clear(h.index)
+	emitClearEvent(h.events)
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its array, its
+// index map and the element IDs. Memory that values and priorities point to is
+// not counted. Summing the IDs makes it O(n).
+func (h *IndexedDaryHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[IndexedDaryHeap[V, P]]() + h.heap.ApproxMemoryUsage() + mapMemory[string, int](len(h.index)) + idMemory(h.index)
+}
+
+// Stats returns the operations the heap has performed since it was created,
+// as counted by the underlying d-ary heap. Removals by ID count as
+// removes, and updates are not counted.
+func (h *IndexedDaryHeap[V, P]) Stats() HeapStats { return h.heap.Stats() }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (h *IndexedDaryHeap[V, P]) Instrument(hook Instrumentation) { h.heap.Instrument(hook) }
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, with the element's ID, and whenever the heap is cleared. Returns
+// an ID that can be passed to RemoveListener.
+func (h *IndexedDaryHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return h.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *IndexedDaryHeap[V, P]) RemoveListener(id string) error { return h.events.deregister(id) }
+
+// Length returns the number of elements in the heap.
+func (h *IndexedDaryHeap[V, P]) Length() int { return h.heap.Length() }
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *IndexedDaryHeap[V, P]) IsEmpty() bool { return h.heap.IsEmpty() }
+
+// Verify checks the internal consistency of the heap: the underlying d-ary
+// heap must pass its own Verify, and the ID map must hold exactly one entry
+// per element, pointing at that element's current index. It is intended for
+// tests and debugging, runs in O(n) and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (h *IndexedDaryHeap[V, P]) Verify() error {
+	if err := h.heap.Verify(); err != nil {
+		return err
+	}
+	for i, node := range h.heap.data {
+		if j, exists := h.index[node.value.id]; !exists || j != i {
+			return invariantError("element %q at index %d is indexed at %d", node.value.id, i, j)
+		}
+	}
+	return verifyElements(len(h.index), h.heap.Length())
+}
+
+// peek is an internal method that returns the root element without removing
+// it.
+func (h *IndexedDaryHeap[V, P]) peek() (V, P, error) {
+	entry, priority, err := h.heap.Peek()
+	return entry.value, priority, err
+}
+
+// Peek returns the value and priority of the root element without removing
+// it. Returns zero values and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) Peek() (V, P, error) { return h.peek() }
+
+// PeekValue returns the value of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(h.peek())
+}
+
+// PeekPriority returns the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(h.peek())
+}
+
+// pop is an internal method that removes and returns the root element and
+// forgets its ID.
+func (h *IndexedDaryHeap[V, P]) pop() (V, P, error) {
+	entry, priority, err := h.heap.Pop()
+	if err == nil {
+		delete(h.index, entry.id)
+		emitHeapEvent(h.events, EventPop, entry.id, entry.value, priority)
+	}
+	return entry.value, priority, err
+}
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) Pop() (V, P, error) { return h.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(h.Peek, h.Pop, pred)
+}
+
+// PopValue removes and returns the value of the root element. Returns zero
+// value and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(h.pop())
+}
+
+// PopPriority removes and returns the priority of the root element. Returns
+// zero value and an error if the heap is empty.
+func (h *IndexedDaryHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(h.pop())
+}
+
+// Drain removes all elements from the heap and returns them in priority
+// order. The heap is empty afterwards.
+func (h *IndexedDaryHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(h.Length(), h.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (h *IndexedDaryHeap[V, P]) DrainValues() []V {
+	return drainValues(h.Length(), h.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (h *IndexedDaryHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(h.Length(), h.pop)
+}
+
+// forEach calls fn for every element in the heap, in storage order.
+func (h *IndexedDaryHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range h.heap.data {
+		fn(node.value.value, node.priority)
+	}
+}
+
+// Export returns a copy of the elements in the heap according to opts. The
+// heap itself is not modified.
+func (h *IndexedDaryHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(h.Length(), h.forEach, h.heap.cmp, opts)
+}
@@ -0,0 +1,21 @@
+package heapcraft
+
+// NewIndexedDaryHeap creates an empty IndexedDaryHeap with arity d. The
+// comparison function determines the heap order (min or max), and config
+// selects pooling, the generator used for element IDs and the number of
+// elements to reserve room for.
+func NewIndexedDaryHeap[V any, P any](d int, cmp func(a, b P) bool, config HeapConfig) *IndexedDaryHeap[V, P] {
+	h := &IndexedDaryHeap[V, P]{
+		heap:  NewDaryHeapWithConfig(d, make([]HeapNode[indexedEntry[V], P], 0, config.Capacity), cmp, DaryHeapConfig{}),
+		index: make(map[string]int, config.Capacity),
+		idGen: config.GetGenerator(),
+	}
+	h.heap.Register(h.track)
+	return h
+}
+
+// NewSyncIndexedDaryHeap creates an empty thread-safe IndexedDaryHeap with
+// arity d. The comparison function determines the heap order (min or max).
+func NewSyncIndexedDaryHeap[V any, P any](d int, cmp func(a, b P) bool, config HeapConfig) *SyncIndexedDaryHeap[V, P] {
+	return &SyncIndexedDaryHeap[V, P]{heap: NewIndexedDaryHeap[V, P](d, cmp, config)}
+}
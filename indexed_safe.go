@@ -0,0 +1,216 @@
+package heapcraft
+
+import "sync"
+
+// SyncIndexedDaryHeap is a thread-safe wrapper around IndexedDaryHeap.
+type SyncIndexedDaryHeap[V any, P any] struct {
+	heap *IndexedDaryHeap[V, P]
+	lock sync.RWMutex
+}
+
+// Push inserts a new element into the heap and returns its ID.
+func (h *SyncIndexedDaryHeap[V, P]) Push(value V, priority P) (string, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Push(value, priority)
+}
+
+// IndexOf returns the current index of the element with the given ID.
+func (h *SyncIndexedDaryHeap[V, P]) IndexOf(id string) (int, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.IndexOf(id)
+}
+
+// Get returns the value and priority of the element with the given ID.
+func (h *SyncIndexedDaryHeap[V, P]) Get(id string) (V, P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Get(id)
+}
+
+// GetValue returns the value of the element with the given ID.
+func (h *SyncIndexedDaryHeap[V, P]) GetValue(id string) (V, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.GetValue(id)
+}
+
+// GetPriority returns the priority of the element with the given ID.
+func (h *SyncIndexedDaryHeap[V, P]) GetPriority(id string) (P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.GetPriority(id)
+}
+
+// UpdateByID replaces the value and priority of the element with the given
+// ID and restores the heap order.
+func (h *SyncIndexedDaryHeap[V, P]) UpdateByID(id string, value V, priority P) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.UpdateByID(id, value, priority)
+}
+
+// RemoveByID deletes the element with the given ID from the heap and returns
+// its value and priority.
+func (h *SyncIndexedDaryHeap[V, P]) RemoveByID(id string) (V, P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RemoveByID(id)
+}
+
+// Clear removes all elements from the heap.
+func (h *SyncIndexedDaryHeap[V, P]) Clear() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Clear()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (h *SyncIndexedDaryHeap[V, P]) ApproxMemoryUsage() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.ApproxMemoryUsage()
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (h *SyncIndexedDaryHeap[V, P]) Stats() HeapStats {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (h *SyncIndexedDaryHeap[V, P]) Instrument(hook Instrumentation) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (h *SyncIndexedDaryHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *SyncIndexedDaryHeap[V, P]) RemoveListener(id string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RemoveListener(id)
+}
+
+// Length returns the number of elements in the heap.
+func (h *SyncIndexedDaryHeap[V, P]) Length() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *SyncIndexedDaryHeap[V, P]) IsEmpty() bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (h *SyncIndexedDaryHeap[V, P]) Verify() error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Verify()
+}
+
+// Peek returns the value and priority of the root element without removing
+// it.
+func (h *SyncIndexedDaryHeap[V, P]) Peek() (V, P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Peek()
+}
+
+// PeekValue returns the value of the root element without removing it.
+func (h *SyncIndexedDaryHeap[V, P]) PeekValue() (V, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.PeekValue()
+}
+
+// PeekPriority returns the priority of the root element without removing it.
+func (h *SyncIndexedDaryHeap[V, P]) PeekPriority() (P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and priority of the root element.
+func (h *SyncIndexedDaryHeap[V, P]) Pop() (V, P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (h *SyncIndexedDaryHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopIf(pred)
+}
+
+// PopValue removes and returns the value of the root element.
+func (h *SyncIndexedDaryHeap[V, P]) PopValue() (V, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopValue()
+}
+
+// PopPriority removes and returns the priority of the root element.
+func (h *SyncIndexedDaryHeap[V, P]) PopPriority() (P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopPriority()
+}
+
+// Drain removes all elements from the heap and returns them in priority
+// order.
+func (h *SyncIndexedDaryHeap[V, P]) Drain() []HeapNode[V, P] {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order.
+func (h *SyncIndexedDaryHeap[V, P]) DrainValues() []V {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order.
+func (h *SyncIndexedDaryHeap[V, P]) DrainPriorities() []P {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap according to opts.
+func (h *SyncIndexedDaryHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Export(opts)
+}
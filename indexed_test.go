@@ -0,0 +1,154 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertIndexed checks that every tracked ID points at the element it was
+// assigned to.
+func assertIndexed(t *testing.T, heap *IndexedDaryHeap[int, int]) {
+	t.Helper()
+	require.Equal(t, heap.Length(), len(heap.index))
+	for id, i := range heap.index {
+		require.Equal(t, id, heap.heap.data[i].value.id)
+	}
+}
+
+func TestIndexedDaryHeap_PushPop(t *testing.T) {
+	heap := NewIndexedDaryHeap[int, int](3, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	for _, p := range []int{5, 2, 8, 1, 9, 3} {
+		_, err := heap.Push(p*10, p)
+		require.NoError(t, err)
+	}
+	assertIndexed(t, heap)
+
+	value, priority, err := heap.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 1, priority)
+	assertIndexed(t, heap)
+
+	assert.Equal(t, []int{2, 3, 5, 8, 9}, heap.DrainPriorities())
+	assert.Empty(t, heap.index)
+}
+
+func TestIndexedDaryHeap_UpdateByID(t *testing.T) {
+	heap := NewIndexedDaryHeap[string, int](2, lt, HeapConfig{})
+	idA, _ := heap.Push("a", 1)
+	idB, _ := heap.Push("b", 5)
+	heap.Push("c", 3)
+
+	assert.NoError(t, heap.UpdateByID(idB, "B", 0))
+	value, err := heap.PeekValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "B", value)
+
+	assert.NoError(t, heap.UpdateByID(idA, "a", 10))
+	priority, err := heap.GetPriority(idA)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, priority)
+	assert.Equal(t, []string{"B", "c", "a"}, heap.DrainValues())
+
+	assert.ErrorIs(t, heap.UpdateByID(idA, "a", 1), ErrNodeNotFound)
+}
+
+func TestIndexedDaryHeap_RemoveByID(t *testing.T) {
+	heap := NewIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	ids := make([]string, 0, 7)
+	for _, p := range []int{4, 7, 1, 9, 3, 6, 2} {
+		id, _ := heap.Push(p, p)
+		ids = append(ids, id)
+	}
+
+	value, priority, err := heap.RemoveByID(ids[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 7, value)
+	assert.Equal(t, 7, priority)
+	assertIndexed(t, heap)
+
+	_, _, err = heap.RemoveByID(ids[1])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	_, err = heap.GetValue(ids[1])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 6, 9}, heap.DrainPriorities())
+}
+
+func TestIndexedDaryHeap_Random(t *testing.T) {
+	heap := NewIndexedDaryHeap[int, int](4, lt, HeapConfig{UsePool: true})
+	r := rand.New(rand.NewSource(1))
+	live := make(map[string]int)
+	for range 2000 {
+		switch op := r.Intn(4); {
+		case op < 2 || len(live) == 0:
+			p := r.Intn(1000)
+			id, err := heap.Push(p, p)
+			require.NoError(t, err)
+			live[id] = p
+		case op == 2:
+			for id := range live {
+				p := r.Intn(1000)
+				require.NoError(t, heap.UpdateByID(id, p, p))
+				live[id] = p
+				break
+			}
+		default:
+			for id, want := range live {
+				_, p, err := heap.RemoveByID(id)
+				require.NoError(t, err)
+				require.Equal(t, want, p)
+				delete(live, id)
+				break
+			}
+		}
+		assertIndexed(t, heap)
+	}
+
+	prev := -1
+	for _, p := range heap.DrainPriorities() {
+		assert.LessOrEqual(t, prev, p)
+		prev = p
+	}
+}
+
+func TestIndexedDaryHeap_ClearAndExport(t *testing.T) {
+	heap := NewIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	id, _ := heap.Push(1, 3)
+	heap.Push(2, 1)
+
+	i, err := heap.IndexOf(id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.Len(t, heap.Export(ExportOptions[int, int]{}), 2)
+
+	heap.Clear()
+	assert.True(t, heap.IsEmpty())
+	_, err = heap.IndexOf(id)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+}
+
+func TestSyncIndexedDaryHeap_Concurrent(t *testing.T) {
+	heap := NewSyncIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				id, _ := heap.Push(i, w*100+i)
+				if i%2 == 0 {
+					heap.UpdateByID(id, i, -i)
+				} else {
+					heap.RemoveByID(id)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 200, heap.Length())
+}
@@ -0,0 +1,316 @@
+package heapcraft
+
+// BaseHeap is the set of operations shared by every heap in the package. It
+// covers inspection and removal of the root element but not insertion, since
+// the signature of Push differs between heap families.
+type BaseHeap[V any, P any] interface {
+	Pop() (V, P, error)
+	PopValue() (V, error)
+	PopPriority() (P, error)
+	Peek() (V, P, error)
+	PeekValue() (V, error)
+	PeekPriority() (P, error)
+	Drain() []HeapNode[V, P]
+	DrainValues() []V
+	DrainPriorities() []P
+	Export(opts ExportOptions[V, P]) []HeapNode[V, P]
+	Length() int
+	IsEmpty() bool
+	Clear()
+}
+
+// Heap is implemented by heaps whose Push cannot fail and does not track
+// elements, such as DaryHeap, PairingHeap, LeftistHeap and SkewHeap. It allows
+// one heap implementation to be swapped for another behind a single field.
+type Heap[V any, P any] interface {
+	BaseHeap[V, P]
+	Push(value V, priority P)
+}
+
+// TrackedHeap is implemented by heaps that assign an ID to every pushed
+// element and support lookups and updates by that ID, such as
+// FullPairingHeap, FullLeftistHeap and FullSkewHeap.
+type TrackedHeap[V any, P any] interface {
+	BaseHeap[V, P]
+	Push(value V, priority P) (string, error)
+	Get(id string) (V, P, error)
+	GetValue(id string) (V, error)
+	GetPriority(id string) (P, error)
+	UpdateValue(id string, value V) error
+	UpdatePriority(id string, priority P) error
+	Remove(id string) (V, P, error)
+	OnValueUpdate(fn func(event ValueUpdateEvent[V])) string
+	RemoveListener(id string) error
+}
+
+// DoubleEndedHeap is a double-ended priority queue that gives access to both
+// the element with the highest priority and the element with the lowest
+// priority according to its comparison function, such as MinMaxHeap.
+type DoubleEndedHeap[V any, P any] interface {
+	Push(value V, priority P)
+	PopMin() (V, P, error)
+	PopMax() (V, P, error)
+	PeekMin() (V, P, error)
+	PeekMax() (V, P, error)
+	Length() int
+	IsEmpty() bool
+	Clear()
+}
+
+// Verifier is implemented by heaps that can check their own internal
+// consistency, which every mutable heap in the package does. Verify is meant
+// for tests and debugging, not for production hot paths.
+type Verifier interface {
+	Verify() error
+}
+
+// PoolReporter is implemented by heaps that allocate their nodes through a
+// pool and can report how well it is being reused.
+type PoolReporter interface {
+	PoolStats() PoolStats
+}
+
+// MemoryReporter is implemented by heaps that can estimate the memory they
+// hold, for capacity planning. Estimates cover the heap's own arrays, nodes,
+// maps and pooled nodes, but not memory that values and priorities point to.
+type MemoryReporter interface {
+	ApproxMemoryUsage() int
+}
+
+// StatsReporter is implemented by heaps that count their operations and can
+// report each change in length to an Instrumentation hook.
+type StatsReporter interface {
+	Stats() HeapStats
+	Instrument(hook Instrumentation)
+}
+
+// EventSource is implemented by heaps that report elements being pushed,
+// popped, removed or updated, and the heap being cleared, to registered
+// listeners.
+type EventSource[V any, P any] interface {
+	OnEvent(fn func(HeapEvent[V, P])) string
+	RemoveListener(id string) error
+}
+
+// IDPusher is implemented by tracked heaps that accept caller-chosen IDs, such
+// as the primary keys of rows loaded with LoadRows.
+type IDPusher[V any, P any] interface {
+	PushWithID(id string, value V, priority P) error
+}
+
+// Compile-time assertions that every heap in the package satisfies the
+// interfaces it is documented to implement.
+var (
+	_ Heap[int, int] = (*DaryHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncDaryHeap[int, int])(nil)
+	_ Heap[int, int] = (*PairingHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncPairingHeap[int, int])(nil)
+	_ Heap[int, int] = (*LeftistHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncLeftistHeap[int, int])(nil)
+	_ Heap[int, int] = (*SkewHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncSkewHeap[int, int])(nil)
+	_ Heap[int, int] = (*BinomialHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncBinomialHeap[int, int])(nil)
+	_ Heap[int, int] = (*SkewBinomialHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncSkewBinomialHeap[int, int])(nil)
+	_ Heap[int, int] = (*AdaptiveHeap[int, int])(nil)
+	_ Heap[int, int] = (*BlockingHeap[int, int])(nil)
+	_ Heap[int, int] = (*BoundedHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncBoundedHeap[int, int])(nil)
+	_ Heap[int, int] = (*MPSCHeap[int, int])(nil)
+	_ Heap[int, int] = (*ShardedSyncHeap[int, int])(nil)
+	_ Heap[int, int] = (*SoftHeap[int, int])(nil)
+	_ Heap[int, int] = (*SyncHeap[int, int])(nil)
+	_ Heap[int, int] = (*DerivedHeap[int, int])(nil)
+
+	_ DoubleEndedHeap[int, int] = (*MinMaxHeap[int, int])(nil)
+	_ DoubleEndedHeap[int, int] = (*SyncMinMaxHeap[int, int])(nil)
+
+	_ TrackedHeap[int, int] = (*FullPairingHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*SyncFullPairingHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*FullLeftistHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*SyncFullLeftistHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*FullSkewHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*SyncFullSkewHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*LazyHeap[int, int])(nil)
+	_ TrackedHeap[int, int] = (*SyncLazyHeap[int, int])(nil)
+
+	_ BaseHeap[int, uint] = (*RadixHeap[int, uint])(nil)
+	_ BaseHeap[int, uint] = (*SyncRadixHeap[int, uint])(nil)
+	_ BaseHeap[int, uint] = (*MultiLevelRadixHeap[int, uint])(nil)
+	_ BaseHeap[int, uint] = (*SyncMultiLevelRadixHeap[int, uint])(nil)
+	_ BaseHeap[int, int]  = (*ExpiringHeap[int, int])(nil)
+	_ BaseHeap[int, int]  = (*SyncExpiringHeap[int, int])(nil)
+	_ Heap[int, int]      = (*AgingHeap[int, int])(nil)
+	_ Heap[int, int]      = (*SyncAgingHeap[int, int])(nil)
+	_ BaseHeap[int, int]  = (*IndexedDaryHeap[int, int])(nil)
+	_ BaseHeap[int, int]  = (*SyncIndexedDaryHeap[int, int])(nil)
+	_ BaseHeap[int, int]  = (*KeyedHeap[string, int, int])(nil)
+	_ BaseHeap[int, int]  = (*SyncKeyedHeap[string, int, int])(nil)
+
+	_ Maintainer = (*DaryHeap[int, int])(nil)
+	_ Maintainer = (*SyncDaryHeap[int, int])(nil)
+	_ Maintainer = (*RadixHeap[int, uint])(nil)
+	_ Maintainer = (*SyncRadixHeap[int, uint])(nil)
+	_ Maintainer = (*ExpiringHeap[int, int])(nil)
+	_ Maintainer = (*SyncExpiringHeap[int, int])(nil)
+	_ Maintainer = (*LazyHeap[int, int])(nil)
+	_ Maintainer = (*SyncLazyHeap[int, int])(nil)
+	_ Maintainer = (*AgingHeap[int, int])(nil)
+	_ Maintainer = (*SyncAgingHeap[int, int])(nil)
+
+	_ Verifier = (*DaryHeap[int, int])(nil)
+	_ Verifier = (*SyncDaryHeap[int, int])(nil)
+	_ Verifier = (*RadixHeap[int, uint])(nil)
+	_ Verifier = (*SyncRadixHeap[int, uint])(nil)
+	_ Verifier = (*ShardedSyncHeap[int, int])(nil)
+	_ Verifier = (*MultiLevelRadixHeap[int, uint])(nil)
+	_ Verifier = (*SyncMultiLevelRadixHeap[int, uint])(nil)
+	_ Verifier = (*PairingHeap[int, int])(nil)
+	_ Verifier = (*SyncPairingHeap[int, int])(nil)
+	_ Verifier = (*FullPairingHeap[int, int])(nil)
+	_ Verifier = (*SyncFullPairingHeap[int, int])(nil)
+	_ Verifier = (*LeftistHeap[int, int])(nil)
+	_ Verifier = (*SyncLeftistHeap[int, int])(nil)
+	_ Verifier = (*FullLeftistHeap[int, int])(nil)
+	_ Verifier = (*SyncFullLeftistHeap[int, int])(nil)
+	_ Verifier = (*SkewHeap[int, int])(nil)
+	_ Verifier = (*SyncSkewHeap[int, int])(nil)
+	_ Verifier = (*FullSkewHeap[int, int])(nil)
+	_ Verifier = (*SyncFullSkewHeap[int, int])(nil)
+	_ Verifier = (*BinomialHeap[int, int])(nil)
+	_ Verifier = (*SyncBinomialHeap[int, int])(nil)
+	_ Verifier = (*SkewBinomialHeap[int, int])(nil)
+	_ Verifier = (*SyncSkewBinomialHeap[int, int])(nil)
+	_ Verifier = (*AdaptiveHeap[int, int])(nil)
+	_ Verifier = (*BlockingHeap[int, int])(nil)
+	_ Verifier = (*BoundedHeap[int, int])(nil)
+	_ Verifier = (*SyncBoundedHeap[int, int])(nil)
+	_ Verifier = (*ExpiringHeap[int, int])(nil)
+	_ Verifier = (*SyncExpiringHeap[int, int])(nil)
+	_ Verifier = (*AgingHeap[int, int])(nil)
+	_ Verifier = (*SyncAgingHeap[int, int])(nil)
+	_ Verifier = (*IndexedDaryHeap[int, int])(nil)
+	_ Verifier = (*SyncIndexedDaryHeap[int, int])(nil)
+	_ Verifier = (*KeyedHeap[string, int, int])(nil)
+	_ Verifier = (*SyncKeyedHeap[string, int, int])(nil)
+	_ Verifier = (*LazyHeap[int, int])(nil)
+	_ Verifier = (*SyncLazyHeap[int, int])(nil)
+	_ Verifier = (*SoftHeap[int, int])(nil)
+	_ Verifier = (*IntervalHeap[int, int])(nil)
+	_ Verifier = (*MinMaxHeap[int, int])(nil)
+	_ Verifier = (*SyncMinMaxHeap[int, int])(nil)
+
+	_ PoolReporter = (*BinomialHeap[int, int])(nil)
+	_ PoolReporter = (*SyncBinomialHeap[int, int])(nil)
+	_ PoolReporter = (*SkewBinomialHeap[int, int])(nil)
+	_ PoolReporter = (*SyncSkewBinomialHeap[int, int])(nil)
+	_ PoolReporter = (*RadixHeap[int, uint])(nil)
+	_ PoolReporter = (*SyncRadixHeap[int, uint])(nil)
+	_ PoolReporter = (*MultiLevelRadixHeap[int, uint])(nil)
+	_ PoolReporter = (*SyncMultiLevelRadixHeap[int, uint])(nil)
+	_ PoolReporter = (*PairingHeap[int, int])(nil)
+	_ PoolReporter = (*SyncPairingHeap[int, int])(nil)
+	_ PoolReporter = (*FullPairingHeap[int, int])(nil)
+	_ PoolReporter = (*SyncFullPairingHeap[int, int])(nil)
+	_ PoolReporter = (*LeftistHeap[int, int])(nil)
+	_ PoolReporter = (*SyncLeftistHeap[int, int])(nil)
+	_ PoolReporter = (*FullLeftistHeap[int, int])(nil)
+	_ PoolReporter = (*SyncFullLeftistHeap[int, int])(nil)
+	_ PoolReporter = (*SkewHeap[int, int])(nil)
+	_ PoolReporter = (*SyncSkewHeap[int, int])(nil)
+	_ PoolReporter = (*FullSkewHeap[int, int])(nil)
+	_ PoolReporter = (*SyncFullSkewHeap[int, int])(nil)
+	_ PoolReporter = (*IntervalHeap[int, int])(nil)
+
+	_ MemoryReporter = (*BinomialHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncBinomialHeap[int, int])(nil)
+	_ MemoryReporter = (*SkewBinomialHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncSkewBinomialHeap[int, int])(nil)
+	_ MemoryReporter = (*DaryHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncDaryHeap[int, int])(nil)
+	_ MemoryReporter = (*IndexedDaryHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncIndexedDaryHeap[int, int])(nil)
+	_ MemoryReporter = (*KeyedHeap[string, int, int])(nil)
+	_ MemoryReporter = (*SyncKeyedHeap[string, int, int])(nil)
+	_ MemoryReporter = (*RadixHeap[int, uint])(nil)
+	_ MemoryReporter = (*SyncRadixHeap[int, uint])(nil)
+	_ MemoryReporter = (*MultiLevelRadixHeap[int, uint])(nil)
+	_ MemoryReporter = (*SyncMultiLevelRadixHeap[int, uint])(nil)
+	_ MemoryReporter = (*PairingHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncPairingHeap[int, int])(nil)
+	_ MemoryReporter = (*FullPairingHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncFullPairingHeap[int, int])(nil)
+	_ MemoryReporter = (*LeftistHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncLeftistHeap[int, int])(nil)
+	_ MemoryReporter = (*FullLeftistHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncFullLeftistHeap[int, int])(nil)
+	_ MemoryReporter = (*SkewHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncSkewHeap[int, int])(nil)
+	_ MemoryReporter = (*FullSkewHeap[int, int])(nil)
+	_ MemoryReporter = (*SyncFullSkewHeap[int, int])(nil)
+	_ MemoryReporter = (*IntervalHeap[int, int])(nil)
+
+	_ StatsReporter = (*AdaptiveHeap[int, int])(nil)
+	_ StatsReporter = (*BinomialHeap[int, int])(nil)
+	_ StatsReporter = (*SyncBinomialHeap[int, int])(nil)
+	_ StatsReporter = (*SkewBinomialHeap[int, int])(nil)
+	_ StatsReporter = (*SyncSkewBinomialHeap[int, int])(nil)
+	_ StatsReporter = (*DaryHeap[int, int])(nil)
+	_ StatsReporter = (*SyncDaryHeap[int, int])(nil)
+	_ StatsReporter = (*IndexedDaryHeap[int, int])(nil)
+	_ StatsReporter = (*SyncIndexedDaryHeap[int, int])(nil)
+	_ StatsReporter = (*KeyedHeap[string, int, int])(nil)
+	_ StatsReporter = (*SyncKeyedHeap[string, int, int])(nil)
+	_ StatsReporter = (*PairingHeap[int, int])(nil)
+	_ StatsReporter = (*SyncPairingHeap[int, int])(nil)
+	_ StatsReporter = (*FullPairingHeap[int, int])(nil)
+	_ StatsReporter = (*SyncFullPairingHeap[int, int])(nil)
+	_ StatsReporter = (*LeftistHeap[int, int])(nil)
+	_ StatsReporter = (*SyncLeftistHeap[int, int])(nil)
+	_ StatsReporter = (*FullLeftistHeap[int, int])(nil)
+	_ StatsReporter = (*SyncFullLeftistHeap[int, int])(nil)
+	_ StatsReporter = (*SkewHeap[int, int])(nil)
+	_ StatsReporter = (*SyncSkewHeap[int, int])(nil)
+	_ StatsReporter = (*FullSkewHeap[int, int])(nil)
+	_ StatsReporter = (*SyncFullSkewHeap[int, int])(nil)
+	_ StatsReporter = (*RadixHeap[int, uint])(nil)
+	_ StatsReporter = (*SyncRadixHeap[int, uint])(nil)
+	_ StatsReporter = (*MultiLevelRadixHeap[int, uint])(nil)
+	_ StatsReporter = (*SyncMultiLevelRadixHeap[int, uint])(nil)
+	_ StatsReporter = (*SoftHeap[int, int])(nil)
+	_ StatsReporter = (*IntervalHeap[int, int])(nil)
+
+	_ EventSource[int, int]  = (*AdaptiveHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*BinomialHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncBinomialHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SkewBinomialHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncSkewBinomialHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*DaryHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncDaryHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*IndexedDaryHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncIndexedDaryHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*KeyedHeap[string, int, int])(nil)
+	_ EventSource[int, int]  = (*SyncKeyedHeap[string, int, int])(nil)
+	_ EventSource[int, int]  = (*PairingHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncPairingHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*FullPairingHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncFullPairingHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*LeftistHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncLeftistHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*FullLeftistHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncFullLeftistHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SkewHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncSkewHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*FullSkewHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*SyncFullSkewHeap[int, int])(nil)
+	_ EventSource[int, uint] = (*RadixHeap[int, uint])(nil)
+	_ EventSource[int, uint] = (*SyncRadixHeap[int, uint])(nil)
+	_ EventSource[int, uint] = (*MultiLevelRadixHeap[int, uint])(nil)
+	_ EventSource[int, uint] = (*SyncMultiLevelRadixHeap[int, uint])(nil)
+	_ EventSource[int, int]  = (*SoftHeap[int, int])(nil)
+	_ EventSource[int, int]  = (*IntervalHeap[int, int])(nil)
+
+	_ IDPusher[int, int] = (*FullPairingHeap[int, int])(nil)
+	_ IDPusher[int, int] = (*SyncFullPairingHeap[int, int])(nil)
+)
@@ -0,0 +1,464 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeapInterface_Interchangeable(t *testing.T) {
+	heaps := map[string]Heap[int, int]{
+		"dary":     NewDaryHeap[int, int](3, nil, lt, false),
+		"pairing":  NewPairingHeap[int, int](nil, lt, false),
+		"leftist":  NewLeftistHeap[int, int](nil, lt, false),
+		"skew":     NewSkewHeap[int, int](nil, lt, false),
+		"binomial": NewBinomialHeap[int, int](nil, lt, HeapConfig{}),
+		"adaptive": NewAdaptiveHeap[int, int](nil, lt, HeapConfig{}),
+	}
+
+	for name, heap := range heaps {
+		for _, v := range []int{5, 1, 4, 2, 3} {
+			heap.Push(v, v)
+		}
+		result := make([]int, 0, 5)
+		for !heap.IsEmpty() {
+			v, _ := heap.PopValue()
+			result = append(result, v)
+		}
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, result, name)
+	}
+}
+
+func TestTrackedHeapInterface_Interchangeable(t *testing.T) {
+	config := HeapConfig{UsePool: false}
+	heaps := map[string]TrackedHeap[int, int]{
+		"pairing": NewFullPairingHeap[int, int](nil, lt, config),
+		"leftist": NewFullLeftistHeap[int, int](nil, lt, config),
+		"skew":    NewFullSkewHeap[int, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		id, err := heap.Push(10, 10)
+		assert.Nil(t, err, name)
+		heap.Push(5, 5)
+		assert.Nil(t, heap.UpdatePriority(id, 1), name)
+		value, _ := heap.PeekValue()
+		assert.Equal(t, 10, value, name)
+	}
+}
+
+// priorityAdjuster is implemented by heaps that update a priority through a
+// function of the current one.
+type priorityAdjuster interface {
+	TrackedHeap[int, int]
+	UpdatePriorityFunc(id string, fn func(old int) int) error
+}
+
+func TestUpdatePriorityFunc(t *testing.T) {
+	config := HeapConfig{UsePool: false}
+	heaps := map[string]priorityAdjuster{
+		"pairing":     NewFullPairingHeap[int, int](nil, lt, config),
+		"leftist":     NewFullLeftistHeap[int, int](nil, lt, config),
+		"skew":        NewFullSkewHeap[int, int](nil, lt, config),
+		"lazy":        NewLazyHeap[int, int](nil, lt, config),
+		"syncPairing": NewSyncFullPairingHeap[int, int](nil, lt, config),
+		"syncLeftist": NewSyncFullLeftistHeap[int, int](nil, lt, config),
+		"syncSkew":    NewSyncFullSkewHeap[int, int](nil, lt, config),
+		"syncLazy":    NewSyncLazyHeap[int, int](nil, lt, config),
+	}
+	for name, heap := range heaps {
+		id, _ := heap.Push(10, 10)
+		heap.Push(5, 5)
+		require.NoError(t, heap.UpdatePriorityFunc(id, func(old int) int { return old - 8 }), name)
+		value, priority, err := heap.Peek()
+		require.NoError(t, err, name)
+		assert.Equal(t, 10, value, name)
+		assert.Equal(t, 2, priority, name)
+
+		called := false
+		err = heap.UpdatePriorityFunc("missing", func(old int) int { called = true; return old })
+		assert.ErrorIs(t, err, ErrNodeNotFound, name)
+		assert.False(t, called, name)
+	}
+
+	keyed := NewSyncKeyedHeap[string, string, int](2, lt, DaryHeapConfig{})
+	keyed.Push("a", "a", 3)
+	keyed.Push("b", "b", 1)
+	require.NoError(t, keyed.UpdatePriorityFunc("a", func(old int) int { return old - 3 }))
+	value, priority, err := keyed.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "a", value)
+	assert.Equal(t, 0, priority)
+	assert.ErrorIs(t, keyed.UpdatePriorityFunc("c", func(old int) int { return old }), ErrKeyNotFound)
+}
+
+func TestUpdatePriorityFunc_SyncIsAtomic(t *testing.T) {
+	heap := NewSyncFullPairingHeap[int, int](nil, lt, HeapConfig{})
+	id, _ := heap.Push(0, 0)
+
+	// A Get followed by UpdatePriority would lose increments made by other
+	// goroutines in between.
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				heap.UpdatePriorityFunc(id, func(old int) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+	priority, err := heap.GetPriority(id)
+	require.NoError(t, err)
+	assert.Equal(t, 800, priority)
+}
+
+func TestBaseHeapInterface_DrainExport(t *testing.T) {
+	data := []HeapNode[int, uint]{
+		CreateHeapNode(3, uint(3)),
+		CreateHeapNode(1, uint(1)),
+		CreateHeapNode(2, uint(2)),
+	}
+	config := HeapConfig{UsePool: true}
+	heaps := map[string]func() BaseHeap[int, uint]{
+		"dary":        func() BaseHeap[int, uint] { return NewDaryHeapCopy(2, data, ltu, true) },
+		"syncDary":    func() BaseHeap[int, uint] { return NewSyncDaryHeapCopy(2, data, ltu, true) },
+		"pairing":     func() BaseHeap[int, uint] { return NewPairingHeap(data, ltu, true) },
+		"syncPairing": func() BaseHeap[int, uint] { return NewSyncPairingHeap(data, ltu, true) },
+		"fullPairing": func() BaseHeap[int, uint] { return NewFullPairingHeap(data, ltu, config) },
+		"leftist":     func() BaseHeap[int, uint] { return NewLeftistHeap(data, ltu, true) },
+		"fullLeftist": func() BaseHeap[int, uint] { return NewSyncFullLeftistHeap(data, ltu, config) },
+		"skew":        func() BaseHeap[int, uint] { return NewSyncSkewHeap(data, ltu, true) },
+		"fullSkew":    func() BaseHeap[int, uint] { return NewFullSkewHeap(data, ltu, config) },
+		"binomial":    func() BaseHeap[int, uint] { return NewSyncBinomialHeap(data, ltu, HeapConfig{UsePool: true}) },
+		"adaptive":    func() BaseHeap[int, uint] { return NewAdaptiveHeap(data, ltu, HeapConfig{UsePool: true}) },
+		"radix":       func() BaseHeap[int, uint] { return NewRadixHeap(data, true) },
+		"syncRadix":   func() BaseHeap[int, uint] { return NewSyncRadixHeap(data, true) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		assert.Equal(t, []HeapNode[int, uint]{
+			CreateHeapNode(1, uint(1)),
+			CreateHeapNode(2, uint(2)),
+			CreateHeapNode(3, uint(3)),
+		}, heap.Drain(), name)
+		assert.True(t, heap.IsEmpty(), name)
+		assert.Empty(t, heap.Drain(), name)
+
+		exported := constructor().Export(ExportOptions[int, uint]{Order: WorstFirst, Limit: 2})
+		assert.Equal(t, []HeapNode[int, uint]{
+			CreateHeapNode(3, uint(3)),
+			CreateHeapNode(2, uint(2)),
+		}, exported, name)
+
+		assert.Equal(t, []int{1, 2, 3}, constructor().DrainValues(), name)
+		assert.Equal(t, []uint{1, 2, 3}, constructor().DrainPriorities(), name)
+	}
+}
+
+// ltu returns true if a is less than b
+func ltu(a, b uint) bool { return a < b }
+
+func TestTrackedHeapInterface_RemoveRandom(t *testing.T) {
+	config := HeapConfig{UsePool: true}
+	heaps := map[string]TrackedHeap[int, int]{
+		"pairing":     NewFullPairingHeap[int, int](nil, lt, config),
+		"syncPairing": NewSyncFullPairingHeap[int, int](nil, lt, config),
+		"leftist":     NewFullLeftistHeap[int, int](nil, lt, config),
+		"syncLeftist": NewSyncFullLeftistHeap[int, int](nil, lt, config),
+		"skew":        NewFullSkewHeap[int, int](nil, lt, config),
+		"syncSkew":    NewSyncFullSkewHeap[int, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		rng := rand.New(rand.NewSource(42))
+		live := make(map[string]int)
+		for i := 0; i < 200; i++ {
+			v := rng.Intn(100)
+			id, _ := heap.Push(v, v)
+			live[id] = v
+		}
+
+		for id, v := range live {
+			if rng.Intn(2) == 0 {
+				continue
+			}
+			if rng.Intn(2) == 0 {
+				v = rng.Intn(100)
+				assert.Nil(t, heap.UpdatePriority(id, v), name)
+				assert.Nil(t, heap.UpdateValue(id, v), name)
+				live[id] = v
+				continue
+			}
+			value, _, err := heap.Remove(id)
+			assert.Nil(t, err, name)
+			assert.Equal(t, v, value, name)
+			delete(live, id)
+		}
+
+		expected := make([]int, 0, len(live))
+		for _, v := range live {
+			expected = append(expected, v)
+		}
+		sort.Ints(expected)
+		assert.Equal(t, expected, heap.DrainPriorities(), name)
+	}
+}
+
+func TestHeap_PushAll(t *testing.T) {
+	heaps := map[string]interface {
+		Heap[int, int]
+		PushAll(data []HeapNode[int, int])
+	}{
+		"pairing":     NewPairingHeap[int, int](nil, lt, false),
+		"syncPairing": NewSyncPairingHeap[int, int](nil, lt, true),
+		"leftist":     NewLeftistHeap[int, int](nil, lt, false),
+		"syncLeftist": NewSyncLeftistHeap[int, int](nil, lt, true),
+		"skew":        NewSkewHeap[int, int](nil, lt, false),
+		"syncSkew":    NewSyncSkewHeap[int, int](nil, lt, true),
+	}
+
+	for name, heap := range heaps {
+		heap.Push(50, 50)
+		data := make([]HeapNode[int, int], 0, 100)
+		for _, p := range rand.Perm(100) {
+			data = append(data, CreateHeapNode(p, p))
+		}
+		heap.PushAll(data)
+		heap.PushAll(nil)
+
+		assert.Equal(t, 101, heap.Length(), name)
+		assert.IsNonDecreasing(t, heap.DrainValues(), name)
+	}
+}
+
+func TestTrackedHeap_PushAll(t *testing.T) {
+	heaps := map[string]interface {
+		TrackedHeap[int, int]
+		PushAll(data []HeapNode[int, int]) ([]string, error)
+	}{
+		"pairing":     NewFullPairingHeap[int, int](nil, lt, HeapConfig{}),
+		"syncPairing": NewSyncFullPairingHeap[int, int](nil, lt, HeapConfig{}),
+		"leftist":     NewFullLeftistHeap[int, int](nil, lt, HeapConfig{}),
+		"syncLeftist": NewSyncFullLeftistHeap[int, int](nil, lt, HeapConfig{}),
+		"skew":        NewFullSkewHeap[int, int](nil, lt, HeapConfig{}),
+		"syncSkew":    NewSyncFullSkewHeap[int, int](nil, lt, HeapConfig{}),
+	}
+
+	for name, heap := range heaps {
+		heap.Push(50, 50)
+		data := make([]HeapNode[int, int], 0, 100)
+		for _, p := range rand.Perm(100) {
+			data = append(data, CreateHeapNode(p, p))
+		}
+		ids, err := heap.PushAll(data)
+		assert.NoError(t, err, name)
+		assert.Len(t, ids, 100, name)
+
+		for i, id := range ids {
+			value, err := heap.GetValue(id)
+			assert.NoError(t, err, name)
+			assert.Equal(t, data[i].Value(), value, name)
+		}
+		assert.NoError(t, heap.UpdatePriority(ids[0], -1), name)
+		assert.Equal(t, 101, heap.Length(), name)
+		assert.Equal(t, data[0].Value(), heap.DrainValues()[0], name)
+	}
+
+	heap := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{NextID: 0}})
+	heap.Push(1, 1)
+	heap.idGen = &IntegerIDGenerator{NextID: 0}
+	_, err := heap.PushAll([]HeapNode[int, int]{CreateHeapNode(2, 2)})
+	assert.ErrorIs(t, err, ErrIDGenerationFailed)
+	assert.Equal(t, 1, heap.Length())
+}
+
+// conditionalHeap is the subset of heap methods exercised by the PopIf tests.
+type conditionalHeap interface {
+	PopIf(pred func(int, uint) bool) (int, uint, bool, error)
+	Length() int
+}
+
+func TestPopIf_AllHeaps(t *testing.T) {
+	data := []HeapNode[int, uint]{
+		CreateHeapNode(3, uint(3)),
+		CreateHeapNode(1, uint(1)),
+		CreateHeapNode(2, uint(2)),
+	}
+	config := HeapConfig{UsePool: true}
+	fill := func(push func(v int, p uint)) {
+		for _, node := range data {
+			push(node.value, node.priority)
+		}
+	}
+	heaps := map[string]func() conditionalHeap{
+		"dary":            func() conditionalHeap { return NewDaryHeapCopy(2, data, ltu, true) },
+		"syncDary":        func() conditionalHeap { return NewSyncDaryHeapCopy(2, data, ltu, true) },
+		"pairing":         func() conditionalHeap { return NewPairingHeap(data, ltu, true) },
+		"syncPairing":     func() conditionalHeap { return NewSyncPairingHeap(data, ltu, true) },
+		"fullPairing":     func() conditionalHeap { return NewFullPairingHeap(data, ltu, config) },
+		"syncFullPairing": func() conditionalHeap { return NewSyncFullPairingHeap(data, ltu, config) },
+		"leftist":         func() conditionalHeap { return NewLeftistHeap(data, ltu, true) },
+		"syncLeftist":     func() conditionalHeap { return NewSyncLeftistHeap(data, ltu, true) },
+		"fullLeftist":     func() conditionalHeap { return NewFullLeftistHeap(data, ltu, config) },
+		"syncFullLeftist": func() conditionalHeap { return NewSyncFullLeftistHeap(data, ltu, config) },
+		"skew":            func() conditionalHeap { return NewSkewHeap(data, ltu, true) },
+		"syncSkew":        func() conditionalHeap { return NewSyncSkewHeap(data, ltu, true) },
+		"fullSkew":        func() conditionalHeap { return NewFullSkewHeap(data, ltu, config) },
+		"syncFullSkew":    func() conditionalHeap { return NewSyncFullSkewHeap(data, ltu, config) },
+		"binomial":        func() conditionalHeap { return NewBinomialHeap(data, ltu, HeapConfig{UsePool: true}) },
+		"syncBinomial":    func() conditionalHeap { return NewSyncBinomialHeap(data, ltu, HeapConfig{UsePool: true}) },
+		"adaptive":        func() conditionalHeap { return NewAdaptiveHeap(data, ltu, HeapConfig{UsePool: true}) },
+		"radix":           func() conditionalHeap { return NewRadixHeap(data, true) },
+		"syncRadix":       func() conditionalHeap { return NewSyncRadixHeap(data, true) },
+		"multiRadix":      func() conditionalHeap { return NewMultiLevelRadixHeap(data, 4, RadixHeapConfig{UsePool: true}) },
+		"syncMultiRadix":  func() conditionalHeap { return NewSyncMultiLevelRadixHeap(data, 4, RadixHeapConfig{UsePool: true}) },
+		"blocking": func() conditionalHeap {
+			return NewBlockingHeap[int, uint](NewSyncDaryHeapCopy(2, data, ltu, true))
+		},
+		"bounded": func() conditionalHeap {
+			h := NewSyncBoundedHeap[int, uint](10, ShedDropWorst, ltu, DaryHeapConfig{})
+			fill(h.Push)
+			return h
+		},
+		"indexed": func() conditionalHeap {
+			h := NewSyncIndexedDaryHeap[int, uint](2, ltu, config)
+			fill(func(v int, p uint) { h.Push(v, p) })
+			return h
+		},
+		"keyed": func() conditionalHeap {
+			h := NewSyncKeyedHeap[int, int, uint](2, ltu, DaryHeapConfig{})
+			fill(func(v int, p uint) { h.Push(v, v, p) })
+			return h
+		},
+		"expiring": func() conditionalHeap {
+			h := NewSyncExpiringHeap[int, uint](ltu, time.Now, DaryHeapConfig{})
+			fill(func(v int, p uint) { h.Push(v, p, time.Hour) })
+			return h
+		},
+		"mpsc": func() conditionalHeap {
+			h := NewMPSCHeap[int](ltu)
+			fill(h.Push)
+			return h
+		},
+		"sharded": func() conditionalHeap {
+			h := NewShardedSyncHeap[int, uint](2, ltu, DaryHeapConfig{})
+			fill(h.Push)
+			return h
+		},
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		v, p, ok, err := heap.PopIf(func(_ int, p uint) bool { return p > 1 })
+		assert.NoError(t, err, name)
+		assert.False(t, ok, name)
+		assert.Equal(t, 1, v, name)
+		assert.Equal(t, uint(1), p, name)
+		assert.Equal(t, 3, heap.Length(), name)
+
+		for want := 1; want <= 3; want++ {
+			v, _, ok, err = heap.PopIf(func(int, uint) bool { return true })
+			assert.NoError(t, err, name)
+			assert.True(t, ok, name)
+			assert.Equal(t, want, v, name)
+		}
+		_, _, ok, err = heap.PopIf(func(int, uint) bool { return true })
+		assert.ErrorIs(t, err, ErrHeapEmpty, name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestPopIf_SyncIsAtomic(t *testing.T) {
+	heap := NewSyncDaryHeap[int, int](2, nil, lt, false)
+	for i := 0; i < 1000; i++ {
+		heap.Push(i, i)
+	}
+
+	// Every goroutine claims elements below 500 until none are left. Without
+	// a single lock around the check and the removal, a check could pass for
+	// one root while the pop removes the next, claiming elements of 500 or more.
+	var wg sync.WaitGroup
+	claimed := make([][]int, 8)
+	for w := range claimed {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for {
+				v, _, ok, _ := heap.PopIf(func(_ int, p int) bool { return p < 500 })
+				if !ok {
+					return
+				}
+				claimed[w] = append(claimed[w], v)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var all []int
+	for _, values := range claimed {
+		all = append(all, values...)
+	}
+	sort.Ints(all)
+	assert.Len(t, all, 500)
+	for i, v := range all {
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, 500, heap.Length())
+}
+
+// replacer is implemented by heaps with combined push and pop operations.
+type replacer interface {
+	Heap[int, int]
+	PushPop(value int, priority int) (int, int)
+	PopPush(value int, priority int) (int, int)
+}
+
+func TestPushPopPopPush_MatchDary(t *testing.T) {
+	heaps := map[string]func() replacer{
+		"pairing":     func() replacer { return NewPairingHeap[int, int](nil, lt, false) },
+		"syncPairing": func() replacer { return NewSyncPairingHeap[int, int](nil, lt, true) },
+		"leftist":     func() replacer { return NewLeftistHeap[int, int](nil, lt, false) },
+		"syncLeftist": func() replacer { return NewSyncLeftistHeap[int, int](nil, lt, true) },
+		"skew":        func() replacer { return NewSkewHeap[int, int](nil, lt, false) },
+		"syncSkew":    func() replacer { return NewSyncSkewHeap[int, int](nil, lt, true) },
+	}
+
+	for name, constructor := range heaps {
+		rng := rand.New(rand.NewSource(5))
+		heap := constructor()
+		reference := NewBinaryHeap[int, int](nil, lt, false)
+
+		v, p := heap.PopPush(1, 1)
+		assert.Equal(t, 1, v, name)
+		assert.Equal(t, 1, p, name)
+		assert.True(t, heap.IsEmpty(), name)
+
+		for i := 0; i < 1000; i++ {
+			priority := rng.Intn(500)
+			switch rng.Intn(3) {
+			case 0:
+				heap.Push(priority, priority)
+				reference.Push(priority, priority)
+			case 1:
+				_, got := heap.PushPop(priority, priority)
+				_, want := reference.PushPop(priority, priority)
+				require.Equal(t, want, got, name)
+			case 2:
+				_, got := heap.PopPush(priority, priority)
+				_, want := reference.PopPush(priority, priority)
+				require.Equal(t, want, got, name)
+			}
+			require.Equal(t, reference.Length(), heap.Length(), name)
+		}
+		assert.NoError(t, heap.(Verifier).Verify(), name)
+		assert.Equal(t, reference.DrainPriorities(), heap.DrainPriorities(), name)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build tinygo || wasm
+
+// Command wasmcheck is a build check for TinyGo and WebAssembly targets. It
+// constructs every heap in the package with pooling enabled, so a successful
+// build shows that none of them depend on features those targets lack. It is
+// excluded from regular builds by its build constraint and is not run in CI.
+//
+//	tinygo build -target=wasi -o /dev/null ./internal/wasmcheck
+//	GOOS=wasip1 GOARCH=wasm go build -o /dev/null ./internal/wasmcheck
+package main
+
+import (
+	"fmt"
+
+	"github.com/galactixx/heapcraft"
+)
+
+func lt(a, b int) bool { return a < b }
+
+func main() {
+	config := heapcraft.HeapConfig{UsePool: true}
+	heaps := map[string]heapcraft.BaseHeap[int, int]{
+		"dary":            heapcraft.NewDaryHeapWithConfig[int, int](4, nil, lt, heapcraft.DaryHeapConfig{}),
+		"syncDary":        heapcraft.NewSyncDaryHeapWithConfig[int, int](4, nil, lt, heapcraft.DaryHeapConfig{}),
+		"pairing":         heapcraft.NewSimplePairingHeap[int, int](nil, lt, config),
+		"syncPairing":     heapcraft.NewSyncSimplePairingHeap[int, int](nil, lt, config),
+		"fullPairing":     heapcraft.NewFullPairingHeap[int, int](nil, lt, config),
+		"syncFullPairing": heapcraft.NewSyncFullPairingHeap[int, int](nil, lt, config),
+		"leftist":         heapcraft.NewSimpleLeftistHeap[int, int](nil, lt, config),
+		"syncLeftist":     heapcraft.NewSyncSimpleLeftistHeap[int, int](nil, lt, config),
+		"fullLeftist":     heapcraft.NewFullLeftistHeap[int, int](nil, lt, config),
+		"syncFullLeftist": heapcraft.NewSyncFullLeftistHeap[int, int](nil, lt, config),
+		"skew":            heapcraft.NewSimpleSkewHeap[int, int](nil, lt, config),
+		"syncSkew":        heapcraft.NewSyncSimpleSkewHeap[int, int](nil, lt, config),
+		"fullSkew":        heapcraft.NewFullSkewHeap[int, int](nil, lt, config),
+		"syncFullSkew":    heapcraft.NewSyncFullSkewHeap[int, int](nil, lt, config),
+		"binomial":        heapcraft.NewBinomialHeap[int, int](nil, lt, config),
+		"syncBinomial":    heapcraft.NewSyncBinomialHeap[int, int](nil, lt, config),
+		"adaptive":        heapcraft.NewAdaptiveHeap[int, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		switch h := heap.(type) {
+		case heapcraft.Heap[int, int]:
+			h.Push(2, 2)
+			h.Push(1, 1)
+		case heapcraft.TrackedHeap[int, int]:
+			h.Push(2, 2)
+			h.Push(1, 1)
+		}
+		value, _ := heap.PopValue()
+		fmt.Println(name, value)
+	}
+
+	radix := heapcraft.NewSyncRadixHeapWithConfig[int, uint](nil, heapcraft.RadixHeapConfig{UsePool: true})
+	radix.Push(1, 1)
+	value, _ := radix.PopValue()
+	fmt.Println("syncRadix", value)
+}
@@ -0,0 +1,285 @@
+package heapcraft
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is a value stored under the closed range [low, high].
+type Interval[V any, P constraints.Ordered] struct {
+	value V
+	low   P
+	high  P
+}
+
+// CreateInterval constructs a new Interval from the given value and bounds.
+func CreateInterval[V any, P constraints.Ordered](value V, low P, high P) Interval[V, P] {
+	return Interval[V, P]{value: value, low: low, high: high}
+}
+
+// Value returns the value stored in the interval.
+func (i Interval[V, P]) Value() V { return i.value }
+
+// Low returns the lower bound of the interval.
+func (i Interval[V, P]) Low() P { return i.low }
+
+// High returns the upper bound of the interval.
+func (i Interval[V, P]) High() P { return i.high }
+
+// Contains reports whether point lies within the interval, bounds included.
+func (i Interval[V, P]) Contains(point P) bool { return i.low <= point && point <= i.high }
+
+// intervalNode is an element of an IntervalHeap. maxHigh is the largest upper
+// bound in the subtree rooted at the node, which lets queries skip subtrees
+// that end before the queried range.
+type intervalNode[V any, P constraints.Ordered] struct {
+	interval Interval[V, P]
+	maxHigh  P
+}
+
+// IntervalHeap is a binary min-heap of closed intervals ordered by their lower
+// bound, so Pop returns the interval that starts first. Every node also
+// records the largest upper bound in its subtree, so Stab and Overlapping
+// visit only the subtrees that can hold a match: a subtree is skipped when its
+// root starts after the queried range or when every interval in it ends before
+// the range. This suits timeout ranges and calendar bookings, where the next
+// interval to start and the intervals active at a moment are both needed.
+type IntervalHeap[V any, P constraints.Ordered] struct {
+	data   []*intervalNode[V, P]
+	pool   pool[*intervalNode[V, P]]
+	stats  heapStats
+	events listeners[HeapEvent[V, P]]
+}
+
+// less reports whether the node at i starts before the node at j.
+func (h *IntervalHeap[V, P]) less(i, j int) bool {
+	return h.data[i].interval.low < h.data[j].interval.low
+}
+
+// updateMax recomputes the largest upper bound of the subtree at i from the
+// node itself and its children.
+func (h *IntervalHeap[V, P]) updateMax(i int) {
+	node := h.data[i]
+	node.maxHigh = node.interval.high
+	for c := 2*i + 1; c <= 2*i+2 && c < len(h.data); c++ {
+		node.maxHigh = max(node.maxHigh, h.data[c].maxHigh)
+	}
+}
+
+// updateMaxToRoot recomputes the largest upper bound of every subtree on the
+// path from i up to the root.
+func (h *IntervalHeap[V, P]) updateMaxToRoot(i int) {
+	for ; i > 0; i = (i - 1) / 2 {
+		h.updateMax(i)
+	}
+	if len(h.data) > 0 {
+		h.updateMax(0)
+	}
+}
+
+// siftUp moves the node at i towards the root until its parent starts no
+// later than it does, and returns its final index.
+func (h *IntervalHeap[V, P]) siftUp(i int) int {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.data[i], h.data[parent] = h.data[parent], h.data[i]
+		h.stats.swap()
+		h.updateMax(i)
+		i = parent
+	}
+	return i
+}
+
+// siftDown moves the node at i away from the root until neither child starts
+// before it, and returns its final index.
+func (h *IntervalHeap[V, P]) siftDown(i int) int {
+	for {
+		smallest := i
+		for c := 2*i + 1; c <= 2*i+2 && c < len(h.data); c++ {
+			if h.less(c, smallest) {
+				smallest = c
+			}
+		}
+		if smallest == i {
+			return i
+		}
+		h.data[i], h.data[smallest] = h.data[smallest], h.data[i]
+		h.stats.swap()
+		i = smallest
+	}
+}
+
+// Push adds value under the closed range [low, high]. Returns
+// ErrInvalidInterval if low is greater than high.
+func (h *IntervalHeap[V, P]) Push(value V, low P, high P) error {
+	if low > high {
+		return ErrInvalidInterval
+	}
+	node := h.pool.Get()
+	node.interval = Interval[V, P]{value: value, low: low, high: high}
+	node.maxHigh = high
+	h.data = append(h.data, node)
+	h.updateMaxToRoot(h.siftUp(len(h.data) - 1))
+	h.stats.record(OpPush, 1, len(h.data))
+	emitHeapEvent(h.events, EventPush, "", value, low)
+	return nil
+}
+
+// pop is an internal method that removes the interval at the root.
+func (h *IntervalHeap[V, P]) pop() (Interval[V, P], error) {
+	if len(h.data) == 0 {
+		return Interval[V, P]{}, ErrHeapEmpty
+	}
+	removed := h.data[0]
+	last := len(h.data) - 1
+	h.data[0] = h.data[last]
+	h.data[last] = nil
+	h.data = h.data[:last]
+	if last > 0 {
+		// The moved node may end anywhere on its way down, and the parent of
+		// the vacated slot has lost a child.
+		h.updateMaxToRoot(h.siftDown(0))
+		h.updateMaxToRoot((last - 1) / 2)
+	}
+	h.stats.record(OpPop, 1, len(h.data))
+
+	interval := removed.interval
+	*removed = intervalNode[V, P]{}
+	h.pool.Put(removed)
+	emitHeapEvent(h.events, EventPop, "", interval.value, interval.low)
+	return interval, nil
+}
+
+// Pop removes and returns the interval with the smallest lower bound. Returns
+// an error if the heap is empty.
+func (h *IntervalHeap[V, P]) Pop() (Interval[V, P], error) { return h.pop() }
+
+// Peek returns the interval with the smallest lower bound without removing
+// it. Returns an error if the heap is empty.
+func (h *IntervalHeap[V, P]) Peek() (Interval[V, P], error) {
+	if len(h.data) == 0 {
+		return Interval[V, P]{}, ErrHeapEmpty
+	}
+	return h.data[0].interval, nil
+}
+
+// search calls fn for every interval that overlaps the closed range
+// [low, high], in no particular order.
+func (h *IntervalHeap[V, P]) search(low P, high P, fn func(Interval[V, P])) {
+	if len(h.data) == 0 {
+		return
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := h.data[i]
+		// Every interval below starts no earlier than the node and ends no
+		// later than maxHigh, so the whole subtree can be skipped.
+		if node.interval.low > high || node.maxHigh < low {
+			continue
+		}
+		if node.interval.high >= low {
+			fn(node.interval)
+		}
+		for c := 2*i + 1; c <= 2*i+2 && c < len(h.data); c++ {
+			stack = append(stack, c)
+		}
+	}
+}
+
+// Stab returns every interval that contains point, in no particular order.
+// Subtrees that start after point or end before it are never visited.
+func (h *IntervalHeap[V, P]) Stab(point P) []Interval[V, P] {
+	return h.Overlapping(point, point)
+}
+
+// Overlapping returns every interval that shares at least one point with the
+// closed range [low, high], in no particular order. Returns nil if low is
+// greater than high.
+func (h *IntervalHeap[V, P]) Overlapping(low P, high P) []Interval[V, P] {
+	if low > high {
+		return nil
+	}
+	var found []Interval[V, P]
+	h.search(low, high, func(interval Interval[V, P]) {
+		found = append(found, interval)
+	})
+	return found
+}
+
+// Clear removes all intervals from the heap. When pooling is enabled, the
+// nodes are returned to the pool for reuse.
+func (h *IntervalHeap[V, P]) Clear() {
+	cleared := h.Length()
+	for i, node := range h.data {
+		*node = intervalNode[V, P]{}
+		h.pool.Put(node)
+		h.data[i] = nil
+	}
+	h.data = h.data[:0]
+	h.stats.record(OpClear, cleared, 0)
+	emitClearEvent(h.events)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+// Swaps counts the nodes moved while sifting.
+func (h *IntervalHeap[V, P]) Stats() HeapStats { return h.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (h *IntervalHeap[V, P]) Instrument(hook Instrumentation) { h.stats.hook = hook }
+
+// OnEvent registers fn to be called for every interval pushed or popped, and
+// whenever the heap is cleared. An event's Priority is the interval's lower
+// bound. Returns an ID that can be passed to RemoveListener.
+func (h *IntervalHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return h.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *IntervalHeap[V, P]) RemoveListener(id string) error { return h.events.deregister(id) }
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (h *IntervalHeap[V, P]) PoolStats() PoolStats { return h.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its array, its
+// nodes and the nodes idle in its pool. Memory that values point to is not
+// counted.
+func (h *IntervalHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[IntervalHeap[V, P]]() + cap(h.data)*sizeOf[*intervalNode[V, P]]() + nodeMemory(len(h.data), h.pool)
+}
+
+// Length returns the number of intervals in the heap.
+func (h *IntervalHeap[V, P]) Length() int { return len(h.data) }
+
+// IsEmpty returns true if the heap contains no intervals.
+func (h *IntervalHeap[V, P]) IsEmpty() bool { return len(h.data) == 0 }
+
+// Verify checks the internal consistency of the heap: no interval may start
+// before its parent, no interval may end before it starts, and every node must
+// record the largest upper bound of its subtree. It is intended for tests and
+// debugging, runs in O(n) and returns an error wrapping ErrInvariantViolated
+// for the first violation.
+func (h *IntervalHeap[V, P]) Verify() error {
+	for i := len(h.data) - 1; i >= 0; i-- {
+		node := h.data[i]
+		if node.interval.low > node.interval.high {
+			return invariantError("the interval at index %d ends before it starts", i)
+		}
+		if i > 0 && h.less(i, (i-1)/2) {
+			return invariantError("the interval at index %d starts before its parent", i)
+		}
+		want := node.interval.high
+		for c := 2*i + 1; c <= 2*i+2 && c < len(h.data); c++ {
+			want = max(want, h.data[c].maxHigh)
+		}
+		if node.maxHigh != want {
+			return invariantError("the node at index %d records upper bound %v instead of %v", i, node.maxHigh, want)
+		}
+	}
+	return nil
+}
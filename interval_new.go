@@ -0,0 +1,36 @@
+package heapcraft
+
+import "golang.org/x/exp/constraints"
+
+// NewIntervalHeap creates an IntervalHeap from a slice of Intervals, built
+// bottom-up in O(n) time. config.UsePool controls pooling of the heap's nodes;
+// the heap does not track IDs or use an arena, so the other fields are
+// ignored. Returns ErrInvalidInterval, and no heap, if any interval's lower
+// bound is greater than its upper bound.
+func NewIntervalHeap[V any, P constraints.Ordered](data []Interval[V, P], config HeapConfig) (*IntervalHeap[V, P], error) {
+	for i := range data {
+		if data[i].low > data[i].high {
+			return nil, ErrInvalidInterval
+		}
+	}
+
+	pool := newPool(config.UsePool, func() *intervalNode[V, P] {
+		return &intervalNode[V, P]{}
+	})
+	heap := &IntervalHeap[V, P]{
+		data: make([]*intervalNode[V, P], len(data)),
+		pool: pool,
+	}
+	for i := range data {
+		node := pool.Get()
+		node.interval = data[i]
+		heap.data[i] = node
+	}
+	for i := len(data)/2 - 1; i >= 0; i-- {
+		heap.siftDown(i)
+	}
+	for i := len(data) - 1; i >= 0; i-- {
+		heap.updateMax(i)
+	}
+	return heap, nil
+}
@@ -0,0 +1,115 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// intervalValues returns the values of intervals in ascending order.
+func intervalValues(intervals []Interval[string, int]) []string {
+	values := make([]string, len(intervals))
+	for i, interval := range intervals {
+		values[i] = interval.Value()
+	}
+	slices.Sort(values)
+	return values
+}
+
+func TestIntervalHeapPopOrder(t *testing.T) {
+	h, err := NewIntervalHeap([]Interval[string, int]{
+		CreateInterval("standup", 9, 10),
+		CreateInterval("lunch", 12, 13),
+		CreateInterval("review", 10, 12),
+	}, HeapConfig{})
+	require.NoError(t, err)
+	require.NoError(t, h.Verify())
+	require.NoError(t, h.Push("breakfast", 7, 8))
+	assert.ErrorIs(t, h.Push("backwards", 5, 4), ErrInvalidInterval)
+	assert.Equal(t, 4, h.Length())
+
+	first, err := h.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "breakfast", first.Value())
+	assert.Equal(t, 7, first.Low())
+	assert.Equal(t, 8, first.High())
+
+	var order []string
+	for !h.IsEmpty() {
+		interval, err := h.Pop()
+		require.NoError(t, err)
+		order = append(order, interval.Value())
+		require.NoError(t, h.Verify())
+	}
+	assert.Equal(t, []string{"breakfast", "standup", "review", "lunch"}, order)
+	_, err = h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	_, err = NewIntervalHeap([]Interval[string, int]{CreateInterval("bad", 2, 1)}, HeapConfig{})
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+func TestIntervalHeapStabAndOverlapping(t *testing.T) {
+	h, err := NewIntervalHeap[string, int](nil, HeapConfig{UsePool: true})
+	require.NoError(t, err)
+	require.NoError(t, h.Push("a", 1, 5))
+	require.NoError(t, h.Push("b", 3, 3))
+	require.NoError(t, h.Push("c", 4, 10))
+	require.NoError(t, h.Push("d", 6, 8))
+
+	assert.Equal(t, []string{"a", "b"}, intervalValues(h.Stab(3)))
+	assert.Equal(t, []string{"a", "c"}, intervalValues(h.Stab(5)))
+	assert.Empty(t, h.Stab(0))
+	assert.Equal(t, []string{"c", "d"}, intervalValues(h.Overlapping(6, 20)))
+	assert.Nil(t, h.Overlapping(6, 5))
+	assert.True(t, CreateInterval("x", 1, 2).Contains(2))
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	assert.Empty(t, h.Stab(3))
+	require.NoError(t, h.Push("e", 2, 2))
+	assert.Equal(t, []string{"e"}, intervalValues(h.Stab(2)))
+}
+
+func TestIntervalHeapMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	h, err := NewIntervalHeap[int, int](nil, HeapConfig{})
+	require.NoError(t, err)
+	var live []Interval[int, int]
+	for i := 0; i < 2000; i++ {
+		if rng.Intn(3) > 0 || len(live) == 0 {
+			low := rng.Intn(1000)
+			high := low + rng.Intn(100)
+			require.NoError(t, h.Push(i, low, high))
+			live = append(live, CreateInterval(i, low, high))
+		} else {
+			popped, err := h.Pop()
+			require.NoError(t, err)
+			for _, interval := range live {
+				require.LessOrEqual(t, popped.Low(), interval.Low())
+			}
+			live = slices.DeleteFunc(live, func(interval Interval[int, int]) bool {
+				return interval.Value() == popped.Value()
+			})
+		}
+		require.NoError(t, h.Verify())
+
+		low := rng.Intn(1100)
+		high := low + rng.Intn(20)
+		var want, got []int
+		for _, interval := range live {
+			if interval.Low() <= high && interval.High() >= low {
+				want = append(want, interval.Value())
+			}
+		}
+		for _, interval := range h.Overlapping(low, high) {
+			got = append(got, interval.Value())
+		}
+		slices.Sort(want)
+		slices.Sort(got)
+		require.Equal(t, want, got)
+	}
+}
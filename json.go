@@ -0,0 +1,114 @@
+package heapcraft
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// heapNodeJSON is the JSON representation of a HeapNode.
+type heapNodeJSON[V any, P any] struct {
+	Value    V `json:"value"`
+	Priority P `json:"priority"`
+}
+
+// MarshalJSON encodes the node as an object with "value" and "priority"
+// fields.
+func (n HeapNode[V, P]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(heapNodeJSON[V, P]{Value: n.value, Priority: n.priority})
+}
+
+// UnmarshalJSON decodes an object with "value" and "priority" fields into the
+// node.
+func (n *HeapNode[V, P]) UnmarshalJSON(data []byte) error {
+	var decoded heapNodeJSON[V, P]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	n.value, n.priority = decoded.Value, decoded.Priority
+	return nil
+}
+
+// heapJSON is the JSON representation of a heap that does not track its
+// elements. Nodes are listed best-first. D is only set for d-ary heaps and
+// Last only for radix heaps.
+type heapJSON[V any, P any] struct {
+	D     int              `json:"d,omitempty"`
+	Last  *P               `json:"last,omitempty"`
+	Nodes []HeapNode[V, P] `json:"nodes"`
+}
+
+// trackedNodeJSON is the JSON representation of a node in a tracked heap.
+type trackedNodeJSON[V any, P any] struct {
+	ID       string `json:"id"`
+	Value    V      `json:"value"`
+	Priority P      `json:"priority"`
+}
+
+// trackedHeapJSON is the JSON representation of a tracked heap. Nodes are
+// listed best-first, with ties broken by ID so the output is deterministic.
+type trackedHeapJSON[V any, P any] struct {
+	Nodes []trackedNodeJSON[V, P] `json:"nodes"`
+}
+
+// marshalHeap encodes the elements of a heap, as returned by Export, in the
+// common JSON representation.
+func marshalHeap[V any, P any](nodes []HeapNode[V, P]) ([]byte, error) {
+	return json.Marshal(heapJSON[V, P]{Nodes: nodes})
+}
+
+// unmarshalHeap decodes the common JSON representation of a heap. It fails
+// with ErrUninitializedHeap if the receiving heap has no comparison function,
+// since elements could not be ordered on restore.
+func unmarshalHeap[V any, P any](data []byte, cmp func(a, b P) bool) (heapJSON[V, P], error) {
+	var decoded heapJSON[V, P]
+	if cmp == nil {
+		return decoded, ErrUninitializedHeap
+	}
+	err := json.Unmarshal(data, &decoded)
+	return decoded, err
+}
+
+// marshalTrackedHeap encodes the elements of a tracked heap, ordered
+// best-first according to cmp and then by ID.
+func marshalTrackedHeap[V any, P any, N any](elements map[string]N, cmp func(a, b P) bool, fields func(N) (V, P)) ([]byte, error) {
+	nodes := make([]trackedNodeJSON[V, P], 0, len(elements))
+	for id, node := range elements {
+		v, p := fields(node)
+		nodes = append(nodes, trackedNodeJSON[V, P]{ID: id, Value: v, Priority: p})
+	}
+
+	slices.SortFunc(nodes, func(a, b trackedNodeJSON[V, P]) int {
+		switch {
+		case cmp(a.Priority, b.Priority):
+			return -1
+		case cmp(b.Priority, a.Priority):
+			return 1
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return json.Marshal(trackedHeapJSON[V, P]{Nodes: nodes})
+}
+
+// unmarshalTrackedHeap decodes the JSON representation of a tracked heap. It
+// fails with ErrUninitializedHeap if the receiving heap has no comparison
+// function and with ErrDuplicateID if an ID appears more than once.
+func unmarshalTrackedHeap[V any, P any](data []byte, cmp func(a, b P) bool) ([]trackedNodeJSON[V, P], error) {
+	if cmp == nil {
+		return nil, ErrUninitializedHeap
+	}
+
+	var decoded trackedHeapJSON[V, P]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(decoded.Nodes))
+	for _, node := range decoded.Nodes {
+		if _, exists := seen[node.ID]; exists {
+			return nil, ErrDuplicateID
+		}
+		seen[node.ID] = struct{}{}
+	}
+	return decoded.Nodes, nil
+}
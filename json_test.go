@@ -0,0 +1,155 @@
+package heapcraft
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonHeap is a heap that can be checkpointed to JSON and restored.
+type jsonHeap interface {
+	Heap[string, int]
+	json.Marshaler
+	json.Unmarshaler
+}
+
+func TestHeapNode_JSON(t *testing.T) {
+	data, err := json.Marshal(CreateHeapNode("job", 3))
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"value":"job","priority":3}`, string(data))
+
+	var node HeapNode[string, int]
+	require.Nil(t, json.Unmarshal(data, &node))
+	assert.Equal(t, CreateHeapNode("job", 3), node)
+}
+
+func TestHeap_JSONRoundTrip(t *testing.T) {
+	heaps := map[string]func() jsonHeap{
+		"dary":        func() jsonHeap { return NewDaryHeap[string, int](3, nil, lt, false) },
+		"syncDary":    func() jsonHeap { return NewSyncDaryHeap[string, int](3, nil, lt, true) },
+		"pairing":     func() jsonHeap { return NewPairingHeap[string, int](nil, lt, false) },
+		"syncPairing": func() jsonHeap { return NewSyncPairingHeap[string, int](nil, lt, true) },
+		"leftist":     func() jsonHeap { return NewLeftistHeap[string, int](nil, lt, false) },
+		"syncLeftist": func() jsonHeap { return NewSyncLeftistHeap[string, int](nil, lt, true) },
+		"skew":        func() jsonHeap { return NewSkewHeap[string, int](nil, lt, false) },
+		"syncSkew":    func() jsonHeap { return NewSyncSkewHeap[string, int](nil, lt, true) },
+		"binomial":    func() jsonHeap { return NewBinomialHeap[string, int](nil, lt, HeapConfig{}) },
+		"adaptive":    func() jsonHeap { return NewAdaptiveHeap[string, int](nil, lt, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		for i, v := range []string{"e", "b", "d", "a", "c"} {
+			heap.Push(v, []int{5, 2, 4, 1, 3}[i])
+		}
+
+		data, err := json.Marshal(heap)
+		require.Nil(t, err, name)
+		assert.Contains(t, string(data), `"nodes":[{"value":"a","priority":1}`, name)
+		assert.Equal(t, 5, heap.Length(), name)
+
+		restored := constructor()
+		restored.Push("stale", 0)
+		require.Nil(t, json.Unmarshal(data, restored), name)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, restored.DrainValues(), name)
+	}
+}
+
+func TestDaryHeap_JSONArity(t *testing.T) {
+	heap := NewDaryHeap[string, int](4, nil, lt, false)
+	heap.Push("a", 1)
+	data, err := json.Marshal(heap)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"d":4,"nodes":[{"value":"a","priority":1}]}`, string(data))
+
+	restored := NewBinaryHeap[string, int](nil, lt, false)
+	require.Nil(t, json.Unmarshal(data, restored))
+	assert.Equal(t, 4, restored.d)
+
+	var uninitialized DaryHeap[string, int]
+	assert.ErrorIs(t, json.Unmarshal(data, &uninitialized), ErrUninitializedHeap)
+}
+
+func TestRadixHeap_JSON(t *testing.T) {
+	heap := NewRadixHeap[string, uint](nil, false)
+	heap.Push("a", 1)
+	heap.Push("b", 5)
+	heap.Push("c", 9)
+	heap.Pop()
+
+	data, err := json.Marshal(heap)
+	require.Nil(t, err)
+
+	restored := NewSyncRadixHeap[string, uint](nil, false)
+	require.Nil(t, json.Unmarshal(data, restored))
+	assert.Equal(t, heap.last, restored.heap.last)
+	assert.ErrorIs(t, restored.Push("z", 0), ErrPriorityLessThanLast)
+	assert.Equal(t, []string{"b", "c"}, restored.DrainValues())
+
+	invalid := []byte(`{"last":4,"nodes":[{"value":"x","priority":3}]}`)
+	assert.ErrorIs(t, json.Unmarshal(invalid, restored), ErrPriorityLessThanLast)
+
+	var uninitialized RadixHeap[string, uint]
+	assert.ErrorIs(t, json.Unmarshal(data, &uninitialized), ErrUninitializedHeap)
+}
+
+func TestTrackedHeap_JSONRoundTrip(t *testing.T) {
+	heaps := map[string]func() TrackedHeap[string, int]{
+		"pairing": func() TrackedHeap[string, int] {
+			return NewFullPairingHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+		},
+		"syncPairing": func() TrackedHeap[string, int] {
+			return NewSyncFullPairingHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+		},
+		"leftist": func() TrackedHeap[string, int] {
+			return NewFullLeftistHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+		},
+		"syncLeftist": func() TrackedHeap[string, int] {
+			return NewSyncFullLeftistHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+		},
+		"skew": func() TrackedHeap[string, int] {
+			return NewFullSkewHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+		},
+		"syncSkew": func() TrackedHeap[string, int] {
+			return NewSyncFullSkewHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+		},
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		heap.Push("c", 3)
+		heap.Push("a", 1)
+		heap.Push("b", 1)
+
+		data, err := json.Marshal(heap)
+		require.Nil(t, err, name)
+		assert.JSONEq(t, `{"nodes":[
+			{"id":"1","value":"a","priority":1},
+			{"id":"2","value":"b","priority":1},
+			{"id":"0","value":"c","priority":3}
+		]}`, string(data), name)
+
+		restored := constructor()
+		require.Nil(t, json.Unmarshal(data, restored), name)
+		value, err := restored.GetValue("0")
+		assert.Nil(t, err, name)
+		assert.Equal(t, "c", value, name)
+		assert.Nil(t, restored.UpdatePriority("0", 0), name)
+
+		// The ID generator continues after the restored IDs.
+		id, err := restored.Push("d", 4)
+		assert.Nil(t, err, name)
+		assert.Equal(t, "3", id, name)
+		assert.Equal(t, []string{"c", "a", "b", "d"}, restored.DrainValues(), name)
+
+		duplicate := []byte(`{"nodes":[{"id":"x","value":"a","priority":1},{"id":"x","value":"b","priority":2}]}`)
+		restored.Push("kept", 1)
+		assert.ErrorIs(t, json.Unmarshal(duplicate, restored), ErrDuplicateID, name)
+		assert.Equal(t, 1, restored.Length(), name)
+	}
+
+	var uninitialized FullPairingHeap[string, int]
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"nodes":[]}`), &uninitialized), ErrUninitializedHeap)
+}
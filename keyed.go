@@ -0,0 +1,298 @@
+package heapcraft
+
+// keyedEntry is a value stored in a KeyedHeap together with the caller's key
+// for it.
+type keyedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// KeyedHeap is a d-ary heap whose elements are identified by a key chosen by
+// the caller rather than a generated ID. Push inserts a new element or
+// replaces the one already stored under the key, and UpdatePriority and
+// Remove look elements up by key in O(1) before restoring the heap order in
+// O(log n). A key-to-index map is kept up to date through the heap's swap
+// callbacks, as in IndexedDaryHeap. The heap is not safe for concurrent use;
+// use SyncKeyedHeap for that.
+type KeyedHeap[K comparable, V any, P any] struct {
+	heap   *DaryHeap[keyedEntry[K, V], P]
+	index  map[K]int
+	events listeners[HeapEvent[V, P]]
+}
+
+// track records the new positions of the elements at indices x and y after
+// the underlying heap has swapped them.
+func (h *KeyedHeap[K, V, P]) track(x, y int) {
+	h.index[h.heap.data[x].value.key] = x
+	h.index[h.heap.data[y].value.key] = y
+}
+
+// Push inserts an element under key. If the key is already present, its value
+// and priority are replaced and the element is moved to its new position
+// instead. Returns true if a new element was inserted and false if an
+// existing one was updated.
+func (h *KeyedHeap[K, V, P]) Push(key K, value V, priority P) bool {
+	if i, exists := h.index[key]; exists {
+		h.heap.data[i].value.value = value
+		h.heap.data[i].priority = priority
+		h.heap.restoreHeap(i)
+		emitHeapEvent(h.events, EventUpdate, "", value, priority)
+		return false
+	}
+	h.index[key] = h.heap.Length()
+	h.heap.Push(keyedEntry[K, V]{key: key, value: value}, priority)
+	emitHeapEvent(h.events, EventPush, "", value, priority)
+	return true
+}
+
+// GetOrPush returns the value and priority of the element stored under key,
+// with true, if there is one. Otherwise it inserts the given element under
+// key and returns it with false. Unlike Push, an existing element is never
+// updated, so enqueueing the same job twice is harmless.
+func (h *KeyedHeap[K, V, P]) GetOrPush(key K, value V, priority P) (V, P, bool) {
+	if i, exists := h.index[key]; exists {
+		node := h.heap.data[i]
+		return node.value.value, node.priority, true
+	}
+	h.Push(key, value, priority)
+	return value, priority, false
+}
+
+// PushIfAbsent inserts an element under key only if no element is stored
+// under it yet. Returns true if the element was inserted.
+func (h *KeyedHeap[K, V, P]) PushIfAbsent(key K, value V, priority P) bool {
+	_, _, loaded := h.GetOrPush(key, value, priority)
+	return !loaded
+}
+
+// Contains returns true if an element is stored under key.
+func (h *KeyedHeap[K, V, P]) Contains(key K) bool {
+	_, exists := h.index[key]
+	return exists
+}
+
+// get is an internal method that returns the value and priority of the
+// element stored under key.
+func (h *KeyedHeap[K, V, P]) get(key K) (V, P, error) {
+	i, exists := h.index[key]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &KeyNotFoundError[K]{Key: key}
+	}
+	node := h.heap.data[i]
+	return node.value.value, node.priority, nil
+}
+
+// Get returns the value and priority of the element stored under key.
+// Returns zero values and an error if the key does not exist.
+func (h *KeyedHeap[K, V, P]) Get(key K) (V, P, error) { return h.get(key) }
+
+// GetValue returns the value of the element stored under key. Returns zero
+// value and an error if the key does not exist.
+func (h *KeyedHeap[K, V, P]) GetValue(key K) (V, error) {
+	return valueFromNode(h.get(key))
+}
+
+// GetPriority returns the priority of the element stored under key. Returns
+// zero value and an error if the key does not exist.
+func (h *KeyedHeap[K, V, P]) GetPriority(key K) (P, error) {
+	return priorityFromNode(h.get(key))
+}
+
+// UpdatePriority changes the priority of the element stored under key and
+// restores the heap order by sifting it up or down. Returns an error if the
+// key does not exist.
+func (h *KeyedHeap[K, V, P]) UpdatePriority(key K, priority P) error {
+	i, exists := h.index[key]
+	if !exists {
+		return &KeyNotFoundError[K]{Key: key}
+	}
+	h.heap.data[i].priority = priority
+	value := h.heap.data[i].value.value
+	h.heap.restoreHeap(i)
+	emitHeapEvent(h.events, EventUpdate, "", value, priority)
+	return nil
+}
+
+// UpdatePriorityFunc replaces the priority of the element stored under key
+// with fn applied to its current priority, for adjustments such as a boost by
+// elapsed time. Returns an error if the key does not exist, in which case fn
+// is not called.
+func (h *KeyedHeap[K, V, P]) UpdatePriorityFunc(key K, fn func(old P) P) error {
+	return updatePriorityFunc(h.GetPriority, h.UpdatePriority, key, fn)
+}
+
+// Remove deletes the element stored under key and returns its value and
+// priority. Returns an error if the key does not exist.
+func (h *KeyedHeap[K, V, P]) Remove(key K) (V, P, error) {
+	i, exists := h.index[key]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &KeyNotFoundError[K]{Key: key}
+	}
+	removed := h.heap.removeAt(i)
+	delete(h.index, key)
+	v, p := removed.value.value, removed.priority
+	emitHeapEvent(h.events, EventRemove, "", v, p)
+	return v, p, nil
+}
+
+// Clear removes all elements from the heap.
+func (h *KeyedHeap[K, V, P]) Clear() {
+	h.heap.Clear()
+	clear(h.index)
+	emitClearEvent(h.events)
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its array and its
+// index map. Memory that keys, values and priorities point to, such as the
+// contents of strings, is not counted.
+func (h *KeyedHeap[K, V, P]) ApproxMemoryUsage() int {
+	return sizeOf[KeyedHeap[K, V, P]]() + h.heap.ApproxMemoryUsage() + mapMemory[K, int](len(h.index))
+}
+
+// Stats returns the operations the heap has performed since it was created,
+// as counted by the underlying d-ary heap. Removals by key count as
+// removes, and updates are not counted.
+func (h *KeyedHeap[K, V, P]) Stats() HeapStats { return h.heap.Stats() }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (h *KeyedHeap[K, V, P]) Instrument(hook Instrumentation) { h.heap.Instrument(hook) }
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, including a Push that replaces the element under an existing
+// key, and whenever the heap is cleared. Events carry no ID; the key is not
+// reported. Returns an ID that can be passed to RemoveListener.
+func (h *KeyedHeap[K, V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return h.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *KeyedHeap[K, V, P]) RemoveListener(id string) error { return h.events.deregister(id) }
+
+// Length returns the number of elements in the heap.
+func (h *KeyedHeap[K, V, P]) Length() int { return h.heap.Length() }
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *KeyedHeap[K, V, P]) IsEmpty() bool { return h.heap.IsEmpty() }
+
+// Verify checks the internal consistency of the heap: the underlying d-ary
+// heap must pass its own Verify, and the key map must hold exactly one entry
+// per element, pointing at that element's current index. It is intended for
+// tests and debugging, runs in O(n) and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (h *KeyedHeap[K, V, P]) Verify() error {
+	if err := h.heap.Verify(); err != nil {
+		return err
+	}
+	for i, node := range h.heap.data {
+		if j, exists := h.index[node.value.key]; !exists || j != i {
+			return invariantError("element %v at index %d is indexed at %d", node.value.key, i, j)
+		}
+	}
+	return verifyElements(len(h.index), h.heap.Length())
+}
+
+// PeekKey returns the key of the root element without removing it. Returns
+// zero value and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PeekKey() (K, error) {
+	entry, _, err := h.heap.Peek()
+	return entry.key, err
+}
+
+// peek is an internal method that returns the root element without removing
+// it.
+func (h *KeyedHeap[K, V, P]) peek() (V, P, error) {
+	entry, priority, err := h.heap.Peek()
+	return entry.value, priority, err
+}
+
+// Peek returns the value and priority of the root element without removing
+// it. Returns zero values and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) Peek() (V, P, error) { return h.peek() }
+
+// PeekValue returns the value of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PeekValue() (V, error) {
+	return valueFromNode(h.peek())
+}
+
+// PeekPriority returns the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(h.peek())
+}
+
+// PopKey removes the root element and returns its key, value and priority.
+// Returns zero values and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PopKey() (K, V, P, error) {
+	entry, priority, err := h.heap.Pop()
+	if err == nil {
+		delete(h.index, entry.key)
+		emitHeapEvent(h.events, EventPop, "", entry.value, priority)
+	}
+	return entry.key, entry.value, priority, err
+}
+
+// pop is an internal method that removes and returns the root element and
+// forgets its key.
+func (h *KeyedHeap[K, V, P]) pop() (V, P, error) {
+	_, v, p, err := h.PopKey()
+	return v, p, err
+}
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) Pop() (V, P, error) { return h.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(h.Peek, h.Pop, pred)
+}
+
+// PopValue removes and returns the value of the root element. Returns zero
+// value and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PopValue() (V, error) {
+	return valueFromNode(h.pop())
+}
+
+// PopPriority removes and returns the priority of the root element. Returns
+// zero value and an error if the heap is empty.
+func (h *KeyedHeap[K, V, P]) PopPriority() (P, error) {
+	return priorityFromNode(h.pop())
+}
+
+// Drain removes all elements from the heap and returns them in priority
+// order. The heap is empty afterwards.
+func (h *KeyedHeap[K, V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(h.Length(), h.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (h *KeyedHeap[K, V, P]) DrainValues() []V {
+	return drainValues(h.Length(), h.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (h *KeyedHeap[K, V, P]) DrainPriorities() []P {
+	return drainPriorities(h.Length(), h.pop)
+}
+
+// forEach calls fn for every element in the heap, in storage order.
+func (h *KeyedHeap[K, V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range h.heap.data {
+		fn(node.value.value, node.priority)
+	}
+}
+
+// Export returns a copy of the elements in the heap according to opts. The
+// heap itself is not modified.
+func (h *KeyedHeap[K, V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(h.Length(), h.forEach, h.heap.cmp, opts)
+}
@@ -0,0 +1,21 @@
+package heapcraft
+
+// NewKeyedHeap creates an empty KeyedHeap with arity d. The comparison
+// function determines the heap order (min or max), and config sets the
+// options of the d-ary heap the elements are kept in. The heap tracks
+// positions through a swap callback, so DisableCallbacks is ignored.
+func NewKeyedHeap[K comparable, V any, P any](d int, cmp func(a, b P) bool, config DaryHeapConfig) *KeyedHeap[K, V, P] {
+	config.DisableCallbacks = false
+	h := &KeyedHeap[K, V, P]{
+		heap:  NewDaryHeapWithConfig[keyedEntry[K, V], P](d, nil, cmp, config),
+		index: make(map[K]int, config.Capacity),
+	}
+	h.heap.Register(h.track)
+	return h
+}
+
+// NewSyncKeyedHeap creates an empty thread-safe KeyedHeap with arity d. The
+// comparison function determines the heap order (min or max).
+func NewSyncKeyedHeap[K comparable, V any, P any](d int, cmp func(a, b P) bool, config DaryHeapConfig) *SyncKeyedHeap[K, V, P] {
+	return &SyncKeyedHeap[K, V, P]{heap: NewKeyedHeap[K, V, P](d, cmp, config)}
+}
@@ -0,0 +1,258 @@
+package heapcraft
+
+import "sync"
+
+// SyncKeyedHeap is a thread-safe wrapper around KeyedHeap.
+type SyncKeyedHeap[K comparable, V any, P any] struct {
+	heap *KeyedHeap[K, V, P]
+	lock sync.RWMutex
+}
+
+// Push inserts an element under key, or updates the element already stored
+// under it. Returns true if a new element was inserted.
+func (h *SyncKeyedHeap[K, V, P]) Push(key K, value V, priority P) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Push(key, value, priority)
+}
+
+// GetOrPush returns the element stored under key with true, or inserts the
+// given element under key and returns it with false. The lookup and the
+// insert happen under a single write lock.
+func (h *SyncKeyedHeap[K, V, P]) GetOrPush(key K, value V, priority P) (V, P, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.GetOrPush(key, value, priority)
+}
+
+// PushIfAbsent inserts an element under key only if no element is stored
+// under it yet. Returns true if the element was inserted.
+func (h *SyncKeyedHeap[K, V, P]) PushIfAbsent(key K, value V, priority P) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PushIfAbsent(key, value, priority)
+}
+
+// Contains returns true if an element is stored under key.
+func (h *SyncKeyedHeap[K, V, P]) Contains(key K) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Contains(key)
+}
+
+// Get returns the value and priority of the element stored under key.
+func (h *SyncKeyedHeap[K, V, P]) Get(key K) (V, P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Get(key)
+}
+
+// GetValue returns the value of the element stored under key.
+func (h *SyncKeyedHeap[K, V, P]) GetValue(key K) (V, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.GetValue(key)
+}
+
+// GetPriority returns the priority of the element stored under key.
+func (h *SyncKeyedHeap[K, V, P]) GetPriority(key K) (P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.GetPriority(key)
+}
+
+// UpdatePriority changes the priority of the element stored under key and
+// restores the heap order.
+func (h *SyncKeyedHeap[K, V, P]) UpdatePriority(key K, priority P) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.UpdatePriority(key, priority)
+}
+
+// UpdatePriorityFunc replaces the priority of the element stored under key
+// with fn applied to its current priority. The read and the update happen
+// under a single write lock, so no other goroutine can change the priority in
+// between. fn must not call back into the heap.
+func (h *SyncKeyedHeap[K, V, P]) UpdatePriorityFunc(key K, fn func(old P) P) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.UpdatePriorityFunc(key, fn)
+}
+
+// Remove deletes the element stored under key and returns its value and
+// priority.
+func (h *SyncKeyedHeap[K, V, P]) Remove(key K) (V, P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Remove(key)
+}
+
+// PeekKey returns the key of the root element without removing it.
+func (h *SyncKeyedHeap[K, V, P]) PeekKey() (K, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.PeekKey()
+}
+
+// PopKey removes the root element and returns its key, value and priority.
+func (h *SyncKeyedHeap[K, V, P]) PopKey() (K, V, P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopKey()
+}
+
+// Clear removes all elements from the heap.
+func (h *SyncKeyedHeap[K, V, P]) Clear() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Clear()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (h *SyncKeyedHeap[K, V, P]) ApproxMemoryUsage() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.ApproxMemoryUsage()
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (h *SyncKeyedHeap[K, V, P]) Stats() HeapStats {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (h *SyncKeyedHeap[K, V, P]) Instrument(hook Instrumentation) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (h *SyncKeyedHeap[K, V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *SyncKeyedHeap[K, V, P]) RemoveListener(id string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.RemoveListener(id)
+}
+
+// Length returns the number of elements in the heap.
+func (h *SyncKeyedHeap[K, V, P]) Length() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *SyncKeyedHeap[K, V, P]) IsEmpty() bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (h *SyncKeyedHeap[K, V, P]) Verify() error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Verify()
+}
+
+// Peek returns the value and priority of the root element without removing
+// it.
+func (h *SyncKeyedHeap[K, V, P]) Peek() (V, P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Peek()
+}
+
+// PeekValue returns the value of the root element without removing it.
+func (h *SyncKeyedHeap[K, V, P]) PeekValue() (V, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.PeekValue()
+}
+
+// PeekPriority returns the priority of the root element without removing it.
+func (h *SyncKeyedHeap[K, V, P]) PeekPriority() (P, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and priority of the root element.
+func (h *SyncKeyedHeap[K, V, P]) Pop() (V, P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (h *SyncKeyedHeap[K, V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopIf(pred)
+}
+
+// PopValue removes and returns the value of the root element.
+func (h *SyncKeyedHeap[K, V, P]) PopValue() (V, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopValue()
+}
+
+// PopPriority removes and returns the priority of the root element.
+func (h *SyncKeyedHeap[K, V, P]) PopPriority() (P, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.PopPriority()
+}
+
+// Drain removes all elements from the heap and returns them in priority
+// order.
+func (h *SyncKeyedHeap[K, V, P]) Drain() []HeapNode[V, P] {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order.
+func (h *SyncKeyedHeap[K, V, P]) DrainValues() []V {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order.
+func (h *SyncKeyedHeap[K, V, P]) DrainPriorities() []P {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap according to opts.
+func (h *SyncKeyedHeap[K, V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.heap.Export(opts)
+}
@@ -0,0 +1,175 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedHeap_PushUpserts(t *testing.T) {
+	heap := NewKeyedHeap[string, string, int](4, lt, DaryHeapConfig{})
+	assert.True(t, heap.Push("reindex", "rebuild", 10))
+	assert.True(t, heap.Push("backup", "nightly", 5))
+	assert.True(t, heap.Push("vacuum", "tables", 7))
+	assert.False(t, heap.Push("reindex", "rebuild now", 1))
+	assert.Equal(t, 3, heap.Length())
+	require.NoError(t, heap.Verify())
+
+	key, err := heap.PeekKey()
+	require.NoError(t, err)
+	assert.Equal(t, "reindex", key)
+
+	key, value, priority, err := heap.PopKey()
+	require.NoError(t, err)
+	assert.Equal(t, "reindex", key)
+	assert.Equal(t, "rebuild now", value)
+	assert.Equal(t, 1, priority)
+	assert.False(t, heap.Contains("reindex"))
+	assert.True(t, heap.Push("reindex", "rebuild", 6))
+
+	assert.Equal(t, []int{5, 6, 7}, heap.DrainPriorities())
+	assert.Empty(t, heap.index)
+	_, _, _, err = heap.PopKey()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestKeyedHeap_GetOrPush(t *testing.T) {
+	heap := NewKeyedHeap[string, string, int](4, lt, DaryHeapConfig{})
+	value, priority, loaded := heap.GetOrPush("retry-7", "send email", 3)
+	assert.False(t, loaded)
+	assert.Equal(t, "send email", value)
+	assert.Equal(t, 3, priority)
+
+	value, priority, loaded = heap.GetOrPush("retry-7", "send email again", 1)
+	assert.True(t, loaded)
+	assert.Equal(t, "send email", value)
+	assert.Equal(t, 3, priority)
+
+	assert.False(t, heap.PushIfAbsent("retry-7", "send email again", 1))
+	assert.True(t, heap.PushIfAbsent("retry-8", "send sms", 2))
+	assert.Equal(t, 2, heap.Length())
+	require.NoError(t, heap.Verify())
+	assert.Equal(t, []string{"send sms", "send email"}, heap.DrainValues())
+}
+
+func TestKeyedHeap_UpdatePriorityAndRemove(t *testing.T) {
+	heap := NewKeyedHeap[int, string, int](2, lt, DaryHeapConfig{})
+	for i, p := range []int{4, 7, 1, 9, 3, 6, 2} {
+		heap.Push(i, string(rune('a'+i)), p)
+	}
+
+	require.NoError(t, heap.UpdatePriority(3, 0))
+	value, err := heap.PeekValue()
+	require.NoError(t, err)
+	assert.Equal(t, "d", value)
+
+	value, priority, err := heap.Remove(1)
+	require.NoError(t, err)
+	assert.Equal(t, "b", value)
+	assert.Equal(t, 7, priority)
+	require.NoError(t, heap.Verify())
+
+	_, _, err = heap.Remove(1)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	assert.ErrorIs(t, heap.UpdatePriority(1, 0), ErrNodeNotFound)
+	_, err = heap.GetPriority(1)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	var notFound *KeyNotFoundError[int]
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, 1, notFound.Key)
+
+	priority, err = heap.GetPriority(3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, priority)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 6}, heap.DrainPriorities())
+}
+
+func TestKeyedHeap_RandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	heap := NewKeyedHeap[int, int, int](3, lt, DaryHeapConfig{})
+	want := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(100)
+		switch rng.Intn(4) {
+		case 0, 1:
+			p := rng.Intn(1000)
+			_, existed := want[key]
+			assert.Equal(t, !existed, heap.Push(key, key, p))
+			want[key] = p
+		case 2:
+			p := rng.Intn(1000)
+			err := heap.UpdatePriority(key, p)
+			if _, exists := want[key]; exists {
+				require.NoError(t, err)
+				want[key] = p
+			} else {
+				assert.ErrorIs(t, err, ErrNodeNotFound)
+			}
+		case 3:
+			_, p, err := heap.Remove(key)
+			if wp, exists := want[key]; exists {
+				require.NoError(t, err)
+				assert.Equal(t, wp, p)
+				delete(want, key)
+			} else {
+				assert.ErrorIs(t, err, ErrNodeNotFound)
+			}
+		}
+		require.NoError(t, heap.Verify())
+	}
+
+	priorities := make([]int, 0, len(want))
+	for _, p := range want {
+		priorities = append(priorities, p)
+	}
+	slices.Sort(priorities)
+	assert.Equal(t, priorities, heap.DrainPriorities())
+}
+
+func TestKeyedHeap_ClearExport(t *testing.T) {
+	heap := NewKeyedHeap[string, int, int](2, gt, DaryHeapConfig{})
+	heap.Push("a", 1, 1)
+	heap.Push("b", 2, 5)
+	heap.Push("c", 3, 3)
+
+	exported := heap.Export(ExportOptions[int, int]{})
+	assert.Equal(t, []int{5, 3, 1}, nodePriorities(exported))
+	assert.Equal(t, 3, heap.Length())
+
+	heap.Clear()
+	assert.True(t, heap.IsEmpty())
+	assert.False(t, heap.Contains("a"))
+	require.NoError(t, heap.Verify())
+}
+
+func TestSyncKeyedHeap_ConcurrentUpserts(t *testing.T) {
+	const workers, keys = 8, 50
+	heap := NewSyncKeyedHeap[int, int, int](4, lt, DaryHeapConfig{})
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for k := 0; k < keys; k++ {
+				heap.Push(k, w, w*keys+k)
+				if k%5 == 0 {
+					heap.UpdatePriority(k, -k)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Equal(t, keys, heap.Length())
+	require.NoError(t, heap.Verify())
+	for k := 0; k < keys; k++ {
+		assert.True(t, heap.Contains(k))
+	}
+	assert.True(t, slices.IsSorted(heap.DrainPriorities()))
+}
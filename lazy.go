@@ -0,0 +1,352 @@
+package heapcraft
+
+import "context"
+
+// lazyEntry is an element of the array underlying a LazyHeap. It refers to
+// the element's state by ID and is a tombstone once that state has moved on
+// to a newer version or been removed.
+type lazyEntry struct {
+	id      string
+	version uint64
+}
+
+// lazyItem is the current state of a live element of a LazyHeap, together
+// with the version of the only entry that still refers to it.
+type lazyItem[V any, P any] struct {
+	value    V
+	priority P
+	version  uint64
+}
+
+// LazyHeap is a tracked heap that removes and reprioritizes elements lazily.
+// Remove marks the element's entry as deleted instead of restructuring the
+// heap, and UpdatePriority pushes a fresh entry and marks the old one, so both
+// cost no more than a push. Pop and Peek skip the deleted entries, called
+// tombstones, that reach the root. Tombstones are compacted away in O(n) as
+// soon as they outnumber the live elements, which bounds memory at twice what
+// the live elements need and keeps every operation O(log n) amortized; Compact
+// and Maintain compact them earlier. This suits cancel-heavy workloads such as
+// schedulers, where structural removal dominates. The heap is not safe for
+// concurrent use; use SyncLazyHeap for that.
+type LazyHeap[V any, P any] struct {
+	heap          *DaryHeap[lazyEntry, P]
+	items         map[string]*lazyItem[V, P]
+	stale         int
+	version       uint64
+	idGen         IDGenerator
+	onValueUpdate listeners[ValueUpdateEvent[V]]
+}
+
+// isStale reports whether entry is a tombstone.
+func (h *LazyHeap[V, P]) isStale(entry lazyEntry) bool {
+	item, exists := h.items[entry.id]
+	return !exists || item.version != entry.version
+}
+
+// push adds an entry for the element with the given ID and state.
+func (h *LazyHeap[V, P]) push(id string, item *lazyItem[V, P]) {
+	h.version++
+	item.version = h.version
+	h.items[id] = item
+	h.heap.Push(lazyEntry{id: id, version: item.version}, item.priority)
+}
+
+// bury records a new tombstone and compacts the heap if tombstones outnumber
+// live elements.
+func (h *LazyHeap[V, P]) bury() {
+	h.stale++
+	if h.stale > len(h.items) {
+		h.Compact()
+	}
+}
+
+// Push inserts a new element with the given value and priority and returns
+// its ID. Returns ErrIDGenerationFailed if the generated ID is already in use.
+func (h *LazyHeap[V, P]) Push(value V, priority P) (string, error) {
+	id := h.idGen.Next()
+	if _, exists := h.items[id]; exists {
+		return "", ErrIDGenerationFailed
+	}
+	h.push(id, &lazyItem[V, P]{value: value, priority: priority})
+	return id, nil
+}
+
+// get is an internal method that returns the value and priority of the live
+// element with the given ID.
+func (h *LazyHeap[V, P]) get(id string) (V, P, error) {
+	item, exists := h.items[id]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &NodeNotFoundError{ID: id}
+	}
+	return item.value, item.priority, nil
+}
+
+// Get returns the value and priority of the element with the given ID.
+// Returns zero values and an error if the ID does not exist.
+func (h *LazyHeap[V, P]) Get(id string) (V, P, error) { return h.get(id) }
+
+// GetValue returns the value of the element with the given ID. Returns zero
+// value and an error if the ID does not exist.
+func (h *LazyHeap[V, P]) GetValue(id string) (V, error) {
+	return valueFromNode(h.get(id))
+}
+
+// GetPriority returns the priority of the element with the given ID. Returns
+// zero value and an error if the ID does not exist.
+func (h *LazyHeap[V, P]) GetPriority(id string) (P, error) {
+	return priorityFromNode(h.get(id))
+}
+
+// UpdateValue replaces the value of the element with the given ID in O(1),
+// since the value is not part of the heap order. Returns an error if the ID
+// does not exist.
+func (h *LazyHeap[V, P]) UpdateValue(id string, value V) error {
+	item, exists := h.items[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	old := item.value
+	item.value = value
+	h.onValueUpdate.emit(ValueUpdateEvent[V]{ID: id, Old: old, New: value})
+	return nil
+}
+
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the element ID and its old and new values. Returns an ID
+// that can be passed to RemoveListener.
+func (h *LazyHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	return h.onValueUpdate.register(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (h *LazyHeap[V, P]) RemoveListener(id string) error {
+	return h.onValueUpdate.deregister(id)
+}
+
+// UpdatePriority changes the priority of the element with the given ID by
+// pushing a new entry for it and leaving the old one as a tombstone. Returns
+// an error if the ID does not exist.
+func (h *LazyHeap[V, P]) UpdatePriority(id string, priority P) error {
+	item, exists := h.items[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	item.priority = priority
+	h.push(id, item)
+	h.bury()
+	return nil
+}
+
+// UpdatePriorityFunc replaces the priority of the element with the given ID
+// with fn applied to its current priority. Returns an error if the ID does not
+// exist, in which case fn is not called.
+func (h *LazyHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	return updatePriorityFunc(h.GetPriority, h.UpdatePriority, id, fn)
+}
+
+// Remove deletes the element with the given ID by leaving its entry as a
+// tombstone, and returns its value and priority. Returns an error if the ID
+// does not exist.
+func (h *LazyHeap[V, P]) Remove(id string) (V, P, error) {
+	item, exists := h.items[id]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &NodeNotFoundError{ID: id}
+	}
+	delete(h.items, id)
+	h.bury()
+	return item.value, item.priority, nil
+}
+
+// Stale returns the number of tombstones waiting to be compacted.
+func (h *LazyHeap[V, P]) Stale() int { return h.stale }
+
+// Compact removes every tombstone and rebuilds the heap from the live
+// entries in O(n). Returns the number of tombstones removed.
+func (h *LazyHeap[V, P]) Compact() int {
+	if h.stale == 0 {
+		return 0
+	}
+	data := h.heap.data
+	kept := data[:0]
+	for _, node := range data {
+		if !h.isStale(node.value) {
+			kept = append(kept, node)
+		}
+	}
+	clear(data[len(kept):])
+	h.heap.data = kept
+	for i := (len(kept) - 2) / h.heap.d; i >= 0; i-- {
+		h.heap.siftDown(i)
+	}
+	removed := h.stale
+	h.stale = 0
+	return removed
+}
+
+// Maintain compacts the heap, allowing it to be registered with a
+// Maintenance registry so that tombstones are removed in the background.
+// Returns the context error if ctx is already done.
+func (h *LazyHeap[V, P]) Maintain(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	h.Compact()
+	return nil
+}
+
+// Clear removes all elements and tombstones from the heap.
+func (h *LazyHeap[V, P]) Clear() {
+	h.heap.Clear()
+	clear(h.items)
+	h.stale = 0
+}
+
+// Length returns the number of live elements in the heap. Tombstones are not
+// counted.
+func (h *LazyHeap[V, P]) Length() int { return len(h.items) }
+
+// IsEmpty returns true if the heap contains no live elements.
+func (h *LazyHeap[V, P]) IsEmpty() bool { return len(h.items) == 0 }
+
+// Verify checks the internal consistency of the underlying d-ary heap and
+// that every live element has exactly one entry and every other entry is
+// counted as a tombstone. It is intended for tests and debugging and returns
+// an error wrapping ErrInvariantViolated for the first violation.
+func (h *LazyHeap[V, P]) Verify() error {
+	if err := h.heap.Verify(); err != nil {
+		return err
+	}
+	live := 0
+	for _, node := range h.heap.data {
+		if !h.isStale(node.value) {
+			live++
+		}
+	}
+	if live != len(h.items) {
+		return invariantError("%d live entries for %d elements", live, len(h.items))
+	}
+	if stale := h.heap.Length() - live; stale != h.stale {
+		return invariantError("%d tombstones counted as %d", stale, h.stale)
+	}
+	return nil
+}
+
+// skipStale removes tombstones from the root until the root is live or the
+// heap is empty.
+func (h *LazyHeap[V, P]) skipStale() {
+	for h.heap.Length() > 0 && h.isStale(h.heap.data[0].value) {
+		h.heap.Pop()
+		h.stale--
+	}
+}
+
+// peek is an internal method that skips tombstones at the root and returns
+// the first live element without removing it.
+func (h *LazyHeap[V, P]) peek() (V, P, error) {
+	h.skipStale()
+	entry, priority, err := h.heap.Peek()
+	if err != nil {
+		v, _ := zeroValuePair[V, P]()
+		return v, priority, err
+	}
+	return h.items[entry.id].value, priority, nil
+}
+
+// Peek returns the value and priority of the best live element without
+// removing it. Returns zero values and an error if the heap is empty.
+func (h *LazyHeap[V, P]) Peek() (V, P, error) { return h.peek() }
+
+// PeekValue returns the value of the best live element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *LazyHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(h.peek())
+}
+
+// PeekPriority returns the priority of the best live element without removing
+// it. Returns zero value and an error if the heap is empty.
+func (h *LazyHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(h.peek())
+}
+
+// popRoot removes and returns the root element, which must be live.
+func (h *LazyHeap[V, P]) popRoot() (V, P, error) {
+	entry, priority, err := h.heap.Pop()
+	if err != nil {
+		v, _ := zeroValuePair[V, P]()
+		return v, priority, err
+	}
+	item := h.items[entry.id]
+	delete(h.items, entry.id)
+	return item.value, priority, nil
+}
+
+// pop is an internal method that skips tombstones at the root and removes and
+// returns the first live element.
+func (h *LazyHeap[V, P]) pop() (V, P, error) {
+	h.skipStale()
+	return h.popRoot()
+}
+
+// Pop removes and returns the value and priority of the best live element.
+// Returns zero values and an error if the heap is empty.
+func (h *LazyHeap[V, P]) Pop() (V, P, error) { return h.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (h *LazyHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(h.Peek, h.Pop, pred)
+}
+
+// PopValue removes and returns the value of the best live element. Returns
+// zero value and an error if the heap is empty.
+func (h *LazyHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(h.pop())
+}
+
+// PopPriority removes and returns the priority of the best live element.
+// Returns zero value and an error if the heap is empty.
+func (h *LazyHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(h.pop())
+}
+
+// Drain compacts the heap and removes and returns every live element in
+// priority order.
+func (h *LazyHeap[V, P]) Drain() []HeapNode[V, P] {
+	h.Compact()
+	return drainNodes(h.Length(), h.popRoot)
+}
+
+// DrainValues compacts the heap and removes and returns the values of every
+// live element in priority order.
+func (h *LazyHeap[V, P]) DrainValues() []V {
+	h.Compact()
+	return drainValues(h.Length(), h.popRoot)
+}
+
+// DrainPriorities compacts the heap and removes and returns the priorities of
+// every live element in order.
+func (h *LazyHeap[V, P]) DrainPriorities() []P {
+	h.Compact()
+	return drainPriorities(h.Length(), h.popRoot)
+}
+
+// forEach calls fn for every live element in internal order.
+func (h *LazyHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range h.heap.data {
+		if !h.isStale(node.value) {
+			fn(h.items[node.value.id].value, node.priority)
+		}
+	}
+}
+
+// Export returns a copy of the live elements of the heap according to opts.
+// Tombstones are skipped but not removed, and the heap itself is not
+// modified.
+func (h *LazyHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(h.Length(), h.forEach, h.heap.cmp, opts)
+}
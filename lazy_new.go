@@ -0,0 +1,24 @@
+package heapcraft
+
+// NewLazyHeap creates a new LazyHeap holding the given elements, which are
+// assigned IDs from the configured generator. The comparison function
+// determines the heap order (min or max). config.UsePool is ignored, since
+// the heap stores its entries inline in an array.
+func NewLazyHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *LazyHeap[V, P] {
+	heap := LazyHeap[V, P]{
+		heap:          NewDaryHeapWithConfig[lazyEntry, P](4, make([]HeapNode[lazyEntry, P], 0, len(data)), cmp, DaryHeapConfig{}),
+		items:         make(map[string]*lazyItem[V, P], len(data)),
+		idGen:         config.GetGenerator(),
+		onValueUpdate: make(listeners[ValueUpdateEvent[V]]),
+	}
+	for _, node := range data {
+		heap.Push(node.value, node.priority)
+	}
+	return &heap
+}
+
+// NewSyncLazyHeap creates a new thread-safe LazyHeap holding the given
+// elements. The comparison function determines the heap order (min or max).
+func NewSyncLazyHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SyncLazyHeap[V, P] {
+	return &SyncLazyHeap[V, P]{heap: NewLazyHeap(data, cmp, config)}
+}
@@ -0,0 +1,246 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+)
+
+// SyncLazyHeap is a thread-safe wrapper around LazyHeap. Every operation takes
+// an exclusive lock, since even Peek may remove tombstones.
+type SyncLazyHeap[V any, P any] struct {
+	heap *LazyHeap[V, P]
+	mu   sync.Mutex
+}
+
+// Push inserts a new element with the given value and priority and returns
+// its ID. Returns ErrIDGenerationFailed if the generated ID is already in use.
+func (s *SyncLazyHeap[V, P]) Push(value V, priority P) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Push(value, priority)
+}
+
+// Get returns the value and priority of the element with the given ID.
+// Returns zero values and an error if the ID does not exist.
+func (s *SyncLazyHeap[V, P]) Get(id string) (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Get(id)
+}
+
+// GetValue returns the value of the element with the given ID. Returns zero
+// value and an error if the ID does not exist.
+func (s *SyncLazyHeap[V, P]) GetValue(id string) (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.GetValue(id)
+}
+
+// GetPriority returns the priority of the element with the given ID. Returns
+// zero value and an error if the ID does not exist.
+func (s *SyncLazyHeap[V, P]) GetPriority(id string) (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.GetPriority(id)
+}
+
+// UpdateValue replaces the value of the element with the given ID in O(1),
+// since the value is not part of the heap order. Returns an error if the ID
+// does not exist.
+func (s *SyncLazyHeap[V, P]) UpdateValue(id string, value V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UpdateValue(id, value)
+}
+
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the element ID and its old and new values. Returns an ID
+// that can be passed to RemoveListener. fn runs while the heap is locked and
+// must not call back into it.
+func (s *SyncLazyHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnValueUpdate(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncLazyHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
+// UpdatePriority changes the priority of the element with the given ID by
+// pushing a new entry for it and leaving the old one as a tombstone. Returns
+// an error if the ID does not exist.
+func (s *SyncLazyHeap[V, P]) UpdatePriority(id string, priority P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UpdatePriority(id, priority)
+}
+
+// UpdatePriorityFunc replaces the priority of the element with the given ID
+// with fn applied to its current priority, reading and updating it under a
+// single lock. fn must not call back into the heap.
+func (s *SyncLazyHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UpdatePriorityFunc(id, fn)
+}
+
+// Remove deletes the element with the given ID by leaving its entry as a
+// tombstone, and returns its value and priority. Returns an error if the ID
+// does not exist.
+func (s *SyncLazyHeap[V, P]) Remove(id string) (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Remove(id)
+}
+
+// Stale returns the number of tombstones waiting to be compacted.
+func (s *SyncLazyHeap[V, P]) Stale() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Stale()
+}
+
+// Compact removes every tombstone and rebuilds the heap from the live
+// entries in O(n). Returns the number of tombstones removed.
+func (s *SyncLazyHeap[V, P]) Compact() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Compact()
+}
+
+// Maintain compacts the heap, allowing it to be registered with a
+// Maintenance registry so that tombstones are removed in the background.
+// Returns the context error if ctx is already done.
+func (s *SyncLazyHeap[V, P]) Maintain(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Maintain(ctx)
+}
+
+// Clear removes all elements and tombstones from the heap.
+func (s *SyncLazyHeap[V, P]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Clear()
+}
+
+// Length returns the number of live elements in the heap. Tombstones are not
+// counted.
+func (s *SyncLazyHeap[V, P]) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no live elements.
+func (s *SyncLazyHeap[V, P]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the underlying d-ary heap and
+// that every live element has exactly one entry and every other entry is
+// counted as a tombstone. It is intended for tests and debugging and returns
+// an error wrapping ErrInvariantViolated for the first violation.
+func (s *SyncLazyHeap[V, P]) Verify() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Verify()
+}
+
+// Peek returns the value and priority of the best live element without
+// removing it. Returns zero values and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) Peek() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Peek()
+}
+
+// PeekValue returns the value of the best live element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) PeekValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns the priority of the best live element without removing
+// it. Returns zero value and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) PeekPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekPriority()
+}
+
+// Pop removes and returns the value and priority of the best live element.
+// Returns zero values and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) Pop() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopValue removes and returns the value of the best live element. Returns
+// zero value and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) PopValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns the priority of the best live element.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncLazyHeap[V, P]) PopPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPriority()
+}
+
+// Drain compacts the heap and removes and returns every live element in
+// priority order.
+func (s *SyncLazyHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues compacts the heap and removes and returns the values of every
+// live element in priority order.
+func (s *SyncLazyHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities compacts the heap and removes and returns the priorities of
+// every live element in order.
+func (s *SyncLazyHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the live elements of the heap according to opts.
+// Tombstones are skipped but not removed, and the heap itself is not
+// modified.
+func (s *SyncLazyHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Export(opts)
+}
@@ -0,0 +1,116 @@
+package heapcraft
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyHeap_MatchesFullPairingHeap(t *testing.T) {
+	config := HeapConfig{IDGenerator: &IntegerIDGenerator{}}
+	heap := NewLazyHeap[int, int](nil, lt, config)
+	reference := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	r := rand.New(rand.NewSource(11))
+	var ids []string
+
+	for step := 0; step < 3000; step++ {
+		switch op := r.Intn(10); {
+		case op < 4 || len(ids) == 0:
+			p := r.Intn(1000)
+			id, err := heap.Push(p, p)
+			require.NoError(t, err)
+			refID, _ := reference.Push(p, p)
+			require.Equal(t, refID, id)
+			ids = append(ids, id)
+		case op < 6:
+			id := ids[r.Intn(len(ids))]
+			p := r.Intn(1000)
+			assert.Equal(t, reference.UpdatePriority(id, p), heap.UpdatePriority(id, p))
+		case op < 8:
+			i := r.Intn(len(ids))
+			_, wantPriority, wantErr := reference.Remove(ids[i])
+			_, priority, err := heap.Remove(ids[i])
+			assert.Equal(t, wantErr, err)
+			assert.Equal(t, wantPriority, priority)
+			ids = append(ids[:i], ids[i+1:]...)
+		default:
+			want, wantErr := reference.PopPriority()
+			got, err := heap.PopPriority()
+			assert.Equal(t, wantErr, err)
+			assert.Equal(t, want, got)
+		}
+		require.NoError(t, heap.Verify())
+		require.Equal(t, reference.Length(), heap.Length())
+	}
+	assert.Equal(t, reference.DrainPriorities(), heap.DrainPriorities())
+	assert.Equal(t, 0, heap.Stale())
+}
+
+func TestLazyHeap_Compaction(t *testing.T) {
+	heap := NewLazyHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	var ids []string
+	for i := 0; i < 10; i++ {
+		id, _ := heap.Push("job", i)
+		ids = append(ids, id)
+	}
+
+	for _, id := range ids[:4] {
+		_, _, err := heap.Remove(id)
+		require.NoError(t, err)
+	}
+	require.NoError(t, heap.UpdatePriority(ids[9], -1))
+	assert.Equal(t, 6, heap.Length())
+	assert.Equal(t, 5, heap.Stale())
+	_, _, err := heap.Remove(ids[0])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+
+	// The root is live, so Peek leaves the tombstones in place.
+	priority, err := heap.PeekPriority()
+	require.NoError(t, err)
+	assert.Equal(t, -1, priority)
+	assert.Equal(t, 5, heap.Stale())
+	assert.Equal(t, []HeapNode[string, int]{
+		CreateHeapNode("job", -1), CreateHeapNode("job", 4), CreateHeapNode("job", 5),
+		CreateHeapNode("job", 6), CreateHeapNode("job", 7), CreateHeapNode("job", 8),
+	}, heap.Export(ExportOptions[string, int]{}))
+
+	assert.Equal(t, 5, heap.Compact())
+	assert.Equal(t, 0, heap.Stale())
+	require.NoError(t, heap.Verify())
+
+	// Tombstones are compacted as soon as they outnumber live elements.
+	for _, id := range ids[4:8] {
+		heap.Remove(id)
+	}
+	assert.Equal(t, 2, heap.Length())
+	assert.Equal(t, 0, heap.Stale())
+	require.NoError(t, heap.Verify())
+}
+
+func TestSyncLazyHeap_UpdateValueAndMaintain(t *testing.T) {
+	heap := NewSyncLazyHeap([]HeapNode[string, int]{
+		CreateHeapNode("a", 1), CreateHeapNode("b", 2), CreateHeapNode("c", 3),
+	}, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	var events []ValueUpdateEvent[string]
+	heap.OnValueUpdate(func(event ValueUpdateEvent[string]) { events = append(events, event) })
+
+	require.NoError(t, heap.UpdateValue("0", "a2"))
+	assert.Equal(t, []ValueUpdateEvent[string]{{ID: "0", Old: "a", New: "a2"}}, events)
+	assert.ErrorIs(t, heap.UpdateValue("9", "x"), ErrNodeNotFound)
+
+	heap.Remove("1")
+	assert.Equal(t, 1, heap.Stale())
+	require.NoError(t, heap.Maintain(context.Background()))
+	assert.Equal(t, 0, heap.Stale())
+
+	value, priority, err := heap.Get("0")
+	require.NoError(t, err)
+	assert.Equal(t, "a2", value)
+	assert.Equal(t, 1, priority)
+	assert.Equal(t, []string{"a2", "c"}, heap.DrainValues())
+	_, _, err = heap.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
@@ -1,5 +1,11 @@
 package heapcraft
 
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
 // leftistQueue is a generic FIFO queue used for building heaps via pairwise merging.
 // It efficiently manages a slice of elements with a head pointer to avoid unnecessary
 // allocations when elements are removed.
@@ -82,68 +88,167 @@ func (n *leftistHeapNode[V, P]) Priority() P { return n.priority }
 // Maintains a map of node IDs to nodes for O(1) access and updates.
 // The heap property is maintained through the comparison function.
 type FullLeftistHeap[V any, P any] struct {
-	root     *leftistHeapNode[V, P]
-	cmp      func(a, b P) bool
-	size     int
-	elements map[string]*leftistHeapNode[V, P]
-	pool     pool[*leftistHeapNode[V, P]]
-	idGen    IDGenerator
+	root          *leftistHeapNode[V, P]
+	cmp           func(a, b P) bool
+	size          int
+	elements      map[string]*leftistHeapNode[V, P]
+	pool          pool[*leftistHeapNode[V, P]]
+	alarms        depthAlarms
+	stats         heapStats
+	idGen         IDGenerator
+	onValueUpdate listeners[ValueUpdateEvent[V]]
+	events        listeners[HeapEvent[V, P]]
 }
 
 // UpdateValue changes the value of the node with the given ID.
 // Returns an error if the ID doesn't exist in the heap.
 func (l *FullLeftistHeap[V, P]) UpdateValue(id string, value V) error {
-	if _, exists := l.elements[id]; !exists {
-		return ErrNodeNotFound
+	node, exists := l.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
 	}
 
-	l.elements[id].value = value
+	old := node.value
+	node.value = value
+	l.onValueUpdate.emit(ValueUpdateEvent[V]{ID: id, Old: old, New: value})
+	emitHeapEvent(l.events, EventUpdate, id, value, node.priority)
 	return nil
 }
 
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the node ID and its old and new values. Returns an ID that
+// can be passed to RemoveListener.
+func (l *FullLeftistHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	return l.onValueUpdate.register(fn)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, including each element melded in from another heap, and
+// whenever the heap is cleared. Events carry the element's ID. Returns an ID
+// that can be passed to RemoveListener.
+func (l *FullLeftistHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	return l.events.add(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID, whether it
+// was registered with OnValueUpdate or OnEvent. Returns an error if no
+// listener exists with the given ID.
+func (l *FullLeftistHeap[V, P]) RemoveListener(id string) error {
+	if err := l.events.deregister(id); err == nil {
+		return nil
+	}
+	return l.onValueUpdate.deregister(id)
+}
+
 // UpdatePriority changes the priority of the node with the given ID and
 // restructures the heap to maintain the heap property.
 // Returns an error if the ID doesn't exist in the heap.
 func (l *FullLeftistHeap[V, P]) UpdatePriority(id string, priority P) error {
-	if _, exists := l.elements[id]; !exists {
-		return ErrNodeNotFound
+	updated, exists := l.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
 	}
 
-	updated := l.elements[id]
+	l.unlink(updated)
 	updated.priority = priority
+	l.root = l.merge(updated, l.root)
+	emitHeapEvent(l.events, EventUpdate, id, updated.value, priority)
+	return nil
+}
 
-	if updated.id == l.root.id {
-		l.root = l.merge(l.root.left, l.root.right)
-		l.root.parent = nil
-	} else {
-		var new *leftistHeapNode[V, P]
-		parent := updated.parent
-		if updated.left == nil && updated.right == nil {
-			new = nil
-		} else {
-			new = l.merge(updated.left, updated.right)
-			new.parent = parent
-		}
+// UpdatePriorityFunc replaces the priority of the node with the given ID with
+// fn applied to its current priority, for adjustments such as a boost by
+// elapsed time. Returns an error if the ID does not exist in the heap, in
+// which case fn is not called.
+func (l *FullLeftistHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	return updatePriorityFunc(l.GetPriority, l.UpdatePriority, id, fn)
+}
+
+// FixID restores the heap order around the node with the given ID after its
+// priority has been changed in place, for instance through a pointer held by
+// the caller. It is equivalent to calling UpdatePriority with the node's
+// current priority. Returns an error if the ID does not exist in the heap.
+func (l *FullLeftistHeap[V, P]) FixID(id string) error {
+	node, exists := l.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	return l.UpdatePriority(id, node.priority)
+}
+
+// Remove deletes the node with the given ID from the heap and returns its
+// value and priority. The node's children are merged back into the heap in
+// its place. Returns an error if the ID doesn't exist in the heap.
+func (l *FullLeftistHeap[V, P]) Remove(id string) (V, P, error) {
+	removed, exists := l.elements[id]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &NodeNotFoundError{ID: id}
+	}
+
+	l.unlink(removed)
+	delete(l.elements, id)
+	l.size--
+	l.stats.record(OpRemove, 1, l.size)
+	l.alarms.check(l.size)
+	v, p := removed.value, removed.priority
+	l.pool.Put(removed)
+	emitHeapEvent(l.events, EventRemove, id, v, p)
+	return v, p, nil
+}
 
-		if parent.left == updated {
-			parent.left = new
-		} else {
-			parent.right = new
+// unlink removes a node from the tree, replacing it with the merge of its
+// children, and clears the node's links. The node stays in the elements map.
+func (l *FullLeftistHeap[V, P]) unlink(node *leftistHeapNode[V, P]) {
+	parent := node.parent
+	merged := l.merge(node.left, node.right)
+	if merged != nil {
+		merged.parent = parent
+	}
+
+	switch {
+	case parent == nil:
+		l.root = merged
+	case parent.left == node:
+		parent.left = merged
+	default:
+		parent.right = merged
+	}
+	node.parent, node.left, node.right = nil, nil, nil
+	node.s = 1
+
+	// Replacing a subtree may shorten the null path of its ancestors, so
+	// s-values are recomputed upwards until they stop changing.
+	for ancestor := parent; ancestor != nil; ancestor = ancestor.parent {
+		if leftistRank(ancestor.left) < leftistRank(ancestor.right) {
+			ancestor.left, ancestor.right = ancestor.right, ancestor.left
+		}
+		s := leftistRank(ancestor.right) + 1
+		if s == ancestor.s {
+			break
 		}
+		ancestor.s = s
 	}
+}
 
-	updated.parent, updated.left, updated.right = nil, nil, nil
-	l.root = l.merge(updated, l.root)
-	return nil
+// leftistRank returns the s-value (null-path length) of a tracked leftist
+// node, treating a nil node as having an s-value of zero.
+func leftistRank[V any, P any](node *leftistHeapNode[V, P]) int {
+	if node == nil {
+		return 0
+	}
+	return node.s
 }
 
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
-// original and cloned heaps.
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other.
 func (l *FullLeftistHeap[V, P]) Clone() *FullLeftistHeap[V, P] {
 	elements := make(map[string]*leftistHeapNode[V, P], len(l.elements))
+	pool := l.pool.fresh()
 	for _, node := range l.elements {
-		cloned := l.pool.Get()
+		cloned := pool.Get()
 		cloned.id = node.id
 		cloned.value = node.value
 		cloned.priority = node.priority
@@ -172,22 +277,64 @@ func (l *FullLeftistHeap[V, P]) Clone() *FullLeftistHeap[V, P] {
 		}
 	}
 
-	return &FullLeftistHeap[V, P]{
-		root:     elements[l.root.id],
-		cmp:      l.cmp,
-		size:     l.size,
-		elements: elements,
-		pool:     l.pool,
-		idGen:    l.idGen,
+	cloned := &FullLeftistHeap[V, P]{
+		cmp:           l.cmp,
+		size:          l.size,
+		elements:      elements,
+		pool:          pool,
+		alarms:        l.alarms.clone(),
+		stats:         l.stats,
+		idGen:         l.idGen,
+		onValueUpdate: l.onValueUpdate.clone(),
+		events:        l.events.clone(),
+	}
+	if l.root != nil {
+		cloned.root = elements[l.root.id]
+	}
+	return cloned
+}
+
+// CloneFresh creates a deep copy of the heap like Clone, but gives every node
+// of the copy a new ID from the heap's IDGenerator, so that several copies of
+// one template heap never share IDs. Clone keeps the original IDs. New IDs are
+// assigned in the order the elements would be popped, which makes them
+// deterministic for a deterministic generator. Returns the copy and a mapping
+// from each original ID to its new one, or ErrIDGenerationFailed if a new ID
+// collides with an original one.
+func (l *FullLeftistHeap[V, P]) CloneFresh() (*FullLeftistHeap[V, P], map[string]string, error) {
+	cloned := l.Clone()
+	elements, ids, err := reassignIDs(cloned.idGen, cloned.ordered(), cloned.elements, func(n *leftistHeapNode[V, P]) *string { return &n.id })
+	if err != nil {
+		return nil, nil, err
 	}
+	cloned.elements = elements
+	return cloned, ids, nil
 }
 
 // Clear removes all elements from the heap and resets its state.
 // The heap is ready for new insertions after clearing.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
 func (l *FullLeftistHeap[V, P]) Clear() {
+	cleared := l.Length()
+	releaseElements(l.pool, l.elements)
 	l.root = nil
 	l.size = 0
+	l.stats.record(OpClear, cleared, l.size)
+	l.alarms.check(l.size)
 	l.elements = make(map[string]*leftistHeapNode[V, P])
+	emitClearEvent(l.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (l *FullLeftistHeap[V, P]) PoolStats() PoolStats { return l.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes, the
+// nodes idle in its pool, the element map and the node IDs. Memory that values
+// and priorities point to is not counted. Summing the IDs makes it O(n).
+func (l *FullLeftistHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[FullLeftistHeap[V, P]]() + nodeMemory(l.size, l.pool) +
+		mapMemory[string, *leftistHeapNode[V, P]](len(l.elements)) + idMemory(l.elements)
 }
 
 // Length returns the current number of elements in the heap.
@@ -196,6 +343,49 @@ func (l *FullLeftistHeap[V, P]) Length() int { return l.size }
 // IsEmpty returns true if the heap contains no elements.
 func (l *FullLeftistHeap[V, P]) IsEmpty() bool { return l.size == 0 }
 
+// Verify checks the internal consistency of the heap: no node may come before
+// its parent, parent links must agree with the child links, every s-value
+// must be one more than the s-value of the node's right child and no larger
+// than that of its left child, and the tree and the ID map must both hold
+// exactly Length nodes, each tracked under its own ID. It is intended for
+// tests and debugging, runs in O(n) and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (l *FullLeftistHeap[V, P]) Verify() error {
+	visited, err := verifyTree(treeRoots(l.root),
+		func(node *leftistHeapNode[V, P], visit func(*leftistHeapNode[V, P])) {
+			if node.left != nil {
+				visit(node.left)
+			}
+			if node.right != nil {
+				visit(node.right)
+			}
+		},
+		func(node, parent *leftistHeapNode[V, P]) error {
+			if node.parent != parent {
+				return invariantError("node %q does not link to its parent", node.id)
+			}
+			if leftistRank(node.left) < leftistRank(node.right) {
+				return invariantError("node %q has a left child with a smaller s-value than its right child", node.id)
+			}
+			if node.s != leftistRank(node.right)+1 {
+				return invariantError("node %q has s-value %d instead of %d", node.id, node.s, leftistRank(node.right)+1)
+			}
+			if parent != nil {
+				if err := verifyOrder(l.cmp, node.priority, parent.priority); err != nil {
+					return err
+				}
+			}
+			return verifyTracked(l.elements, node.id, node)
+		})
+	if err != nil {
+		return err
+	}
+	if err := verifySize(visited, l.size); err != nil {
+		return err
+	}
+	return verifyElements(len(l.elements), l.size)
+}
+
 // peek is an internal method that returns the root node without removing it.
 // Returns nil and an error if the heap is empty.
 func (l *FullLeftistHeap[V, P]) peek() (V, P, error) {
@@ -207,6 +397,29 @@ func (l *FullLeftistHeap[V, P]) peek() (V, P, error) {
 	return v, p, nil
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (l *FullLeftistHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return l.alarms.register(n, l.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (l *FullLeftistHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return l.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (l *FullLeftistHeap[V, P]) Stats() HeapStats { return l.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (l *FullLeftistHeap[V, P]) Instrument(hook Instrumentation) { l.stats.hook = hook }
+
 // Peek returns the minimum element without removing it.
 // Returns nil and an error if the heap is empty.
 func (l *FullLeftistHeap[V, P]) Peek() (V, P, error) { return l.peek() }
@@ -230,7 +443,7 @@ func (l *FullLeftistHeap[V, P]) get(id string) (V, P, error) {
 		return node.value, node.priority, nil
 	}
 	v, p := zeroValuePair[V, P]()
-	return v, p, ErrNodeNotFound
+	return v, p, &NodeNotFoundError{ID: id}
 }
 
 // Get returns the element associated with the given ID.
@@ -254,6 +467,14 @@ func (l *FullLeftistHeap[V, P]) GetPriority(id string) (P, error) {
 // Returns nil and an error if the heap is empty.
 func (l *FullLeftistHeap[V, P]) Pop() (V, P, error) { return l.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (l *FullLeftistHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(l.Peek, l.Pop, pred)
+}
+
 // PopValue removes and returns just the value at the root.
 // The heap property is restored through merging the root's children.
 // Returns zero value and an error if the heap is empty.
@@ -268,6 +489,155 @@ func (l *FullLeftistHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(l.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (l *FullLeftistHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(l.Length(), l.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (l *FullLeftistHeap[V, P]) DrainValues() []V {
+	return drainValues(l.Length(), l.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (l *FullLeftistHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(l.Length(), l.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (l *FullLeftistHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range l.elements {
+		fn(node.value, node.priority)
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (l *FullLeftistHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(l.Length(), l.forEach, l.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (l *FullLeftistHeap[V, P]) ordered() iter.Seq[*leftistHeapNode[V, P]] {
+	priority := func(n *leftistHeapNode[V, P]) P { return n.priority }
+	return orderedNodes(l.cmp, treeRoots(l.root), priority, func(n *leftistHeapNode[V, P], visit func(*leftistHeapNode[V, P])) {
+		if n.left != nil {
+			visit(n.left)
+		}
+		if n.right != nil {
+			visit(n.right)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (l *FullLeftistHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(l.ordered(), func(n *leftistHeapNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "id", "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (l *FullLeftistHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, l.ordered(), func(n *leftistHeapNode[V, P]) trackedNodeJSON[V, P] {
+		return trackedNodeJSON[V, P]{ID: n.id, Value: n.value, Priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its ID, priority and s-value. Left and right links
+// are drawn solid, and parent links dashed. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (l *FullLeftistHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, treeRoots(l.root),
+		func(node *leftistHeapNode[V, P]) string {
+			return fmt.Sprintf("%s\n%v\ns=%d", node.id, node.priority, node.s)
+		},
+		func(node *leftistHeapNode[V, P], edge func(dotEdge[*leftistHeapNode[V, P]])) {
+			if node.left != nil {
+				edge(dotEdge[*leftistHeapNode[V, P]]{label: "left", to: node.left})
+			}
+			if node.right != nil {
+				edge(dotEdge[*leftistHeapNode[V, P]]{label: "right", to: node.right})
+			}
+			if node.parent != nil {
+				edge(dotEdge[*leftistHeapNode[V, P]]{label: "parent", to: node.parent, back: true})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first together with their IDs. The comparison function and
+// ID generator are not encoded.
+func (l *FullLeftistHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalTrackedHeap(l.elements, l.cmp, func(node *leftistHeapNode[V, P]) (V, P) {
+		return node.value, node.priority
+	})
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON, keeping their IDs. The heap must have been created with its
+// constructor so that it has a comparison function to restore them with. An
+// IntegerIDGenerator is advanced past the restored IDs. Returns
+// ErrDuplicateID, leaving the heap unchanged, if an ID is encoded twice.
+func (l *FullLeftistHeap[V, P]) UnmarshalJSON(data []byte) error {
+	nodes, err := unmarshalTrackedHeap[V, P](data, l.cmp)
+	if err != nil {
+		return err
+	}
+
+	l.Clear()
+	for _, node := range nodes {
+		if err := l.pushWithID(node.ID, node.Value, node.Priority); err != nil {
+			return err
+		}
+		advanceIDGenerator(l.idGen, node.ID)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap, together with their IDs, in a
+// compact binary format using encoding/gob, for persisting the heap across
+// process restarts. Elements are written in no particular order. The
+// comparison function and ID generator are not encoded.
+func (l *FullLeftistHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotTracked(l.elements, func(node *leftistHeapNode[V, P]) (V, P) {
+		return node.value, node.priority
+	}))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot, keeping their IDs. The heap must have been created with
+// its constructor so that it has a comparison function to restore them with.
+// An IntegerIDGenerator is advanced past the restored IDs.
+func (l *FullLeftistHeap[V, P]) Restore(data []byte) error {
+	if l.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, true)
+	if err != nil {
+		return err
+	}
+
+	l.Clear()
+	for i, id := range snapshot.IDs {
+		if err := l.pushWithID(id, snapshot.Values[i], snapshot.Priorities[i]); err != nil {
+			return err
+		}
+		advanceIDGenerator(l.idGen, id)
+	}
+	return nil
+}
+
 // pop is an internal method that removes the root node and returns it.
 // Handles the common logic of removing the root and merging its children.
 // Returns nil and an error if the heap is empty.
@@ -285,8 +655,11 @@ func (l *FullLeftistHeap[V, P]) pop() (V, P, error) {
 	delete(l.elements, rootNode.id)
 	rootNode.left, rootNode.right, rootNode.parent = nil, nil, nil
 	l.size--
-	v, p := rootNode.value, rootNode.priority
+	l.stats.record(OpPop, 1, l.size)
+	l.alarms.check(l.size)
+	id, v, p := rootNode.id, rootNode.value, rootNode.priority
 	l.pool.Put(rootNode)
+	emitHeapEvent(l.events, EventPop, id, v, p)
 	return v, p, nil
 }
 
@@ -306,6 +679,7 @@ func (l *FullLeftistHeap[V, P]) merge(a, b *leftistHeapNode[V, P]) *leftistHeapN
 		return l.merge(b, a)
 	}
 
+	l.stats.rebalance()
 	b.right = l.merge(b.right, a)
 	b.right.parent = b
 	if b.left == nil {
@@ -326,64 +700,172 @@ func (l *FullLeftistHeap[V, P]) merge(a, b *leftistHeapNode[V, P]) *leftistHeapN
 // and merging it with the existing tree. The new node is assigned
 // a unique ID and stored in the elements map. Returns the ID of the inserted node.
 func (l *FullLeftistHeap[V, P]) Push(value V, priority P) (string, error) {
-	newNode := l.pool.Get()
-	newNode.id = l.idGen.Next()
-	if _, exists := l.elements[newNode.id]; exists {
+	id := l.idGen.Next()
+	if err := l.pushWithID(id, value, priority); err != nil {
 		return "", ErrIDGenerationFailed
 	}
+	return id, nil
+}
 
+// pushWithID inserts a new node with the given ID into the heap. Returns
+// ErrDuplicateID if a node with the ID already exists.
+func (l *FullLeftistHeap[V, P]) pushWithID(id string, value V, priority P) error {
+	if _, exists := l.elements[id]; exists {
+		return ErrDuplicateID
+	}
+
+	newNode := l.pool.Get()
+	newNode.id = id
 	newNode.value = value
 	newNode.priority = priority
 	newNode.s = 1
 	l.root = l.merge(newNode, l.root)
 	l.elements[newNode.id] = newNode
 	l.size++
-	return newNode.id, nil
+	l.stats.record(OpPush, 1, l.size)
+	l.alarms.check(l.size)
+	emitHeapEvent(l.events, EventPush, id, value, priority)
+	return nil
 }
 
-// LeftistHeap implements a basic leftist heap without node tracking.
-// Maintains the heap property through the comparison function and
-// the leftist property through s-values.
-type LeftistHeap[V any, P any] struct {
-	root *leftistNode[V, P]
-	cmp  func(a, b P) bool
-	size int
-	pool pool[*leftistNode[V, P]]
+// PushAll inserts all of the given elements into the heap and returns their
+// IDs in the same order. The elements are built into a leftist tree of their
+// own in O(k) by merging singleton trees pairwise, which is then merged into
+// the heap once in O(log n). Returns ErrIDGenerationFailed, leaving the heap
+// unchanged, if a generated ID is not unique.
+func (l *FullLeftistHeap[V, P]) PushAll(data []HeapNode[V, P]) ([]string, error) {
+	ids, err := generateIDs(l.idGen, len(data), l.elements)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return ids, nil
+	}
+
+	queueData := make([]*leftistHeapNode[V, P], 0, len(data))
+	initQueue := leftistQueue[*leftistHeapNode[V, P]]{data: queueData, head: 0, size: 0}
+	for i := range data {
+		node := l.pool.Get()
+		node.id = ids[i]
+		node.value = data[i].value
+		node.priority = data[i].priority
+		node.s = 1
+		l.elements[node.id] = node
+		initQueue.push(node)
+	}
+
+	for initQueue.remainingElements() > 1 {
+		merged := l.merge(initQueue.pop(), initQueue.pop())
+		initQueue.push(merged)
+	}
+	l.root = l.merge(initQueue.pop(), l.root)
+	l.root.parent = nil
+	l.size += len(data)
+	l.stats.record(OpPush, len(data), l.size)
+	l.alarms.check(l.size)
+	for i := range data {
+		emitHeapEvent(l.events, EventPush, ids[i], data[i].value, data[i].priority)
+	}
+	return ids, nil
 }
 
-// cloneNode creates a deep copy of a leftist node.
-// It recursively clones the left and right children.
-func (l *LeftistHeap[V, P]) cloneNode(node *leftistNode[V, P]) *leftistNode[V, P] {
-	if node == nil {
+// Meld merges another heap into this one in O(log n) time by linking the two
+// trees, and absorbs the other heap's nodes so they remain addressable by
+// their IDs. The other heap is consumed by the operation and left empty.
+// Returns an error, leaving both heaps unchanged, if an ID exists in both
+// heaps. Both heaps are expected to share the same comparison function.
+func (l *FullLeftistHeap[V, P]) Meld(other *FullLeftistHeap[V, P]) error {
+	if other == nil || other == l {
 		return nil
 	}
+	for id := range other.elements {
+		if _, exists := l.elements[id]; exists {
+			return ErrDuplicateID
+		}
+	}
 
-	cloned := l.pool.Get()
-	cloned.value = node.value
-	cloned.priority = node.priority
-	cloned.s = node.s
-	cloned.left = l.cloneNode(node.left)
-	cloned.right = l.cloneNode(node.right)
-	return cloned
+	for id, node := range other.elements {
+		l.elements[id] = node
+	}
+	l.root = l.merge(l.root, other.root)
+	if l.root != nil {
+		l.root.parent = nil
+	}
+	l.size += other.size
+	l.stats.record(OpMeld, other.size, l.size)
+	l.alarms.check(l.size)
+	if len(l.events) > 0 {
+		for id, node := range other.elements {
+			emitHeapEvent(l.events, EventPush, id, node.value, node.priority)
+		}
+	}
+	other.root, other.elements = nil, nil
+	other.Clear()
+	return nil
+}
+
+// LeftistHeap implements a basic leftist heap without node tracking.
+// Maintains the heap property through the comparison function and
+// the leftist property through s-values.
+type LeftistHeap[V any, P any] struct {
+	root   *leftistNode[V, P]
+	cmp    func(a, b P) bool
+	size   int
+	pool   pool[*leftistNode[V, P]]
+	alarms depthAlarms
+	stats  heapStats
+	events listeners[HeapEvent[V, P]]
 }
 
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
-// original and cloned heaps.
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other. The tree is copied
+// iteratively, so cloning a degenerate heap cannot overflow the stack.
 func (l *LeftistHeap[V, P]) Clone() *LeftistHeap[V, P] {
-	return &LeftistHeap[V, P]{
-		root: l.cloneNode(l.root),
-		cmp:  l.cmp,
-		size: l.size,
-		pool: l.pool,
+	cloned := &LeftistHeap[V, P]{
+		cmp:    l.cmp,
+		size:   l.size,
+		pool:   l.pool.fresh(),
+		alarms: l.alarms.clone(),
+		stats:  l.stats,
+		events: l.events.clone(),
 	}
+	cloned.root = cloneTree(l.root, cloned.pool.Get, func(n *leftistNode[V, P]) (**leftistNode[V, P], **leftistNode[V, P]) {
+		return &n.left, &n.right
+	})
+	return cloned
 }
 
 // Clear removes all elements from the simple heap.
 // The heap is ready for new insertions after clearing.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
 func (l *LeftistHeap[V, P]) Clear() {
+	cleared := l.Length()
+	releaseTree(l.pool, treeRoots(l.root), func(n *leftistNode[V, P], visit func(*leftistNode[V, P])) {
+		if n.left != nil {
+			visit(n.left)
+		}
+		if n.right != nil {
+			visit(n.right)
+		}
+	})
 	l.root = nil
 	l.size = 0
+	l.stats.record(OpClear, cleared, l.size)
+	l.alarms.check(l.size)
+	emitClearEvent(l.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (l *LeftistHeap[V, P]) PoolStats() PoolStats { return l.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes and the
+// nodes idle in its pool. Memory that values and priorities point to, such as
+// the contents of strings, is not counted.
+func (l *LeftistHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[LeftistHeap[V, P]]() + nodeMemory(l.size, l.pool)
 }
 
 // Length returns the current number of elements in the simple heap.
@@ -392,6 +874,46 @@ func (l *LeftistHeap[V, P]) Length() int { return l.size }
 // IsEmpty returns true if the simple heap contains no elements.
 func (l *LeftistHeap[V, P]) IsEmpty() bool { return l.size == 0 }
 
+// Verify checks the internal consistency of the heap: no node may come before
+// its parent, every s-value must be one more than the s-value of the node's
+// right child and no larger than that of its left child, and the tree must
+// hold exactly Length nodes. It is intended for tests and debugging, runs in
+// O(n) and returns an error wrapping ErrInvariantViolated for the first
+// violation.
+func (l *LeftistHeap[V, P]) Verify() error {
+	rank := func(node *leftistNode[V, P]) int {
+		if node == nil {
+			return 0
+		}
+		return node.s
+	}
+	visited, err := verifyTree(treeRoots(l.root),
+		func(node *leftistNode[V, P], visit func(*leftistNode[V, P])) {
+			if node.left != nil {
+				visit(node.left)
+			}
+			if node.right != nil {
+				visit(node.right)
+			}
+		},
+		func(node, parent *leftistNode[V, P]) error {
+			if rank(node.left) < rank(node.right) {
+				return invariantError("a node has a left child with a smaller s-value than its right child")
+			}
+			if node.s != rank(node.right)+1 {
+				return invariantError("a node has s-value %d instead of %d", node.s, rank(node.right)+1)
+			}
+			if parent == nil {
+				return nil
+			}
+			return verifyOrder(l.cmp, node.priority, parent.priority)
+		})
+	if err != nil {
+		return err
+	}
+	return verifySize(visited, l.size)
+}
+
 // peek is an internal method that returns the root node without removing it.
 // Returns nil and an error if the heap is empty.
 func (l *LeftistHeap[V, P]) peek() (V, P, error) {
@@ -403,6 +925,38 @@ func (l *LeftistHeap[V, P]) peek() (V, P, error) {
 	return v, p, nil
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (l *LeftistHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return l.alarms.register(n, l.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (l *LeftistHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return l.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (l *LeftistHeap[V, P]) Stats() HeapStats { return l.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (l *LeftistHeap[V, P]) Instrument(hook Instrumentation) { l.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element melded in from another heap, and whenever the heap is
+// cleared. Returns an ID that can be passed to RemoveListener.
+func (l *LeftistHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return l.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (l *LeftistHeap[V, P]) RemoveListener(id string) error { return l.events.deregister(id) }
+
 // Peek returns the minimum element without removing it.
 // Returns nil and an error if the heap is empty.
 func (l *LeftistHeap[V, P]) Peek() (V, P, error) { return l.peek() }
@@ -432,8 +986,11 @@ func (l *LeftistHeap[V, P]) pop() (V, P, error) {
 	l.root = l.merge(l.root.right, l.root.left)
 	removed.left, removed.right = nil, nil
 	l.size--
+	l.stats.record(OpPop, 1, l.size)
+	l.alarms.check(l.size)
 	v, p := removed.value, removed.priority
 	l.pool.Put(removed)
+	emitHeapEvent(l.events, EventPop, "", v, p)
 	return v, p, nil
 }
 
@@ -442,6 +999,14 @@ func (l *LeftistHeap[V, P]) pop() (V, P, error) {
 // Returns nil and an error if the heap is empty.
 func (l *LeftistHeap[V, P]) Pop() (V, P, error) { return l.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (l *LeftistHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(l.Peek, l.Pop, pred)
+}
+
 // PopValue removes and returns just the value at the root.
 // The heap property is restored through merging the root's children.
 // Returns zero value and an error if the heap is empty.
@@ -456,6 +1021,150 @@ func (l *LeftistHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(l.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (l *LeftistHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(l.Length(), l.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (l *LeftistHeap[V, P]) DrainValues() []V {
+	return drainValues(l.Length(), l.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (l *LeftistHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(l.Length(), l.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (l *LeftistHeap[V, P]) forEach(fn func(v V, p P)) {
+	stack := make([]*leftistNode[V, P], 0)
+	if l.root != nil {
+		stack = append(stack, l.root)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(node.value, node.priority)
+		if node.left != nil {
+			stack = append(stack, node.left)
+		}
+		if node.right != nil {
+			stack = append(stack, node.right)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (l *LeftistHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(l.Length(), l.forEach, l.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (l *LeftistHeap[V, P]) ordered() iter.Seq[*leftistNode[V, P]] {
+	priority := func(n *leftistNode[V, P]) P { return n.priority }
+	return orderedNodes(l.cmp, treeRoots(l.root), priority, func(n *leftistNode[V, P], visit func(*leftistNode[V, P])) {
+		if n.left != nil {
+			visit(n.left)
+		}
+		if n.right != nil {
+			visit(n.right)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (l *LeftistHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(l.ordered(), func(n *leftistNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (l *LeftistHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, l.ordered(), func(n *leftistNode[V, P]) HeapNode[V, P] {
+		return HeapNode[V, P]{value: n.value, priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its priority and s-value and drawing its left and
+// right links. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (l *LeftistHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, treeRoots(l.root),
+		func(node *leftistNode[V, P]) string {
+			return fmt.Sprintf("%v\ns=%d", node.priority, node.s)
+		},
+		func(node *leftistNode[V, P], edge func(dotEdge[*leftistNode[V, P]])) {
+			if node.left != nil {
+				edge(dotEdge[*leftistNode[V, P]]{label: "left", to: node.left})
+			}
+			if node.right != nil {
+				edge(dotEdge[*leftistNode[V, P]]{label: "right", to: node.right})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first. The comparison function is not encoded.
+func (l *LeftistHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalHeap(l.Export(ExportOptions[V, P]{}))
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. The heap must have been created with its constructor so that
+// it has a comparison function to restore them with.
+func (l *LeftistHeap[V, P]) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalHeap[V, P](data, l.cmp)
+	if err != nil {
+		return err
+	}
+
+	l.Clear()
+	for _, node := range decoded.Nodes {
+		l.Push(node.value, node.priority)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap in a compact binary format using
+// encoding/gob, for persisting the heap across process restarts. Elements are
+// written in internal order without sorting. The comparison function is not
+// encoded.
+func (l *LeftistHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotNodes(l.Length(), l.forEach))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. The heap must have been created with its constructor so
+// that it has a comparison function to restore them with.
+func (l *LeftistHeap[V, P]) Restore(data []byte) error {
+	if l.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	l.Clear()
+	for i, value := range snapshot.Values {
+		l.Push(value, snapshot.Priorities[i])
+	}
+	return nil
+}
+
 // merge combines two leftist subheaps while maintaining the heap property
 // and leftist structure. The root of the resulting heap is the node with
 // the minimum priority according to the comparison function.
@@ -472,6 +1181,7 @@ func (l *LeftistHeap[V, P]) merge(a, b *leftistNode[V, P]) *leftistNode[V, P] {
 		return l.merge(b, a)
 	}
 
+	l.stats.rebalance()
 	b.right = l.merge(b.right, a)
 	if b.left == nil {
 		b.left = b.right
@@ -495,4 +1205,106 @@ func (l *LeftistHeap[V, P]) Push(value V, priority P) {
 	newNode.s = 1
 	l.root = l.merge(newNode, l.root)
 	l.size++
+	l.stats.record(OpPush, 1, l.size)
+	l.alarms.check(l.size)
+	emitHeapEvent(l.events, EventPush, "", value, priority)
+}
+
+// build creates a leftist tree holding the given elements in O(n) by merging
+// singleton trees pairwise through a queue until one root remains. Returns
+// nil if data is empty.
+func (l *LeftistHeap[V, P]) build(data []HeapNode[V, P]) *leftistNode[V, P] {
+	if len(data) == 0 {
+		return nil
+	}
+
+	queueData := make([]*leftistNode[V, P], 0, len(data))
+	initQueue := leftistQueue[*leftistNode[V, P]]{data: queueData, head: 0, size: 0}
+	for i := range data {
+		node := l.pool.Get()
+		node.value = data[i].value
+		node.priority = data[i].priority
+		node.s = 1
+		initQueue.push(node)
+	}
+
+	for initQueue.remainingElements() > 1 {
+		merged := l.merge(initQueue.pop(), initQueue.pop())
+		initQueue.push(merged)
+	}
+	return initQueue.pop()
+}
+
+// PushAll inserts all of the given elements into the heap. The elements are
+// built into a leftist tree of their own in O(k), which is then merged into
+// the heap once in O(log n).
+func (l *LeftistHeap[V, P]) PushAll(data []HeapNode[V, P]) {
+	l.root = l.merge(l.build(data), l.root)
+	l.size += len(data)
+	l.stats.record(OpPush, len(data), l.size)
+	l.alarms.check(l.size)
+	for i := range data {
+		emitHeapEvent(l.events, EventPush, "", data[i].value, data[i].priority)
+	}
+}
+
+// Meld merges another heap into this one in O(log n) time by linking the two
+// trees. The other heap is consumed by the operation and left empty. Both
+// heaps are expected to share the same comparison function.
+func (l *LeftistHeap[V, P]) Meld(other *LeftistHeap[V, P]) {
+	if other == nil || other == l {
+		return
+	}
+	if len(l.events) > 0 {
+		other.forEach(func(v V, p P) { emitHeapEvent(l.events, EventPush, "", v, p) })
+	}
+	l.root = l.merge(l.root, other.root)
+	l.size += other.size
+	l.stats.record(OpMeld, other.size, l.size)
+	l.alarms.check(l.size)
+	other.root = nil
+	other.Clear()
+}
+
+// replaceRoot removes the root and inserts value and priority, reusing the
+// root node for the new element so that nothing is allocated or released.
+func (l *LeftistHeap[V, P]) replaceRoot(value V, priority P) (V, P) {
+	node := l.root
+	v, p := node.value, node.priority
+	rest := l.merge(node.right, node.left)
+	node.value, node.priority = value, priority
+	node.left, node.right, node.s = nil, nil, 1
+	l.root = l.merge(node, rest)
+	return v, p
+}
+
+// PopPush removes the root element and inserts a new element in one
+// operation, which is cheaper than a Pop followed by a Push. Returns the
+// removed root element. If the heap is empty, the new element is returned
+// without being inserted.
+func (l *LeftistHeap[V, P]) PopPush(value V, priority P) (V, P) {
+	if l.size == 0 {
+		return value, priority
+	}
+	v, p := l.replaceRoot(value, priority)
+	l.stats.record(OpPop, 1, l.size)
+	l.stats.record(OpPush, 1, l.size)
+	emitHeapEvent(l.events, EventPop, "", v, p)
+	emitHeapEvent(l.events, EventPush, "", value, priority)
+	return v, p
+}
+
+// PushPop inserts a new element and removes the root element in one
+// operation. If the new element belongs at the root, it is returned directly
+// and the heap is left unchanged. Otherwise the old root element is returned.
+func (l *LeftistHeap[V, P]) PushPop(value V, priority P) (V, P) {
+	if l.size == 0 || l.cmp(priority, l.root.priority) {
+		return value, priority
+	}
+	v, p := l.replaceRoot(value, priority)
+	l.stats.record(OpPush, 1, l.size)
+	l.stats.record(OpPop, 1, l.size)
+	emitHeapEvent(l.events, EventPush, "", value, priority)
+	emitHeapEvent(l.events, EventPop, "", v, p)
+	return v, p
 }
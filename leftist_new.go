@@ -1,84 +1,71 @@
 package heapcraft
 
-import "github.com/google/uuid"
+import "golang.org/x/exp/constraints"
 
-// NewLeftistHeap constructs a leftist heap from a slice of HeapPairs.
-// Uses a queue to iteratively merge singleton nodes until one root remains.
-// The comparison function determines the heap order (min or max).
-func NewLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *LeftistHeap[V, P] {
-	pool := newPool(usePool, func() *leftistNode[V, P] {
+// NewSimpleLeftistHeap constructs a simple leftist heap from a slice of
+// HeapPairs. Uses a queue to iteratively merge singleton nodes until one root
+// remains. The comparison function determines the heap order (min or max).
+// Its nodes are allocated as config selects: from an arena if ArenaSlabSize
+// is positive, otherwise from the pool chosen by UsePool. The heap does not
+// track IDs, so IDGenerator is ignored.
+func NewSimpleLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *LeftistHeap[V, P] {
+	pool := newConfiguredPool(config, func() *leftistNode[V, P] {
 		return &leftistNode[V, P]{}
 	})
-	heap := LeftistHeap[V, P]{cmp: cmp, size: 0, pool: pool}
-	if len(data) == 0 {
-		return &heap
-	}
-
-	n := len(data)
-	queueData := make([]*leftistNode[V, P], 0, n)
-	initQueue := leftistQueue[*leftistNode[V, P]]{data: queueData, head: 0, size: 0}
+	return newLeftistHeap(data, cmp, pool)
+}
 
-	heap.size = n
+// NewLeftistHeap constructs a leftist heap from a slice of HeapPairs.
+//
+// Deprecated: use NewSimpleLeftistHeap with HeapConfig{UsePool: usePool}.
+func NewLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *LeftistHeap[V, P] {
+	return NewSimpleLeftistHeap(data, cmp, HeapConfig{UsePool: usePool})
+}
 
-	for i := range data {
-		node := pool.Get()
-		node.value = data[i].value
-		node.priority = data[i].priority
-		node.s = 1
-		initQueue.push(node)
-	}
+// NewArenaLeftistHeap constructs a leftist heap whose nodes are allocated from
+// slabs of slabSize nodes (DefaultArenaSlabSize if not positive) and released
+// all at once by Clear. It suits heaps that are built once and drained, where
+// per-node allocation dominates and sync.Pool cannot help.
+func NewArenaLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, slabSize int) *LeftistHeap[V, P] {
+	return newLeftistHeap(data, cmp, newArenaPool[leftistNode[V, P]](slabSize))
+}
 
-	for initQueue.remainingElements() > 1 {
-		merged := heap.merge(initQueue.pop(), initQueue.pop())
-		initQueue.push(merged)
+// newLeftistHeap constructs a leftist heap from data that allocates its nodes
+// from pool.
+func newLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, pool pool[*leftistNode[V, P]]) *LeftistHeap[V, P] {
+	heap := LeftistHeap[V, P]{cmp: cmp, size: 0, pool: pool}
+	if len(data) == 0 {
+		return &heap
 	}
 
-	heap.root = initQueue.pop()
+	heap.size = len(data)
+	heap.root = heap.build(data)
 	return &heap
 }
 
-// NewLeftistHeap constructs a leftist heap with node tracking from a slice of HeapPairs.
-// Each node is assigned a unique ID and stored in a map for O(1) access.
+// NewFullLeftistHeap constructs a leftist heap with node tracking from a slice of HeapPairs.
+// Each node is assigned a unique ID from the configured generator and stored in
+// a map for O(1) access.
 // Uses a queue to iteratively merge singleton nodes until one root remains.
 // The comparison function determines the heap order (min or max).
 func NewFullLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *FullLeftistHeap[V, P] {
-	pool := newPool(config.UsePool, func() *leftistHeapNode[V, P] {
+	pool := newConfiguredPool(config, func() *leftistHeapNode[V, P] {
 		return &leftistHeapNode[V, P]{}
 	})
-	elements := make(map[string]*leftistHeapNode[V, P])
+	elements := make(map[string]*leftistHeapNode[V, P], config.sizeHint(len(data)))
 	heap := FullLeftistHeap[V, P]{
-		cmp:      cmp,
-		size:     0,
-		elements: elements,
-		pool:     pool,
-		idGen:    config.GetGenerator(),
+		cmp:           cmp,
+		size:          0,
+		elements:      elements,
+		pool:          pool,
+		idGen:         config.GetGenerator(),
+		onValueUpdate: make(listeners[ValueUpdateEvent[V]]),
 	}
 	if len(data) == 0 {
 		return &heap
 	}
 
-	n := len(data)
-	queueData := make([]*leftistHeapNode[V, P], 0, n)
-	initQueue := leftistQueue[*leftistHeapNode[V, P]]{data: queueData, head: 0, size: 0}
-
-	heap.size = n
-
-	for i := range data {
-		node := pool.Get()
-		node.id = uuid.New().String()
-		node.value = data[i].value
-		node.priority = data[i].priority
-		node.s = 1
-		initQueue.push(node)
-		elements[node.id] = node
-	}
-
-	for initQueue.remainingElements() > 1 {
-		merged := heap.merge(initQueue.pop(), initQueue.pop())
-		initQueue.push(merged)
-	}
-
-	heap.root = initQueue.pop()
+	heap.PushAll(data)
 	return &heap
 }
 
@@ -91,11 +78,56 @@ func NewSyncFullLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P
 	}
 }
 
+// NewSyncSimpleLeftistHeap constructs a new thread-safe simple leftist heap
+// with its nodes allocated as config selects. See NewSimpleLeftistHeap.
+func NewSyncSimpleLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SyncLeftistHeap[V, P] {
+	return &SyncLeftistHeap[V, P]{heap: NewSimpleLeftistHeap(data, cmp, config)}
+}
+
 // NewSyncLeftistHeap constructs a new thread-safe leftist
 // heap from the given data and comparison function.
-// The resulting heap is safe for concurrent use.
+//
+// Deprecated: use NewSyncSimpleLeftistHeap with HeapConfig{UsePool: usePool}.
 func NewSyncLeftistHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncLeftistHeap[V, P] {
-	return &SyncLeftistHeap[V, P]{
-		heap: NewLeftistHeap(data, cmp, usePool),
-	}
+	return NewSyncSimpleLeftistHeap(data, cmp, HeapConfig{UsePool: usePool})
+}
+
+// NewMinSimpleLeftistHeap creates a simple leftist min-heap from data, ordered
+// by the natural ordering of P, so the smallest priority is popped first.
+func NewMinSimpleLeftistHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *LeftistHeap[V, P] {
+	return NewSimpleLeftistHeap(data, orderedLess[P], config)
+}
+
+// NewMaxSimpleLeftistHeap creates a simple leftist max-heap from data, ordered
+// by the natural ordering of P, so the largest priority is popped first.
+func NewMaxSimpleLeftistHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *LeftistHeap[V, P] {
+	return NewSimpleLeftistHeap(data, orderedGreater[P], config)
+}
+
+// NewMinLeftistHeap creates a leftist min-heap from data, ordered by the natural
+// ordering of P, so the smallest priority is popped first.
+//
+// Deprecated: use NewMinSimpleLeftistHeap with HeapConfig{UsePool: usePool}.
+func NewMinLeftistHeap[V any, P constraints.Ordered](data []HeapNode[V, P], usePool bool) *LeftistHeap[V, P] {
+	return NewMinSimpleLeftistHeap(data, HeapConfig{UsePool: usePool})
+}
+
+// NewMaxLeftistHeap creates a leftist max-heap from data, ordered by the natural
+// ordering of P, so the largest priority is popped first.
+//
+// Deprecated: use NewMaxSimpleLeftistHeap with HeapConfig{UsePool: usePool}.
+func NewMaxLeftistHeap[V any, P constraints.Ordered](data []HeapNode[V, P], usePool bool) *LeftistHeap[V, P] {
+	return NewMaxSimpleLeftistHeap(data, HeapConfig{UsePool: usePool})
+}
+
+// NewMinFullLeftistHeap creates a leftist min-heap with node tracking from data,
+// ordered by the natural ordering of P.
+func NewMinFullLeftistHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *FullLeftistHeap[V, P] {
+	return NewFullLeftistHeap(data, orderedLess[P], config)
+}
+
+// NewMaxFullLeftistHeap creates a leftist max-heap with node tracking from data,
+// ordered by the natural ordering of P.
+func NewMaxFullLeftistHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *FullLeftistHeap[V, P] {
+	return NewFullLeftistHeap(data, orderedGreater[P], config)
 }
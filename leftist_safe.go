@@ -1,6 +1,7 @@
 package heapcraft
 
 import (
+	"io"
 	"sync"
 )
 
@@ -20,6 +21,25 @@ func (s *SyncFullLeftistHeap[V, P]) Push(value V, priority P) (string, error) {
 	return s.heap.Push(value, priority)
 }
 
+// PushAll inserts all of the given elements into the heap under a single
+// write lock and returns their IDs in the same order.
+func (s *SyncFullLeftistHeap[V, P]) PushAll(data []HeapNode[V, P]) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PushAll(data)
+}
+
+// Meld merges another thread-safe heap into this one. The other heap is
+// consumed by the operation and left empty. Locks are acquired in a consistent
+// order so that two heaps melded into each other concurrently cannot deadlock.
+func (s *SyncFullLeftistHeap[V, P]) Meld(other *SyncFullLeftistHeap[V, P]) error {
+	if other == nil || other == s {
+		return nil
+	}
+	defer lockPair(&s.lock, &other.lock)()
+	return s.heap.Meld(other.heap)
+}
+
 // Pop removes and returns the minimum element from the heap.
 // It acquires a write lock.
 func (s *SyncFullLeftistHeap[V, P]) Pop() (V, P, error) {
@@ -28,6 +48,26 @@ func (s *SyncFullLeftistHeap[V, P]) Pop() (V, P, error) {
 	return s.heap.Pop()
 }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncFullLeftistHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single write
+// lock. pred must not call back into the heap.
+func (s *SyncFullLeftistHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return popWhile(s.heap.Peek, s.heap.Pop, pred)
+}
+
 // PopValue removes and returns just the value at the root.
 // It acquires a write lock.
 func (s *SyncFullLeftistHeap[V, P]) PopValue() (V, error) {
@@ -44,6 +84,88 @@ func (s *SyncFullLeftistHeap[V, P]) PopPriority() (P, error) {
 	return s.heap.PopPriority()
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncFullLeftistHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncFullLeftistHeap[V, P]) DrainValues() []V {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncFullLeftistHeap[V, P]) DrainPriorities() []P {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncFullLeftistHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, in the same format as the underlying heap. It holds a read lock
+// until the whole array has been written.
+func (s *SyncFullLeftistHeap[V, P]) StreamJSON(w io.Writer) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.StreamJSON(w)
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format while holding a read lock.
+func (s *SyncFullLeftistHeap[V, P]) WriteDOT(w io.Writer) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.WriteDOT(w)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (s *SyncFullLeftistHeap[V, P]) MarshalJSON() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (s *SyncFullLeftistHeap[V, P]) UnmarshalJSON(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (s *SyncFullLeftistHeap[V, P]) Snapshot() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (s *SyncFullLeftistHeap[V, P]) Restore(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Restore(data)
+}
+
 // Peek returns the minimum element without removing it.
 // It acquires a read lock.
 func (s *SyncFullLeftistHeap[V, P]) Peek() (V, P, error) {
@@ -76,6 +198,24 @@ func (s *SyncFullLeftistHeap[V, P]) UpdateValue(id string, value V) error {
 	return s.heap.UpdateValue(id, value)
 }
 
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the node ID and its old and new values. Listeners run while
+// the heap lock is held and must not call back into the heap. Returns an ID
+// that can be passed to RemoveListener.
+func (s *SyncFullLeftistHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.OnValueUpdate(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncFullLeftistHeap[V, P]) RemoveListener(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
 // UpdatePriority changes the priority of the node with the given ID and restructures the heap.
 // It acquires a write lock.
 func (s *SyncFullLeftistHeap[V, P]) UpdatePriority(id string, priority P) error {
@@ -84,6 +224,33 @@ func (s *SyncFullLeftistHeap[V, P]) UpdatePriority(id string, priority P) error
 	return s.heap.UpdatePriority(id, priority)
 }
 
+// UpdatePriorityFunc replaces the priority of the node with the given ID with
+// fn applied to its current priority. The read and the update happen under a
+// single write lock, so no other goroutine can change the priority in
+// between. fn must not call back into the heap.
+func (s *SyncFullLeftistHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.UpdatePriorityFunc(id, fn)
+}
+
+// FixID restores the heap order around the node with the given ID after its
+// priority has been changed in place. Returns an error if the ID does not
+// exist in the heap.
+func (s *SyncFullLeftistHeap[V, P]) FixID(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.FixID(id)
+}
+
+// Remove deletes the node with the given ID and returns its value and priority.
+// It acquires a write lock.
+func (s *SyncFullLeftistHeap[V, P]) Remove(id string) (V, P, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Remove(id)
+}
+
 // Get returns the element associated with the given ID.
 // It acquires a read lock.
 func (s *SyncFullLeftistHeap[V, P]) Get(id string) (V, P, error) {
@@ -116,6 +283,50 @@ func (s *SyncFullLeftistHeap[V, P]) Length() int {
 	return s.heap.Length()
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (s *SyncFullLeftistHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SyncFullLeftistHeap[V, P]) RemoveDepthAlarm(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncFullLeftistHeap[V, P]) Stats() HeapStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncFullLeftistHeap[V, P]) Instrument(hook Instrumentation) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncFullLeftistHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
 // IsEmpty returns true if the heap contains no elements.
 // It acquires a read lock.
 func (s *SyncFullLeftistHeap[V, P]) IsEmpty() bool {
@@ -124,6 +335,14 @@ func (s *SyncFullLeftistHeap[V, P]) IsEmpty() bool {
 	return s.heap.IsEmpty()
 }
 
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncFullLeftistHeap[V, P]) Verify() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Verify()
+}
+
 // Clear removes all elements from the heap and resets its state.
 // It acquires a write lock.
 func (s *SyncFullLeftistHeap[V, P]) Clear() {
@@ -132,6 +351,22 @@ func (s *SyncFullLeftistHeap[V, P]) Clear() {
 	s.heap.Clear()
 }
 
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncFullLeftistHeap[V, P]) PoolStats() PoolStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncFullLeftistHeap[V, P]) ApproxMemoryUsage() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
 // Clone creates a deep copy of the heap structure and nodes.
 // The returned heap is also thread-safe, but shares no data with the original.
 // It acquires a read lock.
@@ -143,6 +378,20 @@ func (s *SyncFullLeftistHeap[V, P]) Clone() *SyncFullLeftistHeap[V, P] {
 	}
 }
 
+// CloneFresh creates a thread-safe deep copy of the heap whose nodes get new
+// IDs from the heap's IDGenerator, and returns it with a mapping from each
+// original ID to its new one. It acquires a write lock, since the generator is
+// shared with the original heap.
+func (s *SyncFullLeftistHeap[V, P]) CloneFresh() (*SyncFullLeftistHeap[V, P], map[string]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	cloned, ids, err := s.heap.CloneFresh()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SyncFullLeftistHeap[V, P]{heap: cloned}, ids, nil
+}
+
 // SyncLeftistHeap is a thread-safe wrapper around LeftistHeap.
 // All operations are protected by a sync.RWMutex, making it safe for concurrent use.
 type SyncLeftistHeap[V any, P any] struct {
@@ -159,6 +408,25 @@ func (s *SyncLeftistHeap[V, P]) Push(value V, priority P) {
 	s.heap.Push(value, priority)
 }
 
+// PushAll inserts all of the given elements into the heap under a single
+// write lock.
+func (s *SyncLeftistHeap[V, P]) PushAll(data []HeapNode[V, P]) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.PushAll(data)
+}
+
+// Meld merges another thread-safe heap into this one. The other heap is
+// consumed by the operation and left empty. Locks are acquired in a consistent
+// order so that two heaps melded into each other concurrently cannot deadlock.
+func (s *SyncLeftistHeap[V, P]) Meld(other *SyncLeftistHeap[V, P]) {
+	if other == nil || other == s {
+		return
+	}
+	defer lockPair(&s.lock, &other.lock)()
+	s.heap.Meld(other.heap)
+}
+
 // Pop removes and returns the minimum element from the simple heap.
 // The heap property is restored through merging the root's children.
 // It acquires a write lock.
@@ -168,6 +436,44 @@ func (s *SyncLeftistHeap[V, P]) Pop() (V, P, error) {
 	return s.heap.Pop()
 }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncLeftistHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single write
+// lock. pred must not call back into the heap.
+func (s *SyncLeftistHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return popWhile(s.heap.Peek, s.heap.Pop, pred)
+}
+
+// PopPush removes the root element and inserts a new element in one
+// operation under a single write lock. Returns the removed root element. If
+// the heap is empty, the new element is returned without being inserted.
+func (s *SyncLeftistHeap[V, P]) PopPush(value V, priority P) (V, P) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopPush(value, priority)
+}
+
+// PushPop inserts a new element and removes the root element in one
+// operation under a single write lock. If the new element belongs at the
+// root, it is returned directly. Otherwise the old root element is returned.
+func (s *SyncLeftistHeap[V, P]) PushPop(value V, priority P) (V, P) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PushPop(value, priority)
+}
+
 // PopValue removes and returns just the value at the root.
 // The heap property is restored through merging the root's children.
 // It acquires a write lock.
@@ -186,6 +492,88 @@ func (s *SyncLeftistHeap[V, P]) PopPriority() (P, error) {
 	return s.heap.PopPriority()
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncLeftistHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncLeftistHeap[V, P]) DrainValues() []V {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncLeftistHeap[V, P]) DrainPriorities() []P {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncLeftistHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, in the same format as the underlying heap. It holds a read lock
+// until the whole array has been written.
+func (s *SyncLeftistHeap[V, P]) StreamJSON(w io.Writer) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.StreamJSON(w)
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format while holding a read lock.
+func (s *SyncLeftistHeap[V, P]) WriteDOT(w io.Writer) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.WriteDOT(w)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (s *SyncLeftistHeap[V, P]) MarshalJSON() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (s *SyncLeftistHeap[V, P]) UnmarshalJSON(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (s *SyncLeftistHeap[V, P]) Snapshot() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (s *SyncLeftistHeap[V, P]) Restore(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.Restore(data)
+}
+
 // Peek returns the minimum element without removing it.
 // It acquires a read lock.
 func (s *SyncLeftistHeap[V, P]) Peek() (V, P, error) {
@@ -218,6 +606,58 @@ func (s *SyncLeftistHeap[V, P]) Length() int {
 	return s.heap.Length()
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (s *SyncLeftistHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SyncLeftistHeap[V, P]) RemoveDepthAlarm(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncLeftistHeap[V, P]) Stats() HeapStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncLeftistHeap[V, P]) Instrument(hook Instrumentation) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncLeftistHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncLeftistHeap[V, P]) RemoveListener(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
 // IsEmpty returns true if the simple heap contains no elements.
 // It acquires a read lock.
 func (s *SyncLeftistHeap[V, P]) IsEmpty() bool {
@@ -226,6 +666,14 @@ func (s *SyncLeftistHeap[V, P]) IsEmpty() bool {
 	return s.heap.IsEmpty()
 }
 
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncLeftistHeap[V, P]) Verify() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Verify()
+}
+
 // Clear removes all elements from the simple heap.
 // The heap is ready for new insertions after clearing.
 // It acquires a write lock.
@@ -235,6 +683,22 @@ func (s *SyncLeftistHeap[V, P]) Clear() {
 	s.heap.Clear()
 }
 
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncLeftistHeap[V, P]) PoolStats() PoolStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncLeftistHeap[V, P]) ApproxMemoryUsage() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
 // Clone creates a deep copy of the heap structure and nodes.
 // The returned heap is also thread-safe, but shares no data with the original.
 // It acquires a read lock.
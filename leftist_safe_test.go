@@ -115,7 +115,7 @@ func TestSyncFullLeftistHeap_EmptyOperations(t *testing.T) {
 
 	// Test Get on non-existent ID
 	_, _, err = heap.Get("nonexistent")
-	assert.Equal(t, ErrNodeNotFound, err)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
 }
 
 func TestSyncLeftistHeap_BasicOperations(t *testing.T) {
@@ -249,3 +249,33 @@ func TestSyncLeftistHeap_PriorityOrder(t *testing.T) {
 
 	assert.True(t, heap.IsEmpty())
 }
+
+func TestSyncLeftistHeap_ConcurrentMeld(t *testing.T) {
+	h1 := NewSyncLeftistHeap[int](nil, lt, false)
+	h2 := NewSyncLeftistHeap[int](nil, lt, false)
+	h3 := NewSyncFullLeftistHeap[int](nil, lt, HeapConfig{})
+	h4 := NewSyncFullLeftistHeap[int](nil, lt, HeapConfig{})
+	for i := 0; i < 10; i++ {
+		h1.Push(i, i)
+		h2.Push(i+10, i+10)
+		h3.Push(i, i)
+		h4.Push(i+10, i+10)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		h1.Meld(h2)
+		assert.Nil(t, h3.Meld(h4))
+	}()
+	go func() {
+		defer wg.Done()
+		h2.Meld(h1)
+		assert.Nil(t, h4.Meld(h3))
+	}()
+	wg.Wait()
+
+	assert.Equal(t, 20, h1.Length()+h2.Length())
+	assert.Equal(t, 20, h3.Length()+h4.Length())
+}
@@ -399,3 +399,100 @@ func BenchmarkLeftistHeap_Deletion(b *testing.B) {
 		heap.Pop()
 	}
 }
+
+func TestLeftistHeap_Meld(t *testing.T) {
+	h1 := NewLeftistHeap([]HeapNode[int, int]{
+		CreateHeapNode(5, 5),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(9, 9),
+	}, lt, false)
+	h2 := NewLeftistHeap([]HeapNode[int, int]{
+		CreateHeapNode(4, 4),
+		CreateHeapNode(0, 0),
+		CreateHeapNode(7, 7),
+	}, lt, false)
+
+	h1.Meld(h2)
+	assert.Equal(t, 6, h1.Length())
+	assert.True(t, h2.IsEmpty())
+	assert.Equal(t, []int{0, 1, 4, 5, 7, 9}, h1.DrainValues())
+
+	h1.Meld(h1)
+	h1.Meld(nil)
+	assert.True(t, h1.IsEmpty())
+}
+
+func TestFullLeftistHeap_Meld(t *testing.T) {
+	gen := &IntegerIDGenerator{NextID: 0}
+	h1 := NewFullLeftistHeap[int, int](nil, lt, HeapConfig{IDGenerator: gen})
+	h2 := NewFullLeftistHeap[int, int](nil, lt, HeapConfig{IDGenerator: gen})
+	h1.Push(5, 5)
+	id1, _ := h1.Push(3, 3)
+	h2.Push(4, 4)
+	id2, _ := h2.Push(8, 8)
+
+	assert.Nil(t, h1.Meld(h2))
+	assert.Equal(t, 4, h1.Length())
+	assert.True(t, h2.IsEmpty())
+
+	// Nodes from the other heap remain addressable by ID.
+	assert.Nil(t, h1.UpdatePriority(id2, 1))
+	value, _ := h1.PeekValue()
+	assert.Equal(t, 8, value)
+	assert.Nil(t, h1.UpdatePriority(id1, 0))
+	assert.Equal(t, []int{3, 8, 4, 5}, h1.DrainValues())
+
+	// Melding heaps with overlapping IDs fails and leaves both untouched.
+	h3 := NewFullLeftistHeap[int, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	h4 := NewFullLeftistHeap[int, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	h3.Push(1, 1)
+	h4.Push(2, 2)
+	assert.ErrorIs(t, h3.Meld(h4), ErrDuplicateID)
+	assert.Equal(t, 1, h3.Length())
+	assert.Equal(t, 1, h4.Length())
+}
+
+func TestFullLeftistHeap_Remove(t *testing.T) {
+	gen := &IntegerIDGenerator{NextID: 0}
+	h := NewFullLeftistHeap[int, int](nil, lt, HeapConfig{IDGenerator: gen})
+	ids := make(map[int]string)
+	for _, v := range []int{5, 3, 8, 1, 9, 2, 7} {
+		id, _ := h.Push(v, v)
+		ids[v] = id
+	}
+
+	// Remove the root, a middle node and a leaf.
+	for _, v := range []int{1, 5, 9} {
+		value, priority, err := h.Remove(ids[v])
+		assert.Nil(t, err)
+		assert.Equal(t, v, value)
+		assert.Equal(t, v, priority)
+	}
+	assert.Equal(t, 4, h.Length())
+
+	_, _, err := h.Get(ids[5])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	_, _, err = h.Remove(ids[5])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	assert.Equal(t, []int{2, 3, 7, 8}, h.DrainValues())
+
+	// Updating and removing the only node in the heap.
+	id, _ := h.Push(4, 4)
+	assert.Nil(t, h.UpdatePriority(id, 6))
+	priority, _ := h.PeekPriority()
+	assert.Equal(t, 6, priority)
+	_, _, err = h.Remove(id)
+	assert.Nil(t, err)
+	assert.True(t, h.IsEmpty())
+}
+
+func TestMinMaxLeftistHeap(t *testing.T) {
+	data := []HeapNode[string, int]{
+		CreateHeapNode("b", 2), CreateHeapNode("a", 1), CreateHeapNode("c", 3),
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, NewMinLeftistHeap(data, false).DrainValues())
+	assert.Equal(t, []string{"c", "b", "a"}, NewMaxLeftistHeap(data, false).DrainValues())
+	assert.Equal(t, []string{"a", "b", "c"}, NewMinFullLeftistHeap(data, HeapConfig{}).DrainValues())
+	assert.Equal(t, []string{"c", "b", "a"}, NewMaxFullLeftistHeap(data, HeapConfig{}).DrainValues())
+}
@@ -0,0 +1,123 @@
+package heapcraft
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// MaintenanceTask performs a unit of deferred upkeep on a heap, such as
+// rebalancing radix buckets or releasing unused capacity. Tasks should return
+// promptly once ctx is done.
+type MaintenanceTask func(ctx context.Context) error
+
+// Maintainer is implemented by heaps that have upkeep which can be performed
+// ahead of time instead of inline on the next Pop or Push.
+type Maintainer interface {
+	Maintain(ctx context.Context) error
+}
+
+// Scheduler runs a function at some later point in time. It is the injection
+// point for cron jobs, tickers or worker pools that should drive heap
+// maintenance instead of the caller of Pop.
+type Scheduler interface {
+	Schedule(fn func())
+}
+
+// SchedulerFunc adapts an ordinary function to the Scheduler interface.
+type SchedulerFunc func(fn func())
+
+// Schedule calls f(fn).
+func (f SchedulerFunc) Schedule(fn func()) { f(fn) }
+
+// maintenanceEntry stores a registered task with its unique ID.
+type maintenanceEntry struct {
+	id   string
+	task MaintenanceTask
+}
+
+// Maintenance is a thread-safe registry of maintenance tasks. Tasks are run in
+// registration order either explicitly through Maintain or by an injected
+// Scheduler through ScheduleOn. When tasks run on a different goroutine than
+// the one using the heap, the Sync variant of the heap must be used.
+type Maintenance struct {
+	tasks []maintenanceEntry
+	lock  sync.Mutex
+}
+
+// NewMaintenance creates an empty maintenance registry.
+func NewMaintenance() *Maintenance {
+	return &Maintenance{tasks: make([]maintenanceEntry, 0)}
+}
+
+// Register adds a task to the registry and returns its unique ID, which can be
+// used to deregister the task later.
+func (m *Maintenance) Register(task MaintenanceTask) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	id := registrationIDs.Next()
+	m.tasks = append(m.tasks, maintenanceEntry{id: id, task: task})
+	return id
+}
+
+// RegisterHeap registers the Maintain method of the given heap as a task and
+// returns its unique ID.
+func (m *Maintenance) RegisterHeap(heap Maintainer) string {
+	return m.Register(heap.Maintain)
+}
+
+// Deregister removes the task with the specified ID, returning an error if it
+// does not exist.
+func (m *Maintenance) Deregister(id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i, entry := range m.tasks {
+		if entry.id == id {
+			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+			return nil
+		}
+	}
+	return ErrTaskNotFound
+}
+
+// Count returns the number of registered tasks.
+func (m *Maintenance) Count() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.tasks)
+}
+
+// Maintain performs all registered maintenance tasks in registration order.
+// Every task is attempted even if an earlier one fails; the returned error
+// joins all task errors. If ctx is done before all tasks have run, the
+// remaining tasks are skipped and the context error is included.
+func (m *Maintenance) Maintain(ctx context.Context) error {
+	m.lock.Lock()
+	tasks := make([]maintenanceEntry, len(m.tasks))
+	copy(tasks, m.tasks)
+	m.lock.Unlock()
+
+	var errs []error
+	for _, entry := range tasks {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := entry.task(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ScheduleOn hands a single maintenance pass to the given scheduler. The pass
+// runs with ctx, and any resulting error is passed to onError when it is not
+// nil. Schedulers that repeat (such as cron jobs) may invoke the function
+// multiple times.
+func (m *Maintenance) ScheduleOn(ctx context.Context, scheduler Scheduler, onError func(error)) {
+	scheduler.Schedule(func() {
+		if err := m.Maintain(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package heapcraft
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenance_RegisterDeregister(t *testing.T) {
+	m := NewMaintenance()
+	calls := make([]int, 0)
+	id1 := m.Register(func(ctx context.Context) error {
+		calls = append(calls, 1)
+		return nil
+	})
+	m.Register(func(ctx context.Context) error {
+		calls = append(calls, 2)
+		return nil
+	})
+	assert.Equal(t, 2, m.Count())
+
+	require.NoError(t, m.Maintain(context.Background()))
+	assert.Equal(t, []int{1, 2}, calls)
+
+	require.NoError(t, m.Deregister(id1))
+	assert.ErrorIs(t, m.Deregister(id1), ErrTaskNotFound)
+	assert.Equal(t, 1, m.Count())
+}
+
+func TestMaintenance_ErrorsAndContext(t *testing.T) {
+	m := NewMaintenance()
+	errTask := errors.New("task failed")
+	ran := 0
+	m.Register(func(ctx context.Context) error { return errTask })
+	m.Register(func(ctx context.Context) error {
+		ran++
+		return nil
+	})
+
+	err := m.Maintain(context.Background())
+	assert.ErrorIs(t, err, errTask)
+	assert.Equal(t, 1, ran)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = m.Maintain(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, ran)
+}
+
+func TestMaintenance_ScheduleOnRadix(t *testing.T) {
+	heap := NewSyncRadixHeap([]HeapNode[int, uint]{
+		CreateHeapNode(1, uint(1)),
+		CreateHeapNode(5, uint(5)),
+		CreateHeapNode(9, uint(9)),
+	}, false)
+	heap.PopValue()
+	assert.Equal(t, 0, len(heap.heap.buckets[0]))
+
+	var scheduled []func()
+	scheduler := SchedulerFunc(func(fn func()) { scheduled = append(scheduled, fn) })
+
+	m := NewMaintenance()
+	m.RegisterHeap(heap)
+	m.ScheduleOn(context.Background(), scheduler, func(err error) { t.Fatal(err) })
+	require.Len(t, scheduled, 1)
+
+	scheduled[0]()
+	assert.Equal(t, 1, len(heap.heap.buckets[0]))
+	value, err := heap.PopValue()
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+}
+
+func TestDaryHeap_Maintain(t *testing.T) {
+	heap := NewBinaryHeap[int, int](nil, lt, false)
+	for i := 0; i < 64; i++ {
+		heap.Push(i, i)
+	}
+	for i := 0; i < 60; i++ {
+		heap.Pop()
+	}
+	assert.Greater(t, cap(heap.data), 2*heap.Length())
+
+	require.NoError(t, heap.Maintain(context.Background()))
+	assert.Equal(t, heap.Length(), cap(heap.data))
+	value, _ := heap.PeekValue()
+	assert.Equal(t, 60, value)
+}
+
+func TestHeap_CompactAndAutoShrink(t *testing.T) {
+	heap := NewSyncDaryHeap[int, int](4, nil, lt, false)
+	shrinking := NewDaryHeapWithConfig[int, int](4, nil, lt, DaryHeapConfig{AutoShrink: true})
+	for i := 0; i < 10000; i++ {
+		heap.Push(i, i)
+		shrinking.Push(i, i)
+	}
+	for i := 0; i < 9990; i++ {
+		heap.Pop()
+		shrinking.Pop()
+		require.LessOrEqual(t, cap(shrinking.data), max(minShrinkCapacity, shrinkRatio*shrinking.Length()))
+	}
+	require.NoError(t, shrinking.Verify())
+	assert.Greater(t, cap(heap.heap.data), 1000)
+
+	heap.Compact()
+	assert.Equal(t, 10, cap(heap.heap.data))
+	assert.Equal(t, []int{9990, 9991, 9992, 9993, 9994, 9995, 9996, 9997, 9998, 9999}, heap.DrainValues())
+	heap.Compact()
+	assert.Nil(t, heap.heap.data)
+
+	radix := NewSyncRadixHeap[int, uint](nil, false)
+	shrinkingRadix := NewRadixHeapWithConfig[int, uint](nil, RadixHeapConfig{AutoShrink: true})
+	for i := uint(0); i < 1000; i++ {
+		require.NoError(t, radix.Push(int(i), i/100))
+		require.NoError(t, shrinkingRadix.Push(int(i), i/100))
+	}
+	for i := 0; i < 100; i++ {
+		radix.Pop()
+		shrinkingRadix.Pop()
+	}
+	assert.NotZero(t, cap(radix.heap.buckets[0]))
+	assert.Nil(t, shrinkingRadix.buckets[0])
+	require.NoError(t, shrinkingRadix.Push(1000, 5))
+	assert.True(t, shrinkingRadix.Clone().shrink)
+
+	radix.Compact()
+	for _, bucket := range radix.heap.buckets {
+		assert.Equal(t, len(bucket), cap(bucket))
+	}
+	require.NoError(t, radix.Verify())
+	assert.Equal(t, 900, radix.Length())
+}
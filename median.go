@@ -0,0 +1,67 @@
+package heapcraft
+
+import "golang.org/x/exp/constraints"
+
+// RunningMedian maintains the median of a stream of values in O(log n) per
+// Add and O(1) per query. It keeps the smaller half of the values in a max-heap
+// and the larger half in a min-heap, with the lower half holding at most one
+// more value than the upper half, so the middle values are always at the two
+// roots. A RunningMedian is not safe for concurrent use.
+type RunningMedian[P constraints.Ordered] struct {
+	lower *DaryHeap[struct{}, P]
+	upper *DaryHeap[struct{}, P]
+}
+
+// Add inserts a value and rebalances the halves so that their sizes differ by
+// at most one.
+func (m *RunningMedian[P]) Add(p P) {
+	if m.lower.IsEmpty() || p <= m.lower.data[0].priority {
+		m.lower.Push(struct{}{}, p)
+	} else {
+		m.upper.Push(struct{}{}, p)
+	}
+
+	switch {
+	case m.lower.Length() > m.upper.Length()+1:
+		_, moved, _ := m.lower.Pop()
+		m.upper.Push(struct{}{}, moved)
+	case m.upper.Length() > m.lower.Length():
+		_, moved, _ := m.upper.Pop()
+		m.lower.Push(struct{}{}, moved)
+	}
+}
+
+// Median returns the median of the values added so far. For an even number of
+// values it returns the lower of the two middle values, since an average is
+// not defined for every ordered type; use Middle to get both. Returns zero
+// value and an error if no values have been added.
+func (m *RunningMedian[P]) Median() (P, error) {
+	return m.lower.PeekPriority()
+}
+
+// Middle returns the two middle values of the values added so far, which are
+// the same value when their count is odd. Numeric callers can average them to
+// get the conventional median of an even count. Returns zero values and an
+// error if no values have been added.
+func (m *RunningMedian[P]) Middle() (low P, high P, err error) {
+	low, err = m.lower.PeekPriority()
+	if err != nil {
+		return low, high, err
+	}
+	if m.upper.Length() < m.lower.Length() {
+		return low, low, nil
+	}
+	return low, m.upper.data[0].priority, nil
+}
+
+// Length returns the number of values added so far.
+func (m *RunningMedian[P]) Length() int { return m.lower.Length() + m.upper.Length() }
+
+// IsEmpty returns true if no values have been added.
+func (m *RunningMedian[P]) IsEmpty() bool { return m.lower.IsEmpty() }
+
+// Clear removes all values.
+func (m *RunningMedian[P]) Clear() {
+	m.lower.Clear()
+	m.upper.Clear()
+}
@@ -0,0 +1,11 @@
+package heapcraft
+
+import "golang.org/x/exp/constraints"
+
+// NewRunningMedian creates an empty RunningMedian backed by two binary heaps.
+func NewRunningMedian[P constraints.Ordered]() *RunningMedian[P] {
+	return &RunningMedian[P]{
+		lower: NewMaxBinaryHeap[struct{}, P](nil, DaryHeapConfig{}),
+		upper: NewMinBinaryHeap[struct{}, P](nil, DaryHeapConfig{}),
+	}
+}
@@ -0,0 +1,65 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunningMedian(t *testing.T) {
+	median := NewRunningMedian[int]()
+	_, err := median.Median()
+	assert.Equal(t, ErrHeapEmpty, err)
+	_, _, err = median.Middle()
+	assert.Equal(t, ErrHeapEmpty, err)
+
+	median.Add(5)
+	m, err := median.Median()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, m)
+
+	median.Add(1)
+	low, high, err := median.Middle()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, low)
+	assert.Equal(t, 5, high)
+
+	median.Add(3)
+	low, high, _ = median.Middle()
+	assert.Equal(t, 3, low)
+	assert.Equal(t, 3, high)
+
+	median.Clear()
+	assert.True(t, median.IsEmpty())
+}
+
+func TestRunningMedian_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	median := NewRunningMedian[float64]()
+	var seen []float64
+	for range 500 {
+		v := float64(r.Intn(200))
+		median.Add(v)
+		seen = append(seen, v)
+
+		sorted := slices.Sorted(slices.Values(seen))
+		low, high, err := median.Middle()
+		require.NoError(t, err)
+		require.Equal(t, sorted[(len(sorted)-1)/2], low)
+		require.Equal(t, sorted[len(sorted)/2], high)
+	}
+	assert.Equal(t, 500, median.Length())
+}
+
+func TestRunningMedian_Strings(t *testing.T) {
+	median := NewRunningMedian[string]()
+	for _, s := range []string{"pear", "apple", "fig", "kiwi"} {
+		median.Add(s)
+	}
+	m, err := median.Median()
+	assert.NoError(t, err)
+	assert.Equal(t, "fig", m)
+}
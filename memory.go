@@ -0,0 +1,40 @@
+package heapcraft
+
+import "unsafe"
+
+// sizeOf returns the size in bytes of a value of type T, not counting any
+// memory it points to.
+func sizeOf[T any]() int {
+	var zero T
+	return int(unsafe.Sizeof(zero))
+}
+
+// nodeMemory returns the bytes held by n nodes of type N in use by a heap and
+// by the nodes idle in its pool.
+func nodeMemory[N any](n int, p pool[*N]) int {
+	return (n + p.idle()) * sizeOf[N]()
+}
+
+// mapMemory approximates the bytes held by a map of n entries of type
+// map[K]E. Go maps keep their slots in groups of eight, each with a control
+// byte, and grow to keep at most seven in eight slots full, doubling in size.
+func mapMemory[K comparable, E any](n int) int {
+	if n == 0 {
+		return 0
+	}
+	slots := 8
+	for slots*7/8 < n {
+		slots *= 2
+	}
+	return slots * (sizeOf[K]() + sizeOf[E]() + 1)
+}
+
+// idMemory returns the bytes held by the string IDs that key elements, which
+// are shared between the map and the nodes. It is O(n).
+func idMemory[N any](elements map[string]N) int {
+	total := 0
+	for id := range elements {
+		total += len(id)
+	}
+	return total
+}
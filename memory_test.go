@@ -0,0 +1,102 @@
+package heapcraft
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapMemory(t *testing.T) {
+	assert.Equal(t, 0, mapMemory[int, int](0))
+	assert.Equal(t, 8*17, mapMemory[int, int](7))
+	assert.Equal(t, 16*17, mapMemory[int, int](8))
+	assert.GreaterOrEqual(t, mapMemory[string, int](1000), 1000*(16+8))
+}
+
+func TestApproxMemoryUsage_Dary(t *testing.T) {
+	heap := NewDaryHeap[int, int](4, make([]HeapNode[int, int], 0, 100), lt, false)
+	empty := heap.ApproxMemoryUsage()
+	assert.Equal(t, sizeOf[DaryHeap[int, int]]()+100*24, empty)
+	for i := 0; i < 100; i++ {
+		heap.Push(i, i)
+	}
+	assert.Equal(t, empty, heap.ApproxMemoryUsage())
+
+	// Taking a handle adds the handle array.
+	_, err := heap.HandleAt(0)
+	require.NoError(t, err)
+	assert.Greater(t, heap.ApproxMemoryUsage(), empty)
+}
+
+func TestApproxMemoryUsage_CountsPooledNodes(t *testing.T) {
+	heap := NewPairingHeap[int, int](nil, lt, true)
+	for i := 0; i < 100; i++ {
+		heap.Push(i, i)
+	}
+	full := heap.ApproxMemoryUsage()
+	assert.Equal(t, sizeOf[PairingHeap[int, int]]()+100*sizeOf[pairingNode[int, int]](), full)
+
+	// Popped nodes move to the pool, so they are still counted.
+	heap.DrainValues()
+	assert.Equal(t, full, heap.ApproxMemoryUsage())
+
+	unpooled := NewPairingHeap[int, int](nil, lt, false)
+	unpooled.Push(1, 1)
+	unpooled.Pop()
+	assert.Equal(t, sizeOf[PairingHeap[int, int]](), unpooled.ApproxMemoryUsage())
+}
+
+func TestApproxMemoryUsage_GrowsWithElements(t *testing.T) {
+	const n = 1000
+	config := HeapConfig{}
+	interval, err := NewIntervalHeap[int, int](nil, HeapConfig{})
+	require.NoError(t, err)
+	heaps := map[string]struct {
+		heap MemoryReporter
+		push func(i int)
+	}{}
+	add := func(name string, heap MemoryReporter, push func(i int)) {
+		heaps[name] = struct {
+			heap MemoryReporter
+			push func(i int)
+		}{heap, push}
+	}
+	for name, heap := range map[string]Heap[int, int]{
+		"dary":         NewDaryHeap[int, int](2, nil, lt, false),
+		"pairing":      NewPairingHeap[int, int](nil, lt, false),
+		"leftist":      NewLeftistHeap[int, int](nil, lt, false),
+		"skew":         NewSkewHeap[int, int](nil, lt, false),
+		"binomial":     NewBinomialHeap[int, int](nil, lt, HeapConfig{}),
+		"skewBinomial": NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{}),
+		"syncDary":     NewSyncDaryHeap[int, int](2, nil, lt, false),
+	} {
+		add(name, heap.(MemoryReporter), func(i int) { heap.Push(i, i) })
+	}
+	for name, heap := range map[string]TrackedHeap[int, int]{
+		"fullPairing": NewFullPairingHeap[int, int](nil, lt, config),
+		"fullLeftist": NewFullLeftistHeap[int, int](nil, lt, config),
+		"fullSkew":    NewSyncFullSkewHeap[int, int](nil, lt, config),
+	} {
+		add(name, heap.(MemoryReporter), func(i int) { heap.Push(i, i) })
+	}
+	radix := NewRadixHeap[int, uint](nil, false)
+	add("radix", radix, func(i int) { radix.Push(i, uint(i)) })
+	multi := NewMultiLevelRadixHeap[int, uint](nil, 4, RadixHeapConfig{})
+	add("multiRadix", multi, func(i int) { multi.Push(i, uint(i)) })
+	indexed := NewIndexedDaryHeap[int, int](4, lt, config)
+	add("indexed", indexed, func(i int) { indexed.Push(i, i) })
+	keyed := NewSyncKeyedHeap[string, int, int](4, lt, DaryHeapConfig{})
+	add("keyed", keyed, func(i int) { keyed.Push(strconv.Itoa(i), i, i) })
+	add("interval", interval, func(i int) { interval.Push(i, i, i+1) })
+
+	for name, tc := range heaps {
+		empty := tc.heap.ApproxMemoryUsage()
+		for i := 0; i < n; i++ {
+			tc.push(i)
+		}
+		// Every element holds at least an int value and an int priority.
+		assert.GreaterOrEqual(t, tc.heap.ApproxMemoryUsage()-empty, n*16, name)
+	}
+}
@@ -0,0 +1,84 @@
+package heapcraft
+
+import "iter"
+
+// mergeHead is the next unmerged element of one input to MergeSorted,
+// together with the index of the input it came from.
+type mergeHead[T any] struct {
+	item   T
+	source int
+}
+
+// MergeSorted merges sequences that are each sorted according to cmp into a
+// single sorted sequence. It keeps the head of every input in a binary heap,
+// so producing each element costs O(log k) for k inputs, and it reads each
+// input lazily: elements are pulled only as the merged sequence is consumed.
+// Elements that compare equal are yielded in the order of the inputs they
+// come from. Inputs are pulled with iter.Pull and stopped when iteration
+// ends, including when it ends early.
+func MergeSorted[T any](cmp func(a, b T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			nexts[i] = next
+		}
+		mergeHeads(cmp, nexts, yield)
+	}
+}
+
+// MergeSortedSlices merges slices that are each sorted according to cmp into
+// a single sorted sequence, like MergeSorted. The slices are read in place
+// and must not be modified while the sequence is being consumed.
+func MergeSortedSlices[T any](cmp func(a, b T) bool, data ...[]T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), len(data))
+		for i, s := range data {
+			nexts[i] = func() (T, bool) {
+				if len(s) == 0 {
+					var empty T
+					return empty, false
+				}
+				item := s[0]
+				s = s[1:]
+				return item, true
+			}
+		}
+		mergeHeads(cmp, nexts, yield)
+	}
+}
+
+// mergeHeads yields the elements produced by nexts in merged order. The heap
+// holds one head per input that is not yet exhausted: the root is yielded and
+// then replaced by the next element of the same input, or popped once that
+// input runs out.
+func mergeHeads[T any](cmp func(a, b T) bool, nexts []func() (T, bool), yield func(T) bool) {
+	before := func(a, b mergeHead[T]) bool {
+		if cmp(a.item, b.item) {
+			return true
+		}
+		return !cmp(b.item, a.item) && a.source < b.source
+	}
+
+	heads := make([]HeapNode[struct{}, mergeHead[T]], 0, len(nexts))
+	for i, next := range nexts {
+		if item, ok := next(); ok {
+			heads = append(heads, HeapNode[struct{}, mergeHead[T]]{priority: mergeHead[T]{item: item, source: i}})
+		}
+	}
+	heap := NewBinaryHeapWithConfig(heads, before, DaryHeapConfig{})
+
+	for !heap.IsEmpty() {
+		head := heap.data[0].priority
+		if !yield(head.item) {
+			return
+		}
+		if item, ok := nexts[head.source](); ok {
+			heap.data[0].priority = mergeHead[T]{item: item, source: head.source}
+			heap.siftDown(0)
+		} else {
+			heap.Pop()
+		}
+	}
+}
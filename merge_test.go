@@ -0,0 +1,73 @@
+package heapcraft
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSortedSlices(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	inputs := make([][]int, 7)
+	var want []int
+	for i := range inputs {
+		inputs[i] = make([]int, r.Intn(50))
+		for j := range inputs[i] {
+			inputs[i][j] = r.Intn(100)
+		}
+		slices.Sort(inputs[i])
+		want = append(want, inputs[i]...)
+	}
+	inputs = append(inputs, nil)
+	slices.Sort(want)
+
+	assert.Equal(t, want, slices.Collect(MergeSortedSlices(lt, inputs...)))
+	assert.Empty(t, slices.Collect(MergeSortedSlices[int](lt)))
+}
+
+func TestMergeSorted(t *testing.T) {
+	merged := MergeSorted(gt, slices.Values([]int{9, 4, 1}), slices.Values([]int{8, 4, 2}), slices.Values([]int{}))
+	assert.Equal(t, []int{9, 8, 4, 4, 2, 1}, slices.Collect(merged))
+	// The sequence can be iterated again.
+	assert.Equal(t, []int{9, 8, 4, 4, 2, 1}, slices.Collect(merged))
+}
+
+func TestMergeSorted_Stable(t *testing.T) {
+	type entry struct {
+		key    int
+		source string
+	}
+	byKey := func(a, b entry) bool { return a.key < b.key }
+	merged := MergeSortedSlices(byKey,
+		[]entry{{1, "a"}, {2, "a"}},
+		[]entry{{1, "b"}, {2, "b"}},
+	)
+	assert.Equal(t, []entry{{1, "a"}, {1, "b"}, {2, "a"}, {2, "b"}}, slices.Collect(merged))
+}
+
+func TestMergeSorted_EarlyStop(t *testing.T) {
+	stopped := 0
+	counting := func(values ...int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			defer func() { stopped++ }()
+			for _, v := range values {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+
+	var got []int
+	for v := range MergeSorted(lt, counting(1, 3, 5), counting(2, 4, 6)) {
+		got = append(got, v)
+		if len(got) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.Equal(t, 2, stopped)
+}
@@ -0,0 +1,206 @@
+package heapcraft
+
+import "math/bits"
+
+// MinMaxHeap is a double-ended priority queue stored in a single array. Nodes
+// on even levels, starting with the root, come before all of their
+// descendants according to the comparison function, and nodes on odd levels
+// come after all of theirs, so the first element is the root and the last is
+// one of its children. Push, PopMin and PopMax run in O(log n) and PeekMin and
+// PeekMax in O(1). The heap is not safe for concurrent use; use
+// SyncMinMaxHeap for that.
+type MinMaxHeap[V any, P any] struct {
+	data []HeapNode[V, P]
+	cmp  func(a, b P) bool
+}
+
+// isMinLevel reports whether the node at index i is on an even level, whose
+// nodes come before their descendants.
+func isMinLevel(i int) bool { return bits.Len(uint(i+1))%2 == 1 }
+
+// ordered reports whether the node at index i belongs before the node at
+// index j in the direction of a min level if min is true, or of a max level
+// otherwise.
+func (h *MinMaxHeap[V, P]) ordered(i, j int, min bool) bool {
+	if min {
+		return h.cmp(h.data[i].priority, h.data[j].priority)
+	}
+	return h.cmp(h.data[j].priority, h.data[i].priority)
+}
+
+// swap exchanges the nodes at indices i and j.
+func (h *MinMaxHeap[V, P]) swap(i, j int) { h.data[i], h.data[j] = h.data[j], h.data[i] }
+
+// bubbleUp moves the node at index i towards the root until the min-max
+// property holds.
+func (h *MinMaxHeap[V, P]) bubbleUp(i int) {
+	if i == 0 {
+		return
+	}
+	min := isMinLevel(i)
+	parent := (i - 1) / 2
+	if h.ordered(parent, i, min) {
+		h.swap(i, parent)
+		i, min = parent, !min
+	}
+
+	// Beyond the first step a node only moves between levels of its own
+	// kind, so it is compared with its grandparent.
+	for i > 2 {
+		grandparent := ((i-1)/2 - 1) / 2
+		if !h.ordered(i, grandparent, min) {
+			return
+		}
+		h.swap(i, grandparent)
+		i = grandparent
+	}
+}
+
+// extreme returns the index of the child or grandchild of the node at index i
+// that comes first in the direction of i's level, or -1 if i is a leaf.
+func (h *MinMaxHeap[V, P]) extreme(i int, min bool) int {
+	best := -1
+	first := 2*i + 1
+	for _, j := range [...]int{first, first + 1, 2*first + 1, 2*first + 2, 2*first + 3, 2*first + 4} {
+		if j >= len(h.data) {
+			break
+		}
+		if best == -1 || h.ordered(j, best, min) {
+			best = j
+		}
+	}
+	return best
+}
+
+// trickleDown moves the node at index i away from the root until the min-max
+// property holds.
+func (h *MinMaxHeap[V, P]) trickleDown(i int) {
+	min := isMinLevel(i)
+	for {
+		m := h.extreme(i, min)
+		if m == -1 || !h.ordered(m, i, min) {
+			return
+		}
+		h.swap(m, i)
+		if m <= 2*i+2 {
+			return
+		}
+
+		// The node moved down to a grandchild, which must also be in order
+		// with the parent between them on the opposite kind of level.
+		if parent := (m - 1) / 2; h.ordered(parent, m, min) {
+			h.swap(m, parent)
+		}
+		i = m
+	}
+}
+
+// maxIndex returns the index of the last element, which is the root if it is
+// the only element and otherwise one of its children.
+func (h *MinMaxHeap[V, P]) maxIndex() int {
+	switch {
+	case len(h.data) == 1:
+		return 0
+	case len(h.data) == 2 || h.cmp(h.data[2].priority, h.data[1].priority):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Push inserts an element with the given value and priority in O(log n).
+func (h *MinMaxHeap[V, P]) Push(value V, priority P) {
+	h.data = append(h.data, HeapNode[V, P]{value: value, priority: priority})
+	h.bubbleUp(len(h.data) - 1)
+}
+
+// peekAt returns the value and priority of the node at index i, or an error if
+// the heap is empty.
+func (h *MinMaxHeap[V, P]) peekAt(i int) (V, P, error) {
+	if len(h.data) == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	return h.data[i].value, h.data[i].priority, nil
+}
+
+// PeekMin returns the value and priority of the element that comes first
+// according to the comparison function without removing it. Returns zero
+// values and an error if the heap is empty.
+func (h *MinMaxHeap[V, P]) PeekMin() (V, P, error) { return h.peekAt(0) }
+
+// PeekMax returns the value and priority of the element that comes last
+// according to the comparison function without removing it. Returns zero
+// values and an error if the heap is empty.
+func (h *MinMaxHeap[V, P]) PeekMax() (V, P, error) {
+	if len(h.data) == 0 {
+		return h.peekAt(0)
+	}
+	return h.peekAt(h.maxIndex())
+}
+
+// removeAt removes and returns the node at index i, replacing it with the
+// last node and restoring the min-max property below it.
+func (h *MinMaxHeap[V, P]) removeAt(i int) (V, P, error) {
+	node := h.data[i]
+	last := len(h.data) - 1
+	h.data[i] = h.data[last]
+	h.data[last] = HeapNode[V, P]{}
+	h.data = h.data[:last]
+	if i < last {
+		h.trickleDown(i)
+	}
+	return node.value, node.priority, nil
+}
+
+// PopMin removes and returns the value and priority of the element that comes
+// first according to the comparison function. Returns zero values and an
+// error if the heap is empty.
+func (h *MinMaxHeap[V, P]) PopMin() (V, P, error) {
+	if len(h.data) == 0 {
+		return h.peekAt(0)
+	}
+	return h.removeAt(0)
+}
+
+// PopMax removes and returns the value and priority of the element that comes
+// last according to the comparison function. Returns zero values and an error
+// if the heap is empty.
+func (h *MinMaxHeap[V, P]) PopMax() (V, P, error) {
+	if len(h.data) == 0 {
+		return h.peekAt(0)
+	}
+	return h.removeAt(h.maxIndex())
+}
+
+// Clear removes all elements from the heap.
+func (h *MinMaxHeap[V, P]) Clear() {
+	clear(h.data)
+	h.data = h.data[:0]
+}
+
+// Length returns the number of elements in the heap.
+func (h *MinMaxHeap[V, P]) Length() int { return len(h.data) }
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *MinMaxHeap[V, P]) IsEmpty() bool { return len(h.data) == 0 }
+
+// Verify checks that every node on an even level comes no later than its
+// children and grandchildren, and every node on an odd level no earlier. It
+// is intended for tests and debugging and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (h *MinMaxHeap[V, P]) Verify() error {
+	for i := range h.data {
+		min := isMinLevel(i)
+		first := 2*i + 1
+		for _, j := range [...]int{first, first + 1, 2*first + 1, 2*first + 2, 2*first + 3, 2*first + 4} {
+			if j >= len(h.data) {
+				break
+			}
+			if h.ordered(j, i, min) {
+				return invariantError("node %d is out of order with its descendant %d", i, j)
+			}
+		}
+	}
+	return nil
+}
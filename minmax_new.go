@@ -0,0 +1,23 @@
+package heapcraft
+
+// NewMinMaxHeap creates a MinMaxHeap from the given data in O(n). The
+// comparison function decides which end of the heap is the minimum: PopMin
+// returns the element that comes first according to cmp and PopMax the one
+// that comes last.
+func NewMinMaxHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool) *MinMaxHeap[V, P] {
+	heap := &MinMaxHeap[V, P]{
+		data: make([]HeapNode[V, P], len(data)),
+		cmp:  cmp,
+	}
+	copy(heap.data, data)
+	for i := len(heap.data)/2 - 1; i >= 0; i-- {
+		heap.trickleDown(i)
+	}
+	return heap
+}
+
+// NewSyncMinMaxHeap creates a thread-safe MinMaxHeap from the given data in
+// O(n).
+func NewSyncMinMaxHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool) *SyncMinMaxHeap[V, P] {
+	return &SyncMinMaxHeap[V, P]{heap: NewMinMaxHeap(data, cmp)}
+}
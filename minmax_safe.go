@@ -0,0 +1,78 @@
+package heapcraft
+
+import "sync"
+
+// SyncMinMaxHeap is a thread-safe wrapper around MinMaxHeap. Peeks take a
+// read lock and all other operations an exclusive lock.
+type SyncMinMaxHeap[V any, P any] struct {
+	heap *MinMaxHeap[V, P]
+	lock sync.RWMutex
+}
+
+// Push inserts an element with the given value and priority.
+func (s *SyncMinMaxHeap[V, P]) Push(value V, priority P) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.Push(value, priority)
+}
+
+// PeekMin returns the value and priority of the element that comes first
+// according to the comparison function without removing it.
+func (s *SyncMinMaxHeap[V, P]) PeekMin() (V, P, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.PeekMin()
+}
+
+// PeekMax returns the value and priority of the element that comes last
+// according to the comparison function without removing it.
+func (s *SyncMinMaxHeap[V, P]) PeekMax() (V, P, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.PeekMax()
+}
+
+// PopMin removes and returns the value and priority of the element that comes
+// first according to the comparison function.
+func (s *SyncMinMaxHeap[V, P]) PopMin() (V, P, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopMin()
+}
+
+// PopMax removes and returns the value and priority of the element that comes
+// last according to the comparison function.
+func (s *SyncMinMaxHeap[V, P]) PopMax() (V, P, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.heap.PopMax()
+}
+
+// Clear removes all elements from the heap.
+func (s *SyncMinMaxHeap[V, P]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heap.Clear()
+}
+
+// Length returns the number of elements in the heap.
+func (s *SyncMinMaxHeap[V, P]) Length() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SyncMinMaxHeap[V, P]) IsEmpty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncMinMaxHeap[V, P]) Verify() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.heap.Verify()
+}
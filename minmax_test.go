@@ -0,0 +1,78 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinMaxHeap_MatchesSortedSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	data := make([]HeapNode[int, int], 0, 50)
+	ref := make([]int, 0, 50)
+	for i := 0; i < 50; i++ {
+		p := r.Intn(100)
+		data = append(data, CreateHeapNode(p, p))
+		ref = append(ref, p)
+	}
+	heap := NewMinMaxHeap(data, lt)
+	sort.Ints(ref)
+	require.NoError(t, heap.Verify())
+
+	for step := 0; step < 3000; step++ {
+		switch op := r.Intn(3); {
+		case op == 0:
+			p := r.Intn(100)
+			heap.Push(p, p)
+			ref = append(ref, p)
+			sort.Ints(ref)
+		case len(ref) == 0:
+			_, _, err := heap.PopMin()
+			assert.ErrorIs(t, err, ErrHeapEmpty)
+			_, _, err = heap.PeekMax()
+			assert.ErrorIs(t, err, ErrHeapEmpty)
+		case op == 1:
+			_, p, err := heap.PeekMin()
+			require.NoError(t, err)
+			require.Equal(t, ref[0], p)
+			v, p, err := heap.PopMin()
+			require.NoError(t, err)
+			require.Equal(t, ref[0], p)
+			require.Equal(t, p, v)
+			ref = ref[1:]
+		default:
+			_, p, err := heap.PeekMax()
+			require.NoError(t, err)
+			require.Equal(t, ref[len(ref)-1], p)
+			_, p, err = heap.PopMax()
+			require.NoError(t, err)
+			require.Equal(t, ref[len(ref)-1], p)
+			ref = ref[:len(ref)-1]
+		}
+		require.NoError(t, heap.Verify())
+		require.Equal(t, len(ref), heap.Length())
+	}
+}
+
+func TestMinMaxHeap_MaxHeapOrder(t *testing.T) {
+	heap := NewSyncMinMaxHeap[string, int](nil, gt)
+	heap.Push("b", 2)
+	heap.Push("c", 3)
+	heap.Push("a", 1)
+
+	// With a max-heap comparison, the minimum end holds the largest priority.
+	v, _, err := heap.PopMin()
+	require.NoError(t, err)
+	assert.Equal(t, "c", v)
+	v, _, err = heap.PopMax()
+	require.NoError(t, err)
+	assert.Equal(t, "a", v)
+	assert.Equal(t, 1, heap.Length())
+
+	heap.Clear()
+	assert.True(t, heap.IsEmpty())
+	assert.NoError(t, heap.Verify())
+}
@@ -0,0 +1,167 @@
+package heapcraft
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// mpscNode is an element waiting in the inbox of an MPSCHeap.
+type mpscNode[V any, P any] struct {
+	node HeapNode[V, P]
+	next *mpscNode[V, P]
+}
+
+// MPSCHeap is a multi-producer, single-consumer priority queue. Any number of
+// goroutines may call Push concurrently: a push is a lock-free
+// compare-and-swap onto an inbox list, so producers never wait on each other
+// or on the consumer. Every other method must be called from a single
+// consumer goroutine, which moves the inbox into a private binary heap before
+// it pops or peeks. Length and IsEmpty are the exception and may be called
+// from any goroutine.
+//
+// Elements pushed concurrently become visible to the consumer in batches, so
+// elements of equal priority are popped in no particular order.
+type MPSCHeap[V any, P any] struct {
+	inbox atomic.Pointer[mpscNode[V, P]]
+	size  atomic.Int64
+	// ready holds a token whenever something may have been pushed since the
+	// consumer last emptied the inbox, so PopWait never misses a push.
+	ready chan struct{}
+	heap  *DaryHeap[V, P]
+}
+
+// Push adds an element to the heap. It is safe to call from any number of
+// goroutines and never blocks.
+func (m *MPSCHeap[V, P]) Push(value V, priority P) {
+	n := &mpscNode[V, P]{node: HeapNode[V, P]{value: value, priority: priority}}
+	// The size is raised before the element is published so that the
+	// consumer can never pop it first and drive the size below zero.
+	m.size.Add(1)
+	for {
+		head := m.inbox.Load()
+		n.next = head
+		if m.inbox.CompareAndSwap(head, n) {
+			break
+		}
+	}
+	select {
+	case m.ready <- struct{}{}:
+	default:
+	}
+}
+
+// collect moves every element waiting in the inbox into the consumer's heap.
+func (m *MPSCHeap[V, P]) collect() {
+	for n := m.inbox.Swap(nil); n != nil; n = n.next {
+		m.heap.Push(n.node.value, n.node.priority)
+	}
+}
+
+// pop is an internal method that collects the inbox and removes the root
+// element.
+func (m *MPSCHeap[V, P]) pop() (V, P, error) {
+	m.collect()
+	v, p, err := m.heap.Pop()
+	if err == nil {
+		m.size.Add(-1)
+	}
+	return v, p, err
+}
+
+// peek is an internal method that collects the inbox and returns the root
+// element without removing it.
+func (m *MPSCHeap[V, P]) peek() (V, P, error) {
+	m.collect()
+	return m.heap.Peek()
+}
+
+// Pop removes and returns the value and priority of the root element.
+// Returns zero values and an error if the heap is empty. Consumer only.
+func (m *MPSCHeap[V, P]) Pop() (V, P, error) { return m.pop() }
+
+// PopValue removes and returns the value of the root element. Returns zero
+// value and an error if the heap is empty. Consumer only.
+func (m *MPSCHeap[V, P]) PopValue() (V, error) { return valueFromNode(m.pop()) }
+
+// PopPriority removes and returns the priority of the root element. Returns
+// zero value and an error if the heap is empty. Consumer only.
+func (m *MPSCHeap[V, P]) PopPriority() (P, error) { return priorityFromNode(m.pop()) }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Consumer only.
+func (m *MPSCHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(m.peek, m.pop, pred)
+}
+
+// PopWait removes and returns the value and priority of the root element,
+// blocking until the heap is non-empty. Returns zero values and the context's
+// error if ctx is done before an element becomes available. Consumer only.
+func (m *MPSCHeap[V, P]) PopWait(ctx context.Context) (V, P, error) {
+	for {
+		if v, p, err := m.pop(); err == nil {
+			return v, p, nil
+		}
+		select {
+		case <-m.ready:
+		case <-ctx.Done():
+			v, p := zeroValuePair[V, P]()
+			return v, p, ctx.Err()
+		}
+	}
+}
+
+// Peek returns the value and priority of the root element without removing
+// it. Returns zero values and an error if the heap is empty. Consumer only.
+func (m *MPSCHeap[V, P]) Peek() (V, P, error) { return m.peek() }
+
+// PeekValue returns the value of the root element without removing it.
+// Returns zero value and an error if the heap is empty. Consumer only.
+func (m *MPSCHeap[V, P]) PeekValue() (V, error) { return valueFromNode(m.peek()) }
+
+// PeekPriority returns the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty. Consumer only.
+func (m *MPSCHeap[V, P]) PeekPriority() (P, error) { return priorityFromNode(m.peek()) }
+
+// Drain removes all elements pushed so far and returns them in priority
+// order. Consumer only.
+func (m *MPSCHeap[V, P]) Drain() []HeapNode[V, P] {
+	m.collect()
+	return drainNodes(m.heap.Length(), m.pop)
+}
+
+// DrainValues removes all elements pushed so far and returns their values in
+// priority order. Consumer only.
+func (m *MPSCHeap[V, P]) DrainValues() []V {
+	m.collect()
+	return drainValues(m.heap.Length(), m.pop)
+}
+
+// DrainPriorities removes all elements pushed so far and returns their
+// priorities in priority order. Consumer only.
+func (m *MPSCHeap[V, P]) DrainPriorities() []P {
+	m.collect()
+	return drainPriorities(m.heap.Length(), m.pop)
+}
+
+// Export returns a copy of the elements pushed so far according to opts. The
+// elements stay in the heap. Consumer only.
+func (m *MPSCHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	m.collect()
+	return m.heap.Export(opts)
+}
+
+// Clear removes all elements pushed so far. Consumer only.
+func (m *MPSCHeap[V, P]) Clear() {
+	m.collect()
+	m.size.Add(-int64(m.heap.Length()))
+	m.heap.Clear()
+}
+
+// Length returns the number of elements in the heap, including those still
+// waiting in the inbox. It may be called from any goroutine.
+func (m *MPSCHeap[V, P]) Length() int { return int(m.size.Load()) }
+
+// IsEmpty returns true if the heap contains no elements. It may be called
+// from any goroutine.
+func (m *MPSCHeap[V, P]) IsEmpty() bool { return m.Length() == 0 }
@@ -0,0 +1,10 @@
+package heapcraft
+
+// NewMPSCHeap creates an empty multi-producer, single-consumer heap. The
+// comparison function determines the heap order (min or max).
+func NewMPSCHeap[V any, P any](cmp func(a, b P) bool) *MPSCHeap[V, P] {
+	return &MPSCHeap[V, P]{
+		ready: make(chan struct{}, 1),
+		heap:  NewBinaryHeapWithConfig[V, P](nil, cmp, DaryHeapConfig{}),
+	}
+}
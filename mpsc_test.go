@@ -0,0 +1,123 @@
+package heapcraft
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPSCHeapPopOrder(t *testing.T) {
+	h := NewMPSCHeap[string](lt)
+	assert.True(t, h.IsEmpty())
+	_, _, err := h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	h.Push("c", 3)
+	h.Push("a", 1)
+	h.Push("b", 2)
+	assert.Equal(t, 3, h.Length())
+
+	v, p, err := h.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "a", v)
+	assert.Equal(t, 1, p)
+
+	h.Push("z", 0)
+	assert.Equal(t, []string{"z", "a", "b", "c"}, h.DrainValues())
+	assert.True(t, h.IsEmpty())
+}
+
+func TestMPSCHeapConcurrentProducers(t *testing.T) {
+	const producers, perProducer = 32, 200
+	h := NewMPSCHeap[int](lt)
+
+	var wg sync.WaitGroup
+	for w := 0; w < producers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				h.Push(w, w*perProducer+i)
+			}
+		}(w)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	seen := make([]int, 0, producers*perProducer)
+	for len(seen) < producers*perProducer {
+		_, p, err := h.PopWait(ctx)
+		require.NoError(t, err)
+		seen = append(seen, p)
+	}
+	wg.Wait()
+
+	assert.True(t, h.IsEmpty())
+	slices.Sort(seen)
+	for i, p := range seen {
+		require.Equal(t, i, p)
+	}
+}
+
+func TestMPSCHeapPopWait(t *testing.T) {
+	h := NewMPSCHeap[string](lt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := h.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		h.Push("late", 1)
+	}()
+	v, err := valueFromNode(h.PopWait(context.Background()))
+	require.NoError(t, err)
+	assert.Equal(t, "late", v)
+}
+
+func TestMPSCHeapClear(t *testing.T) {
+	h := NewMPSCHeap[int](gt)
+	for i := 0; i < 10; i++ {
+		h.Push(i, i)
+	}
+	p, err := h.PopPriority()
+	require.NoError(t, err)
+	assert.Equal(t, 9, p)
+
+	h.Clear()
+	assert.Equal(t, 0, h.Length())
+	h.Push(1, 1)
+	assert.Equal(t, 1, h.Length())
+}
+
+// -------------------------------- MPSC Heap Benchmarks --------------------------------
+
+func BenchmarkMPSCHeapParallelPush(b *testing.B) {
+	h := NewMPSCHeap[int](lt)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			h.Push(i, i)
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncDaryHeapParallelPush(b *testing.B) {
+	h := NewSyncDaryHeap[int](2, nil, lt, false)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			h.Push(i, i)
+			i++
+		}
+	})
+}
@@ -0,0 +1,411 @@
+package heapcraft
+
+import (
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// DefaultRadixDigitBits is the digit width used by NewMultiLevelRadixHeap when
+// the requested width is out of range. Eight-bit digits split a 64-bit
+// priority into eight levels of 256 buckets each.
+const DefaultRadixDigitBits = 8
+
+// maxRadixDigitBits caps the digit width so that a single level never holds
+// more than 65536 buckets.
+const maxRadixDigitBits = 16
+
+// MultiLevelRadixHeap implements a monotonic priority queue over unsigned
+// priorities, like RadixHeap, but buckets elements by digit rather than by
+// bit. A priority is split into digits of digitBits bits, and each element
+// sits in the bucket named by the most significant digit in which it differs
+// from 'last' together with its own value of that digit. A rebalance empties
+// the lowest occupied bucket, and every element it moves lands at least one
+// level lower, so an element is moved at most once per level instead of once
+// per bit. With 64-bit priorities such as timestamps this cuts the worst-case
+// rebalance work by a factor of digitBits.
+//   - buckets: bucket 0 holds items equal to 'last'; bucket
+//     1 + level<<digitBits + digit holds the items differing from 'last'
+//     first in the given digit level.
+//   - occupied: a bitmap of the non-empty buckets, used to find the lowest one
+//     without scanning every bucket.
+//   - size: the count of elements in the heap.
+//   - last: the most recently extracted minimum priority.
+type MultiLevelRadixHeap[V any, P constraints.Unsigned] struct {
+	buckets   [][]HeapNode[V, P]
+	occupied  []uint64
+	digitBits int
+	size      int
+	last      P
+	pool      pool[HeapNode[V, P]]
+	stats     heapStats
+	events    listeners[HeapEvent[V, P]]
+}
+
+// Clone creates a deep copy of the heap structure. The new heap preserves the
+// original size and last value. If values or priorities are reference types, those
+// reference values are shared between the original and cloned heaps. The
+// clone receives its own node pool.
+func (r *MultiLevelRadixHeap[V, P]) Clone() *MultiLevelRadixHeap[V, P] {
+	occupied := make([]uint64, len(r.occupied))
+	copy(occupied, r.occupied)
+	return &MultiLevelRadixHeap[V, P]{
+		buckets:   cloneBuckets(r.buckets),
+		occupied:  occupied,
+		digitBits: r.digitBits,
+		size:      r.size,
+		last:      r.last,
+		pool:      r.pool.fresh(),
+		stats:     r.stats,
+		events:    r.events.clone(),
+	}
+}
+
+// DigitBits returns the number of priority bits covered by each level.
+func (r *MultiLevelRadixHeap[V, P]) DigitBits() int { return r.digitBits }
+
+// bucketIndex returns the bucket a priority belongs to relative to 'last'.
+// The priority must not be less than 'last'.
+func (r *MultiLevelRadixHeap[V, P]) bucketIndex(priority P) int {
+	if priority == r.last {
+		return 0
+	}
+	level := (bits.Len64(uint64(priority^r.last)) - 1) / r.digitBits
+	digit := int(uint64(priority)>>(level*r.digitBits)) & (1<<r.digitBits - 1)
+	return 1 + level<<r.digitBits + digit
+}
+
+// insert places a node into its bucket and marks the bucket as occupied.
+func (r *MultiLevelRadixHeap[V, P]) insert(node HeapNode[V, P]) {
+	i := r.bucketIndex(node.priority)
+	r.buckets[i] = append(r.buckets[i], node)
+	r.occupied[i>>6] |= 1 << (i & 63)
+}
+
+// lowestBucket returns the index of the first non-empty bucket after bucket
+// 0, or -1 if there is none. The items in that bucket are smaller than those
+// in every later bucket.
+func (r *MultiLevelRadixHeap[V, P]) lowestBucket() int {
+	for w, word := range r.occupied {
+		if w == 0 {
+			word &^= 1
+		}
+		if word != 0 {
+			return w<<6 + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// Push adds a new value and priority pair into the heap.
+// Returns a *PriorityError if the priority is less than the last extracted
+// priority, as this would violate the monotonic property.
+func (r *MultiLevelRadixHeap[V, P]) Push(value V, priority P) error {
+	if r.size == 0 {
+		r.last = priority
+	}
+	if priority < r.last {
+		return &PriorityError[P]{Priority: priority, Last: r.last}
+	}
+	node := r.pool.Get()
+	node.value = value
+	node.priority = priority
+	r.insert(node)
+	r.size++
+	r.stats.record(OpPush, 1, r.size)
+	emitHeapEvent(r.events, EventPush, "", value, priority)
+	return nil
+}
+
+// rebalance empties the lowest occupied bucket, updates 'last' to the
+// smallest priority found there and redistributes its items, all of which
+// move to a lower level. The caller must ensure the heap is not empty and
+// bucket 0 is empty.
+func (r *MultiLevelRadixHeap[V, P]) rebalance() {
+	i := r.lowestBucket()
+	bucket := r.buckets[i]
+	r.occupied[i>>6] &^= 1 << (i & 63)
+	r.last = minFromSlice(bucket).priority
+	r.stats.rebalance()
+	for _, node := range bucket {
+		r.insert(node)
+	}
+	clear(bucket)
+	r.buckets[i] = bucket[:0]
+}
+
+// Rebalance fills bucket 0 if it is empty.
+// Returns an error if the heap is empty, or if bucket 0 already contains elements
+// (no action was needed).
+func (r *MultiLevelRadixHeap[V, P]) Rebalance() error {
+	if r.size == 0 {
+		return ErrHeapEmpty
+	}
+	if len(r.buckets[0]) == 0 {
+		r.rebalance()
+		return nil
+	}
+	return ErrNoRebalancingNeeded
+}
+
+// pop removes and returns an element from bucket 0, rebalancing first if
+// bucket 0 is empty. Returns zero values and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) pop() (V, P, error) {
+	if r.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	if len(r.buckets[0]) == 0 {
+		r.rebalance()
+	}
+
+	n := len(r.buckets[0]) - 1
+	removed := r.buckets[0][n]
+	r.buckets[0][n] = HeapNode[V, P]{}
+	r.buckets[0] = r.buckets[0][:n]
+	if n == 0 {
+		r.occupied[0] &^= 1
+	}
+	r.size--
+	r.stats.record(OpPop, 1, r.size)
+
+	v, p := removed.value, removed.priority
+	r.pool.Put(removed)
+	emitHeapEvent(r.events, EventPop, "", v, p)
+	return v, p, nil
+}
+
+// peek returns the element with the minimum priority without removing it.
+// Returns zero values and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) peek() (V, P, error) {
+	if r.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	bucket := r.buckets[0]
+	if len(bucket) == 0 {
+		bucket = r.buckets[r.lowestBucket()]
+	}
+	root := minFromSlice(bucket)
+	return root.value, root.priority, nil
+}
+
+// Pop extracts and returns the element with the minimum priority.
+// Returns zero values and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) Pop() (V, P, error) { return r.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(r.Peek, r.Pop, pred)
+}
+
+// Peek returns the element with the minimum priority without removing it.
+// Returns zero values and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) Peek() (V, P, error) { return r.peek() }
+
+// PopValue removes and returns just the value of the root element.
+// Returns zero value and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(r.pop())
+}
+
+// PopPriority removes and returns just the priority of the root element.
+// Returns zero value and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(r.pop())
+}
+
+// PopEqual removes and returns every element whose priority equals the
+// current minimum, rebalancing first if needed. All of them share bucket 0,
+// so the batch is taken in a single step. Returns nil and an error if the
+// heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) PopEqual() ([]HeapNode[V, P], error) {
+	if r.size == 0 {
+		return nil, ErrHeapEmpty
+	}
+	if len(r.buckets[0]) == 0 {
+		r.rebalance()
+	}
+	batch := r.buckets[0]
+	r.buckets[0] = nil
+	r.occupied[0] &^= 1
+	r.size -= len(batch)
+	r.stats.record(OpPop, len(batch), r.size)
+	for _, node := range batch {
+		emitHeapEvent(r.events, EventPop, "", node.value, node.priority)
+	}
+	return batch, nil
+}
+
+// Last returns the heap's floor: the most recently extracted minimum priority,
+// or the priority most recently passed to AdvanceTo. Pushes below it fail.
+func (r *MultiLevelRadixHeap[V, P]) Last() P { return r.last }
+
+// AdvanceTo moves the heap's floor forward to p, so the heap can model a
+// monotone clock. Elements with a priority below p are stale: they are
+// removed and returned in priority order. Elements at or above p stay in the
+// heap, and only the single bucket that p falls into has to be redistributed.
+// Returns a *PriorityError if p is less than Last. As with Push, the first
+// push into an empty heap sets the floor to its own priority.
+func (r *MultiLevelRadixHeap[V, P]) AdvanceTo(p P) ([]HeapNode[V, P], error) {
+	if p < r.last {
+		return nil, &PriorityError[P]{Priority: p, Last: r.last}
+	}
+	var stale []HeapNode[V, P]
+	for r.size > 0 {
+		if len(r.buckets[0]) == 0 {
+			if _, next, _ := r.peek(); next >= p {
+				break
+			}
+			r.rebalance()
+		}
+		if r.last >= p {
+			break
+		}
+		batch, _ := r.PopEqual()
+		stale = append(stale, batch...)
+	}
+	if r.size > 0 && p != r.last {
+		i := r.bucketIndex(p)
+		bucket := r.buckets[i]
+		r.buckets[i] = nil
+		r.occupied[i>>6] &^= 1 << (i & 63)
+		r.last = p
+		r.stats.rebalance()
+		for _, node := range bucket {
+			r.insert(node)
+		}
+	}
+	r.last = p
+	return stale, nil
+}
+
+// PeekValue returns just the value of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(r.peek())
+}
+
+// PeekPriority returns just the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (r *MultiLevelRadixHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(r.peek())
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (r *MultiLevelRadixHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(r.Length(), r.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (r *MultiLevelRadixHeap[V, P]) DrainValues() []V {
+	return drainValues(r.Length(), r.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (r *MultiLevelRadixHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(r.Length(), r.pop)
+}
+
+// forEach calls fn for every element in the heap, bucket by bucket.
+func (r *MultiLevelRadixHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, bucket := range r.buckets {
+		for _, node := range bucket {
+			fn(node.value, node.priority)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (r *MultiLevelRadixHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(r.Length(), r.forEach, func(a, b P) bool { return a < b }, opts)
+}
+
+// Clear reinitializes the heap by creating fresh buckets, resetting size to zero,
+// and setting 'last' back to its zero value.
+func (r *MultiLevelRadixHeap[V, P]) Clear() {
+	cleared := r.Length()
+	r.buckets = make([][]HeapNode[V, P], len(r.buckets))
+	clear(r.occupied)
+	r.size = 0
+	r.stats.record(OpClear, cleared, r.size)
+	r.last = 0
+	emitClearEvent(r.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (r *MultiLevelRadixHeap[V, P]) PoolStats() PoolStats { return r.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its buckets, counted
+// by capacity, its occupancy bitmap and the elements idle in its pool. Memory
+// that values point to is not counted.
+func (r *MultiLevelRadixHeap[V, P]) ApproxMemoryUsage() int {
+	total := sizeOf[MultiLevelRadixHeap[V, P]]() + cap(r.buckets)*sizeOf[[]HeapNode[V, P]]() + cap(r.occupied)*sizeOf[uint64]()
+	for _, bucket := range r.buckets {
+		total += cap(bucket) * sizeOf[HeapNode[V, P]]()
+	}
+	return total + r.pool.idle()*sizeOf[HeapNode[V, P]]()
+}
+
+// Stats returns the operations the heap has performed since it was created.
+// Rebalances counts the buckets emptied and redistributed by Pop, PopEqual,
+// Rebalance and AdvanceTo.
+func (r *MultiLevelRadixHeap[V, P]) Stats() HeapStats { return r.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (r *MultiLevelRadixHeap[V, P]) Instrument(hook Instrumentation) { r.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including the stale elements AdvanceTo removes, and whenever the heap is
+// cleared. Returns an ID that can be passed to RemoveListener.
+func (r *MultiLevelRadixHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	return r.events.add(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (r *MultiLevelRadixHeap[V, P]) RemoveListener(id string) error {
+	return r.events.deregister(id)
+}
+
+// Length returns the number of items currently stored in the heap.
+func (r *MultiLevelRadixHeap[V, P]) Length() int { return r.size }
+
+// IsEmpty returns true if the heap contains no items.
+func (r *MultiLevelRadixHeap[V, P]) IsEmpty() bool { return r.size == 0 }
+
+// Verify checks the internal consistency of the heap: every element must
+// have a priority no less than the last extracted priority and sit in the
+// bucket that priority maps to, the occupancy bitmap must mark exactly the
+// non-empty buckets, and the buckets must hold exactly Length elements. It is
+// intended for tests and debugging, runs in O(n) and returns an error
+// wrapping ErrInvariantViolated for the first violation.
+func (r *MultiLevelRadixHeap[V, P]) Verify() error {
+	count := 0
+	for i, bucket := range r.buckets {
+		if marked := r.occupied[i>>6]&(1<<(i&63)) != 0; marked != (len(bucket) > 0) {
+			return invariantError("bucket %d holds %d elements but is marked occupied=%t", i, len(bucket), marked)
+		}
+		for _, node := range bucket {
+			if node.priority < r.last {
+				return invariantError("priority %d is below the last extracted priority %d", node.priority, r.last)
+			}
+			if want := r.bucketIndex(node.priority); want != i {
+				return invariantError("priority %d is in bucket %d instead of bucket %d", node.priority, i, want)
+			}
+		}
+		count += len(bucket)
+	}
+	return verifySize(count, r.size)
+}
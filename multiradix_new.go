@@ -0,0 +1,52 @@
+package heapcraft
+
+import (
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// NewMultiLevelRadixHeap creates a MultiLevelRadixHeap from a given slice of
+// HeapNode[V,P], splitting priorities into digits of digitBits bits. Values of
+// digitBits below 1 or above 16 use DefaultRadixDigitBits, and values above
+// the bit-length of P are capped to it. 'last' is initialized to the minimum
+// priority if data is present, and each element is assigned to its
+// corresponding bucket. config.UsePool controls pooling of the elements;
+// AutoShrink is not supported and is ignored.
+func NewMultiLevelRadixHeap[V any, P constraints.Unsigned](data []HeapNode[V, P], digitBits int, config RadixHeapConfig) *MultiLevelRadixHeap[V, P] {
+	pool := newPool(config.UsePool, func() HeapNode[V, P] {
+		return HeapNode[V, P]{}
+	})
+	var pType P
+	width := reflect.TypeOf(pType).Bits()
+	if digitBits < 1 || digitBits > maxRadixDigitBits {
+		digitBits = DefaultRadixDigitBits
+	}
+	digitBits = min(digitBits, width)
+	levels := (width + digitBits - 1) / digitBits
+	numBuckets := 1 + levels<<digitBits
+
+	heap := &MultiLevelRadixHeap[V, P]{
+		buckets:   make([][]HeapNode[V, P], numBuckets),
+		occupied:  make([]uint64, (numBuckets+63)/64),
+		digitBits: digitBits,
+		pool:      pool,
+	}
+	if len(data) > 0 {
+		heap.last = minFromSlice(data).priority
+		heap.size = len(data)
+		for _, pair := range data {
+			node := pool.Get()
+			node.value = pair.value
+			node.priority = pair.priority
+			heap.insert(node)
+		}
+	}
+	return heap
+}
+
+// NewSyncMultiLevelRadixHeap creates a new thread-safe MultiLevelRadixHeap
+// from a given slice of HeapNode[V,P]. See NewMultiLevelRadixHeap.
+func NewSyncMultiLevelRadixHeap[V any, P constraints.Unsigned](data []HeapNode[V, P], digitBits int, config RadixHeapConfig) *SyncMultiLevelRadixHeap[V, P] {
+	return &SyncMultiLevelRadixHeap[V, P]{heap: NewMultiLevelRadixHeap(data, digitBits, config)}
+}
@@ -0,0 +1,247 @@
+package heapcraft
+
+import (
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SyncMultiLevelRadixHeap provides a thread-safe wrapper around
+// MultiLevelRadixHeap. It uses a read-write mutex to allow concurrent reads
+// and exclusive writes.
+type SyncMultiLevelRadixHeap[V any, P constraints.Unsigned] struct {
+	heap *MultiLevelRadixHeap[V, P]
+	mu   sync.RWMutex
+}
+
+// Clone creates a deep copy of the heap structure. The new heap preserves the
+// original size and last value. If values or priorities are reference types, those
+// reference values are shared between the original and cloned heaps.
+func (s *SyncMultiLevelRadixHeap[V, P]) Clone() *SyncMultiLevelRadixHeap[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &SyncMultiLevelRadixHeap[V, P]{
+		heap: s.heap.Clone(),
+	}
+}
+
+// Push adds a new value and priority pair into the heap.
+// Returns a *PriorityError if the priority is less than the last extracted
+// priority, as this would violate the monotonic property.
+func (s *SyncMultiLevelRadixHeap[V, P]) Push(value V, priority P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Push(value, priority)
+}
+
+// Pop extracts and returns the element with the minimum priority.
+// Returns zero values and an error if the heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) Pop() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pop()
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncMultiLevelRadixHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// Peek returns the element with the minimum priority without removing it.
+// Returns zero values and an error if the heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) Peek() (V, P, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Peek()
+}
+
+// PopValue removes and returns just the value of the root element.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) PopValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns just the priority of the root element.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) PopPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPriority()
+}
+
+// PopEqual removes and returns every element whose priority equals the
+// current minimum under a single write lock. Returns nil and an error if the
+// heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) PopEqual() ([]HeapNode[V, P], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopEqual()
+}
+
+// Last returns the heap's floor: the most recently extracted minimum priority,
+// or the priority most recently passed to AdvanceTo.
+func (s *SyncMultiLevelRadixHeap[V, P]) Last() P {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Last()
+}
+
+// AdvanceTo moves the heap's floor forward to p under a single write lock,
+// removing and returning the elements with a priority below p in priority
+// order. Returns a *PriorityError if p is less than Last.
+func (s *SyncMultiLevelRadixHeap[V, P]) AdvanceTo(p P) ([]HeapNode[V, P], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.AdvanceTo(p)
+}
+
+// PeekValue returns just the value of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) PeekValue() (V, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns just the priority of the root element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SyncMultiLevelRadixHeap[V, P]) PeekPriority() (P, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PeekPriority()
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncMultiLevelRadixHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncMultiLevelRadixHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncMultiLevelRadixHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncMultiLevelRadixHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// Clear reinitializes the heap by creating fresh buckets, resetting size to zero,
+// and setting 'last' back to its zero value.
+func (s *SyncMultiLevelRadixHeap[V, P]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Clear()
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncMultiLevelRadixHeap[V, P]) PoolStats() PoolStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncMultiLevelRadixHeap[V, P]) ApproxMemoryUsage() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
+// Rebalance fills bucket 0 if it is empty.
+// Returns an error if the heap is empty, or if bucket 0 already contains elements
+// (no action was needed).
+func (s *SyncMultiLevelRadixHeap[V, P]) Rebalance() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Rebalance()
+}
+
+// DigitBits returns the number of priority bits covered by each level.
+func (s *SyncMultiLevelRadixHeap[V, P]) DigitBits() int {
+	return s.heap.DigitBits()
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncMultiLevelRadixHeap[V, P]) Stats() HeapStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncMultiLevelRadixHeap[V, P]) Instrument(hook Instrumentation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncMultiLevelRadixHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncMultiLevelRadixHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
+// Length returns the number of items currently stored in the heap.
+func (s *SyncMultiLevelRadixHeap[V, P]) Length() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no items.
+func (s *SyncMultiLevelRadixHeap[V, P]) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.IsEmpty()
+}
+
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncMultiLevelRadixHeap[V, P]) Verify() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Verify()
+}
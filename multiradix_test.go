@@ -0,0 +1,228 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiLevelRadixHeapPopOrder(t *testing.T) {
+	raw := []HeapNode[string, uint]{
+		CreateHeapNode("value10", uint(10)),
+		CreateHeapNode("value3", uint(3)),
+		CreateHeapNode("value700", uint(700)),
+		CreateHeapNode("value1", uint(1)),
+		CreateHeapNode("value5", uint(5)),
+		CreateHeapNode("value2", uint(2)),
+	}
+	h := NewMultiLevelRadixHeap(raw, 4, RadixHeapConfig{})
+	assert.Equal(t, len(raw), h.Length())
+	require.NoError(t, h.Verify())
+
+	assert.Equal(t, []string{"value1", "value2", "value3", "value5", "value10", "value700"}, h.DrainValues())
+	assert.True(t, h.IsEmpty())
+	_, _, err := h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestMultiLevelRadixHeapDigitBits(t *testing.T) {
+	assert.Equal(t, DefaultRadixDigitBits, NewMultiLevelRadixHeap[int, uint64](nil, 0, RadixHeapConfig{}).DigitBits())
+	assert.Equal(t, DefaultRadixDigitBits, NewMultiLevelRadixHeap[int, uint64](nil, 64, RadixHeapConfig{}).DigitBits())
+	assert.Equal(t, 8, NewMultiLevelRadixHeap[int, uint8](nil, 12, RadixHeapConfig{}).DigitBits())
+	assert.Equal(t, 3, NewMultiLevelRadixHeap[int, uint16](nil, 3, RadixHeapConfig{}).DigitBits())
+}
+
+func TestMultiLevelRadixHeapMatchesSortedOrder(t *testing.T) {
+	for _, digitBits := range []int{1, 3, 8, 16} {
+		rng := rand.New(rand.NewSource(int64(digitBits)))
+		h := NewMultiLevelRadixHeap[int, uint64](nil, digitBits, RadixHeapConfig{UsePool: true})
+		floor := uint64(rng.Int63())
+		require.NoError(t, h.Push(-1, floor))
+		pending := []uint64{floor}
+		for i := 0; i < 2000; i++ {
+			if rng.Intn(3) > 0 || len(pending) == 1 {
+				p := floor + uint64(rng.Int63n(1<<40))
+				require.NoError(t, h.Push(i, p))
+				pending = append(pending, p)
+			} else {
+				slices.Sort(pending)
+				p, err := h.PopPriority()
+				require.NoError(t, err)
+				assert.Equal(t, pending[0], p)
+				pending = pending[1:]
+				floor = p
+			}
+			require.NoError(t, h.Verify(), "digitBits=%d", digitBits)
+		}
+		slices.Sort(pending)
+		assert.Equal(t, pending, h.DrainPriorities(), "digitBits=%d", digitBits)
+	}
+}
+
+func TestMultiLevelRadixHeapPushMonotonicity(t *testing.T) {
+	h := NewMultiLevelRadixHeap[string, uint](nil, 4, RadixHeapConfig{})
+	require.NoError(t, h.Push("a", 20))
+	require.NoError(t, h.Push("b", 30))
+	_, err := h.PopValue()
+	require.NoError(t, err)
+
+	err = h.Push("c", 10)
+	var perr *PriorityError[uint]
+	require.ErrorAs(t, err, &perr)
+	assert.Equal(t, uint(10), perr.Priority)
+	assert.Equal(t, uint(20), perr.Last)
+	assert.ErrorIs(t, err, ErrPriorityLessThanLast)
+	assert.Equal(t, 1, h.Length())
+}
+
+func TestMultiLevelRadixHeapPeekAndRebalance(t *testing.T) {
+	h := NewMultiLevelRadixHeap[string, uint](nil, 2, RadixHeapConfig{})
+	assert.ErrorIs(t, h.Rebalance(), ErrHeapEmpty)
+
+	h.Push("a", 5)
+	assert.ErrorIs(t, h.Rebalance(), ErrNoRebalancingNeeded)
+	h.Push("b", 9)
+	h.Push("c", 7)
+	h.Pop()
+
+	v, p, err := h.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "c", v)
+	assert.Equal(t, uint(7), p)
+	assert.Equal(t, 2, h.Length())
+
+	require.NoError(t, h.Rebalance())
+	require.NoError(t, h.Verify())
+	p, err = h.PeekPriority()
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), p)
+}
+
+func TestMultiLevelRadixHeapClearClone(t *testing.T) {
+	h := NewMultiLevelRadixHeap([]HeapNode[int, uint32]{
+		CreateHeapNode(1, uint32(100)),
+		CreateHeapNode(2, uint32(1<<20)),
+		CreateHeapNode(3, uint32(300)),
+	}, 8, RadixHeapConfig{})
+
+	clone := h.Clone()
+	assert.Equal(t, []uint32{100, 300, 1 << 20}, h.DrainPriorities())
+	assert.Equal(t, 3, clone.Length())
+	require.NoError(t, clone.Verify())
+	assert.Equal(t, []int{1, 3, 2}, clone.DrainValues())
+
+	h.Push(4, 50)
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	require.NoError(t, h.Verify())
+	require.NoError(t, h.Push(5, 1))
+	v, err := h.PeekValue()
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+}
+
+func TestMultiLevelRadixHeapPopEqual(t *testing.T) {
+	h := NewMultiLevelRadixHeap[string, uint64](nil, 4, RadixHeapConfig{})
+	_, err := h.PopEqual()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	for i, p := range []uint64{100, 250, 100, 250, 250, 999} {
+		h.Push(string(rune('a'+i)), p)
+	}
+	batch, err := h.PopEqual()
+	require.NoError(t, err)
+	assert.Len(t, batch, 2)
+	batch, err = h.PopEqual()
+	require.NoError(t, err)
+	assert.Len(t, batch, 3)
+	for _, node := range batch {
+		assert.Equal(t, uint64(250), node.Priority())
+	}
+	require.NoError(t, h.Verify())
+	assert.Equal(t, 1, h.Length())
+}
+
+func TestMultiLevelRadixHeapAdvanceTo(t *testing.T) {
+	for _, digitBits := range []int{1, 4, 8} {
+		rng := rand.New(rand.NewSource(int64(digitBits)))
+		h := NewMultiLevelRadixHeap[int, uint64](nil, digitBits, RadixHeapConfig{})
+		h.Push(0, 1<<40)
+		pending := []uint64{1 << 40}
+		for i := 1; i < 2000; i++ {
+			if rng.Intn(4) > 0 {
+				p := h.Last() + uint64(rng.Int63n(1<<20))
+				require.NoError(t, h.Push(i, p))
+				pending = append(pending, p)
+			} else {
+				clock := h.Last() + uint64(rng.Int63n(1<<18))
+				slices.Sort(pending)
+				n, _ := slices.BinarySearch(pending, clock)
+				stale, err := h.AdvanceTo(clock)
+				require.NoError(t, err)
+				assert.Equal(t, pending[:n], nodePriorities(stale))
+				assert.Equal(t, clock, h.Last())
+				pending = slices.Clone(pending[n:])
+			}
+			require.NoError(t, h.Verify(), "digitBits=%d", digitBits)
+		}
+		_, err := h.AdvanceTo(h.Last() - 1)
+		assert.ErrorIs(t, err, ErrPriorityLessThanLast)
+		slices.Sort(pending)
+		assert.Equal(t, pending, h.DrainPriorities())
+	}
+}
+
+func TestSyncMultiLevelRadixHeapConcurrentPush(t *testing.T) {
+	h := NewSyncMultiLevelRadixHeap[int, uint64](nil, 8, RadixHeapConfig{})
+	require.NoError(t, h.Push(0, 0))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				assert.NoError(t, h.Push(w, uint64(w*1000+i)))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 401, h.Length())
+	require.NoError(t, h.Verify())
+	priorities := h.DrainPriorities()
+	assert.True(t, slices.IsSorted(priorities))
+}
+
+// -------------------------------- Multi-Level Radix Heap Benchmarks --------------------------------
+
+// benchmarkTimestamps pushes b.N nanosecond timestamps within a one-second
+// window and pops them all, mimicking a timer queue keyed by uint64 time.
+func benchmarkTimestamps(b *testing.B, push func(v int, p uint64), pop func()) {
+	rng := rand.New(rand.NewSource(1))
+	base := uint64(1_700_000_000_000_000_000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		push(i, base+uint64(rng.Int63n(1_000_000_000)))
+	}
+	for i := 0; i < b.N; i++ {
+		pop()
+	}
+}
+
+func BenchmarkRadixHeapTimestamps(b *testing.B) {
+	heap := NewRadixHeap[int, uint64](nil, false)
+	heap.Push(0, 0)
+	benchmarkTimestamps(b, func(v int, p uint64) { heap.Push(v, p) }, func() { heap.Pop() })
+}
+
+func BenchmarkMultiLevelRadixHeapTimestamps(b *testing.B) {
+	heap := NewMultiLevelRadixHeap[int, uint64](nil, DefaultRadixDigitBits, RadixHeapConfig{})
+	heap.Push(0, 0)
+	benchmarkTimestamps(b, func(v int, p uint64) { heap.Push(v, p) }, func() { heap.Pop() })
+}
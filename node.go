@@ -1,12 +1,69 @@
 package heapcraft
 
+import "fmt"
+
 // HeapNode binds a value to its priority for heap operations.
 type HeapNode[V any, P any] struct {
 	value    V
 	priority P
+	// seq records insertion order, and is only set by stable heaps.
+	seq uint64
 }
 
 // CreateHeapNode constructs a new HeapNode from the given value and priority.
 func CreateHeapNode[V any, P any](value V, priority P) HeapNode[V, P] {
 	return HeapNode[V, P]{value: value, priority: priority}
 }
+
+// Value returns the value stored in the node.
+func (n HeapNode[V, P]) Value() V { return n.value }
+
+// Priority returns the priority stored in the node.
+func (n HeapNode[V, P]) Priority() P { return n.priority }
+
+// WithValue returns a copy of the node holding value instead of its current
+// value.
+func (n HeapNode[V, P]) WithValue(value V) HeapNode[V, P] {
+	return HeapNode[V, P]{value: value, priority: n.priority}
+}
+
+// WithPriority returns a copy of the node holding priority instead of its
+// current priority.
+func (n HeapNode[V, P]) WithPriority(priority P) HeapNode[V, P] {
+	return HeapNode[V, P]{value: n.value, priority: priority}
+}
+
+// NodesFromMap returns a node for every entry of m, with the key as the value
+// and the map value as the priority. The nodes are in no particular order,
+// which does not matter to the heap constructors.
+func NodesFromMap[V comparable, P any](m map[V]P) []HeapNode[V, P] {
+	nodes := make([]HeapNode[V, P], 0, len(m))
+	for value, priority := range m {
+		nodes = append(nodes, CreateHeapNode(value, priority))
+	}
+	return nodes
+}
+
+// NodesFromPairs returns a node for each index of values and priorities,
+// pairing the elements at the same index. Returns ErrLengthMismatch if the
+// slices have different lengths.
+func NodesFromPairs[V any, P any](values []V, priorities []P) ([]HeapNode[V, P], error) {
+	if len(values) != len(priorities) {
+		return nil, fmt.Errorf("%w: %d values, %d priorities", ErrLengthMismatch, len(values), len(priorities))
+	}
+	nodes := make([]HeapNode[V, P], len(values))
+	for i := range values {
+		nodes[i] = CreateHeapNode(values[i], priorities[i])
+	}
+	return nodes, nil
+}
+
+// NodesFromSlice returns a node for each item, with the item as the value and
+// the result of priority as the priority, in the order of items.
+func NodesFromSlice[V any, P any](items []V, priority func(V) P) []HeapNode[V, P] {
+	nodes := make([]HeapNode[V, P], len(items))
+	for i, item := range items {
+		nodes[i] = CreateHeapNode(item, priority(item))
+	}
+	return nodes
+}
@@ -28,6 +28,8 @@ func TestHeapNodeMethods(t *testing.T) {
 
 	assert.Equal(t, 100, node.value)
 	assert.Equal(t, "high", node.priority)
+	assert.Equal(t, 100, node.Value())
+	assert.Equal(t, "high", node.Priority())
 }
 
 func TestRadixPairCreation(t *testing.T) {
@@ -101,3 +103,24 @@ func TestPointerVsValue(t *testing.T) {
 	assert.Equal(t, "modified", ptrNode.value)
 	assert.Equal(t, "test", valueNode.value)
 }
+
+func TestHeapNodeBuilders(t *testing.T) {
+	node := CreateHeapNode("a", 1)
+	assert.Equal(t, CreateHeapNode("b", 1), node.WithValue("b"))
+	assert.Equal(t, CreateHeapNode("a", 2), node.WithPriority(2))
+	assert.Equal(t, "a", node.Value())
+
+	fromMap := NodesFromMap(map[string]int{"x": 3, "y": 1, "z": 2})
+	assert.Equal(t, []string{"y", "z", "x"}, NewMinDaryHeap(2, fromMap, DaryHeapConfig{}).DrainValues())
+
+	pairs, err := NodesFromPairs([]string{"x", "y"}, []int{2, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []HeapNode[string, int]{CreateHeapNode("x", 2), CreateHeapNode("y", 1)}, pairs)
+	_, err = NodesFromPairs([]string{"x"}, []int{})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+
+	words := []string{"ccc", "a", "bb"}
+	fromSlice := NodesFromSlice(words, func(w string) int { return len(w) })
+	assert.Equal(t, CreateHeapNode("ccc", 3), fromSlice[0])
+	assert.Equal(t, []string{"a", "bb", "ccc"}, NewMinPairingHeap(fromSlice, false).DrainValues())
+}
@@ -1,5 +1,11 @@
 package heapcraft
 
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
 // clearNodeLinks resets all the linking pointers of a node to nil.
 // This is used when removing a node from its current position in the heap
 // before reinserting it elsewhere.
@@ -34,80 +40,266 @@ func (n *pairingHeapNode[V, P]) Priority() P { return n.priority }
 // The heap supports efficient insertion, deletion, and priority updates of nodes.
 // Nodes are tracked by unique IDs, allowing for O(1) access and updates.
 type FullPairingHeap[V any, P any] struct {
-	root     *pairingHeapNode[V, P]
-	cmp      func(a, b P) bool
-	size     int
-	elements map[string]*pairingHeapNode[V, P]
-	pool     pool[*pairingHeapNode[V, P]]
-	idGen    IDGenerator
+	root          *pairingHeapNode[V, P]
+	cmp           func(a, b P) bool
+	size          int
+	elements      map[string]*pairingHeapNode[V, P]
+	pool          pool[*pairingHeapNode[V, P]]
+	alarms        depthAlarms
+	stats         heapStats
+	idGen         IDGenerator
+	onValueUpdate listeners[ValueUpdateEvent[V]]
+	events        listeners[HeapEvent[V, P]]
+	maxDepth      int
 }
 
 // UpdateValue updates the value of a node with the given ID.
 // Returns an error if the ID does not exist in the heap.
 // The heap structure remains unchanged as this operation only modifies the value.
 func (p *FullPairingHeap[V, P]) UpdateValue(id string, value V) error {
-	if _, exists := p.elements[id]; !exists {
-		return ErrNodeNotFound
+	node, exists := p.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
 	}
 
-	p.elements[id].value = value
+	old := node.value
+	node.value = value
+	p.onValueUpdate.emit(ValueUpdateEvent[V]{ID: id, Old: old, New: value})
+	emitHeapEvent(p.events, EventUpdate, id, value, node.priority)
 	return nil
 }
 
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the node ID and its old and new values. Returns an ID that
+// can be passed to RemoveListener.
+func (p *FullPairingHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	return p.onValueUpdate.register(fn)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, including each element melded in from another heap, and
+// whenever the heap is cleared. Events carry the element's ID. Returns an ID
+// that can be passed to RemoveListener.
+func (p *FullPairingHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	return p.events.add(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID, whether it
+// was registered with OnValueUpdate or OnEvent. Returns an error if no
+// listener exists with the given ID.
+func (p *FullPairingHeap[V, P]) RemoveListener(id string) error {
+	if err := p.events.deregister(id); err == nil {
+		return nil
+	}
+	return p.onValueUpdate.deregister(id)
+}
+
 // UpdatePriority updates the priority of a node with the given ID.
 // Returns an error if the ID does not exist in the heap.
-// The node is removed from its current position and reinserted into the heap
-// to maintain the heap property. This operation may change the heap structure.
+// The node is restructured only as far as the heap property requires: see
+// DecreasePriority and IncreasePriority for the paths taken when the new
+// priority moves the node towards or away from the root.
 func (p *FullPairingHeap[V, P]) UpdatePriority(id string, priority P) error {
-	if _, exists := p.elements[id]; !exists {
-		return ErrNodeNotFound
+	node, exists := p.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	if p.cmp(node.priority, priority) {
+		if err := p.increase(node, priority); err != nil {
+			return err
+		}
+	} else {
+		p.decrease(node, priority)
 	}
+	emitHeapEvent(p.events, EventUpdate, id, node.value, priority)
+	return nil
+}
 
-	updated := p.elements[id]
-	updated.priority = priority
+// UpdatePriorityFunc replaces the priority of the node with the given ID with
+// fn applied to its current priority, for adjustments such as a boost by
+// elapsed time. Returns an error if the ID does not exist in the heap, in
+// which case fn is not called.
+func (p *FullPairingHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	return updatePriorityFunc(p.GetPriority, p.UpdatePriority, id, fn)
+}
 
-	switch {
-	case updated.id == p.root.id:
-		newRoot := updated.firstChild
-		if newRoot != nil {
-			newRoot.prevSibling, newRoot.parent = nil, nil
-		}
-		updated.firstChild = nil
-		p.root = p.merge(newRoot)
+// DecreasePriority gives the node with the given ID a priority that does not
+// come after its current one, moving it towards the root. If the node is the
+// root, or the first child of a parent it still does not come before, it keeps
+// its place; otherwise it is cut from its parent and melded with the root.
+// Returns ErrNodeNotFound if the ID does not exist in the heap and
+// ErrPriorityNotDecreased if priority comes after the current priority.
+func (p *FullPairingHeap[V, P]) DecreasePriority(id string, priority P) error {
+	node, exists := p.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	if p.cmp(node.priority, priority) {
+		return ErrPriorityNotDecreased
+	}
+	p.decrease(node, priority)
+	emitHeapEvent(p.events, EventUpdate, id, node.value, priority)
+	return nil
+}
+
+// IncreasePriority gives the node with the given ID a priority that does not
+// come before its current one, moving it away from the root. If none of the
+// node's children comes before the new priority, as is always the case for a
+// leaf, it keeps its place; otherwise its children are merged and melded with
+// the root, and the root itself is melded back below them if needed. Returns
+// ErrNodeNotFound if the ID does not exist in the heap and
+// ErrPriorityNotIncreased if priority comes before the current priority.
+func (p *FullPairingHeap[V, P]) IncreasePriority(id string, priority P) error {
+	node, exists := p.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	if p.cmp(priority, node.priority) {
+		return ErrPriorityNotIncreased
+	}
+	if err := p.increase(node, priority); err != nil {
+		return err
+	}
+	emitHeapEvent(p.events, EventUpdate, id, node.value, priority)
+	return nil
+}
+
+// decrease sets the priority of node to one that does not come after its
+// current priority. Only the first child of a parent records a reliable parent
+// link, so any other non-root node is cut and melded with the root, which is
+// O(1) either way.
+func (p *FullPairingHeap[V, P]) decrease(node *pairingHeapNode[V, P], priority P) {
+	node.priority = priority
+	if node == p.root {
+		return
+	}
+	if node.prevSibling == nil && !p.cmp(priority, node.parent.priority) {
+		return
+	}
+	p.detach(node)
+	clearNodeLinks(node)
+	p.root = p.meld(node, p.root)
+}
+
+// increase sets the priority of node to one that does not come before its
+// current priority. The node's children are only detached when one of them
+// would otherwise come before it; the node itself stays in place, since a
+// worse priority cannot come before its parent.
+func (p *FullPairingHeap[V, P]) increase(node *pairingHeapNode[V, P], priority P) error {
+	violated := false
+	for child := node.firstChild; child != nil && !violated; child = child.nextSibling {
+		violated = p.cmp(child.priority, priority)
+	}
+	if !violated {
+		node.priority = priority
+		return nil
+	}
+	if p.mergeExceedsDepth(node.firstChild) {
+		return ErrMaxDepthExceeded
+	}
 
-	case updated.prevSibling != nil:
-		prev, next := updated.prevSibling, updated.nextSibling
+	node.priority = priority
+	children := node.firstChild
+	children.prevSibling, children.parent = nil, nil
+	node.firstChild = nil
+	if node == p.root {
+		p.root = p.meld(node, p.merge(children))
+	} else {
+		p.root = p.meld(p.merge(children), p.root)
+	}
+	return nil
+}
+
+// FixID restores the heap order around the node with the given ID after its
+// priority has been changed in place, for instance through a pointer held by
+// the caller. Since the direction of the change is unknown, the node is
+// checked against both its parent and its children. Returns an error if the ID
+// does not exist in the heap.
+func (p *FullPairingHeap[V, P]) FixID(id string) error {
+	node, exists := p.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	p.decrease(node, node.priority)
+	if err := p.increase(node, node.priority); err != nil {
+		return err
+	}
+	emitHeapEvent(p.events, EventUpdate, id, node.value, node.priority)
+	return nil
+}
+
+// Remove deletes the node with the given ID from the heap and returns its
+// value and priority. The node's children are merged back into the heap in
+// its place. Returns an error if the ID doesn't exist in the heap.
+func (p *FullPairingHeap[V, P]) Remove(id string) (V, P, error) {
+	removed, exists := p.elements[id]
+	if !exists {
+		v, pr := zeroValuePair[V, P]()
+		return v, pr, &NodeNotFoundError{ID: id}
+	}
+
+	if removed == p.root {
+		return p.pop()
+	}
+	if p.mergeExceedsDepth(removed.firstChild) {
+		v, pr := zeroValuePair[V, P]()
+		return v, pr, ErrMaxDepthExceeded
+	}
+
+	p.detach(removed)
+	clearNodeLinks(removed)
+	if children := removed.firstChild; children != nil {
+		children.prevSibling, children.parent = nil, nil
+		removed.firstChild = nil
+		p.root = p.meld(p.merge(children), p.root)
+	}
+
+	delete(p.elements, id)
+	p.size--
+	p.stats.record(OpRemove, 1, p.size)
+	p.alarms.check(p.size)
+	v, pr := removed.value, removed.priority
+	p.pool.Put(removed)
+	emitHeapEvent(p.events, EventRemove, id, v, pr)
+	return v, pr, nil
+}
+
+// detach unlinks a non-root node, together with its subtree, from its
+// parent's list of children. The node's own sibling links are left for the
+// caller to clear.
+func (p *FullPairingHeap[V, P]) detach(node *pairingHeapNode[V, P]) {
+	if node.prevSibling != nil {
+		prev, next := node.prevSibling, node.nextSibling
 		if next != nil {
 			next.prevSibling = prev
 		}
-
 		prev.nextSibling = next
-	default:
-		next := updated.nextSibling
-		if next != nil {
-			next.prevSibling, next.parent = nil, updated.parent
-		}
-		updated.parent.firstChild = next
+		return
 	}
 
-	clearNodeLinks(updated)
-	p.root = p.meld(updated, p.root)
-	return nil
+	next := node.nextSibling
+	if next != nil {
+		next.prevSibling, next.parent = nil, node.parent
+	}
+	node.parent.firstChild = next
 }
 
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
-// original and cloned heaps.
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other.
 func (p *FullPairingHeap[V, P]) Clone() *FullPairingHeap[V, P] {
 	elements := make(map[string]*pairingHeapNode[V, P], len(p.elements))
+	pool := p.pool.fresh()
 	for _, node := range p.elements {
-		cloned := p.pool.Get()
+		cloned := pool.Get()
 		cloned.id = node.id
 		cloned.value = node.value
 		cloned.priority = node.priority
 		cloned.parent = node.parent
 		cloned.firstChild = node.firstChild
 		cloned.nextSibling = node.nextSibling
+		cloned.prevSibling = node.prevSibling
 		elements[node.id] = cloned
 	}
 
@@ -135,23 +327,65 @@ func (p *FullPairingHeap[V, P]) Clone() *FullPairingHeap[V, P] {
 		}
 	}
 
-	return &FullPairingHeap[V, P]{
-		root:     elements[p.root.id],
-		cmp:      p.cmp,
-		size:     p.size,
-		elements: elements,
-		pool:     p.pool,
-		idGen:    p.idGen,
+	cloned := &FullPairingHeap[V, P]{
+		cmp:           p.cmp,
+		size:          p.size,
+		elements:      elements,
+		pool:          pool,
+		alarms:        p.alarms.clone(),
+		stats:         p.stats,
+		idGen:         p.idGen,
+		onValueUpdate: p.onValueUpdate.clone(),
+		events:        p.events.clone(),
+		maxDepth:      p.maxDepth,
+	}
+	if p.root != nil {
+		cloned.root = elements[p.root.id]
+	}
+	return cloned
+}
+
+// CloneFresh creates a deep copy of the heap like Clone, but gives every node
+// of the copy a new ID from the heap's IDGenerator, so that several copies of
+// one template heap never share IDs. Clone keeps the original IDs. New IDs are
+// assigned in the order the elements would be popped, which makes them
+// deterministic for a deterministic generator. Returns the copy and a mapping
+// from each original ID to its new one, or ErrIDGenerationFailed if a new ID
+// collides with an original one.
+func (p *FullPairingHeap[V, P]) CloneFresh() (*FullPairingHeap[V, P], map[string]string, error) {
+	cloned := p.Clone()
+	elements, ids, err := reassignIDs(cloned.idGen, cloned.ordered(), cloned.elements, func(n *pairingHeapNode[V, P]) *string { return &n.id })
+	if err != nil {
+		return nil, nil, err
 	}
+	cloned.elements = elements
+	return cloned, ids, nil
 }
 
 // Clear removes all elements from the heap.
 // Resets the root to nil, size to zero, and initializes a new empty element map.
-// The next node ID is reset to 1.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
 func (p *FullPairingHeap[V, P]) Clear() {
+	cleared := p.Length()
+	releaseElements(p.pool, p.elements)
 	p.root = nil
 	p.size = 0
+	p.stats.record(OpClear, cleared, p.size)
+	p.alarms.check(p.size)
 	p.elements = make(map[string]*pairingHeapNode[V, P], 0)
+	emitClearEvent(p.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (p *FullPairingHeap[V, P]) PoolStats() PoolStats { return p.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes, the
+// nodes idle in its pool, the element map and the node IDs. Memory that values
+// and priorities point to is not counted. Summing the IDs makes it O(n).
+func (p *FullPairingHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[FullPairingHeap[V, P]]() + nodeMemory(p.size, p.pool) +
+		mapMemory[string, *pairingHeapNode[V, P]](len(p.elements)) + idMemory(p.elements)
 }
 
 // Length returns the current number of elements in the heap.
@@ -160,6 +394,48 @@ func (p *FullPairingHeap[V, P]) Length() int { return p.size }
 // IsEmpty returns true if the heap contains no elements.
 func (p *FullPairingHeap[V, P]) IsEmpty() bool { return p.size == 0 }
 
+// Verify checks the internal consistency of the heap: no node may come before
+// its parent, parent and sibling links must agree with the child lists, the
+// root may not have a parent or siblings, and the tree and the ID map must
+// both hold exactly Length nodes, each tracked under its own ID. It is
+// intended for tests and debugging, runs in O(n) and returns an error
+// wrapping ErrInvariantViolated for the first violation.
+func (p *FullPairingHeap[V, P]) Verify() error {
+	if p.root != nil && (p.root.nextSibling != nil || p.root.prevSibling != nil) {
+		return invariantError("the root has a sibling")
+	}
+	visited, err := verifyTree(treeRoots(p.root),
+		func(node *pairingHeapNode[V, P], visit func(*pairingHeapNode[V, P])) {
+			for child := node.firstChild; child != nil; child = child.nextSibling {
+				visit(child)
+			}
+		},
+		func(node, parent *pairingHeapNode[V, P]) error {
+			if node.parent != parent {
+				return invariantError("node %q does not link to its parent", node.id)
+			}
+			if node.firstChild != nil && node.firstChild.prevSibling != nil {
+				return invariantError("the first child of node %q has a previous sibling", node.id)
+			}
+			if node.nextSibling != nil && node.nextSibling.prevSibling != node {
+				return invariantError("the next sibling of node %q does not link back to it", node.id)
+			}
+			if parent != nil {
+				if err := verifyOrder(p.cmp, node.priority, parent.priority); err != nil {
+					return err
+				}
+			}
+			return verifyTracked(p.elements, node.id, node)
+		})
+	if err != nil {
+		return err
+	}
+	if err := verifySize(visited, p.size); err != nil {
+		return err
+	}
+	return verifyElements(len(p.elements), p.size)
+}
+
 // peek is an internal method that returns the root node's value and priority without removing it.
 // Returns nil and an error if the heap is empty.
 func (p *FullPairingHeap[V, P]) peek() (V, P, error) {
@@ -171,6 +447,29 @@ func (p *FullPairingHeap[V, P]) peek() (V, P, error) {
 	return v, pr, nil
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (p *FullPairingHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return p.alarms.register(n, p.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (p *FullPairingHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return p.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (p *FullPairingHeap[V, P]) Stats() HeapStats { return p.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (p *FullPairingHeap[V, P]) Instrument(hook Instrumentation) { p.stats.hook = hook }
+
 // Peek returns a HeapNode containing the value and priority
 // of the root node without removing it. Returns nil and an error if the heap is empty.
 func (p *FullPairingHeap[V, P]) Peek() (V, P, error) { return p.peek() }
@@ -193,7 +492,7 @@ func (p *FullPairingHeap[V, P]) get(id string) (V, P, error) {
 	node, exists := p.elements[id]
 	if !exists {
 		v, p := zeroValuePair[V, P]()
-		return v, p, ErrNodeNotFound
+		return v, p, &NodeNotFoundError{ID: id}
 	}
 	v, pr := node.value, node.priority
 	return v, pr, nil
@@ -230,6 +529,7 @@ func (p *FullPairingHeap[V, P]) meld(new *pairingHeapNode[V, P], root *pairingHe
 	}
 
 	var prior, noPrior *pairingHeapNode[V, P]
+	p.stats.rebalance()
 
 	if p.cmp(new.priority, root.priority) {
 		prior, noPrior = new, root
@@ -249,6 +549,21 @@ func (p *FullPairingHeap[V, P]) meld(new *pairingHeapNode[V, P], root *pairingHe
 	return prior
 }
 
+// mergeExceedsDepth reports whether merging the sibling list starting at
+// first would recurse deeper than the heap's MaxDepth. merge recurses once
+// per pair of siblings, so the list is only walked when the heap is large
+// enough to reach the limit.
+func (p *FullPairingHeap[V, P]) mergeExceedsDepth(first *pairingHeapNode[V, P]) bool {
+	if !depthExceeds(p.maxDepth, (p.size+1)/2) {
+		return false
+	}
+	siblings := 0
+	for node := first; node != nil; node = node.nextSibling {
+		siblings++
+	}
+	return depthExceeds(p.maxDepth, (siblings+1)/2)
+}
+
 // merge performs the two-pass pairing process on a list of siblings.
 // It pairs adjacent siblings, melds them, and recursively merges the
 // remaining siblings. This operation is used during Pop to combine
@@ -282,17 +597,24 @@ func (p *FullPairingHeap[V, P]) pop() (V, P, error) {
 		v, p := zeroValuePair[V, P]()
 		return v, p, ErrHeapEmpty
 	}
+	if p.mergeExceedsDepth(p.root.firstChild) {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrMaxDepthExceeded
+	}
 
 	removed := p.root
 	p.root = p.merge(p.root.firstChild)
 	p.size--
+	p.stats.record(OpPop, 1, p.size)
+	p.alarms.check(p.size)
 	removed.firstChild = nil
 	removed.nextSibling = nil
 	removed.parent = nil
 	removed.prevSibling = nil
 	delete(p.elements, removed.id)
-	v, pr := removed.value, removed.priority
+	id, v, pr := removed.id, removed.value, removed.priority
 	p.pool.Put(removed)
+	emitHeapEvent(p.events, EventPop, id, v, pr)
 	return v, pr, nil
 }
 
@@ -301,6 +623,14 @@ func (p *FullPairingHeap[V, P]) pop() (V, P, error) {
 // Returns nil and an error if the heap is empty.
 func (p *FullPairingHeap[V, P]) Pop() (V, P, error) { return p.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (p *FullPairingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(p.Peek, p.Pop, pred)
+}
+
 // PopValue removes and returns just the value at the root.
 // The root's children are merged to form the new heap.
 // Returns zero value and an error if the heap is empty.
@@ -308,6 +638,29 @@ func (p *FullPairingHeap[V, P]) PopValue() (V, error) {
 	return valueFromNode(p.pop())
 }
 
+// PopCommit passes the ID, value and priority of the root element to commit
+// and removes the element only if commit succeeds. This lets the caller
+// persist the hand-off, for example by marking a job row as claimed, before
+// the element leaves the heap: if commit returns an error, the element stays
+// at the root and the error is returned. Returns ErrHeapEmpty without calling
+// commit if the heap is empty.
+func (p *FullPairingHeap[V, P]) PopCommit(commit func(id string, value V, priority P) error) (string, V, P, error) {
+	if p.size == 0 {
+		v, pr := zeroValuePair[V, P]()
+		return "", v, pr, ErrHeapEmpty
+	}
+	id, value, priority := p.root.id, p.root.value, p.root.priority
+	if err := commit(id, value, priority); err != nil {
+		v, pr := zeroValuePair[V, P]()
+		return "", v, pr, err
+	}
+	if _, _, err := p.pop(); err != nil {
+		v, pr := zeroValuePair[V, P]()
+		return "", v, pr, err
+	}
+	return id, value, priority, nil
+}
+
 // PopPriority removes and returns just the priority at the root.
 // The root's children are merged to form the new heap.
 // Returns zero value and an error if the heap is empty.
@@ -315,23 +668,278 @@ func (p *FullPairingHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(p.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (p *FullPairingHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(p.Length(), p.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (p *FullPairingHeap[V, P]) DrainValues() []V {
+	return drainValues(p.Length(), p.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (p *FullPairingHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(p.Length(), p.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (p *FullPairingHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range p.elements {
+		fn(node.value, node.priority)
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (p *FullPairingHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(p.Length(), p.forEach, p.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (p *FullPairingHeap[V, P]) ordered() iter.Seq[*pairingHeapNode[V, P]] {
+	priority := func(n *pairingHeapNode[V, P]) P { return n.priority }
+	return orderedNodes(p.cmp, treeRoots(p.root), priority, func(n *pairingHeapNode[V, P], visit func(*pairingHeapNode[V, P])) {
+		for child := n.firstChild; child != nil; child = child.nextSibling {
+			visit(child)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (p *FullPairingHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(p.ordered(), func(n *pairingHeapNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "id", "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (p *FullPairingHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, p.ordered(), func(n *pairingHeapNode[V, P]) trackedNodeJSON[V, P] {
+		return trackedNodeJSON[V, P]{ID: n.id, Value: n.value, Priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its ID and priority. First-child and next-sibling
+// links are drawn solid, and parent and previous-sibling links dashed. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (p *FullPairingHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, treeRoots(p.root),
+		func(node *pairingHeapNode[V, P]) string {
+			return fmt.Sprintf("%s\n%v", node.id, node.priority)
+		},
+		func(node *pairingHeapNode[V, P], edge func(dotEdge[*pairingHeapNode[V, P]])) {
+			if node.firstChild != nil {
+				edge(dotEdge[*pairingHeapNode[V, P]]{label: "child", to: node.firstChild})
+			}
+			if node.nextSibling != nil {
+				edge(dotEdge[*pairingHeapNode[V, P]]{label: "sibling", to: node.nextSibling})
+			}
+			if node.parent != nil {
+				edge(dotEdge[*pairingHeapNode[V, P]]{label: "parent", to: node.parent, back: true})
+			}
+			if node.prevSibling != nil {
+				edge(dotEdge[*pairingHeapNode[V, P]]{label: "prev", to: node.prevSibling, back: true})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first together with their IDs. The comparison function and
+// ID generator are not encoded.
+func (p *FullPairingHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalTrackedHeap(p.elements, p.cmp, func(node *pairingHeapNode[V, P]) (V, P) {
+		return node.value, node.priority
+	})
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON, keeping their IDs. The heap must have been created with its
+// constructor so that it has a comparison function to restore them with. An
+// IntegerIDGenerator is advanced past the restored IDs. Returns
+// ErrDuplicateID, leaving the heap unchanged, if an ID is encoded twice.
+func (p *FullPairingHeap[V, P]) UnmarshalJSON(data []byte) error {
+	nodes, err := unmarshalTrackedHeap[V, P](data, p.cmp)
+	if err != nil {
+		return err
+	}
+
+	p.Clear()
+	for _, node := range nodes {
+		if err := p.pushWithID(node.ID, node.Value, node.Priority); err != nil {
+			return err
+		}
+		advanceIDGenerator(p.idGen, node.ID)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap, together with their IDs, in a
+// compact binary format using encoding/gob, for persisting the heap across
+// process restarts. Elements are written in no particular order. The
+// comparison function and ID generator are not encoded.
+func (p *FullPairingHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotTracked(p.elements, func(node *pairingHeapNode[V, P]) (V, P) {
+		return node.value, node.priority
+	}))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot, keeping their IDs. The heap must have been created with
+// its constructor so that it has a comparison function to restore them with.
+// An IntegerIDGenerator is advanced past the restored IDs.
+func (p *FullPairingHeap[V, P]) Restore(data []byte) error {
+	if p.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, true)
+	if err != nil {
+		return err
+	}
+
+	p.Clear()
+	for i, id := range snapshot.IDs {
+		if err := p.pushWithID(id, snapshot.Values[i], snapshot.Priorities[i]); err != nil {
+			return err
+		}
+		advanceIDGenerator(p.idGen, id)
+	}
+	return nil
+}
+
 // Push adds a new element with the given value and priority to the heap.
 // A new node is created with a unique ID and melded with the existing root.
 // The new node becomes the root if its priority is higher than the current root's.
 // Returns the ID of the inserted node.
 func (p *FullPairingHeap[V, P]) Push(value V, priority P) (string, error) {
-	newNode := p.pool.Get()
-	newNode.id = p.idGen.Next()
-	if _, exists := p.elements[newNode.id]; exists {
+	id := p.idGen.Next()
+	if err := p.pushWithID(id, value, priority); err != nil {
 		return "", ErrIDGenerationFailed
 	}
+	return id, nil
+}
+
+// pushWithID inserts a new node with the given ID into the heap. Returns
+// ErrDuplicateID if a node with the ID already exists.
+func (p *FullPairingHeap[V, P]) pushWithID(id string, value V, priority P) error {
+	if _, exists := p.elements[id]; exists {
+		return ErrDuplicateID
+	}
 
+	newNode := p.pool.Get()
+	newNode.id = id
 	newNode.value = value
 	newNode.priority = priority
 	p.elements[newNode.id] = newNode
 	p.root = p.meld(newNode, p.root)
 	p.size++
-	return newNode.id, nil
+	p.stats.record(OpPush, 1, p.size)
+	p.alarms.check(p.size)
+	emitHeapEvent(p.events, EventPush, id, value, priority)
+	return nil
+}
+
+// PushWithID inserts a new element under an ID chosen by the caller, such as
+// the primary key of the row the element was loaded from, instead of one
+// drawn from the ID generator. An IntegerIDGenerator is advanced past numeric
+// IDs so that later calls to Push do not collide with them. Returns
+// ErrDuplicateID if a node with the ID already exists.
+func (p *FullPairingHeap[V, P]) PushWithID(id string, value V, priority P) error {
+	if err := p.pushWithID(id, value, priority); err != nil {
+		return err
+	}
+	advanceIDGenerator(p.idGen, id)
+	return nil
+}
+
+// GetOrPush returns the value and priority of the node with the given ID,
+// with true, if there is one. Otherwise it inserts the given element under
+// the ID like PushWithID and returns it with false, so that a retried job
+// keyed by its own ID is enqueued only once.
+func (p *FullPairingHeap[V, P]) GetOrPush(id string, value V, priority P) (V, P, bool) {
+	if node, exists := p.elements[id]; exists {
+		return node.value, node.priority, true
+	}
+	p.PushWithID(id, value, priority)
+	return value, priority, false
+}
+
+// PushIfAbsent inserts an element under the given ID only if no node with the
+// ID exists yet. Returns true if the element was inserted.
+func (p *FullPairingHeap[V, P]) PushIfAbsent(id string, value V, priority P) bool {
+	_, _, loaded := p.GetOrPush(id, value, priority)
+	return !loaded
+}
+
+// PushAll inserts all of the given elements into the heap and returns their
+// IDs in the same order. The elements are linked into a subtree of their own,
+// which is then melded into the heap once. Returns ErrIDGenerationFailed,
+// leaving the heap unchanged, if a generated ID is not unique.
+func (p *FullPairingHeap[V, P]) PushAll(data []HeapNode[V, P]) ([]string, error) {
+	ids, err := generateIDs(p.idGen, len(data), p.elements)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtree *pairingHeapNode[V, P]
+	for i := range data {
+		node := p.pool.Get()
+		node.id = ids[i]
+		node.value = data[i].value
+		node.priority = data[i].priority
+		p.elements[node.id] = node
+		subtree = p.meld(node, subtree)
+	}
+	p.root = p.meld(subtree, p.root)
+	p.size += len(data)
+	p.stats.record(OpPush, len(data), p.size)
+	p.alarms.check(p.size)
+	for i := range data {
+		emitHeapEvent(p.events, EventPush, ids[i], data[i].value, data[i].priority)
+	}
+	return ids, nil
+}
+
+// Meld merges another heap into this one in O(1) time by linking the two
+// trees, and absorbs the other heap's nodes so they remain addressable by
+// their IDs. The other heap is consumed by the operation and left empty.
+// Returns an error, leaving both heaps unchanged, if an ID exists in both
+// heaps. Both heaps are expected to share the same comparison function.
+func (p *FullPairingHeap[V, P]) Meld(other *FullPairingHeap[V, P]) error {
+	if other == nil || other == p {
+		return nil
+	}
+	for id := range other.elements {
+		if _, exists := p.elements[id]; exists {
+			return ErrDuplicateID
+		}
+	}
+
+	for id, node := range other.elements {
+		p.elements[id] = node
+	}
+	p.root = p.meld(other.root, p.root)
+	p.size += other.size
+	p.stats.record(OpMeld, other.size, p.size)
+	p.alarms.check(p.size)
+	if len(p.events) > 0 {
+		for id, node := range other.elements {
+			emitHeapEvent(p.events, EventPush, id, node.value, node.priority)
+		}
+	}
+	other.root, other.elements = nil, nil
+	other.Clear()
+	return nil
 }
 
 // pairingNode represents a node in the simple pairing heap.
@@ -354,45 +962,94 @@ func (n *pairingNode[V, P]) Priority() P { return n.priority }
 // It maintains a multi-way tree structure but does not support node updates
 // or removal of arbitrary nodes. This implementation is simpler but less
 // feature-rich than FullPairingHeap.
+//
+// A heap created with HeapConfig.AuxiliaryPush set keeps new elements in an
+// auxiliary list of single nodes instead of melding each one into the root, so
+// Push is strictly O(1) and never touches the root's child list. The list is
+// paired up in multiple passes and melded into the tree the next time the root
+// is removed.
+//
+// Pop is O(log n) amortized only: the first Pop after n pushes pairs up about
+// n children of the root, and that work cannot be spread over later calls
+// without losing track of the new root. Use SkewBinomialHeap where the
+// latency of every single Pop must be bounded.
 type PairingHeap[V any, P any] struct {
-	root *pairingNode[V, P]
-	cmp  func(a, b P) bool
-	size int
-	pool pool[*pairingNode[V, P]]
-}
-
-// cloneNode creates a deep copy of a pairing node.
-// It recursively clones the first child and next sibling.
-func (p *PairingHeap[V, P]) cloneNode(node *pairingNode[V, P]) *pairingNode[V, P] {
-	if node == nil {
-		return nil
-	}
-
-	cloned := p.pool.Get()
-	cloned.value = node.value
-	cloned.priority = node.priority
-	cloned.firstChild = p.cloneNode(node.firstChild)
-	cloned.nextSibling = p.cloneNode(node.nextSibling)
-	return cloned
+	root     *pairingNode[V, P]
+	cmp      func(a, b P) bool
+	size     int
+	pool     pool[*pairingNode[V, P]]
+	alarms   depthAlarms
+	stats    heapStats
+	events   listeners[HeapEvent[V, P]]
+	maxDepth int
+
+	// auxiliary selects the auxiliary push mode. aux is the list of nodes
+	// pushed since the last flush, linked through nextSibling, and auxBest is
+	// the best of them.
+	auxiliary bool
+	aux       *pairingNode[V, P]
+	auxBest   *pairingNode[V, P]
 }
 
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
-// original and cloned heaps.
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other. The tree is copied
+// iteratively, so cloning a degenerate heap cannot overflow the stack.
 func (p *PairingHeap[V, P]) Clone() *PairingHeap[V, P] {
-	return &PairingHeap[V, P]{
-		root: p.cloneNode(p.root),
-		cmp:  p.cmp,
-		size: p.size,
-		pool: p.pool,
+	cloned := &PairingHeap[V, P]{
+		cmp:       p.cmp,
+		size:      p.size,
+		pool:      p.pool.fresh(),
+		alarms:    p.alarms.clone(),
+		stats:     p.stats,
+		events:    p.events.clone(),
+		maxDepth:  p.maxDepth,
+		auxiliary: p.auxiliary,
+	}
+	links := func(n *pairingNode[V, P]) (**pairingNode[V, P], **pairingNode[V, P]) {
+		return &n.firstChild, &n.nextSibling
 	}
+	cloned.root = cloneTree(p.root, cloned.pool.Get, links)
+	cloned.aux = cloneTree(p.aux, cloned.pool.Get, links)
+	for node := cloned.aux; node != nil; node = node.nextSibling {
+		if cloned.auxBest == nil || cloned.cmp(node.priority, cloned.auxBest.priority) {
+			cloned.auxBest = node
+		}
+	}
+	return cloned
 }
 
 // Clear removes all elements from the simple heap.
 // The heap is ready for new insertions after clearing.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
 func (p *PairingHeap[V, P]) Clear() {
+	cleared := p.Length()
+	releaseTree(p.pool, append(treeRoots(p.root), treeRoots(p.aux)...), func(n *pairingNode[V, P], visit func(*pairingNode[V, P])) {
+		if n.firstChild != nil {
+			visit(n.firstChild)
+		}
+		if n.nextSibling != nil {
+			visit(n.nextSibling)
+		}
+	})
 	p.root = nil
+	p.aux, p.auxBest = nil, nil
 	p.size = 0
+	p.stats.record(OpClear, cleared, p.size)
+	p.alarms.check(p.size)
+	emitClearEvent(p.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (p *PairingHeap[V, P]) PoolStats() PoolStats { return p.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes and the
+// nodes idle in its pool. Memory that values and priorities point to, such as
+// the contents of strings, is not counted.
+func (p *PairingHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[PairingHeap[V, P]]() + nodeMemory(p.size, p.pool)
 }
 
 // Length returns the current number of elements in the heap.
@@ -401,6 +1058,52 @@ func (p *PairingHeap[V, P]) Length() int { return p.size }
 // IsEmpty returns true if the simple heap contains no elements.
 func (p *PairingHeap[V, P]) IsEmpty() bool { return p.size == 0 }
 
+// Verify checks the internal consistency of the heap: no node may come before
+// its parent, the root may not have siblings, the auxiliary list must hold
+// only single nodes and track its best one, and together they must hold
+// exactly Length nodes. It is intended for tests and debugging, runs in O(n)
+// and returns an error wrapping ErrInvariantViolated for the first violation.
+func (p *PairingHeap[V, P]) Verify() error {
+	if p.root != nil && p.root.nextSibling != nil {
+		return invariantError("the root has a sibling")
+	}
+	if !p.auxiliary && p.aux != nil {
+		return invariantError("the auxiliary list is used outside auxiliary mode")
+	}
+	if (p.aux == nil) != (p.auxBest == nil) {
+		return invariantError("the best auxiliary node is out of date")
+	}
+	foundBest := p.aux == nil
+	for node := p.aux; node != nil; node = node.nextSibling {
+		if node.firstChild != nil {
+			return invariantError("an auxiliary node has children")
+		}
+		if p.cmp(node.priority, p.auxBest.priority) {
+			return invariantError("an auxiliary node comes before the best auxiliary node")
+		}
+		foundBest = foundBest || node == p.auxBest
+	}
+	if !foundBest {
+		return invariantError("the best auxiliary node is not in the auxiliary list")
+	}
+	visited, err := verifyTree(p.roots(),
+		func(node *pairingNode[V, P], visit func(*pairingNode[V, P])) {
+			for child := node.firstChild; child != nil; child = child.nextSibling {
+				visit(child)
+			}
+		},
+		func(node, parent *pairingNode[V, P]) error {
+			if parent == nil {
+				return nil
+			}
+			return verifyOrder(p.cmp, node.priority, parent.priority)
+		})
+	if err != nil {
+		return err
+	}
+	return verifySize(visited, p.size)
+}
+
 // peek is an internal method that returns the root node's value and priority without removing it.
 // Returns nil and an error if the heap is empty.
 func (p *PairingHeap[V, P]) peek() (V, P, error) {
@@ -408,10 +1111,93 @@ func (p *PairingHeap[V, P]) peek() (V, P, error) {
 		v, p := zeroValuePair[V, P]()
 		return v, p, ErrHeapEmpty
 	}
-	v, pr := p.root.value, p.root.priority
-	return v, pr, nil
+	top := p.top()
+	return top.value, top.priority, nil
+}
+
+// top returns the node that would be popped next: the better of the root and
+// the best node of the auxiliary list.
+func (p *PairingHeap[V, P]) top() *pairingNode[V, P] {
+	if p.root == nil || (p.auxBest != nil && p.cmp(p.auxBest.priority, p.root.priority)) {
+		return p.auxBest
+	}
+	return p.root
+}
+
+// roots returns the root of the tree followed by every node of the auxiliary
+// list, each of which is the root of a single-node tree.
+func (p *PairingHeap[V, P]) roots() []*pairingNode[V, P] {
+	roots := treeRoots(p.root)
+	for node := p.aux; node != nil; node = node.nextSibling {
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// flush pairs up the auxiliary list in repeated left-to-right passes until a
+// single tree remains, and melds that tree into the root. Each pass halves the
+// list, so flushing k nodes takes O(k) time and no recursion.
+func (p *PairingHeap[V, P]) flush() {
+	list := p.aux
+	if list == nil {
+		return
+	}
+	for list.nextSibling != nil {
+		var head, tail *pairingNode[V, P]
+		for node := list; node != nil; {
+			first, second := node, node.nextSibling
+			node = nil
+			if second != nil {
+				node = second.nextSibling
+				second.nextSibling = nil
+			}
+			first.nextSibling = nil
+			tree := p.meld(first, second)
+			if head == nil {
+				head = tree
+			} else {
+				tail.nextSibling = tree
+			}
+			tail = tree
+		}
+		list = head
+	}
+	p.root = p.meld(list, p.root)
+	p.aux, p.auxBest = nil, nil
+}
+
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (p *PairingHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return p.alarms.register(n, p.Length(), fn)
 }
 
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (p *PairingHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return p.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (p *PairingHeap[V, P]) Stats() HeapStats { return p.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (p *PairingHeap[V, P]) Instrument(hook Instrumentation) { p.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element melded in from another heap, and whenever the heap is
+// cleared. Returns an ID that can be passed to RemoveListener.
+func (p *PairingHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return p.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (p *PairingHeap[V, P]) RemoveListener(id string) error { return p.events.deregister(id) }
+
 // Peek returns a HeapNode containing the value and priority
 // of the root node without removing it. Returns nil and an error if the heap is empty.
 func (p *PairingHeap[V, P]) Peek() (V, P, error) { return p.peek() }
@@ -442,6 +1228,7 @@ func (p *PairingHeap[V, P]) meld(new *pairingNode[V, P], root *pairingNode[V, P]
 	}
 
 	newRoot := root
+	p.stats.rebalance()
 
 	if p.cmp(new.priority, newRoot.priority) {
 		newRoot.nextSibling = new.firstChild
@@ -454,6 +1241,21 @@ func (p *PairingHeap[V, P]) meld(new *pairingNode[V, P], root *pairingNode[V, P]
 	return newRoot
 }
 
+// mergeExceedsDepth reports whether merging the sibling list starting at
+// first would recurse deeper than the heap's MaxDepth. merge recurses once
+// per pair of siblings, so the list is only walked when the heap is large
+// enough to reach the limit.
+func (p *PairingHeap[V, P]) mergeExceedsDepth(first *pairingNode[V, P]) bool {
+	if !depthExceeds(p.maxDepth, (p.size+1)/2) {
+		return false
+	}
+	siblings := 0
+	for node := first; node != nil; node = node.nextSibling {
+		siblings++
+	}
+	return depthExceeds(p.maxDepth, (siblings+1)/2)
+}
+
 // merge performs the two-pass pairing process on the sibling list.
 // It pairs adjacent siblings, melds them, and recursively merges the
 // remaining siblings. This is used during Pop to combine the root's
@@ -473,6 +1275,37 @@ func (p *PairingHeap[V, P]) merge(node *pairingNode[V, P]) *pairingNode[V, P] {
 	return p.meld(p.meld(firstNode, secondNode), p.merge(remaining))
 }
 
+// mergeIteratively builds the same tree as merge with loops instead of
+// recursion. The operations without an error result use it when the
+// recursive merge would exceed the heap's MaxDepth.
+func (p *PairingHeap[V, P]) mergeIteratively(node *pairingNode[V, P]) *pairingNode[V, P] {
+	// The first pass melds adjacent siblings and stacks the pairs in reverse
+	// order through their sibling links.
+	var pairs *pairingNode[V, P]
+	for node != nil {
+		first, second := node, node.nextSibling
+		node = nil
+		if second != nil {
+			node, second.nextSibling = second.nextSibling, nil
+		}
+		first.nextSibling = nil
+		pair := p.meld(first, second)
+		pair.nextSibling = pairs
+		pairs = pair
+	}
+
+	// The second pass melds the pairs from the last to the first, as the
+	// recursion in merge unwinds.
+	var root *pairingNode[V, P]
+	for pairs != nil {
+		next := pairs.nextSibling
+		pairs.nextSibling = nil
+		root = p.meld(pairs, root)
+		pairs = next
+	}
+	return root
+}
+
 // pop is an internal method that removes the root node and returns it.
 // It handles the common logic of removing the root and merging children.
 // Returns nil and an error if the heap is empty.
@@ -481,6 +1314,11 @@ func (p *PairingHeap[V, P]) pop() (V, P, error) {
 		v, p := zeroValuePair[V, P]()
 		return v, p, ErrHeapEmpty
 	}
+	p.flush()
+	if p.mergeExceedsDepth(p.root.firstChild) {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrMaxDepthExceeded
+	}
 
 	removed := p.root
 	p.root = p.merge(p.root.firstChild)
@@ -489,6 +1327,9 @@ func (p *PairingHeap[V, P]) pop() (V, P, error) {
 	v, pr := removed.value, removed.priority
 	p.pool.Put(removed)
 	p.size--
+	p.stats.record(OpPop, 1, p.size)
+	p.alarms.check(p.size)
+	emitHeapEvent(p.events, EventPop, "", v, pr)
 	return v, pr, nil
 }
 
@@ -497,6 +1338,14 @@ func (p *PairingHeap[V, P]) pop() (V, P, error) {
 // Returns nil and an error if the heap is empty.
 func (p *PairingHeap[V, P]) Pop() (V, P, error) { return p.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (p *PairingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(p.Peek, p.Pop, pred)
+}
+
 // PopValue removes and returns just the value at the root.
 // The root's children are merged to form the new heap.
 // Returns zero value and an error if the heap is empty.
@@ -511,13 +1360,309 @@ func (p *PairingHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(p.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (p *PairingHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(p.Length(), p.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (p *PairingHeap[V, P]) DrainValues() []V {
+	return drainValues(p.Length(), p.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (p *PairingHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(p.Length(), p.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (p *PairingHeap[V, P]) forEach(fn func(v V, p P)) {
+	stack := make([]*pairingNode[V, P], 0)
+	if p.root != nil {
+		stack = append(stack, p.root)
+	}
+	if p.aux != nil {
+		stack = append(stack, p.aux)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(node.value, node.priority)
+		if node.firstChild != nil {
+			stack = append(stack, node.firstChild)
+		}
+		if node.nextSibling != nil {
+			stack = append(stack, node.nextSibling)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (p *PairingHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(p.Length(), p.forEach, p.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (p *PairingHeap[V, P]) ordered() iter.Seq[*pairingNode[V, P]] {
+	priority := func(n *pairingNode[V, P]) P { return n.priority }
+	return orderedNodes(p.cmp, p.roots(), priority, func(n *pairingNode[V, P], visit func(*pairingNode[V, P])) {
+		for child := n.firstChild; child != nil; child = child.nextSibling {
+			visit(child)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (p *PairingHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(p.ordered(), func(n *pairingNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (p *PairingHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, p.ordered(), func(n *pairingNode[V, P]) HeapNode[V, P] {
+		return HeapNode[V, P]{value: n.value, priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its priority and drawing first-child and next-sibling links. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (p *PairingHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, append(treeRoots(p.root), treeRoots(p.aux)...),
+		func(node *pairingNode[V, P]) string {
+			return fmt.Sprint(node.priority)
+		},
+		func(node *pairingNode[V, P], edge func(dotEdge[*pairingNode[V, P]])) {
+			if node.firstChild != nil {
+				edge(dotEdge[*pairingNode[V, P]]{label: "child", to: node.firstChild})
+			}
+			if node.nextSibling != nil {
+				edge(dotEdge[*pairingNode[V, P]]{label: "sibling", to: node.nextSibling})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first. The comparison function is not encoded.
+func (p *PairingHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalHeap(p.Export(ExportOptions[V, P]{}))
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. The heap must have been created with its constructor so that
+// it has a comparison function to restore them with.
+func (p *PairingHeap[V, P]) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalHeap[V, P](data, p.cmp)
+	if err != nil {
+		return err
+	}
+
+	p.Clear()
+	for _, node := range decoded.Nodes {
+		p.Push(node.value, node.priority)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap in a compact binary format using
+// encoding/gob, for persisting the heap across process restarts. Elements are
+// written in internal order without sorting. The comparison function is not
+// encoded.
+func (p *PairingHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotNodes(p.Length(), p.forEach))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. The heap must have been created with its constructor so
+// that it has a comparison function to restore them with.
+func (p *PairingHeap[V, P]) Restore(data []byte) error {
+	if p.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	p.Clear()
+	for i, value := range snapshot.Values {
+		p.Push(value, snapshot.Priorities[i])
+	}
+	return nil
+}
+
 // Push adds a new element with its priority by creating a single-node heap
 // and melding it with the existing root. The new node becomes the root if
-// its priority is higher than the current root's priority.
+// its priority is higher than the current root's priority. In auxiliary mode
+// the node is prepended to the auxiliary list instead.
 func (p *PairingHeap[V, P]) Push(value V, priority P) {
 	newNode := p.pool.Get()
 	newNode.value = value
 	newNode.priority = priority
-	p.root = p.meld(newNode, p.root)
+	p.push(newNode)
 	p.size++
+	p.stats.record(OpPush, 1, p.size)
+	p.alarms.check(p.size)
+	emitHeapEvent(p.events, EventPush, "", value, priority)
+}
+
+// push links a single node into the heap, either by melding it with the root
+// or, in auxiliary mode, by prepending it to the auxiliary list.
+func (p *PairingHeap[V, P]) push(node *pairingNode[V, P]) {
+	if !p.auxiliary {
+		p.root = p.meld(node, p.root)
+		return
+	}
+	node.nextSibling = p.aux
+	p.aux = node
+	if p.auxBest == nil || p.cmp(node.priority, p.auxBest.priority) {
+		p.auxBest = node
+	}
+}
+
+// PushAll inserts all of the given elements into the heap. The elements are
+// linked into a subtree of their own, which is then melded into the heap
+// once. In auxiliary mode they are prepended to the auxiliary list instead.
+func (p *PairingHeap[V, P]) PushAll(data []HeapNode[V, P]) {
+	var subtree *pairingNode[V, P]
+	for i := range data {
+		node := p.pool.Get()
+		node.value = data[i].value
+		node.priority = data[i].priority
+		if p.auxiliary {
+			p.push(node)
+		} else {
+			subtree = p.meld(node, subtree)
+		}
+	}
+	p.root = p.meld(subtree, p.root)
+	p.size += len(data)
+	p.stats.record(OpPush, len(data), p.size)
+	p.alarms.check(p.size)
+	for i := range data {
+		emitHeapEvent(p.events, EventPush, "", data[i].value, data[i].priority)
+	}
+}
+
+// Meld merges another heap into this one by linking the two trees, after
+// flushing the auxiliary list of either heap. The other heap is consumed by
+// the operation and left empty. Both heaps are expected to share the same
+// comparison function.
+func (p *PairingHeap[V, P]) Meld(other *PairingHeap[V, P]) {
+	if other == nil || other == p {
+		return
+	}
+	p.flush()
+	other.flush()
+	if len(p.events) > 0 {
+		other.forEach(func(v V, pr P) { emitHeapEvent(p.events, EventPush, "", v, pr) })
+	}
+	p.root = p.meld(other.root, p.root)
+	p.size += other.size
+	p.stats.record(OpMeld, other.size, p.size)
+	p.alarms.check(p.size)
+	other.root = nil
+	other.Clear()
+}
+
+// replaceRoot removes the root and inserts value and priority in a single
+// pass: the root node is reused for the new element, which joins the root's
+// children in the sibling list before they are paired up. If the merge would
+// recurse deeper than the heap's MaxDepth, it returns ErrMaxDepthExceeded,
+// leaving the heap unchanged, when strict is true and merges without
+// recursion otherwise.
+func (p *PairingHeap[V, P]) replaceRoot(value V, priority P, strict bool) (V, P, error) {
+	p.flush()
+	node := p.root
+	children := node.firstChild
+	node.firstChild, node.nextSibling = nil, children
+	merge := p.merge
+	if p.mergeExceedsDepth(node) {
+		if strict {
+			node.firstChild, node.nextSibling = children, nil
+			v, pr := zeroValuePair[V, P]()
+			return v, pr, ErrMaxDepthExceeded
+		}
+		merge = p.mergeIteratively
+	}
+	v, pr := node.value, node.priority
+	node.value, node.priority = value, priority
+	p.root = merge(node)
+	return v, pr, nil
+}
+
+// popPush is an internal method that implements PopPush and TryPopPush.
+func (p *PairingHeap[V, P]) popPush(value V, priority P, strict bool) (V, P, error) {
+	if p.size == 0 {
+		return value, priority, nil
+	}
+	v, pr, err := p.replaceRoot(value, priority, strict)
+	if err != nil {
+		return v, pr, err
+	}
+	p.stats.record(OpPop, 1, p.size)
+	p.stats.record(OpPush, 1, p.size)
+	emitHeapEvent(p.events, EventPop, "", v, pr)
+	emitHeapEvent(p.events, EventPush, "", value, priority)
+	return v, pr, nil
+}
+
+// PopPush removes the root element and inserts a new element in one
+// operation, which is cheaper than a Pop followed by a Push. Returns the
+// removed root element. If the heap is empty, the new element is returned
+// without being inserted. PopPush cannot fail: if the merge would recurse
+// deeper than the heap's MaxDepth, it is done without recursion instead. Use
+// TryPopPush to be told.
+func (p *PairingHeap[V, P]) PopPush(value V, priority P) (V, P) {
+	v, pr, _ := p.popPush(value, priority, false)
+	return v, pr
+}
+
+// TryPopPush works like PopPush, but returns ErrMaxDepthExceeded, leaving the
+// heap unchanged, if the merge would recurse deeper than the heap's MaxDepth.
+func (p *PairingHeap[V, P]) TryPopPush(value V, priority P) (V, P, error) {
+	return p.popPush(value, priority, true)
+}
+
+// pushPop is an internal method that implements PushPop and TryPushPop.
+func (p *PairingHeap[V, P]) pushPop(value V, priority P, strict bool) (V, P, error) {
+	if p.size == 0 || p.cmp(priority, p.top().priority) {
+		return value, priority, nil
+	}
+	v, pr, err := p.replaceRoot(value, priority, strict)
+	if err != nil {
+		return v, pr, err
+	}
+	p.stats.record(OpPush, 1, p.size)
+	p.stats.record(OpPop, 1, p.size)
+	emitHeapEvent(p.events, EventPush, "", value, priority)
+	emitHeapEvent(p.events, EventPop, "", v, pr)
+	return v, pr, nil
+}
+
+// PushPop inserts a new element and removes the root element in one
+// operation. If the new element belongs at the root, it is returned directly
+// and the heap is left unchanged. Otherwise the old root element is returned.
+// Like PopPush, it cannot fail.
+func (p *PairingHeap[V, P]) PushPop(value V, priority P) (V, P) {
+	v, pr, _ := p.pushPop(value, priority, false)
+	return v, pr
+}
+
+// TryPushPop works like PushPop, but returns ErrMaxDepthExceeded, leaving the
+// heap unchanged, if the merge would recurse deeper than the heap's MaxDepth.
+func (p *PairingHeap[V, P]) TryPushPop(value V, priority P) (V, P, error) {
+	return p.pushPop(value, priority, true)
 }
@@ -1,43 +1,91 @@
 package heapcraft
 
+import "golang.org/x/exp/constraints"
+
 // NewFullPairingHeap creates a new pairing heap from a slice of HeapPairs.
 // The heap is initialized with the provided elements and uses the given comparison
 // function to determine heap order. The comparison function determines the heap order (min or max).
 // Returns an empty heap if the input slice is empty.
 func NewFullPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *FullPairingHeap[V, P] {
-	pool := newPool(config.UsePool, func() *pairingHeapNode[V, P] {
+	pool := newConfiguredPool(config, func() *pairingHeapNode[V, P] {
 		return &pairingHeapNode[V, P]{}
 	})
-	elements := make(map[string]*pairingHeapNode[V, P])
+	elements := make(map[string]*pairingHeapNode[V, P], config.sizeHint(len(data)))
 	heap := FullPairingHeap[V, P]{
-		cmp:      cmp,
-		size:     0,
-		elements: elements,
-		pool:     pool,
-		idGen:    config.GetGenerator(),
+		cmp:           cmp,
+		size:          0,
+		elements:      elements,
+		pool:          pool,
+		idGen:         config.GetGenerator(),
+		onValueUpdate: make(listeners[ValueUpdateEvent[V]]),
+		maxDepth:      config.MaxDepth,
 	}
 	if len(data) == 0 {
 		return &heap
 	}
 
-	for i := range data {
-		heap.Push(data[i].value, data[i].priority)
-	}
+	heap.PushAll(data)
 	return &heap
 }
 
-// NewPairingHeap creates a new simple pairing heap from a slice of HeapPairs.
-// Unlike PairingHeap, this implementation does not track node IDs or support
-// node updates. It uses the provided comparison function to determine heap order (min or max).
-// Returns an empty heap if the input slice is empty.
-func NewPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *PairingHeap[V, P] {
-	pool := newPool(usePool, func() *pairingNode[V, P] {
+// NewSimplePairingHeap creates a new simple pairing heap from a slice of
+// HeapPairs. Unlike FullPairingHeap, it does not track node IDs or support
+// node updates. Its nodes are allocated as config selects: from an arena if
+// ArenaSlabSize is positive, otherwise from the pool chosen by UsePool. Its
+// merges are limited to config.MaxDepth, and AuxiliaryPush selects the
+// auxiliary mode described on PairingHeap. The heap does not track IDs,
+// so IDGenerator is ignored.
+func NewSimplePairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *PairingHeap[V, P] {
+	pool := newConfiguredPool(config, func() *pairingNode[V, P] {
 		return &pairingNode[V, P]{}
 	})
-	heap := PairingHeap[V, P]{cmp: cmp, size: 0, pool: pool}
+	return newPairingHeap(data, cmp, pool, config)
+}
+
+// NewPairingHeap creates a new simple pairing heap from a slice of HeapPairs.
+//
+// Deprecated: use NewSimplePairingHeap with HeapConfig{UsePool: usePool}.
+func NewPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *PairingHeap[V, P] {
+	return NewSimplePairingHeap(data, cmp, HeapConfig{UsePool: usePool})
+}
+
+// NewArenaPairingHeap creates a simple pairing heap whose nodes are allocated
+// from slabs of slabSize nodes (DefaultArenaSlabSize if not positive) and
+// released all at once by Clear. It suits heaps that are built once and
+// drained, where per-node allocation dominates and sync.Pool cannot help.
+func NewArenaPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, slabSize int) *PairingHeap[V, P] {
+	return newPairingHeap(data, cmp, newArenaPool[pairingNode[V, P]](slabSize), HeapConfig{})
+}
+
+// NewAuxPairingHeap creates a simple pairing heap in auxiliary mode: Push
+// prepends new elements to an auxiliary list in strictly O(1) time without
+// touching the root, and the list is paired up in multiple passes and melded
+// into the tree on the next Pop. It suits push-heavy workloads that pop
+// rarely.
+//
+// Deprecated: use NewSimplePairingHeap with
+// HeapConfig{UsePool: usePool, AuxiliaryPush: true}.
+func NewAuxPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *PairingHeap[V, P] {
+	return NewSimplePairingHeap(data, cmp, HeapConfig{UsePool: usePool, AuxiliaryPush: true})
+}
+
+// newPairingHeap creates a simple pairing heap from data that allocates its
+// nodes from pool, with the MaxDepth and AuxiliaryPush settings of config.
+func newPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, pool pool[*pairingNode[V, P]], config HeapConfig) *PairingHeap[V, P] {
+	heap := PairingHeap[V, P]{
+		cmp:       cmp,
+		size:      0,
+		pool:      pool,
+		maxDepth:  config.MaxDepth,
+		auxiliary: config.AuxiliaryPush,
+	}
 	if len(data) == 0 {
 		return &heap
 	}
+	if heap.auxiliary {
+		heap.PushAll(data)
+		return &heap
+	}
 
 	for i := range data {
 		heap.Push(data[i].value, data[i].priority)
@@ -45,7 +93,7 @@ func NewPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool,
 	return &heap
 }
 
-// NewSyncPairingHeap creates a new thread-safe pairing heap from a slice of HeapPairs.
+// NewSyncFullPairingHeap creates a new thread-safe pairing heap from a slice of HeapPairs.
 // The heap is initialized with the provided elements and uses the given comparison
 // function to determine heap order. The comparison function determines the heap order (min or max).
 // Returns an empty heap if the input slice is empty.
@@ -53,10 +101,64 @@ func NewSyncFullPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P
 	return &SyncFullPairingHeap[V, P]{heap: NewFullPairingHeap(data, cmp, config)}
 }
 
+// NewSyncSimplePairingHeap creates a new thread-safe simple pairing heap with
+// its nodes allocated as config selects. See NewSimplePairingHeap.
+func NewSyncSimplePairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SyncPairingHeap[V, P] {
+	return &SyncPairingHeap[V, P]{heap: NewSimplePairingHeap(data, cmp, config)}
+}
+
 // NewSyncPairingHeap creates a new thread-safe simple pairing heap from a slice of HeapPairs.
-// Unlike SyncPairingHeap, this implementation does not track node IDs or support
-// node updates. It uses the provided comparison function to determine heap order (min or max).
-// Returns an empty heap if the input slice is empty.
+//
+// Deprecated: use NewSyncSimplePairingHeap with HeapConfig{UsePool: usePool}.
 func NewSyncPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncPairingHeap[V, P] {
-	return &SyncPairingHeap[V, P]{heap: NewPairingHeap(data, cmp, usePool)}
+	return NewSyncSimplePairingHeap(data, cmp, HeapConfig{UsePool: usePool})
+}
+
+// NewSyncAuxPairingHeap creates a new thread-safe simple pairing heap in
+// auxiliary mode, as described on PairingHeap.
+//
+// Deprecated: use NewSyncSimplePairingHeap with
+// HeapConfig{UsePool: usePool, AuxiliaryPush: true}.
+func NewSyncAuxPairingHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncPairingHeap[V, P] {
+	return NewSyncSimplePairingHeap(data, cmp, HeapConfig{UsePool: usePool, AuxiliaryPush: true})
+}
+
+// NewMinSimplePairingHeap creates a simple pairing min-heap from data, ordered
+// by the natural ordering of P, so the smallest priority is popped first.
+func NewMinSimplePairingHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *PairingHeap[V, P] {
+	return NewSimplePairingHeap(data, orderedLess[P], config)
+}
+
+// NewMaxSimplePairingHeap creates a simple pairing max-heap from data, ordered
+// by the natural ordering of P, so the largest priority is popped first.
+func NewMaxSimplePairingHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *PairingHeap[V, P] {
+	return NewSimplePairingHeap(data, orderedGreater[P], config)
+}
+
+// NewMinPairingHeap creates a pairing min-heap from data, ordered by the natural
+// ordering of P, so the smallest priority is popped first.
+//
+// Deprecated: use NewMinSimplePairingHeap with HeapConfig{UsePool: usePool}.
+func NewMinPairingHeap[V any, P constraints.Ordered](data []HeapNode[V, P], usePool bool) *PairingHeap[V, P] {
+	return NewMinSimplePairingHeap(data, HeapConfig{UsePool: usePool})
+}
+
+// NewMaxPairingHeap creates a pairing max-heap from data, ordered by the natural
+// ordering of P, so the largest priority is popped first.
+//
+// Deprecated: use NewMaxSimplePairingHeap with HeapConfig{UsePool: usePool}.
+func NewMaxPairingHeap[V any, P constraints.Ordered](data []HeapNode[V, P], usePool bool) *PairingHeap[V, P] {
+	return NewMaxSimplePairingHeap(data, HeapConfig{UsePool: usePool})
+}
+
+// NewMinFullPairingHeap creates a pairing min-heap with node tracking from data,
+// ordered by the natural ordering of P.
+func NewMinFullPairingHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *FullPairingHeap[V, P] {
+	return NewFullPairingHeap(data, orderedLess[P], config)
+}
+
+// NewMaxFullPairingHeap creates a pairing max-heap with node tracking from data,
+// ordered by the natural ordering of P.
+func NewMaxFullPairingHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *FullPairingHeap[V, P] {
+	return NewFullPairingHeap(data, orderedGreater[P], config)
 }
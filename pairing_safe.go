@@ -1,6 +1,7 @@
 package heapcraft
 
 import (
+	"io"
 	"sync"
 )
 
@@ -20,16 +21,79 @@ func (s *SyncFullPairingHeap[V, P]) UpdateValue(id string, value V) error {
 	return s.heap.UpdateValue(id, value)
 }
 
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the node ID and its old and new values. Listeners run while
+// the heap lock is held and must not call back into the heap. Returns an ID
+// that can be passed to RemoveListener.
+func (s *SyncFullPairingHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnValueUpdate(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncFullPairingHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
 // UpdatePriority updates the priority of a node with the given ID.
 // Returns an error if the ID does not exist in the heap.
-// The node is removed from its current position and reinserted into the heap
-// to maintain the heap property. This operation may change the heap structure.
+// The node is restructured only as far as the heap property requires.
 func (s *SyncFullPairingHeap[V, P]) UpdatePriority(id string, priority P) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return s.heap.UpdatePriority(id, priority)
 }
 
+// UpdatePriorityFunc replaces the priority of the node with the given ID with
+// fn applied to its current priority. The read and the update happen under a
+// single write lock, so no other goroutine can change the priority in
+// between. fn must not call back into the heap.
+func (s *SyncFullPairingHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UpdatePriorityFunc(id, fn)
+}
+
+// DecreasePriority gives the node with the given ID a priority that does not
+// come after its current one, keeping it in place when the heap property
+// allows. Returns ErrNodeNotFound or ErrPriorityNotDecreased on failure.
+func (s *SyncFullPairingHeap[V, P]) DecreasePriority(id string, priority P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DecreasePriority(id, priority)
+}
+
+// IncreasePriority gives the node with the given ID a priority that does not
+// come before its current one, keeping it in place when the heap property
+// allows. Returns ErrNodeNotFound or ErrPriorityNotIncreased on failure.
+func (s *SyncFullPairingHeap[V, P]) IncreasePriority(id string, priority P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.IncreasePriority(id, priority)
+}
+
+// FixID restores the heap order around the node with the given ID after its
+// priority has been changed in place. Returns an error if the ID does not
+// exist in the heap.
+func (s *SyncFullPairingHeap[V, P]) FixID(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.FixID(id)
+}
+
+// Remove deletes the node with the given ID and returns its value and priority.
+// Returns an error if the ID does not exist in the heap. The node's children
+// are merged back into the heap in its place.
+func (s *SyncFullPairingHeap[V, P]) Remove(id string) (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Remove(id)
+}
+
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
 // original and cloned heaps.
@@ -39,6 +103,20 @@ func (s *SyncFullPairingHeap[V, P]) Clone() *SyncFullPairingHeap[V, P] {
 	return &SyncFullPairingHeap[V, P]{heap: s.heap.Clone()}
 }
 
+// CloneFresh creates a thread-safe deep copy of the heap whose nodes get new
+// IDs from the heap's IDGenerator, and returns it with a mapping from each
+// original ID to its new one. It acquires a write lock, since the generator is
+// shared with the original heap.
+func (s *SyncFullPairingHeap[V, P]) CloneFresh() (*SyncFullPairingHeap[V, P], map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cloned, ids, err := s.heap.CloneFresh()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SyncFullPairingHeap[V, P]{heap: cloned}, ids, nil
+}
+
 // Clear removes all elements from the heap.
 // Resets the root to nil, size to zero, and initializes a new empty element map.
 // The next node ID is reset to 1.
@@ -48,6 +126,22 @@ func (s *SyncFullPairingHeap[V, P]) Clear() {
 	s.heap.Clear()
 }
 
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncFullPairingHeap[V, P]) PoolStats() PoolStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncFullPairingHeap[V, P]) ApproxMemoryUsage() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
 // Length returns the current number of elements in the heap.
 func (s *SyncFullPairingHeap[V, P]) Length() int {
 	s.mu.RLock()
@@ -55,6 +149,50 @@ func (s *SyncFullPairingHeap[V, P]) Length() int {
 	return s.heap.Length()
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (s *SyncFullPairingHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SyncFullPairingHeap[V, P]) RemoveDepthAlarm(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncFullPairingHeap[V, P]) Stats() HeapStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncFullPairingHeap[V, P]) Instrument(hook Instrumentation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncFullPairingHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
 // IsEmpty returns true if the heap contains no elements.
 func (s *SyncFullPairingHeap[V, P]) IsEmpty() bool {
 	s.mu.RLock()
@@ -62,6 +200,14 @@ func (s *SyncFullPairingHeap[V, P]) IsEmpty() bool {
 	return s.heap.IsEmpty()
 }
 
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncFullPairingHeap[V, P]) Verify() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Verify()
+}
+
 // Peek returns a HeapNode containing the value and priority
 // of the root node without removing it. Returns nil and an error if the heap is empty.
 func (s *SyncFullPairingHeap[V, P]) Peek() (V, P, error) {
@@ -119,6 +265,26 @@ func (s *SyncFullPairingHeap[V, P]) Pop() (V, P, error) {
 	return s.heap.Pop()
 }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncFullPairingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single write
+// lock. pred must not call back into the heap.
+func (s *SyncFullPairingHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return popWhile(s.heap.Peek, s.heap.Pop, pred)
+}
+
 // PopValue removes and returns just the value at the root.
 // The root's children are merged to form the new heap.
 // Returns zero value and an error if the heap is empty.
@@ -137,6 +303,88 @@ func (s *SyncFullPairingHeap[V, P]) PopPriority() (P, error) {
 	return s.heap.PopPriority()
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncFullPairingHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncFullPairingHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncFullPairingHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncFullPairingHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, in the same format as the underlying heap. It holds a read lock
+// until the whole array has been written.
+func (s *SyncFullPairingHeap[V, P]) StreamJSON(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.StreamJSON(w)
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format while holding a read lock.
+func (s *SyncFullPairingHeap[V, P]) WriteDOT(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.WriteDOT(w)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (s *SyncFullPairingHeap[V, P]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (s *SyncFullPairingHeap[V, P]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (s *SyncFullPairingHeap[V, P]) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (s *SyncFullPairingHeap[V, P]) Restore(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Restore(data)
+}
+
 // Push adds a new element with the given value and priority to the heap.
 // A new node is created with a unique ID and melded with the existing root.
 // The new node becomes the root if its priority is higher than the current root's.
@@ -147,6 +395,59 @@ func (s *SyncFullPairingHeap[V, P]) Push(value V, priority P) (string, error) {
 	return s.heap.Push(value, priority)
 }
 
+// PushWithID inserts a new element under an ID chosen by the caller. Returns
+// ErrDuplicateID if a node with the ID already exists.
+func (s *SyncFullPairingHeap[V, P]) PushWithID(id string, value V, priority P) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PushWithID(id, value, priority)
+}
+
+// GetOrPush returns the node with the given ID with true, or inserts the
+// given element under the ID and returns it with false. The lookup and the
+// insert happen under a single write lock.
+func (s *SyncFullPairingHeap[V, P]) GetOrPush(id string, value V, priority P) (V, P, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.GetOrPush(id, value, priority)
+}
+
+// PushIfAbsent inserts an element under the given ID only if no node with the
+// ID exists yet. Returns true if the element was inserted.
+func (s *SyncFullPairingHeap[V, P]) PushIfAbsent(id string, value V, priority P) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PushIfAbsent(id, value, priority)
+}
+
+// PopCommit passes the root element to commit and removes it only if commit
+// succeeds. commit runs while the heap lock is held, so no other goroutine can
+// claim the same element, and it must not call back into the heap.
+func (s *SyncFullPairingHeap[V, P]) PopCommit(commit func(id string, value V, priority P) error) (string, V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopCommit(commit)
+}
+
+// PushAll inserts all of the given elements into the heap under a single
+// write lock and returns their IDs in the same order.
+func (s *SyncFullPairingHeap[V, P]) PushAll(data []HeapNode[V, P]) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PushAll(data)
+}
+
+// Meld merges another thread-safe heap into this one. The other heap is
+// consumed by the operation and left empty. Locks are acquired in a consistent
+// order so that two heaps melded into each other concurrently cannot deadlock.
+func (s *SyncFullPairingHeap[V, P]) Meld(other *SyncFullPairingHeap[V, P]) error {
+	if other == nil || other == s {
+		return nil
+	}
+	defer lockPair(&s.mu, &other.mu)()
+	return s.heap.Meld(other.heap)
+}
+
 // SyncPairingHeap provides a thread-safe wrapper around PairingHeap.
 // It uses a read-write mutex to allow concurrent reads and exclusive writes.
 type SyncPairingHeap[V any, P any] struct {
@@ -173,6 +474,22 @@ func (s *SyncPairingHeap[V, P]) Clear() {
 	s.heap.Clear()
 }
 
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncPairingHeap[V, P]) PoolStats() PoolStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncPairingHeap[V, P]) ApproxMemoryUsage() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
 // Length returns the current number of elements in the simple heap.
 func (s *SyncPairingHeap[V, P]) Length() int {
 	s.mu.RLock()
@@ -180,6 +497,58 @@ func (s *SyncPairingHeap[V, P]) Length() int {
 	return s.heap.Length()
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (s *SyncPairingHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SyncPairingHeap[V, P]) RemoveDepthAlarm(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncPairingHeap[V, P]) Stats() HeapStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncPairingHeap[V, P]) Instrument(hook Instrumentation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncPairingHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncPairingHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
 // IsEmpty returns true if the simple heap contains no elements.
 func (s *SyncPairingHeap[V, P]) IsEmpty() bool {
 	s.mu.RLock()
@@ -187,6 +556,14 @@ func (s *SyncPairingHeap[V, P]) IsEmpty() bool {
 	return s.heap.IsEmpty()
 }
 
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncPairingHeap[V, P]) Verify() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Verify()
+}
+
 // Peek returns a HeapNode containing the value and priority
 // of the root node without removing it. Returns nil and an error if the heap is empty.
 func (s *SyncPairingHeap[V, P]) Peek() (V, P, error) {
@@ -220,6 +597,60 @@ func (s *SyncPairingHeap[V, P]) Pop() (V, P, error) {
 	return s.heap.Pop()
 }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncPairingHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single write
+// lock. pred must not call back into the heap.
+func (s *SyncPairingHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return popWhile(s.heap.Peek, s.heap.Pop, pred)
+}
+
+// PopPush removes the root element and inserts a new element in one
+// operation under a single write lock. Returns the removed root element. If
+// the heap is empty, the new element is returned without being inserted.
+func (s *SyncPairingHeap[V, P]) PopPush(value V, priority P) (V, P) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPush(value, priority)
+}
+
+// TryPopPush works like PopPush, but returns ErrMaxDepthExceeded, leaving the
+// heap unchanged, if a merge would recurse deeper than the heap's MaxDepth.
+func (s *SyncPairingHeap[V, P]) TryPopPush(value V, priority P) (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.TryPopPush(value, priority)
+}
+
+// PushPop inserts a new element and removes the root element in one
+// operation under a single write lock. If the new element belongs at the
+// root, it is returned directly. Otherwise the old root element is returned.
+func (s *SyncPairingHeap[V, P]) PushPop(value V, priority P) (V, P) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PushPop(value, priority)
+}
+
+// TryPushPop works like PushPop, but returns ErrMaxDepthExceeded, leaving the
+// heap unchanged, if a merge would recurse deeper than the heap's MaxDepth.
+func (s *SyncPairingHeap[V, P]) TryPushPop(value V, priority P) (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.TryPushPop(value, priority)
+}
+
 // PopValue removes and returns just the value at the root.
 // The root's children are merged to form the new heap.
 // Returns zero value and an error if the heap is empty.
@@ -238,6 +669,88 @@ func (s *SyncPairingHeap[V, P]) PopPriority() (P, error) {
 	return s.heap.PopPriority()
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncPairingHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncPairingHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncPairingHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncPairingHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, in the same format as the underlying heap. It holds a read lock
+// until the whole array has been written.
+func (s *SyncPairingHeap[V, P]) StreamJSON(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.StreamJSON(w)
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format while holding a read lock.
+func (s *SyncPairingHeap[V, P]) WriteDOT(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.WriteDOT(w)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (s *SyncPairingHeap[V, P]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (s *SyncPairingHeap[V, P]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (s *SyncPairingHeap[V, P]) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (s *SyncPairingHeap[V, P]) Restore(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Restore(data)
+}
+
 // Push adds a new element with its priority by creating a single-node heap
 // and melding it with the existing root. The new node becomes the root if
 // its priority is higher than the current root's priority.
@@ -246,3 +759,22 @@ func (s *SyncPairingHeap[V, P]) Push(value V, priority P) {
 	defer s.mu.Unlock()
 	s.heap.Push(value, priority)
 }
+
+// PushAll inserts all of the given elements into the heap under a single
+// write lock.
+func (s *SyncPairingHeap[V, P]) PushAll(data []HeapNode[V, P]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.PushAll(data)
+}
+
+// Meld merges another thread-safe heap into this one. The other heap is
+// consumed by the operation and left empty. Locks are acquired in a consistent
+// order so that two heaps melded into each other concurrently cannot deadlock.
+func (s *SyncPairingHeap[V, P]) Meld(other *SyncPairingHeap[V, P]) {
+	if other == nil || other == s {
+		return
+	}
+	defer lockPair(&s.mu, &other.mu)()
+	s.heap.Meld(other.heap)
+}
@@ -1,6 +1,7 @@
 package heapcraft
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -561,3 +562,33 @@ func TestSyncPairingHeap_IsEmpty(t *testing.T) {
 	heap.Clear()
 	assert.True(t, heap.IsEmpty())
 }
+
+func TestSyncPairingHeap_ConcurrentMeld(t *testing.T) {
+	h1 := NewSyncPairingHeap[int](nil, lt, false)
+	h2 := NewSyncPairingHeap[int](nil, lt, false)
+	h3 := NewSyncFullPairingHeap[int](nil, lt, HeapConfig{})
+	h4 := NewSyncFullPairingHeap[int](nil, lt, HeapConfig{})
+	for i := 0; i < 10; i++ {
+		h1.Push(i, i)
+		h2.Push(i+10, i+10)
+		h3.Push(i, i)
+		h4.Push(i+10, i+10)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		h1.Meld(h2)
+		assert.Nil(t, h3.Meld(h4))
+	}()
+	go func() {
+		defer wg.Done()
+		h2.Meld(h1)
+		assert.Nil(t, h4.Meld(h3))
+	}()
+	wg.Wait()
+
+	assert.Equal(t, 20, h1.Length()+h2.Length())
+	assert.Equal(t, 20, h3.Length()+h4.Length())
+}
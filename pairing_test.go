@@ -1,9 +1,12 @@
 package heapcraft
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPairingHeap_PopOrder(t *testing.T) {
@@ -353,8 +356,11 @@ func TestFullPairingHeap_UpdateValue(t *testing.T) {
 	assert.Equal(t, 100, node.value)
 
 	err = h.UpdateValue("non-existent-id", 100)
-	assert.NotNil(t, err)
-	assert.Equal(t, ErrNodeNotFound.Error(), err.Error())
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	var notFound *NodeNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "non-existent-id", notFound.ID)
+	assert.Equal(t, `id does not link to existing node: id "non-existent-id"`, err.Error())
 
 	popped, _, err := h.Pop()
 	assert.Nil(t, err)
@@ -416,6 +422,74 @@ func TestPairingHeapUpdatePriorityEdgeCases(t *testing.T) {
 	assert.Equal(t, 5, priority)
 }
 
+func TestFullPairingHeap_DecreaseIncreasePriority(t *testing.T) {
+	h := NewFullPairingHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	idA, _ := h.Push("a", 10)
+	idB, _ := h.Push("b", 20)
+	idC, _ := h.Push("c", 30)
+
+	assert.ErrorIs(t, h.DecreasePriority("missing", 1), ErrNodeNotFound)
+	assert.ErrorIs(t, h.IncreasePriority("missing", 1), ErrNodeNotFound)
+	assert.ErrorIs(t, h.DecreasePriority(idB, 25), ErrPriorityNotDecreased)
+	assert.ErrorIs(t, h.IncreasePriority(idB, 15), ErrPriorityNotIncreased)
+
+	// b and c are leaves below a, so neither change needs restructuring.
+	root, first := h.root, h.root.firstChild
+	require.NoError(t, h.IncreasePriority(idB, 40))
+	require.NoError(t, h.DecreasePriority(idC, 15))
+	assert.Same(t, root, h.root)
+	assert.Same(t, first, h.root.firstChild)
+	require.NoError(t, h.Verify())
+
+	require.NoError(t, h.IncreasePriority(idA, 50))
+	require.NoError(t, h.DecreasePriority(idB, 5))
+	require.NoError(t, h.Verify())
+	assert.Equal(t, []string{"b", "c", "a"}, h.DrainValues())
+}
+
+func TestFullPairingHeap_PriorityChangesMatchSortedOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	h := NewFullPairingHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	priorities := make(map[string]int)
+	ids := make([]string, 0)
+	for i := 0; i < 200; i++ {
+		id, _ := h.Push(i, rng.Intn(1000))
+		priorities[id], _ = h.GetPriority(id)
+		ids = append(ids, id)
+	}
+	for i := 0; i < 2000; i++ {
+		id := ids[rng.Intn(len(ids))]
+		p := rng.Intn(1000)
+		switch rng.Intn(4) {
+		case 0:
+			require.NoError(t, h.UpdatePriority(id, p))
+			priorities[id] = p
+		case 1:
+			if p <= priorities[id] {
+				require.NoError(t, h.DecreasePriority(id, p))
+				priorities[id] = p
+			}
+		case 2:
+			if p >= priorities[id] {
+				require.NoError(t, h.IncreasePriority(id, p))
+				priorities[id] = p
+			}
+		case 3:
+			h.elements[id].priority = p
+			require.NoError(t, h.FixID(id))
+			priorities[id] = p
+		}
+		require.NoError(t, h.Verify())
+	}
+
+	want := make([]int, 0, len(priorities))
+	for _, p := range priorities {
+		want = append(want, p)
+	}
+	slices.Sort(want)
+	assert.Equal(t, want, h.DrainPriorities())
+}
+
 func TestPairingHeapClone(t *testing.T) {
 	cmp := lt
 	h := NewFullPairingHeap([]HeapNode[int, int]{}, cmp, HeapConfig{UsePool: false})
@@ -436,6 +510,73 @@ func TestPairingHeapClone(t *testing.T) {
 	}
 }
 
+func TestPairingHeapUpdatePriorityWithChildren(t *testing.T) {
+	h := NewFullPairingHeap([]HeapNode[int, int]{}, lt, HeapConfig{UsePool: false})
+
+	// The node with priority 5 is a child of the root and has its own child
+	// with priority 6, which must not stay below it once 5 becomes 10.
+	id5, _ := h.Push(5, 5)
+	h.Push(6, 6)
+	h.Push(1, 1)
+
+	err := h.UpdatePriority(id5, 10)
+	assert.Nil(t, err)
+
+	for _, expected := range []int{1, 6, 10} {
+		priority, err := h.PopPriority()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, priority)
+	}
+}
+
+func TestPairingHeapCloneUpdateSibling(t *testing.T) {
+	h := NewFullPairingHeap([]HeapNode[int, int]{}, lt, HeapConfig{UsePool: false})
+	ids := make([]string, 0, 4)
+	for _, p := range []int{1, 2, 3, 4} {
+		id, _ := h.Push(p, p)
+		ids = append(ids, id)
+	}
+
+	// Every node is updated in its own clone, so that nodes that are not the
+	// first child of their parent are unlinked through their previous sibling.
+	for i, id := range ids {
+		clone := h.Clone()
+		err := clone.UpdatePriority(id, 0)
+		assert.Nil(t, err)
+
+		expected := []int{0}
+		for j := range ids {
+			if j != i {
+				expected = append(expected, j+1)
+			}
+		}
+		for _, p := range expected {
+			priority, err := clone.PopPriority()
+			assert.Nil(t, err)
+			assert.Equal(t, p, priority)
+		}
+		assert.True(t, clone.IsEmpty())
+	}
+
+	for _, expected := range []int{1, 2, 3, 4} {
+		priority, err := h.PopPriority()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, priority)
+	}
+}
+
+func TestPairingHeapCloneEmpty(t *testing.T) {
+	h := NewFullPairingHeap([]HeapNode[int, int]{}, lt, HeapConfig{UsePool: false})
+	clone := h.Clone()
+	assert.True(t, clone.IsEmpty())
+
+	clone.Push(1, 1)
+	priority, err := clone.PeekPriority()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, priority)
+	assert.True(t, h.IsEmpty())
+}
+
 func TestComplexHeapStructure(t *testing.T) {
 	h := NewFullPairingHeap([]HeapNode[int, int]{}, lt, HeapConfig{UsePool: false})
 
@@ -740,3 +881,289 @@ func BenchmarkPairingHeap_Deletion(b *testing.B) {
 		heap.Pop()
 	}
 }
+
+func TestPairingHeap_Meld(t *testing.T) {
+	h1 := NewPairingHeap([]HeapNode[int, int]{
+		CreateHeapNode(5, 5),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(9, 9),
+	}, lt, false)
+	h2 := NewPairingHeap([]HeapNode[int, int]{
+		CreateHeapNode(4, 4),
+		CreateHeapNode(0, 0),
+		CreateHeapNode(7, 7),
+	}, lt, false)
+
+	h1.Meld(h2)
+	assert.Equal(t, 6, h1.Length())
+	assert.True(t, h2.IsEmpty())
+	assert.Equal(t, []int{0, 1, 4, 5, 7, 9}, h1.DrainValues())
+
+	h1.Meld(h1)
+	h1.Meld(nil)
+	assert.True(t, h1.IsEmpty())
+}
+
+func TestFullPairingHeap_Meld(t *testing.T) {
+	gen := &IntegerIDGenerator{NextID: 0}
+	h1 := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: gen})
+	h2 := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: gen})
+	h1.Push(5, 5)
+	id1, _ := h1.Push(3, 3)
+	h2.Push(4, 4)
+	id2, _ := h2.Push(8, 8)
+
+	assert.Nil(t, h1.Meld(h2))
+	assert.Equal(t, 4, h1.Length())
+	assert.True(t, h2.IsEmpty())
+
+	// Nodes from the other heap remain addressable by ID.
+	assert.Nil(t, h1.UpdatePriority(id2, 1))
+	value, _ := h1.PeekValue()
+	assert.Equal(t, 8, value)
+	assert.Nil(t, h1.UpdatePriority(id1, 0))
+	assert.Equal(t, []int{3, 8, 4, 5}, h1.DrainValues())
+
+	// Melding heaps with overlapping IDs fails and leaves both untouched.
+	h3 := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	h4 := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	h3.Push(1, 1)
+	h4.Push(2, 2)
+	assert.ErrorIs(t, h3.Meld(h4), ErrDuplicateID)
+	assert.Equal(t, 1, h3.Length())
+	assert.Equal(t, 1, h4.Length())
+}
+
+func TestFullPairingHeap_Remove(t *testing.T) {
+	gen := &IntegerIDGenerator{NextID: 0}
+	h := NewFullPairingHeap[int, int](nil, lt, HeapConfig{IDGenerator: gen})
+	ids := make(map[int]string)
+	for _, v := range []int{5, 3, 8, 1, 9, 2, 7} {
+		id, _ := h.Push(v, v)
+		ids[v] = id
+	}
+
+	// Remove the root, a middle node and a leaf.
+	for _, v := range []int{1, 5, 9} {
+		value, priority, err := h.Remove(ids[v])
+		assert.Nil(t, err)
+		assert.Equal(t, v, value)
+		assert.Equal(t, v, priority)
+	}
+	assert.Equal(t, 4, h.Length())
+
+	_, _, err := h.Get(ids[5])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	_, _, err = h.Remove(ids[5])
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	assert.Equal(t, []int{2, 3, 7, 8}, h.DrainValues())
+
+	// Updating and removing the only node in the heap.
+	id, _ := h.Push(4, 4)
+	assert.Nil(t, h.UpdatePriority(id, 6))
+	priority, _ := h.PeekPriority()
+	assert.Equal(t, 6, priority)
+	_, _, err = h.Remove(id)
+	assert.Nil(t, err)
+	assert.True(t, h.IsEmpty())
+}
+
+func TestTrackedHeap_FixID(t *testing.T) {
+	type job struct{ priority int }
+	less := func(a, b *job) bool { return a.priority < b.priority }
+	type fixableHeap interface {
+		TrackedHeap[int, *job]
+		FixID(id string) error
+	}
+
+	heaps := map[string]func() fixableHeap{
+		"pairing":     func() fixableHeap { return NewFullPairingHeap[int, *job](nil, less, HeapConfig{}) },
+		"syncPairing": func() fixableHeap { return NewSyncFullPairingHeap[int, *job](nil, less, HeapConfig{}) },
+		"leftist":     func() fixableHeap { return NewFullLeftistHeap[int, *job](nil, less, HeapConfig{}) },
+		"syncLeftist": func() fixableHeap { return NewSyncFullLeftistHeap[int, *job](nil, less, HeapConfig{}) },
+		"skew":        func() fixableHeap { return NewFullSkewHeap[int, *job](nil, less, HeapConfig{}) },
+		"syncSkew":    func() fixableHeap { return NewSyncFullSkewHeap[int, *job](nil, less, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		h := constructor()
+		jobs := make(map[string]*job)
+		for i := 0; i < 40; i++ {
+			j := &job{priority: (i * 11) % 40}
+			id, err := h.Push(i, j)
+			assert.NoError(t, err, name)
+			jobs[id] = j
+		}
+		// Pop once so the tree heaps have some internal structure.
+		_, popped, _ := h.Pop()
+		for id, j := range jobs {
+			if j == popped {
+				delete(jobs, id)
+			}
+		}
+
+		for id, j := range jobs {
+			j.priority = (j.priority*17 + 3) % 60
+			assert.NoError(t, h.FixID(id), name)
+		}
+
+		last := -1
+		for !h.IsEmpty() {
+			_, p, _ := h.Pop()
+			assert.LessOrEqual(t, last, p.priority, name)
+			last = p.priority
+		}
+		assert.ErrorIs(t, h.FixID("missing"), ErrNodeNotFound, name)
+	}
+}
+
+func TestMinMaxPairingHeap(t *testing.T) {
+	data := []HeapNode[string, int]{
+		CreateHeapNode("b", 2), CreateHeapNode("a", 1), CreateHeapNode("c", 3),
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, NewMinPairingHeap(data, false).DrainValues())
+	assert.Equal(t, []string{"c", "b", "a"}, NewMaxPairingHeap(data, false).DrainValues())
+	assert.Equal(t, []string{"a", "b", "c"}, NewMinFullPairingHeap(data, HeapConfig{}).DrainValues())
+	assert.Equal(t, []string{"c", "b", "a"}, NewMaxFullPairingHeap(data, HeapConfig{}).DrainValues())
+}
+
+func TestAuxPairingHeap_MatchesSortedOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	h := NewAuxPairingHeap([]HeapNode[int, int]{
+		CreateHeapNode(50, 50),
+		CreateHeapNode(20, 20),
+	}, lt, true)
+	pending := []int{50, 20}
+	for i := 0; i < 3000; i++ {
+		p := rng.Intn(1000)
+		switch rng.Intn(6) {
+		case 0, 1:
+			h.Push(p, p)
+			pending = append(pending, p)
+		case 2:
+			batch := []HeapNode[int, int]{CreateHeapNode(p, p), CreateHeapNode(p+1, p+1)}
+			h.PushAll(batch)
+			pending = append(pending, p, p+1)
+		case 3:
+			slices.Sort(pending)
+			got, err := h.PopPriority()
+			if len(pending) == 0 {
+				assert.ErrorIs(t, err, ErrHeapEmpty)
+				continue
+			}
+			require.NoError(t, err)
+			assert.Equal(t, pending[0], got)
+			pending = pending[1:]
+		case 4:
+			slices.Sort(pending)
+			_, got := h.PushPop(p, p)
+			want := p
+			if len(pending) > 0 && pending[0] <= p {
+				want, pending[0] = pending[0], p
+			}
+			assert.Equal(t, want, got)
+		case 5:
+			slices.Sort(pending)
+			_, got := h.PopPush(p, p)
+			if len(pending) > 0 {
+				assert.Equal(t, pending[0], got)
+				pending[0] = p
+			}
+		}
+		require.NoError(t, h.Verify())
+		require.Equal(t, len(pending), h.Length())
+		if len(pending) > 0 {
+			top, err := h.PeekPriority()
+			require.NoError(t, err)
+			assert.Equal(t, slices.Min(pending), top)
+		}
+	}
+	slices.Sort(pending)
+	assert.Equal(t, pending, h.DrainPriorities())
+}
+
+func TestAuxPairingHeap_MeldCloneClear(t *testing.T) {
+	h1 := NewAuxPairingHeap([]HeapNode[int, int]{
+		CreateHeapNode(5, 5),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(9, 9),
+	}, lt, true)
+	h2 := NewAuxPairingHeap([]HeapNode[int, int]{
+		CreateHeapNode(4, 4),
+		CreateHeapNode(0, 0),
+	}, lt, true)
+	h2.Push(7, 7)
+
+	clone := h1.Clone()
+	require.NoError(t, clone.Verify())
+	var ordered []int
+	for v := range clone.Ordered() {
+		ordered = append(ordered, v)
+	}
+	assert.Equal(t, []int{1, 5, 9}, ordered)
+
+	h1.Meld(h2)
+	require.NoError(t, h1.Verify())
+	assert.True(t, h2.IsEmpty())
+	h2.Push(3, 3)
+	assert.Equal(t, []int{0, 1, 4, 5, 7, 9}, h1.DrainValues())
+	assert.Equal(t, []int{3}, h2.DrainValues())
+
+	assert.Equal(t, []int{1, 5, 9}, clone.DrainValues())
+	clone.Push(2, 2)
+	clone.Clear()
+	require.NoError(t, clone.Verify())
+	_, _, err := clone.Peek()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestSimplePairingHeap_AuxiliaryPush(t *testing.T) {
+	config := NewHeapConfig(WithAuxiliaryPush(), WithArena(8))
+	h := NewSimplePairingHeap([]HeapNode[int, int]{
+		CreateHeapNode(5, 5),
+		CreateHeapNode(1, 1),
+	}, lt, config)
+	assert.True(t, h.auxiliary)
+	h.Push(3, 3)
+	assert.Nil(t, h.root)
+	require.NoError(t, h.Verify())
+	assert.Equal(t, []int{1, 3, 5}, h.DrainValues())
+
+	sh := NewSyncSimplePairingHeap[int, int](nil, lt, config)
+	assert.True(t, sh.heap.auxiliary)
+	assert.False(t, NewSimplePairingHeap[int, int](nil, lt, HeapConfig{}).auxiliary)
+}
+
+func TestSyncAuxPairingHeap(t *testing.T) {
+	h := NewSyncAuxPairingHeap[string, int](nil, gt, false)
+	h.Push("a", 1)
+	h.Push("c", 3)
+	h.Push("b", 2)
+	v, err := h.PeekValue()
+	require.NoError(t, err)
+	assert.Equal(t, "c", v)
+	assert.Equal(t, []string{"c", "b", "a"}, h.DrainValues())
+}
+
+// benchmarkPushHeavy pushes b.N elements and pops one in every hundred of
+// them, a workload where most of the work is in Push.
+func benchmarkPushHeavy(b *testing.B, heap *PairingHeap[int, int]) {
+	insertions := generateRandomNumbersv1(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(insertions[i], insertions[i])
+		if i%100 == 99 {
+			heap.Pop()
+		}
+	}
+}
+
+func BenchmarkPairingHeap_PushHeavy(b *testing.B) {
+	benchmarkPushHeavy(b, NewPairingHeap[int, int](nil, lt, true))
+}
+
+func BenchmarkAuxPairingHeap_PushHeavy(b *testing.B) {
+	benchmarkPushHeavy(b, NewAuxPairingHeap[int, int](nil, lt, true))
+}
@@ -1,50 +1,187 @@
 package heapcraft
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
+// PoolStats reports how a heap's node pool has been used since the heap was
+// created. Hits counts nodes that were handed out again after being released,
+// Misses counts nodes that had to be allocated, and Releases counts nodes that
+// were returned to the pool for reuse. A heap created without pooling or with
+// an arena never reuses a node, so it only ever reports misses.
+type PoolStats struct {
+	Hits     uint64
+	Misses   uint64
+	Releases uint64
+}
+
+// pool is the node allocator used by every heap. Pools are never shared
+// between heaps: a cloned heap receives a fresh pool of the same kind via
+// fresh, so nodes released by one heap can never be handed out to another
+// heap that may still reference them.
 type pool[T any] interface {
 	Get() T
 	Put(node T)
+	fresh() pool[T]
+	stats() PoolStats
+	// idle returns the number of nodes the pool holds that are not in use.
+	idle() int
 }
 
-// syncPool is a pool that uses a sync.Pool to store the nodes.
-type syncPool[T any] struct{ pool sync.Pool }
-
-// Get returns a node from the pool.
-func (p *syncPool[T]) Get() T { return p.pool.Get().(T) }
-
-// Put returns a node to the pool
-func (p *syncPool[T]) Put(node T) { p.pool.Put(node) }
-
 // defaultPool is a pool that uses a constructor function to create a new node.
 // this is the default pool used by the heapcraft package, where the nodes are
 // created on the fly.
-type defaultPool[T any] struct{ constructor func() T }
+type defaultPool[T any] struct {
+	constructor func() T
+	misses      atomic.Uint64
+}
 
 // Get just generates a new node based on the constructor function.
-func (p *defaultPool[T]) Get() T { return p.constructor() }
+func (p *defaultPool[T]) Get() T {
+	p.misses.Add(1)
+	return p.constructor()
+}
 
 // Put is a no-op for the default pool.
 func (p *defaultPool[T]) Put(node T) {}
 
+// stats reports every node as a miss, since none are reused.
+func (p *defaultPool[T]) stats() PoolStats { return PoolStats{Misses: p.misses.Load()} }
+
+// idle reports no nodes, since the default pool keeps none.
+func (p *defaultPool[T]) idle() int { return 0 }
+
+// fresh returns a new default pool using the same constructor.
+func (p *defaultPool[T]) fresh() pool[T] { return newDefaultPool(p.constructor) }
+
 // newDefaultPool creates a new default pool with the given constructor function.
 func newDefaultPool[T any](constructor func() T) pool[T] {
 	return &defaultPool[T]{constructor: constructor}
 }
 
-// newSyncPool creates a new sync pool with the given constructor function.
-func newSyncPool[T any](constructor func() T) pool[T] {
-	return &syncPool[T]{
-		pool: sync.Pool{
-			New: func() any { return constructor() },
-		},
+// freeListPool is a pool that keeps released nodes in a bounded free list
+// guarded by a mutex. Unlike sync.Pool it never drops nodes on its own, so
+// the list is capped at maxFreeNodes to keep idle memory bounded.
+type freeListPool[T any] struct {
+	mu          sync.Mutex
+	free        []T
+	constructor func() T
+	counts      PoolStats
+}
+
+// maxFreeNodes is the maximum number of released nodes a freeListPool keeps.
+const maxFreeNodes = 1024
+
+// Get returns a released node if one is available, otherwise a new node from
+// the constructor function.
+func (p *freeListPool[T]) Get() T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n := len(p.free); n > 0 {
+		node := p.free[n-1]
+		var zero T
+		p.free[n-1] = zero
+		p.free = p.free[:n-1]
+		p.counts.Hits++
+		return node
 	}
+	p.counts.Misses++
+	return p.constructor()
+}
+
+// Put returns a node to the free list, dropping it if the list is full.
+func (p *freeListPool[T]) Put(node T) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.free) < maxFreeNodes {
+		p.free = append(p.free, node)
+		p.counts.Releases++
+	}
+}
+
+// stats returns the pool's counters under its lock.
+func (p *freeListPool[T]) stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts
 }
 
-// newPool creates a new pool based on the usePool flag.
+// idle returns the length of the free list under the pool's lock.
+func (p *freeListPool[T]) idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.free)
+}
+
+// fresh returns a new, empty free list pool using the same constructor.
+func (p *freeListPool[T]) fresh() pool[T] { return newFreeListPool(p.constructor) }
+
+// newFreeListPool creates a new free list pool with the given constructor
+// function.
+func newFreeListPool[T any](constructor func() T) pool[T] {
+	return &freeListPool[T]{constructor: constructor}
+}
+
+// newPool creates a new pool based on the usePool flag. When pooling is
+// enabled the pool is backed by sync.Pool, or by a free list under TinyGo (see
+// pool_tinygo.go).
 func newPool[T any](usePool bool, constructor func() T) pool[T] {
 	if usePool {
 		return newSyncPool(constructor)
 	}
 	return newDefaultPool(constructor)
 }
+
+// newConfiguredPool creates the node pool of a tracked heap from its config:
+// an arena pool if ArenaSlabSize is positive, with a first slab of at least
+// Capacity nodes, otherwise the pool chosen by UsePool.
+func newConfiguredPool[N any](config HeapConfig, constructor func() *N) pool[*N] {
+	if config.ArenaSlabSize > 0 {
+		arena := &arenaPool[N]{slabSize: config.ArenaSlabSize}
+		arena.reserve(config.Capacity)
+		return arena
+	}
+	return newPool(config.UsePool, constructor)
+}
+
+// releaseTree returns every node of the trees rooted at roots to p, after
+// zeroing it so that the pool holds no links or values. children reports the
+// nodes directly below a node; they are visited with an explicit stack, so a
+// degenerate tree cannot overflow the goroutine stack. Nothing is walked when
+// p does not reuse nodes, and an arena pool is reset in one step instead.
+func releaseTree[T any](p pool[*T], roots []*T, children func(node *T, visit func(*T))) {
+	if _, ok := p.(*defaultPool[*T]); ok {
+		return
+	}
+	if arena, ok := p.(resetter); ok {
+		arena.reset()
+		return
+	}
+	stack := roots
+	push := func(child *T) { stack = append(stack, child) }
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		children(node, push)
+		*node = *new(T)
+		p.Put(node)
+	}
+}
+
+// releaseElements returns every node of a tracked heap's element map to p,
+// after zeroing it so that the pool holds no links or values. An arena pool is
+// reset in one step instead.
+func releaseElements[T any](p pool[*T], elements map[string]*T) {
+	if _, ok := p.(*defaultPool[*T]); ok {
+		return
+	}
+	if arena, ok := p.(resetter); ok {
+		arena.reset()
+		return
+	}
+	for _, node := range elements {
+		*node = *new(T)
+		p.Put(node)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !tinygo
+
+package heapcraft
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// syncPool is a pool that uses a sync.Pool to store the nodes. Misses are
+// counted by the sync.Pool's New function, which only runs inside Get.
+type syncPool[T any] struct {
+	pool        sync.Pool
+	constructor func() T
+	gets        atomic.Uint64
+	misses      atomic.Uint64
+	releases    atomic.Uint64
+}
+
+// Get returns a node from the pool.
+func (p *syncPool[T]) Get() T {
+	p.gets.Add(1)
+	return p.pool.Get().(T)
+}
+
+// Put returns a node to the pool
+func (p *syncPool[T]) Put(node T) {
+	p.releases.Add(1)
+	p.pool.Put(node)
+}
+
+// stats returns the pool's counters. Misses are loaded before gets, so hits
+// never underflow while other goroutines use the pool.
+func (p *syncPool[T]) stats() PoolStats {
+	misses := p.misses.Load()
+	return PoolStats{Hits: p.gets.Load() - misses, Misses: misses, Releases: p.releases.Load()}
+}
+
+// idle returns the number of nodes released and not handed out again. This
+// is an upper bound, since sync.Pool may drop nodes during garbage collection.
+func (p *syncPool[T]) idle() int {
+	stats := p.stats()
+	return int(max(stats.Releases, stats.Hits) - stats.Hits)
+}
+
+// fresh returns a new, empty sync pool using the same constructor.
+func (p *syncPool[T]) fresh() pool[T] { return newSyncPool(p.constructor) }
+
+// newSyncPool creates a new sync pool with the given constructor function.
+func newSyncPool[T any](constructor func() T) pool[T] {
+	p := &syncPool[T]{constructor: constructor}
+	p.pool.New = func() any {
+		p.misses.Add(1)
+		return constructor()
+	}
+	return p
+}
@@ -1,9 +1,12 @@
 package heapcraft
 
 import (
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestNode is a simple struct for testing the pool functionality
@@ -78,6 +81,8 @@ func TestPoolInterface(t *testing.T) {
 	testPoolInterface(t, defaultPool, "defaultPool")
 	syncPool := newSyncPool(constructor)
 	testPoolInterface(t, syncPool, "syncPool")
+	freeListPool := newFreeListPool(constructor)
+	testPoolInterface(t, freeListPool, "freeListPool")
 }
 
 // testPoolInterface is a helper function to test pool interface methods
@@ -105,3 +110,492 @@ func TestPoolConstructorFunctions(t *testing.T) {
 	pool2 := newPool(false, constructor)
 	assert.NotNil(t, pool2)
 }
+
+// TestPoolFresh tests that fresh returns an independent pool of the same kind
+func TestPoolFresh(t *testing.T) {
+	constructor := func() TestNode {
+		return TestNode{Value: 7}
+	}
+
+	for _, usePool := range []bool{true, false} {
+		original := newPool(usePool, constructor)
+		fresh := original.fresh()
+		assert.NotSame(t, original, fresh)
+		assert.IsType(t, original, fresh)
+		assert.Equal(t, 7, fresh.Get().Value)
+	}
+}
+
+// TestFreeListPool tests the bounded free list pool used under TinyGo
+func TestFreeListPool(t *testing.T) {
+	constructor := func() *TestNode {
+		return &TestNode{Value: 9}
+	}
+
+	pool := newFreeListPool(constructor)
+
+	node := pool.Get()
+	node.Value = 1
+	pool.Put(node)
+	assert.Same(t, node, pool.Get())
+	assert.Equal(t, 9, pool.Get().Value)
+
+	for i := 0; i < maxFreeNodes+10; i++ {
+		pool.Put(&TestNode{})
+	}
+	assert.Len(t, pool.(*freeListPool[*TestNode]).free, maxFreeNodes)
+
+	fresh := pool.fresh()
+	assert.IsType(t, pool, fresh)
+	assert.Empty(t, fresh.(*freeListPool[*TestNode]).free)
+}
+
+// stressHeap is the subset of heap operations exercised by the pooled clone
+// stress test.
+type stressHeap[H any] interface {
+	Heap[int, int]
+	Clone() H
+}
+
+// runPooledCloneStress interleaves pushes, pops and clones on a pooled heap and
+// its clones, checking every heap against a sorted reference after each step.
+func runPooledCloneStress[H stressHeap[H]](t *testing.T, name string, heap H) {
+	r := rand.New(rand.NewSource(3))
+	heaps := []H{heap}
+	refs := [][]int{{}}
+
+	for step := 0; step < 2000; step++ {
+		i := r.Intn(len(heaps))
+		switch op := r.Intn(10); {
+		case op < 5:
+			v := r.Intn(1000)
+			heaps[i].Push(v, v)
+			refs[i] = append(refs[i], v)
+			sort.Ints(refs[i])
+		case op < 9:
+			v, err := heaps[i].PopValue()
+			if len(refs[i]) == 0 {
+				assert.ErrorIs(t, err, ErrHeapEmpty, name)
+				continue
+			}
+			if !assert.Equal(t, refs[i][0], v, name) {
+				return
+			}
+			refs[i] = refs[i][1:]
+		default:
+			if len(heaps) < 8 {
+				heaps = append(heaps, heaps[i].Clone())
+				refs = append(refs, append([]int(nil), refs[i]...))
+			}
+		}
+	}
+
+	for i := range heaps {
+		assert.Equal(t, len(refs[i]), heaps[i].Length(), name)
+	}
+}
+
+func TestPooledCloneStress(t *testing.T) {
+	runPooledCloneStress(t, "dary", NewDaryHeap[int, int](3, nil, lt, true))
+	runPooledCloneStress(t, "pairing", NewPairingHeap[int, int](nil, lt, true))
+	runPooledCloneStress(t, "leftist", NewLeftistHeap[int, int](nil, lt, true))
+	runPooledCloneStress(t, "skew", NewSkewHeap[int, int](nil, lt, true))
+	runPooledCloneStress(t, "binomial", NewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}))
+	runPooledCloneStress(t, "adaptive", NewAdaptiveHeap[int, int](nil, lt, HeapConfig{UsePool: true}))
+}
+
+func TestPooledCloneStressTracked(t *testing.T) {
+	config := HeapConfig{UsePool: true}
+	heaps := map[string]func() TrackedHeap[int, int]{
+		"pairing": func() TrackedHeap[int, int] { return NewFullPairingHeap[int, int](nil, lt, config) },
+		"leftist": func() TrackedHeap[int, int] { return NewFullLeftistHeap[int, int](nil, lt, config) },
+		"skew":    func() TrackedHeap[int, int] { return NewFullSkewHeap[int, int](nil, lt, config) },
+	}
+	clone := func(h TrackedHeap[int, int]) TrackedHeap[int, int] {
+		switch h := h.(type) {
+		case *FullPairingHeap[int, int]:
+			return h.Clone()
+		case *FullLeftistHeap[int, int]:
+			return h.Clone()
+		case *FullSkewHeap[int, int]:
+			return h.Clone()
+		}
+		return nil
+	}
+
+	for name, constructor := range heaps {
+		r := rand.New(rand.NewSource(5))
+		original := constructor()
+		ids := make([]string, 0)
+		for i := 0; i < 200; i++ {
+			id, err := original.Push(r.Intn(1000), r.Intn(1000))
+			assert.Nil(t, err, name)
+			ids = append(ids, id)
+		}
+
+		cloned := clone(original)
+		for i := 0; i < 100; i++ {
+			original.Pop()
+			cloned.UpdatePriority(ids[r.Intn(len(ids))], r.Intn(1000))
+			cloned.Pop()
+			original.Push(r.Intn(1000), r.Intn(1000))
+			if i%10 == 0 {
+				original, cloned = cloned, clone(original)
+			}
+		}
+
+		for _, h := range []TrackedHeap[int, int]{original, cloned} {
+			last := -1
+			for !h.IsEmpty() {
+				p, err := h.PopPriority()
+				assert.Nil(t, err, name)
+				assert.GreaterOrEqual(t, p, last, name)
+				last = p
+			}
+		}
+
+		empty := constructor()
+		assert.True(t, clone(empty).IsEmpty(), name)
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	constructor := func() *TestNode { return &TestNode{} }
+
+	plain := newDefaultPool(constructor)
+	plain.Put(plain.Get())
+	plain.Get()
+	assert.Equal(t, PoolStats{Misses: 2}, plain.stats())
+
+	free := newFreeListPool(constructor)
+	node := free.Get()
+	free.Put(node)
+	free.Get()
+	free.Get()
+	assert.Equal(t, PoolStats{Hits: 1, Misses: 2, Releases: 1}, free.stats())
+
+	pooled := newSyncPool(constructor)
+	for i := 0; i < 5; i++ {
+		pooled.Put(pooled.Get())
+	}
+	stats := pooled.stats()
+	assert.Equal(t, uint64(5), stats.Hits+stats.Misses)
+	assert.Equal(t, uint64(5), stats.Releases)
+	assert.Equal(t, PoolStats{}, pooled.fresh().stats())
+}
+
+func TestClearReleasesNodes(t *testing.T) {
+	// Each heap gets a free list pool so that reuse is deterministic, unlike
+	// sync.Pool, which may drop released nodes at any garbage collection.
+	binomial := NewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	binomial.pool = newFreeListPool(func() *binomialNode[int, int] { return &binomialNode[int, int]{} })
+	pairing := NewPairingHeap[int, int](nil, lt, true)
+	pairing.pool = newFreeListPool(func() *pairingNode[int, int] { return &pairingNode[int, int]{} })
+	leftist := NewLeftistHeap[int, int](nil, lt, true)
+	leftist.pool = newFreeListPool(func() *leftistNode[int, int] { return &leftistNode[int, int]{} })
+	skew := NewSkewHeap[int, int](nil, lt, true)
+	skew.pool = newFreeListPool(func() *skewNode[int, int] { return &skewNode[int, int]{} })
+	fullPairing := NewFullPairingHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	fullPairing.pool = newFreeListPool(func() *pairingHeapNode[int, int] { return &pairingHeapNode[int, int]{} })
+	fullLeftist := NewFullLeftistHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	fullLeftist.pool = newFreeListPool(func() *leftistHeapNode[int, int] { return &leftistHeapNode[int, int]{} })
+	fullSkew := NewFullSkewHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	fullSkew.pool = newFreeListPool(func() *skewHeapNode[int, int] { return &skewHeapNode[int, int]{} })
+
+	type pooledHeap interface {
+		BaseHeap[int, int]
+		PoolReporter
+		Verifier
+	}
+	heaps := map[string]struct {
+		pooledHeap
+		push func(v, p int)
+	}{
+		"binomial":    {binomial, binomial.Push},
+		"pairing":     {pairing, pairing.Push},
+		"leftist":     {leftist, leftist.Push},
+		"skew":        {skew, skew.Push},
+		"fullPairing": {fullPairing, func(v, p int) { fullPairing.Push(v, p) }},
+		"fullLeftist": {fullLeftist, func(v, p int) { fullLeftist.Push(v, p) }},
+		"fullSkew":    {fullSkew, func(v, p int) { fullSkew.Push(v, p) }},
+	}
+	const n = 100
+	for name, heap := range heaps {
+		for round := 0; round < 3; round++ {
+			for i := 0; i < n; i++ {
+				heap.push(i, (i*37)%n)
+			}
+			heap.Clear()
+			require.NoError(t, heap.Verify(), name)
+		}
+		assert.Equal(t, PoolStats{Hits: 2 * n, Misses: n, Releases: 3 * n}, heap.PoolStats(), name)
+
+		heap.push(1, 1)
+		heap.push(0, 0)
+		v, err := heap.PopValue()
+		require.NoError(t, err, name)
+		assert.Equal(t, 0, v, name)
+	}
+}
+
+func TestClearWithoutPool(t *testing.T) {
+	heap := NewSyncSkewHeap[int, int](nil, lt, false)
+	for i := 0; i < 10; i++ {
+		heap.Push(i, i)
+	}
+	heap.Clear()
+	assert.Equal(t, PoolStats{Misses: 10}, heap.PoolStats())
+}
+
+func TestMeldDoesNotReleaseMeldedNodes(t *testing.T) {
+	// Free list pools make reuse deterministic, so a node released by the
+	// consumed heap would be handed straight back to its next Push.
+	type meldedHeap interface {
+		BaseHeap[int, int]
+		Verifier
+	}
+	type meldCase struct {
+		receiver, other meldedHeap
+		pushReceiver    func(v, p int)
+		pushOther       func(v, p int)
+		meld            func()
+	}
+	config := HeapConfig{UsePool: true}
+	cases := map[string]func() meldCase{
+		"binomial": func() meldCase {
+			r, o := NewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}), NewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+			r.pool = newFreeListPool(func() *binomialNode[int, int] { return &binomialNode[int, int]{} })
+			o.pool = newFreeListPool(func() *binomialNode[int, int] { return &binomialNode[int, int]{} })
+			return meldCase{r, o, r.Push, o.Push, func() { r.Meld(o) }}
+		},
+		"pairing": func() meldCase {
+			r, o := NewPairingHeap[int, int](nil, lt, true), NewPairingHeap[int, int](nil, lt, true)
+			r.pool = newFreeListPool(func() *pairingNode[int, int] { return &pairingNode[int, int]{} })
+			o.pool = newFreeListPool(func() *pairingNode[int, int] { return &pairingNode[int, int]{} })
+			return meldCase{r, o, r.Push, o.Push, func() { r.Meld(o) }}
+		},
+		"leftist": func() meldCase {
+			r, o := NewLeftistHeap[int, int](nil, lt, true), NewLeftistHeap[int, int](nil, lt, true)
+			r.pool = newFreeListPool(func() *leftistNode[int, int] { return &leftistNode[int, int]{} })
+			o.pool = newFreeListPool(func() *leftistNode[int, int] { return &leftistNode[int, int]{} })
+			return meldCase{r, o, r.Push, o.Push, func() { r.Meld(o) }}
+		},
+		"skew": func() meldCase {
+			r, o := NewSkewHeap[int, int](nil, lt, true), NewSkewHeap[int, int](nil, lt, true)
+			r.pool = newFreeListPool(func() *skewNode[int, int] { return &skewNode[int, int]{} })
+			o.pool = newFreeListPool(func() *skewNode[int, int] { return &skewNode[int, int]{} })
+			return meldCase{r, o, r.Push, o.Push, func() { r.Meld(o) }}
+		},
+		"fullPairing": func() meldCase {
+			r, o := NewFullPairingHeap[int, int](nil, lt, config), NewFullPairingHeap[int, int](nil, lt, config)
+			r.pool = newFreeListPool(func() *pairingHeapNode[int, int] { return &pairingHeapNode[int, int]{} })
+			o.pool = newFreeListPool(func() *pairingHeapNode[int, int] { return &pairingHeapNode[int, int]{} })
+			return meldCase{
+				r, o,
+				func(v, p int) { r.Push(v, p) },
+				func(v, p int) { o.Push(v, p) },
+				func() { require.NoError(t, r.Meld(o)) },
+			}
+		},
+		"fullLeftist": func() meldCase {
+			r, o := NewFullLeftistHeap[int, int](nil, lt, config), NewFullLeftistHeap[int, int](nil, lt, config)
+			r.pool = newFreeListPool(func() *leftistHeapNode[int, int] { return &leftistHeapNode[int, int]{} })
+			o.pool = newFreeListPool(func() *leftistHeapNode[int, int] { return &leftistHeapNode[int, int]{} })
+			return meldCase{
+				r, o,
+				func(v, p int) { r.Push(v, p) },
+				func(v, p int) { o.Push(v, p) },
+				func() { require.NoError(t, r.Meld(o)) },
+			}
+		},
+		"fullSkew": func() meldCase {
+			r, o := NewFullSkewHeap[int, int](nil, lt, config), NewFullSkewHeap[int, int](nil, lt, config)
+			r.pool = newFreeListPool(func() *skewHeapNode[int, int] { return &skewHeapNode[int, int]{} })
+			o.pool = newFreeListPool(func() *skewHeapNode[int, int] { return &skewHeapNode[int, int]{} })
+			return meldCase{
+				r, o,
+				func(v, p int) { r.Push(v, p) },
+				func(v, p int) { o.Push(v, p) },
+				func() { require.NoError(t, r.Meld(o)) },
+			}
+		},
+	}
+
+	const n = 10
+	for name, build := range cases {
+		c := build()
+		for i := 0; i < n; i++ {
+			c.pushReceiver(2*i, 2*i)
+			c.pushOther(2*i+1, 2*i+1)
+		}
+		c.meld()
+		c.other.Clear()
+		for i := 0; i < n; i++ {
+			c.pushOther(100+i, 100+i)
+		}
+		c.pushReceiver(-1, -1)
+
+		require.NoError(t, c.receiver.Verify(), name)
+		require.NoError(t, c.other.Verify(), name)
+		assert.Equal(t, n, c.other.Length(), name)
+		for expected := -1; expected < 2*n; expected++ {
+			p, err := c.receiver.PopPriority()
+			require.NoError(t, err, name)
+			assert.Equal(t, expected, p, name)
+		}
+		assert.True(t, c.receiver.IsEmpty(), name)
+	}
+}
+func TestArenaPool(t *testing.T) {
+	p := newArenaPool[TestNode](2)
+	a, b, c := p.Get(), p.Get(), p.Get()
+	assert.NotSame(t, a, b)
+	assert.NotSame(t, b, c)
+	assert.Len(t, p.(*arenaPool[TestNode]).slab, 1)
+
+	p.Put(b)
+	assert.NotSame(t, b, p.Get())
+	assert.Equal(t, PoolStats{Misses: 4}, p.stats())
+
+	p.(resetter).reset()
+	assert.Empty(t, p.(*arenaPool[TestNode]).slab)
+	assert.NotSame(t, a, p.Get())
+	assert.Equal(t, 2, p.fresh().(*arenaPool[TestNode]).slabSize)
+	assert.Equal(t, DefaultArenaSlabSize, newArenaPool[TestNode](0).(*arenaPool[TestNode]).slabSize)
+}
+
+func TestArenaHeaps(t *testing.T) {
+	runPooledCloneStress(t, "arenaPairing", NewArenaPairingHeap[int, int](nil, lt, 16))
+	runPooledCloneStress(t, "arenaLeftist", NewArenaLeftistHeap[int, int](nil, lt, 16))
+	runPooledCloneStress(t, "arenaSkew", NewArenaSkewHeap[int, int](nil, lt, 16))
+
+	data := make([]HeapNode[int, int], 1000)
+	for i := range data {
+		data[i] = CreateHeapNode(i, (i*7919)%len(data))
+	}
+	heap := NewArenaSkewHeap(data, lt, 64)
+	require.NoError(t, heap.Verify())
+	heap.Clear()
+	assert.Empty(t, heap.pool.(*arenaPool[skewNode[int, int]]).slab)
+	heap.Push(1, 1)
+	assert.Equal(t, []int{1}, heap.DrainPriorities())
+
+	tracked := NewFullPairingHeap(data, lt, HeapConfig{UsePool: true, ArenaSlabSize: 128})
+	assert.IsType(t, &arenaPool[pairingHeapNode[int, int]]{}, tracked.pool)
+	id, err := tracked.Push(-1, -1)
+	require.NoError(t, err)
+	require.NoError(t, tracked.UpdatePriority(id, 2000))
+	priorities := tracked.DrainPriorities()
+	assert.Len(t, priorities, len(data)+1)
+	assert.Equal(t, 2000, priorities[len(data)])
+}
+
+func TestSimpleHeaps_Config(t *testing.T) {
+	for _, config := range []HeapConfig{{UsePool: true}, {ArenaSlabSize: 16}} {
+		runPooledCloneStress(t, "pairing", NewSimplePairingHeap[int, int](nil, lt, config))
+		runPooledCloneStress(t, "leftist", NewSimpleLeftistHeap[int, int](nil, lt, config))
+		runPooledCloneStress(t, "skew", NewSimpleSkewHeap[int, int](nil, lt, config))
+		runPooledCloneStress(t, "binomial", NewBinomialHeap[int, int](nil, lt, config))
+		runPooledCloneStress(t, "skewBinomial", NewSkewBinomialHeap[int, int](nil, lt, config))
+	}
+
+	config := NewHeapConfig(WithArena(16))
+	data := []HeapNode[int, int]{CreateHeapNode(3, 3), CreateHeapNode(1, 1), CreateHeapNode(2, 2)}
+	heaps := map[string]Heap[int, int]{
+		"syncPairing":      NewSyncSimplePairingHeap(data, lt, config),
+		"syncLeftist":      NewSyncSimpleLeftistHeap(data, lt, config),
+		"syncSkew":         NewSyncSimpleSkewHeap(data, lt, config),
+		"syncBinomial":     NewSyncBinomialHeap(data, lt, config),
+		"syncSkewBinomial": NewSyncSkewBinomialHeap(data, lt, config),
+	}
+	for name, heap := range heaps {
+		assert.Equal(t, []int{1, 2, 3}, heap.DrainPriorities(), name)
+	}
+	assert.IsType(t, &arenaPool[binomialNode[int, int]]{}, NewBinomialHeap(data, lt, config).pool)
+}
+
+func TestSimpleHeaps_NaturalOrderAndDeprecatedNames(t *testing.T) {
+	data := []HeapNode[int, int]{CreateHeapNode(3, 3), CreateHeapNode(1, 1), CreateHeapNode(2, 2)}
+	config := HeapConfig{ArenaSlabSize: 16}
+	heaps := map[string]struct {
+		heap Heap[int, int]
+		want []int
+	}{
+		"minPairing": {NewMinSimplePairingHeap(data, config), []int{1, 2, 3}},
+		"maxPairing": {NewMaxSimplePairingHeap(data, config), []int{3, 2, 1}},
+		"minLeftist": {NewMinSimpleLeftistHeap(data, config), []int{1, 2, 3}},
+		"maxLeftist": {NewMaxSimpleLeftistHeap(data, config), []int{3, 2, 1}},
+		"minSkew":    {NewMinSimpleSkewHeap(data, config), []int{1, 2, 3}},
+		"maxSkew":    {NewMaxSimpleSkewHeap(data, config), []int{3, 2, 1}},
+	}
+	for name, tc := range heaps {
+		assert.Equal(t, tc.want, tc.heap.DrainPriorities(), name)
+	}
+
+	// The deprecated names forward usePool as HeapConfig.UsePool.
+	assert.IsType(t, newSyncPool(func() *pairingNode[int, int] { return nil }), NewPairingHeap(data, lt, true).pool)
+	assert.IsType(t, newSyncPool(func() *leftistNode[int, int] { return nil }), NewLeftistHeap(data, lt, true).pool)
+	assert.IsType(t, newSyncPool(func() *skewNode[int, int] { return nil }), NewSkewHeap(data, lt, true).pool)
+	assert.True(t, NewAuxPairingHeap(data, lt, false).auxiliary)
+}
+
+func TestHeapConfig_Capacity(t *testing.T) {
+	config := HeapConfig{ArenaSlabSize: 16, Capacity: 1000}
+	tracked := NewFullLeftistHeap[int, int](nil, lt, config)
+	slab := tracked.pool.(*arenaPool[leftistHeapNode[int, int]]).slab
+	assert.Equal(t, 1000, cap(slab))
+	for i := 0; i < 1000; i++ {
+		_, err := tracked.Push(i, i)
+		require.NoError(t, err)
+	}
+	assert.Len(t, tracked.pool.(*arenaPool[leftistHeapNode[int, int]]).slab, 1000)
+
+	indexed := NewIndexedDaryHeap[int, int](4, lt, HeapConfig{Capacity: 100})
+	assert.Equal(t, 100, cap(indexed.heap.data))
+
+	heap := NewDaryHeap[int, int](4, nil, lt, false)
+	heap.Reserve(500)
+	reserved := cap(heap.data)
+	assert.GreaterOrEqual(t, reserved, 500)
+	for i := 0; i < 500; i++ {
+		heap.Push(i, i)
+	}
+	assert.Equal(t, reserved, cap(heap.data))
+
+	syncHeap := NewSyncDaryHeap[int, int](2, nil, lt, false)
+	syncHeap.Reserve(10)
+	syncHeap.Reserve(-1)
+	assert.GreaterOrEqual(t, cap(syncHeap.heap.data), 10)
+}
+
+// -------------------------------- Arena Benchmarks --------------------------------
+
+// benchmarkBuildDrain builds a heap of size elements b.N times and drains it,
+// the workload where per-node allocation dominates.
+func benchmarkBuildDrain(b *testing.B, build func() (push func(v, p int), pop func())) {
+	const size = 100_000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		push, pop := build()
+		for j := 0; j < size; j++ {
+			push(j, (j*7919)%size)
+		}
+		for j := 0; j < size; j++ {
+			pop()
+		}
+	}
+}
+
+func BenchmarkPairingHeapBuildDrain(b *testing.B) {
+	benchmarkBuildDrain(b, func() (func(v, p int), func()) {
+		h := NewPairingHeap[int, int](nil, lt, false)
+		return h.Push, func() { h.Pop() }
+	})
+}
+
+func BenchmarkArenaPairingHeapBuildDrain(b *testing.B) {
+	benchmarkBuildDrain(b, func() (func(v, p int), func()) {
+		h := NewArenaPairingHeap[int, int](nil, lt, DefaultArenaSlabSize)
+		return h.Push, func() { h.Pop() }
+	})
+}
@@ -0,0 +1,10 @@
+//go:build tinygo
+
+package heapcraft
+
+// newSyncPool creates the pool used when pooling is enabled. TinyGo's
+// sync.Pool is never drained by its garbage collector and grows with every
+// Put, so a bounded free list is used in its place.
+func newSyncPool[T any](constructor func() T) pool[T] {
+	return newFreeListPool(constructor)
+}
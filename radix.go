@@ -1,6 +1,8 @@
 package heapcraft
 
 import (
+	"context"
+	"encoding/json"
 	"math"
 
 	"golang.org/x/exp/constraints"
@@ -28,23 +30,33 @@ type RadixHeap[V any, P constraints.Unsigned] struct {
 	size    int
 	last    P
 	pool    pool[HeapNode[V, P]]
+	alarms  depthAlarms
+	stats   heapStats
+	events  listeners[HeapEvent[V, P]]
+	// shrink releases bucket 0 once pops have emptied it.
+	shrink bool
 }
 
 // Clone creates a deep copy of the heap structure. The new heap preserves the
 // original size and last value. If values or priorities are reference types, those
-// reference values are shared between the original and cloned heaps.
+// reference values are shared between the original and cloned heaps. The
+// clone receives its own node pool.
 func (r *RadixHeap[V, P]) Clone() *RadixHeap[V, P] {
 	return &RadixHeap[V, P]{
 		buckets: cloneBuckets(r.buckets),
 		size:    r.size,
 		last:    r.last,
-		pool:    r.pool,
+		pool:    r.pool.fresh(),
+		alarms:  r.alarms.clone(),
+		stats:   r.stats,
+		events:  r.events.clone(),
+		shrink:  r.shrink,
 	}
 }
 
 // Push adds a new value and priority pair into the heap.
-// Returns an error if the priority is less than r.last, as this would violate
-// the monotonic property. Otherwise, puts the item into the appropriate bucket
+// Returns a *PriorityError if the priority is less than r.last, as this would
+// violate the monotonic property. Otherwise, puts the item into the appropriate bucket
 // and increments the size.
 func (r *RadixHeap[V, P]) Push(value V, priority P) error {
 	return r.push(value, priority)
@@ -59,13 +71,16 @@ func (r *RadixHeap[V, P]) push(value V, priority P) error {
 	}
 
 	if priority < r.last {
-		return ErrPriorityLessThanLast
+		return &PriorityError[P]{Priority: priority, Last: r.last}
 	}
 	newPair := r.pool.Get()
 	newPair.value = value
 	newPair.priority = priority
 	bucketInsert(newPair, r.last, r.buckets)
 	r.size++
+	r.stats.record(OpPush, 1, r.size)
+	r.alarms.check(r.size)
+	emitHeapEvent(r.events, EventPush, "", value, priority)
 	return nil
 }
 
@@ -74,7 +89,13 @@ func (r *RadixHeap[V, P]) push(value V, priority P) error {
 func (r *RadixHeap[V, P]) getMin() HeapNode[V, P] {
 	minPair := r.buckets[0][0]
 	r.buckets[0] = r.buckets[0][1:]
+	if r.shrink && len(r.buckets[0]) == 0 {
+		r.buckets[0] = nil
+	}
 	r.size--
+	r.stats.record(OpPop, 1, r.size)
+	r.alarms.check(r.size)
+	emitHeapEvent(r.events, EventPop, "", minPair.value, minPair.priority)
 	return minPair
 }
 
@@ -133,6 +154,14 @@ func (r *RadixHeap[V, P]) peek() (V, P, error) {
 // Returns nil and an error if the heap is empty.
 func (r *RadixHeap[V, P]) Pop() (V, P, error) { return r.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (r *RadixHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(r.Peek, r.Pop, pred)
+}
+
 // Peek returns a HeapNode with the minimum priority without removing it.
 // Returns nil and an error if the heap is empty.
 func (r *RadixHeap[V, P]) Peek() (V, P, error) { return r.peek() }
@@ -149,6 +178,187 @@ func (r *RadixHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(r.pop())
 }
 
+// PopEqual removes and returns every element whose priority equals the
+// current minimum, in insertion order, rebalancing first if needed. All of
+// them share bucket 0, so the batch is taken in a single step. Returns nil
+// and an error if the heap is empty.
+func (r *RadixHeap[V, P]) PopEqual() ([]HeapNode[V, P], error) {
+	if r.size == 0 {
+		return nil, ErrHeapEmpty
+	}
+	if len(r.buckets[0]) == 0 {
+		r.rebalance()
+	}
+	batch := r.buckets[0]
+	r.buckets[0] = nil
+	r.size -= len(batch)
+	r.stats.record(OpPop, len(batch), r.size)
+	r.alarms.check(r.size)
+	for _, node := range batch {
+		emitHeapEvent(r.events, EventPop, "", node.value, node.priority)
+	}
+	return batch, nil
+}
+
+// Last returns the heap's floor: the most recently extracted minimum priority,
+// or the priority most recently passed to AdvanceTo. Pushes below it fail.
+func (r *RadixHeap[V, P]) Last() P { return r.last }
+
+// AdvanceTo moves the heap's floor forward to p, so the heap can model a
+// monotone clock. Elements with a priority below p are stale: they are
+// removed and returned in priority order. Elements at or above p stay in the
+// heap, and only the single bucket that p falls into has to be redistributed.
+// Returns a *PriorityError if p is less than Last. As with Push, the first
+// push into an empty heap sets the floor to its own priority.
+func (r *RadixHeap[V, P]) AdvanceTo(p P) ([]HeapNode[V, P], error) {
+	if p < r.last {
+		return nil, &PriorityError[P]{Priority: p, Last: r.last}
+	}
+	var stale []HeapNode[V, P]
+	for r.size > 0 {
+		if len(r.buckets[0]) == 0 {
+			if _, next, _ := r.peek(); next >= p {
+				break
+			}
+			r.rebalance()
+		}
+		if r.last >= p {
+			break
+		}
+		batch, _ := r.PopEqual()
+		stale = append(stale, batch...)
+	}
+	if r.size > 0 && p != r.last {
+		i := getBucketIndex(p, r.last)
+		bucket := r.buckets[i]
+		r.buckets[i] = nil
+		r.last = p
+		for _, node := range bucket {
+			bucketInsert(node, r.last, r.buckets)
+		}
+	}
+	r.last = p
+	return stale, nil
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (r *RadixHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(r.Length(), r.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (r *RadixHeap[V, P]) DrainValues() []V {
+	return drainValues(r.Length(), r.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (r *RadixHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(r.Length(), r.pop)
+}
+
+// forEach calls fn for every element in the heap, bucket by bucket.
+func (r *RadixHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, bucket := range r.buckets {
+		for _, node := range bucket {
+			fn(node.value, node.priority)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (r *RadixHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(r.Length(), r.forEach, func(a, b P) bool { return a < b }, opts)
+}
+
+// MarshalJSON encodes the heap as a JSON object holding the last extracted
+// priority in "last" and its elements best-first in "nodes".
+func (r *RadixHeap[V, P]) MarshalJSON() ([]byte, error) {
+	last := r.last
+	return json.Marshal(heapJSON[V, P]{Last: &last, Nodes: r.Export(ExportOptions[V, P]{})})
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON and restores the last extracted priority, so the monotonic
+// property carries over. Returns a *PriorityError, leaving the heap
+// unchanged, if an element's priority is below the encoded last priority.
+func (r *RadixHeap[V, P]) UnmarshalJSON(data []byte) error {
+	if len(r.buckets) == 0 {
+		return ErrUninitializedHeap
+	}
+
+	var decoded heapJSON[V, P]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var last P
+	switch {
+	case decoded.Last != nil:
+		last = *decoded.Last
+	case len(decoded.Nodes) > 0:
+		last = minFromSlice(decoded.Nodes).priority
+	}
+	return r.reload(last, len(decoded.Nodes), func(i int) (V, P) {
+		return decoded.Nodes[i].value, decoded.Nodes[i].priority
+	})
+}
+
+// Snapshot encodes the elements of the heap and the last extracted priority in
+// a compact binary format using encoding/gob, for persisting the heap across
+// process restarts. Elements are written in internal order without sorting.
+func (r *RadixHeap[V, P]) Snapshot() ([]byte, error) {
+	snapshot := snapshotNodes(r.Length(), r.forEach)
+	snapshot.Last = r.last
+	return encodeSnapshot(snapshot)
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot and restores the last extracted priority. Returns a
+// *PriorityError, leaving the heap unchanged, if an element's priority
+// is below the encoded last priority.
+func (r *RadixHeap[V, P]) Restore(data []byte) error {
+	if len(r.buckets) == 0 {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	return r.reload(snapshot.Last, len(snapshot.Values), func(i int) (V, P) {
+		return snapshot.Values[i], snapshot.Priorities[i]
+	})
+}
+
+// reload replaces the contents of the heap with n elements returned by node
+// and sets the last extracted priority. Returns a *PriorityError, leaving the
+// heap unchanged, if an element's priority is below last.
+func (r *RadixHeap[V, P]) reload(last P, n int, node func(i int) (V, P)) error {
+	for i := 0; i < n; i++ {
+		if _, priority := node(i); priority < last {
+			return &PriorityError[P]{Priority: priority, Last: last}
+		}
+	}
+
+	r.Clear()
+	r.last = last
+	for i := 0; i < n; i++ {
+		pair := r.pool.Get()
+		pair.value, pair.priority = node(i)
+		bucketInsert(pair, r.last, r.buckets)
+		r.size++
+		r.stats.record(OpPush, 1, r.size)
+		r.alarms.check(r.size)
+		emitHeapEvent(r.events, EventPush, "", pair.value, pair.priority)
+	}
+	return nil
+}
+
 // PeekValue returns just the value of the root element without removing it.
 // Returns zero value and an error if the heap is empty.
 func (r *RadixHeap[V, P]) PeekValue() (V, error) {
@@ -164,9 +374,28 @@ func (r *RadixHeap[V, P]) PeekPriority() (P, error) {
 // Clear reinitializes the heap by creating fresh buckets, resetting size to zero,
 // and setting 'last' back to its zero value.
 func (r *RadixHeap[V, P]) Clear() {
+	cleared := r.Length()
 	r.buckets = make([][]HeapNode[V, P], len(r.buckets))
 	r.size = 0
+	r.stats.record(OpClear, cleared, r.size)
+	r.alarms.check(r.size)
 	r.last = 0
+	emitClearEvent(r.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (r *RadixHeap[V, P]) PoolStats() PoolStats { return r.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its buckets, counted
+// by capacity, and the elements idle in its pool. Memory that values point to
+// is not counted.
+func (r *RadixHeap[V, P]) ApproxMemoryUsage() int {
+	total := sizeOf[RadixHeap[V, P]]() + cap(r.buckets)*sizeOf[[]HeapNode[V, P]]()
+	for _, bucket := range r.buckets {
+		total += cap(bucket) * sizeOf[HeapNode[V, P]]()
+	}
+	return total + r.pool.idle()*sizeOf[HeapNode[V, P]]()
 }
 
 // rebalance locates the next bucket with elements (i > 0), updates 'last'
@@ -177,6 +406,7 @@ func (r *RadixHeap[V, P]) rebalance() {
 	for i := 1; i < len(r.buckets); i++ {
 		if len(r.buckets[i]) > 0 {
 			r.last = minFromSlice(r.buckets[i]).priority
+			r.stats.rebalance()
 			for _, pair := range r.buckets[i] {
 				bucketInsert(pair, r.last, r.buckets)
 			}
@@ -200,37 +430,163 @@ func (r *RadixHeap[V, P]) Rebalance() error {
 	return ErrNoRebalancingNeeded
 }
 
+// Maintain performs pending upkeep ahead of the next Pop. If bucket 0 is empty
+// the heap is rebalanced so that the next Pop does not have to, and buckets
+// whose backing arrays have grown far beyond their contents are compacted.
+// Returns the context error if ctx is already done.
+func (r *RadixHeap[V, P]) Maintain(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.size > 0 && len(r.buckets[0]) == 0 {
+		r.rebalance()
+	}
+	for i, bucket := range r.buckets {
+		if cap(bucket) > 2*len(bucket) {
+			r.buckets[i] = compactNodes(bucket)
+		}
+	}
+	return nil
+}
+
+// Compact reallocates every bucket to fit its elements exactly, releasing any
+// capacity left over from earlier bursts. It runs in O(n) and is intended for
+// long-lived heaps after a burst has been drained.
+func (r *RadixHeap[V, P]) Compact() {
+	for i, bucket := range r.buckets {
+		if cap(bucket) > len(bucket) {
+			r.buckets[i] = compactNodes(bucket)
+		}
+	}
+}
+
 // Length returns the number of items currently stored in the heap.
 func (r *RadixHeap[V, P]) Length() int { return r.size }
 
 // IsEmpty returns true if the heap contains no items.
 func (r *RadixHeap[V, P]) IsEmpty() bool { return r.size == 0 }
 
+// Verify checks the internal consistency of the heap: every element must
+// have a priority no less than the last extracted priority and sit in the
+// bucket that priority maps to, and the buckets must hold exactly Length
+// elements. It is intended for tests and debugging, runs in O(n) and returns
+// an error wrapping ErrInvariantViolated for the first violation.
+func (r *RadixHeap[V, P]) Verify() error {
+	count := 0
+	for i, bucket := range r.buckets {
+		for _, node := range bucket {
+			if node.priority < r.last {
+				return invariantError("priority %d is below the last extracted priority %d", node.priority, r.last)
+			}
+			want := 0
+			if node.priority != r.last {
+				want = getBucketIndex(node.priority, r.last)
+			}
+			if want != i {
+				return invariantError("priority %d is in bucket %d instead of bucket %d", node.priority, i, want)
+			}
+		}
+		count += len(bucket)
+	}
+	return verifySize(count, r.size)
+}
+
 // Merge integrates another RadixHeap into this one.
 // It selects the heap with the smaller 'last' as the new baseline, adopts its
 // buckets and 'last', then reinserts all items from the other heap to preserve
-// the monotonic property.
+// the monotonic property. An empty heap has no baseline, so merging one in or
+// into one never lowers or raises 'last'. The other heap is left unchanged.
 func (r *RadixHeap[V, P]) Merge(radix *RadixHeap[V, P]) {
+	if radix == nil || radix == r || radix.size == 0 {
+		return
+	}
+
+	// Depth alarms, statistics and event listeners are detached while the
+	// buckets are swapped and refilled so that they only observe the merged
+	// length and the elements that came from radix.
+	alarms, stats, events := r.alarms, r.stats, r.events
+	r.alarms, r.stats, r.events = nil, heapStats{}, nil
+	merged := radix.size
+	var melded []HeapNode[V, P]
+	if len(events) > 0 {
+		radix.forEach(func(v V, p P) { melded = append(melded, CreateHeapNode(v, p)) })
+	}
+
 	var newRadix *RadixHeap[V, P]
-	if r.last > radix.last {
+	if r.size == 0 || r.last > radix.last {
 		newRadix = &RadixHeap[V, P]{
-			buckets: cloneBuckets(r.buckets),
+			buckets: r.buckets,
 			size:    r.size,
 			last:    r.last,
 		}
-		r.buckets = radix.buckets
+		r.buckets = cloneBuckets(radix.buckets)
 		r.last = radix.last
 		r.size = radix.size
 	} else {
 		newRadix = radix
 	}
+
+	// Every element of newRadix has a priority of at least newRadix.last,
+	// which is no lower than r.last, so each one can be bucketed against
+	// r.last directly. Going through push would reset r.last whenever r is
+	// still empty.
 	for i := range newRadix.buckets {
 		for _, pair := range newRadix.buckets[i] {
-			r.push(pair.value, pair.priority)
+			bucketInsert(pair, r.last, r.buckets)
+			r.size++
 		}
 	}
+	r.alarms, r.stats, r.events = alarms, stats, events
+	r.stats.record(OpMeld, merged, r.size)
+	r.alarms.check(r.size)
+	for _, node := range melded {
+		emitHeapEvent(r.events, EventPush, "", node.value, node.priority)
+	}
+}
+
+// MergeSync integrates the elements of a SyncRadixHeap into this one, the
+// same way Merge does. other is read under its read lock and left unchanged,
+// so it can keep being used concurrently.
+func (r *RadixHeap[V, P]) MergeSync(other *SyncRadixHeap[V, P]) {
+	if other == nil || other.heap == r {
+		return
+	}
+	r.Merge(other.Clone().heap)
+}
+
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (r *RadixHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return r.alarms.register(n, r.Length(), fn)
 }
 
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (r *RadixHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return r.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (r *RadixHeap[V, P]) Stats() HeapStats { return r.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (r *RadixHeap[V, P]) Instrument(hook Instrumentation) { r.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element merged in from another heap and each stale element
+// returned by AdvanceTo, and whenever the heap is cleared. Returns an ID that
+// can be passed to RemoveListener.
+func (r *RadixHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return r.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (r *RadixHeap[V, P]) RemoveListener(id string) error { return r.events.deregister(id) }
+
 // getBucketIndex calculates which bucket index a priority 'num' belongs to,
 // relative to 'last'.
 // Returns floor(log2(num XOR last)) + 1. If num equals last, callers should
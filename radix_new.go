@@ -7,12 +7,21 @@ import (
 )
 
 // NewRadixHeap creates a RadixHeap from a given slice of HeapNode[V,P].
-// It determines the number of buckets from the bit-length of P, initializess
-// 'last' to the minimum priority if data is present, and assigns each element
-// into its corresponding bucket. The heap maintains a monotonic property where
-// priorities must be non-decreasing.
+// See NewRadixHeapWithConfig.
+//
+// Deprecated: use NewRadixHeapWithConfig with RadixHeapConfig{UsePool: usePool}.
 func NewRadixHeap[V any, P constraints.Unsigned](data []HeapNode[V, P], usePool bool) *RadixHeap[V, P] {
-	pool := newPool(usePool, func() HeapNode[V, P] {
+	return NewRadixHeapWithConfig(data, RadixHeapConfig{UsePool: usePool})
+}
+
+// NewRadixHeapWithConfig creates a RadixHeap from a given slice of
+// HeapNode[V,P] with the options set in config. It determines the number of
+// buckets from the bit-length of P, initializes 'last' to the minimum
+// priority if data is present, and assigns each element into its
+// corresponding bucket. The heap maintains a monotonic property where
+// priorities must be non-decreasing.
+func NewRadixHeapWithConfig[V any, P constraints.Unsigned](data []HeapNode[V, P], config RadixHeapConfig) *RadixHeap[V, P] {
+	pool := newPool(config.UsePool, func() HeapNode[V, P] {
 		return HeapNode[V, P]{}
 	})
 	var pType P
@@ -42,11 +51,20 @@ func NewRadixHeap[V any, P constraints.Unsigned](data []HeapNode[V, P], usePool
 	}
 
 	return &RadixHeap[V, P]{
-		buckets: buckets, size: size, last: last, pool: pool,
+		buckets: buckets, size: size, last: last, pool: pool, shrink: config.AutoShrink,
 	}
 }
 
 // NewSyncRadixHeap creates a new thread-safe RadixHeap from a given slice of HeapNode[V,P].
+//
+// Deprecated: use NewSyncRadixHeapWithConfig with
+// RadixHeapConfig{UsePool: usePool}.
 func NewSyncRadixHeap[V any, P constraints.Unsigned](data []HeapNode[V, P], usePool bool) *SyncRadixHeap[V, P] {
-	return &SyncRadixHeap[V, P]{heap: NewRadixHeap(data, usePool)}
+	return NewSyncRadixHeapWithConfig(data, RadixHeapConfig{UsePool: usePool})
+}
+
+// NewSyncRadixHeapWithConfig creates a new thread-safe RadixHeap from a given
+// slice of HeapNode[V,P] with the options set in config.
+func NewSyncRadixHeapWithConfig[V any, P constraints.Unsigned](data []HeapNode[V, P], config RadixHeapConfig) *SyncRadixHeap[V, P] {
+	return &SyncRadixHeap[V, P]{heap: NewRadixHeapWithConfig(data, config)}
 }
@@ -1,17 +1,12 @@
 package heapcraft
 
 import (
+	"context"
 	"sync"
-	"unsafe"
 
 	"golang.org/x/exp/constraints"
 )
 
-// getHeapAddr returns the address of the heap.
-func getHeapAddr[V any, P constraints.Unsigned](h *SyncRadixHeap[V, P]) uintptr {
-	return uintptr(unsafe.Pointer(h))
-}
-
 // SyncRadixHeap provides a thread-safe wrapper around RadixHeap.
 // It uses a read-write mutex to allow concurrent reads and exclusive writes.
 type SyncRadixHeap[V any, P constraints.Unsigned] struct {
@@ -31,8 +26,8 @@ func (s *SyncRadixHeap[V, P]) Clone() *SyncRadixHeap[V, P] {
 }
 
 // Push adds a new value and priority pair into the heap.
-// Returns an error if the priority is less than the last extracted priority, as this would violate
-// the monotonic property. Otherwise, puts the item into the appropriate bucket
+// Returns a *PriorityError if the priority is less than the last extracted
+// priority, as this would violate the monotonic property. Otherwise, puts the item into the appropriate bucket
 // and increments the size.
 func (s *SyncRadixHeap[V, P]) Push(value V, priority P) error {
 	s.mu.Lock()
@@ -48,6 +43,17 @@ func (s *SyncRadixHeap[V, P]) Pop() (V, P, error) {
 	return s.heap.Pop()
 }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// write lock, so no other goroutine can take the root in between. If pred
+// reports false the root stays in the heap and is returned with false.
+// pred must not call back into the heap.
+func (s *SyncRadixHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopIf(pred)
+}
+
 // Peek returns a HeapNode with the minimum priority without removing it.
 // Returns nil and an error if the heap is empty.
 func (s *SyncRadixHeap[V, P]) Peek() (V, P, error) {
@@ -72,6 +78,97 @@ func (s *SyncRadixHeap[V, P]) PopPriority() (P, error) {
 	return s.heap.PopPriority()
 }
 
+// PopEqual removes and returns every element whose priority equals the
+// current minimum under a single write lock. Returns nil and an error if the
+// heap is empty.
+func (s *SyncRadixHeap[V, P]) PopEqual() ([]HeapNode[V, P], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopEqual()
+}
+
+// Last returns the heap's floor: the most recently extracted minimum priority,
+// or the priority most recently passed to AdvanceTo.
+func (s *SyncRadixHeap[V, P]) Last() P {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Last()
+}
+
+// AdvanceTo moves the heap's floor forward to p under a single write lock,
+// removing and returning the elements with a priority below p in priority
+// order. Returns a *PriorityError if p is less than Last.
+func (s *SyncRadixHeap[V, P]) AdvanceTo(p P) ([]HeapNode[V, P], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.AdvanceTo(p)
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SyncRadixHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SyncRadixHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SyncRadixHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SyncRadixHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Export(opts)
+}
+
+// MarshalJSON encodes the heap as JSON in the same format as the underlying
+// heap. It acquires a read lock.
+func (s *SyncRadixHeap[V, P]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. It acquires a write lock.
+func (s *SyncRadixHeap[V, P]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.UnmarshalJSON(data)
+}
+
+// Snapshot encodes the heap in the same binary format as the underlying heap.
+// It acquires a read lock.
+func (s *SyncRadixHeap[V, P]) Snapshot() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Snapshot()
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. It acquires a write lock.
+func (s *SyncRadixHeap[V, P]) Restore(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Restore(data)
+}
+
 // PeekValue returns just the value of the root element without removing it.
 // Returns zero value and an error if the heap is empty.
 func (s *SyncRadixHeap[V, P]) PeekValue() (V, error) {
@@ -96,6 +193,22 @@ func (s *SyncRadixHeap[V, P]) Clear() {
 	s.heap.Clear()
 }
 
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SyncRadixHeap[V, P]) PoolStats() PoolStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.PoolStats()
+}
+
+// ApproxMemoryUsage estimates the bytes held by the heap, not counting memory
+// that values and priorities point to.
+func (s *SyncRadixHeap[V, P]) ApproxMemoryUsage() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.ApproxMemoryUsage()
+}
+
 // Rebalance fills bucket 0 if it is empty.
 // Returns an error if the heap is empty, or if bucket 0 already contains elements
 // (no action was needed).
@@ -105,6 +218,24 @@ func (s *SyncRadixHeap[V, P]) Rebalance() error {
 	return s.heap.Rebalance()
 }
 
+// Maintain performs pending upkeep ahead of the next Pop. If bucket 0 is empty
+// the heap is rebalanced so that the next Pop does not have to, and buckets
+// whose backing arrays have grown far beyond their contents are compacted.
+// Returns the context error if ctx is already done.
+func (s *SyncRadixHeap[V, P]) Maintain(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Maintain(ctx)
+}
+
+// Compact reallocates every bucket to fit its elements exactly, releasing any
+// capacity left over from earlier bursts.
+func (s *SyncRadixHeap[V, P]) Compact() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Compact()
+}
+
 // Length returns the number of items currently stored in the heap.
 func (s *SyncRadixHeap[V, P]) Length() int {
 	s.mu.RLock()
@@ -112,6 +243,58 @@ func (s *SyncRadixHeap[V, P]) Length() int {
 	return s.heap.Length()
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below. fn runs while the heap is locked and must not call back into it.
+// Returns an ID that can be passed to RemoveDepthAlarm.
+func (s *SyncRadixHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RegisterDepthAlarm(n, fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SyncRadixHeap[V, P]) RemoveDepthAlarm(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveDepthAlarm(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SyncRadixHeap[V, P]) Stats() HeapStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Stats()
+}
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. The hook runs
+// while the heap is locked and must not call back into it.
+func (s *SyncRadixHeap[V, P]) Instrument(hook Instrumentation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Instrument(hook)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, and whenever the heap is cleared. fn runs while the heap is
+// locked and must not call back into it. Returns an ID that can be passed to
+// RemoveListener.
+func (s *SyncRadixHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.OnEvent(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SyncRadixHeap[V, P]) RemoveListener(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.RemoveListener(id)
+}
+
 // IsEmpty returns true if the heap contains no items.
 func (s *SyncRadixHeap[V, P]) IsEmpty() bool {
 	s.mu.RLock()
@@ -119,21 +302,37 @@ func (s *SyncRadixHeap[V, P]) IsEmpty() bool {
 	return s.heap.IsEmpty()
 }
 
+// Verify checks the internal consistency of the heap under a read lock. It is
+// intended for tests and debugging.
+func (s *SyncRadixHeap[V, P]) Verify() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.heap.Verify()
+}
+
 // Merge integrates another SafeRadixHeap into this one.
 // It selects the heap with the smaller 'last' as the new baseline, adopts its
 // buckets and 'last', then reinserts all items from the other heap to preserve
 // the monotonic property.
 func (s *SyncRadixHeap[V, P]) Merge(other *SyncRadixHeap[V, P]) {
-	if getHeapAddr(s) > getHeapAddr(other) {
-		s.mu.Lock()
-		defer s.mu.Unlock()
-		other.mu.Lock()
-		defer other.mu.Unlock()
-	} else {
-		other.mu.Lock()
-		defer other.mu.Unlock()
-		s.mu.Lock()
-		defer s.mu.Unlock()
+	if other == nil || other == s {
+		return
 	}
+
+	defer lockPair(&s.mu, &other.mu)()
 	s.heap.Merge(other.heap)
 }
+
+// MergeHeap integrates an unsynchronized RadixHeap into this one under a
+// write lock, the same way Merge does. It lets heaps filled locally by
+// individual goroutines be folded into a shared heap. other is not locked, so
+// the caller must ensure nothing else uses it during or after the merge.
+func (s *SyncRadixHeap[V, P]) MergeHeap(other *RadixHeap[V, P]) {
+	if other == nil || other == s.heap {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Merge(other)
+}
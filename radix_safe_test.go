@@ -1,6 +1,8 @@
 package heapcraft
 
 import (
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -81,7 +83,12 @@ func TestSyncRadixHeap_Push(t *testing.T) {
 	t.Run("push with lower priority should fail", func(t *testing.T) {
 		err := heap.Push(100, 5)
 		assert.Error(t, err)
-		assert.Equal(t, ErrPriorityLessThanLast, err)
+		assert.ErrorIs(t, err, ErrPriorityLessThanLast)
+
+		var priorityErr *PriorityError[uint]
+		require.ErrorAs(t, err, &priorityErr)
+		assert.Equal(t, uint(5), priorityErr.Priority)
+		assert.Equal(t, uint(10), priorityErr.Last)
 		assert.Equal(t, 2, heap.Length())
 	})
 }
@@ -346,3 +353,44 @@ func TestSyncRadixHeap_Merge(t *testing.T) {
 	expectedValues := []int{24, 50, 42, 100}
 	assert.ElementsMatch(t, expectedValues, allValues)
 }
+
+func TestSyncRadixHeap_MergeHeap(t *testing.T) {
+	shared := NewSyncRadixHeap([]HeapNode[int, uint]{{value: 1, priority: 10}}, false)
+
+	var wg sync.WaitGroup
+	locals := make([]*RadixHeap[int, uint], 4)
+	for w := range locals {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			local := NewRadixHeap[int, uint](nil, false)
+			for i := 0; i < 25; i++ {
+				local.Push(w, uint(w*100+i))
+			}
+			locals[w] = local
+		}(w)
+	}
+	wg.Wait()
+	for _, local := range locals {
+		shared.MergeHeap(local)
+	}
+
+	assert.Equal(t, 101, shared.Length())
+	require.NoError(t, shared.Verify())
+	assert.True(t, slices.IsSorted(shared.DrainPriorities()))
+}
+
+func TestRadixHeap_MergeSync(t *testing.T) {
+	shared := NewSyncRadixHeap([]HeapNode[int, uint]{
+		{value: 1, priority: 3},
+		{value: 2, priority: 9},
+	}, false)
+	local := NewRadixHeap([]HeapNode[int, uint]{{value: 3, priority: 5}}, false)
+
+	local.MergeSync(shared)
+	assert.Equal(t, 3, local.Length())
+	assert.Equal(t, 2, shared.Length())
+	require.NoError(t, shared.Verify())
+	assert.Equal(t, []int{1, 3, 2}, local.DrainValues())
+	assert.Equal(t, []int{1, 2}, shared.DrainValues())
+}
@@ -1,9 +1,12 @@
 package heapcraft
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewRadixHeapPopOrder(t *testing.T) {
@@ -63,7 +66,13 @@ func TestRadixHeapPushMonotonicity(t *testing.T) {
 	assert.Equal(t, uint(3), priority)
 
 	err = rh.Push("value1", uint(1))
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrPriorityLessThanLast)
+
+	var priorityErr *PriorityError[uint]
+	require.ErrorAs(t, err, &priorityErr)
+	assert.Equal(t, uint(1), priorityErr.Priority)
+	assert.Equal(t, uint(2), priorityErr.Last)
+	assert.Equal(t, "insertion of a priority less than last popped: priority 1, last 2", err.Error())
 }
 
 func TestRadixHeapPeek(t *testing.T) {
@@ -137,6 +146,51 @@ func TestRadixHeapMerge(t *testing.T) {
 	assert.Equal(t, []uint{1, 2, 3, 4, 5, 6}, result)
 }
 
+func TestRadixHeapMerge_PoppedAndEmpty(t *testing.T) {
+	popped := func() *RadixHeap[int, uint] {
+		h := NewRadixHeap[int, uint](nil, false)
+		for _, p := range []uint{10, 30, 20} {
+			require.NoError(t, h.Push(int(p), p))
+		}
+		_, _, err := h.Pop()
+		require.NoError(t, err)
+		return h
+	}
+	drain := func(h *RadixHeap[int, uint]) []uint {
+		require.NoError(t, h.Verify())
+		var priorities []uint
+		for !h.IsEmpty() {
+			_, p, err := h.Pop()
+			require.NoError(t, err)
+			priorities = append(priorities, p)
+		}
+		return priorities
+	}
+
+	h := popped()
+	h.Merge(NewRadixHeap[int, uint](nil, false))
+	assert.Equal(t, []uint{20, 30}, drain(h))
+
+	empty := NewRadixHeap[int, uint](nil, false)
+	other := popped()
+	empty.Merge(other)
+	assert.Equal(t, []uint{20, 30}, drain(empty))
+	assert.Equal(t, 2, other.Length())
+
+	shared := NewSyncRadixHeap[int, uint](nil, false)
+	require.NoError(t, shared.Push(10, 10))
+	require.NoError(t, shared.Push(40, 40))
+	_, _, err := shared.Pop()
+	require.NoError(t, err)
+	shared.MergeHeap(NewRadixHeap[int, uint](nil, false))
+	shared.MergeHeap(popped())
+	assert.Equal(t, []uint{20, 30, 40}, shared.DrainPriorities())
+
+	h = popped()
+	h.MergeSync(NewSyncRadixHeap[int, uint](nil, false))
+	assert.Equal(t, []uint{20, 30}, drain(h))
+}
+
 func TestRadixHeapRemoveAndErrors(t *testing.T) {
 	rh := NewRadixHeap([]HeapNode[string, uint]{}, false)
 	assert.True(t, rh.IsEmpty())
@@ -161,6 +215,86 @@ func TestRadixHeapLengthIsEmpty(t *testing.T) {
 	assert.Equal(t, 1, rh.Length())
 }
 
+func TestRadixHeapPopEqual(t *testing.T) {
+	rh := NewRadixHeap[string, uint](nil, false)
+	_, err := rh.PopEqual()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	rh.Push("a", 3)
+	rh.Push("b", 5)
+	rh.Push("c", 3)
+	rh.Push("d", 5)
+	rh.Push("e", 9)
+
+	batch, err := rh.PopEqual()
+	require.NoError(t, err)
+	assert.Equal(t, []HeapNode[string, uint]{CreateHeapNode("a", uint(3)), CreateHeapNode("c", uint(3))}, batch)
+	assert.Equal(t, 3, rh.Length())
+
+	batch, err = rh.PopEqual()
+	require.NoError(t, err)
+	assert.Equal(t, []HeapNode[string, uint]{CreateHeapNode("b", uint(5)), CreateHeapNode("d", uint(5))}, batch)
+	require.NoError(t, rh.Verify())
+
+	require.NoError(t, rh.Push("f", 5))
+	batch, err = rh.PopEqual()
+	require.NoError(t, err)
+	assert.Equal(t, []HeapNode[string, uint]{CreateHeapNode("f", uint(5))}, batch)
+	assert.Equal(t, []string{"e"}, rh.DrainValues())
+}
+
+func TestRadixHeapAdvanceTo(t *testing.T) {
+	rh := NewRadixHeap[string, uint](nil, false)
+	for i, p := range []uint{10, 12, 15, 20, 20, 40} {
+		rh.Push(string(rune('a'+i)), p)
+	}
+
+	stale, err := rh.AdvanceTo(20)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{10, 12, 15}, nodePriorities(stale))
+	assert.Equal(t, uint(20), rh.Last())
+	assert.Equal(t, 3, rh.Length())
+	require.NoError(t, rh.Verify())
+
+	stale, err = rh.AdvanceTo(33)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{20, 20}, nodePriorities(stale))
+	require.NoError(t, rh.Push("g", 35))
+	var perr *PriorityError[uint]
+	require.ErrorAs(t, rh.Push("h", 30), &perr)
+	assert.Equal(t, uint(33), perr.Last)
+	require.NoError(t, rh.Verify())
+
+	_, err = rh.AdvanceTo(5)
+	assert.ErrorIs(t, err, ErrPriorityLessThanLast)
+	assert.Equal(t, []uint{35, 40}, rh.DrainPriorities())
+}
+
+func TestRadixHeapAdvanceToRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	rh := NewRadixHeap[int, uint](nil, false)
+	rh.Push(0, 0)
+	pending := []uint{0}
+	for i := 1; i < 2000; i++ {
+		if rng.Intn(4) > 0 {
+			p := rh.Last() + uint(rng.Intn(1<<12))
+			require.NoError(t, rh.Push(i, p))
+			pending = append(pending, p)
+		} else {
+			clock := rh.Last() + uint(rng.Intn(1<<10))
+			slices.Sort(pending)
+			n, _ := slices.BinarySearch(pending, clock)
+			stale, err := rh.AdvanceTo(clock)
+			require.NoError(t, err)
+			assert.Equal(t, pending[:n], nodePriorities(stale))
+			pending = slices.Clone(pending[n:])
+		}
+		require.NoError(t, rh.Verify())
+	}
+	slices.Sort(pending)
+	assert.Equal(t, pending, rh.DrainPriorities())
+}
+
 // -------------------------------- Radix Heap Benchmarks --------------------------------
 
 func BenchmarkRadixHeapInsertion(b *testing.B) {
@@ -0,0 +1,120 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket hands out one token per interval, accumulating up to burst
+// tokens while none are taken.
+type tokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// refill adds the tokens earned since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+	b.last = now
+}
+
+// take takes a token if one is available and returns zero, or otherwise
+// returns how long until one will be. Unlimited buckets always have a token.
+func (b *tokenBucket) take() time.Duration {
+	if b.interval <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) * float64(b.interval))
+}
+
+// refund returns a token that was taken but not used.
+func (b *tokenBucket) refund() {
+	if b.interval <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// RateLimitedHeap limits how fast elements are popped from a BlockingHeap
+// with a token bucket: one token is earned every interval, up to a burst, and
+// every pop spends one. PopWait blocks until both an element and a token are
+// available, which makes the pair a complete job dispatcher: producers push
+// into the BlockingHeap and workers pop through the RateLimitedHeap. Pops made
+// directly on the BlockingHeap are not limited. A RateLimitedHeap is safe for
+// concurrent use.
+type RateLimitedHeap[V any, P any] struct {
+	heap   *BlockingHeap[V, P]
+	bucket tokenBucket
+}
+
+// Heap returns the wrapped BlockingHeap.
+func (r *RateLimitedHeap[V, P]) Heap() *BlockingHeap[V, P] { return r.heap }
+
+// Push adds an element to the wrapped heap and wakes any consumers blocked in
+// PopWait.
+func (r *RateLimitedHeap[V, P]) Push(value V, priority P) { r.heap.Push(value, priority) }
+
+// Pop removes and returns the root element if a token is available, without
+// blocking. Returns ErrRateLimited if no token is available and ErrHeapEmpty
+// if the heap is empty, in which case no token is spent.
+func (r *RateLimitedHeap[V, P]) Pop() (V, P, error) {
+	if r.bucket.take() > 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrRateLimited
+	}
+	value, priority, err := r.heap.Pop()
+	if err != nil {
+		r.bucket.refund()
+	}
+	return value, priority, err
+}
+
+// PopWait removes and returns the root element, blocking first until a token
+// is available and then until the heap is non-empty. The token is taken
+// before waiting for an element, so an element pushed to an idle dispatcher is
+// handed out at once. Returns zero values and the context's error if ctx is
+// done first, in which case no token is spent.
+func (r *RateLimitedHeap[V, P]) PopWait(ctx context.Context) (V, P, error) {
+	for {
+		wait := r.bucket.take()
+		if wait == 0 {
+			break
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			v, p := zeroValuePair[V, P]()
+			return v, p, ctx.Err()
+		}
+	}
+	value, priority, err := r.heap.PopWait(ctx)
+	if err != nil {
+		r.bucket.refund()
+	}
+	return value, priority, err
+}
+
+// Peek returns the value and priority of the root element without removing it
+// or spending a token.
+func (r *RateLimitedHeap[V, P]) Peek() (V, P, error) { return r.heap.Peek() }
+
+// Length returns the number of elements in the wrapped heap.
+func (r *RateLimitedHeap[V, P]) Length() int { return r.heap.Length() }
+
+// IsEmpty returns true if the wrapped heap contains no elements.
+func (r *RateLimitedHeap[V, P]) IsEmpty() bool { return r.heap.IsEmpty() }
@@ -0,0 +1,20 @@
+package heapcraft
+
+import "time"
+
+// NewRateLimitedHeap wraps heap in a RateLimitedHeap that allows one pop every
+// interval on average, with bursts of up to burst pops after a quiet period.
+// The bucket starts full. An interval of zero or less disables the limit, and
+// a burst below one is treated as one.
+func NewRateLimitedHeap[V any, P any](heap *BlockingHeap[V, P], interval time.Duration, burst int) *RateLimitedHeap[V, P] {
+	burst = max(burst, 1)
+	return &RateLimitedHeap[V, P]{
+		heap: heap,
+		bucket: tokenBucket{
+			interval: interval,
+			burst:    float64(burst),
+			tokens:   float64(burst),
+			last:     time.Now(),
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package heapcraft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitedHeap_Pop(t *testing.T) {
+	heap := NewRateLimitedHeap(NewBlockingHeap(NewSyncDaryHeap[string, int](2, nil, lt, false)), time.Hour, 2)
+
+	// An empty heap does not spend a token.
+	_, _, err := heap.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	heap.Push("c", 3)
+	heap.Push("a", 1)
+	heap.Push("b", 2)
+	assert.Equal(t, 3, heap.Length())
+	value, _, err := heap.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "a", value)
+
+	for _, want := range []string{"a", "b"} {
+		value, _, err := heap.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, want, value)
+	}
+	_, _, err = heap.Pop()
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.False(t, heap.IsEmpty())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = heap.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, heap.Heap().Length())
+}
+
+func TestRateLimitedHeap_PopWaitPaces(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	heap := NewRateLimitedHeap(NewBlockingHeap(NewSyncPairingHeap[int, int](nil, lt, false)), interval, 1)
+	for i := 0; i < 4; i++ {
+		heap.Push(i, i)
+	}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		value, _, err := heap.PopWait(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, i, value)
+	}
+	// The first pop spends the initial token and each of the others waits
+	// for a new one.
+	assert.GreaterOrEqual(t, time.Since(start), 3*interval-time.Millisecond)
+}
+
+func TestRateLimitedHeap_PopWaitForElement(t *testing.T) {
+	heap := NewRateLimitedHeap(NewBlockingHeap(NewSyncDaryHeap[string, int](2, nil, lt, false)), time.Hour, 1)
+
+	// A context that ends while waiting for an element refunds the token.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := heap.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	result := make(chan string)
+	go func() {
+		value, _, err := heap.PopWait(context.Background())
+		assert.NoError(t, err)
+		result <- value
+	}()
+	time.Sleep(10 * time.Millisecond)
+	heap.Heap().Push("job", 1)
+	select {
+	case value := <-result:
+		assert.Equal(t, "job", value)
+	case <-time.After(time.Second):
+		t.Fatal("PopWait was not woken by Push")
+	}
+}
+
+func TestRateLimitedHeap_Unlimited(t *testing.T) {
+	heap := NewRateLimitedHeap(NewBlockingHeap(NewSyncDaryHeap[int, int](2, nil, lt, false)), 0, 0)
+	for i := 0; i < 100; i++ {
+		heap.Push(i, i)
+	}
+	for i := 0; i < 100; i++ {
+		_, _, err := heap.Pop()
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,32 @@
+package heapcraft
+
+// RowScanner is the subset of *sql.Rows used by LoadRows, so that a tracked
+// heap can be hydrated from a query result without the package depending on
+// database/sql.
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// LoadRows pushes one element into heap for every remaining row of rows,
+// under the ID returned by scan, which is typically the row's primary key.
+// Keeping the database's IDs as node IDs lets later updates and removals go
+// straight from a row to its element. It returns the number of rows loaded
+// and stops at the first error from scan, from pushing a duplicate ID, or
+// from iterating rows. Rows loaded before an error stay in the heap. LoadRows
+// does not close rows.
+func LoadRows[V any, P any](heap IDPusher[V, P], rows RowScanner, scan func(rows RowScanner) (id string, value V, priority P, err error)) (int, error) {
+	loaded := 0
+	for rows.Next() {
+		id, value, priority, err := scan(rows)
+		if err != nil {
+			return loaded, err
+		}
+		if err := heap.PushWithID(id, value, priority); err != nil {
+			return loaded, err
+		}
+		loaded++
+	}
+	return loaded, rows.Err()
+}
@@ -0,0 +1,126 @@
+package heapcraft
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// job is a row of a jobs table.
+type job struct {
+	id       string
+	name     string
+	priority int
+}
+
+// fakeRows serves jobs the way *sql.Rows serves the result of a query.
+type fakeRows struct {
+	jobs []job
+	next int
+	err  error
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.jobs)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.jobs[r.next-1]
+	*dest[0].(*string) = row.id
+	*dest[1].(*string) = row.name
+	*dest[2].(*int) = row.priority
+	return nil
+}
+
+func (r *fakeRows) Err() error { return r.err }
+
+// scanJob scans a fakeRows row into its ID, name and priority.
+func scanJob(rows RowScanner) (id string, name string, priority int, err error) {
+	err = rows.Scan(&id, &name, &priority)
+	return id, name, priority, err
+}
+
+func TestLoadRows(t *testing.T) {
+	heap := NewFullPairingHeap[string, int](nil, lt, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	rows := &fakeRows{jobs: []job{{"7", "email", 3}, {"3", "report", 1}, {"12", "backup", 2}}}
+
+	loaded, err := LoadRows(heap, rows, scanJob)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loaded)
+
+	name, err := heap.GetValue("12")
+	assert.NoError(t, err)
+	assert.Equal(t, "backup", name)
+
+	// New IDs continue after the largest loaded row ID.
+	id, err := heap.Push("cleanup", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "13", id)
+}
+
+func TestLoadRows_Errors(t *testing.T) {
+	heap := NewSyncFullPairingHeap[string, int](nil, lt, HeapConfig{})
+	rows := &fakeRows{jobs: []job{{"1", "a", 1}, {"1", "b", 2}}}
+	loaded, err := LoadRows(heap, rows, scanJob)
+	assert.Equal(t, ErrDuplicateID, err)
+	assert.Equal(t, 1, loaded)
+
+	failure := errors.New("connection reset")
+	heap.Clear()
+	loaded, err = LoadRows(heap, &fakeRows{jobs: []job{{"2", "c", 1}}, err: failure}, scanJob)
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 1, loaded)
+}
+
+func TestFullPairingHeap_GetOrPush(t *testing.T) {
+	heap := NewSyncFullPairingHeap[string, int](nil, lt, HeapConfig{})
+	value, priority, loaded := heap.GetOrPush("job-1", "first", 2)
+	assert.False(t, loaded)
+	assert.Equal(t, "first", value)
+	assert.Equal(t, 2, priority)
+
+	value, priority, loaded = heap.GetOrPush("job-1", "retried", 0)
+	assert.True(t, loaded)
+	assert.Equal(t, "first", value)
+	assert.Equal(t, 2, priority)
+
+	assert.False(t, heap.PushIfAbsent("job-1", "retried", 0))
+	assert.True(t, heap.PushIfAbsent("job-2", "second", 1))
+	assert.Equal(t, 2, heap.Length())
+	assert.Equal(t, []string{"second", "first"}, heap.DrainValues())
+}
+
+func TestFullPairingHeap_PopCommit(t *testing.T) {
+	heap := NewFullPairingHeap[string, int](nil, lt, HeapConfig{})
+	require.NoError(t, heap.PushWithID("a", "first", 1))
+	require.NoError(t, heap.PushWithID("b", "second", 2))
+	assert.Equal(t, ErrDuplicateID, heap.PushWithID("a", "again", 0))
+
+	failure := errors.New("update failed")
+	_, _, _, err := heap.PopCommit(func(id string, value string, priority int) error {
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 2, heap.Length())
+
+	var committed []string
+	commit := func(id string, value string, priority int) error {
+		committed = append(committed, id)
+		return nil
+	}
+	id, value, priority, err := heap.PopCommit(commit)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", id)
+	assert.Equal(t, "first", value)
+	assert.Equal(t, 1, priority)
+	_, err = heap.GetValue("a")
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+
+	heap.PopCommit(commit)
+	_, _, _, err = heap.PopCommit(commit)
+	assert.Equal(t, ErrHeapEmpty, err)
+	assert.Equal(t, []string{"a", "b"}, committed)
+}
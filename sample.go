@@ -0,0 +1,110 @@
+package heapcraft
+
+import (
+	"iter"
+	"math"
+	"math/rand/v2"
+)
+
+// WeightedSampler draws a weighted random sample of k elements without
+// replacement from a stream of any length, in O(k) memory, using the
+// reservoir algorithms of Efraimidis and Spirakis. Every element gets a random
+// key u^(1/w), where u is uniform on (0, 1] and w is the element's weight, and
+// the sample is the k elements with the largest keys, kept in a min-heap like
+// NLargestDary so that the smallest key is the one displaced.
+//
+// While the reservoir fills, every element is keyed as in A-Res. After that
+// the sampler switches to A-ExpJ, which draws how much weight to skip before
+// the next element enters the reservoir, so most elements cost a subtraction
+// rather than a random number and a heap operation. Both yield the same
+// distribution. Keys are kept as logarithms so that large weights do not round
+// every key to 1. A WeightedSampler is not safe for concurrent use.
+type WeightedSampler[V any] struct {
+	k    int
+	rng  *rand.Rand
+	heap *DaryHeap[V, float64]
+	// jump is the weight left to skip before the next element enters the
+	// reservoir, once it is full.
+	jump float64
+}
+
+// K returns the size of the sample.
+func (s *WeightedSampler[V]) K() int { return s.k }
+
+// Length returns the number of elements currently in the sample, which is at
+// most K.
+func (s *WeightedSampler[V]) Length() int { return s.heap.Length() }
+
+// uniform returns a random number in (0, 1], so that its logarithm is finite.
+func (s *WeightedSampler[V]) uniform() float64 {
+	if s.rng == nil {
+		return 1 - rand.Float64()
+	}
+	return 1 - s.rng.Float64()
+}
+
+// drawJump draws the weight to skip before the element that displaces the
+// smallest key in the reservoir.
+func (s *WeightedSampler[V]) drawJump() {
+	s.jump = math.Log(s.uniform()) / s.heap.data[0].priority
+}
+
+// Add offers an element with the given weight to the sampler and reports
+// whether it entered the sample. Elements whose weight is not positive, NaN
+// included, are never sampled.
+func (s *WeightedSampler[V]) Add(value V, weight float64) bool {
+	if !(weight > 0) || s.k <= 0 {
+		return false
+	}
+	if s.heap.Length() < s.k {
+		s.heap.Push(value, math.Log(s.uniform())/weight)
+		if s.heap.Length() == s.k {
+			s.drawJump()
+		}
+		return true
+	}
+
+	s.jump -= weight
+	if s.jump > 0 {
+		return false
+	}
+	// The element's key is conditioned on beating the smallest key t, so u
+	// is drawn uniformly from (t^w, 1] instead of (0, 1].
+	floor := math.Exp(weight * s.heap.data[0].priority)
+	key := math.Log(floor+(1-floor)*s.uniform()) / weight
+	s.heap.data[0] = HeapNode[V, float64]{value: value, priority: key}
+	s.heap.siftDown(0)
+	s.drawJump()
+	return true
+}
+
+// Sample returns the values in the sample, in no particular order. The
+// sampler is left unchanged and can keep accepting elements.
+func (s *WeightedSampler[V]) Sample() []V {
+	values := make([]V, s.heap.Length())
+	for i, node := range s.heap.data {
+		values[i] = node.value
+	}
+	return values
+}
+
+// Reset discards the sample, keeping the allocated capacity.
+func (s *WeightedSampler[V]) Reset() {
+	s.heap.Reset()
+	s.jump = 0
+}
+
+// SampleK draws a weighted random sample of k values from stream without
+// replacement, where weight returns the weight of each value. A value is
+// more likely to be drawn the larger its weight, and values whose weight is
+// not positive are never drawn. If the stream holds k values or fewer with
+// positive weights, all of them are returned. The sample is in no particular
+// order. It uses the global random source; use NewWeightedSampler with a
+// seeded source for reproducible samples.
+func SampleK[V any](k int, stream iter.Seq[V], weight func(V) float64) []V {
+	sampler := NewWeightedSampler[V](k, nil)
+	for value := range stream {
+		sampler.Add(value, weight(value))
+	}
+	return sampler.Sample()
+}
@@ -0,0 +1,14 @@
+package heapcraft
+
+import "math/rand/v2"
+
+// NewWeightedSampler creates an empty WeightedSampler that keeps a sample of
+// k elements. Random numbers are drawn from rng, or from the global source if
+// rng is nil.
+func NewWeightedSampler[V any](k int, rng *rand.Rand) *WeightedSampler[V] {
+	return &WeightedSampler[V]{
+		k:    k,
+		rng:  rng,
+		heap: NewBinaryHeapWithConfig(make([]HeapNode[V, float64], 0, max(k, 0)), orderedLess[float64], DaryHeapConfig{}),
+	}
+}
@@ -0,0 +1,89 @@
+package heapcraft
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedSampler_Basics(t *testing.T) {
+	s := NewWeightedSampler[string](3, rand.New(rand.NewPCG(1, 2)))
+	assert.Equal(t, 3, s.K())
+	assert.True(t, s.Add("a", 1))
+	assert.False(t, s.Add("zero", 0))
+	assert.False(t, s.Add("negative", -2))
+	assert.False(t, s.Add("nan", math.NaN()))
+	assert.True(t, s.Add("b", 2))
+	sample := s.Sample()
+	slices.Sort(sample)
+	assert.Equal(t, []string{"a", "b"}, sample)
+
+	for i := 0; i < 100; i++ {
+		s.Add("c", 1)
+	}
+	assert.Equal(t, 3, s.Length())
+	s.Reset()
+	assert.Zero(t, s.Length())
+
+	assert.Empty(t, NewWeightedSampler[int](0, nil).Sample())
+	assert.False(t, NewWeightedSampler[int](-1, nil).Add(1, 1))
+}
+
+// TestWeightedSampler_Distribution checks the inclusion frequency of every
+// element against a reference implementation of A-Res, which keys every
+// element and keeps the k largest keys.
+func TestWeightedSampler_Distribution(t *testing.T) {
+	const n, k, trials = 40, 5, 20_000
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = float64(i%8 + 1)
+	}
+	weights[7] = 1000
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	got, want := make([]float64, n), make([]float64, n)
+	for trial := 0; trial < trials; trial++ {
+		s := NewWeightedSampler[int](k, rng)
+		ref := NewTopK[int, float64](k, gtFloat)
+		for i, w := range weights {
+			s.Add(i, w)
+			ref.Add(i, math.Pow(1-rng.Float64(), 1/w))
+		}
+		sample := s.Sample()
+		require.Len(t, sample, k)
+		for _, i := range sample {
+			got[i]++
+		}
+		for _, node := range ref.Result() {
+			want[node.value]++
+		}
+	}
+
+	for i := range weights {
+		assert.InDelta(t, want[i]/trials, got[i]/trials, 0.02, "element %d", i)
+	}
+	assert.Greater(t, got[7]/trials, 0.99)
+}
+
+func gtFloat(a, b float64) bool { return a > b }
+
+func TestSampleK(t *testing.T) {
+	stream := func(yield func(string) bool) {
+		for _, v := range []string{"a", "bb", "", "ccc"} {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+	weight := func(v string) float64 { return float64(len(v)) }
+
+	sample := SampleK(5, stream, weight)
+	slices.Sort(sample)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, sample)
+	assert.Len(t, SampleK(2, stream, weight), 2)
+	assert.Empty(t, SampleK(0, stream, weight))
+}
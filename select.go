@@ -0,0 +1,124 @@
+package heapcraft
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// heapSelectRatio is the ratio of input length to k above which SelectK
+// uses heap selection instead of quickselect.
+const heapSelectRatio = 64
+
+// SelectK reorders data in place so that its first k elements are the k
+// elements that would be popped first from a heap ordered by cmp, and returns
+// them as data[:k] in no particular order. Unless k is small relative to the
+// length of data, it uses quickselect, which runs in expected O(n) time
+// instead of the O(n log k) of keeping a heap of size k, and falls back to heap
+// selection if partitioning stops making progress. If k is greater than the
+// length of data, all of data is returned.
+func SelectK[V any, P any](k int, data []HeapNode[V, P], cmp func(a, b P) bool) []HeapNode[V, P] {
+	if k <= 0 {
+		return data[:0]
+	}
+	if k >= len(data) {
+		return data
+	}
+
+	// Keeping a heap of the best k elements is cheaper when k is small
+	// relative to n, since most elements are rejected with one comparison.
+	if k < len(data)/heapSelectRatio {
+		heapSelect(k, data, cmp)
+		return data[:k]
+	}
+
+	lo, hi := 0, len(data)
+	budget := 2 * bits.Len(uint(len(data)))
+	for hi-lo > 1 {
+		if budget == 0 {
+			heapSelect(k-lo, data[lo:hi], cmp)
+			break
+		}
+		budget--
+
+		lt, gt := partitionNodes(data[lo:hi], cmp)
+		switch {
+		case k < lo+lt:
+			hi = lo + lt
+		case k >= lo+gt:
+			lo += gt
+		default:
+			return data[:k]
+		}
+	}
+	return data[:k]
+}
+
+// SelectKSeq returns the k elements of seq that would be popped first from a
+// heap ordered by cmp, in no particular order. Unlike SelectK it does not need
+// the whole input at once: it keeps a heap of the best k elements seen so far,
+// so it runs in O(n log k) time and O(k) memory over a stream of any length.
+func SelectKSeq[V any, P any](k int, seq iter.Seq2[V, P], cmp func(a, b P) bool) []HeapNode[V, P] {
+	if k <= 0 {
+		return []HeapNode[V, P]{}
+	}
+
+	// The heap is ordered in reverse so that its root is the worst of the
+	// elements kept, which is the one displaced by a better element.
+	heap := NewBinaryHeapWithConfig(make([]HeapNode[V, P], 0, k), Reverse(cmp), DaryHeapConfig{})
+	for value, priority := range seq {
+		switch {
+		case heap.Length() < k:
+			heap.Push(value, priority)
+		case cmp(priority, heap.data[0].priority):
+			heap.data[0] = HeapNode[V, P]{value: value, priority: priority}
+			heap.siftDown(0)
+		}
+	}
+	return heap.data
+}
+
+// heapSelect moves the k elements of data that come first according to cmp
+// into data[:k]. It builds a reverse-ordered binary heap over data[:k] in
+// place and replaces its root whenever a better element is found.
+func heapSelect[V any, P any](k int, data []HeapNode[V, P], cmp func(a, b P) bool) {
+	heap := NewBinaryHeapWithConfig(data[:k], Reverse(cmp), DaryHeapConfig{})
+	for i := k; i < len(data); i++ {
+		if cmp(data[i].priority, data[0].priority) {
+			data[0], data[i] = data[i], data[0]
+			heap.siftDown(0)
+		}
+	}
+}
+
+// partitionNodes performs a three-way partition of data around a
+// median-of-three pivot. On return, data[:lt] come before the pivot according
+// to cmp, data[lt:gt] are tied with it and data[gt:] come after it.
+func partitionNodes[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool) (lt int, gt int) {
+	a, b, c := data[0].priority, data[len(data)/2].priority, data[len(data)-1].priority
+	if cmp(b, a) {
+		a, b = b, a
+	}
+	if cmp(c, b) {
+		b = c
+		if cmp(b, a) {
+			b = a
+		}
+	}
+	pivot := b
+
+	lt, gt = 0, len(data)
+	for i := 0; i < gt; {
+		switch {
+		case cmp(data[i].priority, pivot):
+			data[lt], data[i] = data[i], data[lt]
+			lt++
+			i++
+		case cmp(pivot, data[i].priority):
+			gt--
+			data[i], data[gt] = data[gt], data[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
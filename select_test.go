@@ -0,0 +1,190 @@
+package heapcraft
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// selectedPriorities returns the priorities of nodes in ascending order.
+func selectedPriorities(nodes []HeapNode[int, int]) []int {
+	priorities := make([]int, len(nodes))
+	for i, node := range nodes {
+		priorities[i] = node.priority
+	}
+	slices.Sort(priorities)
+	return priorities
+}
+
+// randomNodes returns n nodes with priorities drawn from [0, limit).
+func randomNodes(r *rand.Rand, n int, limit int) []HeapNode[int, int] {
+	nodes := make([]HeapNode[int, int], n)
+	for i := range nodes {
+		p := r.Intn(limit)
+		nodes[i] = CreateHeapNode(p, p)
+	}
+	return nodes
+}
+
+func TestSelectK(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for _, limit := range []int{3, 50, 1_000_000} {
+		for _, k := range []int{1, 10, 250, 999} {
+			data := randomNodes(r, 1000, limit)
+			expected := selectedPriorities(data)[:k]
+
+			selected := SelectK(k, data, lt)
+			assert.Len(t, selected, k)
+			assert.Equal(t, expected, selectedPriorities(selected))
+			assert.Len(t, data, 1000)
+		}
+	}
+
+	data := randomNodes(r, 10, 100)
+	assert.Len(t, SelectK(0, data, lt), 0)
+	assert.Len(t, SelectK(-1, data, lt), 0)
+	assert.Len(t, SelectK(20, data, lt), 10)
+}
+
+func TestSelectK_MaxOrder(t *testing.T) {
+	data := []HeapNode[int, int]{
+		CreateHeapNode(7, 7),
+		CreateHeapNode(2, 2),
+		CreateHeapNode(9, 9),
+		CreateHeapNode(1, 1),
+		CreateHeapNode(5, 5),
+		CreateHeapNode(3, 3),
+	}
+	assert.Equal(t, []int{5, 7, 9}, selectedPriorities(SelectK(3, data, gt)))
+}
+
+func TestSelectK_SortedInput(t *testing.T) {
+	data := make([]HeapNode[int, int], 10_000)
+	for i := range data {
+		data[i] = CreateHeapNode(i, i)
+	}
+	expected := selectedPriorities(data)[:1000]
+	slices.Reverse(data)
+	assert.Equal(t, expected, selectedPriorities(SelectK(1000, data, lt)))
+}
+
+func TestHeapSelect(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	data := randomNodes(r, 500, 1000)
+	expected := selectedPriorities(data)[:40]
+
+	heapSelect(40, data, lt)
+	assert.Equal(t, expected, selectedPriorities(data[:40]))
+}
+
+func TestSelectKSeq(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	data := randomNodes(r, 1000, 10_000)
+	expected := selectedPriorities(data)[:25]
+
+	seq := func(yield func(int, int) bool) {
+		for _, node := range data {
+			if !yield(node.value, node.priority) {
+				return
+			}
+		}
+	}
+	assert.Equal(t, expected, selectedPriorities(SelectKSeq(25, seq, lt)))
+	assert.Len(t, SelectKSeq(2000, seq, lt), 1000)
+	assert.Empty(t, SelectKSeq(0, seq, lt))
+}
+
+func TestNLargestDary_LeavesDataUnchanged(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	data := randomNodes(r, 200, 1000)
+	original := slices.Clone(data)
+
+	heap := NLargestDary(10, 4, data, lt, false)
+	assert.Equal(t, original, data)
+	assert.Equal(t, selectedPriorities(original)[190:], heap.DrainPriorities())
+}
+
+func TestNLargestFromSeq(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	data := randomNodes(r, 500, 10_000)
+	seq := func(yield func(int, int) bool) {
+		for _, node := range data {
+			if !yield(node.value, node.priority) {
+				return
+			}
+		}
+	}
+	sorted := selectedPriorities(data)
+
+	largest := NLargestFromSeq(20, 3, seq, lt, DaryHeapConfig{})
+	assert.NoError(t, largest.Verify())
+	assert.Equal(t, sorted[480:], largest.DrainPriorities())
+
+	smallest := NSmallestFromSeq(20, 3, seq, gt, DaryHeapConfig{})
+	expected := slices.Clone(sorted[:20])
+	slices.Reverse(expected)
+	assert.Equal(t, expected, smallest.DrainPriorities())
+
+	assert.True(t, NLargestFromSeq(0, 2, seq, lt, DaryHeapConfig{}).IsEmpty())
+}
+
+func TestNLargestFromReader(t *testing.T) {
+	source := NewBinaryHeap([]HeapNode[string, int]{
+		CreateHeapNode("a", 5), CreateHeapNode("b", 1), CreateHeapNode("c", 9), CreateHeapNode("d", 3),
+	}, lt, false)
+	var array bytes.Buffer
+	assert.NoError(t, source.StreamJSON(&array))
+	lines := "{\"value\":\"a\",\"priority\":5}\n{\"value\":\"b\",\"priority\":1}\n" +
+		"{\"value\":\"c\",\"priority\":9}\n{\"value\":\"d\",\"priority\":3}\n"
+
+	for name, input := range map[string]string{"array": array.String(), "lines": lines} {
+		largest, err := NLargestFromReader[string, int](2, 2, strings.NewReader(input), lt, DaryHeapConfig{})
+		assert.NoError(t, err, name)
+		assert.Equal(t, []string{"a", "c"}, largest.DrainValues(), name)
+
+		smallest, err := NSmallestFromReader[string, int](3, 4, strings.NewReader(input), gt, DaryHeapConfig{})
+		assert.NoError(t, err, name)
+		assert.Equal(t, []int{5, 3, 1}, smallest.DrainPriorities(), name)
+	}
+
+	empty, err := NLargestFromReader[string, int](2, 2, strings.NewReader("  "), lt, DaryHeapConfig{})
+	assert.NoError(t, err)
+	assert.True(t, empty.IsEmpty())
+
+	for _, input := range []string{`[{"value":"a","priority":1}`, `{"value":"a","priority":"x"}`, `[{"value":"a"`} {
+		_, err := NLargestFromReader[string, int](2, 2, strings.NewReader(input), lt, DaryHeapConfig{})
+		assert.Error(t, err, input)
+	}
+}
+
+func BenchmarkNSmallest(b *testing.B) {
+	r := rand.New(rand.NewSource(42))
+	data := randomNodes(r, 100_000, 1_000_000)
+	seq := func(yield func(int, int) bool) {
+		for _, node := range data {
+			if !yield(node.value, node.priority) {
+				return
+			}
+		}
+	}
+
+	for _, k := range []int{100, 10_000} {
+		b.Run(fmt.Sprintf("SelectK/k=%d", k), func(b *testing.B) {
+			buf := make([]HeapNode[int, int], len(data))
+			for i := 0; i < b.N; i++ {
+				copy(buf, data)
+				SelectK(k, buf, lt)
+			}
+		})
+		b.Run(fmt.Sprintf("SelectKSeq/k=%d", k), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				SelectKSeq(k, seq, lt)
+			}
+		})
+	}
+}
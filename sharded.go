@@ -0,0 +1,247 @@
+package heapcraft
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// heapShard is one partition of a ShardedSyncHeap, a binary heap guarded by
+// its own mutex.
+type heapShard[V any, P any] struct {
+	mu   sync.Mutex
+	heap *DaryHeap[V, P]
+}
+
+// root returns the priority of the shard's root element under the shard's
+// lock, and false if the shard is empty.
+func (s *heapShard[V, P]) root() (P, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := s.heap.PeekPriority()
+	return p, err == nil
+}
+
+// ShardedSyncHeap is a heap that is safe for concurrent use and spreads its
+// elements over a fixed number of independently locked shards, so that
+// pushes from many goroutines contend on different locks instead of a single
+// one. Pushes are distributed round-robin. Pop scans the root of every shard,
+// picks the best one and pops from that shard.
+//
+// The ordering is relaxed. When no other goroutine modifies the heap during a
+// Pop, it returns the exact global best element. Otherwise a Pop can miss an
+// element that a concurrent Push added to a shard it had already scanned, or
+// find that a concurrent Pop took the root it chose and return that shard's
+// next element instead. The number of elements that can be popped ahead of
+// their turn is therefore bounded by the number of Push and Pop calls that
+// overlap the Pop; with no overlap there are no inversions. Elements of equal
+// priority pop in no particular order.
+type ShardedSyncHeap[V any, P any] struct {
+	shards []*heapShard[V, P]
+	cmp    func(a, b P) bool
+	next   atomic.Uint64
+	size   atomic.Int64
+}
+
+// Shards returns the number of shards the heap is split into.
+func (h *ShardedSyncHeap[V, P]) Shards() int { return len(h.shards) }
+
+// Push adds an element to the next shard in round-robin order, locking only
+// that shard.
+func (h *ShardedSyncHeap[V, P]) Push(value V, priority P) {
+	s := h.shards[h.next.Add(1)%uint64(len(h.shards))]
+	// The size is raised first so that a concurrent Pop of this element can
+	// never drive it below zero.
+	h.size.Add(1)
+	s.mu.Lock()
+	s.heap.Push(value, priority)
+	s.mu.Unlock()
+}
+
+// best returns the shard whose root was the best while scanning, or nil if
+// every shard was empty. Each shard is locked only while its root is read.
+func (h *ShardedSyncHeap[V, P]) best() *heapShard[V, P] {
+	var chosen *heapShard[V, P]
+	var bestPriority P
+	for _, s := range h.shards {
+		p, ok := s.root()
+		if ok && (chosen == nil || h.cmp(p, bestPriority)) {
+			chosen, bestPriority = s, p
+		}
+	}
+	return chosen
+}
+
+// pop is an internal method that removes the root of the best shard. If the
+// chosen shard was emptied by a concurrent Pop in the meantime, the scan is
+// repeated.
+func (h *ShardedSyncHeap[V, P]) pop() (V, P, error) {
+	for {
+		s := h.best()
+		if s == nil {
+			v, p := zeroValuePair[V, P]()
+			return v, p, ErrHeapEmpty
+		}
+		s.mu.Lock()
+		v, p, err := s.heap.Pop()
+		s.mu.Unlock()
+		if err == nil {
+			h.size.Add(-1)
+			return v, p, nil
+		}
+	}
+}
+
+// peek is an internal method that returns the root of the best shard without
+// removing it.
+func (h *ShardedSyncHeap[V, P]) peek() (V, P, error) {
+	for {
+		s := h.best()
+		if s == nil {
+			v, p := zeroValuePair[V, P]()
+			return v, p, ErrHeapEmpty
+		}
+		s.mu.Lock()
+		v, p, err := s.heap.Peek()
+		s.mu.Unlock()
+		if err == nil {
+			return v, p, nil
+		}
+	}
+}
+
+// Pop removes and returns the value and priority of the best element, subject
+// to the relaxed ordering described on ShardedSyncHeap. Returns zero values
+// and an error if the heap is empty.
+func (h *ShardedSyncHeap[V, P]) Pop() (V, P, error) { return h.pop() }
+
+// PopValue removes and returns the value of the best element. Returns zero
+// value and an error if the heap is empty.
+func (h *ShardedSyncHeap[V, P]) PopValue() (V, error) { return valueFromNode(h.pop()) }
+
+// PopPriority removes and returns the priority of the best element. Returns
+// zero value and an error if the heap is empty.
+func (h *ShardedSyncHeap[V, P]) PopPriority() (P, error) { return priorityFromNode(h.pop()) }
+
+// PopIf removes and returns the best element only if pred reports true for
+// its value and priority. The check and the removal happen under the lock of
+// the chosen shard, so no other goroutine can take that element in between.
+// If pred reports false the element stays in the heap and is returned with
+// false. pred must not call back into the heap.
+func (h *ShardedSyncHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	for {
+		s := h.best()
+		if s == nil {
+			v, p := zeroValuePair[V, P]()
+			return v, p, false, ErrHeapEmpty
+		}
+		s.mu.Lock()
+		v, p, ok, err := popIf(s.heap.Peek, s.heap.Pop, pred)
+		s.mu.Unlock()
+		if err == nil {
+			if ok {
+				h.size.Add(-1)
+			}
+			return v, p, ok, nil
+		}
+	}
+}
+
+// Peek returns the value and priority of the best element without removing
+// it. Returns zero values and an error if the heap is empty.
+func (h *ShardedSyncHeap[V, P]) Peek() (V, P, error) { return h.peek() }
+
+// PeekValue returns the value of the best element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *ShardedSyncHeap[V, P]) PeekValue() (V, error) { return valueFromNode(h.peek()) }
+
+// PeekPriority returns the priority of the best element without removing it.
+// Returns zero value and an error if the heap is empty.
+func (h *ShardedSyncHeap[V, P]) PeekPriority() (P, error) { return priorityFromNode(h.peek()) }
+
+// Drain removes elements until the heap is empty and returns them in
+// priority order, subject to the relaxed ordering described on
+// ShardedSyncHeap. Elements pushed concurrently may or may not be included.
+func (h *ShardedSyncHeap[V, P]) Drain() []HeapNode[V, P] {
+	nodes := make([]HeapNode[V, P], 0, h.Length())
+	for {
+		v, p, err := h.pop()
+		if err != nil {
+			return nodes
+		}
+		nodes = append(nodes, HeapNode[V, P]{value: v, priority: p})
+	}
+}
+
+// DrainValues removes elements until the heap is empty and returns their
+// values in priority order, subject to the relaxed ordering described on
+// ShardedSyncHeap.
+func (h *ShardedSyncHeap[V, P]) DrainValues() []V {
+	nodes := h.Drain()
+	values := make([]V, len(nodes))
+	for i, node := range nodes {
+		values[i] = node.value
+	}
+	return values
+}
+
+// DrainPriorities removes elements until the heap is empty and returns their
+// priorities in priority order, subject to the relaxed ordering described on
+// ShardedSyncHeap.
+func (h *ShardedSyncHeap[V, P]) DrainPriorities() []P {
+	nodes := h.Drain()
+	priorities := make([]P, len(nodes))
+	for i, node := range nodes {
+		priorities[i] = node.priority
+	}
+	return priorities
+}
+
+// forEach calls fn for every element in the heap, shard by shard, holding
+// each shard's lock while it is visited.
+func (h *ShardedSyncHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, s := range h.shards {
+		s.mu.Lock()
+		for _, node := range s.heap.data {
+			fn(node.value, node.priority)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Export returns a copy of the elements in the heap according to opts. The
+// heap itself is not modified. Shards are visited one at a time, so the
+// result is not an atomic snapshot if other goroutines modify the heap.
+func (h *ShardedSyncHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(h.Length(), h.forEach, h.cmp, opts)
+}
+
+// Clear removes all elements from every shard.
+func (h *ShardedSyncHeap[V, P]) Clear() {
+	for _, s := range h.shards {
+		s.mu.Lock()
+		h.size.Add(-int64(s.heap.Length()))
+		s.heap.Clear()
+		s.mu.Unlock()
+	}
+}
+
+// Length returns the number of elements in the heap.
+func (h *ShardedSyncHeap[V, P]) Length() int { return int(h.size.Load()) }
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *ShardedSyncHeap[V, P]) IsEmpty() bool { return h.Length() == 0 }
+
+// Verify checks every shard under its lock with DaryHeap.Verify. It is
+// intended for tests and debugging and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (h *ShardedSyncHeap[V, P]) Verify() error {
+	for _, s := range h.shards {
+		s.mu.Lock()
+		err := s.heap.Verify()
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
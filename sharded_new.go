@@ -0,0 +1,21 @@
+package heapcraft
+
+import "runtime"
+
+// NewShardedSyncHeap creates an empty ShardedSyncHeap split into the given
+// number of shards. A shard count below 1 uses runtime.GOMAXPROCS(0). The
+// comparison function determines the heap order (min or max), and config sets
+// the options of every shard's binary heap.
+func NewShardedSyncHeap[V any, P any](shards int, cmp func(a, b P) bool, config DaryHeapConfig) *ShardedSyncHeap[V, P] {
+	if shards < 1 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	heap := &ShardedSyncHeap[V, P]{
+		shards: make([]*heapShard[V, P], shards),
+		cmp:    cmp,
+	}
+	for i := range heap.shards {
+		heap.shards[i] = &heapShard[V, P]{heap: NewBinaryHeapWithConfig[V, P](nil, cmp, config)}
+	}
+	return heap
+}
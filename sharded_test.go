@@ -0,0 +1,125 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedSyncHeapExactWithoutConcurrency(t *testing.T) {
+	h := NewShardedSyncHeap[int, int](4, lt, DaryHeapConfig{})
+	assert.Equal(t, 4, h.Shards())
+	_, _, err := h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	rng := rand.New(rand.NewSource(3))
+	want := make([]int, 500)
+	for i := range want {
+		want[i] = rng.Intn(1000)
+		h.Push(i, want[i])
+	}
+	require.NoError(t, h.Verify())
+	assert.Equal(t, len(want), h.Length())
+
+	p, err := h.PeekPriority()
+	require.NoError(t, err)
+	slices.Sort(want)
+	assert.Equal(t, want[0], p)
+	assert.Equal(t, want, h.DrainPriorities())
+	assert.True(t, h.IsEmpty())
+}
+
+func TestShardedSyncHeapDefaultShards(t *testing.T) {
+	h := NewShardedSyncHeap[int, int](0, lt, DaryHeapConfig{})
+	assert.Equal(t, runtime.GOMAXPROCS(0), h.Shards())
+}
+
+func TestShardedSyncHeapConcurrent(t *testing.T) {
+	const workers, perWorker = 16, 200
+	h := NewShardedSyncHeap[int, int](8, lt, DaryHeapConfig{})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var popped []int
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				h.Push(w, w*perWorker+i)
+				if i%2 == 0 {
+					if p, err := h.PopPriority(); err == nil {
+						mu.Lock()
+						popped = append(popped, p)
+						mu.Unlock()
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	require.NoError(t, h.Verify())
+	assert.Equal(t, workers*perWorker-len(popped), h.Length())
+	rest := h.DrainPriorities()
+	assert.True(t, slices.IsSorted(rest))
+
+	all := append(popped, rest...)
+	slices.Sort(all)
+	for i, p := range all {
+		require.Equal(t, i, p)
+	}
+}
+
+func TestShardedSyncHeapClearExport(t *testing.T) {
+	h := NewShardedSyncHeap[string, int](3, gt, DaryHeapConfig{})
+	h.Push("a", 1)
+	h.Push("b", 5)
+	h.Push("c", 3)
+
+	exported := h.Export(ExportOptions[string, int]{})
+	assert.Equal(t, []int{5, 3, 1}, nodePriorities(exported))
+	assert.Equal(t, 3, h.Length())
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	_, err := h.PeekValue()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+// -------------------------------- Sharded Heap Benchmarks --------------------------------
+
+func BenchmarkShardedSyncHeapParallelPushPop(b *testing.B) {
+	h := NewShardedSyncHeap[int, int](0, lt, DaryHeapConfig{})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			h.Push(i, i)
+			if i%2 == 1 {
+				h.Pop()
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncDaryHeapParallelPushPop(b *testing.B) {
+	h := NewSyncDaryHeap[int](2, nil, lt, false)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			h.Push(i, i)
+			if i%2 == 1 {
+				h.Pop()
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package heapcraft
+
+import (
+	"iter"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Weight is the set of numeric types that can be used as edge weights by
+// Dijkstra and AStar.
+type Weight interface {
+	constraints.Integer | constraints.Float
+}
+
+// ShortestPaths holds the result of a single-source shortest path search: the
+// distance to every reachable vertex and the predecessor of each vertex on a
+// shortest path from the source.
+type ShortestPaths[K comparable, W Weight] struct {
+	source K
+	dist   map[K]W
+	prev   map[K]K
+}
+
+// Distance returns the length of the shortest path from the source to
+// vertex, and false if vertex is unreachable.
+func (s *ShortestPaths[K, W]) Distance(vertex K) (W, bool) {
+	d, ok := s.dist[vertex]
+	return d, ok
+}
+
+// PathTo returns the vertices on a shortest path from the source to vertex,
+// including both ends. Returns nil if vertex is unreachable.
+func (s *ShortestPaths[K, W]) PathTo(vertex K) []K {
+	if _, ok := s.dist[vertex]; !ok {
+		return nil
+	}
+	return buildPath(s.prev, s.source, vertex)
+}
+
+// buildPath follows prev from target back to source and returns the path in
+// forward order.
+func buildPath[K comparable](prev map[K]K, source K, target K) []K {
+	path := []K{target}
+	for vertex := target; vertex != source; {
+		vertex = prev[vertex]
+		path = append(path, vertex)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Dijkstra computes shortest paths from source to every vertex reachable
+// from it. neighbors returns the outgoing edges of a vertex as pairs of
+// target vertex and weight; weights must not be negative. The graph is
+// explored lazily, so it can be implicit or larger than what is reachable.
+func Dijkstra[K comparable, W Weight](source K, neighbors func(vertex K) iter.Seq2[K, W]) *ShortestPaths[K, W] {
+	paths := &ShortestPaths[K, W]{
+		source: source,
+		dist:   make(map[K]W),
+		prev:   make(map[K]K),
+	}
+	queue := NewAddressableMinQueue[K](func(a, b W) bool { return a < b }, HeapConfig{})
+	queue.Push(source, 0)
+
+	for !queue.IsEmpty() {
+		vertex, d, _ := queue.Pop()
+		paths.dist[vertex] = d
+		for next, weight := range neighbors(vertex) {
+			if _, settled := paths.dist[next]; settled {
+				continue
+			}
+			if relaxed, _ := queue.Relax(next, d+weight); relaxed {
+				paths.prev[next] = vertex
+			}
+		}
+	}
+	return paths
+}
+
+// AStar finds a shortest path from source to target, guided by heuristic,
+// which estimates the remaining distance from a vertex to target. The
+// heuristic must never overestimate that distance and must be consistent for
+// the returned path to be shortest; a heuristic that always returns zero
+// makes AStar behave like Dijkstra. Returns the path including both ends and
+// its length, or false if target is unreachable.
+func AStar[K comparable, W Weight](source K, target K, neighbors func(vertex K) iter.Seq2[K, W], heuristic func(vertex K) W) ([]K, W, bool) {
+	cost := map[K]W{source: 0}
+	prev := make(map[K]K)
+	closed := make(map[K]struct{})
+	queue := NewAddressableMinQueue[K](func(a, b W) bool { return a < b }, HeapConfig{})
+	queue.Push(source, heuristic(source))
+
+	for !queue.IsEmpty() {
+		vertex, _, _ := queue.Pop()
+		if vertex == target {
+			return buildPath(prev, source, target), cost[target], true
+		}
+		closed[vertex] = struct{}{}
+
+		for next, weight := range neighbors(vertex) {
+			if _, done := closed[next]; done {
+				continue
+			}
+			c := cost[vertex] + weight
+			if known, seen := cost[next]; seen && c >= known {
+				continue
+			}
+			cost[next] = c
+			prev[next] = vertex
+			queue.Relax(next, c+heuristic(next))
+		}
+	}
+	return nil, 0, false
+}
@@ -0,0 +1,140 @@
+package heapcraft
+
+import (
+	"iter"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphEdge is a weighted edge in a test graph.
+type graphEdge struct {
+	to     int
+	weight int
+}
+
+// adjacency returns a neighbors function over an adjacency list.
+func adjacency(graph map[int][]graphEdge) func(int) iter.Seq2[int, int] {
+	return func(vertex int) iter.Seq2[int, int] {
+		return func(yield func(int, int) bool) {
+			for _, edge := range graph[vertex] {
+				if !yield(edge.to, edge.weight) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	graph := map[int][]graphEdge{
+		0: {{1, 4}, {2, 1}},
+		2: {{1, 2}, {3, 7}},
+		1: {{3, 1}},
+		4: {{0, 1}},
+	}
+	paths := Dijkstra(0, adjacency(graph))
+
+	d, ok := paths.Distance(3)
+	require.True(t, ok)
+	assert.Equal(t, 4, d)
+	assert.Equal(t, []int{0, 2, 1, 3}, paths.PathTo(3))
+	assert.Equal(t, []int{0}, paths.PathTo(0))
+
+	_, ok = paths.Distance(4)
+	assert.False(t, ok)
+	assert.Nil(t, paths.PathTo(4))
+}
+
+func TestDijkstra_MatchesBellmanFord(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	const n = 60
+	graph := make(map[int][]graphEdge)
+	for i := 0; i < 400; i++ {
+		from, to := r.Intn(n), r.Intn(n)
+		graph[from] = append(graph[from], graphEdge{to, r.Intn(20)})
+	}
+
+	dist := map[int]int{0: 0}
+	for i := 0; i < n; i++ {
+		for from, edges := range graph {
+			d, ok := dist[from]
+			if !ok {
+				continue
+			}
+			for _, edge := range edges {
+				if known, seen := dist[edge.to]; !seen || d+edge.weight < known {
+					dist[edge.to] = d + edge.weight
+				}
+			}
+		}
+	}
+
+	paths := Dijkstra(0, adjacency(graph))
+	for vertex := 0; vertex < n; vertex++ {
+		expected, reachable := dist[vertex]
+		d, ok := paths.Distance(vertex)
+		assert.Equal(t, reachable, ok, vertex)
+		assert.Equal(t, expected, d, vertex)
+		if !ok {
+			continue
+		}
+
+		// The reported path must exist in the graph and have the reported length.
+		path := paths.PathTo(vertex)
+		length := 0
+		for i := 1; i < len(path); i++ {
+			best := -1
+			for _, edge := range graph[path[i-1]] {
+				if edge.to == path[i] && (best < 0 || edge.weight < best) {
+					best = edge.weight
+				}
+			}
+			require.GreaterOrEqual(t, best, 0)
+			length += best
+		}
+		assert.Equal(t, d, length, vertex)
+	}
+}
+
+func TestAStar_Grid(t *testing.T) {
+	type cell struct{ x, y int }
+	const size = 20
+	walls := map[cell]bool{}
+	for y := 0; y < size-1; y++ {
+		walls[cell{10, y}] = true
+	}
+
+	neighbors := func(c cell) iter.Seq2[cell, float64] {
+		return func(yield func(cell, float64) bool) {
+			for _, d := range []cell{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				next := cell{c.x + d.x, c.y + d.y}
+				if next.x < 0 || next.y < 0 || next.x >= size || next.y >= size || walls[next] {
+					continue
+				}
+				if !yield(next, 1) {
+					return
+				}
+			}
+		}
+	}
+	target := cell{size - 1, 0}
+	manhattan := func(c cell) float64 {
+		dx, dy := target.x-c.x, target.y-c.y
+		return float64(max(dx, -dx) + max(dy, -dy))
+	}
+
+	path, length, ok := AStar(cell{0, 0}, target, neighbors, manhattan)
+	require.True(t, ok)
+	expected, _ := Dijkstra(cell{0, 0}, neighbors).Distance(target)
+	assert.Equal(t, expected, length)
+	assert.Equal(t, int(length)+1, len(path))
+	assert.Equal(t, cell{0, 0}, path[0])
+	assert.Equal(t, target, path[len(path)-1])
+
+	walls[cell{10, size - 1}] = true
+	_, _, ok = AStar(cell{0, 0}, target, neighbors, manhattan)
+	assert.False(t, ok)
+}
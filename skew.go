@@ -1,5 +1,11 @@
 package heapcraft
 
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
 // skewNode represents a node in a simple skew heap without parent pointers.
 // Each node contains a value, priority, and links to its left and right children.
 type skewNode[V any, P any] struct {
@@ -36,21 +42,28 @@ func (n *skewHeapNode[V, P]) Priority() P { return n.priority }
 // It maintains a map of node IDs to nodes for O(1) element access and updates.
 // The heap can be either a min-heap or max-heap depending on the comparison function.
 type FullSkewHeap[V any, P any] struct {
-	root     *skewHeapNode[V, P]
-	cmp      func(a, b P) bool
-	size     int
-	elements map[string]*skewHeapNode[V, P]
-	pool     pool[*skewHeapNode[V, P]]
-	idGen    IDGenerator
+	root          *skewHeapNode[V, P]
+	cmp           func(a, b P) bool
+	size          int
+	elements      map[string]*skewHeapNode[V, P]
+	pool          pool[*skewHeapNode[V, P]]
+	alarms        depthAlarms
+	stats         heapStats
+	idGen         IDGenerator
+	onValueUpdate listeners[ValueUpdateEvent[V]]
+	events        listeners[HeapEvent[V, P]]
+	maxDepth      int
 }
 
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
-// original and cloned heaps.
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other.
 func (s *FullSkewHeap[V, P]) Clone() *FullSkewHeap[V, P] {
 	elements := make(map[string]*skewHeapNode[V, P], len(s.elements))
+	pool := s.pool.fresh()
 	for _, node := range s.elements {
-		cloned := s.pool.Get()
+		cloned := pool.Get()
 		cloned.id = node.id
 		cloned.value = node.value
 		cloned.priority = node.priority
@@ -76,23 +89,65 @@ func (s *FullSkewHeap[V, P]) Clone() *FullSkewHeap[V, P] {
 		}
 	}
 
-	return &FullSkewHeap[V, P]{
-		root:     elements[s.root.id],
-		cmp:      s.cmp,
-		size:     s.size,
-		elements: elements,
-		pool:     s.pool,
-		idGen:    s.idGen,
+	cloned := &FullSkewHeap[V, P]{
+		cmp:           s.cmp,
+		size:          s.size,
+		elements:      elements,
+		pool:          pool,
+		alarms:        s.alarms.clone(),
+		stats:         s.stats,
+		idGen:         s.idGen,
+		onValueUpdate: s.onValueUpdate.clone(),
+		events:        s.events.clone(),
+		maxDepth:      s.maxDepth,
 	}
+	if s.root != nil {
+		cloned.root = elements[s.root.id]
+	}
+	return cloned
+}
+
+// CloneFresh creates a deep copy of the heap like Clone, but gives every node
+// of the copy a new ID from the heap's IDGenerator, so that several copies of
+// one template heap never share IDs. Clone keeps the original IDs. New IDs are
+// assigned in the order the elements would be popped, which makes them
+// deterministic for a deterministic generator. Returns the copy and a mapping
+// from each original ID to its new one, or ErrIDGenerationFailed if a new ID
+// collides with an original one.
+func (s *FullSkewHeap[V, P]) CloneFresh() (*FullSkewHeap[V, P], map[string]string, error) {
+	cloned := s.Clone()
+	elements, ids, err := reassignIDs(cloned.idGen, cloned.ordered(), cloned.elements, func(n *skewHeapNode[V, P]) *string { return &n.id })
+	if err != nil {
+		return nil, nil, err
+	}
+	cloned.elements = elements
+	return cloned, ids, nil
 }
 
 // Clear removes all elements from the heap.
 // Resets the root to nil, size to zero, and initializes a new empty element map.
-// The next node ID is reset to 1.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
 func (s *FullSkewHeap[V, P]) Clear() {
+	cleared := s.Length()
+	releaseElements(s.pool, s.elements)
 	s.root = nil
 	s.size = 0
+	s.stats.record(OpClear, cleared, s.size)
+	s.alarms.check(s.size)
 	s.elements = make(map[string]*skewHeapNode[V, P])
+	emitClearEvent(s.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *FullSkewHeap[V, P]) PoolStats() PoolStats { return s.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes, the
+// nodes idle in its pool, the element map and the node IDs. Memory that values
+// and priorities point to is not counted. Summing the IDs makes it O(n).
+func (s *FullSkewHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[FullSkewHeap[V, P]]() + nodeMemory(s.size, s.pool) +
+		mapMemory[string, *skewHeapNode[V, P]](len(s.elements)) + idMemory(s.elements)
 }
 
 // Length returns the current number of elements in the heap.
@@ -101,6 +156,41 @@ func (s *FullSkewHeap[V, P]) Length() int { return s.size }
 // IsEmpty returns true if the heap contains no elements.
 func (s *FullSkewHeap[V, P]) IsEmpty() bool { return s.size == 0 }
 
+// Verify checks the internal consistency of the heap: no node may come before
+// its parent, parent links must agree with the child links, and the tree and
+// the ID map must both hold exactly Length nodes, each tracked under its own
+// ID. It is intended for tests and debugging, runs in O(n) and returns an
+// error wrapping ErrInvariantViolated for the first violation.
+func (s *FullSkewHeap[V, P]) Verify() error {
+	visited, err := verifyTree(treeRoots(s.root),
+		func(node *skewHeapNode[V, P], visit func(*skewHeapNode[V, P])) {
+			if node.left != nil {
+				visit(node.left)
+			}
+			if node.right != nil {
+				visit(node.right)
+			}
+		},
+		func(node, parent *skewHeapNode[V, P]) error {
+			if node.parent != parent {
+				return invariantError("node %q does not link to its parent", node.id)
+			}
+			if parent != nil {
+				if err := verifyOrder(s.cmp, node.priority, parent.priority); err != nil {
+					return err
+				}
+			}
+			return verifyTracked(s.elements, node.id, node)
+		})
+	if err != nil {
+		return err
+	}
+	if err := verifySize(visited, s.size); err != nil {
+		return err
+	}
+	return verifyElements(len(s.elements), s.size)
+}
+
 // peek is an internal method that returns the root node's value and priority without removing it.
 // Returns nil and an error if the heap is empty.
 func (s *FullSkewHeap[V, P]) peek() (V, P, error) {
@@ -111,6 +201,29 @@ func (s *FullSkewHeap[V, P]) peek() (V, P, error) {
 	return s.root.value, s.root.priority, nil
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (s *FullSkewHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return s.alarms.register(n, s.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *FullSkewHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return s.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *FullSkewHeap[V, P]) Stats() HeapStats { return s.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (s *FullSkewHeap[V, P]) Instrument(hook Instrumentation) { s.stats.hook = hook }
+
 // Peek returns the minimum element without removing it.
 // Returns nil and an error if the heap is empty.
 func (s *FullSkewHeap[V, P]) Peek() (V, P, error) { return s.peek() }
@@ -134,7 +247,7 @@ func (s *FullSkewHeap[V, P]) get(id string) (V, P, error) {
 		return node.value, node.priority, nil
 	}
 	v, p := zeroValuePair[V, P]()
-	return v, p, ErrNodeNotFound
+	return v, p, &NodeNotFoundError{ID: id}
 }
 
 // Get returns the element with the given ID.
@@ -160,6 +273,10 @@ func (s *FullSkewHeap[V, P]) pop() (V, P, error) {
 		v, p := zeroValuePair[V, P]()
 		return v, p, ErrHeapEmpty
 	}
+	if s.mergeExceedsDepth(s.root.left, s.root.right, s.size) {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrMaxDepthExceeded
+	}
 
 	removed := s.root
 	s.root = s.merge(s.root.left, s.root.right)
@@ -167,10 +284,13 @@ func (s *FullSkewHeap[V, P]) pop() (V, P, error) {
 		s.root.parent = nil
 	}
 	s.size--
+	s.stats.record(OpPop, 1, s.size)
+	s.alarms.check(s.size)
 	delete(s.elements, removed.id)
 	removed.left, removed.right, removed.parent = nil, nil, nil
-	v, p := removed.value, removed.priority
+	id, v, p := removed.id, removed.value, removed.priority
 	s.pool.Put(removed)
+	emitHeapEvent(s.events, EventPop, id, v, p)
 	return v, p, nil
 }
 
@@ -178,6 +298,14 @@ func (s *FullSkewHeap[V, P]) pop() (V, P, error) {
 // Returns nil and an error if the heap is empty.
 func (s *FullSkewHeap[V, P]) Pop() (V, P, error) { return s.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (s *FullSkewHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(s.Peek, s.Pop, pred)
+}
+
 // PopValue removes and returns the value of the minimum element.
 // Returns zero value and an error if the heap is empty.
 func (s *FullSkewHeap[V, P]) PopValue() (V, error) {
@@ -190,6 +318,173 @@ func (s *FullSkewHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(s.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *FullSkewHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(s.Length(), s.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *FullSkewHeap[V, P]) DrainValues() []V {
+	return drainValues(s.Length(), s.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *FullSkewHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(s.Length(), s.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (s *FullSkewHeap[V, P]) forEach(fn func(v V, p P)) {
+	for _, node := range s.elements {
+		fn(node.value, node.priority)
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *FullSkewHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(s.Length(), s.forEach, s.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (s *FullSkewHeap[V, P]) ordered() iter.Seq[*skewHeapNode[V, P]] {
+	priority := func(n *skewHeapNode[V, P]) P { return n.priority }
+	return orderedNodes(s.cmp, treeRoots(s.root), priority, func(n *skewHeapNode[V, P], visit func(*skewHeapNode[V, P])) {
+		if n.left != nil {
+			visit(n.left)
+		}
+		if n.right != nil {
+			visit(n.right)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (s *FullSkewHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(s.ordered(), func(n *skewHeapNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "id", "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (s *FullSkewHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, s.ordered(), func(n *skewHeapNode[V, P]) trackedNodeJSON[V, P] {
+		return trackedNodeJSON[V, P]{ID: n.id, Value: n.value, Priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its ID and priority. Left and right links are
+// drawn solid, and parent links dashed. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (s *FullSkewHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, treeRoots(s.root),
+		func(node *skewHeapNode[V, P]) string {
+			return fmt.Sprintf("%s\n%v", node.id, node.priority)
+		},
+		func(node *skewHeapNode[V, P], edge func(dotEdge[*skewHeapNode[V, P]])) {
+			if node.left != nil {
+				edge(dotEdge[*skewHeapNode[V, P]]{label: "left", to: node.left})
+			}
+			if node.right != nil {
+				edge(dotEdge[*skewHeapNode[V, P]]{label: "right", to: node.right})
+			}
+			if node.parent != nil {
+				edge(dotEdge[*skewHeapNode[V, P]]{label: "parent", to: node.parent, back: true})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first together with their IDs. The comparison function and
+// ID generator are not encoded.
+func (s *FullSkewHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalTrackedHeap(s.elements, s.cmp, func(node *skewHeapNode[V, P]) (V, P) {
+		return node.value, node.priority
+	})
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON, keeping their IDs. The heap must have been created with its
+// constructor so that it has a comparison function to restore them with. An
+// IntegerIDGenerator is advanced past the restored IDs. Returns
+// ErrDuplicateID, leaving the heap unchanged, if an ID is encoded twice.
+func (s *FullSkewHeap[V, P]) UnmarshalJSON(data []byte) error {
+	nodes, err := unmarshalTrackedHeap[V, P](data, s.cmp)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	for _, node := range nodes {
+		if err := s.pushWithID(node.ID, node.Value, node.Priority); err != nil {
+			return err
+		}
+		advanceIDGenerator(s.idGen, node.ID)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap, together with their IDs, in a
+// compact binary format using encoding/gob, for persisting the heap across
+// process restarts. Elements are written in no particular order. The
+// comparison function and ID generator are not encoded.
+func (s *FullSkewHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotTracked(s.elements, func(node *skewHeapNode[V, P]) (V, P) {
+		return node.value, node.priority
+	}))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot, keeping their IDs. The heap must have been created with
+// its constructor so that it has a comparison function to restore them with.
+// An IntegerIDGenerator is advanced past the restored IDs.
+func (s *FullSkewHeap[V, P]) Restore(data []byte) error {
+	if s.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, true)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	for i, id := range snapshot.IDs {
+		if err := s.pushWithID(id, snapshot.Values[i], snapshot.Priorities[i]); err != nil {
+			return err
+		}
+		advanceIDGenerator(s.idGen, id)
+	}
+	return nil
+}
+
+// mergeExceedsDepth reports whether merging a and b would recurse deeper than
+// the heap's MaxDepth. merge recurses once per node on the right
+// spines of its inputs, so the spines are only walked when bound, the number
+// of nodes involved, is large enough to reach the limit.
+func (s *FullSkewHeap[V, P]) mergeExceedsDepth(a, b *skewHeapNode[V, P], bound int) bool {
+	if !depthExceeds(s.maxDepth, bound) {
+		return false
+	}
+	depth := 1
+	for node := a; node != nil; node = node.right {
+		depth++
+	}
+	for node := b; node != nil; node = node.right {
+		depth++
+	}
+	return depthExceeds(s.maxDepth, depth)
+}
+
 // merge combines two skew heap subtrees into a single heap.
 // The root with the higher priority (according to cmp) becomes the new root.
 // Children are swapped to maintain the skew heap property.
@@ -205,6 +500,7 @@ func (s *FullSkewHeap[V, P]) merge(new *skewHeapNode[V, P], root *skewHeapNode[V
 
 	first := new
 	second := root
+	s.stats.rebalance()
 
 	if s.cmp(first.priority, second.priority) {
 		tempNode := first.right
@@ -239,112 +535,343 @@ func (s *FullSkewHeap[V, P]) merge(new *skewHeapNode[V, P], root *skewHeapNode[V
 
 // Push adds a new element to the heap.
 // The element is assigned a unique ID and stored in the elements map.
-// Returns the ID of the inserted node.
+// Returns the ID of the inserted node, or ErrMaxDepthExceeded if the merge
+// would recurse deeper than the heap's MaxDepth.
 func (s *FullSkewHeap[V, P]) Push(value V, priority P) (string, error) {
-	newNode := s.pool.Get()
-	newNode.id = s.idGen.Next()
-	if _, exists := s.elements[newNode.id]; exists {
+	id := s.idGen.Next()
+	if err := s.pushWithID(id, value, priority); err != nil {
+		if err == ErrMaxDepthExceeded {
+			return "", err
+		}
 		return "", ErrIDGenerationFailed
 	}
+	return id, nil
+}
+
+// pushWithID inserts a new node with the given ID into the heap. Returns
+// ErrDuplicateID if a node with the ID already exists.
+func (s *FullSkewHeap[V, P]) pushWithID(id string, value V, priority P) error {
+	if _, exists := s.elements[id]; exists {
+		return ErrDuplicateID
+	}
+	if s.mergeExceedsDepth(nil, s.root, s.size+1) {
+		return ErrMaxDepthExceeded
+	}
 
+	newNode := s.pool.Get()
+	newNode.id = id
 	newNode.value = value
 	newNode.priority = priority
 	s.elements[newNode.id] = newNode
 	s.root = s.merge(newNode, s.root)
 	s.size++
-	return newNode.id, nil
+	s.stats.record(OpPush, 1, s.size)
+	s.alarms.check(s.size)
+	emitHeapEvent(s.events, EventPush, id, value, priority)
+	return nil
+}
+
+// PushAll inserts all of the given elements into the heap and returns their
+// IDs in the same order. The elements are built into a skew tree of their own
+// by merging singleton trees pairwise, which is then merged into the heap
+// once. Returns ErrIDGenerationFailed if a generated ID is not unique, or
+// ErrMaxDepthExceeded if the final merge would recurse deeper than the
+// heap's MaxDepth, leaving the heap unchanged in both cases.
+func (s *FullSkewHeap[V, P]) PushAll(data []HeapNode[V, P]) ([]string, error) {
+	ids, err := generateIDs(s.idGen, len(data), s.elements)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return ids, nil
+	}
+
+	nodes := make([]*skewHeapNode[V, P], len(data))
+	initQueue := leftistQueue[*skewHeapNode[V, P]]{data: make([]*skewHeapNode[V, P], 0, len(data))}
+	for i := range data {
+		node := s.pool.Get()
+		node.id = ids[i]
+		node.value = data[i].value
+		node.priority = data[i].priority
+		nodes[i] = node
+		initQueue.push(node)
+	}
+
+	for initQueue.remainingElements() > 1 {
+		merged := s.merge(initQueue.pop(), initQueue.pop())
+		initQueue.push(merged)
+	}
+	subtree := initQueue.pop()
+	if s.mergeExceedsDepth(subtree, s.root, s.size+len(data)) {
+		for _, node := range nodes {
+			node.left, node.right, node.parent = nil, nil, nil
+			s.pool.Put(node)
+		}
+		return nil, ErrMaxDepthExceeded
+	}
+
+	for _, node := range nodes {
+		s.elements[node.id] = node
+	}
+	s.root = s.merge(subtree, s.root)
+	s.root.parent = nil
+	s.size += len(data)
+	s.stats.record(OpPush, len(data), s.size)
+	s.alarms.check(s.size)
+	for i := range data {
+		emitHeapEvent(s.events, EventPush, ids[i], data[i].value, data[i].priority)
+	}
+	return ids, nil
+}
+
+// Meld merges another heap into this one in O(log n) amortized time by linking the two
+// trees, and absorbs the other heap's nodes so they remain addressable by
+// their IDs. The other heap is consumed by the operation and left empty.
+// Returns an error, leaving both heaps unchanged, if an ID exists in both
+// heaps. Both heaps are expected to share the same comparison function.
+func (s *FullSkewHeap[V, P]) Meld(other *FullSkewHeap[V, P]) error {
+	if other == nil || other == s {
+		return nil
+	}
+	for id := range other.elements {
+		if _, exists := s.elements[id]; exists {
+			return ErrDuplicateID
+		}
+	}
+	if s.mergeExceedsDepth(other.root, s.root, s.size+other.size) {
+		return ErrMaxDepthExceeded
+	}
+
+	for id, node := range other.elements {
+		s.elements[id] = node
+	}
+	s.root = s.merge(other.root, s.root)
+	if s.root != nil {
+		s.root.parent = nil
+	}
+	s.size += other.size
+	s.stats.record(OpMeld, other.size, s.size)
+	s.alarms.check(s.size)
+	if len(s.events) > 0 {
+		for id, node := range other.elements {
+			emitHeapEvent(s.events, EventPush, id, node.value, node.priority)
+		}
+	}
+	other.root, other.elements = nil, nil
+	other.Clear()
+	return nil
 }
 
 // UpdateValue updates the value of the element with the given ID.
 // Returns an error if the ID does not exist.
 // The heap structure remains unchanged as this operation only modifies the value.
 func (s *FullSkewHeap[V, P]) UpdateValue(id string, value V) error {
-	if _, exists := s.elements[id]; !exists {
-		return ErrNodeNotFound
+	node, exists := s.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
 	}
 
-	s.elements[id].value = value
+	old := node.value
+	node.value = value
+	s.onValueUpdate.emit(ValueUpdateEvent[V]{ID: id, Old: old, New: value})
+	emitHeapEvent(s.events, EventUpdate, id, value, node.priority)
 	return nil
 }
 
+// OnValueUpdate registers a listener that is invoked after every successful
+// UpdateValue with the node ID and its old and new values. Returns an ID that
+// can be passed to RemoveListener.
+func (s *FullSkewHeap[V, P]) OnValueUpdate(fn func(event ValueUpdateEvent[V])) string {
+	return s.onValueUpdate.register(fn)
+}
+
+// OnEvent registers fn to be called for every element pushed, popped, removed
+// or updated, including each element melded in from another heap, and
+// whenever the heap is cleared. Events carry the element's ID. Returns an ID
+// that can be passed to RemoveListener.
+func (s *FullSkewHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string {
+	return s.events.add(fn)
+}
+
+// RemoveListener removes the event listener with the specified ID, whether it
+// was registered with OnValueUpdate or OnEvent. Returns an error if no
+// listener exists with the given ID.
+func (s *FullSkewHeap[V, P]) RemoveListener(id string) error {
+	if err := s.events.deregister(id); err == nil {
+		return nil
+	}
+	return s.onValueUpdate.deregister(id)
+}
+
 // UpdatePriority updates the priority of the element with the given ID.
 // The heap is restructured to maintain the heap property.
 // Returns an error if the ID does not exist.
 func (s *FullSkewHeap[V, P]) UpdatePriority(id string, priority P) error {
-	if _, exists := s.elements[id]; !exists {
-		return ErrNodeNotFound
+	updated, exists := s.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	if s.unlinkExceedsDepth(updated) {
+		return ErrMaxDepthExceeded
 	}
 
-	updated := s.elements[id]
+	s.unlink(updated)
 	updated.priority = priority
+	s.root = s.merge(updated, s.root)
+	emitHeapEvent(s.events, EventUpdate, id, updated.value, priority)
+	return nil
+}
 
-	if updated.id == s.root.id {
-		s.root = s.merge(updated.left, updated.right)
-		s.root.parent = nil
-	} else {
-		var new *skewHeapNode[V, P]
-		parent := updated.parent
-		if updated.left == nil && updated.right == nil {
-			new = nil
-		} else {
-			new = s.merge(updated.left, updated.right)
-			new.parent = parent
-		}
+// UpdatePriorityFunc replaces the priority of the node with the given ID with
+// fn applied to its current priority, for adjustments such as a boost by
+// elapsed time. Returns an error if the ID does not exist in the heap, in
+// which case fn is not called.
+func (s *FullSkewHeap[V, P]) UpdatePriorityFunc(id string, fn func(old P) P) error {
+	return updatePriorityFunc(s.GetPriority, s.UpdatePriority, id, fn)
+}
 
-		if parent.left == updated {
-			parent.left = new
-		} else {
-			parent.right = new
-		}
+// FixID restores the heap order around the node with the given ID after its
+// priority has been changed in place, for instance through a pointer held by
+// the caller. It is equivalent to calling UpdatePriority with the node's
+// current priority. Returns an error if the ID does not exist in the heap.
+func (s *FullSkewHeap[V, P]) FixID(id string) error {
+	node, exists := s.elements[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
 	}
+	return s.UpdatePriority(id, node.priority)
+}
 
-	updated.parent, updated.left, updated.right = nil, nil, nil
-	s.root = s.merge(updated, s.root)
-	return nil
+// Remove deletes the node with the given ID from the heap and returns its
+// value and priority. The node's children are merged back into the heap in
+// its place. Returns an error if the ID doesn't exist in the heap.
+func (s *FullSkewHeap[V, P]) Remove(id string) (V, P, error) {
+	removed, exists := s.elements[id]
+	if !exists {
+		v, p := zeroValuePair[V, P]()
+		return v, p, &NodeNotFoundError{ID: id}
+	}
+	if s.unlinkExceedsDepth(removed) {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrMaxDepthExceeded
+	}
+
+	s.unlink(removed)
+	delete(s.elements, id)
+	s.size--
+	s.stats.record(OpRemove, 1, s.size)
+	s.alarms.check(s.size)
+	v, p := removed.value, removed.priority
+	s.pool.Put(removed)
+	emitHeapEvent(s.events, EventRemove, id, v, p)
+	return v, p, nil
+}
+
+// unlinkExceedsDepth reports whether unlinking node, and merging it back in
+// at the root for UpdatePriority, could recurse deeper than the heap's
+// MaxDepth. Merging the node back in walks at most the root's right
+// spine plus the spine produced by merging the node's children.
+func (s *FullSkewHeap[V, P]) unlinkExceedsDepth(node *skewHeapNode[V, P]) bool {
+	if !depthExceeds(s.maxDepth, s.size) {
+		return false
+	}
+	depth := 1
+	for child := node.left; child != nil; child = child.right {
+		depth++
+	}
+	for child := node.right; child != nil; child = child.right {
+		depth++
+	}
+	for spine := s.root; spine != nil; spine = spine.right {
+		depth++
+	}
+	return depthExceeds(s.maxDepth, depth)
+}
+
+// unlink removes a node from the tree, replacing it with the merge of its
+// children, and clears the node's links. The node stays in the elements map.
+func (s *FullSkewHeap[V, P]) unlink(node *skewHeapNode[V, P]) {
+	parent := node.parent
+	merged := s.merge(node.left, node.right)
+	if merged != nil {
+		merged.parent = parent
+	}
+
+	switch {
+	case parent == nil:
+		s.root = merged
+	case parent.left == node:
+		parent.left = merged
+	default:
+		parent.right = merged
+	}
+	node.parent, node.left, node.right = nil, nil, nil
 }
 
 // SkewHeap implements a basic skew heap without parent pointers.
 // It provides the same core functionality as FullSkewHeap but without element tracking.
 // The heap can be either a min-heap or max-heap depending on the comparison function.
 type SkewHeap[V any, P any] struct {
-	root *skewNode[V, P]
-	cmp  func(a, b P) bool
-	size int
-	pool pool[*skewNode[V, P]]
+	root     *skewNode[V, P]
+	cmp      func(a, b P) bool
+	size     int
+	pool     pool[*skewNode[V, P]]
+	alarms   depthAlarms
+	stats    heapStats
+	events   listeners[HeapEvent[V, P]]
+	maxDepth int
 }
 
 // Clone creates a deep copy of the heap structure and nodes. If values or
 // priorities are reference types, those reference values are shared between the
-// original and cloned heaps.
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other. The tree is copied
+// iteratively, so cloning a degenerate heap cannot overflow the stack.
 func (s *SkewHeap[V, P]) Clone() *SkewHeap[V, P] {
-	return &SkewHeap[V, P]{
-		root: s.cloneNode(s.root),
-		cmp:  s.cmp,
-		size: s.size,
-		pool: s.pool,
-	}
-}
-
-// cloneNode creates a deep copy of a skew node.
-// It recursively clones the left and right children.
-func (s *SkewHeap[V, P]) cloneNode(node *skewNode[V, P]) *skewNode[V, P] {
-	if node == nil {
-		return nil
+	cloned := &SkewHeap[V, P]{
+		cmp:      s.cmp,
+		size:     s.size,
+		pool:     s.pool.fresh(),
+		alarms:   s.alarms.clone(),
+		stats:    s.stats,
+		events:   s.events.clone(),
+		maxDepth: s.maxDepth,
 	}
-
-	cloned := s.pool.Get()
-	cloned.value = node.value
-	cloned.priority = node.priority
-	cloned.left = s.cloneNode(node.left)
-	cloned.right = s.cloneNode(node.right)
+	cloned.root = cloneTree(s.root, cloned.pool.Get, func(n *skewNode[V, P]) (**skewNode[V, P], **skewNode[V, P]) {
+		return &n.left, &n.right
+	})
 	return cloned
 }
 
 // Clear removes all elements from the heap.
 // Resets the root to nil and size to zero.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
 func (s *SkewHeap[V, P]) Clear() {
+	cleared := s.Length()
+	releaseTree(s.pool, treeRoots(s.root), func(n *skewNode[V, P], visit func(*skewNode[V, P])) {
+		if n.left != nil {
+			visit(n.left)
+		}
+		if n.right != nil {
+			visit(n.right)
+		}
+	})
 	s.root = nil
 	s.size = 0
+	s.stats.record(OpClear, cleared, s.size)
+	s.alarms.check(s.size)
+	emitClearEvent(s.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SkewHeap[V, P]) PoolStats() PoolStats { return s.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes and the
+// nodes idle in its pool. Memory that values and priorities point to, such as
+// the contents of strings, is not counted.
+func (s *SkewHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[SkewHeap[V, P]]() + nodeMemory(s.size, s.pool)
 }
 
 // Length returns the current number of elements in the heap.
@@ -353,6 +880,32 @@ func (s *SkewHeap[V, P]) Length() int { return s.size }
 // IsEmpty returns true if the heap contains no elements.
 func (s *SkewHeap[V, P]) IsEmpty() bool { return s.size == 0 }
 
+// Verify checks the internal consistency of the heap: no node may come before
+// its parent, and the tree must hold exactly Length nodes. It is intended for
+// tests and debugging, runs in O(n) and returns an error wrapping
+// ErrInvariantViolated for the first violation.
+func (s *SkewHeap[V, P]) Verify() error {
+	visited, err := verifyTree(treeRoots(s.root),
+		func(node *skewNode[V, P], visit func(*skewNode[V, P])) {
+			if node.left != nil {
+				visit(node.left)
+			}
+			if node.right != nil {
+				visit(node.right)
+			}
+		},
+		func(node, parent *skewNode[V, P]) error {
+			if parent == nil {
+				return nil
+			}
+			return verifyOrder(s.cmp, node.priority, parent.priority)
+		})
+	if err != nil {
+		return err
+	}
+	return verifySize(visited, s.size)
+}
+
 // peek is an internal method that returns the root node's value and priority without removing it.
 // Returns nil and an error if the heap is empty.
 func (s *SkewHeap[V, P]) peek() (V, P, error) {
@@ -363,6 +916,38 @@ func (s *SkewHeap[V, P]) peek() (V, P, error) {
 	return s.root.value, s.root.priority, nil
 }
 
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (s *SkewHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return s.alarms.register(n, s.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SkewHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return s.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SkewHeap[V, P]) Stats() HeapStats { return s.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (s *SkewHeap[V, P]) Instrument(hook Instrumentation) { s.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element melded in from another heap, and whenever the heap is
+// cleared. Returns an ID that can be passed to RemoveListener.
+func (s *SkewHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return s.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SkewHeap[V, P]) RemoveListener(id string) error { return s.events.deregister(id) }
+
 // Peek returns the minimum element without removing it.
 // Returns nil and an error if the heap is empty.
 func (s *SkewHeap[V, P]) Peek() (V, P, error) { return s.peek() }
@@ -386,13 +971,20 @@ func (s *SkewHeap[V, P]) pop() (V, P, error) {
 		v, p := zeroValuePair[V, P]()
 		return v, p, ErrHeapEmpty
 	}
+	if s.mergeExceedsDepth(s.root.left, s.root.right, s.size) {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrMaxDepthExceeded
+	}
 
 	rootNode := s.root
 	s.root = s.merge(s.root.left, s.root.right)
 	rootNode.left, rootNode.right = nil, nil
 	s.size--
+	s.stats.record(OpPop, 1, s.size)
+	s.alarms.check(s.size)
 	v, p := rootNode.value, rootNode.priority
 	s.pool.Put(rootNode)
+	emitHeapEvent(s.events, EventPop, "", v, p)
 	return v, p, nil
 }
 
@@ -400,6 +992,14 @@ func (s *SkewHeap[V, P]) pop() (V, P, error) {
 // Returns nil and an error if the heap is empty.
 func (s *SkewHeap[V, P]) Pop() (V, P, error) { return s.pop() }
 
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (s *SkewHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(s.Peek, s.Pop, pred)
+}
+
 // PopValue removes and returns the value of the minimum element.
 // Returns zero value and an error if the heap is empty.
 func (s *SkewHeap[V, P]) PopValue() (V, error) {
@@ -412,6 +1012,167 @@ func (s *SkewHeap[V, P]) PopPriority() (P, error) {
 	return priorityFromNode(s.pop())
 }
 
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SkewHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(s.Length(), s.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SkewHeap[V, P]) DrainValues() []V {
+	return drainValues(s.Length(), s.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SkewHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(s.Length(), s.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (s *SkewHeap[V, P]) forEach(fn func(v V, p P)) {
+	stack := make([]*skewNode[V, P], 0)
+	if s.root != nil {
+		stack = append(stack, s.root)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(node.value, node.priority)
+		if node.left != nil {
+			stack = append(stack, node.left)
+		}
+		if node.right != nil {
+			stack = append(stack, node.right)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SkewHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(s.Length(), s.forEach, s.cmp, opts)
+}
+
+// ordered returns an iterator over the heap's nodes in the order they would be
+// popped.
+func (s *SkewHeap[V, P]) ordered() iter.Seq[*skewNode[V, P]] {
+	priority := func(n *skewNode[V, P]) P { return n.priority }
+	return orderedNodes(s.cmp, treeRoots(s.root), priority, func(n *skewNode[V, P], visit func(*skewNode[V, P])) {
+		if n.left != nil {
+			visit(n.left)
+		}
+		if n.right != nil {
+			visit(n.right)
+		}
+	})
+}
+
+// Ordered returns an iterator over the elements of the heap in the order they
+// would be popped, without modifying the heap. Only the yielded elements and
+// their children are visited, so stopping early over a large heap is cheap.
+// The heap must not be modified while the iterator is in use.
+func (s *SkewHeap[V, P]) Ordered() iter.Seq2[V, P] {
+	return orderedPairs(s.ordered(), func(n *skewNode[V, P]) (V, P) { return n.value, n.priority })
+}
+
+// StreamJSON writes the elements of the heap to w in the order they would be
+// popped, as a JSON array of objects with "value" and "priority" fields. Elements
+// are encoded one at a time as Ordered reaches them, so the heap is neither
+// copied nor modified.
+func (s *SkewHeap[V, P]) StreamJSON(w io.Writer) error {
+	return streamNodes(w, s.ordered(), func(n *skewNode[V, P]) HeapNode[V, P] {
+		return HeapNode[V, P]{value: n.value, priority: n.priority}
+	})
+}
+
+// WriteDOT writes the tree structure of the heap to w in the Graphviz DOT
+// format, labelling each node with its priority and drawing its left and right links. It is intended for debugging; render the output with
+// "dot -Tsvg".
+func (s *SkewHeap[V, P]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, treeRoots(s.root),
+		func(node *skewNode[V, P]) string {
+			return fmt.Sprint(node.priority)
+		},
+		func(node *skewNode[V, P], edge func(dotEdge[*skewNode[V, P]])) {
+			if node.left != nil {
+				edge(dotEdge[*skewNode[V, P]]{label: "left", to: node.left})
+			}
+			if node.right != nil {
+				edge(dotEdge[*skewNode[V, P]]{label: "right", to: node.right})
+			}
+		})
+}
+
+// MarshalJSON encodes the heap as a JSON object whose "nodes" field lists its
+// elements best-first. The comparison function is not encoded.
+func (s *SkewHeap[V, P]) MarshalJSON() ([]byte, error) {
+	return marshalHeap(s.Export(ExportOptions[V, P]{}))
+}
+
+// UnmarshalJSON replaces the contents of the heap with elements encoded by
+// MarshalJSON. The heap must have been created with its constructor so that
+// it has a comparison function to restore them with.
+func (s *SkewHeap[V, P]) UnmarshalJSON(data []byte) error {
+	decoded, err := unmarshalHeap[V, P](data, s.cmp)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	for _, node := range decoded.Nodes {
+		s.Push(node.value, node.priority)
+	}
+	return nil
+}
+
+// Snapshot encodes the elements of the heap in a compact binary format using
+// encoding/gob, for persisting the heap across process restarts. Elements are
+// written in internal order without sorting. The comparison function is not
+// encoded.
+func (s *SkewHeap[V, P]) Snapshot() ([]byte, error) {
+	return encodeSnapshot(snapshotNodes(s.Length(), s.forEach))
+}
+
+// Restore replaces the contents of the heap with the elements of a snapshot
+// taken by Snapshot. The heap must have been created with its constructor so
+// that it has a comparison function to restore them with.
+func (s *SkewHeap[V, P]) Restore(data []byte) error {
+	if s.cmp == nil {
+		return ErrUninitializedHeap
+	}
+	snapshot, err := decodeSnapshot[V, P](data, false)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	for i, value := range snapshot.Values {
+		s.Push(value, snapshot.Priorities[i])
+	}
+	return nil
+}
+
+// mergeExceedsDepth reports whether merging a and b would recurse deeper than
+// the heap's MaxDepth. merge recurses once per node on the right spines of
+// its inputs, so the spines are only walked when bound, the number of nodes
+// involved, is large enough to reach the limit.
+func (s *SkewHeap[V, P]) mergeExceedsDepth(a, b *skewNode[V, P], bound int) bool {
+	if !depthExceeds(s.maxDepth, bound) {
+		return false
+	}
+	depth := 1
+	for node := a; node != nil; node = node.right {
+		depth++
+	}
+	for node := b; node != nil; node = node.right {
+		depth++
+	}
+	return depthExceeds(s.maxDepth, depth)
+}
+
 // merge combines two skew heap subtrees into a single heap.
 // The root with the higher priority (according to cmp) becomes the new root.
 // Children are swapped to maintain the skew heap property.
@@ -427,6 +1188,7 @@ func (s *SkewHeap[V, P]) merge(new *skewNode[V, P], root *skewNode[V, P]) *skewN
 
 	first := new
 	second := root
+	s.stats.rebalance()
 
 	if s.cmp(first.priority, second.priority) {
 		tempNode := first.right
@@ -443,12 +1205,261 @@ func (s *SkewHeap[V, P]) merge(new *skewNode[V, P], root *skewNode[V, P]) *skewN
 	}
 }
 
-// Push adds a new element to the heap.
-// The element is merged with the existing root to maintain the heap property.
-func (s *SkewHeap[V, P]) Push(value V, priority P) {
+// mergeIteratively builds the same tree as merge with a loop instead of
+// recursion. It is used by the operations that have no error result when the
+// recursive merge would exceed the heap's MaxDepth.
+func (s *SkewHeap[V, P]) mergeIteratively(new *skewNode[V, P], root *skewNode[V, P]) *skewNode[V, P] {
+	var merged *skewNode[V, P]
+	link := &merged
+	for new != nil && root != nil {
+		s.stats.rebalance()
+		winner, loser := root, new
+		if s.cmp(new.priority, root.priority) {
+			winner, loser = new, root
+		}
+		next := winner.right
+		winner.right = winner.left
+		*link = winner
+		link = &winner.left
+		new, root = loser, next
+	}
+	if new != nil {
+		*link = new
+	} else {
+		*link = root
+	}
+	return merged
+}
+
+// merger returns mergeIteratively if deep is true and merge otherwise.
+func (s *SkewHeap[V, P]) merger(deep bool) func(new, root *skewNode[V, P]) *skewNode[V, P] {
+	if deep {
+		return s.mergeIteratively
+	}
+	return s.merge
+}
+
+// push is an internal method that inserts an element. If the merge would
+// recurse deeper than the heap's MaxDepth, it returns ErrMaxDepthExceeded
+// when strict is true and merges without recursion otherwise.
+func (s *SkewHeap[V, P]) push(value V, priority P, strict bool) error {
+	deep := s.mergeExceedsDepth(nil, s.root, s.size+1)
+	if deep && strict {
+		return ErrMaxDepthExceeded
+	}
 	newNode := s.pool.Get()
 	newNode.value = value
 	newNode.priority = priority
-	s.root = s.merge(newNode, s.root)
+	s.root = s.merger(deep)(newNode, s.root)
 	s.size++
+	s.stats.record(OpPush, 1, s.size)
+	s.alarms.check(s.size)
+	emitHeapEvent(s.events, EventPush, "", value, priority)
+	return nil
+}
+
+// Push adds a new element to the heap.
+// The element is merged with the existing root to maintain the heap property.
+// Push cannot fail: if the merge would recurse deeper than the heap's
+// MaxDepth, it is done without recursion instead. Use TryPush to be told.
+func (s *SkewHeap[V, P]) Push(value V, priority P) { s.push(value, priority, false) }
+
+// TryPush adds a new element to the heap like Push, but returns
+// ErrMaxDepthExceeded, leaving the heap unchanged, if the merge would recurse
+// deeper than the heap's MaxDepth.
+func (s *SkewHeap[V, P]) TryPush(value V, priority P) error {
+	return s.push(value, priority, true)
+}
+
+// pushAll is an internal method that inserts all of the given elements. The
+// elements are built into a skew tree of their own by merging singleton trees
+// pairwise, which is then merged into the heap once. strict selects what
+// happens if the final merge would recurse too deep, as for push.
+func (s *SkewHeap[V, P]) pushAll(data []HeapNode[V, P], strict bool) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	queueData := make([]*skewNode[V, P], 0, len(data))
+	initQueue := leftistQueue[*skewNode[V, P]]{data: queueData, head: 0, size: 0}
+	for i := range data {
+		node := s.pool.Get()
+		node.value = data[i].value
+		node.priority = data[i].priority
+		initQueue.push(node)
+	}
+
+	for initQueue.remainingElements() > 1 {
+		merged := s.merge(initQueue.pop(), initQueue.pop())
+		initQueue.push(merged)
+	}
+	subtree := initQueue.pop()
+	deep := s.mergeExceedsDepth(subtree, s.root, s.size+len(data))
+	if deep && strict {
+		return ErrMaxDepthExceeded
+	}
+	s.root = s.merger(deep)(subtree, s.root)
+	s.size += len(data)
+	s.stats.record(OpPush, len(data), s.size)
+	s.alarms.check(s.size)
+	for i := range data {
+		emitHeapEvent(s.events, EventPush, "", data[i].value, data[i].priority)
+	}
+	return nil
+}
+
+// PushAll inserts all of the given elements into the heap. The elements are
+// built into a skew tree of their own by merging singleton trees pairwise,
+// which is then merged into the heap once. Like Push, it cannot fail.
+func (s *SkewHeap[V, P]) PushAll(data []HeapNode[V, P]) { s.pushAll(data, false) }
+
+// TryPushAll inserts all of the given elements like PushAll, but returns
+// ErrMaxDepthExceeded, leaving the heap unchanged, if the final merge would
+// recurse deeper than the heap's MaxDepth.
+func (s *SkewHeap[V, P]) TryPushAll(data []HeapNode[V, P]) error {
+	return s.pushAll(data, true)
+}
+
+// meld is an internal method that merges other into the heap. strict selects
+// what happens if the merge would recurse too deep, as for push.
+func (s *SkewHeap[V, P]) meld(other *SkewHeap[V, P], strict bool) error {
+	if other == nil || other == s {
+		return nil
+	}
+	deep := s.mergeExceedsDepth(other.root, s.root, s.size+other.size)
+	if deep && strict {
+		return ErrMaxDepthExceeded
+	}
+	if len(s.events) > 0 {
+		other.forEach(func(v V, p P) { emitHeapEvent(s.events, EventPush, "", v, p) })
+	}
+	s.root = s.merger(deep)(other.root, s.root)
+	s.size += other.size
+	s.stats.record(OpMeld, other.size, s.size)
+	s.alarms.check(s.size)
+	other.root = nil
+	other.Clear()
+	return nil
+}
+
+// Meld merges another heap into this one in O(log n) amortized time by linking the two
+// trees. The other heap is consumed by the operation and left empty. Both
+// heaps are expected to share the same comparison function. Like Push, it
+// cannot fail.
+func (s *SkewHeap[V, P]) Meld(other *SkewHeap[V, P]) { s.meld(other, false) }
+
+// TryMeld merges another heap into this one like Meld, but returns
+// ErrMaxDepthExceeded, leaving both heaps unchanged, if the merge would
+// recurse deeper than the heap's MaxDepth.
+func (s *SkewHeap[V, P]) TryMeld(other *SkewHeap[V, P]) error { return s.meld(other, true) }
+
+// replaceExceedsDepth reports whether replaceRoot would recurse deeper than
+// the heap's MaxDepth. Merging the root's children makes the winning child
+// the new root, with its old left subtree as its right child, so the right
+// spine the new element is merged along is known beforehand.
+func (s *SkewHeap[V, P]) replaceExceedsDepth() bool {
+	if !depthExceeds(s.maxDepth, s.size+1) {
+		return false
+	}
+	left, right := s.root.left, s.root.right
+	if s.mergeExceedsDepth(left, right, s.size) {
+		return true
+	}
+	spine, merged := 0, left
+	switch {
+	case left == nil:
+		merged = right
+	case right != nil:
+		merged = right
+		if s.cmp(left.priority, right.priority) {
+			merged = left
+		}
+		spine, merged = 1, merged.left
+	}
+	for node := merged; node != nil; node = node.right {
+		spine++
+	}
+	return depthExceeds(s.maxDepth, 2+spine)
+}
+
+// replaceRoot removes the root and inserts value and priority, reusing the
+// root node for the new element so that nothing is allocated or released.
+// strict selects what happens if either merge would recurse too deep, as for
+// push.
+func (s *SkewHeap[V, P]) replaceRoot(value V, priority P, strict bool) (V, P, error) {
+	deep := s.replaceExceedsDepth()
+	if deep && strict {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrMaxDepthExceeded
+	}
+	merge := s.merger(deep)
+	node := s.root
+	v, p := node.value, node.priority
+	rest := merge(node.left, node.right)
+	node.value, node.priority = value, priority
+	node.left, node.right = nil, nil
+	s.root = merge(node, rest)
+	return v, p, nil
+}
+
+// popPush is an internal method that implements PopPush and TryPopPush.
+func (s *SkewHeap[V, P]) popPush(value V, priority P, strict bool) (V, P, error) {
+	if s.size == 0 {
+		return value, priority, nil
+	}
+	v, p, err := s.replaceRoot(value, priority, strict)
+	if err != nil {
+		return v, p, err
+	}
+	s.stats.record(OpPop, 1, s.size)
+	s.stats.record(OpPush, 1, s.size)
+	emitHeapEvent(s.events, EventPop, "", v, p)
+	emitHeapEvent(s.events, EventPush, "", value, priority)
+	return v, p, nil
+}
+
+// PopPush removes the root element and inserts a new element in one
+// operation, which is cheaper than a Pop followed by a Push. Returns the
+// removed root element. If the heap is empty, the new element is returned
+// without being inserted. Like Push, it cannot fail.
+func (s *SkewHeap[V, P]) PopPush(value V, priority P) (V, P) {
+	v, p, _ := s.popPush(value, priority, false)
+	return v, p
+}
+
+// TryPopPush works like PopPush, but returns ErrMaxDepthExceeded, leaving the
+// heap unchanged, if a merge would recurse deeper than the heap's MaxDepth.
+func (s *SkewHeap[V, P]) TryPopPush(value V, priority P) (V, P, error) {
+	return s.popPush(value, priority, true)
+}
+
+// pushPop is an internal method that implements PushPop and TryPushPop.
+func (s *SkewHeap[V, P]) pushPop(value V, priority P, strict bool) (V, P, error) {
+	if s.size == 0 || s.cmp(priority, s.root.priority) {
+		return value, priority, nil
+	}
+	v, p, err := s.replaceRoot(value, priority, strict)
+	if err != nil {
+		return v, p, err
+	}
+	s.stats.record(OpPush, 1, s.size)
+	s.stats.record(OpPop, 1, s.size)
+	emitHeapEvent(s.events, EventPush, "", value, priority)
+	emitHeapEvent(s.events, EventPop, "", v, p)
+	return v, p, nil
+}
+
+// PushPop inserts a new element and removes the root element in one
+// operation. If the new element belongs at the root, it is returned directly
+// and the heap is left unchanged. Otherwise the old root element is returned.
+// Like Push, it cannot fail.
+func (s *SkewHeap[V, P]) PushPop(value V, priority P) (V, P) {
+	v, p, _ := s.pushPop(value, priority, false)
+	return v, p
+}
+
+// TryPushPop works like PushPop, but returns ErrMaxDepthExceeded, leaving the
+// heap unchanged, if a merge would recurse deeper than the heap's MaxDepth.
+func (s *SkewHeap[V, P]) TryPushPop(value V, priority P) (V, P, error) {
+	return s.pushPop(value, priority, true)
 }
@@ -1,40 +1,65 @@
 package heapcraft
 
+import "golang.org/x/exp/constraints"
+
 // NewFullSkewHeap creates a new skew heap from the given data slice.
 // Each element is inserted individually using the provided comparison function
 // to determine heap order (min or max). Returns an empty heap if the input
 // slice is empty.
 func NewFullSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *FullSkewHeap[V, P] {
-	pool := newPool(config.UsePool, func() *skewHeapNode[V, P] {
+	pool := newConfiguredPool(config, func() *skewHeapNode[V, P] {
 		return &skewHeapNode[V, P]{}
 	})
-	elements := make(map[string]*skewHeapNode[V, P], len(data))
+	elements := make(map[string]*skewHeapNode[V, P], config.sizeHint(len(data)))
 	heap := FullSkewHeap[V, P]{
-		cmp:      cmp,
-		size:     0,
-		elements: elements,
-		pool:     pool,
-		idGen:    config.GetGenerator(),
+		cmp:           cmp,
+		size:          0,
+		elements:      elements,
+		pool:          pool,
+		idGen:         config.GetGenerator(),
+		onValueUpdate: make(listeners[ValueUpdateEvent[V]]),
+		maxDepth:      config.MaxDepth,
 	}
 	if len(data) == 0 {
 		return &heap
 	}
 
-	for i := range data {
-		heap.Push(data[i].value, data[i].priority)
-	}
+	heap.PushAll(data)
 	return &heap
 }
 
-// NewSkewHeap creates a new simple skew heap from the given data slice.
+// NewSimpleSkewHeap creates a new simple skew heap from the given data slice.
 // Each element is inserted individually using the provided comparison function
-// to determine heap order (min or max). Returns an empty heap if the input
-// slice is empty.
-func NewSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SkewHeap[V, P] {
-	pool := newPool(usePool, func() *skewNode[V, P] {
+// to determine heap order (min or max). Its nodes are allocated as config
+// selects: from an arena if ArenaSlabSize is positive, otherwise from the pool
+// chosen by UsePool, and its merges are limited to config.MaxDepth. The heap
+// does not track IDs, so IDGenerator is ignored.
+func NewSimpleSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SkewHeap[V, P] {
+	pool := newConfiguredPool(config, func() *skewNode[V, P] {
 		return &skewNode[V, P]{}
 	})
-	heap := SkewHeap[V, P]{cmp: cmp, size: 0, pool: pool}
+	return newSkewHeap(data, cmp, pool, config.MaxDepth)
+}
+
+// NewSkewHeap creates a new simple skew heap from the given data slice.
+//
+// Deprecated: use NewSimpleSkewHeap with HeapConfig{UsePool: usePool}.
+func NewSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SkewHeap[V, P] {
+	return NewSimpleSkewHeap(data, cmp, HeapConfig{UsePool: usePool})
+}
+
+// NewArenaSkewHeap creates a simple skew heap whose nodes are allocated from
+// slabs of slabSize nodes (DefaultArenaSlabSize if not positive) and released
+// all at once by Clear. It suits heaps that are built once and drained, where
+// per-node allocation dominates and sync.Pool cannot help.
+func NewArenaSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, slabSize int) *SkewHeap[V, P] {
+	return newSkewHeap(data, cmp, newArenaPool[skewNode[V, P]](slabSize), 0)
+}
+
+// newSkewHeap creates a simple skew heap from data that allocates its nodes
+// from pool and limits its merges to maxDepth.
+func newSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, pool pool[*skewNode[V, P]], maxDepth int) *SkewHeap[V, P] {
+	heap := SkewHeap[V, P]{cmp: cmp, size: 0, pool: pool, maxDepth: maxDepth}
 	if len(data) == 0 {
 		return &heap
 	}
@@ -45,12 +70,17 @@ func NewSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, use
 	return &heap
 }
 
+// NewSyncSimpleSkewHeap constructs a new thread-safe simple skew heap with its
+// nodes allocated as config selects. See NewSimpleSkewHeap.
+func NewSyncSimpleSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SyncSkewHeap[V, P] {
+	return &SyncSkewHeap[V, P]{heap: NewSimpleSkewHeap(data, cmp, config)}
+}
+
 // NewSyncSkewHeap constructs a new thread-safe skew heap from the given data and comparison function.
-// The resulting heap is safe for concurrent use.
+//
+// Deprecated: use NewSyncSimpleSkewHeap with HeapConfig{UsePool: usePool}.
 func NewSyncSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, usePool bool) *SyncSkewHeap[V, P] {
-	return &SyncSkewHeap[V, P]{
-		heap: NewSkewHeap(data, cmp, usePool),
-	}
+	return NewSyncSimpleSkewHeap(data, cmp, HeapConfig{UsePool: usePool})
 }
 
 // NewSyncFullSkewHeap constructs a new thread-safe full skew heap from the given data and comparison function.
@@ -60,3 +90,43 @@ func NewSyncFullSkewHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) b
 		heap: NewFullSkewHeap(data, cmp, config),
 	}
 }
+
+// NewMinSimpleSkewHeap creates a simple skew min-heap from data, ordered by
+// the natural ordering of P, so the smallest priority is popped first.
+func NewMinSimpleSkewHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *SkewHeap[V, P] {
+	return NewSimpleSkewHeap(data, orderedLess[P], config)
+}
+
+// NewMaxSimpleSkewHeap creates a simple skew max-heap from data, ordered by
+// the natural ordering of P, so the largest priority is popped first.
+func NewMaxSimpleSkewHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *SkewHeap[V, P] {
+	return NewSimpleSkewHeap(data, orderedGreater[P], config)
+}
+
+// NewMinSkewHeap creates a skew min-heap from data, ordered by the natural
+// ordering of P, so the smallest priority is popped first.
+//
+// Deprecated: use NewMinSimpleSkewHeap with HeapConfig{UsePool: usePool}.
+func NewMinSkewHeap[V any, P constraints.Ordered](data []HeapNode[V, P], usePool bool) *SkewHeap[V, P] {
+	return NewMinSimpleSkewHeap(data, HeapConfig{UsePool: usePool})
+}
+
+// NewMaxSkewHeap creates a skew max-heap from data, ordered by the natural
+// ordering of P, so the largest priority is popped first.
+//
+// Deprecated: use NewMaxSimpleSkewHeap with HeapConfig{UsePool: usePool}.
+func NewMaxSkewHeap[V any, P constraints.Ordered](data []HeapNode[V, P], usePool bool) *SkewHeap[V, P] {
+	return NewMaxSimpleSkewHeap(data, HeapConfig{UsePool: usePool})
+}
+
+// NewMinFullSkewHeap creates a skew min-heap with node tracking from data,
+// ordered by the natural ordering of P.
+func NewMinFullSkewHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *FullSkewHeap[V, P] {
+	return NewFullSkewHeap(data, orderedLess[P], config)
+}
+
+// NewMaxFullSkewHeap creates a skew max-heap with node tracking from data,
+// ordered by the natural ordering of P.
+func NewMaxFullSkewHeap[V any, P constraints.Ordered](data []HeapNode[V, P], config HeapConfig) *FullSkewHeap[V, P] {
+	return NewFullSkewHeap(data, orderedGreater[P], config)
+}
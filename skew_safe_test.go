@@ -100,7 +100,7 @@ func TestSyncFullSkewHeap_EmptyOperations(t *testing.T) {
 	assert.Equal(t, ErrHeapEmpty, err)
 
 	_, _, err = heap.Get("nonexistent")
-	assert.Equal(t, ErrNodeNotFound, err)
+	assert.ErrorIs(t, err, ErrNodeNotFound)
 }
 
 func TestSyncSkewHeap_BasicOperations(t *testing.T) {
@@ -177,3 +177,33 @@ func TestSyncSkewHeap_EmptyOperations(t *testing.T) {
 	_, _, err = heap.Peek()
 	assert.Equal(t, ErrHeapEmpty, err)
 }
+
+func TestSyncSkewHeap_ConcurrentMeld(t *testing.T) {
+	h1 := NewSyncSkewHeap[int](nil, lt, false)
+	h2 := NewSyncSkewHeap[int](nil, lt, false)
+	h3 := NewSyncFullSkewHeap[int](nil, lt, HeapConfig{})
+	h4 := NewSyncFullSkewHeap[int](nil, lt, HeapConfig{})
+	for i := 0; i < 10; i++ {
+		h1.Push(i, i)
+		h2.Push(i+10, i+10)
+		h3.Push(i, i)
+		h4.Push(i+10, i+10)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		h1.Meld(h2)
+		assert.Nil(t, h3.Meld(h4))
+	}()
+	go func() {
+		defer wg.Done()
+		h2.Meld(h1)
+		assert.Nil(t, h4.Meld(h3))
+	}()
+	wg.Wait()
+
+	assert.Equal(t, 20, h1.Length()+h2.Length())
+	assert.Equal(t, 20, h3.Length()+h4.Length())
+}
@@ -0,0 +1,470 @@
+package heapcraft
+
+import "slices"
+
+// skewBinomialNode represents a node in a skew binomial heap. Each node is the
+// root of a skew binomial tree of the given rank, whose children are linked
+// through the sibling pointer in decreasing order of rank. A node of rank r
+// also carries up to r extra elements that are no better than the node itself
+// and have not yet been given nodes of their own.
+type skewBinomialNode[V any, P any] struct {
+	value    V
+	priority P
+	rank     int
+	extra    []HeapNode[V, P]
+	child    *skewBinomialNode[V, P]
+	sibling  *skewBinomialNode[V, P]
+}
+
+// SkewBinomialHeap implements a skew binomial heap (Brodal and Okasaki), a
+// forest of skew binomial trees kept in increasing order of rank where only
+// the first two trees may share a rank. Unlike the pairing, skew and leftist
+// heaps, whose bounds are amortized, every operation is bounded in the worst
+// case: Push takes O(1) time, and Pop and Meld take O(log n) time, so no
+// single call ever pays for work deferred by earlier ones. This suits
+// latency-sensitive loops where an occasional long pause is worse than a
+// slightly higher average cost. No operation recurses, so deep trees cannot
+// exhaust the stack either. The heap can be either a min-heap or max-heap
+// depending on the comparison function.
+type SkewBinomialHeap[V any, P any] struct {
+	head   *skewBinomialNode[V, P]
+	cmp    func(a, b P) bool
+	size   int
+	pool   pool[*skewBinomialNode[V, P]]
+	alarms depthAlarms
+	stats  heapStats
+	events listeners[HeapEvent[V, P]]
+}
+
+// Clone creates a deep copy of the heap structure and nodes. If values or
+// priorities are reference types, those reference values are shared between the
+// original and cloned heaps. The clone receives its own node pool, so nodes
+// released by one heap are never reused by the other.
+func (s *SkewBinomialHeap[V, P]) Clone() *SkewBinomialHeap[V, P] {
+	cloned := &SkewBinomialHeap[V, P]{
+		cmp:    s.cmp,
+		size:   s.size,
+		pool:   s.pool.fresh(),
+		alarms: s.alarms.clone(),
+		stats:  s.stats,
+		events: s.events.clone(),
+	}
+	cloned.head = cloneTree(s.head, cloned.pool.Get, func(n *skewBinomialNode[V, P]) (**skewBinomialNode[V, P], **skewBinomialNode[V, P]) {
+		// Each copy starts out sharing its original's extra elements.
+		n.extra = slices.Clone(n.extra)
+		return &n.child, &n.sibling
+	})
+	return cloned
+}
+
+// Clear removes all elements from the heap.
+// The heap is ready for new insertions after clearing.
+// When pooling is enabled, the nodes are returned to the pool for reuse.
+func (s *SkewBinomialHeap[V, P]) Clear() {
+	cleared := s.Length()
+	releaseTree(s.pool, treeRoots(s.head), func(n *skewBinomialNode[V, P], visit func(*skewBinomialNode[V, P])) {
+		if n.child != nil {
+			visit(n.child)
+		}
+		if n.sibling != nil {
+			visit(n.sibling)
+		}
+	})
+	s.head = nil
+	s.size = 0
+	s.stats.record(OpClear, cleared, s.size)
+	s.alarms.check(s.size)
+	emitClearEvent(s.events)
+}
+
+// PoolStats reports how the heap's node pool has been used since the heap was
+// created.
+func (s *SkewBinomialHeap[V, P]) PoolStats() PoolStats { return s.pool.stats() }
+
+// ApproxMemoryUsage estimates the bytes held by the heap: its nodes and the
+// nodes idle in its pool. Memory that values and priorities point to, such as
+// the contents of strings, is not counted.
+func (s *SkewBinomialHeap[V, P]) ApproxMemoryUsage() int {
+	return sizeOf[SkewBinomialHeap[V, P]]() + nodeMemory(s.size, s.pool)
+}
+
+// RegisterDepthAlarm registers fn to be called once when the length of the
+// heap rises to n, and once more when it falls back to n - max(1, n/10) or
+// below, so a backlog hovering around n does not fire repeatedly. Values of n
+// below 1 are treated as 1. Returns an ID that can be passed to
+// RemoveDepthAlarm.
+func (s *SkewBinomialHeap[V, P]) RegisterDepthAlarm(n int, fn func(DepthEvent)) string {
+	return s.alarms.register(n, s.Length(), fn)
+}
+
+// RemoveDepthAlarm removes the depth alarm with the specified ID. Returns an
+// error if no alarm exists with the given ID.
+func (s *SkewBinomialHeap[V, P]) RemoveDepthAlarm(id string) error {
+	return s.alarms.deregister(id)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+func (s *SkewBinomialHeap[V, P]) Stats() HeapStats { return s.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (s *SkewBinomialHeap[V, P]) Instrument(hook Instrumentation) { s.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element melded in from another heap, and whenever the heap is
+// cleared. Returns an ID that can be passed to RemoveListener.
+func (s *SkewBinomialHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return s.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SkewBinomialHeap[V, P]) RemoveListener(id string) error { return s.events.deregister(id) }
+
+// Length returns the current number of elements in the heap.
+func (s *SkewBinomialHeap[V, P]) Length() int { return s.size }
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SkewBinomialHeap[V, P]) IsEmpty() bool { return s.size == 0 }
+
+// Verify checks the internal consistency of the heap: the root list must be
+// in increasing order of rank with only the first two roots allowed to share
+// a rank, every node of rank k must have children of ranks k-1 down to 0 in
+// that order and at most k extra elements, no node or extra element may come
+// before its parent, and the trees must hold exactly Length elements. It is
+// intended for tests and debugging, runs in O(n) and returns an error
+// wrapping ErrInvariantViolated for the first violation.
+func (s *SkewBinomialHeap[V, P]) Verify() error {
+	var roots []*skewBinomialNode[V, P]
+	for root := s.head; root != nil; root = root.sibling {
+		if n := len(roots); n > 0 && (roots[n-1].rank > root.rank || roots[n-1].rank == root.rank && n > 1) {
+			return invariantError("root %d of rank %d follows a root of rank %d", n, root.rank, roots[n-1].rank)
+		}
+		roots = append(roots, root)
+	}
+
+	extras := 0
+	visited, err := verifyTree(roots,
+		func(node *skewBinomialNode[V, P], visit func(*skewBinomialNode[V, P])) {
+			for child := node.child; child != nil; child = child.sibling {
+				visit(child)
+			}
+		},
+		func(node, parent *skewBinomialNode[V, P]) error {
+			rank := node.rank - 1
+			for child := node.child; child != nil; child = child.sibling {
+				if child.rank != rank {
+					return invariantError("node of rank %d has a child of rank %d instead of %d", node.rank, child.rank, rank)
+				}
+				rank--
+			}
+			if rank != -1 {
+				return invariantError("node of rank %d has %d children", node.rank, node.rank-rank-1)
+			}
+			if len(node.extra) > node.rank {
+				return invariantError("node of rank %d carries %d extra elements", node.rank, len(node.extra))
+			}
+			for _, e := range node.extra {
+				if err := verifyOrder(s.cmp, e.priority, node.priority); err != nil {
+					return err
+				}
+			}
+			extras += len(node.extra)
+			if parent == nil {
+				return nil
+			}
+			return verifyOrder(s.cmp, node.priority, parent.priority)
+		})
+	if err != nil {
+		return err
+	}
+	return verifySize(visited+extras, s.size)
+}
+
+// findRoot scans the root list and returns the root with the highest priority
+// (according to cmp) together with the root preceding it in the list. The
+// list holds O(log n) roots. The caller must ensure the heap is not empty.
+func (s *SkewBinomialHeap[V, P]) findRoot() (*skewBinomialNode[V, P], *skewBinomialNode[V, P]) {
+	var prev, prevBest *skewBinomialNode[V, P]
+	best := s.head
+	for cur := s.head; cur != nil; prev, cur = cur, cur.sibling {
+		if s.cmp(cur.priority, best.priority) {
+			best, prevBest = cur, prev
+		}
+	}
+	return best, prevBest
+}
+
+// peek is an internal method that returns the root node's value and priority
+// without removing it. Returns zero values and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) peek() (V, P, error) {
+	if s.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	root, _ := s.findRoot()
+	return root.value, root.priority, nil
+}
+
+// Peek returns the value and priority of the root element without removing it.
+// Returns zero values and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) Peek() (V, P, error) { return s.peek() }
+
+// PeekValue returns the value at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) PeekValue() (V, error) {
+	return valueFromNode(s.peek())
+}
+
+// PeekPriority returns the priority at the root without removing it.
+// Returns zero value and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) PeekPriority() (P, error) {
+	return priorityFromNode(s.peek())
+}
+
+// link combines two trees of the same rank by making the one with the worse
+// root the first child of the other, and returns the resulting tree.
+func (s *SkewBinomialHeap[V, P]) link(x, y *skewBinomialNode[V, P]) *skewBinomialNode[V, P] {
+	if s.cmp(y.priority, x.priority) {
+		x, y = y, x
+	}
+	s.stats.rebalance()
+	y.sibling = x.child
+	x.child = y
+	x.rank++
+	return x
+}
+
+// mergeRoots merges two root lists, each sorted by increasing rank, into a
+// single root list sorted by increasing rank. Trees of equal rank are not yet
+// combined.
+func (s *SkewBinomialHeap[V, P]) mergeRoots(x, y *skewBinomialNode[V, P]) *skewBinomialNode[V, P] {
+	var head skewBinomialNode[V, P]
+	tail := &head
+	for x != nil && y != nil {
+		if x.rank <= y.rank {
+			tail.sibling, x = x, x.sibling
+		} else {
+			tail.sibling, y = y, y.sibling
+		}
+		tail = tail.sibling
+	}
+
+	if x != nil {
+		tail.sibling = x
+	} else {
+		tail.sibling = y
+	}
+	return head.sibling
+}
+
+// union combines two root lists that each hold at most one tree per rank
+// into a single root list with the same property, linking trees of equal
+// rank. Returns the head of the resulting root list.
+func (s *SkewBinomialHeap[V, P]) union(x, y *skewBinomialNode[V, P]) *skewBinomialNode[V, P] {
+	head := s.mergeRoots(x, y)
+	if head == nil {
+		return nil
+	}
+
+	var prev *skewBinomialNode[V, P]
+	cur := head
+	next := cur.sibling
+	for next != nil {
+		if cur.rank != next.rank || (next.sibling != nil && next.sibling.rank == cur.rank) {
+			prev, cur = cur, next
+		} else {
+			after := next.sibling
+			cur.sibling, next.sibling = nil, nil
+			cur = s.link(cur, next)
+			cur.sibling = after
+			if prev == nil {
+				head = cur
+			} else {
+				prev.sibling = cur
+			}
+		}
+		next = cur.sibling
+	}
+	return head
+}
+
+// normalize links the first two trees of a root list if they share a rank,
+// so that the list holds at most one tree per rank as union expects.
+func (s *SkewBinomialHeap[V, P]) normalize(head *skewBinomialNode[V, P]) *skewBinomialNode[V, P] {
+	if head == nil {
+		return nil
+	}
+	rest := head.sibling
+	head.sibling = nil
+	return s.union(head, rest)
+}
+
+// insert adds an element to the root list in O(1) time. If the first two
+// trees share a rank they are skew linked under the better of their roots
+// and the new element, and the worst of the three roots becomes an extra
+// element of the combined tree; otherwise the element becomes a new tree of
+// rank 0.
+func (s *SkewBinomialHeap[V, P]) insert(value V, priority P) {
+	if first := s.head; first != nil && first.sibling != nil && first.rank == first.sibling.rank {
+		second := first.sibling
+		rest := second.sibling
+		first.sibling, second.sibling = nil, nil
+		root := s.link(first, second)
+		if s.cmp(priority, root.priority) {
+			root.extra = append(root.extra, HeapNode[V, P]{value: root.value, priority: root.priority})
+			root.value, root.priority = value, priority
+		} else {
+			root.extra = append(root.extra, HeapNode[V, P]{value: value, priority: priority})
+		}
+		root.sibling = rest
+		s.head = root
+		return
+	}
+
+	node := s.pool.Get()
+	node.value = value
+	node.priority = priority
+	node.sibling = s.head
+	s.head = node
+}
+
+// pop is an internal method that removes the root node and returns it.
+// The children of the removed root are reversed into a root list and
+// unioned back into the heap, and its extra elements are inserted again.
+// Returns zero values and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) pop() (V, P, error) {
+	if s.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+
+	removed, prev := s.findRoot()
+	if prev == nil {
+		s.head = removed.sibling
+	} else {
+		prev.sibling = removed.sibling
+	}
+
+	// Children are stored in decreasing order of rank, so reverse them to
+	// form a valid root list before unioning.
+	var children *skewBinomialNode[V, P]
+	for child := removed.child; child != nil; {
+		next := child.sibling
+		child.sibling = children
+		children = child
+		child = next
+	}
+
+	s.head = s.union(s.normalize(s.head), children)
+	for _, e := range removed.extra {
+		s.insert(e.value, e.priority)
+	}
+	s.size--
+	s.stats.record(OpPop, 1, s.size)
+	s.alarms.check(s.size)
+	v, p := removed.value, removed.priority
+	*removed = skewBinomialNode[V, P]{}
+	s.pool.Put(removed)
+	emitHeapEvent(s.events, EventPop, "", v, p)
+	return v, p, nil
+}
+
+// Pop removes and returns the value and priority of the root element in
+// O(log n) worst-case time. Returns zero values and an error if the heap is
+// empty.
+func (s *SkewBinomialHeap[V, P]) Pop() (V, P, error) { return s.pop() }
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. If pred reports false the root stays in the heap and
+// its value and priority are returned with false. Returns zero values, false
+// and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	return popIf(s.Peek, s.Pop, pred)
+}
+
+// PopValue removes and returns just the value at the root.
+// Returns zero value and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) PopValue() (V, error) {
+	return valueFromNode(s.pop())
+}
+
+// PopPriority removes and returns just the priority at the root.
+// Returns zero value and an error if the heap is empty.
+func (s *SkewBinomialHeap[V, P]) PopPriority() (P, error) {
+	return priorityFromNode(s.pop())
+}
+
+// Drain removes all elements from the heap and returns them in priority order.
+// The heap is empty afterwards.
+func (s *SkewBinomialHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(s.Length(), s.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// priority order. The heap is empty afterwards.
+func (s *SkewBinomialHeap[V, P]) DrainValues() []V {
+	return drainValues(s.Length(), s.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// priorities in priority order. The heap is empty afterwards.
+func (s *SkewBinomialHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(s.Length(), s.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (s *SkewBinomialHeap[V, P]) forEach(fn func(v V, p P)) {
+	stack := make([]*skewBinomialNode[V, P], 0)
+	if s.head != nil {
+		stack = append(stack, s.head)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(node.value, node.priority)
+		for _, e := range node.extra {
+			fn(e.value, e.priority)
+		}
+		if node.child != nil {
+			stack = append(stack, node.child)
+		}
+		if node.sibling != nil {
+			stack = append(stack, node.sibling)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first and truncated to the optional
+// limit. The heap itself is not modified.
+func (s *SkewBinomialHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(s.Length(), s.forEach, s.cmp, opts)
+}
+
+// Push adds a new element to the heap in O(1) worst-case time. At most two
+// roots are linked, however many elements the heap holds.
+func (s *SkewBinomialHeap[V, P]) Push(value V, priority P) {
+	s.insert(value, priority)
+	s.size++
+	s.stats.record(OpPush, 1, s.size)
+	s.alarms.check(s.size)
+	emitHeapEvent(s.events, EventPush, "", value, priority)
+}
+
+// Meld merges another skew binomial heap into this one in O(log n)
+// worst-case time. The other heap is consumed by the operation and left
+// empty. Both heaps are expected to share the same comparison function.
+func (s *SkewBinomialHeap[V, P]) Meld(other *SkewBinomialHeap[V, P]) {
+	if other == nil || other == s {
+		return
+	}
+	if len(s.events) > 0 {
+		other.forEach(func(v V, p P) { emitHeapEvent(s.events, EventPush, "", v, p) })
+	}
+	s.head = s.union(s.normalize(s.head), s.normalize(other.head))
+	s.size += other.size
+	s.stats.record(OpMeld, other.size, s.size)
+	s.alarms.check(s.size)
+	other.head = nil
+	other.Clear()
+}
@@ -0,0 +1,27 @@
+package heapcraft
+
+// NewSkewBinomialHeap creates a new skew binomial heap from the given data
+// slice. Each element is inserted individually in O(1) time using the provided
+// comparison function to determine heap order (min or max). Returns an empty
+// heap if the input slice is empty. Nodes are allocated as config selects:
+// from an arena if ArenaSlabSize is positive, otherwise from the pool chosen
+// by UsePool. The heap does not track IDs, so IDGenerator is ignored.
+func NewSkewBinomialHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SkewBinomialHeap[V, P] {
+	pool := newConfiguredPool(config, func() *skewBinomialNode[V, P] {
+		return &skewBinomialNode[V, P]{}
+	})
+	heap := SkewBinomialHeap[V, P]{cmp: cmp, size: 0, pool: pool}
+	for i := range data {
+		heap.Push(data[i].value, data[i].priority)
+	}
+	return &heap
+}
+
+// NewSyncSkewBinomialHeap constructs a new thread-safe skew binomial heap from
+// the given data and comparison function. The resulting heap is safe for
+// concurrent use. See NewSkewBinomialHeap.
+func NewSyncSkewBinomialHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, config HeapConfig) *SyncSkewBinomialHeap[V, P] {
+	return &SyncSkewBinomialHeap[V, P]{
+		heap: NewSkewBinomialHeap(data, cmp, config),
+	}
+}
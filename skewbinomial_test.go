@@ -0,0 +1,171 @@
+package heapcraft
+
+import (
+	"math/bits"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkewBinomialHeap_MatchesSortedOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	h := NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	_, _, err := h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	var want []int
+	for i := 0; i < 3000; i++ {
+		if rng.Intn(3) > 0 || h.IsEmpty() {
+			p := rng.Intn(500)
+			h.Push(i, p)
+			want = append(want, p)
+		} else {
+			slices.Sort(want)
+			peeked, err := h.PeekPriority()
+			require.NoError(t, err)
+			p, err := h.PopPriority()
+			require.NoError(t, err)
+			assert.Equal(t, want[0], p)
+			assert.Equal(t, peeked, p)
+			want = want[1:]
+		}
+		if i%100 == 0 {
+			require.NoError(t, h.Verify())
+		}
+	}
+	require.NoError(t, h.Verify())
+	assert.Equal(t, len(want), h.Length())
+	slices.Sort(want)
+	assert.Equal(t, want, h.DrainPriorities())
+	assert.True(t, h.IsEmpty())
+}
+
+func TestSkewBinomialHeap_RootListStaysLogarithmic(t *testing.T) {
+	h := NewSkewBinomialHeap[int, int](nil, gt, HeapConfig{})
+	for i := 1; i <= 1<<16; i++ {
+		h.Push(i, i)
+		roots := 0
+		for root := h.head; root != nil; root = root.sibling {
+			roots++
+		}
+		require.LessOrEqual(t, roots, 2*bits.Len(uint(i)), "after %d pushes", i)
+	}
+	require.NoError(t, h.Verify())
+
+	v, err := h.PopValue()
+	require.NoError(t, err)
+	assert.Equal(t, 1<<16, v)
+	require.NoError(t, h.Verify())
+}
+
+func TestSkewBinomialHeap_MeldCloneClear(t *testing.T) {
+	h1 := NewSkewBinomialHeap([]HeapNode[string, int]{
+		CreateHeapNode("c", 3),
+		CreateHeapNode("a", 1),
+		CreateHeapNode("e", 5),
+		CreateHeapNode("g", 7),
+	}, lt, HeapConfig{UsePool: true})
+	h2 := NewSkewBinomialHeap([]HeapNode[string, int]{
+		CreateHeapNode("b", 2),
+		CreateHeapNode("d", 4),
+		CreateHeapNode("f", 6),
+	}, lt, HeapConfig{UsePool: true})
+
+	h1.Meld(h2)
+	h1.Meld(h1)
+	h1.Meld(nil)
+	require.NoError(t, h1.Verify())
+	assert.True(t, h2.IsEmpty())
+	assert.Equal(t, 7, h1.Length())
+
+	clone := h1.Clone()
+	v, p, err := h1.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "a", v)
+	assert.Equal(t, 1, p)
+
+	exported := clone.Export(ExportOptions[string, int]{})
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, nodePriorities(exported))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g"}, clone.DrainValues())
+	assert.Equal(t, []string{"b", "c", "d", "e", "f", "g"}, h1.DrainValues())
+
+	h1.Push("z", 26)
+	h1.Clear()
+	assert.True(t, h1.IsEmpty())
+	require.NoError(t, h1.Verify())
+	_, err = h1.PeekValue()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestSyncSkewBinomialHeap_ConcurrentPushPop(t *testing.T) {
+	h := NewSyncSkewBinomialHeap[int, int](nil, lt, HeapConfig{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				h.Push(g*500+i, g*500+i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	require.NoError(t, h.Verify())
+	assert.Equal(t, 2000, h.Length())
+
+	got := h.DrainPriorities()
+	assert.True(t, slices.IsSorted(got))
+	assert.Len(t, got, 2000)
+}
+
+// -------------------------------- Skew Binomial Heap Benchmarks --------------------------------
+
+func BenchmarkSkewBinomialHeap_PushPop(b *testing.B) {
+	heap := NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+	insertions := generateRandomNumbersv1(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(insertions[i], insertions[i])
+	}
+	for i := 0; i < b.N; i++ {
+		heap.Pop()
+	}
+}
+
+// benchmarkPopLatency alternates bursts of pushes with a few pops and reports
+// the 99th percentile latency of a single Pop, which an amortized heap spends
+// on the first Pop after each burst.
+func benchmarkPopLatency(b *testing.B, h Heap[int, int]) {
+	const burst, pops = 1 << 12, 64
+	rng := rand.New(rand.NewSource(42))
+	latencies := make([]time.Duration, 0, b.N*pops)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < burst; j++ {
+			p := rng.Int()
+			h.Push(p, p)
+		}
+		for j := 0; j < pops; j++ {
+			start := time.Now()
+			h.Pop()
+			latencies = append(latencies, time.Since(start))
+		}
+	}
+	b.StopTimer()
+	slices.Sort(latencies)
+	b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns/pop")
+}
+
+func BenchmarkPopLatency_PairingHeap(b *testing.B) {
+	benchmarkPopLatency(b, NewPairingHeap[int, int](nil, lt, true))
+}
+
+func BenchmarkPopLatency_SkewBinomialHeap(b *testing.B) {
+	benchmarkPopLatency(b, NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}))
+}
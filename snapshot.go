@@ -0,0 +1,95 @@
+package heapcraft
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// snapshotVersion is the version of the binary snapshot format written by
+// Snapshot. Restore rejects snapshots written in any other version.
+const snapshotVersion = 1
+
+// heapSnapshot is the gob-encoded form of a heap. Elements are stored column
+// by column rather than as a slice of structs, which keeps gob's per-element
+// overhead low for large heaps. Elements are stored in the heap's internal
+// order, so taking a snapshot never sorts.
+type heapSnapshot[V any, P any] struct {
+	Version    int
+	D          int
+	Last       P
+	IDs        []string
+	Values     []V
+	Priorities []P
+}
+
+// encodeSnapshot gob-encodes a snapshot after stamping it with the current
+// format version.
+func encodeSnapshot[V any, P any](snapshot heapSnapshot[V, P]) ([]byte, error) {
+	snapshot.Version = snapshotVersion
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeSnapshot decodes a snapshot and checks that its version is supported
+// and that its columns line up. Snapshots of tracked heaps must carry an ID
+// for every element and must not repeat an ID.
+func decodeSnapshot[V any, P any](data []byte, tracked bool) (heapSnapshot[V, P], error) {
+	var snapshot heapSnapshot[V, P]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+		return snapshot, err
+	}
+
+	n := len(snapshot.Values)
+	if snapshot.Version != snapshotVersion || len(snapshot.Priorities) != n {
+		return snapshot, ErrInvalidSnapshot
+	}
+	if !tracked {
+		return snapshot, nil
+	}
+
+	if len(snapshot.IDs) != n {
+		return snapshot, ErrInvalidSnapshot
+	}
+	seen := make(map[string]struct{}, n)
+	for _, id := range snapshot.IDs {
+		if _, exists := seen[id]; exists {
+			return snapshot, ErrDuplicateID
+		}
+		seen[id] = struct{}{}
+	}
+	return snapshot, nil
+}
+
+// snapshotNodes collects the elements visited by forEach into the columns of
+// a snapshot.
+func snapshotNodes[V any, P any](n int, forEach func(fn func(v V, p P))) heapSnapshot[V, P] {
+	snapshot := heapSnapshot[V, P]{
+		Values:     make([]V, 0, n),
+		Priorities: make([]P, 0, n),
+	}
+	forEach(func(v V, p P) {
+		snapshot.Values = append(snapshot.Values, v)
+		snapshot.Priorities = append(snapshot.Priorities, p)
+	})
+	return snapshot
+}
+
+// snapshotTracked collects the elements of a tracked heap, together with their
+// IDs, into the columns of a snapshot.
+func snapshotTracked[V any, P any, N any](elements map[string]N, fields func(N) (V, P)) heapSnapshot[V, P] {
+	snapshot := heapSnapshot[V, P]{
+		IDs:        make([]string, 0, len(elements)),
+		Values:     make([]V, 0, len(elements)),
+		Priorities: make([]P, 0, len(elements)),
+	}
+	for id, node := range elements {
+		v, p := fields(node)
+		snapshot.IDs = append(snapshot.IDs, id)
+		snapshot.Values = append(snapshot.Values, v)
+		snapshot.Priorities = append(snapshot.Priorities, p)
+	}
+	return snapshot
+}
@@ -0,0 +1,210 @@
+package heapcraft
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotHeap is a heap that can be persisted with Snapshot and Restore.
+type snapshotHeap interface {
+	Heap[string, int]
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
+}
+
+func TestHeap_SnapshotRestore(t *testing.T) {
+	heaps := map[string]func() snapshotHeap{
+		"dary":        func() snapshotHeap { return NewDaryHeap[string, int](3, nil, lt, false) },
+		"syncDary":    func() snapshotHeap { return NewSyncDaryHeap[string, int](3, nil, lt, true) },
+		"pairing":     func() snapshotHeap { return NewPairingHeap[string, int](nil, lt, false) },
+		"syncPairing": func() snapshotHeap { return NewSyncPairingHeap[string, int](nil, lt, true) },
+		"leftist":     func() snapshotHeap { return NewLeftistHeap[string, int](nil, lt, false) },
+		"syncLeftist": func() snapshotHeap { return NewSyncLeftistHeap[string, int](nil, lt, true) },
+		"skew":        func() snapshotHeap { return NewSkewHeap[string, int](nil, lt, false) },
+		"syncSkew":    func() snapshotHeap { return NewSyncSkewHeap[string, int](nil, lt, true) },
+		"binomial":    func() snapshotHeap { return NewBinomialHeap[string, int](nil, lt, HeapConfig{}) },
+		"adaptive":    func() snapshotHeap { return NewAdaptiveHeap[string, int](nil, lt, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		for i, v := range []string{"e", "b", "d", "a", "c"} {
+			heap.Push(v, []int{5, 2, 4, 1, 3}[i])
+		}
+
+		data, err := heap.Snapshot()
+		require.Nil(t, err, name)
+		assert.Equal(t, 5, heap.Length(), name)
+
+		restored := constructor()
+		restored.Push("stale", 0)
+		require.Nil(t, restored.Restore(data), name)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, restored.DrainValues(), name)
+	}
+}
+
+func TestDaryHeap_SnapshotArity(t *testing.T) {
+	heap := NewDaryHeap[string, int](4, nil, lt, false)
+	for i := 20; i > 0; i-- {
+		heap.Push("v", i)
+	}
+	data, err := heap.Snapshot()
+	require.Nil(t, err)
+
+	restored := NewBinaryHeap[string, int](nil, lt, false)
+	require.Nil(t, restored.Restore(data))
+	assert.Equal(t, 4, restored.d)
+	assert.Equal(t, heap.DrainPriorities(), restored.DrainPriorities())
+
+	var uninitialized DaryHeap[string, int]
+	assert.ErrorIs(t, uninitialized.Restore(data), ErrUninitializedHeap)
+}
+
+func TestRadixHeap_SnapshotRestore(t *testing.T) {
+	heap := NewRadixHeap[string, uint](nil, false)
+	heap.Push("a", 1)
+	heap.Push("b", 5)
+	heap.Push("c", 9)
+	heap.Pop()
+
+	data, err := heap.Snapshot()
+	require.Nil(t, err)
+
+	restored := NewSyncRadixHeap[string, uint](nil, false)
+	require.Nil(t, restored.Restore(data))
+	assert.Equal(t, heap.last, restored.heap.last)
+	assert.ErrorIs(t, restored.Push("z", 0), ErrPriorityLessThanLast)
+	assert.Equal(t, []string{"b", "c"}, restored.DrainValues())
+
+	invalid, err := encodeSnapshot(heapSnapshot[string, uint]{
+		Last:       4,
+		Values:     []string{"x"},
+		Priorities: []uint{3},
+	})
+	require.Nil(t, err)
+	assert.ErrorIs(t, restored.Restore(invalid), ErrPriorityLessThanLast)
+
+	var uninitialized RadixHeap[string, uint]
+	assert.ErrorIs(t, uninitialized.Restore(data), ErrUninitializedHeap)
+}
+
+func TestTrackedHeap_SnapshotRestore(t *testing.T) {
+	type trackedSnapshotHeap interface {
+		TrackedHeap[string, int]
+		Snapshot() ([]byte, error)
+		Restore(data []byte) error
+	}
+
+	config := func() HeapConfig { return HeapConfig{IDGenerator: &IntegerIDGenerator{}} }
+	heaps := map[string]func() trackedSnapshotHeap{
+		"pairing":     func() trackedSnapshotHeap { return NewFullPairingHeap[string, int](nil, lt, config()) },
+		"syncPairing": func() trackedSnapshotHeap { return NewSyncFullPairingHeap[string, int](nil, lt, config()) },
+		"leftist":     func() trackedSnapshotHeap { return NewFullLeftistHeap[string, int](nil, lt, config()) },
+		"syncLeftist": func() trackedSnapshotHeap { return NewSyncFullLeftistHeap[string, int](nil, lt, config()) },
+		"skew":        func() trackedSnapshotHeap { return NewFullSkewHeap[string, int](nil, lt, config()) },
+		"syncSkew":    func() trackedSnapshotHeap { return NewSyncFullSkewHeap[string, int](nil, lt, config()) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		heap.Push("c", 3)
+		heap.Push("a", 1)
+		heap.Push("b", 2)
+
+		data, err := heap.Snapshot()
+		require.Nil(t, err, name)
+
+		restored := constructor()
+		require.Nil(t, restored.Restore(data), name)
+		value, err := restored.GetValue("0")
+		assert.Nil(t, err, name)
+		assert.Equal(t, "c", value, name)
+		assert.Nil(t, restored.UpdatePriority("0", 0), name)
+
+		// The ID generator continues after the restored IDs.
+		id, err := restored.Push("d", 4)
+		assert.Nil(t, err, name)
+		assert.Equal(t, "3", id, name)
+		assert.Equal(t, []string{"c", "a", "b", "d"}, restored.DrainValues(), name)
+
+		// A snapshot of a simple heap carries no IDs.
+		simple := NewPairingHeap[string, int](nil, lt, false)
+		simple.Push("x", 1)
+		untracked, err := simple.Snapshot()
+		require.Nil(t, err)
+		assert.ErrorIs(t, restored.Restore(untracked), ErrInvalidSnapshot, name)
+	}
+
+	duplicate, err := encodeSnapshot(heapSnapshot[string, int]{
+		IDs:        []string{"x", "x"},
+		Values:     []string{"a", "b"},
+		Priorities: []int{1, 2},
+	})
+	require.Nil(t, err)
+	heap := NewFullSkewHeap[string, int](nil, lt, HeapConfig{})
+	heap.Push("kept", 1)
+	assert.ErrorIs(t, heap.Restore(duplicate), ErrDuplicateID)
+	assert.Equal(t, 1, heap.Length())
+
+	var uninitialized FullLeftistHeap[string, int]
+	assert.ErrorIs(t, uninitialized.Restore(duplicate), ErrUninitializedHeap)
+}
+
+func TestSnapshot_Invalid(t *testing.T) {
+	heap := NewBinaryHeap[string, int](nil, lt, false)
+	heap.Push("kept", 1)
+
+	assert.NotNil(t, heap.Restore([]byte("not a snapshot")))
+
+	mismatched, err := encodeSnapshot(heapSnapshot[string, int]{Values: []string{"a"}})
+	require.Nil(t, err)
+	assert.ErrorIs(t, heap.Restore(mismatched), ErrInvalidSnapshot)
+
+	var future bytes.Buffer
+	require.Nil(t, gob.NewEncoder(&future).Encode(heapSnapshot[string, int]{Version: snapshotVersion + 1}))
+	assert.ErrorIs(t, heap.Restore(future.Bytes()), ErrInvalidSnapshot)
+
+	assert.Equal(t, 1, heap.Length())
+}
+
+func TestSnapshot_SmallerThanJSON(t *testing.T) {
+	heap := NewBinaryHeap[int, int](nil, lt, false)
+	for i := 0; i < 10_000; i++ {
+		heap.Push(i, i)
+	}
+
+	binary, err := heap.Snapshot()
+	require.Nil(t, err)
+	text, err := json.Marshal(heap)
+	require.Nil(t, err)
+	assert.Less(t, 2*len(binary), len(text))
+}
+
+func BenchmarkSnapshotRestore(b *testing.B) {
+	heap := NewBinaryHeap[int, int](nil, lt, false)
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 100_000; i++ {
+		v := r.Intn(10_000)
+		heap.Push(v, v)
+	}
+
+	b.Run("Snapshot", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			heap.Snapshot()
+		}
+	})
+
+	data, _ := heap.Snapshot()
+	b.Run("Restore", func(b *testing.B) {
+		restored := NewBinaryHeap[int, int](nil, lt, false)
+		for i := 0; i < b.N; i++ {
+			restored.Restore(data)
+		}
+	})
+}
@@ -0,0 +1,393 @@
+package heapcraft
+
+// DefaultSoftHeapEpsilon is the error rate used by NewSoftHeap when the given
+// epsilon is not in the range (0, 1).
+const DefaultSoftHeapEpsilon = 0.1
+
+// softNode is a node of one of the binary trees of a SoftHeap. It holds a list
+// of elements that all share the node's key: every element's own priority is
+// the key or comes before it, and an element whose priority comes strictly
+// before the key is corrupted.
+type softNode[V any, P any] struct {
+	key   P
+	items []HeapNode[V, P]
+	rank  int
+	// target is the number of elements sift tries to keep in the node.
+	target int
+	left   *softNode[V, P]
+	right  *softNode[V, P]
+}
+
+// leaf reports whether the node has no children.
+func (n *softNode[V, P]) leaf() bool { return n.left == nil && n.right == nil }
+
+// SoftHeap is a soft heap in the simplified form of Kaplan, Tarjan and Zwick.
+// Elements are grouped into lists that share a single key, so that Push runs
+// in O(log 1/ε) amortized time and most pops take an element from an already
+// filled list, at the cost of corrupting some priorities: at any moment at most ε·n of the elements in the heap are
+// corrupted, where n is the number of elements ever pushed. A corrupted
+// element is treated as if its priority were its list's later key, so Pop may
+// return elements slightly out of order. Pop and Peek always return each
+// element's original priority.
+//
+// Soft heaps suit approximate selection, such as picking an element near a
+// given percentile, and algorithms such as minimum spanning trees that
+// tolerate a bounded number of out-of-order removals. With an epsilon small
+// enough that no list ever holds more than one element, the heap is exact.
+type SoftHeap[V any, P any] struct {
+	// trees holds the roots of the heap's trees in strictly decreasing rank
+	// order, so that pushes and the carries they cause work on the end of the
+	// slice.
+	trees []*softNode[V, P]
+	// best[i] is the index of the tree with the best key among trees[:i+1].
+	best      []int
+	cmp       func(a, b P) bool
+	epsilon   float64
+	threshold int
+	size      int
+	stats     heapStats
+	events    listeners[HeapEvent[V, P]]
+}
+
+// Epsilon returns the error rate of the heap.
+func (s *SoftHeap[V, P]) Epsilon() float64 { return s.epsilon }
+
+// target returns the number of elements a node of the given rank is filled
+// to, given the target of a node of the rank below.
+func (s *SoftHeap[V, P]) target(rank int, below int) int {
+	if rank <= s.threshold {
+		return 1
+	}
+	return (3*below + 1) / 2
+}
+
+// sift refills node from its children until it holds at least its target
+// number of elements or has become a leaf. Elements are always taken from the
+// child with the better key, whose key the node takes over; an emptied child
+// is refilled in turn or removed once it is an empty leaf.
+func (s *SoftHeap[V, P]) sift(node *softNode[V, P]) {
+	for len(node.items) < node.target && !node.leaf() {
+		if node.left == nil || (node.right != nil && s.cmp(node.right.key, node.left.key)) {
+			node.left, node.right = node.right, node.left
+		}
+		child := node.left
+		node.items = append(node.items, child.items...)
+		node.key = child.key
+		clear(child.items)
+		child.items = child.items[:0]
+		s.sift(child)
+		if len(child.items) == 0 {
+			node.left = nil
+		}
+	}
+}
+
+// combine links two trees of equal rank under a new, empty root of the next
+// rank and fills it from them.
+func (s *SoftHeap[V, P]) combine(x, y *softNode[V, P]) *softNode[V, P] {
+	node := &softNode[V, P]{
+		rank:  x.rank + 1,
+		left:  x,
+		right: y,
+	}
+	s.stats.rebalance()
+	node.target = s.target(node.rank, x.target)
+	s.sift(node)
+	return node
+}
+
+// updateBest recomputes best for every tree from index i onwards.
+func (s *SoftHeap[V, P]) updateBest(i int) {
+	s.best = s.best[:len(s.trees)]
+	for ; i < len(s.trees); i++ {
+		if i == 0 || s.cmp(s.trees[i].key, s.trees[s.best[i-1]].key) {
+			s.best[i] = i
+		} else {
+			s.best[i] = s.best[i-1]
+		}
+	}
+}
+
+// Push adds an element with the given priority to the heap. It runs in
+// O(log 1/ε) amortized time.
+func (s *SoftHeap[V, P]) Push(value V, priority P) {
+	node := &softNode[V, P]{
+		key:    priority,
+		items:  []HeapNode[V, P]{{value: value, priority: priority}},
+		target: 1,
+	}
+	for n := len(s.trees); n > 0 && s.trees[n-1].rank == node.rank; n-- {
+		node = s.combine(s.trees[n-1], node)
+		s.trees = s.trees[:n-1]
+	}
+	s.trees = append(s.trees, node)
+	s.best = append(s.best, 0)
+	s.updateBest(len(s.trees) - 1)
+	s.size++
+	s.stats.record(OpPush, 1, s.size)
+	emitHeapEvent(s.events, EventPush, "", value, priority)
+}
+
+// Meld moves every element of other into this heap in O(log n) time, linking
+// trees of equal rank like the digits of a binary sum. The other heap is left
+// empty. Both heaps are expected to share the same comparison function and
+// epsilon.
+func (s *SoftHeap[V, P]) Meld(other *SoftHeap[V, P]) {
+	if other == nil || other == s {
+		return
+	}
+	if len(s.events) > 0 {
+		other.forEach(func(v V, p P) { emitHeapEvent(s.events, EventPush, "", v, p) })
+	}
+
+	// Both root lists are walked from their lowest rank upwards together
+	// with the tree carried from the rank below, and the result is built in
+	// increasing rank order before it is reversed. Of the up to three trees
+	// of the lowest remaining rank, one is kept if their number is odd and
+	// the other two are combined into the carry of the next rank.
+	merged := make([]*softNode[V, P], 0, len(s.trees)+len(other.trees))
+	i, j := len(s.trees)-1, len(other.trees)-1
+	var carry *softNode[V, P]
+	for i >= 0 || j >= 0 || carry != nil {
+		rank := -1
+		if carry != nil {
+			rank = carry.rank
+		}
+		if i >= 0 && (rank < 0 || s.trees[i].rank < rank) {
+			rank = s.trees[i].rank
+		}
+		if j >= 0 && (rank < 0 || other.trees[j].rank < rank) {
+			rank = other.trees[j].rank
+		}
+
+		same := make([]*softNode[V, P], 0, 3)
+		if carry != nil && carry.rank == rank {
+			same, carry = append(same, carry), nil
+		}
+		if i >= 0 && s.trees[i].rank == rank {
+			same, i = append(same, s.trees[i]), i-1
+		}
+		if j >= 0 && other.trees[j].rank == rank {
+			same, j = append(same, other.trees[j]), j-1
+		}
+		if len(same)%2 == 1 {
+			merged = append(merged, same[0])
+			same = same[1:]
+		}
+		if len(same) == 2 {
+			carry = s.combine(same[0], same[1])
+		}
+	}
+	for l, r := 0, len(merged)-1; l < r; l, r = l+1, r-1 {
+		merged[l], merged[r] = merged[r], merged[l]
+	}
+
+	s.trees = merged
+	s.best = make([]int, len(merged))
+	s.updateBest(0)
+	s.size += other.size
+	s.stats.record(OpMeld, other.size, s.size)
+	other.Clear()
+}
+
+// pop is an internal method that removes an element from the list of the tree
+// with the best key. The tree is refilled from its children once its list is
+// empty, and removed once it has no elements left.
+func (s *SoftHeap[V, P]) pop() (V, P, error) {
+	if s.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+
+	i := s.best[len(s.trees)-1]
+	tree := s.trees[i]
+	last := len(tree.items) - 1
+	item := tree.items[last]
+	tree.items[last] = HeapNode[V, P]{}
+	tree.items = tree.items[:last]
+	if len(tree.items) == 0 {
+		s.sift(tree)
+		if len(tree.items) == 0 {
+			s.trees = append(s.trees[:i], s.trees[i+1:]...)
+		}
+	}
+	s.updateBest(i)
+	s.size--
+	s.stats.record(OpPop, 1, s.size)
+	emitHeapEvent(s.events, EventPop, "", item.value, item.priority)
+	return item.value, item.priority, nil
+}
+
+// peek is an internal method that returns the element Pop would remove
+// next without removing it.
+func (s *SoftHeap[V, P]) peek() (V, P, error) {
+	if s.size == 0 {
+		v, p := zeroValuePair[V, P]()
+		return v, p, ErrHeapEmpty
+	}
+	tree := s.trees[s.best[len(s.trees)-1]]
+	item := tree.items[len(tree.items)-1]
+	return item.value, item.priority, nil
+}
+
+// Pop removes and returns an element whose key is the best in the heap,
+// together with its original priority. Because of corruption the element may
+// not have the best original priority. Returns zero values and an error if the
+// heap is empty.
+func (s *SoftHeap[V, P]) Pop() (V, P, error) { return s.pop() }
+
+// PopValue removes an element as Pop does and returns its value. Returns zero
+// value and an error if the heap is empty.
+func (s *SoftHeap[V, P]) PopValue() (V, error) { return valueFromNode(s.pop()) }
+
+// PopPriority removes an element as Pop does and returns its original
+// priority. Returns zero value and an error if the heap is empty.
+func (s *SoftHeap[V, P]) PopPriority() (P, error) { return priorityFromNode(s.pop()) }
+
+// Peek returns the element Pop would remove next, without removing it.
+// Returns zero values and an error if the heap is empty.
+func (s *SoftHeap[V, P]) Peek() (V, P, error) { return s.peek() }
+
+// PeekValue returns the value of the element Pop would remove next. Returns
+// zero value and an error if the heap is empty.
+func (s *SoftHeap[V, P]) PeekValue() (V, error) { return valueFromNode(s.peek()) }
+
+// PeekPriority returns the original priority of the element Pop would remove
+// next. Returns zero value and an error if the heap is empty.
+func (s *SoftHeap[V, P]) PeekPriority() (P, error) { return priorityFromNode(s.peek()) }
+
+// Drain removes all elements from the heap and returns them in the order Pop
+// would, which is only approximately sorted. The heap is empty afterwards.
+func (s *SoftHeap[V, P]) Drain() []HeapNode[V, P] {
+	return drainNodes(s.Length(), s.pop)
+}
+
+// DrainValues removes all elements from the heap and returns their values in
+// the order Pop would. The heap is empty afterwards.
+func (s *SoftHeap[V, P]) DrainValues() []V {
+	return drainValues(s.Length(), s.pop)
+}
+
+// DrainPriorities removes all elements from the heap and returns their
+// original priorities in the order Pop would. The heap is empty afterwards.
+func (s *SoftHeap[V, P]) DrainPriorities() []P {
+	return drainPriorities(s.Length(), s.pop)
+}
+
+// forEach calls fn for every element in the heap, in no particular order.
+func (s *SoftHeap[V, P]) forEach(fn func(v V, p P)) {
+	stack := append([]*softNode[V, P](nil), s.trees...)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, item := range node.items {
+			fn(item.value, item.priority)
+		}
+		if node.left != nil {
+			stack = append(stack, node.left)
+		}
+		if node.right != nil {
+			stack = append(stack, node.right)
+		}
+	}
+}
+
+// Export returns a copy of the elements in the heap that pass the optional
+// filter, ordered best-first or worst-first by their original priorities and
+// truncated to the optional limit. The heap itself is not modified.
+func (s *SoftHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	return exportNodes(s.Length(), s.forEach, s.cmp, opts)
+}
+
+// Clear removes all elements from the heap.
+func (s *SoftHeap[V, P]) Clear() {
+	cleared := s.Length()
+	s.trees = nil
+	s.best = nil
+	s.size = 0
+	s.stats.record(OpClear, cleared, s.size)
+	emitClearEvent(s.events)
+}
+
+// Stats returns the operations the heap has performed since it was created.
+// Rebalances counts the trees linked under a new root by Push and Meld.
+func (s *SoftHeap[V, P]) Stats() HeapStats { return s.stats.counts }
+
+// Instrument sets hook to be called after every operation that changes the
+// number of elements in the heap, replacing any previous hook. A nil hook
+// removes it.
+func (s *SoftHeap[V, P]) Instrument(hook Instrumentation) { s.stats.hook = hook }
+
+// OnEvent registers fn to be called for every element pushed or popped,
+// including each element melded in from another heap, and whenever the heap is
+// cleared. Events carry each element's original priority. Returns an ID that
+// can be passed to RemoveListener.
+func (s *SoftHeap[V, P]) OnEvent(fn func(HeapEvent[V, P])) string { return s.events.add(fn) }
+
+// RemoveListener removes the event listener with the specified ID. Returns an
+// error if no listener exists with the given ID.
+func (s *SoftHeap[V, P]) RemoveListener(id string) error { return s.events.deregister(id) }
+
+// Length returns the number of elements in the heap.
+func (s *SoftHeap[V, P]) Length() int { return s.size }
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SoftHeap[V, P]) IsEmpty() bool { return s.size == 0 }
+
+// Verify checks the internal consistency of the heap: the trees must be in
+// strictly decreasing rank order with the best key of every prefix recorded,
+// every node must hold elements none of which comes after its key, no child's
+// key may come before its parent's, and the trees must hold exactly Length
+// elements. It is intended for tests and debugging, runs in O(n) and returns
+// an error wrapping ErrInvariantViolated for the first violation.
+func (s *SoftHeap[V, P]) Verify() error {
+	if len(s.best) != len(s.trees) {
+		return invariantError("the best-key index has %d entries for %d trees", len(s.best), len(s.trees))
+	}
+	for i, tree := range s.trees {
+		if i > 0 && tree.rank >= s.trees[i-1].rank {
+			return invariantError("tree %d has rank %d after rank %d", i, tree.rank, s.trees[i-1].rank)
+		}
+		b := s.best[i]
+		if b > i || s.cmp(tree.key, s.trees[b].key) || (i > 0 && s.cmp(s.trees[s.best[i-1]].key, s.trees[b].key)) {
+			return invariantError("the best-key index of tree %d is out of date", i)
+		}
+	}
+
+	_, err := verifyTree(s.trees,
+		func(node *softNode[V, P], visit func(*softNode[V, P])) {
+			if node.left != nil {
+				visit(node.left)
+			}
+			if node.right != nil {
+				visit(node.right)
+			}
+		},
+		func(node, parent *softNode[V, P]) error {
+			if len(node.items) == 0 {
+				return invariantError("a node of rank %d holds no elements", node.rank)
+			}
+			for _, item := range node.items {
+				if s.cmp(node.key, item.priority) {
+					return invariantError("priority %v comes after its key %v", item.priority, node.key)
+				}
+			}
+			if parent == nil {
+				return nil
+			}
+			if node.rank >= parent.rank {
+				return invariantError("a node of rank %d has a child of rank %d", parent.rank, node.rank)
+			}
+			return verifyOrder(s.cmp, node.key, parent.key)
+		})
+	if err != nil {
+		return err
+	}
+
+	elements := 0
+	s.forEach(func(V, P) { elements++ })
+	if elements != s.size {
+		return invariantError("%d elements are reachable but the size is %d", elements, s.size)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package heapcraft
+
+import "math"
+
+// NewSoftHeap creates a soft heap from a slice of HeapNodes, using cmp to
+// determine heap order (min or max). epsilon is the error rate: at most
+// epsilon times the number of pushed elements are corrupted at any time.
+// Values of epsilon outside the range (0, 1) use DefaultSoftHeapEpsilon.
+func NewSoftHeap[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool, epsilon float64) *SoftHeap[V, P] {
+	if !(epsilon > 0 && epsilon < 1) {
+		epsilon = DefaultSoftHeapEpsilon
+	}
+	// Lists hold more than one element only above the threshold rank. There
+	// are at most n/2^k nodes of rank k and their lists grow by about 3/2 per
+	// rank, so the corrupted elements add up to a geometric series of about
+	// 12n/2^threshold, which a threshold of log2(12/ε) keeps below ε·n.
+	heap := &SoftHeap[V, P]{
+		cmp:       cmp,
+		epsilon:   epsilon,
+		threshold: int(math.Ceil(math.Log2(12 / epsilon))),
+	}
+	for i := range data {
+		heap.Push(data[i].value, data[i].priority)
+	}
+	return heap
+}
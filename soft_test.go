@@ -0,0 +1,180 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// softCorrupted returns the number of elements in h whose priority differs
+// from the key of the node holding them.
+func softCorrupted[V any](h *SoftHeap[V, int]) int {
+	corrupted := 0
+	stack := append([]*softNode[V, int](nil), h.trees...)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, item := range node.items {
+			if item.priority != node.key {
+				corrupted++
+			}
+		}
+		if node.left != nil {
+			stack = append(stack, node.left)
+		}
+		if node.right != nil {
+			stack = append(stack, node.right)
+		}
+	}
+	return corrupted
+}
+
+func TestSoftHeapExactWithSmallEpsilon(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	h := NewSoftHeap[int, int](nil, lt, 1e-4)
+	_, _, err := h.Pop()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	want := make([]int, 1000)
+	for i := range want {
+		want[i] = rng.Intn(10000)
+		h.Push(i, want[i])
+	}
+	require.NoError(t, h.Verify())
+	assert.Equal(t, 0, softCorrupted(h))
+
+	slices.Sort(want)
+	p, err := h.PeekPriority()
+	require.NoError(t, err)
+	assert.Equal(t, want[0], p)
+	assert.Equal(t, want, h.DrainPriorities())
+	assert.True(t, h.IsEmpty())
+}
+
+func TestSoftHeapCorruptionBound(t *testing.T) {
+	for _, epsilon := range []float64{0.5, 0.2, 0.05} {
+		rng := rand.New(rand.NewSource(int64(epsilon * 100)))
+		h := NewSoftHeap[int, int](nil, gt, epsilon)
+		assert.Equal(t, epsilon, h.Epsilon())
+
+		pushed, popped := 0, []int{}
+		var want []int
+		for i := 0; i < 5000; i++ {
+			if rng.Intn(3) > 0 || h.IsEmpty() {
+				p := rng.Intn(100000)
+				h.Push(i, p)
+				want = append(want, p)
+				pushed++
+			} else {
+				_, peeked, err := h.Peek()
+				require.NoError(t, err)
+				p, err := h.PopPriority()
+				require.NoError(t, err)
+				assert.Equal(t, peeked, p)
+				popped = append(popped, p)
+			}
+			require.LessOrEqual(t, float64(softCorrupted(h)), epsilon*float64(pushed), "epsilon=%v", epsilon)
+		}
+		require.NoError(t, h.Verify())
+		assert.Equal(t, len(want)-len(popped), h.Length())
+
+		got := append(popped, h.DrainPriorities()...)
+		slices.Sort(got)
+		slices.Sort(want)
+		assert.Equal(t, want, got, "epsilon=%v", epsilon)
+	}
+}
+
+func TestSoftHeapApproximateMedian(t *testing.T) {
+	const n, epsilon = 10000, 0.1
+	rng := rand.New(rand.NewSource(9))
+	data := make([]HeapNode[int, int], n)
+	for i, p := range rng.Perm(n) {
+		data[i] = CreateHeapNode(i, p)
+	}
+	h := NewSoftHeap(data, lt, epsilon)
+
+	median := -1
+	for i := 0; i < n/2; i++ {
+		p, err := h.PopPriority()
+		require.NoError(t, err)
+		median = max(median, p)
+	}
+	// The priorities are a permutation of 0..n-1, so each is its own rank.
+	assert.GreaterOrEqual(t, median, n/2-1)
+	assert.LessOrEqual(t, median, n/2+int(epsilon*n))
+}
+
+func TestSoftHeapMeldClear(t *testing.T) {
+	h1 := NewSoftHeap([]HeapNode[string, int]{
+		CreateHeapNode("c", 3),
+		CreateHeapNode("a", 1),
+		CreateHeapNode("e", 5),
+	}, lt, 0)
+	h2 := NewSoftHeap([]HeapNode[string, int]{
+		CreateHeapNode("b", 2),
+		CreateHeapNode("d", 4),
+	}, lt, 0)
+	assert.Equal(t, DefaultSoftHeapEpsilon, h1.Epsilon())
+
+	h1.Meld(h2)
+	h1.Meld(h1)
+	h1.Meld(nil)
+	require.NoError(t, h1.Verify())
+	assert.True(t, h2.IsEmpty())
+	assert.Equal(t, 5, h1.Length())
+
+	exported := h1.Export(ExportOptions[string, int]{})
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, nodePriorities(exported))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, h1.DrainValues())
+
+	h1.Push("z", 26)
+	h1.Clear()
+	assert.True(t, h1.IsEmpty())
+	require.NoError(t, h1.Verify())
+	_, err := h1.PeekValue()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestSoftHeapMeldAllSizes(t *testing.T) {
+	build := func(n, offset int) *SoftHeap[int, int] {
+		h := NewSoftHeap[int, int](nil, lt, 0)
+		for i := 0; i < n; i++ {
+			h.Push(offset+i, offset+i)
+		}
+		return h
+	}
+	for a := 0; a <= 12; a++ {
+		for b := 0; b <= 12; b++ {
+			h := build(a, 0)
+			h.Meld(build(b, 100))
+			require.NoError(t, h.Verify(), "%d+%d", a, b)
+			assert.Equal(t, a+b, h.Length(), "%d+%d", a, b)
+
+			for i := 0; i < 5; i++ {
+				h.Push(200+i, 200+i)
+				require.NoError(t, h.Verify(), "%d+%d, push %d", a, b, i)
+			}
+			priorities := h.DrainPriorities()
+			assert.Len(t, priorities, a+b+5, "%d+%d", a, b)
+		}
+	}
+}
+
+// -------------------------------- Soft Heap Benchmarks --------------------------------
+
+func BenchmarkSoftHeap_PushPop(b *testing.B) {
+	heap := NewSoftHeap[int, int](nil, lt, DefaultSoftHeapEpsilon)
+	insertions := generateRandomNumbersv1(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(insertions[i], insertions[i])
+	}
+	for i := 0; i < b.N; i++ {
+		heap.Pop()
+	}
+}
@@ -0,0 +1,50 @@
+package heapcraft
+
+import "slices"
+
+// sortArity is the arity of the heap used by SortSlice. A 4-ary heap does
+// fewer sift levels than a binary heap and keeps each node's children in the
+// same cache line for small elements.
+const sortArity = 4
+
+// heapsort sorts the elements of h in place into the order they would be
+// popped, assuming h already satisfies the heap property. It repeatedly moves
+// the root past the end of a shrinking heap and sifts down the element that
+// replaced it, which leaves the data in reverse pop order, and then reverses
+// it. The backing array of h.data holds the result.
+func heapsort[V any, P any](h *DaryHeap[V, P]) {
+	data := h.data
+	for end := len(data) - 1; end > 0; end-- {
+		data[0], data[end] = data[end], data[0]
+		h.data = data[:end]
+		h.siftDown(0)
+	}
+	h.data = data
+	slices.Reverse(data)
+}
+
+// SortSlice sorts data in place into the order the elements would be popped
+// from a heap ordered by cmp, using a d-ary heapsort. It runs in O(n log n)
+// time without allocating. Like any heapsort it is not stable: elements with
+// equal priorities may end up in any order.
+func SortSlice[V any, P any](data []HeapNode[V, P], cmp func(a, b P) bool) {
+	heapsort(NewDaryHeapWithConfig(sortArity, data, cmp, DaryHeapConfig{}))
+}
+
+// Sorted returns the elements of heap in the order they would be popped,
+// leaving the heap unchanged. The elements are copied once into the returned
+// slice, which is sorted in place starting from the heap order it already
+// has, so no intermediate heap is built. Elements of a stable heap with equal
+// priorities come out in insertion order.
+func Sorted[V any, P any](heap *DaryHeap[V, P]) []HeapNode[V, P] {
+	data := make([]HeapNode[V, P], heap.Length())
+	copy(data, heap.data)
+	heapsort(&DaryHeap[V, P]{
+		data:   data,
+		cmp:    heap.cmp,
+		d:      heap.d,
+		blocks: heap.blocks,
+		stable: heap.stable,
+	})
+	return data
+}
@@ -0,0 +1,59 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nodePriorities returns the priorities of nodes in slice order.
+func nodePriorities[V any, P any](nodes []HeapNode[V, P]) []P {
+	priorities := make([]P, len(nodes))
+	for i, node := range nodes {
+		priorities[i] = node.priority
+	}
+	return priorities
+}
+
+func TestSortSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 5, 100, 1000} {
+		data := randomNodes(r, n, 50)
+		want := nodePriorities(data)
+		slices.Sort(want)
+
+		SortSlice(data, lt)
+		assert.Equal(t, want, nodePriorities(data))
+
+		SortSlice(data, gt)
+		slices.Reverse(want)
+		assert.Equal(t, want, nodePriorities(data))
+	}
+}
+
+func TestSorted(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	heap := NewDaryHeap(3, randomNodes(r, 200, 1000), lt, false)
+	before := slices.Clone(heap.data)
+
+	sorted := Sorted(heap)
+	assert.Equal(t, before, heap.data)
+	assert.Equal(t, heap.DrainPriorities(), nodePriorities(sorted))
+}
+
+func TestSorted_Stable(t *testing.T) {
+	heap := NewStableBinaryHeap[string, int](nil, lt, DaryHeapConfig{})
+	for _, v := range []string{"a", "b", "c", "d", "e", "f"} {
+		heap.Push(v, len(v)%2)
+	}
+	heap.Push("first", 0)
+
+	values := make([]string, 0, heap.Length())
+	for _, node := range Sorted(heap) {
+		values = append(values, node.value)
+	}
+	assert.Equal(t, heap.DrainValues(), values)
+	assert.Empty(t, Sorted(heap))
+}
@@ -0,0 +1,104 @@
+package heapcraft
+
+// HeapOp identifies an operation reported to an Instrumentation hook.
+type HeapOp int
+
+const (
+	// OpPush reports elements added to the heap.
+	OpPush HeapOp = iota
+	// OpPop reports elements removed from the top of the heap.
+	OpPop
+	// OpRemove reports elements removed by ID or index.
+	OpRemove
+	// OpMeld reports the elements of another heap melded or merged in.
+	OpMeld
+	// OpClear reports the heap being emptied by Clear.
+	OpClear
+)
+
+// String returns the lower-case name of the operation, suitable as a metric
+// label.
+func (op HeapOp) String() string {
+	switch op {
+	case OpPush:
+		return "push"
+	case OpPop:
+		return "pop"
+	case OpRemove:
+		return "remove"
+	case OpMeld:
+		return "meld"
+	case OpClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// HeapStats counts the operations a heap has performed since it was created.
+// Pushes, Pops and Removes count elements, so a PushAll of ten elements adds
+// ten pushes, while Melds counts calls. Swaps counts the element moves made
+// while sifting in d-ary and interval heaps. Rebalances counts structural
+// repairs: trees linked together in pairing, binomial, skew binomial and soft
+// heaps, merge steps in leftist and skew heaps, bucket redistributions in
+// radix heaps, whole re-heapifications in d-ary heaps and switches between
+// representations in AdaptiveHeap. Size is
+// the current number of elements and MaxSize the largest it has been.
+type HeapStats struct {
+	Pushes     uint64
+	Pops       uint64
+	Removes    uint64
+	Melds      uint64
+	Swaps      uint64
+	Rebalances uint64
+	MaxSize    int
+	Size       int
+}
+
+// Instrumentation receives a call after every operation that changes the
+// number of elements in a heap, so that metrics can be exported without
+// wrapping every method. n is the number of elements the operation added or
+// removed and size is the length of the heap afterwards. Observe runs while a
+// thread-safe heap is locked and must not call back into the heap.
+type Instrumentation interface {
+	Observe(op HeapOp, n int, size int)
+}
+
+// InstrumentationFunc adapts an ordinary function to the Instrumentation
+// interface.
+type InstrumentationFunc func(op HeapOp, n int, size int)
+
+// Observe calls f(op, n, size).
+func (f InstrumentationFunc) Observe(op HeapOp, n int, size int) { f(op, n, size) }
+
+// heapStats keeps the counters behind a heap's Stats method together with its
+// optional Instrumentation hook.
+type heapStats struct {
+	counts HeapStats
+	hook   Instrumentation
+}
+
+// record counts an operation that added or removed n elements and left the
+// heap with size elements, and reports it to the hook if one is set.
+func (s *heapStats) record(op HeapOp, n int, size int) {
+	switch op {
+	case OpPush:
+		s.counts.Pushes += uint64(n)
+	case OpPop:
+		s.counts.Pops += uint64(n)
+	case OpRemove:
+		s.counts.Removes += uint64(n)
+	case OpMeld:
+		s.counts.Melds++
+	}
+	s.counts.Size = size
+	s.counts.MaxSize = max(s.counts.MaxSize, size)
+	if s.hook != nil {
+		s.hook.Observe(op, n, size)
+	}
+}
+
+// swap counts an element moved while sifting.
+func (s *heapStats) swap() { s.counts.Swaps++ }
+
+// rebalance counts a structural repair.
+func (s *heapStats) rebalance() { s.counts.Rebalances++ }
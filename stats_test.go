@@ -0,0 +1,223 @@
+package heapcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsHeap is a heap that counts its operations.
+type statsHeap interface {
+	Heap[int, int]
+	StatsReporter
+}
+
+type observation struct {
+	op   HeapOp
+	n    int
+	size int
+}
+
+func TestHeap_Stats(t *testing.T) {
+	heaps := map[string]func() statsHeap{
+		"dary":         func() statsHeap { return NewDaryHeap[int, int](3, nil, lt, false) },
+		"syncDary":     func() statsHeap { return NewSyncDaryHeap[int, int](3, nil, lt, true) },
+		"pairing":      func() statsHeap { return NewPairingHeap[int, int](nil, lt, false) },
+		"syncPairing":  func() statsHeap { return NewSyncPairingHeap[int, int](nil, lt, true) },
+		"leftist":      func() statsHeap { return NewLeftistHeap[int, int](nil, lt, false) },
+		"syncLeftist":  func() statsHeap { return NewSyncLeftistHeap[int, int](nil, lt, true) },
+		"skew":         func() statsHeap { return NewSkewHeap[int, int](nil, lt, false) },
+		"syncSkew":     func() statsHeap { return NewSyncSkewHeap[int, int](nil, lt, true) },
+		"binomial":     func() statsHeap { return NewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncBinomial": func() statsHeap { return NewSyncBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"skewBinomial": func() statsHeap { return NewSkewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncSkewBinomial": func() statsHeap {
+			return NewSyncSkewBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true})
+		},
+		"soft":     func() statsHeap { return NewSoftHeap[int, int](nil, lt, 0.5) },
+		"adaptive": func() statsHeap { return NewAdaptiveHeap[int, int](nil, lt, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		var observed []observation
+		heap.Instrument(InstrumentationFunc(func(op HeapOp, n int, size int) {
+			observed = append(observed, observation{op, n, size})
+		}))
+
+		for i := 20; i > 0; i-- {
+			heap.Push(i, i)
+		}
+		for i := 0; i < 5; i++ {
+			heap.Pop()
+		}
+		heap.Instrument(nil)
+		heap.Pop()
+
+		stats := heap.Stats()
+		assert.Equal(t, uint64(20), stats.Pushes, name)
+		assert.Equal(t, uint64(6), stats.Pops, name)
+		assert.Equal(t, 14, stats.Size, name)
+		assert.Equal(t, 20, stats.MaxSize, name)
+		assert.NotZero(t, stats.Swaps+stats.Rebalances, name)
+
+		require.Len(t, observed, 25, name)
+		assert.Equal(t, observation{OpPush, 1, 1}, observed[0], name)
+		assert.Equal(t, observation{OpPush, 1, 20}, observed[19], name)
+		assert.Equal(t, observation{OpPop, 1, 15}, observed[24], name)
+
+		heap.Clear()
+		stats = heap.Stats()
+		assert.Equal(t, 0, stats.Size, name)
+		assert.Equal(t, 20, stats.MaxSize, name)
+	}
+}
+
+func TestHeap_StatsPopPushAndPushPop(t *testing.T) {
+	type replaceHeap interface {
+		statsHeap
+		PopPush(value int, priority int) (int, int)
+		PushPop(value int, priority int) (int, int)
+	}
+	config := HeapConfig{}
+	heaps := map[string]replaceHeap{
+		"dary":        NewDaryHeapWithConfig[int, int](3, nil, lt, DaryHeapConfig{}),
+		"syncDary":    NewSyncDaryHeapWithConfig[int, int](3, nil, lt, DaryHeapConfig{}),
+		"pairing":     NewSimplePairingHeap[int, int](nil, lt, config),
+		"syncPairing": NewSyncSimplePairingHeap[int, int](nil, lt, config),
+		"leftist":     NewSimpleLeftistHeap[int, int](nil, lt, config),
+		"syncLeftist": NewSyncSimpleLeftistHeap[int, int](nil, lt, config),
+		"skew":        NewSimpleSkewHeap[int, int](nil, lt, config),
+		"syncSkew":    NewSyncSimpleSkewHeap[int, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		heap.PopPush(0, 0)
+		heap.Push(1, 1)
+		heap.Push(2, 2)
+		var observed []observation
+		heap.Instrument(InstrumentationFunc(func(op HeapOp, n int, size int) {
+			observed = append(observed, observation{op, n, size})
+		}))
+
+		_, p := heap.PopPush(3, 3)
+		assert.Equal(t, 1, p, name)
+		_, p = heap.PushPop(4, 4)
+		assert.Equal(t, 2, p, name)
+		_, p = heap.PushPop(0, 0)
+		assert.Equal(t, 0, p, name)
+
+		assert.Equal(t, []observation{
+			{OpPop, 1, 2}, {OpPush, 1, 2},
+			{OpPush, 1, 2}, {OpPop, 1, 2},
+		}, observed, name)
+		stats := heap.Stats()
+		assert.Equal(t, uint64(4), stats.Pushes, name)
+		assert.Equal(t, uint64(2), stats.Pops, name)
+		assert.Equal(t, 2, stats.Size, name)
+		assert.Equal(t, 2, stats.MaxSize, name)
+	}
+}
+
+func TestHeap_StatsMeldAndPushAll(t *testing.T) {
+	h := NewPairingHeap[int, int](nil, lt, false)
+	other := NewPairingHeap([]HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}, lt, false)
+	var ops []string
+	h.Instrument(InstrumentationFunc(func(op HeapOp, n int, size int) {
+		ops = append(ops, op.String())
+	}))
+
+	h.PushAll([]HeapNode[int, int]{CreateHeapNode(3, 3), CreateHeapNode(4, 4), CreateHeapNode(5, 5)})
+	h.Meld(other)
+	h.Clear()
+	assert.Equal(t, []string{"push", "meld", "clear"}, ops)
+
+	stats := h.Stats()
+	assert.Equal(t, uint64(3), stats.Pushes)
+	assert.Equal(t, uint64(1), stats.Melds)
+	assert.Equal(t, 5, stats.MaxSize)
+	assert.Equal(t, 0, stats.Size)
+
+	r := NewRadixHeap[int, uint](nil, false)
+	require.NoError(t, r.Push(1, 1))
+	require.NoError(t, r.Push(2, 9))
+	_, _, err := r.Pop()
+	require.NoError(t, err)
+	_, _, err = r.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), r.Stats().Rebalances)
+}
+
+func TestHeap_StatsIDAndRangeHeaps(t *testing.T) {
+	var observed []observation
+	record := InstrumentationFunc(func(op HeapOp, n int, size int) {
+		observed = append(observed, observation{op, n, size})
+	})
+
+	indexed := NewIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	indexed.Instrument(record)
+	id, err := indexed.Push(1, 1)
+	require.NoError(t, err)
+	_, err = indexed.Push(2, 2)
+	require.NoError(t, err)
+	_, _, err = indexed.RemoveByID(id)
+	require.NoError(t, err)
+	assert.Equal(t, HeapStats{Pushes: 2, Removes: 1, MaxSize: 2, Size: 1}, indexed.Stats())
+
+	keyed := NewSyncKeyedHeap[string, int, int](2, lt, DaryHeapConfig{})
+	keyed.Instrument(record)
+	keyed.Push("a", 1, 1)
+	keyed.Push("a", 1, 0)
+	_, _, err = keyed.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), keyed.Stats().Pushes)
+	assert.Equal(t, uint64(1), keyed.Stats().Pops)
+
+	radix := NewMultiLevelRadixHeap[int, uint](nil, 4, RadixHeapConfig{})
+	radix.Instrument(record)
+	require.NoError(t, radix.Push(1, 1))
+	require.NoError(t, radix.Push(2, 300))
+	require.NoError(t, radix.Push(3, 300))
+	_, _, err = radix.Pop()
+	require.NoError(t, err)
+	batch, err := radix.PopEqual()
+	require.NoError(t, err)
+	assert.Len(t, batch, 2)
+	assert.Equal(t, uint64(3), radix.Stats().Pops)
+	assert.NotZero(t, radix.Stats().Rebalances)
+
+	intervals, err := NewIntervalHeap[int, int](nil, HeapConfig{})
+	require.NoError(t, err)
+	intervals.Instrument(record)
+	require.NoError(t, intervals.Push(1, 5, 9))
+	require.NoError(t, intervals.Push(2, 1, 3))
+	_, err = intervals.Pop()
+	require.NoError(t, err)
+	intervals.Clear()
+	assert.Equal(t, HeapStats{Pushes: 2, Pops: 1, Swaps: 1, MaxSize: 2}, intervals.Stats())
+
+	assert.Equal(t, []observation{
+		{OpPush, 1, 1}, {OpPush, 1, 2}, {OpRemove, 1, 1},
+		{OpPush, 1, 1}, {OpPop, 1, 0},
+		{OpPush, 1, 1}, {OpPush, 1, 2}, {OpPush, 1, 3}, {OpPop, 1, 2}, {OpPop, 2, 0},
+		{OpPush, 1, 1}, {OpPush, 1, 2}, {OpPop, 1, 1}, {OpClear, 1, 0},
+	}, observed)
+}
+
+func TestSkewBinomialHeap_StatsMeld(t *testing.T) {
+	h := NewSkewBinomialHeap([]HeapNode[int, int]{CreateHeapNode(3, 3)}, lt, HeapConfig{})
+	other := NewSkewBinomialHeap([]HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}, lt, HeapConfig{})
+	var ops []string
+	h.Instrument(InstrumentationFunc(func(op HeapOp, n int, size int) {
+		ops = append(ops, op.String())
+	}))
+
+	h.Meld(other)
+	h.Clear()
+	assert.Equal(t, []string{"meld", "clear"}, ops)
+	stats := h.Stats()
+	assert.Equal(t, uint64(1), stats.Melds)
+	assert.Equal(t, 3, stats.MaxSize)
+	assert.NotZero(t, stats.Rebalances)
+}
@@ -0,0 +1,192 @@
+package heapcraft
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+)
+
+// frontier is a minimal binary heap of nodes keyed by K, used by
+// orderedNodes. It is separate from DaryHeap so that DaryHeap itself can be
+// iterated without instantiating DaryHeap recursively.
+type frontier[N any, K any] struct {
+	nodes []N
+	keys  []K
+	cmp   func(a, b K) bool
+}
+
+// push adds a node to the frontier and sifts it up.
+func (f *frontier[N, K]) push(node N, key K) {
+	f.nodes = append(f.nodes, node)
+	f.keys = append(f.keys, key)
+	for i := len(f.nodes) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !f.cmp(f.keys[i], f.keys[parent]) {
+			break
+		}
+		f.swap(i, parent)
+		i = parent
+	}
+}
+
+// pop removes and returns the best node in the frontier, which must not be
+// empty.
+func (f *frontier[N, K]) pop() N {
+	top := f.nodes[0]
+	last := len(f.nodes) - 1
+	f.swap(0, last)
+	var empty N
+	f.nodes[last] = empty
+	f.nodes, f.keys = f.nodes[:last], f.keys[:last]
+	for i := 0; ; {
+		best, left := i, 2*i+1
+		for c := left; c < left+2 && c < last; c++ {
+			if f.cmp(f.keys[c], f.keys[best]) {
+				best = c
+			}
+		}
+		if best == i {
+			break
+		}
+		f.swap(i, best)
+		i = best
+	}
+	return top
+}
+
+// swap exchanges the nodes and keys at indices i and j.
+func (f *frontier[N, K]) swap(i, j int) {
+	f.nodes[i], f.nodes[j] = f.nodes[j], f.nodes[i]
+	f.keys[i], f.keys[j] = f.keys[j], f.keys[i]
+}
+
+// orderedNodes returns an iterator over the nodes of a heap in the order they
+// would be popped, without modifying the heap. It keeps a frontier heap of
+// candidates that starts with the roots: each step pops the best candidate
+// and pushes its children, which can only come after it. The frontier never
+// holds more than the children of the nodes yielded so far, which makes a
+// prefix of a large heap cheap. key extracts what cmp compares, and children
+// calls visit for every child of a node.
+func orderedNodes[N any, K any](cmp func(a, b K) bool, roots []N, key func(N) K, children func(node N, visit func(N))) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		candidates := &frontier[N, K]{cmp: cmp}
+		for _, root := range roots {
+			candidates.push(root, key(root))
+		}
+		push := func(child N) { candidates.push(child, key(child)) }
+		for len(candidates.nodes) > 0 {
+			node := candidates.pop()
+			if !yield(node) {
+				return
+			}
+			children(node, push)
+		}
+	}
+}
+
+// orderedPairs adapts an iterator over heap nodes into an iterator over their
+// values and priorities.
+func orderedPairs[N any, V any, P any](nodes iter.Seq[N], fields func(N) (V, P)) iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		for node := range nodes {
+			if !yield(fields(node)) {
+				return
+			}
+		}
+	}
+}
+
+// streamNodes writes the nodes produced by the iterator to w as a JSON array,
+// encoding each one as it is reached so the array is never held in memory.
+func streamNodes[N any, T any](w io.Writer, nodes iter.Seq[N], encode func(N) T) error {
+	buf := bufio.NewWriter(w)
+	if err := buf.WriteByte('['); err != nil {
+		return err
+	}
+	first := true
+	for node := range nodes {
+		data, err := json.Marshal(encode(node))
+		if err != nil {
+			return err
+		}
+		if !first {
+			if err := buf.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := buf.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := buf.WriteByte(']'); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// decodeNodes returns an iterator over the nodes encoded in r, either as a
+// JSON array or as a sequence of JSON objects, decoding one node per step. It
+// stops at the first error, which it stores in err.
+func decodeNodes[V any, P any](r io.Reader, err *error) iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		buf := bufio.NewReader(r)
+		array, e := startsArray(buf)
+		if e != nil {
+			*err = e
+			return
+		}
+		dec := json.NewDecoder(buf)
+		if array {
+			if _, e := dec.Token(); e != nil {
+				*err = e
+				return
+			}
+		}
+		for dec.More() {
+			var node HeapNode[V, P]
+			if e := dec.Decode(&node); e != nil {
+				*err = e
+				return
+			}
+			if !yield(node.value, node.priority) {
+				return
+			}
+		}
+		if array {
+			if _, e := dec.Token(); e != nil {
+				*err = e
+			}
+		}
+	}
+}
+
+// startsArray reports whether the first byte of buf after any whitespace
+// opens a JSON array, without consuming it. An empty input is not an error.
+func startsArray(buf *bufio.Reader) (bool, error) {
+	for {
+		b, err := buf.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b == '[', buf.UnreadByte()
+	}
+}
+
+// treeRoots returns a single-element root list for a tree heap, or nil if the
+// heap is empty.
+func treeRoots[N comparable](root N) []N {
+	var empty N
+	if root == empty {
+		return nil
+	}
+	return []N{root}
+}
@@ -0,0 +1,100 @@
+package heapcraft
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamHeap is a heap that can stream its elements as JSON.
+type streamHeap interface {
+	Heap[int, int]
+	StreamJSON(w io.Writer) error
+}
+
+func TestHeap_StreamJSON(t *testing.T) {
+	heaps := map[string]func() streamHeap{
+		"dary":         func() streamHeap { return NewDaryHeap[int, int](3, nil, lt, false) },
+		"syncDary":     func() streamHeap { return NewSyncDaryHeap[int, int](3, nil, lt, true) },
+		"pairing":      func() streamHeap { return NewPairingHeap[int, int](nil, lt, false) },
+		"syncPairing":  func() streamHeap { return NewSyncPairingHeap[int, int](nil, lt, true) },
+		"leftist":      func() streamHeap { return NewLeftistHeap[int, int](nil, lt, false) },
+		"syncLeftist":  func() streamHeap { return NewSyncLeftistHeap[int, int](nil, lt, true) },
+		"skew":         func() streamHeap { return NewSkewHeap[int, int](nil, lt, false) },
+		"syncSkew":     func() streamHeap { return NewSyncSkewHeap[int, int](nil, lt, true) },
+		"binomial":     func() streamHeap { return NewBinomialHeap[int, int](nil, lt, HeapConfig{}) },
+		"syncBinomial": func() streamHeap { return NewSyncBinomialHeap[int, int](nil, lt, HeapConfig{UsePool: true}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		var buf bytes.Buffer
+		require.NoError(t, heap.StreamJSON(&buf), name)
+		assert.Equal(t, "[]", buf.String(), name)
+
+		for _, p := range rand.Perm(200) {
+			heap.Push(p, p)
+		}
+		// Popping once leaves the tree-based heaps with non-trivial shapes.
+		_, _, _ = heap.Pop()
+
+		buf.Reset()
+		require.NoError(t, heap.StreamJSON(&buf), name)
+		var nodes []HeapNode[int, int]
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &nodes), name)
+		assert.Equal(t, heap.Export(ExportOptions[int, int]{}), nodes, name)
+		assert.Equal(t, 199, heap.Length(), name)
+	}
+}
+
+func TestFullHeap_StreamJSON(t *testing.T) {
+	heaps := map[string]func() TrackedHeap[int, int]{
+		"pairing": func() TrackedHeap[int, int] { return NewFullPairingHeap[int, int](nil, lt, HeapConfig{}) },
+		"leftist": func() TrackedHeap[int, int] { return NewFullLeftistHeap[int, int](nil, lt, HeapConfig{}) },
+		"skew":    func() TrackedHeap[int, int] { return NewSyncFullSkewHeap[int, int](nil, lt, HeapConfig{}) },
+	}
+
+	for name, constructor := range heaps {
+		heap := constructor()
+		ids := make(map[int]string)
+		for _, p := range []int{3, 1, 2} {
+			id, err := heap.Push(p*10, p)
+			require.NoError(t, err, name)
+			ids[p] = id
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, heap.(interface{ StreamJSON(io.Writer) error }).StreamJSON(&buf), name)
+		var nodes []trackedNodeJSON[int, int]
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &nodes), name)
+		require.Len(t, nodes, 3, name)
+		for i, node := range nodes {
+			assert.Equal(t, i+1, node.Priority, name)
+			assert.Equal(t, (i+1)*10, node.Value, name)
+			assert.Equal(t, ids[i+1], node.ID, name)
+		}
+	}
+}
+
+func TestDaryHeap_OrderedStopsEarly(t *testing.T) {
+	heap := NewStableBinaryHeap[string, int](nil, lt, DaryHeapConfig{})
+	for _, v := range []string{"a", "b", "c", "d"} {
+		heap.Push(v, 1)
+	}
+	heap.Push("z", 0)
+
+	var values []string
+	for v := range heap.Ordered() {
+		values = append(values, v)
+		if len(values) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"z", "a", "b"}, values)
+	assert.Equal(t, 5, heap.Length())
+}
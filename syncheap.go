@@ -0,0 +1,156 @@
+package heapcraft
+
+import "sync"
+
+// SyncHeap makes any Heap safe for concurrent use by guarding every call with
+// a mutex. It is the generic counterpart of the hand-written Sync wrappers
+// such as SyncDaryHeap: it works for heap types that have no Sync variant of
+// their own, including ones defined outside the package, at the cost of
+// serializing reads as well as writes, since a heap's read methods are not
+// guaranteed to leave it untouched.
+//
+// Only the methods of Heap are wrapped. Do runs any other operation on the
+// wrapped heap while holding the lock. The wrapped heap must not be used
+// directly once it has been wrapped.
+type SyncHeap[V any, P any] struct {
+	heap Heap[V, P]
+	mu   sync.Mutex
+}
+
+// NewSyncHeap wraps heap so that it is safe for concurrent use.
+func NewSyncHeap[V any, P any](heap Heap[V, P]) *SyncHeap[V, P] {
+	return &SyncHeap[V, P]{heap: heap}
+}
+
+// Do calls fn with the wrapped heap while holding the lock, so that several
+// operations, or ones that Heap does not cover, happen atomically. fn must not
+// call back into the SyncHeap or keep the heap after it returns.
+func (s *SyncHeap[V, P]) Do(fn func(heap Heap[V, P])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.heap)
+}
+
+// Push inserts an element with the given value and priority.
+func (s *SyncHeap[V, P]) Push(value V, priority P) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Push(value, priority)
+}
+
+// Pop removes and returns the root element. If the heap is empty, returns
+// zero values and an error.
+func (s *SyncHeap[V, P]) Pop() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pop()
+}
+
+// PopWhile removes and returns the root element for as long as pred reports
+// true for its priority, in the order they are popped, under a single lock.
+// pred must not call back into the heap.
+func (s *SyncHeap[V, P]) PopWhile(pred func(P) bool) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return PopWhile(s.heap, pred)
+}
+
+// PopIf removes and returns the root element only if pred reports true for
+// its value and priority. The check and the removal happen under a single
+// lock, so no other goroutine can take the root in between. If pred reports
+// false the root stays in the heap and is returned with false. pred must not
+// call back into the heap.
+func (s *SyncHeap[V, P]) PopIf(pred func(V, P) bool) (V, P, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return popIf(s.heap.Peek, s.heap.Pop, pred)
+}
+
+// PopValue removes and returns just the value of the root element.
+func (s *SyncHeap[V, P]) PopValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopValue()
+}
+
+// PopPriority removes and returns just the priority of the root element.
+func (s *SyncHeap[V, P]) PopPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PopPriority()
+}
+
+// Peek returns the root element without removing it. If the heap is empty,
+// returns zero values and an error.
+func (s *SyncHeap[V, P]) Peek() (V, P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Peek()
+}
+
+// PeekValue returns just the value of the root element without removing it.
+func (s *SyncHeap[V, P]) PeekValue() (V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekValue()
+}
+
+// PeekPriority returns just the priority of the root element without
+// removing it.
+func (s *SyncHeap[V, P]) PeekPriority() (P, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekPriority()
+}
+
+// Drain removes every element and returns them in the order they would be
+// popped.
+func (s *SyncHeap[V, P]) Drain() []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Drain()
+}
+
+// DrainValues removes every element and returns their values in the order
+// they would be popped.
+func (s *SyncHeap[V, P]) DrainValues() []V {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainValues()
+}
+
+// DrainPriorities removes every element and returns their priorities in the
+// order they would be popped.
+func (s *SyncHeap[V, P]) DrainPriorities() []P {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.DrainPriorities()
+}
+
+// Export returns a copy of the heap's elements selected and ordered by opts.
+func (s *SyncHeap[V, P]) Export(opts ExportOptions[V, P]) []HeapNode[V, P] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Export(opts)
+}
+
+// Length returns the number of elements in the heap.
+func (s *SyncHeap[V, P]) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Length()
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (s *SyncHeap[V, P]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.IsEmpty()
+}
+
+// Clear removes all elements from the heap.
+func (s *SyncHeap[V, P]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Clear()
+}
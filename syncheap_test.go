@@ -0,0 +1,130 @@
+package heapcraft
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncHeap_ConcurrentPushPop(t *testing.T) {
+	heaps := map[string]*SyncHeap[int, int]{
+		"adaptive":   NewSyncHeap[int, int](NewAdaptiveHeap[int, int](nil, lt, HeapConfig{})),
+		"auxPairing": NewSyncHeap[int, int](NewAuxPairingHeap[int, int](nil, lt, true)),
+		"dary":       NewSyncHeap[int, int](NewDaryHeap[int, int](4, nil, lt, false)),
+	}
+	for name, h := range heaps {
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					h.Push(g*500+i, g*500+i)
+				}
+			}(g)
+		}
+		wg.Wait()
+		assert.Equal(t, 2000, h.Length(), name)
+
+		popped := make(chan int, 1000)
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 250; i++ {
+					p, err := h.PopPriority()
+					if assert.NoError(t, err, name) {
+						popped <- p
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(popped)
+		assert.Len(t, popped, 1000, name)
+		assert.Equal(t, 1000, h.Length(), name)
+		assert.True(t, slices.IsSorted(h.DrainPriorities()), name)
+		assert.True(t, h.IsEmpty(), name)
+	}
+}
+
+func TestSyncHeap_PopIf(t *testing.T) {
+	h := NewSyncHeap[int, int](NewAdaptiveHeap[int, int](nil, lt, HeapConfig{}))
+	_, _, ok, err := h.PopIf(func(int, int) bool { return true })
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	for i := 0; i < 200; i++ {
+		h.Push(i, i)
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var popped []int
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, p, ok, err := h.PopIf(func(_ int, p int) bool { return p < 100 })
+				require.NoError(t, err)
+				if ok {
+					mu.Lock()
+					popped = append(popped, p)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	slices.Sort(popped)
+	assert.Len(t, popped, 100)
+	assert.Equal(t, 0, popped[0])
+	assert.Equal(t, 99, popped[99])
+	assert.Equal(t, 100, h.Length())
+
+	v, p, ok, err := h.PopIf(func(_ int, p int) bool { return p < 100 })
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 100, v)
+	assert.Equal(t, 100, p)
+	assert.Equal(t, 100, h.Length())
+}
+
+func TestSyncHeap_DoAndBlocking(t *testing.T) {
+	h := NewSyncHeap[string, int](NewDaryHeap[string, int](2, nil, lt, false))
+	h.Push("b", 2)
+	h.Push("a", 1)
+	h.Do(func(heap Heap[string, int]) {
+		heap.(*DaryHeap[string, int]).PushPop("c", 3)
+		require.NoError(t, heap.(Verifier).Verify())
+	})
+	v, err := h.PeekValue()
+	require.NoError(t, err)
+	assert.Equal(t, "b", v)
+
+	blocking := NewBlockingHeap[string, int](h)
+	v, _, err = blocking.PopWait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "b", v)
+	assert.Equal(t, []string{"c"}, h.DrainValues())
+	h.Clear()
+	_, err = h.PopValue()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestSyncVariants_BinaryAndLeftist(t *testing.T) {
+	data := []HeapNode[int, int]{CreateHeapNode(3, 3), CreateHeapNode(1, 1), CreateHeapNode(2, 2)}
+	heaps := map[string]Heap[int, int]{
+		"syncBinary":  NewSyncBinaryHeap(data, lt, false),
+		"syncLeftist": NewSyncLeftistHeap(data, lt, true),
+		"syncSoft":    NewSyncHeap[int, int](NewSoftHeap(data, lt, 0.1)),
+	}
+	for name, heap := range heaps {
+		assert.Equal(t, 3, heap.Length(), name)
+		assert.ElementsMatch(t, []int{1, 2, 3}, heap.DrainPriorities(), name)
+	}
+}
@@ -0,0 +1,91 @@
+package heapcraft
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Tiers maps continuous priorities onto a small set of integer tiers using a
+// list of ascending upper bounds. A priority p belongs to tier i, where i is
+// the index of the first bound with p <= bound; priorities above every bound
+// belong to the last tier, len(bounds). Pushing tiers instead of raw
+// priorities makes comparisons cheaper and turns near-equal priorities into
+// ties that can be consumed together with PopAllEqual.
+type Tiers[P constraints.Ordered] struct {
+	bounds []P
+}
+
+// NewTiers creates a Tiers mapping from the given upper bounds, which must be
+// strictly ascending. Returns an error if they are not.
+func NewTiers[P constraints.Ordered](bounds ...P) (*Tiers[P], error) {
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			return nil, ErrInvalidTiers
+		}
+	}
+	copied := make([]P, len(bounds))
+	copy(copied, bounds)
+	return &Tiers[P]{bounds: copied}, nil
+}
+
+// NewLinearTiers creates a Tiers mapping with count tiers. Tier 0 holds
+// priorities up to start, each following tier covers the next width units, and
+// the last tier holds everything above. Returns an error if width is not
+// positive or count is less than one.
+func NewLinearTiers[P constraints.Integer | constraints.Float](start, width P, count int) (*Tiers[P], error) {
+	if width <= 0 || count < 1 {
+		return nil, ErrInvalidTiers
+	}
+	bounds := make([]P, count-1)
+	for i := range bounds {
+		bounds[i] = start + width*P(i)
+	}
+	return &Tiers[P]{bounds: bounds}, nil
+}
+
+// Count returns the number of tiers.
+func (t *Tiers[P]) Count() int { return len(t.bounds) + 1 }
+
+// Tier returns the tier that the given priority belongs to.
+func (t *Tiers[P]) Tier(priority P) int {
+	return sort.Search(len(t.bounds), func(i int) bool {
+		return priority <= t.bounds[i]
+	})
+}
+
+// PopAllEqual removes and returns every element whose priority is equal to the
+// priority at the root of the heap, in the order they are popped. Returns an
+// error if the heap is empty. The operation is not atomic for thread-safe
+// heaps; elements pushed concurrently may or may not be included.
+func PopAllEqual[V any, P comparable](heap BaseHeap[V, P]) ([]HeapNode[V, P], error) {
+	v, p, err := heap.Pop()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []HeapNode[V, P]{{value: v, priority: p}}
+	for {
+		next, err := heap.PeekPriority()
+		if err != nil || next != p {
+			return nodes, nil
+		}
+		v, _, _ := heap.Pop()
+		nodes = append(nodes, HeapNode[V, P]{value: v, priority: p})
+	}
+}
+
+// PopWhile removes and returns the root element of heap for as long as pred
+// reports true for its priority, in the order they are popped, for example
+// every timer whose deadline has passed. Heaps with a PopWhile method of their
+// own, such as DaryHeap, SyncHeap and the Sync variants of the pairing,
+// leftist, skew and binomial heaps, are drained with it, which the thread-safe
+// ones do under a single lock. Other heaps are drained with Peek and Pop.
+func PopWhile[V any, P any](heap BaseHeap[V, P], pred func(P) bool) []HeapNode[V, P] {
+	if native, ok := heap.(interface {
+		PopWhile(pred func(P) bool) []HeapNode[V, P]
+	}); ok {
+		return native.PopWhile(pred)
+	}
+	return popWhile(heap.Peek, heap.Pop, pred)
+}
@@ -0,0 +1,116 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiers_Tier(t *testing.T) {
+	tiers, err := NewTiers(0.5, 1.0, 2.5)
+	require.NoError(t, err)
+	assert.Equal(t, 4, tiers.Count())
+	assert.Equal(t, 0, tiers.Tier(-3))
+	assert.Equal(t, 0, tiers.Tier(0.5))
+	assert.Equal(t, 1, tiers.Tier(0.75))
+	assert.Equal(t, 2, tiers.Tier(2.5))
+	assert.Equal(t, 3, tiers.Tier(100))
+
+	_, err = NewTiers(1, 1)
+	assert.ErrorIs(t, err, ErrInvalidTiers)
+	_, err = NewTiers(3, 2)
+	assert.ErrorIs(t, err, ErrInvalidTiers)
+}
+
+func TestTiers_Linear(t *testing.T) {
+	tiers, err := NewLinearTiers(0, 10, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, tiers.Count())
+	assert.Equal(t, 0, tiers.Tier(-5))
+	assert.Equal(t, 1, tiers.Tier(5))
+	assert.Equal(t, 1, tiers.Tier(10))
+	assert.Equal(t, 2, tiers.Tier(11))
+
+	_, err = NewLinearTiers(0, 0, 3)
+	assert.ErrorIs(t, err, ErrInvalidTiers)
+	_, err = NewLinearTiers(0, 1, 0)
+	assert.ErrorIs(t, err, ErrInvalidTiers)
+}
+
+func TestPopAllEqual_WithTiers(t *testing.T) {
+	tiers, _ := NewTiers(1.0, 2.0)
+	heap := NewBinaryHeap[string, int](nil, lt, false)
+	latencies := map[string]float64{"a": 0.2, "b": 1.7, "c": 0.9, "d": 5.0, "e": 1.1}
+	for name, latency := range latencies {
+		heap.Push(name, tiers.Tier(latency))
+	}
+
+	batch, err := PopAllEqual[string, int](heap)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []HeapNode[string, int]{
+		CreateHeapNode("a", 0),
+		CreateHeapNode("c", 0),
+	}, batch)
+
+	batch, _ = PopAllEqual[string, int](heap)
+	assert.Len(t, batch, 2)
+	batch, _ = PopAllEqual[string, int](heap)
+	assert.Equal(t, []HeapNode[string, int]{CreateHeapNode("d", 2)}, batch)
+
+	_, err = PopAllEqual[string, int](heap)
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+}
+
+func TestPopWhile(t *testing.T) {
+	below := func(limit int) func(int) bool { return func(p int) bool { return p <= limit } }
+	heaps := map[string]Heap[int, int]{
+		"dary":        NewDaryHeap[int, int](3, nil, lt, false),
+		"pairing":     NewPairingHeap[int, int](nil, lt, false),
+		"syncDary":    NewSyncDaryHeap[int, int](2, nil, lt, false),
+		"syncSkew":    NewSyncSkewHeap[int, int](nil, lt, false),
+		"syncLeftist": NewSyncLeftistHeap[int, int](nil, lt, false),
+		"syncHeap":    NewSyncHeap[int, int](NewBinomialHeap[int, int](nil, lt, HeapConfig{})),
+	}
+	for name, heap := range heaps {
+		for _, p := range []int{5, 1, 9, 3, 7, 3} {
+			heap.Push(p*10, p)
+		}
+		assert.Empty(t, PopWhile(heap, below(0)), name)
+
+		nodes := PopWhile(heap, below(5))
+		assert.Equal(t, []int{1, 3, 3, 5}, nodePriorities(nodes), name)
+		assert.Equal(t, 2, heap.Length(), name)
+
+		assert.Len(t, PopWhile(heap, below(100)), 2, name)
+		assert.Empty(t, PopWhile(heap, below(100)), name)
+	}
+}
+
+func TestDaryHeap_CountAtOrBelow(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for _, levels := range []int{0, 2} {
+		data := make([]HeapNode[int, int], 500)
+		for i := range data {
+			data[i] = CreateHeapNode(i, r.Intn(100))
+		}
+		minHeap := NewDaryHeapWithConfig(4, slices.Clone(data), lt, DaryHeapConfig{BlockLevels: levels})
+		maxHeap := NewSyncDaryHeapWithConfig(3, slices.Clone(data), gt, DaryHeapConfig{BlockLevels: levels})
+		for _, p := range []int{-1, 0, 10, 50, 99, 200} {
+			below, above := 0, 0
+			for _, node := range data {
+				if node.priority <= p {
+					below++
+				}
+				if node.priority >= p {
+					above++
+				}
+			}
+			assert.Equal(t, below, minHeap.CountAtOrBelow(p), "levels=%d p=%d", levels, p)
+			assert.Equal(t, above, maxHeap.CountAtOrBelow(p), "levels=%d p=%d", levels, p)
+		}
+	}
+	assert.Zero(t, NewBinaryHeap[int, int](nil, lt, false).CountAtOrBelow(10))
+}
@@ -0,0 +1,139 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TimerScheduler runs callbacks at scheduled times. Pending callbacks are kept
+// in a FullPairingHeap keyed by their due time, so scheduling, cancelling and
+// rescheduling a callback are all heap operations on its ID, and a single
+// goroutine calling Run serves any number of timers. It is safe for
+// concurrent use.
+type TimerScheduler struct {
+	heap *FullPairingHeap[func(), time.Time]
+	mu   sync.Mutex
+	// wake is signalled whenever the earliest due time may have changed, so
+	// that a waiting Run re-examines the heap instead of sleeping until a
+	// stale deadline.
+	wake chan struct{}
+}
+
+// notify wakes a waiting Run without blocking.
+func (s *TimerScheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// At schedules fn to run at the given time and returns an ID that can be
+// passed to Cancel or Reschedule. A time in the past runs fn as soon as
+// possible. Returns an error if an ID could not be generated.
+func (s *TimerScheduler) At(at time.Time, fn func()) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, err := s.heap.Push(fn, at)
+	if err != nil {
+		return "", err
+	}
+	s.notify()
+	return id, nil
+}
+
+// After schedules fn to run once the duration d has elapsed and returns an ID
+// that can be passed to Cancel or Reschedule.
+func (s *TimerScheduler) After(d time.Duration, fn func()) (string, error) {
+	return s.At(time.Now().Add(d), fn)
+}
+
+// Cancel removes the callback with the given ID so that it never runs.
+// Returns an error if no pending callback has the ID, for instance because it
+// has already run.
+func (s *TimerScheduler) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, _, err := s.heap.Remove(id); err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// Reschedule moves the callback with the given ID to a new time. Returns an
+// error if no pending callback has the ID.
+func (s *TimerScheduler) Reschedule(id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.heap.UpdatePriority(id, at); err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// Next returns the time at which the earliest pending callback is due.
+// Returns the zero time and an error if no callback is pending.
+func (s *TimerScheduler) Next() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.PeekPriority()
+}
+
+// Length returns the number of pending callbacks.
+func (s *TimerScheduler) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Length()
+}
+
+// popDue removes and returns the earliest callback if it is due. Otherwise it
+// returns nil and how long to wait for it, or a negative duration if no
+// callback is pending.
+func (s *TimerScheduler) popDue() (func(), time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	at, err := s.heap.PeekPriority()
+	if err != nil {
+		return nil, -1
+	}
+	if wait := time.Until(at); wait > 0 {
+		return nil, wait
+	}
+	fn, _ := s.heap.PopValue()
+	return fn, 0
+}
+
+// Run runs callbacks as they become due until ctx is done, and then returns
+// the context's error. Callbacks run one at a time on the calling goroutine
+// in order of their due time; a slow callback delays the ones after it.
+// Callbacks may schedule, cancel or reschedule other callbacks.
+func (s *TimerScheduler) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fn, wait := s.popDue()
+		if fn != nil {
+			fn()
+			continue
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package heapcraft
+
+import "time"
+
+// NewTimerScheduler creates a TimerScheduler with no pending callbacks.
+// config controls node pooling and the generator used for callback IDs.
+func NewTimerScheduler(config HeapConfig) *TimerScheduler {
+	before := func(a, b time.Time) bool { return a.Before(b) }
+	return &TimerScheduler{
+		heap: NewFullPairingHeap[func(), time.Time](nil, before, config),
+		wake: make(chan struct{}, 1),
+	}
+}
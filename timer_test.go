@@ -0,0 +1,115 @@
+package heapcraft
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runScheduler starts Run on a new goroutine and returns a function that
+// stops it and returns Run's error.
+func runScheduler(s *TimerScheduler) func() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func TestTimerScheduler_RunsInOrder(t *testing.T) {
+	s := NewTimerScheduler(HeapConfig{})
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+
+	now := time.Now()
+	_, err := s.At(now.Add(30*time.Millisecond), record("c"))
+	require.Nil(t, err)
+	_, err = s.At(now.Add(-time.Second), record("a"))
+	require.Nil(t, err)
+	_, err = s.After(10*time.Millisecond, record("b"))
+	require.Nil(t, err)
+	assert.Equal(t, 3, s.Length())
+
+	stop := runScheduler(s)
+	assert.Eventually(t, func() bool { return s.Length() == 0 }, time.Second, time.Millisecond)
+	assert.ErrorIs(t, stop(), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+}
+
+func TestTimerScheduler_CancelAndReschedule(t *testing.T) {
+	s := NewTimerScheduler(HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	fired := make(chan string, 3)
+
+	cancelled, err := s.After(time.Hour, func() { fired <- "cancelled" })
+	require.Nil(t, err)
+	moved, err := s.After(time.Hour, func() { fired <- "moved" })
+	require.Nil(t, err)
+
+	require.Nil(t, s.Cancel(cancelled))
+	assert.ErrorIs(t, s.Cancel(cancelled), ErrNodeNotFound)
+	assert.Equal(t, 1, s.Length())
+
+	stop := runScheduler(s)
+	defer stop()
+
+	// Rescheduling wakes the running loop, which was waiting for an hour.
+	require.Nil(t, s.Reschedule(moved, time.Now()))
+	select {
+	case name := <-fired:
+		assert.Equal(t, "moved", name)
+	case <-time.After(time.Second):
+		t.Fatal("rescheduled callback did not run")
+	}
+	assert.ErrorIs(t, s.Reschedule(moved, time.Now()), ErrNodeNotFound)
+}
+
+func TestTimerScheduler_ScheduleFromCallback(t *testing.T) {
+	s := NewTimerScheduler(HeapConfig{})
+	fired := make(chan struct{})
+	_, err := s.After(0, func() {
+		s.After(time.Millisecond, func() { close(fired) })
+	})
+	require.Nil(t, err)
+
+	stop := runScheduler(s)
+	defer stop()
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("callback scheduled from a callback did not run")
+	}
+}
+
+func TestTimerScheduler_Next(t *testing.T) {
+	s := NewTimerScheduler(HeapConfig{})
+	_, err := s.Next()
+	assert.ErrorIs(t, err, ErrHeapEmpty)
+
+	at := time.Now().Add(time.Minute)
+	s.At(at.Add(time.Minute), func() {})
+	s.At(at, func() {})
+	next, err := s.Next()
+	require.Nil(t, err)
+	assert.True(t, at.Equal(next))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.Run(ctx), context.Canceled)
+	assert.Equal(t, 2, s.Length())
+}
@@ -0,0 +1,65 @@
+package heapcraft
+
+import "slices"
+
+// TopK collects the k elements that come first according to a comparison
+// function from a stream of any length, in O(log k) time per element and O(k)
+// memory. Unlike SelectK and NLargestDary it does not need the whole input at
+// once, and partial results collected separately, for example one per
+// goroutine, can be combined with Merge. A TopK is not safe for concurrent
+// use.
+type TopK[V any, P any] struct {
+	k   int
+	cmp func(a, b P) bool
+	// heap is ordered in reverse so that its root is the worst element kept,
+	// which is the one displaced by a better element.
+	heap *DaryHeap[V, P]
+}
+
+// K returns the maximum number of elements the collector keeps.
+func (t *TopK[V, P]) K() int { return t.k }
+
+// Length returns the number of elements currently kept, which is at most K.
+func (t *TopK[V, P]) Length() int { return t.heap.Length() }
+
+// Add offers an element to the collector and reports whether it was kept. An
+// element is kept if fewer than k elements have been seen or if it comes
+// before the worst element kept, which it then replaces. Ties with the worst
+// element keep the element already collected.
+func (t *TopK[V, P]) Add(value V, priority P) bool {
+	switch {
+	case t.heap.Length() < t.k:
+		t.heap.Push(value, priority)
+	case t.k > 0 && t.cmp(priority, t.heap.data[0].priority):
+		t.heap.data[0] = HeapNode[V, P]{value: value, priority: priority}
+		t.heap.siftDown(0)
+	default:
+		return false
+	}
+	return true
+}
+
+// Merge offers every element kept by other to this collector, so that it
+// holds the top k of both inputs. other is left unchanged. Both collectors
+// are expected to share the same comparison function.
+func (t *TopK[V, P]) Merge(other *TopK[V, P]) {
+	if other == nil || other == t {
+		return
+	}
+	for _, node := range other.heap.data {
+		t.Add(node.value, node.priority)
+	}
+}
+
+// Result returns the elements kept so far, best first. The collector is left
+// unchanged and can keep accepting elements.
+func (t *TopK[V, P]) Result() []HeapNode[V, P] {
+	result := Sorted(t.heap)
+	slices.Reverse(result)
+	return result
+}
+
+// Reset discards all elements kept so far, keeping the allocated capacity.
+func (t *TopK[V, P]) Reset() {
+	t.heap.Reset()
+}
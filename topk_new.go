@@ -0,0 +1,12 @@
+package heapcraft
+
+// NewTopK creates an empty TopK that keeps the k elements that come first
+// according to cmp: pass a less-than function to keep the k smallest
+// priorities and a greater-than function to keep the k largest.
+func NewTopK[V any, P any](k int, cmp func(a, b P) bool) *TopK[V, P] {
+	return &TopK[V, P]{
+		k:    k,
+		cmp:  cmp,
+		heap: NewBinaryHeapWithConfig(make([]HeapNode[V, P], 0, max(k, 0)), Reverse(cmp), DaryHeapConfig{}),
+	}
+}
@@ -0,0 +1,66 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK_Add(t *testing.T) {
+	top := NewTopK[string, int](3, gt)
+	assert.True(t, top.Add("a", 5))
+	assert.True(t, top.Add("b", 1))
+	assert.True(t, top.Add("c", 8))
+	assert.True(t, top.Add("d", 6))
+	assert.False(t, top.Add("e", 2))
+	assert.False(t, top.Add("f", 5))
+
+	assert.Equal(t, 3, top.Length())
+	assert.Equal(t, []int{8, 6, 5}, nodePriorities(top.Result()))
+	// Result leaves the collector usable.
+	assert.True(t, top.Add("g", 9))
+	assert.Equal(t, []int{9, 8, 6}, nodePriorities(top.Result()))
+
+	top.Reset()
+	assert.Equal(t, 0, top.Length())
+	assert.Empty(t, top.Result())
+}
+
+func TestTopK_ZeroK(t *testing.T) {
+	top := NewTopK[int, int](0, lt)
+	assert.False(t, top.Add(1, 1))
+	assert.Empty(t, top.Result())
+}
+
+func TestTopK_Merge(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	data := randomNodes(r, 4000, 100_000)
+	want := nodePriorities(data)
+	slices.Sort(want)
+	want = want[:25]
+
+	parts := make([]*TopK[int, int], 4)
+	var wg sync.WaitGroup
+	for i := range parts {
+		parts[i] = NewTopK[int, int](25, lt)
+		wg.Add(1)
+		go func(top *TopK[int, int], chunk []HeapNode[int, int]) {
+			defer wg.Done()
+			for _, node := range chunk {
+				top.Add(node.value, node.priority)
+			}
+		}(parts[i], data[i*1000:(i+1)*1000])
+	}
+	wg.Wait()
+
+	merged := NewTopK[int, int](25, lt)
+	for _, part := range parts {
+		merged.Merge(part)
+	}
+	merged.Merge(merged)
+	assert.Equal(t, want, nodePriorities(merged.Result()))
+	assert.Equal(t, 25, parts[0].Length())
+}
@@ -0,0 +1,54 @@
+package heapcraft
+
+// MapNodes returns the result of applying fn to every element of heap, in the
+// order the elements would be popped. The heap itself is not modified, so the
+// result can be passed to any constructor to build a heap of a different type.
+func MapNodes[V any, P any, V2 any, P2 any](heap BaseHeap[V, P], fn func(value V, priority P) (V2, P2)) []HeapNode[V2, P2] {
+	nodes := heap.Export(ExportOptions[V, P]{})
+	mapped := make([]HeapNode[V2, P2], len(nodes))
+	for i, node := range nodes {
+		mapped[i].value, mapped[i].priority = fn(node.value, node.priority)
+	}
+	return mapped
+}
+
+// MapHeap pushes the result of applying fn to every element of src into dst,
+// in the order the elements would be popped from src, and returns dst. src is
+// not modified. Since fn may change priorities, the elements need not come out
+// of dst in the same order as they would from src.
+func MapHeap[V any, P any, V2 any, P2 any, H Heap[V2, P2]](src BaseHeap[V, P], dst H, fn func(value V, priority P) (V2, P2)) H {
+	for _, node := range src.Export(ExportOptions[V, P]{}) {
+		dst.Push(fn(node.value, node.priority))
+	}
+	return dst
+}
+
+// FilterHeap removes every element of heap for which keep returns false and
+// returns the number of elements removed. It drains the heap and pushes the
+// kept elements back in the order they were popped, so elements of a stable
+// heap with equal priorities keep their relative order. It takes O(n log n)
+// time and is meant for occasional pruning, such as dropping cancelled jobs
+// from a queue. The operation is not atomic for thread-safe heaps; wrap it in
+// SyncHeap.Do or hold off other writers while it runs.
+func FilterHeap[V any, P any](heap Heap[V, P], keep func(value V, priority P) bool) int {
+	removed := 0
+	for _, node := range heap.Drain() {
+		if keep(node.value, node.priority) {
+			heap.Push(node.value, node.priority)
+		} else {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Reduce folds every element of heap into an accumulator, starting from init
+// and visiting the elements in the order they would be popped. The heap itself
+// is not modified.
+func Reduce[V any, P any, A any](heap BaseHeap[V, P], init A, fn func(acc A, value V, priority P) A) A {
+	acc := init
+	for _, node := range heap.Export(ExportOptions[V, P]{}) {
+		acc = fn(acc, node.value, node.priority)
+	}
+	return acc
+}
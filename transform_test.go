@@ -0,0 +1,46 @@
+package heapcraft
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransforms(t *testing.T) {
+	data := []HeapNode[int, int]{
+		CreateHeapNode(5, 5), CreateHeapNode(1, 1), CreateHeapNode(4, 4),
+		CreateHeapNode(2, 2), CreateHeapNode(3, 3),
+	}
+	h := NewDaryHeapCopy(2, data, lt, false)
+
+	labels := MapNodes(h, func(v int, p int) (string, int) { return strconv.Itoa(v), -p })
+	assert.Equal(t, []int{-1, -2, -3, -4, -5}, nodePriorities(labels))
+	assert.Equal(t, "1", labels[0].Value())
+
+	reversed := MapHeap(h, NewPairingHeap[string, int](nil, lt, false), func(v int, p int) (string, int) {
+		return strconv.Itoa(v), -p
+	})
+	assert.Equal(t, []string{"5", "4", "3", "2", "1"}, reversed.DrainValues())
+
+	sum := Reduce(h, 0, func(acc int, v int, p int) int { return acc + v })
+	assert.Equal(t, 15, sum)
+	order := Reduce(h, "", func(acc string, v int, p int) string { return acc + strconv.Itoa(v) })
+	assert.Equal(t, "12345", order)
+	assert.Equal(t, 5, h.Length())
+
+	removed := FilterHeap(h, func(v int, p int) bool { return v%2 == 1 })
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, []int{1, 3, 5}, h.DrainValues())
+
+	stable := NewStableDaryHeap[int, int](2, nil, lt, DaryHeapConfig{})
+	for i, p := range []int{2, 1, 2, 1, 2} {
+		stable.Push(i, p)
+	}
+	assert.Equal(t, 0, FilterHeap(stable, func(v int, p int) bool { return true }))
+	assert.Equal(t, []int{1, 3, 0, 2, 4}, stable.DrainValues())
+
+	synced := NewSyncHeap[int, int](NewLeftistHeap(data, lt, false))
+	assert.Equal(t, 3, FilterHeap(synced, func(v int, p int) bool { return v > 3 }))
+	assert.Equal(t, []int{4, 5}, synced.DrainValues())
+}
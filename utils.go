@@ -1,10 +1,20 @@
 package heapcraft
 
 import (
-	"math/rand"
-	"testing"
+	"reflect"
+	"sync"
+
+	"golang.org/x/exp/constraints"
 )
 
+// orderedLess reports whether a < b. It is the comparison used by the Min
+// heap constructors.
+func orderedLess[P constraints.Ordered](a, b P) bool { return a < b }
+
+// orderedGreater reports whether a > b. It is the comparison used by the Max
+// heap constructors.
+func orderedGreater[P constraints.Ordered](a, b P) bool { return a > b }
+
 // zeroValuePair returns the zero value of type V and P.
 func zeroValuePair[V any, P any]() (V, P) {
 	var zeroV V
@@ -32,26 +42,154 @@ func priorityFromNode[V any, P any](_ V, p P, err error) (P, error) {
 	return p, nil
 }
 
-// generateRandomNumbers generates a slice of random numbers for benchmarking.
-// It uses a dynamic seed for the random number generator.
-func generateRandomNumbers(b *testing.B, seed int64) []int {
-	N := 10_000
-	r := rand.New(rand.NewSource(seed))
-	randomNumbers := make([]int, 0, b.N)
-	for i := 0; i < b.N; i++ {
-		randomNumbers = append(randomNumbers, r.Intn(N))
+// popIf peeks at the root with peek and removes it with pop only if pred
+// reports true for it. When pred reports false the peeked root is returned
+// with false. Callers that are safe for concurrent use must hold their lock
+// across the call so that the root cannot change in between.
+func popIf[V any, P any](peek, pop func() (V, P, error), pred func(V, P) bool) (V, P, bool, error) {
+	v, p, err := peek()
+	if err != nil || !pred(v, p) {
+		return v, p, false, err
+	}
+	v, p, err = pop()
+	return v, p, err == nil, err
+}
+
+// popWhile pops elements with pop for as long as the heap is not empty and
+// pred reports true for the priority that peek returns for the root. Callers
+// that are safe for concurrent use must hold their lock across the call.
+func popWhile[V any, P any](peek, pop func() (V, P, error), pred func(P) bool) []HeapNode[V, P] {
+	var nodes []HeapNode[V, P]
+	for {
+		_, p, err := peek()
+		if err != nil || !pred(p) {
+			return nodes
+		}
+		v, p, _ := pop()
+		nodes = append(nodes, HeapNode[V, P]{value: v, priority: p})
+	}
+}
+
+// updatePriorityFunc reads the priority of the element identified by id with
+// get and replaces it with update by the result of fn. Callers that are safe
+// for concurrent use must hold their write lock across the call.
+func updatePriorityFunc[ID any, P any](get func(ID) (P, error), update func(ID, P) error, id ID, fn func(old P) P) error {
+	old, err := get(id)
+	if err != nil {
+		return err
+	}
+	return update(id, fn(old))
+}
+
+// drainNodes pops n elements using the given pop function and collects them
+// into a single preallocated slice, in the order they were removed.
+func drainNodes[V any, P any](n int, pop func() (V, P, error)) []HeapNode[V, P] {
+	nodes := make([]HeapNode[V, P], 0, n)
+	for i := 0; i < n; i++ {
+		v, p, _ := pop()
+		nodes = append(nodes, HeapNode[V, P]{value: v, priority: p})
 	}
-	return randomNumbers
+	return nodes
 }
 
-// generateRandomNumbersv1 generates a slice of random numbers for benchmarking.
-// It uses a fixed seed of 42 for the random number generator.
-func generateRandomNumbersv1(b *testing.B) []int {
-	return generateRandomNumbers(b, 42)
+// drainValues pops n elements using the given pop function and collects their
+// values into a single preallocated slice, in the order they were removed.
+func drainValues[V any, P any](n int, pop func() (V, P, error)) []V {
+	values := make([]V, 0, n)
+	for i := 0; i < n; i++ {
+		v, _, _ := pop()
+		values = append(values, v)
+	}
+	return values
 }
 
-// generateRandomNumbersv2 generates a slice of random numbers for benchmarking.
-// It uses a fixed seed of 50 for the random number generator.
-func generateRandomNumbersv2(b *testing.B) []int {
-	return generateRandomNumbers(b, 50)
+// drainPriorities pops n elements using the given pop function and collects
+// their priorities into a single preallocated slice, in the order they were
+// removed.
+func drainPriorities[V any, P any](n int, pop func() (V, P, error)) []P {
+	priorities := make([]P, 0, n)
+	for i := 0; i < n; i++ {
+		_, p, _ := pop()
+		priorities = append(priorities, p)
+	}
+	return priorities
+}
+
+// lockPair write-locks two distinct mutexes in address order, so that two
+// goroutines locking the same pair in opposite roles cannot deadlock. It
+// returns a function that releases both locks. Addresses are read through
+// reflect rather than unsafe so the package builds where unsafe is disallowed,
+// such as sandboxed WebAssembly plugin runtimes.
+func lockPair(a, b *sync.RWMutex) func() {
+	if reflect.ValueOf(a).Pointer() > reflect.ValueOf(b).Pointer() {
+		a, b = b, a
+	}
+	a.Lock()
+	b.Lock()
+	return func() {
+		b.Unlock()
+		a.Unlock()
+	}
+}
+
+// cloneTree copies the binary tree rooted at root into nodes taken from get
+// and returns the copy's root. Each node is copied field by field and links
+// reports the addresses of its two child links, which are then redirected to
+// copies of the children. The tree is walked with an explicit stack, so a
+// degenerate tree of any depth cannot overflow the goroutine stack.
+func cloneTree[T any](root *T, get func() *T, links func(node *T) (**T, **T)) *T {
+	if root == nil {
+		return nil
+	}
+	cloned := get()
+	*cloned = *root
+	stack := []*T{cloned}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		first, second := links(node)
+		for _, link := range [2]**T{first, second} {
+			if *link != nil {
+				child := get()
+				*child = **link
+				*link = child
+				stack = append(stack, child)
+			}
+		}
+	}
+	return cloned
+}
+
+const (
+	// shrinkRatio is how many times its length the capacity of an array must
+	// exceed before an auto-shrinking heap reallocates it.
+	shrinkRatio = 4
+	// minShrinkCapacity is the largest capacity an auto-shrinking heap keeps
+	// however few elements it holds, so small heaps are never reallocated.
+	minShrinkCapacity = 64
+)
+
+// compactNodes returns a copy of nodes in a newly allocated array of exactly
+// its length, or nil if nodes is empty, so that the old backing array can be
+// reclaimed.
+func compactNodes[V any, P any](nodes []HeapNode[V, P]) []HeapNode[V, P] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	compacted := make([]HeapNode[V, P], len(nodes))
+	copy(compacted, nodes)
+	return compacted
+}
+
+// shrinkNodes returns nodes moved to an array of twice its length once its
+// capacity has grown past shrinkRatio times its length. The headroom left
+// behind means a shrunk array is only copied again after its length halves
+// twice more, so shrinking costs amortized O(1) per removal.
+func shrinkNodes[V any, P any](nodes []HeapNode[V, P]) []HeapNode[V, P] {
+	if cap(nodes) <= minShrinkCapacity || cap(nodes) <= shrinkRatio*len(nodes) {
+		return nodes
+	}
+	shrunk := make([]HeapNode[V, P], len(nodes), 2*len(nodes))
+	copy(shrunk, nodes)
+	return shrunk
 }
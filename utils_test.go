@@ -1,6 +1,7 @@
 package heapcraft
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,3 +39,27 @@ func TestPriorityFromNode(t *testing.T) {
 	assert.Equal(t, "", priority)
 	assert.Equal(t, ErrNodeNotFound, err)
 }
+
+// generateRandomNumbers generates a slice of random numbers for benchmarking.
+// It uses a dynamic seed for the random number generator.
+func generateRandomNumbers(b *testing.B, seed int64) []int {
+	N := 10_000
+	r := rand.New(rand.NewSource(seed))
+	randomNumbers := make([]int, 0, b.N)
+	for i := 0; i < b.N; i++ {
+		randomNumbers = append(randomNumbers, r.Intn(N))
+	}
+	return randomNumbers
+}
+
+// generateRandomNumbersv1 generates a slice of random numbers for benchmarking.
+// It uses a fixed seed of 42 for the random number generator.
+func generateRandomNumbersv1(b *testing.B) []int {
+	return generateRandomNumbers(b, 42)
+}
+
+// generateRandomNumbersv2 generates a slice of random numbers for benchmarking.
+// It uses a fixed seed of 50 for the random number generator.
+func generateRandomNumbersv2(b *testing.B) []int {
+	return generateRandomNumbers(b, 50)
+}
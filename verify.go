@@ -0,0 +1,73 @@
+package heapcraft
+
+import "fmt"
+
+// invariantError returns an error wrapping ErrInvariantViolated with a
+// description of the violation.
+func invariantError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvariantViolated, fmt.Sprintf(format, args...))
+}
+
+// verifyTree walks the trees below roots without recursion, so arbitrarily
+// deep trees can be checked, and calls check for every node together with its
+// parent, which is the zero value for a root. children calls visit for every
+// child of a node. Returns the number of nodes visited, or the first error
+// returned by check.
+func verifyTree[N comparable](roots []N, children func(node N, visit func(N)), check func(node, parent N) error) (int, error) {
+	type pending struct{ node, parent N }
+	stack := make([]pending, 0, len(roots))
+	var none N
+	for _, root := range roots {
+		stack = append(stack, pending{root, none})
+	}
+
+	visited := 0
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if err := check(top.node, top.parent); err != nil {
+			return visited, err
+		}
+		visited++
+		children(top.node, func(child N) {
+			stack = append(stack, pending{child, top.node})
+		})
+	}
+	return visited, nil
+}
+
+// verifySize checks that the number of nodes reachable in a heap agrees with
+// its recorded size.
+func verifySize(visited int, size int) error {
+	if visited != size {
+		return invariantError("%d nodes are reachable but the size is %d", visited, size)
+	}
+	return nil
+}
+
+// verifyElements checks that the ID map of a tracked heap holds exactly the
+// nodes reachable in the tree. Each reachable node is checked against the map
+// as it is visited, so only the count is compared here.
+func verifyElements(elements int, size int) error {
+	if elements != size {
+		return invariantError("%d nodes are tracked by ID but the size is %d", elements, size)
+	}
+	return nil
+}
+
+// verifyTracked checks that a reachable node of a tracked heap is the node its
+// ID maps to.
+func verifyTracked[N comparable](elements map[string]N, id string, node N) error {
+	if elements[id] != node {
+		return invariantError("node %q is not the node tracked under its ID", id)
+	}
+	return nil
+}
+
+// verifyOrder checks that a node does not come before its parent.
+func verifyOrder[P any](cmp func(a, b P) bool, priority P, parent P) error {
+	if cmp(priority, parent) {
+		return invariantError("a node comes before its parent")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package heapcraft
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_Heaps(t *testing.T) {
+	heaps := map[string]Heap[int, int]{
+		"dary":     NewDaryHeap[int, int](3, nil, lt, false),
+		"syncDary": NewSyncDaryHeap[int, int](4, nil, lt, true),
+		"stable":   NewStableBinaryHeap[int, int](nil, lt, DaryHeapConfig{}),
+		"pairing":  NewPairingHeap[int, int](nil, lt, false),
+		"leftist":  NewLeftistHeap[int, int](nil, lt, true),
+		"skew":     NewSyncSkewHeap[int, int](nil, lt, false),
+		"binomial": NewBinomialHeap[int, int](nil, lt, HeapConfig{}),
+		"adaptive": NewAdaptiveHeap[int, int](nil, lt, HeapConfig{}),
+		"bounded":  NewBoundedHeap[int, int](50, ShedDropWorst, lt, DaryHeapConfig{}),
+	}
+
+	for name, heap := range heaps {
+		rng := rand.New(rand.NewSource(7))
+		for i := 0; i < 500; i++ {
+			if rng.Intn(3) == 0 {
+				heap.Pop()
+			} else {
+				heap.Push(i, rng.Intn(100))
+			}
+			require.NoError(t, heap.(Verifier).Verify(), name)
+		}
+	}
+}
+
+func TestVerify_TrackedHeaps(t *testing.T) {
+	config := HeapConfig{UsePool: true}
+	heaps := map[string]TrackedHeap[int, int]{
+		"pairing":     NewFullPairingHeap[int, int](nil, lt, config),
+		"syncPairing": NewSyncFullPairingHeap[int, int](nil, lt, config),
+		"leftist":     NewFullLeftistHeap[int, int](nil, lt, config),
+		"syncLeftist": NewSyncFullLeftistHeap[int, int](nil, lt, config),
+		"skew":        NewFullSkewHeap[int, int](nil, lt, config),
+		"syncSkew":    NewSyncFullSkewHeap[int, int](nil, lt, config),
+	}
+
+	for name, heap := range heaps {
+		rng := rand.New(rand.NewSource(8))
+		var ids []string
+		for i := 0; i < 1000; i++ {
+			switch op := rng.Intn(5); {
+			case op < 2 || len(ids) == 0:
+				id, err := heap.Push(i, rng.Intn(100))
+				require.NoError(t, err, name)
+				ids = append(ids, id)
+			case op == 2:
+				heap.Pop()
+			case op == 3:
+				heap.UpdatePriority(ids[rng.Intn(len(ids))], rng.Intn(100))
+			default:
+				heap.Remove(ids[rng.Intn(len(ids))])
+			}
+			require.NoError(t, heap.(Verifier).Verify(), name)
+		}
+	}
+}
+
+func TestVerify_RadixAndIndexed(t *testing.T) {
+	radix := NewSyncRadixHeap[int, uint](nil, false)
+	indexed := NewIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	rng := rand.New(rand.NewSource(9))
+	var last uint
+	for i := 0; i < 500; i++ {
+		if rng.Intn(3) == 0 {
+			if p, err := radix.PopPriority(); err == nil {
+				last = p
+			}
+			indexed.Pop()
+		} else {
+			radix.Push(i, last+uint(rng.Intn(1<<20)))
+			id, _ := indexed.Push(i, rng.Intn(100))
+			if i%4 == 0 {
+				indexed.UpdateByID(id, i, rng.Intn(100))
+			}
+		}
+		require.NoError(t, radix.Verify())
+		require.NoError(t, indexed.Verify())
+	}
+}
+
+func TestVerify_DetectsCorruption(t *testing.T) {
+	dary := NewDaryHeap(2, []HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}, lt, false)
+	dary.data[0].priority = 5
+	assert.ErrorIs(t, dary.Verify(), ErrInvariantViolated)
+
+	pairing := NewFullPairingHeap[int, int](nil, lt, HeapConfig{})
+	pairing.Push(1, 1)
+	id, _ := pairing.Push(2, 2)
+	pairing.elements[id].parent = nil
+	assert.ErrorIs(t, pairing.Verify(), ErrInvariantViolated)
+
+	leftist := NewLeftistHeap([]HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}, lt, false)
+	leftist.root.s = 3
+	assert.ErrorIs(t, leftist.Verify(), ErrInvariantViolated)
+
+	skew := NewFullSkewHeap[int, int](nil, lt, HeapConfig{})
+	skew.Push(1, 1)
+	delete(skew.elements, skew.root.id)
+	assert.ErrorIs(t, skew.Verify(), ErrInvariantViolated)
+
+	binomial := NewBinomialHeap([]HeapNode[int, int]{CreateHeapNode(1, 1), CreateHeapNode(2, 2)}, lt, HeapConfig{})
+	binomial.size = 3
+	assert.ErrorIs(t, binomial.Verify(), ErrInvariantViolated)
+
+	radix := NewRadixHeap([]HeapNode[int, uint]{CreateHeapNode(1, uint(4))}, false)
+	radix.last = 9
+	assert.ErrorIs(t, radix.Verify(), ErrInvariantViolated)
+
+	indexed := NewIndexedDaryHeap[int, int](2, lt, HeapConfig{})
+	id, _ = indexed.Push(1, 1)
+	indexed.index[id] = 3
+	assert.ErrorIs(t, indexed.Verify(), ErrInvariantViolated)
+}
@@ -0,0 +1,295 @@
+package heapcraft
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+const (
+	// wheelBits is the number of tick bits resolved by each level of a
+	// TimingWheel, so every level has 1<<wheelBits slots.
+	wheelBits  = 6
+	wheelSlots = 1 << wheelBits
+	// wheelLevels is enough levels to resolve every bit of a uint64 tick, so
+	// no deadline ever overflows the wheel.
+	wheelLevels = (64 + wheelBits - 1) / wheelBits
+)
+
+// DefaultWheelTick is the tick length used by NewTimingWheel when the given
+// tick is not positive.
+const DefaultWheelTick = time.Millisecond
+
+// wheelTimer is a callback waiting in a TimingWheel. Timers in the same slot
+// form a doubly linked list so that they can be cancelled in O(1).
+type wheelTimer struct {
+	id       string
+	fn       func()
+	deadline uint64
+	level    int
+	slot     int
+	prev     *wheelTimer
+	next     *wheelTimer
+}
+
+// TimingWheel runs callbacks at scheduled times like TimerScheduler, but
+// keeps them in a hierarchical hashed timing wheel instead of a heap, so
+// adding, cancelling and expiring a timer take O(1) time however many timers
+// are pending. Time is divided into ticks of a fixed length, and a timer fires
+// on the first Advance to a tick at or after its due time, so it may fire up
+// to one tick late but never early. Timers that are due in the same tick fire
+// in no particular order.
+//
+// The wheel has levels of 64 slots each. Level 0 holds timers due within the
+// current 64 ticks, one slot per tick, and each level above covers 64 times
+// the span of the one below. When the wheel reaches a slot of a higher level,
+// its timers are cascaded down to the levels below. Empty slots are skipped
+// using a bitmap per level, so advancing over a long idle period is cheap.
+//
+// The wheel does not keep time on its own: call Advance with the current time,
+// or Run to have it advanced once per tick. It is safe for concurrent use, and
+// callbacks run without the wheel's lock held, so they may add or cancel
+// timers.
+type TimingWheel struct {
+	mu       sync.Mutex
+	tick     time.Duration
+	start    time.Time
+	now      uint64
+	slots    [wheelLevels][wheelSlots]*wheelTimer
+	occupied [wheelLevels]uint64
+	// due holds timers that were added with a deadline the wheel has already
+	// passed. They fire on the next Advance.
+	due    []*wheelTimer
+	timers map[string]*wheelTimer
+	idGen  IDGenerator
+	pool   pool[*wheelTimer]
+}
+
+// Tick returns the length of one tick of the wheel.
+func (w *TimingWheel) Tick() time.Duration { return w.tick }
+
+// ticks converts at to the first tick that starts at or after it, so that a
+// timer never fires early.
+func (w *TimingWheel) ticks(at time.Time) uint64 {
+	d := at.Sub(w.start)
+	if d <= 0 {
+		return 0
+	}
+	return uint64((d + w.tick - 1) / w.tick)
+}
+
+// link places timer in the slot for its deadline, relative to the wheel's
+// current tick, or in due if the deadline has already been reached.
+func (w *TimingWheel) link(timer *wheelTimer) {
+	if timer.deadline <= w.now {
+		timer.level = -1
+		w.due = append(w.due, timer)
+		return
+	}
+	// The highest bit in which the deadline differs from the current tick
+	// selects the level; the deadline's bits at that level select the slot.
+	level := (bits.Len64(timer.deadline^w.now) - 1) / wheelBits
+	slot := int(timer.deadline>>(level*wheelBits)) & (wheelSlots - 1)
+	timer.level, timer.slot = level, slot
+	timer.prev = nil
+	timer.next = w.slots[level][slot]
+	if timer.next != nil {
+		timer.next.prev = timer
+	}
+	w.slots[level][slot] = timer
+	w.occupied[level] |= 1 << slot
+}
+
+// unlink removes timer from its slot or from due.
+func (w *TimingWheel) unlink(timer *wheelTimer) {
+	if timer.level < 0 {
+		for i, t := range w.due {
+			if t == timer {
+				w.due = append(w.due[:i], w.due[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	if timer.prev != nil {
+		timer.prev.next = timer.next
+	} else {
+		w.slots[timer.level][timer.slot] = timer.next
+		if timer.next == nil {
+			w.occupied[timer.level] &^= 1 << timer.slot
+		}
+	}
+	if timer.next != nil {
+		timer.next.prev = timer.prev
+	}
+	timer.prev, timer.next = nil, nil
+}
+
+// take empties the slot and returns the timers it held.
+func (w *TimingWheel) take(level, slot int) *wheelTimer {
+	head := w.slots[level][slot]
+	w.slots[level][slot] = nil
+	w.occupied[level] &^= 1 << slot
+	return head
+}
+
+// Add schedules fn to run once the wheel has been advanced to at, and returns
+// an ID that can be passed to Cancel or Reschedule. A time the wheel has
+// already reached runs fn on the next Advance. Returns ErrIDGenerationFailed
+// if the generated ID is already in use.
+func (w *TimingWheel) Add(at time.Time, fn func()) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.idGen.Next()
+	if _, exists := w.timers[id]; exists {
+		return "", ErrIDGenerationFailed
+	}
+	timer := w.pool.Get()
+	timer.id, timer.fn, timer.deadline = id, fn, w.ticks(at)
+	w.timers[id] = timer
+	w.link(timer)
+	return id, nil
+}
+
+// After schedules fn to run once the wheel has been advanced by d past its
+// current tick, and returns an ID that can be passed to Cancel or Reschedule.
+func (w *TimingWheel) After(d time.Duration, fn func()) (string, error) {
+	w.mu.Lock()
+	at := w.start.Add(time.Duration(w.now) * w.tick).Add(d)
+	w.mu.Unlock()
+	return w.Add(at, fn)
+}
+
+// Cancel removes the timer with the given ID so that it never fires. Returns
+// ErrNodeNotFound if no pending timer has the ID, for instance because it has
+// already fired.
+func (w *TimingWheel) Cancel(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	timer, exists := w.timers[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	w.unlink(timer)
+	delete(w.timers, id)
+	*timer = wheelTimer{}
+	w.pool.Put(timer)
+	return nil
+}
+
+// Reschedule moves the timer with the given ID to a new time. Returns
+// ErrNodeNotFound if no pending timer has the ID.
+func (w *TimingWheel) Reschedule(id string, at time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	timer, exists := w.timers[id]
+	if !exists {
+		return &NodeNotFoundError{ID: id}
+	}
+	w.unlink(timer)
+	timer.deadline = w.ticks(at)
+	w.link(timer)
+	return nil
+}
+
+// Length returns the number of pending timers.
+func (w *TimingWheel) Length() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.timers)
+}
+
+// expire moves the wheel forward to the given tick and returns the callbacks
+// of every timer that became due, in the order their ticks were reached. Each
+// step jumps straight to the next occupied slot of the lowest non-empty
+// level: a level 0 slot fires its timers, and a higher slot is cascaded into
+// the levels below.
+func (w *TimingWheel) expire(target uint64) []func() {
+	var fired []*wheelTimer
+	fired, w.due = append(fired, w.due...), w.due[:0]
+	for w.now < target {
+		level := 0
+		for level < wheelLevels && w.occupied[level] == 0 {
+			level++
+		}
+		if level == wheelLevels {
+			w.now = target
+			break
+		}
+
+		// Occupied slots always lie after the current position of their
+		// level, so the lowest one is the next event on that level.
+		slot := bits.TrailingZeros64(w.occupied[level])
+		shift := uint(level * wheelBits)
+		next := w.now
+		if shift+wheelBits < 64 {
+			next = next >> (shift + wheelBits) << (shift + wheelBits)
+		} else {
+			next = 0
+		}
+		next |= uint64(slot) << shift
+		if next > target {
+			w.now = target
+			break
+		}
+
+		w.now = next
+		head := w.take(level, slot)
+		for timer := head; timer != nil; {
+			following := timer.next
+			timer.prev, timer.next = nil, nil
+			if level == 0 {
+				fired = append(fired, timer)
+			} else {
+				w.link(timer)
+			}
+			timer = following
+		}
+		fired, w.due = append(fired, w.due...), w.due[:0]
+	}
+
+	callbacks := make([]func(), len(fired))
+	for i, timer := range fired {
+		callbacks[i] = timer.fn
+		delete(w.timers, timer.id)
+		*timer = wheelTimer{}
+		w.pool.Put(timer)
+	}
+	return callbacks
+}
+
+// Advance moves the wheel forward to now and runs the callbacks of every timer
+// that became due, on the calling goroutine and without the wheel's lock held.
+// Returns the number of callbacks run. Moving the wheel backwards has no
+// effect other than firing timers that were added with a time already passed.
+func (w *TimingWheel) Advance(now time.Time) int {
+	w.mu.Lock()
+	target := uint64(0)
+	if d := now.Sub(w.start); d > 0 {
+		target = uint64(d / w.tick)
+	}
+	callbacks := w.expire(max(target, w.now))
+	w.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+	return len(callbacks)
+}
+
+// Run advances the wheel to the current time once per tick until ctx is done,
+// and then returns the context's error. Callbacks run one at a time on the
+// calling goroutine.
+func (w *TimingWheel) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			w.Advance(now)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package heapcraft
+
+import "time"
+
+// NewTimingWheel creates a TimingWheel with no pending timers whose ticks
+// have the given length, counted from start. A tick that is not positive uses
+// DefaultWheelTick. config controls timer pooling, the generator used for
+// timer IDs and the number of timers the wheel is sized for.
+func NewTimingWheel(tick time.Duration, start time.Time, config HeapConfig) *TimingWheel {
+	if tick <= 0 {
+		tick = DefaultWheelTick
+	}
+	return &TimingWheel{
+		tick:   tick,
+		start:  start,
+		timers: make(map[string]*wheelTimer, config.Capacity),
+		idGen:  config.GetGenerator(),
+		pool: newPool(config.UsePool, func() *wheelTimer {
+			return &wheelTimer{}
+		}),
+	}
+}
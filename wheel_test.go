@@ -0,0 +1,186 @@
+package heapcraft
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimingWheel_FiresInTickOrder(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := NewTimingWheel(time.Millisecond, start, HeapConfig{IDGenerator: &IntegerIDGenerator{}})
+	assert.Equal(t, time.Millisecond, w.Tick())
+
+	var order []string
+	record := func(name string) func() { return func() { order = append(order, name) } }
+	_, err := w.Add(start.Add(5*time.Second), record("c"))
+	require.NoError(t, err)
+	_, err = w.Add(start.Add(3*time.Millisecond), record("a"))
+	require.NoError(t, err)
+	_, err = w.After(70*time.Millisecond, record("b"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, w.Length())
+
+	assert.Equal(t, 0, w.Advance(start.Add(2*time.Millisecond)))
+	assert.Equal(t, 1, w.Advance(start.Add(3*time.Millisecond)))
+	assert.Equal(t, 2, w.Advance(start.Add(time.Hour)))
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+	assert.Equal(t, 0, w.Length())
+
+	// A time the wheel has passed fires on the next Advance, even one that
+	// does not move the wheel.
+	_, err = w.Add(start, record("late"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Advance(start))
+	assert.Equal(t, "late", order[len(order)-1])
+}
+
+func TestTimingWheel_CancelAndReschedule(t *testing.T) {
+	start := time.Unix(1000, 0)
+	w := NewTimingWheel(0, start, HeapConfig{UsePool: true})
+	assert.Equal(t, DefaultWheelTick, w.Tick())
+	fired := make([]string, 0)
+
+	cancelled, err := w.Add(start.Add(time.Minute), func() { fired = append(fired, "cancelled") })
+	require.NoError(t, err)
+	moved, err := w.Add(start.Add(time.Hour), func() { fired = append(fired, "moved") })
+	require.NoError(t, err)
+	past, err := w.Add(start.Add(-time.Second), func() { fired = append(fired, "past") })
+	require.NoError(t, err)
+
+	require.NoError(t, w.Cancel(cancelled))
+	require.NoError(t, w.Cancel(past))
+	assert.ErrorIs(t, w.Cancel(cancelled), ErrNodeNotFound)
+	require.NoError(t, w.Reschedule(moved, start.Add(10*time.Millisecond)))
+	assert.ErrorIs(t, w.Reschedule("missing", start), ErrNodeNotFound)
+
+	w.Advance(start.Add(2 * time.Hour))
+	assert.Equal(t, []string{"moved"}, fired)
+}
+
+func TestTimingWheel_CallbacksMayAddTimers(t *testing.T) {
+	start := time.Unix(0, 0)
+	w := NewTimingWheel(time.Millisecond, start, HeapConfig{})
+	count := 0
+	var repeat func()
+	repeat = func() {
+		count++
+		if count < 3 {
+			_, err := w.After(time.Millisecond, repeat)
+			assert.NoError(t, err)
+		}
+	}
+	_, err := w.After(time.Millisecond, repeat)
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		w.Advance(start.Add(time.Duration(i) * time.Millisecond))
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestTimingWheel_MatchesSortedDeadlines(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	start := time.Unix(0, 0)
+	w := NewTimingWheel(time.Microsecond, start, HeapConfig{UsePool: true})
+
+	var fired []int
+	deadlines := make(map[int]int)
+	ids := make(map[int]string)
+	now := 0
+	for i := 0; i < 5000; i++ {
+		switch rng.Intn(4) {
+		case 0, 1:
+			// Spread deadlines over many levels of the wheel.
+			d := now + rng.Intn(1<<uint(rng.Intn(30)))
+			id, err := w.Add(start.Add(time.Duration(d)*time.Microsecond), func() { fired = append(fired, d) })
+			require.NoError(t, err)
+			deadlines[i], ids[i] = d, id
+		case 2:
+			for k, id := range ids {
+				require.NoError(t, w.Cancel(id))
+				delete(ids, k)
+				delete(deadlines, k)
+				break
+			}
+		case 3:
+			now += rng.Intn(1 << uint(rng.Intn(24)))
+			fired = fired[:0]
+			w.Advance(start.Add(time.Duration(now) * time.Microsecond))
+
+			want := make([]int, 0)
+			for k, d := range deadlines {
+				if d <= now {
+					want = append(want, d)
+					delete(deadlines, k)
+					delete(ids, k)
+				}
+			}
+			slices.Sort(want)
+			require.True(t, slices.IsSorted(fired))
+			require.Equal(t, len(want), len(fired))
+			require.Equal(t, want, fired)
+		}
+		require.Equal(t, len(deadlines), w.Length())
+	}
+}
+
+func TestTimingWheel_Run(t *testing.T) {
+	w := NewTimingWheel(time.Millisecond, time.Now(), HeapConfig{})
+	done := make(chan struct{})
+	_, err := w.After(5*time.Millisecond, func() { close(done) })
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- w.Run(ctx) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timer did not fire")
+	}
+	cancel()
+	assert.ErrorIs(t, <-stopped, context.Canceled)
+}
+
+// -------------------------------- Timing Wheel Benchmarks --------------------------------
+
+// BenchmarkTimingWheel_AddExpire and BenchmarkTimerScheduler_AddExpire keep a
+// million timers pending and measure scheduling one timer and expiring the
+// earliest, the steady state of a timeout queue.
+func BenchmarkTimingWheel_AddExpire(b *testing.B) {
+	start := time.Unix(0, 0)
+	w := NewTimingWheel(time.Millisecond, start, HeapConfig{IDGenerator: &AtomicIDGenerator{}, UsePool: true})
+	noop := func() {}
+	for i := 0; i < 1_000_000; i++ {
+		w.Add(start.Add(time.Duration(i)*time.Millisecond), noop)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Add(start.Add(time.Duration(1_000_000+i)*time.Millisecond), noop)
+		w.Advance(start.Add(time.Duration(i) * time.Millisecond))
+	}
+}
+
+func BenchmarkTimerScheduler_AddExpire(b *testing.B) {
+	start := time.Unix(0, 0)
+	s := NewTimerScheduler(HeapConfig{IDGenerator: &AtomicIDGenerator{}, UsePool: true})
+	noop := func() {}
+	for i := 0; i < 1_000_000; i++ {
+		s.At(start.Add(time.Duration(i)*time.Millisecond), noop)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.At(start.Add(time.Duration(1_000_000+i)*time.Millisecond), noop)
+		s.mu.Lock()
+		s.heap.Pop()
+		s.mu.Unlock()
+	}
+}